@@ -27,6 +27,7 @@ import (
 
 	"github.com/altinity/clickhouse-operator/pkg/apis/metrics"
 	"github.com/altinity/clickhouse-operator/pkg/chop"
+	chopKube "github.com/altinity/clickhouse-operator/pkg/chop/kube"
 	"github.com/altinity/clickhouse-operator/pkg/version"
 )
 
@@ -90,10 +91,10 @@ func Run() {
 	log.Infof("Starting metrics exporter. Version:%s GitSHA:%s BuiltAt:%s\n", version.Version, version.GitSHA, version.BuiltAt)
 
 	// Initialize k8s API clients
-	kubeClient, _, chopClient := chop.GetClientset(kubeConfigFile, masterURL)
+	kubeClient, _, chopClient := chopKube.GetClientset(kubeConfigFile, masterURL)
 
 	// Create operator instance
-	chop.New(kubeClient, chopClient, chopConfigFile)
+	chop.New(chopKube.NewConfigSource(kubeClient, chopClient), chopConfigFile)
 	log.Info(chop.Config().String(true))
 
 	exporter := metrics.StartMetricsREST(
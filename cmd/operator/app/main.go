@@ -73,10 +73,11 @@ func Run() {
 
 	initClickHouse(ctx)
 	initClickHouseReconcilerMetricsExporter(ctx)
+	initIntrospection(ctx)
 	keeperErr := initKeeper(ctx)
 
 	var wg sync.WaitGroup
-	wg.Add(3)
+	wg.Add(4)
 
 	go func() {
 		defer wg.Done()
@@ -86,6 +87,10 @@ func Run() {
 		defer wg.Done()
 		runClickHouseReconcilerMetricsExporter(ctx)
 	}()
+	go func() {
+		defer wg.Done()
+		runIntrospection(ctx)
+	}()
 	go func() {
 		defer wg.Done()
 		if keeperErr == nil {
@@ -22,6 +22,7 @@ import (
 
 	log "github.com/altinity/clickhouse-operator/pkg/announcer"
 	"github.com/altinity/clickhouse-operator/pkg/chop"
+	chopKube "github.com/altinity/clickhouse-operator/pkg/chop/kube"
 	chopinformers "github.com/altinity/clickhouse-operator/pkg/client/informers/externalversions"
 	"github.com/altinity/clickhouse-operator/pkg/controller/chi"
 )
@@ -56,10 +57,10 @@ func initClickHouse(ctx context.Context) {
 	}
 
 	// Initialize k8s API clients
-	kubeClient, extClient, chopClient := chop.GetClientset(kubeConfigFile, masterURL)
+	kubeClient, extClient, chopClient := chopKube.GetClientset(kubeConfigFile, masterURL)
 
 	// Create operator instance
-	chop.New(kubeClient, chopClient, chopConfigFile)
+	chop.New(chopKube.NewConfigSource(kubeClient, chopClient), chopConfigFile)
 	log.V(1).F().Info("Config parsed:")
 	log.Info("\n" + chop.Config().String(true))
 
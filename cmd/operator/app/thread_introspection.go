@@ -0,0 +1,80 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"context"
+	"flag"
+	"net/http"
+	"os"
+	"strings"
+
+	log "github.com/altinity/clickhouse-operator/pkg/announcer"
+	"github.com/altinity/clickhouse-operator/pkg/controller/chi"
+)
+
+// CLI parameter variables
+var (
+	// introspectionEP defines CHI introspection API end-point address. Empty means API is disabled
+	introspectionEP string
+	// introspectionTokenFile defines path to file with bearer token introspection API requests are authenticated with
+	introspectionTokenFile string
+)
+
+func init() {
+	flag.StringVar(&introspectionEP, "introspection-endpoint", "", "The CHI introspection API endpoint. API is disabled if not specified.")
+	flag.StringVar(&introspectionTokenFile, "introspection-token-file", "", "Path to file with bearer token required by the CHI introspection API.")
+}
+
+var introspectionServer *http.Server
+
+// initIntrospection is an entry point of the application
+func initIntrospection(ctx context.Context) {
+	if introspectionEP == "" {
+		return
+	}
+
+	token, err := os.ReadFile(introspectionTokenFile)
+	if err != nil {
+		log.F().Fatal("Unable to read introspection API token from '%s'. err: %v", introspectionTokenFile, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(chi.IntrospectionPath, chiController.IntrospectionHandler(strings.TrimSpace(string(token))))
+	introspectionServer = &http.Server{
+		Addr:    introspectionEP,
+		Handler: mux,
+	}
+}
+
+// runIntrospection is an entry point of the application
+func runIntrospection(ctx context.Context) {
+	if introspectionServer == nil {
+		return
+	}
+
+	log.S().P()
+	defer log.E().P()
+
+	go func() {
+		<-ctx.Done()
+		_ = introspectionServer.Shutdown(context.Background())
+	}()
+
+	log.V(1).F().Info("Starting CHI introspection API at '%s%s'", introspectionEP, chi.IntrospectionPath)
+	if err := introspectionServer.ListenAndServe(); err != http.ErrServerClosed {
+		log.F().Error("CHI introspection API failed. err: %v", err)
+	}
+}
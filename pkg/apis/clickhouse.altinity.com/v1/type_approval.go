@@ -0,0 +1,54 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+// ChiApproval describes disruptive actions of the reconcile plan which require approval before being applied
+type ChiApproval struct {
+	// Plan specifies hash of disruptive actions pending approval
+	Plan string `json:"plan,omitempty"     yaml:"plan,omitempty"`
+	// Actions specifies disruptive actions pending approval
+	Actions []string `json:"actions,omitempty"  yaml:"actions,omitempty"`
+	// Approved specifies hash of the plan approved by the user
+	Approved string `json:"approved,omitempty" yaml:"approved,omitempty"`
+}
+
+// NewChiApproval creates new approval request for the plan
+func NewChiApproval(plan string, actions []string) *ChiApproval {
+	return &ChiApproval{
+		Plan:    plan,
+		Actions: actions,
+	}
+}
+
+// GetPlan gets hash of the plan pending approval
+func (a *ChiApproval) GetPlan() string {
+	if a == nil {
+		return ""
+	}
+	return a.Plan
+}
+
+// GetApproved gets hash of the plan approved by the user
+func (a *ChiApproval) GetApproved() string {
+	if a == nil {
+		return ""
+	}
+	return a.Approved
+}
+
+// IsApproved checks whether specified plan is approved
+func (a *ChiApproval) IsApproved(plan string) bool {
+	return (plan != "") && (a.GetApproved() == plan)
+}
@@ -53,6 +53,38 @@ func (chi *ClickHouseInstallation) FillStatus(endpoint string, pods, fqdns []str
 
 // FillSelfCalculatedAddressInfo calculates and fills address info
 func (chi *ClickHouseInstallation) FillSelfCalculatedAddressInfo() {
+	chiScopeCycleSize, clusterScopeCycleSize := chi.getCycleSizes()
+	chi.WalkHostsFullPathAndScope(chiScopeCycleSize, clusterScopeCycleSize, fillSelfCalculatedAddressInfo)
+}
+
+// FillShardSelfCalculatedAddressInfo fills address info of a shard, materialized by the ShardExpander of the cluster.
+// All shards of the lazy cluster have the same number of hosts, so addresses are calculated without walking preceding shards.
+func (chi *ClickHouseInstallation) FillShardSelfCalculatedAddressInfo(cluster *Cluster, shardIndex int, shard *ChiShard) {
+	chiScopeCycleSize, clusterScopeCycleSize := chi.getCycleSizes()
+	address := NewHostAddress(chiScopeCycleSize, clusterScopeCycleSize)
+
+	// Hosts of the preceding clusters
+	chiScopeOffset := 0
+	for clusterIndex := range chi.Spec.Configuration.Clusters {
+		if chi.Spec.Configuration.Clusters[clusterIndex] == cluster {
+			address.ClusterIndex = clusterIndex
+			break
+		}
+		chiScopeOffset += chi.Spec.Configuration.Clusters[clusterIndex].HostsCount()
+	}
+
+	address.ShardIndex = shardIndex
+	for replicaIndex, host := range shard.Hosts {
+		clusterScopeIndex := shardIndex*len(shard.Hosts) + replicaIndex
+		address.ReplicaIndex = replicaIndex
+		address.CHIScopeAddress.Set(chiScopeOffset + clusterScopeIndex)
+		address.ClusterScopeAddress.Set(clusterScopeIndex)
+		_ = fillSelfCalculatedAddressInfo(chi, cluster, shard, cluster.GetReplica(replicaIndex), host, address)
+	}
+}
+
+// getCycleSizes calculates sizes of CHI-scope and cluster-scope cycles of host addresses
+func (chi *ClickHouseInstallation) getCycleSizes() (chiScopeCycleSize, clusterScopeCycleSize int) {
 	// What is the max number of Pods allowed per Node
 	// TODO need to support multi-cluster
 	maxNumberOfPodsPerNode := 0
@@ -93,8 +125,7 @@ func (chi *ClickHouseInstallation) FillSelfCalculatedAddressInfo() {
 		requestedClusterScopeCyclesNum = 1
 	}
 
-	chiScopeCycleSize := 0 // Unlimited
-	clusterScopeCycleSize := 0
+	chiScopeCycleSize = 0 // Unlimited
 	if requestedClusterScopeCyclesNum == 1 {
 		// One cycle only requested
 		clusterScopeCycleSize = 0 // Unlimited
@@ -102,61 +133,60 @@ func (chi *ClickHouseInstallation) FillSelfCalculatedAddressInfo() {
 		clusterScopeCycleSize = int(math.Ceil(float64(chi.HostsCount()) / float64(requestedClusterScopeCyclesNum)))
 	}
 
-	chi.WalkHostsFullPathAndScope(
-		chiScopeCycleSize,
-		clusterScopeCycleSize,
-		func(
-			chi *ClickHouseInstallation,
-			cluster *Cluster,
-			shard *ChiShard,
-			replica *ChiReplica,
-			host *ChiHost,
-			address *HostAddress,
-		) error {
-			cluster.Runtime.Address.Namespace = chi.Namespace
-			cluster.Runtime.Address.CHIName = chi.Name
-			cluster.Runtime.Address.ClusterName = cluster.Name
-			cluster.Runtime.Address.ClusterIndex = address.ClusterIndex
-
-			shard.Runtime.Address.Namespace = chi.Namespace
-			shard.Runtime.Address.CHIName = chi.Name
-			shard.Runtime.Address.ClusterName = cluster.Name
-			shard.Runtime.Address.ClusterIndex = address.ClusterIndex
-			shard.Runtime.Address.ShardName = shard.Name
-			shard.Runtime.Address.ShardIndex = address.ShardIndex
-
-			replica.Runtime.Address.Namespace = chi.Namespace
-			replica.Runtime.Address.CHIName = chi.Name
-			replica.Runtime.Address.ClusterName = cluster.Name
-			replica.Runtime.Address.ClusterIndex = address.ClusterIndex
-			replica.Runtime.Address.ReplicaName = replica.Name
-			replica.Runtime.Address.ReplicaIndex = address.ReplicaIndex
-
-			host.Runtime.Address.Namespace = chi.Namespace
-			// Skip StatefulSet as impossible to self-calculate
-			// host.Address.StatefulSet = CreateStatefulSetName(host)
-			host.Runtime.Address.CHIName = chi.Name
-			host.Runtime.Address.ClusterName = cluster.Name
-			host.Runtime.Address.ClusterIndex = address.ClusterIndex
-			host.Runtime.Address.ShardName = shard.Name
-			host.Runtime.Address.ShardIndex = address.ShardIndex
-			host.Runtime.Address.ReplicaName = replica.Name
-			host.Runtime.Address.ReplicaIndex = address.ReplicaIndex
-			host.Runtime.Address.HostName = host.Name
-			host.Runtime.Address.CHIScopeIndex = address.CHIScopeAddress.Index
-			host.Runtime.Address.CHIScopeCycleSize = address.CHIScopeAddress.CycleSpec.Size
-			host.Runtime.Address.CHIScopeCycleIndex = address.CHIScopeAddress.CycleAddress.CycleIndex
-			host.Runtime.Address.CHIScopeCycleOffset = address.CHIScopeAddress.CycleAddress.Index
-			host.Runtime.Address.ClusterScopeIndex = address.ClusterScopeAddress.Index
-			host.Runtime.Address.ClusterScopeCycleSize = address.ClusterScopeAddress.CycleSpec.Size
-			host.Runtime.Address.ClusterScopeCycleIndex = address.ClusterScopeAddress.CycleAddress.CycleIndex
-			host.Runtime.Address.ClusterScopeCycleOffset = address.ClusterScopeAddress.CycleAddress.Index
-			host.Runtime.Address.ShardScopeIndex = address.ReplicaIndex
-			host.Runtime.Address.ReplicaScopeIndex = address.ShardIndex
+	return chiScopeCycleSize, clusterScopeCycleSize
+}
 
-			return nil
-		},
-	)
+// fillSelfCalculatedAddressInfo fills address info of the host and its parents
+func fillSelfCalculatedAddressInfo(
+	chi *ClickHouseInstallation,
+	cluster *Cluster,
+	shard *ChiShard,
+	replica *ChiReplica,
+	host *ChiHost,
+	address *HostAddress,
+) error {
+	cluster.Runtime.Address.Namespace = chi.Namespace
+	cluster.Runtime.Address.CHIName = chi.Name
+	cluster.Runtime.Address.ClusterName = cluster.Name
+	cluster.Runtime.Address.ClusterIndex = address.ClusterIndex
+
+	shard.Runtime.Address.Namespace = chi.Namespace
+	shard.Runtime.Address.CHIName = chi.Name
+	shard.Runtime.Address.ClusterName = cluster.Name
+	shard.Runtime.Address.ClusterIndex = address.ClusterIndex
+	shard.Runtime.Address.ShardName = shard.Name
+	shard.Runtime.Address.ShardIndex = address.ShardIndex
+
+	replica.Runtime.Address.Namespace = chi.Namespace
+	replica.Runtime.Address.CHIName = chi.Name
+	replica.Runtime.Address.ClusterName = cluster.Name
+	replica.Runtime.Address.ClusterIndex = address.ClusterIndex
+	replica.Runtime.Address.ReplicaName = replica.Name
+	replica.Runtime.Address.ReplicaIndex = address.ReplicaIndex
+
+	host.Runtime.Address.Namespace = chi.Namespace
+	// Skip StatefulSet as impossible to self-calculate
+	// host.Address.StatefulSet = CreateStatefulSetName(host)
+	host.Runtime.Address.CHIName = chi.Name
+	host.Runtime.Address.ClusterName = cluster.Name
+	host.Runtime.Address.ClusterIndex = address.ClusterIndex
+	host.Runtime.Address.ShardName = shard.Name
+	host.Runtime.Address.ShardIndex = address.ShardIndex
+	host.Runtime.Address.ReplicaName = replica.Name
+	host.Runtime.Address.ReplicaIndex = address.ReplicaIndex
+	host.Runtime.Address.HostName = host.Name
+	host.Runtime.Address.CHIScopeIndex = address.CHIScopeAddress.Index
+	host.Runtime.Address.CHIScopeCycleSize = address.CHIScopeAddress.CycleSpec.Size
+	host.Runtime.Address.CHIScopeCycleIndex = address.CHIScopeAddress.CycleAddress.CycleIndex
+	host.Runtime.Address.CHIScopeCycleOffset = address.CHIScopeAddress.CycleAddress.Index
+	host.Runtime.Address.ClusterScopeIndex = address.ClusterScopeAddress.Index
+	host.Runtime.Address.ClusterScopeCycleSize = address.ClusterScopeAddress.CycleSpec.Size
+	host.Runtime.Address.ClusterScopeCycleIndex = address.ClusterScopeAddress.CycleAddress.CycleIndex
+	host.Runtime.Address.ClusterScopeCycleOffset = address.ClusterScopeAddress.CycleAddress.Index
+	host.Runtime.Address.ShardScopeIndex = address.ReplicaIndex
+	host.Runtime.Address.ReplicaScopeIndex = address.ShardIndex
+
+	return nil
 }
 
 // FillCHIPointer fills CHI pointer
@@ -222,10 +252,10 @@ func (chi *ClickHouseInstallation) WalkShards(
 
 	for clusterIndex := range chi.Spec.Configuration.Clusters {
 		cluster := chi.Spec.Configuration.Clusters[clusterIndex]
-		for shardIndex := range cluster.Layout.Shards {
-			shard := &cluster.Layout.Shards[shardIndex]
+		cluster.WalkShards(func(_ int, shard *ChiShard) error {
 			res = append(res, f(shard))
-		}
+			return nil
+		})
 	}
 
 	return res
@@ -244,7 +274,7 @@ func (chi *ClickHouseInstallation) WalkHostsFullPathAndScope(
 	for clusterIndex := range chi.Spec.Configuration.Clusters {
 		cluster := chi.Spec.Configuration.Clusters[clusterIndex]
 		address.ClusterScopeAddress.Init()
-		for shardIndex := range cluster.Layout.Shards {
+		for shardIndex := 0; shardIndex < cluster.getShardsCount(); shardIndex++ {
 			shard := cluster.GetShard(shardIndex)
 			for replicaIndex, host := range shard.Hosts {
 				replica := cluster.GetReplica(replicaIndex)
@@ -274,13 +304,7 @@ func (chi *ClickHouseInstallation) WalkHosts(f func(host *ChiHost) error) []erro
 
 	for clusterIndex := range chi.Spec.Configuration.Clusters {
 		cluster := chi.Spec.Configuration.Clusters[clusterIndex]
-		for shardIndex := range cluster.Layout.Shards {
-			shard := &cluster.Layout.Shards[shardIndex]
-			for replicaIndex := range shard.Hosts {
-				host := shard.Hosts[replicaIndex]
-				res = append(res, f(host))
-			}
-		}
+		res = append(res, cluster.WalkHosts(f)...)
 	}
 
 	return res
@@ -304,10 +328,11 @@ func (chi *ClickHouseInstallation) WalkTillError(
 			return err
 		}
 
-		shards := make([]*ChiShard, 0, len(cluster.Layout.Shards))
-		for shardIndex := range cluster.Layout.Shards {
-			shards = append(shards, &cluster.Layout.Shards[shardIndex])
-		}
+		shards := make([]*ChiShard, 0, cluster.getShardsCount())
+		cluster.WalkShards(func(_ int, shard *ChiShard) error {
+			shards = append(shards, shard)
+			return nil
+		})
 		if err := fShards(ctx, shards); err != nil {
 			return err
 		}
@@ -398,6 +423,9 @@ func (spec *ChiSpec) MergeFrom(from *ChiSpec, _type MergeType) {
 		if spec.NamespaceDomainPattern == "" {
 			spec.NamespaceDomainPattern = from.NamespaceDomainPattern
 		}
+		if spec.Timezone == "" {
+			spec.Timezone = from.Timezone
+		}
 	case MergeTypeOverrideByNonEmptyValues:
 		if from.HasTaskID() {
 			spec.TaskID = from.TaskID
@@ -417,13 +445,21 @@ func (spec *ChiSpec) MergeFrom(from *ChiSpec, _type MergeType) {
 		if from.NamespaceDomainPattern != "" {
 			spec.NamespaceDomainPattern = from.NamespaceDomainPattern
 		}
+		if from.Timezone != "" {
+			// Override by non-empty values only
+			spec.Timezone = from.Timezone
+		}
 	}
 
+	spec.Debug = spec.Debug.MergeFrom(from.Debug, _type)
 	spec.Templating = spec.Templating.MergeFrom(from.Templating, _type)
 	spec.Reconciling = spec.Reconciling.MergeFrom(from.Reconciling, _type)
 	spec.Defaults = spec.Defaults.MergeFrom(from.Defaults, _type)
 	spec.Configuration = spec.Configuration.MergeFrom(from.Configuration, _type)
 	spec.Templates = spec.Templates.MergeFrom(from.Templates, _type)
+	spec.Chproxy = spec.Chproxy.MergeFrom(from.Chproxy, _type)
+	spec.Logging = spec.Logging.MergeFrom(from.Logging, _type)
+	spec.Fork = spec.Fork.MergeFrom(from.Fork, _type)
 	// TODO may be it would be wiser to make more intelligent merge
 	spec.UseTemplates = append(spec.UseTemplates, from.UseTemplates...)
 }
@@ -483,8 +519,8 @@ func (chi *ClickHouseInstallation) ShardsCount() int {
 // HostsCount counts hosts
 func (chi *ClickHouseInstallation) HostsCount() int {
 	count := 0
-	chi.WalkHosts(func(host *ChiHost) error {
-		count++
+	chi.WalkClusters(func(cluster *Cluster) error {
+		count += cluster.HostsCount()
 		return nil
 	})
 	return count
@@ -510,6 +546,20 @@ func (chi *ClickHouseInstallation) GetHostTemplate(name string) (*ChiHostTemplat
 	return chi.Spec.Templates.GetHostTemplatesIndex().Get(name), true
 }
 
+// GetClusterTemplate gets ChiClusterTemplate by name.
+// Clusters are normalized before templates, so cluster templates are looked up without index
+func (chi *ClickHouseInstallation) GetClusterTemplate(name string) (*ChiClusterTemplate, bool) {
+	if chi.Spec.Templates == nil {
+		return nil, false
+	}
+	for i := range chi.Spec.Templates.ClusterTemplates {
+		if template := &chi.Spec.Templates.ClusterTemplates[i]; template.Name == name {
+			return template, true
+		}
+	}
+	return nil, false
+}
+
 // GetPodTemplate gets ChiPodTemplate by name
 func (chi *ClickHouseInstallation) GetPodTemplate(name string) (*ChiPodTemplate, bool) {
 	if !chi.Spec.Templates.GetPodTemplatesIndex().Has(name) {
@@ -0,0 +1,139 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+// ChiChproxy defines chproxy section of .spec
+// chproxy is deployed alongside the CHI and routes queries of its users to the clusters of the CHI
+type ChiChproxy struct {
+	Enabled  *StringBool       `json:"enabled,omitempty"  yaml:"enabled,omitempty"`
+	Image    string            `json:"image,omitempty"    yaml:"image,omitempty"`
+	Replicas *int32            `json:"replicas,omitempty" yaml:"replicas,omitempty"`
+	Users    []ChiChproxyUser  `json:"users,omitempty"    yaml:"users,omitempty"`
+	Caches   []ChiChproxyCache `json:"caches,omitempty"   yaml:"caches,omitempty"`
+}
+
+// ChiChproxyUser defines chproxy user and the cluster and ClickHouse user its queries are routed to
+type ChiChproxyUser struct {
+	Name                 string   `json:"name,omitempty"                 yaml:"name,omitempty"`
+	Password             string   `json:"password,omitempty"             yaml:"password,omitempty"`
+	ToCluster            string   `json:"toCluster,omitempty"            yaml:"toCluster,omitempty"`
+	ToUser               string   `json:"toUser,omitempty"               yaml:"toUser,omitempty"`
+	ToPassword           string   `json:"toPassword,omitempty"           yaml:"toPassword,omitempty"`
+	Cache                string   `json:"cache,omitempty"                yaml:"cache,omitempty"`
+	AllowedNetworks      []string `json:"allowedNetworks,omitempty"      yaml:"allowedNetworks,omitempty"`
+	MaxConcurrentQueries int      `json:"maxConcurrentQueries,omitempty" yaml:"maxConcurrentQueries,omitempty"`
+	MaxExecutionTime     string   `json:"maxExecutionTime,omitempty"     yaml:"maxExecutionTime,omitempty"`
+}
+
+// ChiChproxyCache defines file system cache of chproxy
+type ChiChproxyCache struct {
+	Name    string `json:"name,omitempty"    yaml:"name,omitempty"`
+	MaxSize string `json:"maxSize,omitempty" yaml:"maxSize,omitempty"`
+	Expire  string `json:"expire,omitempty"  yaml:"expire,omitempty"`
+}
+
+// NewChiChproxy creates new ChiChproxy object
+func NewChiChproxy() *ChiChproxy {
+	return new(ChiChproxy)
+}
+
+// IsEnabled checks whether chproxy is to be deployed. Specified section enables chproxy unless disabled explicitly
+func (c *ChiChproxy) IsEnabled() bool {
+	if c == nil {
+		return false
+	}
+	return !c.Enabled.HasValue() || c.Enabled.IsTrue()
+}
+
+// FindUser finds user by name
+func (c *ChiChproxy) FindUser(name string) *ChiChproxyUser {
+	if c == nil {
+		return nil
+	}
+	for i := range c.Users {
+		if c.Users[i].Name == name {
+			return &c.Users[i]
+		}
+	}
+	return nil
+}
+
+// FindCache finds cache by name
+func (c *ChiChproxy) FindCache(name string) *ChiChproxyCache {
+	if c == nil {
+		return nil
+	}
+	for i := range c.Caches {
+		if c.Caches[i].Name == name {
+			return &c.Caches[i]
+		}
+	}
+	return nil
+}
+
+// MergeFrom merges from specified object
+func (c *ChiChproxy) MergeFrom(from *ChiChproxy, _type MergeType) *ChiChproxy {
+	if from == nil {
+		return c
+	}
+
+	if c == nil {
+		c = NewChiChproxy()
+	}
+
+	switch _type {
+	case MergeTypeFillEmptyValues:
+		if !c.Enabled.HasValue() {
+			c.Enabled = c.Enabled.MergeFrom(from.Enabled)
+		}
+		if c.Image == "" {
+			c.Image = from.Image
+		}
+		if c.Replicas == nil {
+			c.Replicas = from.Replicas
+		}
+	case MergeTypeOverrideByNonEmptyValues:
+		if from.Enabled.HasValue() {
+			// Override by non-empty values only
+			c.Enabled = from.Enabled
+		}
+		if from.Image != "" {
+			// Override by non-empty values only
+			c.Image = from.Image
+		}
+		if from.Replicas != nil {
+			// Override by non-empty values only
+			c.Replicas = from.Replicas
+		}
+	}
+
+	// Users and caches are identified by name, the ones absent are appended
+	for _, user := range from.Users {
+		if existing := c.FindUser(user.Name); existing == nil {
+			c.Users = append(c.Users, *user.DeepCopy())
+		} else if _type == MergeTypeOverrideByNonEmptyValues {
+			*existing = *user.DeepCopy()
+		}
+	}
+	for _, cache := range from.Caches {
+		if existing := c.FindCache(cache.Name); existing == nil {
+			c.Caches = append(c.Caches, cache)
+		} else if _type == MergeTypeOverrideByNonEmptyValues {
+			*existing = cache
+		}
+	}
+
+	return c
+}
@@ -14,18 +14,59 @@
 
 package v1
 
+import (
+	"sync"
+
+	core "k8s.io/api/core/v1"
+
+	"github.com/altinity/clickhouse-operator/pkg/util"
+)
+
 // Cluster defines item of a clusters section of .configuration
 type Cluster struct {
 	Name         string              `json:"name,omitempty"         yaml:"name,omitempty"`
 	Zookeeper    *ChiZookeeperConfig `json:"zookeeper,omitempty"    yaml:"zookeeper,omitempty"`
 	Settings     *Settings           `json:"settings,omitempty"     yaml:"settings,omitempty"`
 	Files        *Settings           `json:"files,omitempty"        yaml:"files,omitempty"`
+	Macros       *Settings           `json:"macros,omitempty"       yaml:"macros,omitempty"`
 	Templates    *ChiTemplateNames   `json:"templates,omitempty"    yaml:"templates,omitempty"`
 	SchemaPolicy *SchemaPolicy       `json:"schemaPolicy,omitempty" yaml:"schemaPolicy,omitempty"`
 	Insecure     *StringBool         `json:"insecure,omitempty"     yaml:"insecure,omitempty"`
 	Secure       *StringBool         `json:"secure,omitempty"       yaml:"secure,omitempty"`
 	Secret       *ClusterSecret      `json:"secret,omitempty"       yaml:"secret,omitempty"`
 	Layout       *ChiClusterLayout   `json:"layout,omitempty"       yaml:"layout,omitempty"`
+	// Zones specifies zones replicas of each shard are spread across
+	Zones *ChiPodTemplateZone `json:"zones,omitempty" yaml:"zones,omitempty"`
+	// HostPorts specifies ports of hosts of the cluster
+	HostPorts `json:",inline" yaml:",inline"`
+	// RemoteReplicas specifies replicas of each shard living outside of the CHI, e.g. in another region
+	RemoteReplicas []ChiRemoteReplica `json:"remoteReplicas,omitempty" yaml:"remoteReplicas,omitempty"`
+	// WriteReliability specifies defaults of write-related settings for hosts of the cluster
+	WriteReliability *ChiWriteReliability `json:"writeReliability,omitempty" yaml:"writeReliability,omitempty"`
+	// ZookeeperPathTemplate specifies template of the ZooKeeper path macro of the cluster
+	ZookeeperPathTemplate string `json:"zookeeperPathTemplate,omitempty" yaml:"zookeeperPathTemplate,omitempty"`
+	// ReplicatedDatabases specifies databases of Replicated engine created on all hosts of the cluster
+	ReplicatedDatabases []ChiReplicatedDatabase `json:"replicatedDatabases,omitempty" yaml:"replicatedDatabases,omitempty"`
+	// Rebalancing specifies moving of historical partitions to shards added to the cluster
+	Rebalancing *ChiClusterRebalancing `json:"rebalancing,omitempty" yaml:"rebalancing,omitempty"`
+	// ScheduledRestart specifies periodic restarts of hosts of the cluster
+	ScheduledRestart *ChiClusterScheduledRestart `json:"scheduledRestart,omitempty" yaml:"scheduledRestart,omitempty"`
+	// ClusterTemplate specifies name of the cluster template from .spec.templates.clusterTemplates the cluster is based on
+	ClusterTemplate string `json:"clusterTemplate,omitempty" yaml:"clusterTemplate,omitempty"`
+	// Resources specifies resources of ClickHouse container of hosts of the cluster
+	Resources *core.ResourceRequirements `json:"resources,omitempty" yaml:"resources,omitempty"`
+	// PriorityClassName specifies priority class of pods of hosts of the cluster
+	PriorityClassName string `json:"priorityClassName,omitempty" yaml:"priorityClassName,omitempty"`
+	// HostNetwork specifies whether pods of hosts of the cluster run in the host network namespace of their nodes
+	HostNetwork *StringBool `json:"hostNetwork,omitempty" yaml:"hostNetwork,omitempty"`
+	// Spot specifies replicas of each shard running on spot nodes
+	Spot *ChiClusterSpot `json:"spot,omitempty" yaml:"spot,omitempty"`
+	// TLS specifies interserver TLS of the cluster
+	TLS *ChiClusterTLS `json:"tls,omitempty" yaml:"tls,omitempty"`
+	// Labels are set on all resources created for the cluster, override .spec.defaults.labels
+	Labels map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+	// Annotations are set on all resources created for the cluster, override .spec.defaults.annotations
+	Annotations map[string]string `json:"annotations,omitempty" yaml:"annotations,omitempty"`
 
 	Runtime ClusterRuntime `json:"-" yaml:"-"`
 }
@@ -33,6 +74,57 @@ type Cluster struct {
 type ClusterRuntime struct {
 	Address ChiClusterAddress       `json:"-" yaml:"-"`
 	CHI     *ClickHouseInstallation `json:"-" yaml:"-" testdiff:"ignore"`
+	// ShardExpander materializes shards on demand for clusters which do not keep shards in the layout
+	ShardExpander *ShardExpander `json:"-" yaml:"-" testdiff:"ignore"`
+}
+
+// ShardExpander builds shards of a lazy cluster on demand.
+// Each shard is built once, on first access, and is reused afterwards, so host modifications survive between walks.
+// +k8s:deepcopy-gen=false
+type ShardExpander struct {
+	expand func(index int) *ChiShard
+
+	mu     sync.Mutex
+	shards map[int]*ChiShard
+}
+
+// NewShardExpander creates new ShardExpander
+func NewShardExpander(expand func(index int) *ChiShard) *ShardExpander {
+	return &ShardExpander{
+		expand: expand,
+	}
+}
+
+// Expand gets shard with specified index, building it in case it is not built yet
+func (e *ShardExpander) Expand(index int) *ChiShard {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if shard, ok := e.shards[index]; ok {
+		return shard
+	}
+	if e.shards == nil {
+		e.shards = make(map[int]*ChiShard)
+	}
+	shard := e.expand(index)
+	e.shards[index] = shard
+	return shard
+}
+
+// DeepCopyInto copies the receiver into out, expand function is shared between copies.
+// Shards already built are not copied, the copy builds shards of its own
+func (in *ShardExpander) DeepCopyInto(out *ShardExpander) {
+	out.expand = in.expand
+}
+
+// DeepCopy copies the receiver, expand function is shared between copies
+func (in *ShardExpander) DeepCopy() *ShardExpander {
+	if in == nil {
+		return nil
+	}
+	out := new(ShardExpander)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // SchemaPolicy defines schema management policy - replica or shard-based
@@ -58,6 +150,8 @@ type ChiClusterLayout struct {
 	// TODO refactor into map[string]ChiShard
 	Shards   []ChiShard   `json:"shards,omitempty"   yaml:"shards,omitempty"`
 	Replicas []ChiReplica `json:"replicas,omitempty" yaml:"replicas,omitempty"`
+	// ShardOverrides override templates of shards with specified indices, without specifying shards explicitly
+	ShardOverrides []ChiShardOverride `json:"shardOverrides,omitempty" yaml:"shardOverrides,omitempty"`
 
 	// Internal data
 	// Whether shards or replicas are explicitly specified as Shards []ChiShard or Replicas []ChiReplica
@@ -66,6 +160,57 @@ type ChiClusterLayout struct {
 	HostsField        *HostsField `json:"-" yaml:"-" testdiff:"ignore"`
 }
 
+// ChiShardOverride defines templates of shards with specified indices
+type ChiShardOverride struct {
+	Shards    []int             `json:"shards,omitempty"    yaml:"shards,omitempty"`
+	Templates *ChiTemplateNames `json:"templates,omitempty" yaml:"templates,omitempty"`
+}
+
+// GetShardTemplates gets templates overridden for the shard with specified index.
+// In case the shard is listed in several overrides, the first one listing the template wins.
+func (layout *ChiClusterLayout) GetShardTemplates(shardIndex int) *ChiTemplateNames {
+	if layout == nil {
+		return nil
+	}
+	var templates *ChiTemplateNames
+	for i := range layout.ShardOverrides {
+		override := &layout.ShardOverrides[i]
+		for _, index := range override.Shards {
+			if index == shardIndex {
+				templates = templates.MergeFrom(override.Templates, MergeTypeFillEmptyValues)
+				break
+			}
+		}
+	}
+	return templates
+}
+
+// mergeFromFillEmptyValues fills layout fields not specified in the receiver
+func (layout *ChiClusterLayout) mergeFromFillEmptyValues(from *ChiClusterLayout) *ChiClusterLayout {
+	if from == nil {
+		return layout
+	}
+	if layout == nil {
+		return from
+	}
+	if layout.ShardsCount == 0 {
+		layout.ShardsCount = from.ShardsCount
+	}
+	if layout.ReplicasCount == 0 {
+		layout.ReplicasCount = from.ReplicasCount
+	}
+	if len(layout.Shards) == 0 {
+		layout.Shards = from.Shards
+	}
+	if len(layout.Replicas) == 0 {
+		layout.Replicas = from.Replicas
+	}
+	if len(layout.ShardOverrides) == 0 {
+		layout.ShardOverrides = from.ShardOverrides
+	}
+	return layout
+}
+
 // NewClusterSchemaPolicy creates new cluster layout
 func NewClusterSchemaPolicy() *SchemaPolicy {
 	return new(SchemaPolicy)
@@ -105,6 +250,71 @@ func (cluster *Cluster) IsShardSpecified() bool {
 	return cluster.isShardSpecified()
 }
 
+// InheritClusterTemplateFrom fills fields not specified in the cluster from the cluster template it refers to
+func (cluster *Cluster) InheritClusterTemplateFrom(chi *ClickHouseInstallation) {
+	if cluster.ClusterTemplate == "" {
+		return
+	}
+	template, ok := chi.GetClusterTemplate(cluster.ClusterTemplate)
+	if !ok {
+		return
+	}
+	// Do not share anything with the template, it may be instantiated by many clusters
+	from := template.Spec.DeepCopy()
+
+	cluster.Zookeeper = cluster.Zookeeper.MergeFrom(from.Zookeeper, MergeTypeFillEmptyValues)
+	cluster.Settings = cluster.Settings.MergeFrom(from.Settings)
+	cluster.Files = cluster.Files.MergeFrom(from.Files)
+	cluster.Macros = cluster.Macros.MergeFrom(from.Macros)
+	cluster.Templates = cluster.Templates.MergeFrom(from.Templates, MergeTypeFillEmptyValues)
+	cluster.Insecure = cluster.Insecure.MergeFrom(from.Insecure)
+	cluster.Secure = cluster.Secure.MergeFrom(from.Secure)
+	if cluster.SchemaPolicy == nil {
+		cluster.SchemaPolicy = from.SchemaPolicy
+	}
+	if cluster.Secret == nil {
+		cluster.Secret = from.Secret
+	}
+	if cluster.Zones == nil {
+		cluster.Zones = from.Zones
+	}
+	if len(cluster.RemoteReplicas) == 0 {
+		cluster.RemoteReplicas = from.RemoteReplicas
+	}
+	if cluster.WriteReliability == nil {
+		cluster.WriteReliability = from.WriteReliability
+	}
+	if cluster.ZookeeperPathTemplate == "" {
+		cluster.ZookeeperPathTemplate = from.ZookeeperPathTemplate
+	}
+	if len(cluster.ReplicatedDatabases) == 0 {
+		cluster.ReplicatedDatabases = from.ReplicatedDatabases
+	}
+	if cluster.Rebalancing == nil {
+		cluster.Rebalancing = from.Rebalancing
+	}
+	if cluster.ScheduledRestart == nil {
+		cluster.ScheduledRestart = from.ScheduledRestart
+	}
+	if cluster.PriorityClassName == "" {
+		cluster.PriorityClassName = from.PriorityClassName
+	}
+	if !cluster.HostNetwork.HasValue() {
+		cluster.HostNetwork = from.HostNetwork
+	}
+	cluster.HostPorts.MergeFrom(&from.HostPorts)
+	if cluster.Spot == nil {
+		cluster.Spot = from.Spot
+	}
+	if cluster.TLS == nil {
+		cluster.TLS = from.TLS
+	}
+	cluster.Labels = util.MergeStringMapsPreserve(cluster.Labels, from.Labels)
+	cluster.Annotations = util.MergeStringMapsPreserve(cluster.Annotations, from.Annotations)
+	cluster.Resources = MergeResourceRequirements(cluster.Resources, from.Resources)
+	cluster.Layout = cluster.Layout.mergeFromFillEmptyValues(from.Layout)
+}
+
 // InheritZookeeperFrom inherits zookeeper config from CHI
 func (cluster *Cluster) InheritZookeeperFrom(chi *ClickHouseInstallation) {
 	if !cluster.Zookeeper.IsEmpty() {
@@ -121,6 +331,25 @@ func (cluster *Cluster) InheritZookeeperFrom(chi *ClickHouseInstallation) {
 	cluster.Zookeeper = cluster.Zookeeper.MergeFrom(chi.Spec.Configuration.Zookeeper, MergeTypeFillEmptyValues)
 }
 
+// InheritZookeeperPathTemplateFrom inherits ZooKeeper path template from CHI defaults
+func (cluster *Cluster) InheritZookeeperPathTemplateFrom(chi *ClickHouseInstallation) {
+	if cluster.ZookeeperPathTemplate != "" {
+		return
+	}
+	if chi.Spec.Defaults == nil {
+		return
+	}
+	cluster.ZookeeperPathTemplate = chi.Spec.Defaults.ZookeeperPathTemplate
+}
+
+// InheritMacrosFrom inherits macros from CHI
+func (cluster *Cluster) InheritMacrosFrom(chi *ClickHouseInstallation) {
+	if chi.Spec.Configuration == nil {
+		return
+	}
+	cluster.Macros = cluster.Macros.MergeFrom(chi.Spec.Configuration.Macros)
+}
+
 // InheritFilesFrom inherits files from CHI
 func (cluster *Cluster) InheritFilesFrom(chi *ClickHouseInstallation) {
 	if chi.Spec.Configuration == nil {
@@ -154,6 +383,66 @@ func (cluster *Cluster) InheritTemplatesFrom(chi *ClickHouseInstallation) {
 	cluster.Templates.HandleDeprecatedFields()
 }
 
+// InheritResourcesFrom inherits resources from .spec.defaults of CHI
+func (cluster *Cluster) InheritResourcesFrom(chi *ClickHouseInstallation) {
+	if chi.Spec.Defaults == nil {
+		return
+	}
+	cluster.Resources = MergeResourceRequirements(cluster.Resources, chi.Spec.Defaults.Resources.DeepCopy())
+}
+
+// InheritPriorityClassNameFrom inherits priority class name from .spec.defaults of CHI
+func (cluster *Cluster) InheritPriorityClassNameFrom(chi *ClickHouseInstallation) {
+	if cluster.PriorityClassName != "" {
+		return
+	}
+	if chi.Spec.Defaults == nil {
+		return
+	}
+	cluster.PriorityClassName = chi.Spec.Defaults.PriorityClassName
+}
+
+// InheritMetadataFrom inherits labels and annotations from .spec.defaults of CHI, cluster's own ones take precedence
+func (cluster *Cluster) InheritMetadataFrom(chi *ClickHouseInstallation) {
+	cluster.Labels = util.MergeStringMapsPreserve(cluster.Labels, chi.Spec.Defaults.GetLabels())
+	cluster.Annotations = util.MergeStringMapsPreserve(cluster.Annotations, chi.Spec.Defaults.GetAnnotations())
+}
+
+// GetLabels gets labels to be set on all resources created for the cluster
+func (cluster *Cluster) GetLabels() map[string]string {
+	if cluster == nil {
+		return nil
+	}
+	return cluster.Labels
+}
+
+// GetAnnotations gets annotations to be set on all resources created for the cluster
+func (cluster *Cluster) GetAnnotations() map[string]string {
+	if cluster == nil {
+		return nil
+	}
+	return cluster.Annotations
+}
+
+// InheritHostNetworkFrom inherits host network flag from .spec.defaults of CHI
+func (cluster *Cluster) InheritHostNetworkFrom(chi *ClickHouseInstallation) {
+	if cluster.HostNetwork.HasValue() {
+		return
+	}
+	if chi.Spec.Defaults == nil {
+		return
+	}
+	cluster.HostNetwork = chi.Spec.Defaults.HostNetwork
+}
+
+// IsHostNetwork checks whether pods of hosts of the cluster run in the host network namespace of their nodes
+func (cluster *Cluster) IsHostNetwork() bool {
+	if cluster == nil {
+		return false
+	}
+	return cluster.HostNetwork.IsTrue()
+}
+
 // GetServiceTemplate returns service template, if exists
 func (cluster *Cluster) GetServiceTemplate() (*ChiServiceTemplate, bool) {
 	if !cluster.Templates.HasClusterServiceTemplate() {
@@ -163,6 +452,24 @@ func (cluster *Cluster) GetServiceTemplate() (*ChiServiceTemplate, bool) {
 	return cluster.Runtime.CHI.GetServiceTemplate(name)
 }
 
+// GetReaderServiceTemplate returns reader service template, if exists
+func (cluster *Cluster) GetReaderServiceTemplate() (*ChiServiceTemplate, bool) {
+	if !cluster.Templates.HasReaderServiceTemplate() {
+		return nil, false
+	}
+	name := cluster.Templates.GetReaderServiceTemplate()
+	return cluster.Runtime.CHI.GetServiceTemplate(name)
+}
+
+// GetWriterServiceTemplate returns writer service template, if exists
+func (cluster *Cluster) GetWriterServiceTemplate() (*ChiServiceTemplate, bool) {
+	if !cluster.Templates.HasWriterServiceTemplate() {
+		return nil, false
+	}
+	name := cluster.Templates.GetWriterServiceTemplate()
+	return cluster.Runtime.CHI.GetServiceTemplate(name)
+}
+
 // GetCHI gets parent CHI
 func (cluster *Cluster) GetCHI() *ClickHouseInstallation {
 	return cluster.Runtime.CHI
@@ -170,11 +477,34 @@ func (cluster *Cluster) GetCHI() *ClickHouseInstallation {
 
 // GetShard gets shard with specified index
 func (cluster *Cluster) GetShard(shard int) *ChiShard {
+	if cluster.IsLazy() {
+		return cluster.Runtime.ShardExpander.Expand(shard)
+	}
 	return &cluster.Layout.Shards[shard]
 }
 
+// IsLazy checks whether shards of the cluster are materialized on demand by the ShardExpander
+func (cluster *Cluster) IsLazy() bool {
+	if cluster == nil {
+		return false
+	}
+	return cluster.Runtime.ShardExpander != nil
+}
+
+// getShardsCount gets number of shards, either materialized or to be expanded
+func (cluster *Cluster) getShardsCount() int {
+	if cluster.IsLazy() {
+		return cluster.Layout.ShardsCount
+	}
+	return len(cluster.Layout.Shards)
+}
+
 // GetOrCreateHost gets or creates host on specified coordinates
 func (cluster *Cluster) GetOrCreateHost(shard, replica int) *ChiHost {
+	if cluster.Layout.HostsField == nil {
+		// Lazy clusters do not keep hosts field, each host is created anew
+		return &ChiHost{}
+	}
 	return cluster.Layout.HostsField.GetOrCreate(shard, replica)
 }
 
@@ -230,8 +560,8 @@ func (cluster *Cluster) WalkShards(
 	}
 	res := make([]error, 0)
 
-	for shardIndex := range cluster.Layout.Shards {
-		shard := &cluster.Layout.Shards[shardIndex]
+	for shardIndex := 0; shardIndex < cluster.getShardsCount(); shardIndex++ {
+		shard := cluster.GetShard(shardIndex)
 		res = append(res, f(shardIndex, shard))
 	}
 
@@ -255,8 +585,8 @@ func (cluster *Cluster) WalkHosts(f func(host *ChiHost) error) []error {
 
 	res := make([]error, 0)
 
-	for shardIndex := range cluster.Layout.Shards {
-		shard := &cluster.Layout.Shards[shardIndex]
+	for shardIndex := 0; shardIndex < cluster.getShardsCount(); shardIndex++ {
+		shard := cluster.GetShard(shardIndex)
 		for replicaIndex := range shard.Hosts {
 			host := shard.Hosts[replicaIndex]
 			res = append(res, f(host))
@@ -271,8 +601,8 @@ func (cluster *Cluster) WalkHostsByShards(f func(shard, replica int, host *ChiHo
 
 	res := make([]error, 0)
 
-	for shardIndex := range cluster.Layout.Shards {
-		shard := &cluster.Layout.Shards[shardIndex]
+	for shardIndex := 0; shardIndex < cluster.getShardsCount(); shardIndex++ {
+		shard := cluster.GetShard(shardIndex)
 		for replicaIndex := range shard.Hosts {
 			host := shard.Hosts[replicaIndex]
 			res = append(res, f(shardIndex, replicaIndex, host))
@@ -298,8 +628,30 @@ func (cluster *Cluster) WalkHostsByReplicas(f func(shard, replica int, host *Chi
 	return res
 }
 
+// HasReadTier checks whether cluster has hosts of the read tier
+func (cluster *Cluster) HasReadTier() bool {
+	found := false
+	cluster.WalkReplicas(func(index int, replica *ChiReplica) error {
+		found = found || (replica.Tier == HostTierRead)
+		return nil
+	})
+	if found || cluster.IsLazy() {
+		// Hosts of lazy clusters inherit tier from replicas
+		return found
+	}
+	cluster.WalkHosts(func(host *ChiHost) error {
+		found = found || host.IsReadTier()
+		return nil
+	})
+	return found
+}
+
 // HostsCount counts hosts
 func (cluster *Cluster) HostsCount() int {
+	if cluster.IsLazy() {
+		// Lazy clusters have Standard layout, no need to expand shards in order to count hosts
+		return cluster.Layout.ShardsCount * cluster.Layout.ReplicasCount
+	}
 	count := 0
 	cluster.WalkHosts(func(host *ChiHost) error {
 		count++
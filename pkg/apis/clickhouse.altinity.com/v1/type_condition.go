@@ -0,0 +1,93 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import (
+	"time"
+)
+
+// Possible condition statuses
+const (
+	ConditionTrue    = "True"
+	ConditionFalse   = "False"
+	ConditionUnknown = "Unknown"
+)
+
+// Possible condition types
+const (
+	// ConditionCapacityAvailable reports whether pre-flight capacity checks passed
+	ConditionCapacityAvailable = "CapacityAvailable"
+	// ConditionPolicyCompliant reports whether CHI complies with policy rules
+	ConditionPolicyCompliant = "PolicyCompliant"
+	// ConditionPlanApproved reports whether disruptive actions of the reconcile plan are approved
+	ConditionPlanApproved = "PlanApproved"
+	// ConditionDiskPressure reports whether disk usage of any host exceeds the threshold
+	ConditionDiskPressure = "DiskPressure"
+	// ConditionQuotaPressure reports whether usage of any quota of any host exceeds the threshold
+	ConditionQuotaPressure = "QuotaPressure"
+	// ConditionConfigDrift reports whether configuration loaded by any host differs from the generated one
+	ConditionConfigDrift = "ConfigDrift"
+	// ConditionReadonlyReplicas reports whether any host has replicated tables in readonly mode
+	ConditionReadonlyReplicas = "ReadonlyReplicas"
+	// ConditionCoordinationReady reports whether coordination ensembles CHI refers to have quorum
+	ConditionCoordinationReady = "CoordinationReady"
+	// ConditionFrozen reports whether disruptive actions are held due to operator-wide freeze
+	ConditionFrozen = "Frozen"
+	// ConditionPortConflict reports whether ports allocated to hosts are allocated to hosts of another CHI as well
+	ConditionPortConflict = "PortConflict"
+)
+
+// ChiCondition describes an aspect of CHI state observed by the operator
+type ChiCondition struct {
+	Type               string `json:"type"                         yaml:"type"`
+	Status             string `json:"status"                       yaml:"status"`
+	Reason             string `json:"reason,omitempty"             yaml:"reason,omitempty"`
+	Message            string `json:"message,omitempty"            yaml:"message,omitempty"`
+	LastTransitionTime string `json:"lastTransitionTime,omitempty" yaml:"lastTransitionTime,omitempty"`
+}
+
+// NewChiCondition creates new condition
+func NewChiCondition(_type, status, reason, message string) ChiCondition {
+	return ChiCondition{
+		Type:    _type,
+		Status:  status,
+		Reason:  reason,
+		Message: message,
+	}
+}
+
+// IsTrue checks whether condition status is True
+func (c ChiCondition) IsTrue() bool {
+	return c.Status == ConditionTrue
+}
+
+// setConditionNoSync sets condition, replacing the one of the same type.
+// Transition time is updated only in case status changes.
+func setConditionNoSync(conditions []ChiCondition, condition ChiCondition) []ChiCondition {
+	for i := range conditions {
+		if conditions[i].Type != condition.Type {
+			continue
+		}
+		if conditions[i].Status == condition.Status {
+			condition.LastTransitionTime = conditions[i].LastTransitionTime
+		} else {
+			condition.LastTransitionTime = time.Now().UTC().Format(time.RFC3339)
+		}
+		conditions[i] = condition
+		return conditions
+	}
+	condition.LastTransitionTime = time.Now().UTC().Format(time.RFC3339)
+	return append(conditions, condition)
+}
@@ -36,8 +36,11 @@ type Configuration struct {
 	Quotas    *Settings           `json:"quotas,omitempty"    yaml:"quotas,omitempty"`
 	Settings  *Settings           `json:"settings,omitempty"  yaml:"settings,omitempty"`
 	Files     *Settings           `json:"files,omitempty"     yaml:"files,omitempty"`
+	Macros    *Settings           `json:"macros,omitempty"    yaml:"macros,omitempty"`
 	// TODO refactor into map[string]ChiCluster
 	Clusters []*Cluster `json:"clusters,omitempty"  yaml:"clusters,omitempty"`
+	// LogicalClusters are additional remote_servers entries over hosts of Clusters
+	LogicalClusters []ChiLogicalCluster `json:"logicalClusters,omitempty" yaml:"logicalClusters,omitempty"`
 }
 
 // NewConfiguration creates new Configuration objects
@@ -61,10 +64,20 @@ func (configuration *Configuration) MergeFrom(from *Configuration, _type MergeTy
 	configuration.Quotas = configuration.Quotas.MergeFrom(from.Quotas)
 	configuration.Settings = configuration.Settings.MergeFrom(from.Settings)
 	configuration.Files = configuration.Files.MergeFrom(from.Files)
+	configuration.Macros = configuration.Macros.MergeFrom(from.Macros)
 
 	// TODO merge clusters
 	// Copy Clusters for now
 	configuration.Clusters = from.Clusters
 
+	// Logical clusters are identified by name, the ones absent are appended
+	for _, logicalCluster := range from.LogicalClusters {
+		if existing := configuration.FindLogicalCluster(logicalCluster.Name); existing == nil {
+			configuration.LogicalClusters = append(configuration.LogicalClusters, *logicalCluster.DeepCopy())
+		} else if _type == MergeTypeOverrideByNonEmptyValues {
+			*existing = *logicalCluster.DeepCopy()
+		}
+	}
+
 	return configuration
 }
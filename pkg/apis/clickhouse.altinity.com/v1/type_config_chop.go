@@ -28,7 +28,9 @@ import (
 	log "github.com/golang/glog"
 	"github.com/imdario/mergo"
 	"gopkg.in/yaml.v3"
+	core "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 
 	"github.com/altinity/clickhouse-operator/pkg/apis/deployment"
 	"github.com/altinity/clickhouse-operator/pkg/util"
@@ -58,6 +60,14 @@ const (
 	// ChSchemeAuto specifies that operator has to decide itself should https or http be used
 	ChSchemeAuto = "auto"
 
+	// Possible values for ClickHouse health-check mode
+
+	// ChHealthCheckModeNetwork specifies health-checks performed over network connection, same as all other queries
+	ChHealthCheckModeNetwork = "network"
+	// ChHealthCheckModeExec specifies health-checks performed by clickhouse-client executed inside the pod
+	ChHealthCheckModeExec    = "exec"
+	defaultChHealthCheckMode = ChHealthCheckModeNetwork
+
 	// Username and Password to be used by operator to connect to ClickHouse instances for
 	// 1. Metrics requests
 	// 2. Schema maintenance
@@ -103,6 +113,28 @@ const (
 	defaultTerminationGracePeriod = 30
 	// defaultRevisionHistoryLimit specifies default value for RevisionHistoryLimit
 	defaultRevisionHistoryLimit = 10
+
+	// defaultPolicyWebhookTimeout specifies default timeout to call policy webhook. In seconds
+	defaultPolicyWebhookTimeout = 5
+	// defaultNotificationSinkTimeout specifies default timeout to deliver notification to a sink. In seconds
+	defaultNotificationSinkTimeout = 5
+
+	// defaultImpersonationServiceAccount specifies default name of the service account to impersonate
+	defaultImpersonationServiceAccount = "clickhouse-operator"
+
+	// defaultResyncInterval specifies default number of seconds between two runs of a periodic check, same as informers resync period
+	defaultResyncInterval = 60
+	// defaultResyncSlowInterval specifies default number of seconds between two runs of periodic checks, which query all hosts heavily
+	defaultResyncSlowInterval = 300
+	// defaultResyncCertificateRotationInterval specifies default number of seconds between two checks of certificates expiry
+	defaultResyncCertificateRotationInterval = 3600
+
+	// defaultHealthWindow specifies default number of the latest health-checks kept per host
+	defaultHealthWindow = 10
+	// defaultHealthFlapThreshold specifies default number of switches between passed and failed checks of flapping host
+	defaultHealthFlapThreshold = 3
+	// defaultHealthDownThreshold specifies default number of consecutive failed checks of down host
+	defaultHealthDownThreshold = 3
 )
 
 // Username/password replacers
@@ -134,6 +166,28 @@ const (
 	OnStatefulSetUpdateFailureActionIgnore = "ignore"
 )
 
+const (
+	// ReconcileModeApply - desired state is applied
+	ReconcileModeApply = "apply"
+
+	// ReconcileModeObserve - desired state is computed and compared with actual state, nothing is applied
+	ReconcileModeObserve = "observe"
+
+	// ReconcileModeApprove - safe changes are applied, disruptive changes wait for approval
+	ReconcileModeApprove = "approve"
+)
+
+const (
+	// PolicyActionIgnore - policy rule is not checked
+	PolicyActionIgnore = "ignore"
+
+	// PolicyActionWarn - policy rule violation is reported, reconcile proceeds
+	PolicyActionWarn = "warn"
+
+	// PolicyActionReject - policy rule violation is reported, reconcile is aborted
+	PolicyActionReject = "reject"
+)
+
 // OperatorConfig specifies operator configuration
 // !!! IMPORTANT !!!
 // !!! IMPORTANT !!!
@@ -163,6 +217,17 @@ type OperatorConfigRuntime struct {
 type OperatorConfigWatch struct {
 	// Namespaces where operator watches for events
 	Namespaces []string `json:"namespaces" yaml:"namespaces"`
+	// LabelSelector specifies labels of CHIs operator manages.
+	// Allows to run multiple operators in the same namespaces, each one managing its own subset of CHIs.
+	LabelSelector string `json:"labelSelector,omitempty" yaml:"labelSelector,omitempty"`
+
+	Runtime OperatorConfigWatchRuntime `json:"-" yaml:"-"`
+}
+
+// OperatorConfigWatchRuntime specifies runtime part of the watch section, filled on config normalization
+type OperatorConfigWatchRuntime struct {
+	// LabelSelector is parsed LabelSelector, so it is not parsed on each event
+	LabelSelector labels.Selector `json:"-" yaml:"-"`
 }
 
 // OperatorConfigConfig specifies Config section
@@ -270,6 +335,12 @@ type OperatorConfigClickHouse struct {
 			Connect time.Duration `json:"connect" yaml:"connect"`
 			Query   time.Duration `json:"query"   yaml:"query"`
 		} `json:"timeouts" yaml:"timeouts"`
+
+		// HealthCheck specifies how the operator checks whether ClickHouse instances are alive
+		HealthCheck struct {
+			// Mode is either "network" or "exec"
+			Mode string `json:"mode,omitempty" yaml:"mode,omitempty"`
+		} `json:"healthCheck" yaml:"healthCheck"`
 	} `json:"access" yaml:"access"`
 
 	// Metrics used to specify how the operator fetches metrics from ClickHouse instances
@@ -307,6 +378,7 @@ func (p OperatorConfigCHIPolicy) Equals(another OperatorConfigCHIPolicy) bool {
 const (
 	OperatorConfigCHIPolicyReadOnStart          OperatorConfigCHIPolicy = "ReadOnStart"
 	OperatorConfigCHIPolicyApplyOnNextReconcile OperatorConfigCHIPolicy = "ApplyOnNextReconcile"
+	OperatorConfigCHIPolicyApplyImmediately     OperatorConfigCHIPolicy = "ApplyImmediately"
 	defaultOperatorConfigCHIPolicy              OperatorConfigCHIPolicy = OperatorConfigCHIPolicyApplyOnNextReconcile
 )
 
@@ -316,6 +388,10 @@ type OperatorConfigCHI struct {
 	Policy OperatorConfigCHIPolicy `json:"policy" yaml:"policy"`
 	// Path where to look for ClickHouseInstallation templates .yaml files
 	Path string `json:"path" yaml:"path"`
+	// ClusterScopeNamespaces specifies namespaces where cluster-wide (platform) templates reside.
+	// Templates from other namespaces are applied to CHIs of the same namespace only.
+	// In case not specified, templates from all namespaces are cluster-wide.
+	ClusterScopeNamespaces []string `json:"clusterScopeNamespaces,omitempty" yaml:"clusterScopeNamespaces,omitempty"`
 
 	Runtime OperatorConfigCHIRuntime `json:"runtime,omitempty" yaml:"runtime,omitempty"`
 }
@@ -334,6 +410,9 @@ type OperatorConfigCHIRuntime struct {
 
 // OperatorConfigReconcile specifies reconcile section
 type OperatorConfigReconcile struct {
+	// Mode specifies whether desired state is applied, only observed or applied with disruptive changes approved. Either apply, observe or approve
+	Mode string `json:"mode,omitempty" yaml:"mode,omitempty"`
+
 	Runtime struct {
 		ReconcileCHIsThreadsNumber           int `json:"reconcileCHIsThreadsNumber"           yaml:"reconcileCHIsThreadsNumber"`
 		ReconcileShardsThreadsNumber         int `json:"reconcileShardsThreadsNumber"         yaml:"reconcileShardsThreadsNumber"`
@@ -356,6 +435,24 @@ type OperatorConfigReconcile struct {
 	} `json:"statefulSet" yaml:"statefulSet"`
 
 	Host OperatorConfigReconcileHost `json:"host" yaml:"host"`
+
+	Preflight OperatorConfigReconcilePreflight `json:"preflight" yaml:"preflight"`
+
+	Drift OperatorConfigReconcileDrift `json:"drift" yaml:"drift"`
+
+	Readonly OperatorConfigReconcileReadonly `json:"readonly" yaml:"readonly"`
+
+	Health OperatorConfigReconcileHealth `json:"health" yaml:"health"`
+
+	Coordination OperatorConfigReconcileCoordination `json:"coordination" yaml:"coordination"`
+
+	Policy OperatorConfigReconcilePolicy `json:"policy" yaml:"policy"`
+
+	Impersonation OperatorConfigReconcileImpersonation `json:"impersonation" yaml:"impersonation"`
+
+	Freeze OperatorConfigReconcileFreeze `json:"freeze" yaml:"freeze"`
+
+	Resync OperatorConfigReconcileResync `json:"resync" yaml:"resync"`
 }
 
 // OperatorConfigReconcileHost defines reconcile host config
@@ -368,6 +465,187 @@ type OperatorConfigReconcileHostWait struct {
 	Exclude *StringBool `json:"exclude,omitempty" yaml:"exclude,omitempty"`
 	Queries *StringBool `json:"queries,omitempty" yaml:"queries,omitempty"`
 	Include *StringBool `json:"include,omitempty" yaml:"include,omitempty"`
+	// Tables specifies whether host is kept out of cluster and services until tables are created on it
+	Tables *StringBool `json:"tables,omitempty" yaml:"tables,omitempty"`
+}
+
+// OperatorConfigReconcilePreflight defines capacity checks to be done before adding new hosts
+type OperatorConfigReconcilePreflight struct {
+	// Quotas specifies whether to check namespace ResourceQuotas
+	Quotas *StringBool `json:"quotas,omitempty" yaml:"quotas,omitempty"`
+	// Storage specifies whether to check StorageClass and PersistentVolume availability
+	Storage *StringBool `json:"storage,omitempty" yaml:"storage,omitempty"`
+	// Nodes specifies whether to check for schedulable nodes matching placement and tolerations of pods
+	Nodes *StringBool `json:"nodes,omitempty" yaml:"nodes,omitempty"`
+}
+
+// OperatorConfigReconcileDrift defines audit of configuration loaded by hosts against the generated one
+type OperatorConfigReconcileDrift struct {
+	// Enabled specifies whether configuration of hosts of reconciled CHIs is audited periodically
+	Enabled *StringBool `json:"enabled,omitempty" yaml:"enabled,omitempty"`
+}
+
+// OperatorConfigReconcileReadonly defines detection of replicas being in readonly mode
+type OperatorConfigReconcileReadonly struct {
+	// Enabled specifies whether hosts of reconciled CHIs are periodically checked for readonly replicas
+	Enabled *StringBool `json:"enabled,omitempty" yaml:"enabled,omitempty"`
+	// Restart specifies whether readonly replicas are restarted automatically
+	Restart *StringBool `json:"restart,omitempty" yaml:"restart,omitempty"`
+	// RestartDelay specifies number of seconds replica has to stay readonly before it is restarted
+	RestartDelay int `json:"restartDelay,omitempty" yaml:"restartDelay,omitempty"`
+}
+
+// OperatorConfigReconcileHealth defines tracking of health of hosts
+type OperatorConfigReconcileHealth struct {
+	// Enabled specifies whether hosts of reconciled CHIs are periodically checked for being alive
+	Enabled *StringBool `json:"enabled,omitempty" yaml:"enabled,omitempty"`
+	// Window specifies number of the latest checks kept per host
+	Window int `json:"window,omitempty" yaml:"window,omitempty"`
+	// FlapThreshold specifies number of switches between passed and failed checks within the window for host to be flapping
+	FlapThreshold int `json:"flapThreshold,omitempty" yaml:"flapThreshold,omitempty"`
+	// DownThreshold specifies number of consecutive failed checks for host to be down
+	DownThreshold int `json:"downThreshold,omitempty" yaml:"downThreshold,omitempty"`
+}
+
+// OperatorConfigPod defines pod specific parameters
+type OperatorConfigPod struct {
+	// Grace period for Pod termination.
+	TerminationGracePeriod int `json:"terminationGracePeriod" yaml:"terminationGracePeriod"`
+	// AntiAffinity spreads pods of different CHIs sharing a label across nodes
+	AntiAffinity OperatorConfigPodAntiAffinity `json:"antiAffinity" yaml:"antiAffinity"`
+	// Zones restrict pods of CHIs of namespaces to approved nodes
+	Zones []OperatorConfigPodZone `json:"zones,omitempty" yaml:"zones,omitempty"`
+	// ImagePullSecrets are attached to all pods created by the operator, ex.: to pull images from private registry
+	ImagePullSecrets []core.LocalObjectReference `json:"imagePullSecrets,omitempty" yaml:"imagePullSecrets,omitempty"`
+}
+
+// OperatorConfigPodZone defines nodes pods of CHIs of namespaces are restricted to by default
+type OperatorConfigPodZone struct {
+	// Namespaces lists namespaces the zone applies to, regexp are supported
+	Namespaces []string `json:"namespaces,omitempty" yaml:"namespaces,omitempty"`
+	// MatchLabels lists node labels pods are required to be scheduled on
+	MatchLabels map[string]string `json:"matchLabels,omitempty" yaml:"matchLabels,omitempty"`
+	// Tolerations allow pods to be scheduled on tainted nodes of the zone
+	Tolerations []core.Toleration `json:"tolerations,omitempty" yaml:"tolerations,omitempty"`
+}
+
+// OperatorConfigPodAntiAffinity defines anti-affinity between pods of different CHIs sharing a label
+type OperatorConfigPodAntiAffinity struct {
+	// Labels lists CHI labels. Pods of different CHIs having the same value of a label are spread across topology domains
+	Labels []string `json:"labels,omitempty" yaml:"labels,omitempty"`
+	// Required specifies whether anti-affinity is required, preferred otherwise
+	Required *StringBool `json:"required,omitempty" yaml:"required,omitempty"`
+	// TopologyKey specifies node label pods are spread by
+	TopologyKey string `json:"topologyKey,omitempty" yaml:"topologyKey,omitempty"`
+}
+
+// OperatorConfigReconcileCoordination defines how hosts to be added wait for coordination ensembles CHI refers to
+type OperatorConfigReconcileCoordination struct {
+	// Wait specifies whether to wait for quorum of in-cluster ClickHouse Keeper or ZooKeeper before hosts are added
+	Wait *StringBool `json:"wait,omitempty" yaml:"wait,omitempty"`
+	// Timeout specifies number of seconds to wait for quorum, reconcile.statefulSet.update.timeout is used in case of 0
+	Timeout int `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+}
+
+// OperatorConfigReconcilePolicy defines policy checks CHI has to pass before reconcile proceeds
+type OperatorConfigReconcilePolicy struct {
+	Rules   OperatorConfigReconcilePolicyRules   `json:"rules"   yaml:"rules"`
+	Webhook OperatorConfigReconcilePolicyWebhook `json:"webhook" yaml:"webhook"`
+}
+
+// OperatorConfigReconcilePolicyRules defines actions of the built-in policy rules.
+// Each action is one of ignore, warn or reject
+type OperatorConfigReconcilePolicyRules struct {
+	// ResourceLimits requires CPU and memory limits on all containers of all hosts
+	ResourceLimits string `json:"resourceLimits,omitempty" yaml:"resourceLimits,omitempty"`
+	// AntiAffinity requires pod anti-affinity or pod distribution on all hosts
+	AntiAffinity string `json:"antiAffinity,omitempty" yaml:"antiAffinity,omitempty"`
+	// Backups requires backup sidecar container on all hosts
+	Backups string `json:"backups,omitempty" yaml:"backups,omitempty"`
+}
+
+// OperatorConfigReconcilePolicyWebhook defines external policy webhook
+type OperatorConfigReconcilePolicyWebhook struct {
+	// URL of the webhook. Empty URL means no webhook to be called
+	URL string `json:"url,omitempty" yaml:"url,omitempty"`
+	// Timeout to call the webhook. In seconds
+	Timeout time.Duration `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	// FailurePolicy specifies what to do in case webhook can not be called. Either ignore or reject
+	FailurePolicy string `json:"failurePolicy,omitempty" yaml:"failurePolicy,omitempty"`
+	// CABundle specifies PEM-encoded CA certificates to verify https webhook with. System CAs are used if empty
+	CABundle string `json:"caBundle,omitempty" yaml:"caBundle,omitempty"`
+	// BearerTokenFile specifies file with token to authenticate to the webhook with. File is read on each call
+	BearerTokenFile string `json:"bearerTokenFile,omitempty" yaml:"bearerTokenFile,omitempty"`
+}
+
+// OperatorConfigReconcileImpersonation defines impersonation of per-namespace service account
+type OperatorConfigReconcileImpersonation struct {
+	// Enabled specifies whether resources of CHI are written on behalf of service account of CHI's namespace
+	Enabled *StringBool `json:"enabled,omitempty" yaml:"enabled,omitempty"`
+	// ServiceAccount specifies name of the service account to impersonate in each namespace
+	ServiceAccount string `json:"serviceAccount,omitempty" yaml:"serviceAccount,omitempty"`
+}
+
+// OperatorConfigReconcileFreeze defines time-bounded freeze of disruptive actions, ex.: for Kubernetes upgrade
+type OperatorConfigReconcileFreeze struct {
+	// Until specifies RFC3339 time the freeze lasts until. Empty or past time means no freeze
+	Until string `json:"until,omitempty" yaml:"until,omitempty"`
+	// Namespaces lists namespaces the freeze applies to, regexp are supported. Empty list means all namespaces
+	Namespaces []string `json:"namespaces,omitempty" yaml:"namespaces,omitempty"`
+}
+
+// OperatorConfigReconcileResync defines minimal number of seconds between two runs of a periodic check for the same CHI.
+// Periodic checks are done on resync of already reconciled CHIs
+type OperatorConfigReconcileResync struct {
+	DiskUsage           int `json:"diskUsage,omitempty"           yaml:"diskUsage,omitempty"`
+	QuotaUsage          int `json:"quotaUsage,omitempty"          yaml:"quotaUsage,omitempty"`
+	ConfigDrift         int `json:"configDrift,omitempty"         yaml:"configDrift,omitempty"`
+	HostsHealth         int `json:"hostsHealth,omitempty"         yaml:"hostsHealth,omitempty"`
+	ReadonlyReplicas    int `json:"readonlyReplicas,omitempty"    yaml:"readonlyReplicas,omitempty"`
+	Rebalancing         int `json:"rebalancing,omitempty"         yaml:"rebalancing,omitempty"`
+	ScheduledRestart    int `json:"scheduledRestart,omitempty"    yaml:"scheduledRestart,omitempty"`
+	CertificateRotation int `json:"certificateRotation,omitempty" yaml:"certificateRotation,omitempty"`
+}
+
+// Possible types of notification sinks
+const (
+	// NotificationSinkTypeWebhook specifies sink receiving notifications as JSON objects
+	NotificationSinkTypeWebhook = "webhook"
+	// NotificationSinkTypeSlack specifies sink receiving notifications as Slack incoming webhook messages
+	NotificationSinkTypeSlack = "slack"
+)
+
+// OperatorConfigNotifications specifies notifications section
+type OperatorConfigNotifications struct {
+	// Sinks lifecycle events are delivered to
+	Sinks []OperatorConfigNotificationSink `json:"sinks,omitempty" yaml:"sinks,omitempty"`
+}
+
+// OperatorConfigNotificationSink specifies where and which lifecycle events are delivered to
+type OperatorConfigNotificationSink struct {
+	// Name of the sink, used in logs only
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+	// Type is either webhook or slack
+	Type string `json:"type,omitempty" yaml:"type,omitempty"`
+	// URL notifications are posted to
+	URL string `json:"url,omitempty" yaml:"url,omitempty"`
+	// Events to be delivered. Empty list means all events
+	Events []string `json:"events,omitempty" yaml:"events,omitempty"`
+	// Timeout to deliver notification. In seconds
+	Timeout time.Duration `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+}
+
+// IsSubscribed checks whether the sink wants to receive the event
+func (s *OperatorConfigNotificationSink) IsSubscribed(event string) bool {
+	if len(s.Events) == 0 {
+		return true
+	}
+	for _, e := range s.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
 }
 
 // OperatorConfigAnnotation specifies annotation section
@@ -409,11 +687,9 @@ type OperatorConfig struct {
 		// Revision history limit
 		RevisionHistoryLimit int `json:"revisionHistoryLimit" yaml:"revisionHistoryLimit"`
 	} `json:"statefulSet" yaml:"statefulSet"`
-	Pod struct {
-		// Grace period for Pod termination.
-		TerminationGracePeriod int `json:"terminationGracePeriod" yaml:"terminationGracePeriod"`
-	} `json:"pod" yaml:"pod"`
-	Logger struct {
+	Pod           OperatorConfigPod           `json:"pod"           yaml:"pod"`
+	Notifications OperatorConfigNotifications `json:"notifications" yaml:"notifications"`
+	Logger        struct {
 		// Logger section
 		LogToStderr     string `json:"logtostderr"      yaml:"logtostderr"`
 		AlsoLogToStderr string `json:"alsologtostderr"  yaml:"alsologtostderr"`
@@ -593,7 +869,9 @@ func (c *OperatorConfig) unlistCHITemplate(template *ClickHouseInstallation) {
 	c.Template.CHI.Runtime.Templates = named
 }
 
-// FindTemplate finds specified template within possibly specified namespace
+// FindTemplate finds template by the reference.
+// Reference without namespace is looked up in the fallback namespace (namespace of the CHI) first
+// and in cluster scope namespaces afterwards, in the order they are specified.
 func (c *OperatorConfig) FindTemplate(templateRef *ChiTemplateRef, fallbackNamespace string) *ClickHouseInstallation {
 	c.Template.CHI.Runtime.mutex.RLock()
 	defer c.Template.CHI.Runtime.mutex.RUnlock()
@@ -607,7 +885,7 @@ func (c *OperatorConfig) FindTemplate(templateRef *ChiTemplateRef, fallbackNames
 	}
 
 	// Exact match is not possible.
-	// Let's try to find by name only in "predefined" namespace
+	// Let's try to find by name only in "predefined" namespaces
 
 	if templateRef.Namespace != "" {
 		// With fully-specified template namespace+name pair exact match is applicable only
@@ -615,35 +893,61 @@ func (c *OperatorConfig) FindTemplate(templateRef *ChiTemplateRef, fallbackNames
 		return nil
 	}
 
-	// Look for templates with specified name in "predefined" namespace
+	// Look for templates with specified name in "predefined" namespaces.
+	// Namespace of the CHI has priority over cluster scope namespaces.
 
-	for _, template := range c.Template.CHI.Runtime.Templates {
-		if template.MatchFullName(fallbackNamespace, templateRef.Name) {
-			// Found template with searched name in "predefined" namespace
-			return template
+	namespaces := append([]string{fallbackNamespace}, c.Template.CHI.ClusterScopeNamespaces...)
+	for _, namespace := range namespaces {
+		for _, template := range c.Template.CHI.Runtime.Templates {
+			if template.MatchFullName(namespace, templateRef.Name) {
+				// Found template with searched name in "predefined" namespace
+				return template
+			}
 		}
 	}
 
 	return nil
 }
 
-// GetAutoTemplates gets all auto templates.
-// Auto templates are sorted alphabetically by tuple: namespace, name
-func (c *OperatorConfig) GetAutoTemplates() []*ClickHouseInstallation {
+// isClusterScopeTemplate checks whether template is a cluster-wide one
+func (c *OperatorConfig) isClusterScopeTemplate(template *ClickHouseInstallation) bool {
+	if len(c.Template.CHI.ClusterScopeNamespaces) == 0 {
+		// All templates are cluster-wide
+		return true
+	}
+	// Templates from files have no namespace and are cluster-wide
+	return (template.Namespace == "") || util.InArray(template.Namespace, c.Template.CHI.ClusterScopeNamespaces)
+}
+
+// GetAutoTemplates gets auto templates applicable to CHIs in specified namespace.
+// Cluster-wide templates go first and namespace templates go last, so namespace templates override cluster-wide ones.
+// Within each group templates are sorted alphabetically by tuple: namespace, name
+func (c *OperatorConfig) GetAutoTemplates(namespace string) []*ClickHouseInstallation {
 	c.Template.CHI.Runtime.mutex.RLock()
 	defer c.Template.CHI.Runtime.mutex.RUnlock()
 
 	// Extract auto-templates from all templates listed
-	var autoTemplates []*ClickHouseInstallation
+	var clusterTemplates []*ClickHouseInstallation
+	var namespaceTemplates []*ClickHouseInstallation
 	for _, _template := range c.Template.CHI.Runtime.Templates {
-		if _template.IsAuto() {
-			autoTemplates = append(autoTemplates, _template)
+		switch {
+		case !_template.IsAuto():
+			continue
+		case _template.MatchNamespace(namespace):
+			namespaceTemplates = append(namespaceTemplates, _template)
+		case c.isClusterScopeTemplate(_template):
+			clusterTemplates = append(clusterTemplates, _template)
 		}
 	}
 
+	return append(sortTemplates(clusterTemplates), sortTemplates(namespaceTemplates)...)
+}
+
+// sortTemplates sorts templates alphabetically by tuple: namespace, name
+func sortTemplates(templates []*ClickHouseInstallation) []*ClickHouseInstallation {
 	// Prepare sorted unique list of namespaces
 	var namespaces []string
-	for _, _template := range autoTemplates {
+	for _, _template := range templates {
 		// Append template's namespace to the list of namespaces
 		if !util.StringSliceContains(namespaces, _template.Namespace) {
 			namespaces = append(namespaces, _template.Namespace)
@@ -657,7 +961,7 @@ func (c *OperatorConfig) GetAutoTemplates() []*ClickHouseInstallation {
 	for _, namespace := range namespaces {
 		// Prepare sorted unique list of names within this namespace
 		var names []string
-		for _, _template := range autoTemplates {
+		for _, _template := range templates {
 			if _template.MatchNamespace(namespace) && !util.StringSliceContains(names, _template.Name) {
 				names = append(names, _template.Name)
 			}
@@ -667,7 +971,7 @@ func (c *OperatorConfig) GetAutoTemplates() []*ClickHouseInstallation {
 		// Walk over sorted unique list of names within this namespace
 		// and append first unseen before template to the result list of templates
 		for _, name := range names {
-			for _, _template := range autoTemplates {
+			for _, _template := range templates {
 				if _template.MatchFullName(namespace, name) && !_template.FoundIn(sortedTemplates) {
 					sortedTemplates = append(sortedTemplates, _template)
 				}
@@ -717,6 +1021,8 @@ func (c *OperatorConfig) normalizeSectionTemplate() {
 		c.Template.CHI.Policy = OperatorConfigCHIPolicyReadOnStart
 	case p.Equals(OperatorConfigCHIPolicyApplyOnNextReconcile):
 		c.Template.CHI.Policy = OperatorConfigCHIPolicyApplyOnNextReconcile
+	case p.Equals(OperatorConfigCHIPolicyApplyImmediately):
+		c.Template.CHI.Policy = OperatorConfigCHIPolicyApplyImmediately
 	default:
 		c.Template.CHI.Policy = defaultOperatorConfigCHIPolicy
 	}
@@ -752,6 +1058,109 @@ func (c *OperatorConfig) normalizeSectionReconcileStatefulSet() {
 	}
 }
 
+// normalizePolicyAction normalizes policy action to one of the known actions
+func normalizePolicyAction(action string, _default string) string {
+	switch strings.ToLower(action) {
+	case PolicyActionIgnore:
+		return PolicyActionIgnore
+	case PolicyActionWarn:
+		return PolicyActionWarn
+	case PolicyActionReject:
+		return PolicyActionReject
+	default:
+		return _default
+	}
+}
+
+func (c *OperatorConfig) normalizeSectionWatch() {
+	c.Watch.Runtime.LabelSelector = labels.Everything()
+	if c.Watch.LabelSelector == "" {
+		// In case no label selector specified - watch all CHIs
+		return
+	}
+
+	selector, err := labels.Parse(c.Watch.LabelSelector)
+	if err != nil {
+		// Invalid label selector matches nothing, so misconfigured operator would not interfere with other operators
+		log.V(1).Infof("unable to parse watch label selector %s err: %v", c.Watch.LabelSelector, err)
+		selector = labels.Nothing()
+	}
+	c.Watch.Runtime.LabelSelector = selector
+}
+
+func (c *OperatorConfig) normalizeSectionReconcileImpersonation() {
+	if c.Reconcile.Impersonation.ServiceAccount == "" {
+		c.Reconcile.Impersonation.ServiceAccount = defaultImpersonationServiceAccount
+	}
+}
+
+func (c *OperatorConfig) normalizeSectionReconcileResync() {
+	resync := &c.Reconcile.Resync
+	for _, interval := range []struct {
+		value        *int
+		defaultValue int
+	}{
+		{&resync.DiskUsage, defaultResyncInterval},
+		{&resync.QuotaUsage, defaultResyncSlowInterval},
+		{&resync.ConfigDrift, defaultResyncSlowInterval},
+		{&resync.HostsHealth, defaultResyncInterval},
+		{&resync.ReadonlyReplicas, defaultResyncInterval},
+		{&resync.Rebalancing, defaultResyncInterval},
+		{&resync.ScheduledRestart, defaultResyncInterval},
+		{&resync.CertificateRotation, defaultResyncCertificateRotationInterval},
+	} {
+		if *interval.value <= 0 {
+			*interval.value = interval.defaultValue
+		}
+	}
+}
+
+func (c *OperatorConfig) normalizeSectionReconcileHealth() {
+	if c.Reconcile.Health.Window <= 0 {
+		c.Reconcile.Health.Window = defaultHealthWindow
+	}
+	if c.Reconcile.Health.FlapThreshold <= 0 {
+		c.Reconcile.Health.FlapThreshold = defaultHealthFlapThreshold
+	}
+	if c.Reconcile.Health.DownThreshold <= 0 {
+		c.Reconcile.Health.DownThreshold = defaultHealthDownThreshold
+	}
+	// Down host has to fit into the window
+	if c.Reconcile.Health.DownThreshold > c.Reconcile.Health.Window {
+		c.Reconcile.Health.DownThreshold = c.Reconcile.Health.Window
+	}
+}
+
+func (c *OperatorConfig) normalizeSectionReconcileMode() {
+	switch strings.ToLower(c.Reconcile.Mode) {
+	case ReconcileModeObserve:
+		c.Reconcile.Mode = ReconcileModeObserve
+	case ReconcileModeApprove:
+		c.Reconcile.Mode = ReconcileModeApprove
+	default:
+		c.Reconcile.Mode = ReconcileModeApply
+	}
+}
+
+func (c *OperatorConfig) normalizeSectionReconcilePolicy() {
+	c.Reconcile.Policy.Rules.ResourceLimits = normalizePolicyAction(c.Reconcile.Policy.Rules.ResourceLimits, PolicyActionIgnore)
+	c.Reconcile.Policy.Rules.AntiAffinity = normalizePolicyAction(c.Reconcile.Policy.Rules.AntiAffinity, PolicyActionIgnore)
+	c.Reconcile.Policy.Rules.Backups = normalizePolicyAction(c.Reconcile.Policy.Rules.Backups, PolicyActionIgnore)
+
+	if c.Reconcile.Policy.Webhook.Timeout == 0 {
+		c.Reconcile.Policy.Webhook.Timeout = defaultPolicyWebhookTimeout
+	}
+	// Adjust seconds to time.Duration
+	c.Reconcile.Policy.Webhook.Timeout = c.Reconcile.Policy.Webhook.Timeout * time.Second
+
+	// Webhook failure can either be ignored or reject CHI
+	if strings.ToLower(c.Reconcile.Policy.Webhook.FailurePolicy) == PolicyActionReject {
+		c.Reconcile.Policy.Webhook.FailurePolicy = PolicyActionReject
+	} else {
+		c.Reconcile.Policy.Webhook.FailurePolicy = PolicyActionIgnore
+	}
+}
+
 func (c *OperatorConfig) normalizeSectionClickHouseConfigurationUserDefault() {
 	// Default values for ClickHouse user configuration
 	// 1. user/profile
@@ -825,6 +1234,19 @@ func (c *OperatorConfig) normalizeSectionClickHouseAccess() {
 	// Adjust seconds to time.Duration
 	c.ClickHouse.Access.Timeouts.Query = c.ClickHouse.Access.Timeouts.Query * time.Second
 
+	switch strings.ToLower(c.ClickHouse.Access.HealthCheck.Mode) {
+	case ChHealthCheckModeNetwork:
+		c.ClickHouse.Access.HealthCheck.Mode = ChHealthCheckModeNetwork
+	case ChHealthCheckModeExec:
+		c.ClickHouse.Access.HealthCheck.Mode = ChHealthCheckModeExec
+	default:
+		c.ClickHouse.Access.HealthCheck.Mode = defaultChHealthCheckMode
+	}
+}
+
+// IsHealthCheckModeExec checks whether ClickHouse instances are health-checked by clickhouse-client executed inside the pod
+func (c *OperatorConfig) IsHealthCheckModeExec() bool {
+	return c.ClickHouse.Access.HealthCheck.Mode == ChHealthCheckModeExec
 }
 
 func (c *OperatorConfig) normalizeSectionClickHouseMetrics() {
@@ -881,6 +1303,25 @@ func (c *OperatorConfig) normalizeSectionPod() {
 	if c.Pod.TerminationGracePeriod == 0 {
 		c.Pod.TerminationGracePeriod = defaultTerminationGracePeriod
 	}
+	if c.Pod.AntiAffinity.TopologyKey == "" {
+		c.Pod.AntiAffinity.TopologyKey = core.LabelHostname
+	}
+}
+
+func (c *OperatorConfig) normalizeSectionNotifications() {
+	for i := range c.Notifications.Sinks {
+		sink := &c.Notifications.Sinks[i]
+		if strings.ToLower(sink.Type) == NotificationSinkTypeSlack {
+			sink.Type = NotificationSinkTypeSlack
+		} else {
+			sink.Type = NotificationSinkTypeWebhook
+		}
+		if sink.Timeout == 0 {
+			sink.Timeout = defaultNotificationSinkTimeout
+		}
+		// Adjust seconds to time.Duration
+		sink.Timeout = sink.Timeout * time.Second
+	}
 }
 
 // normalize() makes fully-and-correctly filled OperatorConfig
@@ -893,12 +1334,19 @@ func (c *OperatorConfig) normalize() {
 	c.normalizeSectionClickHouseAccess()
 	c.normalizeSectionClickHouseMetrics()
 	c.normalizeSectionTemplate()
+	c.normalizeSectionWatch()
 	c.normalizeSectionReconcileStatefulSet()
 	c.normalizeSectionReconcileRuntime()
+	c.normalizeSectionReconcilePolicy()
+	c.normalizeSectionReconcileMode()
+	c.normalizeSectionReconcileImpersonation()
+	c.normalizeSectionReconcileHealth()
+	c.normalizeSectionReconcileResync()
 	c.normalizeSectionLogger()
 	c.normalizeSectionLabel()
 	c.normalizeSectionStatefulSet()
 	c.normalizeSectionPod()
+	c.normalizeSectionNotifications()
 }
 
 // applyEnvVarParams applies ENV VARS over config
@@ -908,6 +1356,18 @@ func (c *OperatorConfig) applyEnvVarParams() {
 		c.Watch.Namespaces = []string{ns}
 	}
 
+	if selector := os.Getenv(deployment.WATCH_LABEL_SELECTOR); len(selector) > 0 {
+		// We have WATCH_LABEL_SELECTOR explicitly specified
+		c.Watch.LabelSelector = selector
+		c.normalizeSectionWatch()
+	}
+
+	if mode := os.Getenv(deployment.RECONCILE_MODE); len(mode) > 0 {
+		// We have RECONCILE_MODE explicitly specified
+		c.Reconcile.Mode = mode
+		c.normalizeSectionReconcileMode()
+	}
+
 	if nss := os.Getenv(deployment.WATCH_NAMESPACES); len(nss) > 0 {
 		// We have WATCH_NAMESPACES explicitly specified
 		namespaces := strings.FieldsFunc(nss, func(r rune) bool {
@@ -993,6 +1453,11 @@ func (c *OperatorConfig) String(hideCredentials bool) string {
 			conf.ClickHouse.Access.Secret.Runtime.Password = PasswordReplacer
 		}
 
+		// Sink URLs, such as Slack incoming webhooks, carry credentials
+		for i := range conf.Notifications.Sinks {
+			conf.Notifications.Sinks[i].URL = PasswordReplacer
+		}
+
 		// DEPRECATED
 		conf.CHConfigUserDefaultPassword = PasswordReplacer
 		conf.CHUsername = UsernameReplacer
@@ -1016,6 +1481,83 @@ func (c *OperatorConfig) IsWatchedNamespace(namespace string) bool {
 	return util.InArrayWithRegexp(namespace, c.Watch.Namespaces)
 }
 
+// GetPodZoneLabels returns node labels pods of CHIs of the namespace are restricted to.
+// Labels of all zones the namespace matches are merged, the latter zone wins
+func (c *OperatorConfig) GetPodZoneLabels(namespace string) map[string]string {
+	var labels map[string]string
+	for i := range c.Pod.Zones {
+		zone := &c.Pod.Zones[i]
+		if util.InArrayWithRegexp(namespace, zone.Namespaces) {
+			labels = util.MergeStringMapsOverwrite(labels, zone.MatchLabels)
+		}
+	}
+	return labels
+}
+
+// GetPodZoneTolerations returns tolerations of all zones pods of CHIs of the namespace are restricted to
+func (c *OperatorConfig) GetPodZoneTolerations(namespace string) []core.Toleration {
+	var tolerations []core.Toleration
+	for i := range c.Pod.Zones {
+		zone := &c.Pod.Zones[i]
+		if util.InArrayWithRegexp(namespace, zone.Namespaces) {
+			for j := range zone.Tolerations {
+				tolerations = append(tolerations, *zone.Tolerations[j].DeepCopy())
+			}
+		}
+	}
+	return tolerations
+}
+
+// IsWatchedLabels returns whether CHI with specified labels matches watch label selector.
+// Invalid label selector matches nothing, so misconfigured operator would not interfere with other operators.
+func (c *OperatorConfig) IsWatchedLabels(_labels map[string]string) bool {
+	if c.Watch.Runtime.LabelSelector == nil {
+		// Label selector is parsed on config normalization, not normalized config watches all CHIs
+		return true
+	}
+	return c.Watch.Runtime.LabelSelector.Matches(labels.Set(_labels))
+}
+
+// IsObserveMode returns whether operator only observes CHIs without applying anything
+func (c *OperatorConfig) IsObserveMode() bool {
+	return c.Reconcile.Mode == ReconcileModeObserve
+}
+
+// IsApproveMode returns whether disruptive changes have to be approved before being applied
+func (c *OperatorConfig) IsApproveMode() bool {
+	return c.Reconcile.Mode == ReconcileModeApprove
+}
+
+// GetFreezeUntil gets time disruptive actions on CHIs of the specified namespace are frozen until.
+// Returns false in case namespace is not frozen at the specified moment
+func (c *OperatorConfig) GetFreezeUntil(namespace string, now time.Time) (time.Time, bool) {
+	freeze := c.Reconcile.Freeze
+	if freeze.Until == "" {
+		return time.Time{}, false
+	}
+	until, err := time.Parse(time.RFC3339, freeze.Until)
+	if err != nil {
+		log.V(1).Infof("unable to parse freeze until %s err: %v", freeze.Until, err)
+		return time.Time{}, false
+	}
+	if !now.Before(until) {
+		return time.Time{}, false
+	}
+	if (len(freeze.Namespaces) > 0) && !util.InArrayWithRegexp(namespace, freeze.Namespaces) {
+		return time.Time{}, false
+	}
+	return until, true
+}
+
+// GetImpersonatedUser gets name of the user to impersonate while writing resources into specified namespace.
+// Empty name means no impersonation
+func (c *OperatorConfig) GetImpersonatedUser(namespace string) string {
+	if !c.Reconcile.Impersonation.Enabled.Value() {
+		return ""
+	}
+	return "system:serviceaccount:" + namespace + ":" + c.Reconcile.Impersonation.ServiceAccount
+}
+
 // GetInformerNamespace is a TODO stub
 // Namespace where informers would watch notifications from
 // The thing is that InformerFactory can accept only one parameter as watched namespace,
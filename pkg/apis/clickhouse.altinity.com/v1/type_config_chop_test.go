@@ -0,0 +1,79 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGetFreezeUntil(t *testing.T) {
+	now := time.Date(2024, time.June, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		freeze    OperatorConfigReconcileFreeze
+		namespace string
+		frozen    bool
+	}{
+		{name: "no freeze", freeze: OperatorConfigReconcileFreeze{}, namespace: "prod", frozen: false},
+		{name: "all namespaces", freeze: OperatorConfigReconcileFreeze{Until: "2024-06-01T18:00:00Z"}, namespace: "prod", frozen: true},
+		{name: "expired", freeze: OperatorConfigReconcileFreeze{Until: "2024-06-01T12:00:00Z"}, namespace: "prod", frozen: false},
+		{name: "matching namespace", freeze: OperatorConfigReconcileFreeze{Until: "2024-06-02T00:00:00+02:00", Namespaces: []string{"prod-.*"}}, namespace: "prod-eu", frozen: true},
+		{name: "other namespace", freeze: OperatorConfigReconcileFreeze{Until: "2024-06-02T00:00:00Z", Namespaces: []string{"prod-.*"}}, namespace: "dev", frozen: false},
+		{name: "malformed", freeze: OperatorConfigReconcileFreeze{Until: "tomorrow"}, namespace: "prod", frozen: false},
+	}
+	for _, test := range tests {
+		config := &OperatorConfig{}
+		config.Reconcile.Freeze = test.freeze
+		if _, frozen := config.GetFreezeUntil(test.namespace, now); frozen != test.frozen {
+			t.Errorf("%s: got %v want %v", test.name, frozen, test.frozen)
+		}
+	}
+}
+
+func TestIsWatchedLabels(t *testing.T) {
+	tests := []struct {
+		name     string
+		selector string
+		labels   map[string]string
+		watched  bool
+	}{
+		{name: "no selector", selector: "", labels: map[string]string{"team": "a"}, watched: true},
+		{name: "no selector no labels", selector: "", labels: nil, watched: true},
+		{name: "matching", selector: "team=a", labels: map[string]string{"team": "a"}, watched: true},
+		{name: "other value", selector: "team=a", labels: map[string]string{"team": "b"}, watched: false},
+		{name: "no labels", selector: "team=a", labels: nil, watched: false},
+		{name: "set based", selector: "team in (a,b),!legacy", labels: map[string]string{"team": "b"}, watched: true},
+		{name: "excluded", selector: "team in (a,b),!legacy", labels: map[string]string{"team": "b", "legacy": "yes"}, watched: false},
+		{name: "invalid", selector: "team==", labels: map[string]string{"team": "a"}, watched: false},
+	}
+	for _, test := range tests {
+		config := &OperatorConfig{}
+		config.Watch.LabelSelector = test.selector
+		config.normalizeSectionWatch()
+		if watched := config.IsWatchedLabels(test.labels); watched != test.watched {
+			t.Errorf("%s: got %v want %v", test.name, watched, test.watched)
+		}
+	}
+
+	// Selector is parsed once on normalization, later changes of the raw value are not picked up
+	config := &OperatorConfig{}
+	config.Watch.LabelSelector = "team=a"
+	config.normalizeSectionWatch()
+	config.Watch.LabelSelector = "team=b"
+	if !config.IsWatchedLabels(map[string]string{"team": "a"}) {
+		t.Errorf("parsed selector is not used")
+	}
+}
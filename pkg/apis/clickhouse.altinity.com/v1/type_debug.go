@@ -0,0 +1,156 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+// ChiDebug defines debug section of .spec
+type ChiDebug struct {
+	CoreDump          *StringBool `json:"coreDump,omitempty"               yaml:"coreDump,omitempty"`
+	CoreDumpSizeLimit string      `json:"coreDumpSizeLimit,omitempty"      yaml:"coreDumpSizeLimit,omitempty"`
+	// CoreDumpVolume specifies volume core dumps are written to, emptyDir is used in case neither claim nor host path is specified
+	CoreDumpVolume *ChiCoreDumpVolume `json:"coreDumpVolume,omitempty" yaml:"coreDumpVolume,omitempty"`
+	// CoreDumpPattern specifies whether node's kernel.core_pattern is pointed into the core dumps volume
+	CoreDumpPattern        *StringBool `json:"coreDumpPattern,omitempty"        yaml:"coreDumpPattern,omitempty"`
+	IntrospectionFunctions *StringBool `json:"introspectionFunctions,omitempty" yaml:"introspectionFunctions,omitempty"`
+}
+
+// ChiCoreDumpVolume specifies volume core dumps are written to
+type ChiCoreDumpVolume struct {
+	// VolumeClaimTemplate specifies name of the volume claim template, core dumps volume is claimed by
+	VolumeClaimTemplate string `json:"volumeClaimTemplate,omitempty" yaml:"volumeClaimTemplate,omitempty"`
+	// HostPath specifies path on the node, core dumps volume is mounted from
+	HostPath string `json:"hostPath,omitempty" yaml:"hostPath,omitempty"`
+}
+
+// GetVolumeClaimTemplate gets name of the volume claim template of core dumps volume
+func (v *ChiCoreDumpVolume) GetVolumeClaimTemplate() string {
+	if v == nil {
+		return ""
+	}
+	return v.VolumeClaimTemplate
+}
+
+// GetHostPath gets node's path of core dumps volume
+func (v *ChiCoreDumpVolume) GetHostPath() string {
+	if v == nil {
+		return ""
+	}
+	return v.HostPath
+}
+
+// NewChiDebug creates new ChiDebug object
+func NewChiDebug() *ChiDebug {
+	return new(ChiDebug)
+}
+
+// IsCoreDump checks whether core dumps are enabled
+func (d *ChiDebug) IsCoreDump() bool {
+	if d == nil {
+		return false
+	}
+	return d.CoreDump.IsTrue()
+}
+
+// HasCoreDumpSizeLimit checks whether core dump size limit is specified
+func (d *ChiDebug) HasCoreDumpSizeLimit() bool {
+	if d == nil {
+		return false
+	}
+	return len(d.CoreDumpSizeLimit) > 0
+}
+
+// GetCoreDumpSizeLimit gets core dump size limit
+func (d *ChiDebug) GetCoreDumpSizeLimit() string {
+	if d == nil {
+		return ""
+	}
+	return d.CoreDumpSizeLimit
+}
+
+// GetCoreDumpVolume gets core dumps volume
+func (d *ChiDebug) GetCoreDumpVolume() *ChiCoreDumpVolume {
+	if d == nil {
+		return nil
+	}
+	return d.CoreDumpVolume
+}
+
+// IsCoreDumpPattern checks whether node's kernel.core_pattern is to be pointed into the core dumps volume
+func (d *ChiDebug) IsCoreDumpPattern() bool {
+	if d == nil {
+		return false
+	}
+	return d.IsCoreDump() && d.CoreDumpPattern.IsTrue()
+}
+
+// IsIntrospectionFunctions checks whether introspection functions are enabled
+func (d *ChiDebug) IsIntrospectionFunctions() bool {
+	if d == nil {
+		return false
+	}
+	return d.IntrospectionFunctions.IsTrue()
+}
+
+// MergeFrom merges from specified object
+func (d *ChiDebug) MergeFrom(from *ChiDebug, _type MergeType) *ChiDebug {
+	if from == nil {
+		return d
+	}
+
+	if d == nil {
+		d = NewChiDebug()
+	}
+
+	switch _type {
+	case MergeTypeFillEmptyValues:
+		if !d.CoreDump.HasValue() {
+			d.CoreDump = d.CoreDump.MergeFrom(from.CoreDump)
+		}
+		if d.CoreDumpSizeLimit == "" {
+			d.CoreDumpSizeLimit = from.CoreDumpSizeLimit
+		}
+		if d.CoreDumpVolume == nil {
+			d.CoreDumpVolume = from.CoreDumpVolume.DeepCopy()
+		}
+		if !d.CoreDumpPattern.HasValue() {
+			d.CoreDumpPattern = d.CoreDumpPattern.MergeFrom(from.CoreDumpPattern)
+		}
+		if !d.IntrospectionFunctions.HasValue() {
+			d.IntrospectionFunctions = d.IntrospectionFunctions.MergeFrom(from.IntrospectionFunctions)
+		}
+	case MergeTypeOverrideByNonEmptyValues:
+		if from.CoreDump.HasValue() {
+			// Override by non-empty values only
+			d.CoreDump = from.CoreDump
+		}
+		if from.CoreDumpSizeLimit != "" {
+			// Override by non-empty values only
+			d.CoreDumpSizeLimit = from.CoreDumpSizeLimit
+		}
+		if from.CoreDumpVolume != nil {
+			// Override by non-empty values only
+			d.CoreDumpVolume = from.CoreDumpVolume.DeepCopy()
+		}
+		if from.CoreDumpPattern.HasValue() {
+			// Override by non-empty values only
+			d.CoreDumpPattern = from.CoreDumpPattern
+		}
+		if from.IntrospectionFunctions.HasValue() {
+			// Override by non-empty values only
+			d.IntrospectionFunctions = from.IntrospectionFunctions
+		}
+	}
+
+	return d
+}
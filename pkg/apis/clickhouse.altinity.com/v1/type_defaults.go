@@ -14,12 +14,36 @@
 
 package v1
 
+import (
+	core "k8s.io/api/core/v1"
+
+	"github.com/altinity/clickhouse-operator/pkg/util"
+)
+
 // ChiDefaults defines defaults section of .spec
 type ChiDefaults struct {
 	ReplicasUseFQDN   *StringBool        `json:"replicasUseFQDN,omitempty"    yaml:"replicasUseFQDN,omitempty"`
 	DistributedDDL    *ChiDistributedDDL `json:"distributedDDL,omitempty"     yaml:"distributedDDL,omitempty"`
 	StorageManagement *StorageManagement `json:"storageManagement,omitempty"  yaml:"storageManagement,omitempty"`
 	Templates         *ChiTemplateNames  `json:"templates,omitempty"          yaml:"templates,omitempty"`
+	AutoTuning        *StringBool        `json:"autoTuning,omitempty"         yaml:"autoTuning,omitempty"`
+	// ZookeeperPathTemplate specifies default template of the ZooKeeper path macro of clusters
+	ZookeeperPathTemplate string `json:"zookeeperPathTemplate,omitempty" yaml:"zookeeperPathTemplate,omitempty"`
+	// Resources specifies default resources of ClickHouse container of all hosts
+	Resources *core.ResourceRequirements `json:"resources,omitempty" yaml:"resources,omitempty"`
+	// ManagedProfiles specifies names of profiles shipped with the operator to be rendered
+	ManagedProfiles []string `json:"managedProfiles,omitempty" yaml:"managedProfiles,omitempty"`
+	// QuotaUsageThreshold specifies percentage of a quota limit at which QuotaPressure condition is raised.
+	// Zero means quota usage is not monitored.
+	QuotaUsageThreshold int `json:"quotaUsageThreshold,omitempty" yaml:"quotaUsageThreshold,omitempty"`
+	// PriorityClassName specifies default priority class of pods of all hosts
+	PriorityClassName string `json:"priorityClassName,omitempty" yaml:"priorityClassName,omitempty"`
+	// HostNetwork specifies whether pods of all hosts run in the host network namespace of their nodes
+	HostNetwork *StringBool `json:"hostNetwork,omitempty" yaml:"hostNetwork,omitempty"`
+	// Labels are set on all resources created for the CHI
+	Labels map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+	// Annotations are set on all resources created for the CHI
+	Annotations map[string]string `json:"annotations,omitempty" yaml:"annotations,omitempty"`
 }
 
 // NewChiDefaults creates new ChiDefaults object
@@ -27,6 +51,30 @@ func NewChiDefaults() *ChiDefaults {
 	return new(ChiDefaults)
 }
 
+// GetQuotaUsageThreshold gets percentage of a quota limit at which QuotaPressure condition is raised
+func (defaults *ChiDefaults) GetQuotaUsageThreshold() int {
+	if defaults == nil {
+		return 0
+	}
+	return defaults.QuotaUsageThreshold
+}
+
+// GetLabels gets labels to be set on all resources created for the CHI
+func (defaults *ChiDefaults) GetLabels() map[string]string {
+	if defaults == nil {
+		return nil
+	}
+	return defaults.Labels
+}
+
+// GetAnnotations gets annotations to be set on all resources created for the CHI
+func (defaults *ChiDefaults) GetAnnotations() map[string]string {
+	if defaults == nil {
+		return nil
+	}
+	return defaults.Annotations
+}
+
 // MergeFrom merges from specified object
 func (defaults *ChiDefaults) MergeFrom(from *ChiDefaults, _type MergeType) *ChiDefaults {
 	if from == nil {
@@ -39,14 +87,65 @@ func (defaults *ChiDefaults) MergeFrom(from *ChiDefaults, _type MergeType) *ChiD
 
 	switch _type {
 	case MergeTypeFillEmptyValues:
-		if !from.ReplicasUseFQDN.HasValue() {
+		if !defaults.ReplicasUseFQDN.HasValue() {
 			defaults.ReplicasUseFQDN = defaults.ReplicasUseFQDN.MergeFrom(from.ReplicasUseFQDN)
 		}
+		if !defaults.AutoTuning.HasValue() {
+			defaults.AutoTuning = defaults.AutoTuning.MergeFrom(from.AutoTuning)
+		}
+		if defaults.ZookeeperPathTemplate == "" {
+			defaults.ZookeeperPathTemplate = from.ZookeeperPathTemplate
+		}
+		defaults.Resources = MergeResourceRequirements(defaults.Resources, from.Resources.DeepCopy())
+		if len(defaults.ManagedProfiles) == 0 {
+			defaults.ManagedProfiles = append([]string{}, from.ManagedProfiles...)
+		}
+		if defaults.QuotaUsageThreshold == 0 {
+			defaults.QuotaUsageThreshold = from.QuotaUsageThreshold
+		}
+		if defaults.PriorityClassName == "" {
+			defaults.PriorityClassName = from.PriorityClassName
+		}
+		if !defaults.HostNetwork.HasValue() {
+			defaults.HostNetwork = defaults.HostNetwork.MergeFrom(from.HostNetwork)
+		}
+		defaults.Labels = util.MergeStringMapsPreserve(defaults.Labels, from.Labels)
+		defaults.Annotations = util.MergeStringMapsPreserve(defaults.Annotations, from.Annotations)
 	case MergeTypeOverrideByNonEmptyValues:
 		if from.ReplicasUseFQDN.HasValue() {
 			// Override by non-empty values only
-			defaults.ReplicasUseFQDN = defaults.ReplicasUseFQDN.MergeFrom(from.ReplicasUseFQDN)
+			defaults.ReplicasUseFQDN = from.ReplicasUseFQDN.MergeFrom(defaults.ReplicasUseFQDN)
+		}
+		if from.AutoTuning.HasValue() {
+			// Override by non-empty values only
+			defaults.AutoTuning = from.AutoTuning.MergeFrom(defaults.AutoTuning)
+		}
+		if from.ZookeeperPathTemplate != "" {
+			// Override by non-empty values only
+			defaults.ZookeeperPathTemplate = from.ZookeeperPathTemplate
+		}
+		if from.Resources != nil {
+			// Override by non-empty values only
+			defaults.Resources = MergeResourceRequirements(from.Resources.DeepCopy(), defaults.Resources)
+		}
+		if len(from.ManagedProfiles) > 0 {
+			// Override by non-empty values only
+			defaults.ManagedProfiles = append([]string{}, from.ManagedProfiles...)
+		}
+		if from.QuotaUsageThreshold != 0 {
+			// Override by non-empty values only
+			defaults.QuotaUsageThreshold = from.QuotaUsageThreshold
+		}
+		if from.PriorityClassName != "" {
+			// Override by non-empty values only
+			defaults.PriorityClassName = from.PriorityClassName
+		}
+		if from.HostNetwork.HasValue() {
+			// Override by non-empty values only
+			defaults.HostNetwork = from.HostNetwork.MergeFrom(defaults.HostNetwork)
 		}
+		defaults.Labels = util.MergeStringMapsOverwrite(defaults.Labels, from.Labels)
+		defaults.Annotations = util.MergeStringMapsOverwrite(defaults.Annotations, from.Annotations)
 	}
 
 	defaults.DistributedDDL = defaults.DistributedDDL.MergeFrom(from.DistributedDDL, _type)
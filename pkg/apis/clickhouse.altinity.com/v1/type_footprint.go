@@ -0,0 +1,61 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import (
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// ChiFootprint describes total resources requested by all hosts of the CHI
+type ChiFootprint struct {
+	CPU     string `json:"cpu,omitempty"     yaml:"cpu,omitempty"`
+	Memory  string `json:"memory,omitempty"  yaml:"memory,omitempty"`
+	Storage string `json:"storage,omitempty" yaml:"storage,omitempty"`
+}
+
+// NewChiFootprint creates new footprint out of resource quantities
+func NewChiFootprint(cpu, memory, storage resource.Quantity) *ChiFootprint {
+	return &ChiFootprint{
+		CPU:     cpu.String(),
+		Memory:  memory.String(),
+		Storage: storage.String(),
+	}
+}
+
+// GetCPU gets requested CPU quantity
+func (f *ChiFootprint) GetCPU() resource.Quantity {
+	return f.get(func(f *ChiFootprint) string { return f.CPU })
+}
+
+// GetMemory gets requested memory quantity
+func (f *ChiFootprint) GetMemory() resource.Quantity {
+	return f.get(func(f *ChiFootprint) string { return f.Memory })
+}
+
+// GetStorage gets requested storage quantity
+func (f *ChiFootprint) GetStorage() resource.Quantity {
+	return f.get(func(f *ChiFootprint) string { return f.Storage })
+}
+
+func (f *ChiFootprint) get(field func(f *ChiFootprint) string) resource.Quantity {
+	if f == nil {
+		return resource.Quantity{}
+	}
+	quantity, err := resource.ParseQuantity(field(f))
+	if err != nil {
+		return resource.Quantity{}
+	}
+	return quantity
+}
@@ -0,0 +1,83 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+// ChiFork defines fork section of .spec
+// Volumes of hosts of the CHI being created are cloned from snapshots of volumes of the same hosts of the source CHI
+type ChiFork struct {
+	// Source specifies name of the CHI in the same namespace the CHI is forked from
+	Source string `json:"source,omitempty"                  yaml:"source,omitempty"`
+	// VolumeSnapshotClassName specifies class of volume snapshots, default class is used when empty
+	VolumeSnapshotClassName string `json:"volumeSnapshotClassName,omitempty" yaml:"volumeSnapshotClassName,omitempty"`
+}
+
+// NewChiFork creates new ChiFork object
+func NewChiFork() *ChiFork {
+	return new(ChiFork)
+}
+
+// HasSource checks whether source CHI is specified
+func (f *ChiFork) HasSource() bool {
+	if f == nil {
+		return false
+	}
+	return f.Source != ""
+}
+
+// GetSource gets name of the source CHI
+func (f *ChiFork) GetSource() string {
+	if f == nil {
+		return ""
+	}
+	return f.Source
+}
+
+// GetVolumeSnapshotClassName gets class of volume snapshots
+func (f *ChiFork) GetVolumeSnapshotClassName() string {
+	if f == nil {
+		return ""
+	}
+	return f.VolumeSnapshotClassName
+}
+
+// MergeFrom merges from specified object
+func (f *ChiFork) MergeFrom(from *ChiFork, _type MergeType) *ChiFork {
+	if from == nil {
+		return f
+	}
+
+	if f == nil {
+		f = NewChiFork()
+	}
+
+	switch _type {
+	case MergeTypeFillEmptyValues:
+		if f.Source == "" {
+			f.Source = from.Source
+		}
+		if f.VolumeSnapshotClassName == "" {
+			f.VolumeSnapshotClassName = from.VolumeSnapshotClassName
+		}
+	case MergeTypeOverrideByNonEmptyValues:
+		if from.Source != "" {
+			f.Source = from.Source
+		}
+		if from.VolumeSnapshotClassName != "" {
+			f.VolumeSnapshotClassName = from.VolumeSnapshotClassName
+		}
+	}
+
+	return f
+}
@@ -0,0 +1,67 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import (
+	"time"
+)
+
+const (
+	maxHistory = 10
+)
+
+// HistoryOutcomeFailed specifies outcome of reconcile which failed without being aborted
+const HistoryOutcomeFailed = "Failed"
+
+// ChiHistoryEntry describes one reconcile of a spec generation
+type ChiHistoryEntry struct {
+	Generation int64  `json:"generation,omitempty" yaml:"generation,omitempty"`
+	TaskID     string `json:"taskID,omitempty"     yaml:"taskID,omitempty"`
+	Started    string `json:"started,omitempty"    yaml:"started,omitempty"`
+	Finished   string `json:"finished,omitempty"   yaml:"finished,omitempty"`
+	Changes    string `json:"changes,omitempty"    yaml:"changes,omitempty"`
+	Outcome    string `json:"outcome,omitempty"    yaml:"outcome,omitempty"`
+}
+
+// NewChiHistoryEntry creates new history entry for reconcile started right now
+func NewChiHistoryEntry(generation int64, taskID, changes string) ChiHistoryEntry {
+	return ChiHistoryEntry{
+		Generation: generation,
+		TaskID:     taskID,
+		Started:    time.Now().UTC().Format(time.RFC3339),
+		Changes:    changes,
+		Outcome:    StatusInProgress,
+	}
+}
+
+// pushHistoryNoSync pushes history entry as the latest one, keeping at most maxHistory entries
+func pushHistoryNoSync(s *ChiStatus, entry ChiHistoryEntry) {
+	s.History = append([]ChiHistoryEntry{entry}, s.History...)
+	if len(s.History) > maxHistory {
+		s.History = s.History[:maxHistory]
+	}
+}
+
+// finishHistoryNoSync sets outcome of the latest history entry, in case it is still in progress
+func finishHistoryNoSync(s *ChiStatus, outcome string) {
+	if len(s.History) == 0 {
+		return
+	}
+	if s.History[0].Outcome != StatusInProgress {
+		return
+	}
+	s.History[0].Finished = time.Now().UTC().Format(time.RFC3339)
+	s.History[0].Outcome = outcome
+}
@@ -19,6 +19,7 @@ import (
 	core "k8s.io/api/core/v1"
 
 	"github.com/altinity/clickhouse-operator/pkg/apis/swversion"
+	"github.com/altinity/clickhouse-operator/pkg/util"
 )
 
 // ChiHost defines host (a data replica within a shard) of .spec.configuration.clusters[n].shards[m]
@@ -36,11 +37,31 @@ type ChiHost struct {
 	InterserverHTTPPort int32             `json:"interserverHTTPPort,omitempty" yaml:"interserverHTTPPort,omitempty"`
 	Settings            *Settings         `json:"settings,omitempty"            yaml:"settings,omitempty"`
 	Files               *Settings         `json:"files,omitempty"               yaml:"files,omitempty"`
+	Macros              *Settings         `json:"macros,omitempty"              yaml:"macros,omitempty"`
 	Templates           *ChiTemplateNames `json:"templates,omitempty"           yaml:"templates,omitempty"`
+	// Zone specifies zone the host is scheduled to, assigned from shard zones in case not specified explicitly
+	Zone *ChiPodTemplateZone `json:"zone,omitempty" yaml:"zone,omitempty"`
+	// Tier specifies tier of the host, inherited from the replica in case not specified explicitly
+	Tier string `json:"tier,omitempty" yaml:"tier,omitempty"`
+	// Priority specifies priority of the host in remote_servers, inherited from the replica in case not specified explicitly
+	Priority *int `json:"priority,omitempty" yaml:"priority,omitempty"`
+	// Resources specifies resources of ClickHouse container of the host, inherited from the shard or the replica
+	Resources *core.ResourceRequirements `json:"resources,omitempty" yaml:"resources,omitempty"`
+	// Annotations specifies annotations of the pod of the host, inherited from the replica
+	Annotations map[string]string `json:"annotations,omitempty" yaml:"annotations,omitempty"`
 
 	Runtime ChiHostRuntime `json:"-" yaml:"-"`
 }
 
+// Tiers of hosts
+const (
+	// HostTierWrite is the default tier, hosts of which take distributed writes
+	HostTierWrite = "write"
+	// HostTierRead specifies read tier, hosts of which are excluded from distributed writes,
+	// served by dedicated read Service and updated last during rollouts
+	HostTierRead = "read"
+)
+
 type ChiHostRuntime struct {
 	// Internal data
 	Address             ChiHostAddress              `json:"-" yaml:"-"`
@@ -52,6 +73,14 @@ type ChiHostRuntime struct {
 	// DesiredStatefulSet is a desired stateful set - reconcile target
 	DesiredStatefulSet *apps.StatefulSet       `json:"-" yaml:"-" testdiff:"ignore"`
 	CHI                *ClickHouseInstallation `json:"-" yaml:"-" testdiff:"ignore"`
+	// Writer specifies whether the host takes writes addressed to its shard
+	Writer bool `json:"-" yaml:"-"`
+	// Spot specifies whether the host runs on spot nodes
+	Spot bool `json:"-" yaml:"-"`
+	// PortsAllocated specifies whether ports of the host are allocated by the Allocated port distribution
+	PortsAllocated bool `json:"-" yaml:"-"`
+	// NodeAddress specifies address of the node the host's pod runs on, known for hosts in host network only
+	NodeAddress string `json:"-" yaml:"-"`
 }
 
 // GetReconcileAttributes is an ensurer getter
@@ -76,6 +105,22 @@ func (host *ChiHost) InheritSettingsFrom(shard *ChiShard, replica *ChiReplica) {
 	}
 }
 
+// InheritMacrosFrom inherits macros from both specified shard and replica and then from the cluster.
+// Shard's macros take precedence over replica's ones
+func (host *ChiHost) InheritMacrosFrom(shard *ChiShard, replica *ChiReplica, cluster *Cluster) {
+	if shard != nil {
+		host.Macros = host.Macros.MergeFrom(shard.Macros)
+	}
+
+	if replica != nil {
+		host.Macros = host.Macros.MergeFrom(replica.Macros)
+	}
+
+	if cluster != nil {
+		host.Macros = host.Macros.MergeFrom(cluster.Macros)
+	}
+}
+
 // InheritFilesFrom inherits files from specified shard and replica
 func (host *ChiHost) InheritFilesFrom(shard *ChiShard, replica *ChiReplica) {
 	if shard != nil {
@@ -104,6 +149,38 @@ func (host *ChiHost) InheritTemplatesFrom(shard *ChiShard, replica *ChiReplica,
 	host.Templates.HandleDeprecatedFields()
 }
 
+// InheritResourcesFrom inherits resources from specified shard and replica
+func (host *ChiHost) InheritResourcesFrom(shard *ChiShard, replica *ChiReplica) {
+	if shard != nil {
+		host.Resources = MergeResourceRequirements(host.Resources, shard.Resources.DeepCopy())
+	}
+
+	if replica != nil {
+		host.Resources = MergeResourceRequirements(host.Resources, replica.Resources.DeepCopy())
+	}
+}
+
+// InheritPortsFrom inherits ports not specified explicitly from the replica, the shard and the cluster, in this order
+func (host *ChiHost) InheritPortsFrom(shard *ChiShard, replica *ChiReplica, cluster *Cluster) {
+	var ports []*HostPorts
+	if replica != nil {
+		ports = append(ports, &replica.HostPorts)
+	}
+	if shard != nil {
+		ports = append(ports, &shard.HostPorts)
+	}
+	if cluster != nil {
+		ports = append(ports, &cluster.HostPorts)
+	}
+	for _, from := range ports {
+		host.TCPPort = EnsurePortValue(host.TCPPort, from.TCPPort, PortUnassigned())
+		host.TLSPort = EnsurePortValue(host.TLSPort, from.TLSPort, PortUnassigned())
+		host.HTTPPort = EnsurePortValue(host.HTTPPort, from.HTTPPort, PortUnassigned())
+		host.HTTPSPort = EnsurePortValue(host.HTTPSPort, from.HTTPSPort, PortUnassigned())
+		host.InterserverHTTPPort = EnsurePortValue(host.InterserverHTTPPort, from.InterserverHTTPPort, PortUnassigned())
+	}
+}
+
 func isUnassigned(port int32) bool {
 	return port == PortMayBeAssignedLaterOrLeftUnused
 }
@@ -133,6 +210,98 @@ func (host *ChiHost) MergeFrom(from *ChiHost) {
 	}
 	host.Templates = host.Templates.MergeFrom(from.Templates, MergeTypeFillEmptyValues)
 	host.Templates.HandleDeprecatedFields()
+	if host.Zone == nil {
+		host.Zone = from.Zone.DeepCopy()
+	}
+	if host.Tier == "" {
+		host.Tier = from.Tier
+	}
+	if (host.Priority == nil) && (from.Priority != nil) {
+		priority := *from.Priority
+		host.Priority = &priority
+	}
+	host.Resources = MergeResourceRequirements(host.Resources, from.Resources.DeepCopy())
+	host.Annotations = util.MergeStringMapsPreserve(host.Annotations, from.Annotations)
+}
+
+// InheritTierFrom inherits tier from specified replica
+func (host *ChiHost) InheritTierFrom(replica *ChiReplica) {
+	if (host.Tier == "") && (replica != nil) {
+		host.Tier = replica.Tier
+	}
+}
+
+// InheritPriorityFrom inherits priority from specified replica
+func (host *ChiHost) InheritPriorityFrom(replica *ChiReplica) {
+	if (host.Priority == nil) && (replica != nil) && (replica.Priority != nil) {
+		priority := *replica.Priority
+		host.Priority = &priority
+	}
+}
+
+// InheritAnnotationsFrom inherits annotations from specified replica, annotations of the host take precedence
+func (host *ChiHost) InheritAnnotationsFrom(replica *ChiReplica) {
+	if replica != nil {
+		host.Annotations = util.MergeStringMapsPreserve(host.Annotations, replica.Annotations)
+	}
+}
+
+// HasPriority checks whether host has applicable priority value specified
+func (host *ChiHost) HasPriority() bool {
+	if host == nil {
+		return false
+	}
+	if host.Priority == nil {
+		return false
+	}
+	return *host.Priority >= 0
+}
+
+// GetPriority gets priority
+func (host *ChiHost) GetPriority() int {
+	if host.HasPriority() {
+		return *host.Priority
+	}
+	return 0
+}
+
+// IsWriter checks whether host takes writes addressed to its shard
+func (host *ChiHost) IsWriter() bool {
+	if host == nil {
+		return false
+	}
+	return host.Runtime.Writer
+}
+
+// IsSpot checks whether host runs on spot nodes
+func (host *ChiHost) IsSpot() bool {
+	if host == nil {
+		return false
+	}
+	return host.Runtime.Spot
+}
+
+// IsReadTier checks whether host belongs to the read tier
+func (host *ChiHost) IsReadTier() bool {
+	if host == nil {
+		return false
+	}
+	return host.Tier == HostTierRead
+}
+
+// IsHostNetwork checks whether host runs in the host network namespace of its node,
+// either requested by the cluster or by the pod template
+func (host *ChiHost) IsHostNetwork() bool {
+	if host == nil {
+		return false
+	}
+	if host.GetCluster().IsHostNetwork() {
+		return true
+	}
+	if podTemplate, ok := host.GetPodTemplate(); ok {
+		return podTemplate.Spec.HostNetwork
+	}
+	return false
 }
 
 // GetHostTemplate gets host template
@@ -254,6 +423,11 @@ func (host *ChiHost) IsStopped() bool {
 	return host.GetCHI().IsStopped()
 }
 
+// IsFrozen checks whether shard of the host is frozen for maintenance
+func (host *ChiHost) IsFrozen() bool {
+	return host.GetShard().IsFrozen()
+}
+
 // IsNewOne checks whether host is a new one
 // TODO unify with model HostIsNewOne
 func (host *ChiHost) IsNewOne() bool {
@@ -0,0 +1,105 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import (
+	"strings"
+	"time"
+)
+
+// Possible host health states
+const (
+	// HostHealthHealthy specifies host passing the latest check
+	HostHealthHealthy = "Healthy"
+	// HostHealthSuspect specifies host failing the latest checks, but not enough of them to be considered down
+	HostHealthSuspect = "Suspect"
+	// HostHealthFlapping specifies host switching between passing and failing checks
+	HostHealthFlapping = "Flapping"
+	// HostHealthDown specifies host failing all the latest checks
+	HostHealthDown = "Down"
+)
+
+// Results of health-checks kept in history
+const (
+	hostHealthCheckPassed = "+"
+	hostHealthCheckFailed = "-"
+)
+
+// ChiHostHealth describes recent health-checks of a host
+type ChiHostHealth struct {
+	Cluster string `json:"cluster"             yaml:"cluster"`
+	Host    string `json:"host"                yaml:"host"`
+	State   string `json:"state"               yaml:"state"`
+	// Checks specifies results of recent checks, the latest last, '+' stands for passed and '-' for failed check
+	Checks string `json:"checks,omitempty"    yaml:"checks,omitempty"`
+	// LastSeen specifies time of the latest passed check
+	LastSeen string `json:"lastSeen,omitempty"  yaml:"lastSeen,omitempty"`
+	// LastCheck specifies time of the latest check
+	LastCheck string `json:"lastCheck,omitempty" yaml:"lastCheck,omitempty"`
+	Error     string `json:"error,omitempty"     yaml:"error,omitempty"`
+}
+
+// Record records result of a health-check and re-evaluates state of the host.
+// At most window results are kept
+func (h *ChiHostHealth) Record(err error, now time.Time, window, flapThreshold, downThreshold int) {
+	now = now.UTC()
+	h.LastCheck = now.Format(time.RFC3339)
+	if err == nil {
+		h.Checks += hostHealthCheckPassed
+		h.LastSeen = h.LastCheck
+		h.Error = ""
+	} else {
+		h.Checks += hostHealthCheckFailed
+		h.Error = err.Error()
+	}
+	if (window > 0) && (len(h.Checks) > window) {
+		h.Checks = h.Checks[len(h.Checks)-window:]
+	}
+	h.State = h.evaluateState(flapThreshold, downThreshold)
+}
+
+// evaluateState evaluates state of the host out of recent checks.
+// Flapping takes precedence, so a host failing checks intermittently is not taken for a down one
+func (h *ChiHostHealth) evaluateState(flapThreshold, downThreshold int) string {
+	transitions := 0
+	for i := 1; i < len(h.Checks); i++ {
+		if h.Checks[i] != h.Checks[i-1] {
+			transitions++
+		}
+	}
+	failures := len(h.Checks) - len(strings.TrimRight(h.Checks, hostHealthCheckFailed))
+
+	switch {
+	case (flapThreshold > 0) && (transitions >= flapThreshold):
+		return HostHealthFlapping
+	case (failures > 0) && (failures >= downThreshold):
+		return HostHealthDown
+	case failures > 0:
+		return HostHealthSuspect
+	default:
+		return HostHealthHealthy
+	}
+}
+
+// IsFlapping checks whether host is switching between passing and failing checks
+func (h ChiHostHealth) IsFlapping() bool {
+	return h.State == HostHealthFlapping
+}
+
+// IsDown checks whether host is failing all the latest checks.
+// Only down hosts are to be considered failed, while suspect and flapping ones may recover on their own
+func (h ChiHostHealth) IsDown() bool {
+	return h.State == HostHealthDown
+}
@@ -0,0 +1,71 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestHostHealthRecord(t *testing.T) {
+	tests := []struct {
+		name   string
+		checks string
+		state  string
+	}{
+		{name: "healthy", checks: "++++", state: HostHealthHealthy},
+		{name: "recovered", checks: "++-+", state: HostHealthHealthy},
+		{name: "suspect", checks: "+++--", state: HostHealthSuspect},
+		{name: "down", checks: "++---", state: HostHealthDown},
+		{name: "flapping", checks: "+-+-", state: HostHealthFlapping},
+		{name: "flapping down", checks: "+-+----", state: HostHealthFlapping},
+		{name: "down after flapping rolled out", checks: "+-+-------------", state: HostHealthDown},
+	}
+	now := time.Date(2024, time.June, 1, 12, 0, 0, 0, time.UTC)
+	for _, test := range tests {
+		health := ChiHostHealth{}
+		for _, check := range test.checks {
+			var err error
+			if check == '-' {
+				err = errors.New("connection refused")
+			}
+			health.Record(err, now, 10, 3, 3)
+		}
+		if health.State != test.state {
+			t.Errorf("%s: checks %s: got %s want %s", test.name, health.Checks, health.State, test.state)
+		}
+		if len(health.Checks) > 10 {
+			t.Errorf("%s: got %d checks want at most 10", test.name, len(health.Checks))
+		}
+	}
+}
+
+func TestHostHealthLastSeen(t *testing.T) {
+	seen := time.Date(2024, time.June, 1, 12, 0, 0, 0, time.UTC)
+	health := ChiHostHealth{}
+	health.Record(nil, seen, 10, 3, 3)
+	health.Record(errors.New("timeout"), seen.Add(time.Minute), 10, 3, 3)
+
+	if health.LastSeen != seen.Format(time.RFC3339) {
+		t.Errorf("got last seen %s want %s", health.LastSeen, seen.Format(time.RFC3339))
+	}
+	if health.LastCheck != seen.Add(time.Minute).Format(time.RFC3339) {
+		t.Errorf("got last check %s want %s", health.LastCheck, seen.Add(time.Minute).Format(time.RFC3339))
+	}
+	if health.Error != "timeout" {
+		t.Errorf("got error %q want timeout", health.Error)
+	}
+}
@@ -72,6 +72,20 @@ func (s *CycleAddress) Inc(spec *CycleSpec) {
 	}
 }
 
+// Set sets the CycleAddress to the position reached after specified number of increases
+func (s *CycleAddress) Set(index int, spec *CycleSpec) {
+	if s == nil {
+		return
+	}
+	if spec.IsValid() {
+		s.CycleIndex = index / spec.Size
+		s.Index = index % spec.Size
+	} else {
+		s.CycleIndex = 0
+		s.Index = index
+	}
+}
+
 // ScopeAddress defines scope address of an entity
 type ScopeAddress struct {
 	// CycleSpec specifies cycle which to be used to specify CycleAddress
@@ -108,6 +122,15 @@ func (s *ScopeAddress) Inc() {
 	s.Index++
 }
 
+// Set sets the ScopeAddress to specified index within the scope
+func (s *ScopeAddress) Set(index int) {
+	if s == nil {
+		return
+	}
+	s.CycleAddress.Set(index, s.CycleSpec)
+	s.Index = index
+}
+
 // HostAddress specifies address of a host
 type HostAddress struct {
 	// CHIScopeAddress specifies address of a host within CHI scope
@@ -0,0 +1,120 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import (
+	core "k8s.io/api/core/v1"
+)
+
+// Possible log shipping agents
+const (
+	LoggingAgentVector    = "vector"
+	LoggingAgentFluentBit = "fluent-bit"
+)
+
+// ChiLogging defines logging section of .spec
+// Log shipping agent is injected as a sidecar into each pod of the CHI and ships ClickHouse logs
+type ChiLogging struct {
+	Enabled *StringBool `json:"enabled,omitempty"    yaml:"enabled,omitempty"`
+	// Agent specifies log shipping agent, either vector or fluent-bit
+	Agent string `json:"agent,omitempty"      yaml:"agent,omitempty"`
+	Image string `json:"image,omitempty"      yaml:"image,omitempty"`
+	// Structured specifies whether ClickHouse writes logs in JSON format, so log entries are shipped with all their fields
+	Structured *StringBool `json:"structured,omitempty" yaml:"structured,omitempty"`
+	// Output specifies agent-specific configuration of destinations logs are shipped to
+	Output    string                     `json:"output,omitempty"     yaml:"output,omitempty"`
+	Resources *core.ResourceRequirements `json:"resources,omitempty"  yaml:"resources,omitempty"`
+}
+
+// NewChiLogging creates new ChiLogging object
+func NewChiLogging() *ChiLogging {
+	return new(ChiLogging)
+}
+
+// IsEnabled checks whether log shipping agent is to be injected. Specified section enables logging unless disabled explicitly
+func (l *ChiLogging) IsEnabled() bool {
+	if l == nil {
+		return false
+	}
+	return !l.Enabled.HasValue() || l.Enabled.IsTrue()
+}
+
+// IsStructured checks whether ClickHouse is to write logs in JSON format
+func (l *ChiLogging) IsStructured() bool {
+	if !l.IsEnabled() {
+		return false
+	}
+	return l.Structured.Value()
+}
+
+// MergeFrom merges from specified object
+func (l *ChiLogging) MergeFrom(from *ChiLogging, _type MergeType) *ChiLogging {
+	if from == nil {
+		return l
+	}
+
+	if l == nil {
+		l = NewChiLogging()
+	}
+
+	switch _type {
+	case MergeTypeFillEmptyValues:
+		if !l.Enabled.HasValue() {
+			l.Enabled = l.Enabled.MergeFrom(from.Enabled)
+		}
+		if l.Agent == "" {
+			l.Agent = from.Agent
+		}
+		if l.Image == "" {
+			l.Image = from.Image
+		}
+		if !l.Structured.HasValue() {
+			l.Structured = l.Structured.MergeFrom(from.Structured)
+		}
+		if l.Output == "" {
+			l.Output = from.Output
+		}
+		if l.Resources == nil {
+			l.Resources = from.Resources.DeepCopy()
+		}
+	case MergeTypeOverrideByNonEmptyValues:
+		if from.Enabled.HasValue() {
+			// Override by non-empty values only
+			l.Enabled = from.Enabled
+		}
+		if from.Agent != "" {
+			// Override by non-empty values only
+			l.Agent = from.Agent
+		}
+		if from.Image != "" {
+			// Override by non-empty values only
+			l.Image = from.Image
+		}
+		if from.Structured.HasValue() {
+			// Override by non-empty values only
+			l.Structured = from.Structured
+		}
+		if from.Output != "" {
+			// Override by non-empty values only
+			l.Output = from.Output
+		}
+		if from.Resources != nil {
+			// Override by non-empty values only
+			l.Resources = from.Resources.DeepCopy()
+		}
+	}
+
+	return l
+}
@@ -0,0 +1,72 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import "github.com/altinity/clickhouse-operator/pkg/util"
+
+// Layouts of logical cluster
+const (
+	// LogicalClusterLayoutOneShard groups all selected hosts as replicas of one shard
+	LogicalClusterLayoutOneShard = "oneShard"
+	// LogicalClusterLayoutShardPerHost places each selected host into its own shard
+	LogicalClusterLayoutShardPerHost = "shardPerHost"
+	// LogicalClusterLayoutShards keeps shards of the source clusters
+	LogicalClusterLayoutShards = "shards"
+)
+
+// ChiLogicalCluster defines additional remote_servers cluster entry built over hosts of the CHI clusters
+type ChiLogicalCluster struct {
+	Name                string      `json:"name,omitempty"                yaml:"name,omitempty"`
+	Layout              string      `json:"layout,omitempty"              yaml:"layout,omitempty"`
+	Clusters            []string    `json:"clusters,omitempty"            yaml:"clusters,omitempty"`
+	Shards              []string    `json:"shards,omitempty"              yaml:"shards,omitempty"`
+	Hosts               []string    `json:"hosts,omitempty"               yaml:"hosts,omitempty"`
+	InternalReplication *StringBool `json:"internalReplication,omitempty" yaml:"internalReplication,omitempty"`
+}
+
+// IncludesCluster checks whether cluster is a source of the logical cluster. Empty list means all clusters
+func (c *ChiLogicalCluster) IncludesCluster(name string) bool {
+	return (len(c.Clusters) == 0) || util.InArray(name, c.Clusters)
+}
+
+// IncludesShard checks whether shard is selected into the logical cluster. Empty list means all shards
+func (c *ChiLogicalCluster) IncludesShard(name string) bool {
+	return (len(c.Shards) == 0) || util.InArray(name, c.Shards)
+}
+
+// IncludesHost checks whether host is selected into the logical cluster
+func (c *ChiLogicalCluster) IncludesHost(host *ChiHost) bool {
+	if host == nil {
+		return false
+	}
+	address := host.Runtime.Address
+	if !c.IncludesCluster(address.ClusterName) || !c.IncludesShard(address.ShardName) {
+		return false
+	}
+	return (len(c.Hosts) == 0) || util.InArray(address.HostName, c.Hosts)
+}
+
+// FindLogicalCluster finds logical cluster by name
+func (configuration *Configuration) FindLogicalCluster(name string) *ChiLogicalCluster {
+	if configuration == nil {
+		return nil
+	}
+	for i := range configuration.LogicalClusters {
+		if configuration.LogicalClusters[i].Name == name {
+			return &configuration.LogicalClusters[i]
+		}
+	}
+	return nil
+}
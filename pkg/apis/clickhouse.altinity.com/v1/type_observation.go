@@ -0,0 +1,80 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import (
+	"time"
+)
+
+// ChiObservation describes difference between desired and actual state of the CHI,
+// as seen by the operator running in observe mode
+type ChiObservation struct {
+	Observed        string   `json:"observed,omitempty"        yaml:"observed,omitempty"`
+	OperatorVersion string   `json:"operatorVersion,omitempty" yaml:"operatorVersion,omitempty"`
+	Generation      int64    `json:"generation,omitempty"      yaml:"generation,omitempty"`
+	Actions         string   `json:"actions,omitempty"         yaml:"actions,omitempty"`
+	Missing         []string `json:"missing,omitempty"         yaml:"missing,omitempty"`
+	Drifted         []string `json:"drifted,omitempty"         yaml:"drifted,omitempty"`
+}
+
+// NewChiObservation creates new observation made right now
+func NewChiObservation(operatorVersion string, generation int64, actions string) *ChiObservation {
+	return &ChiObservation{
+		Observed:        time.Now().UTC().Format(time.RFC3339),
+		OperatorVersion: operatorVersion,
+		Generation:      generation,
+		Actions:         actions,
+	}
+}
+
+// AddMissing adds object which is expected to exist, but does not exist
+func (o *ChiObservation) AddMissing(object string) {
+	if o == nil {
+		return
+	}
+	o.Missing = append(o.Missing, object)
+}
+
+// AddDrifted adds object which exists, but differs from the desired one
+func (o *ChiObservation) AddDrifted(object string) {
+	if o == nil {
+		return
+	}
+	o.Drifted = append(o.Drifted, object)
+}
+
+// GetMissing gets objects which are expected to exist, but do not exist
+func (o *ChiObservation) GetMissing() []string {
+	if o == nil {
+		return nil
+	}
+	return o.Missing
+}
+
+// GetDrifted gets objects which differ from the desired ones
+func (o *ChiObservation) GetDrifted() []string {
+	if o == nil {
+		return nil
+	}
+	return o.Drifted
+}
+
+// HasActions checks whether there are actions planned
+func (o *ChiObservation) HasActions() bool {
+	if o == nil {
+		return false
+	}
+	return o.Actions != ""
+}
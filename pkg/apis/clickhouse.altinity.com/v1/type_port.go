@@ -62,3 +62,36 @@ func EnsurePortValue(port int32, value, _default int32) int32 {
 	// Fallback to default value
 	return _default
 }
+
+// HostPorts specifies ports of hosts at cluster, shard or replica level
+type HostPorts struct {
+	TCPPort             int32 `json:"tcpPort,omitempty"             yaml:"tcpPort,omitempty"`
+	TLSPort             int32 `json:"tlsPort,omitempty"             yaml:"tlsPort,omitempty"`
+	HTTPPort            int32 `json:"httpPort,omitempty"            yaml:"httpPort,omitempty"`
+	HTTPSPort           int32 `json:"httpsPort,omitempty"           yaml:"httpsPort,omitempty"`
+	InterserverHTTPPort int32 `json:"interserverHTTPPort,omitempty" yaml:"interserverHTTPPort,omitempty"`
+}
+
+// Normalize drops invalid ports
+func (p *HostPorts) Normalize() {
+	if p == nil {
+		return
+	}
+	for _, port := range []*int32{&p.TCPPort, &p.TLSPort, &p.HTTPPort, &p.HTTPSPort, &p.InterserverHTTPPort} {
+		if IsPortInvalid(*port) {
+			*port = PortUnassigned()
+		}
+	}
+}
+
+// MergeFrom fills unassigned ports from specified ports
+func (p *HostPorts) MergeFrom(from *HostPorts) {
+	if (p == nil) || (from == nil) {
+		return
+	}
+	p.TCPPort = EnsurePortValue(p.TCPPort, from.TCPPort, PortUnassigned())
+	p.TLSPort = EnsurePortValue(p.TLSPort, from.TLSPort, PortUnassigned())
+	p.HTTPPort = EnsurePortValue(p.HTTPPort, from.HTTPPort, PortUnassigned())
+	p.HTTPSPort = EnsurePortValue(p.HTTPSPort, from.HTTPSPort, PortUnassigned())
+	p.InterserverHTTPPort = EnsurePortValue(p.InterserverHTTPPort, from.InterserverHTTPPort, PortUnassigned())
+}
@@ -0,0 +1,37 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+// ChiPortAllocation describes ports allocated to a host by the Allocated port distribution
+type ChiPortAllocation struct {
+	// Host is the name of the StatefulSet of the host
+	Host string `json:"host,omitempty" yaml:"host,omitempty"`
+	// Slot is the offset of the host's ports from the base ports
+	Slot  int     `json:"slot"            yaml:"slot"`
+	Ports []int32 `json:"ports,omitempty" yaml:"ports,omitempty"`
+}
+
+// HasPort checks whether the allocation includes specified port
+func (a *ChiPortAllocation) HasPort(port int32) bool {
+	if a == nil {
+		return false
+	}
+	for _, p := range a.Ports {
+		if p == port {
+			return true
+		}
+	}
+	return false
+}
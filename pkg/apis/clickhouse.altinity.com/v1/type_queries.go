@@ -0,0 +1,109 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import "time"
+
+// ChiQueries specifies how the operator runs SQL queries on hosts of the CHI.
+// Zero values mean operator-wide defaults.
+type ChiQueries struct {
+	// ConnectTimeout specifies timeout of establishing connection to a host, in seconds
+	ConnectTimeout int `json:"connectTimeout,omitempty" yaml:"connectTimeout,omitempty"`
+	// QueryTimeout specifies timeout of a query, in seconds
+	QueryTimeout int `json:"queryTimeout,omitempty" yaml:"queryTimeout,omitempty"`
+	// Retries specifies how many times failed retriable queries are retried
+	Retries int `json:"retries,omitempty" yaml:"retries,omitempty"`
+	// Backoff specifies base delay between retries, in seconds. Delay grows linearly with each retry
+	Backoff int `json:"backoff,omitempty" yaml:"backoff,omitempty"`
+}
+
+// NewChiQueries creates new queries
+func NewChiQueries() *ChiQueries {
+	return new(ChiQueries)
+}
+
+// GetConnectTimeout gets connect timeout
+func (t *ChiQueries) GetConnectTimeout() time.Duration {
+	if t == nil {
+		return 0
+	}
+	return time.Duration(t.ConnectTimeout) * time.Second
+}
+
+// GetQueryTimeout gets query timeout
+func (t *ChiQueries) GetQueryTimeout() time.Duration {
+	if t == nil {
+		return 0
+	}
+	return time.Duration(t.QueryTimeout) * time.Second
+}
+
+// GetRetries gets number of retries
+func (t *ChiQueries) GetRetries() int {
+	if t == nil {
+		return 0
+	}
+	return t.Retries
+}
+
+// GetBackoff gets base delay between retries
+func (t *ChiQueries) GetBackoff() time.Duration {
+	if t == nil {
+		return 0
+	}
+	return time.Duration(t.Backoff) * time.Second
+}
+
+// MergeFrom merges from specified queries
+func (t *ChiQueries) MergeFrom(from *ChiQueries, _type MergeType) *ChiQueries {
+	if from == nil {
+		return t
+	}
+
+	if t == nil {
+		t = NewChiQueries()
+	}
+
+	switch _type {
+	case MergeTypeFillEmptyValues:
+		if t.ConnectTimeout == 0 {
+			t.ConnectTimeout = from.ConnectTimeout
+		}
+		if t.QueryTimeout == 0 {
+			t.QueryTimeout = from.QueryTimeout
+		}
+		if t.Retries == 0 {
+			t.Retries = from.Retries
+		}
+		if t.Backoff == 0 {
+			t.Backoff = from.Backoff
+		}
+	case MergeTypeOverrideByNonEmptyValues:
+		if from.ConnectTimeout != 0 {
+			t.ConnectTimeout = from.ConnectTimeout
+		}
+		if from.QueryTimeout != 0 {
+			t.QueryTimeout = from.QueryTimeout
+		}
+		if from.Retries != 0 {
+			t.Retries = from.Retries
+		}
+		if from.Backoff != 0 {
+			t.Backoff = from.Backoff
+		}
+	}
+
+	return t
+}
@@ -0,0 +1,145 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import (
+	"time"
+
+	"github.com/altinity/clickhouse-operator/pkg/util"
+)
+
+// Possible rebalancing statuses
+const (
+	RebalancingStatusInProgress = "InProgress"
+	RebalancingStatusCompleted  = "Completed"
+	RebalancingStatusCancelled  = "Cancelled"
+	RebalancingStatusDraining   = "Draining"
+	RebalancingStatusDrained    = "Drained"
+)
+
+// Default rebalancing throttling
+const (
+	RebalancingPartitionsPerRunDefault = 10
+)
+
+// ChiClusterRebalancing defines moving of historical partitions to shards added to the cluster
+type ChiClusterRebalancing struct {
+	Enabled *StringBool `json:"enabled,omitempty"          yaml:"enabled,omitempty"`
+	// Tables limits rebalancing to tables matching any of "database.table" patterns. All replicated tables by default
+	Tables []string `json:"tables,omitempty"           yaml:"tables,omitempty"`
+	// PartitionsPerRun specifies how many partitions are planned to be moved ahead. One partition is moved per resync
+	PartitionsPerRun int `json:"partitionsPerRun,omitempty" yaml:"partitionsPerRun,omitempty"`
+	// PauseSeconds specifies pause between moves of partitions
+	PauseSeconds int `json:"pauseSeconds,omitempty"     yaml:"pauseSeconds,omitempty"`
+	// Drain specifies whether partitions of shards removed from the cluster are moved to the remaining shards
+	// before the removed shards are deleted
+	Drain *StringBool `json:"drain,omitempty"            yaml:"drain,omitempty"`
+}
+
+// IsEnabled checks whether rebalancing is enabled
+func (r *ChiClusterRebalancing) IsEnabled() bool {
+	if r == nil {
+		return false
+	}
+	return r.Enabled.Value()
+}
+
+// IsDrainEnabled checks whether removed shards are to be drained
+func (r *ChiClusterRebalancing) IsDrainEnabled() bool {
+	if r == nil {
+		return false
+	}
+	return r.Drain.Value()
+}
+
+// IncludesTable checks whether table is subject to rebalancing
+func (r *ChiClusterRebalancing) IncludesTable(database, table string) bool {
+	if r == nil {
+		return false
+	}
+	if len(r.Tables) == 0 {
+		return true
+	}
+	for _, pattern := range r.Tables {
+		matchable := Matchable(pattern)
+		if matchable.Match(database + "." + table) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetPause gets pause between moves of partitions
+func (r *ChiClusterRebalancing) GetPause() time.Duration {
+	if r == nil {
+		return 0
+	}
+	return time.Duration(r.PauseSeconds) * time.Second
+}
+
+// ChiRebalancingStatus describes progress of rebalancing of a cluster
+type ChiRebalancingStatus struct {
+	Cluster string `json:"cluster"                     yaml:"cluster"`
+	Status  string `json:"status"                      yaml:"status"`
+	// Shards specifies shards partitions are moved to, or shards partitions are moved from in case of drain
+	Shards            []string `json:"shards,omitempty"            yaml:"shards,omitempty"`
+	PartitionsMoved   int      `json:"partitionsMoved,omitempty"   yaml:"partitionsMoved,omitempty"`
+	BytesMoved        int64    `json:"bytesMoved,omitempty"        yaml:"bytesMoved,omitempty"`
+	PartitionsPending int      `json:"partitionsPending,omitempty" yaml:"partitionsPending,omitempty"`
+	Error             string   `json:"error,omitempty"             yaml:"error,omitempty"`
+	StartTime         string   `json:"startTime,omitempty"         yaml:"startTime,omitempty"`
+	UpdateTime        string   `json:"updateTime,omitempty"        yaml:"updateTime,omitempty"`
+}
+
+// NewChiRebalancingStatus creates new rebalancing status of the cluster
+func NewChiRebalancingStatus(cluster string, shards []string) ChiRebalancingStatus {
+	now := time.Now().UTC().Format(time.RFC3339)
+	return ChiRebalancingStatus{
+		Cluster:    cluster,
+		Status:     RebalancingStatusInProgress,
+		Shards:     shards,
+		StartTime:  now,
+		UpdateTime: now,
+	}
+}
+
+// NewChiDrainStatus creates new drain status of the cluster
+func NewChiDrainStatus(cluster string, shards []string) ChiRebalancingStatus {
+	status := NewChiRebalancingStatus(cluster, shards)
+	status.Status = RebalancingStatusDraining
+	return status
+}
+
+// IsInProgress checks whether rebalancing is in progress
+func (r ChiRebalancingStatus) IsInProgress() bool {
+	return r.Status == RebalancingStatusInProgress
+}
+
+// IsTargetShard checks whether partitions are moved to the shard
+func (r ChiRebalancingStatus) IsTargetShard(name string) bool {
+	return util.InArray(name, r.Shards)
+}
+
+// setRebalancingNoSync sets rebalancing status of a cluster, replacing the one of the same cluster
+func setRebalancingNoSync(rebalancing []ChiRebalancingStatus, entry ChiRebalancingStatus) []ChiRebalancingStatus {
+	entry.UpdateTime = time.Now().UTC().Format(time.RFC3339)
+	for i := range rebalancing {
+		if rebalancing[i].Cluster == entry.Cluster {
+			rebalancing[i] = entry
+			return rebalancing
+		}
+	}
+	return append(rebalancing, entry)
+}
@@ -0,0 +1,63 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+// ChiRemoteReplica defines replica of the cluster which lives outside of the CHI, e.g. in another region.
+// Remote replica is rendered in remote_servers only, no Kubernetes resources are created for it
+type ChiRemoteReplica struct {
+	Name   string `json:"name,omitempty"   yaml:"name,omitempty"`
+	Region string `json:"region,omitempty" yaml:"region,omitempty"`
+	// Hosts specifies external endpoints of the replica, one per shard in order of shards
+	Hosts []string `json:"hosts,omitempty" yaml:"hosts,omitempty"`
+	// CHI specifies another CHI which hosts the replica
+	CHI         *ChiRemoteReplicaCHI `json:"chi,omitempty"         yaml:"chi,omitempty"`
+	Port        int32                `json:"port,omitempty"        yaml:"port,omitempty"`
+	Secure      *StringBool          `json:"secure,omitempty"      yaml:"secure,omitempty"`
+	Compression *StringBool          `json:"compression,omitempty" yaml:"compression,omitempty"`
+}
+
+// ChiRemoteReplicaCHI references replica of a cluster of another CHI with the same shards
+type ChiRemoteReplicaCHI struct {
+	Name      string `json:"name,omitempty"      yaml:"name,omitempty"`
+	Namespace string `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+	Cluster   string `json:"cluster,omitempty"   yaml:"cluster,omitempty"`
+	Replica   string `json:"replica,omitempty"   yaml:"replica,omitempty"`
+	// NamespaceDomainPattern allows to address CHI in another Kubernetes cluster, e.g. "%s.svc.clusterset.local"
+	NamespaceDomainPattern string `json:"namespaceDomainPattern,omitempty" yaml:"namespaceDomainPattern,omitempty"`
+}
+
+// IsSecure checks whether remote replica is to be connected via secure port
+func (r *ChiRemoteReplica) IsSecure() bool {
+	if r == nil {
+		return false
+	}
+	return r.Secure.Value()
+}
+
+// GetHost gets endpoint of the remote replica specified for shard index, if any
+func (r *ChiRemoteReplica) GetHost(shardIndex int) (string, bool) {
+	if (r == nil) || (shardIndex < 0) || (shardIndex >= len(r.Hosts)) {
+		return "", false
+	}
+	return r.Hosts[shardIndex], true
+}
+
+// HasRemoteReplicas checks whether cluster has replicas outside of the CHI
+func (cluster *Cluster) HasRemoteReplicas() bool {
+	if cluster == nil {
+		return false
+	}
+	return len(cluster.RemoteReplicas) > 0
+}
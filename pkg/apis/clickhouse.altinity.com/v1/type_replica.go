@@ -30,6 +30,11 @@ func (replica *ChiReplica) InheritTemplatesFrom(cluster *Cluster) {
 	replica.Templates.HandleDeprecatedFields()
 }
 
+// InheritResourcesFrom inherits resources from specified cluster
+func (replica *ChiReplica) InheritResourcesFrom(cluster *Cluster) {
+	replica.Resources = MergeResourceRequirements(replica.Resources, cluster.Resources.DeepCopy())
+}
+
 // GetServiceTemplate gets service template
 func (replica *ChiReplica) GetServiceTemplate() (*ChiServiceTemplate, bool) {
 	if !replica.Templates.HasReplicaServiceTemplate() {
@@ -0,0 +1,32 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+// ChiReplicatedDatabase defines database of Replicated engine, which is created on all hosts of the cluster
+type ChiReplicatedDatabase struct {
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+	// ZookeeperPath specifies path of the database in ZooKeeper, macros are expanded by ClickHouse
+	ZookeeperPath string `json:"zookeeperPath,omitempty" yaml:"zookeeperPath,omitempty"`
+}
+
+// HasReplicatedDatabases checks whether any cluster of the CHI specifies replicated databases
+func (chi *ClickHouseInstallation) HasReplicatedDatabases() bool {
+	found := false
+	chi.WalkClusters(func(cluster *Cluster) error {
+		found = found || (len(cluster.ReplicatedDatabases) > 0)
+		return nil
+	})
+	return found
+}
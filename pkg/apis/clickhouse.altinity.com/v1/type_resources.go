@@ -0,0 +1,49 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import (
+	core "k8s.io/api/core/v1"
+)
+
+// MergeResourceRequirements fills requests and limits not specified in dst with the ones specified in src.
+// Resources are merged one by one, so cpu and memory may come from different levels of CHI spec
+func MergeResourceRequirements(dst, src *core.ResourceRequirements) *core.ResourceRequirements {
+	if src == nil {
+		return dst
+	}
+	if dst == nil {
+		dst = new(core.ResourceRequirements)
+	}
+
+	dst.Requests = mergeResourceList(dst.Requests, src.Requests)
+	dst.Limits = mergeResourceList(dst.Limits, src.Limits)
+
+	return dst
+}
+
+// mergeResourceList fills resources not specified in dst with the ones specified in src
+func mergeResourceList(dst, src core.ResourceList) core.ResourceList {
+	for name, quantity := range src {
+		if _, ok := dst[name]; ok {
+			continue
+		}
+		if dst == nil {
+			dst = core.ResourceList{}
+		}
+		dst[name] = quantity.DeepCopy()
+	}
+	return dst
+}
@@ -0,0 +1,131 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import (
+	"strings"
+	"time"
+)
+
+// Default interval of scheduled restarts, weekly
+const (
+	ScheduledRestartIntervalHoursDefault = 7 * 24
+)
+
+// ChiClusterScheduledRestart defines periodic restarts of hosts of the cluster, e.g. to mitigate memory fragmentation
+type ChiClusterScheduledRestart struct {
+	Enabled *StringBool `json:"enabled,omitempty"       yaml:"enabled,omitempty"`
+	// IntervalHours specifies how long a host runs before it is restarted
+	IntervalHours int `json:"intervalHours,omitempty" yaml:"intervalHours,omitempty"`
+	// Window restricts restarts to the maintenance window. Restarts may happen any time by default
+	Window *ChiMaintenanceWindow `json:"window,omitempty"        yaml:"window,omitempty"`
+}
+
+// IsEnabled checks whether scheduled restarts are enabled
+func (r *ChiClusterScheduledRestart) IsEnabled() bool {
+	if r == nil {
+		return false
+	}
+	return r.Enabled.Value()
+}
+
+// GetInterval gets how long a host runs before it is restarted
+func (r *ChiClusterScheduledRestart) GetInterval() time.Duration {
+	if r == nil {
+		return 0
+	}
+	return time.Duration(r.IntervalHours) * time.Hour
+}
+
+// IsInWindow checks whether specified time is within the maintenance window
+func (r *ChiClusterScheduledRestart) IsInWindow(t time.Time) bool {
+	if r == nil {
+		return false
+	}
+	if r.Window == nil {
+		return true
+	}
+	return r.Window.Contains(t)
+}
+
+// ChiMaintenanceWindow defines recurring time window maintenance is allowed in
+type ChiMaintenanceWindow struct {
+	// Days specifies days of week, e.g. "Sat" or "Saturday". Every day by default
+	Days []string `json:"days,omitempty"  yaml:"days,omitempty"`
+	// Start and End specify UTC time of day as "HH:MM". Window spans midnight in case End is not after Start
+	Start string `json:"start,omitempty" yaml:"start,omitempty"`
+	End   string `json:"end,omitempty"   yaml:"end,omitempty"`
+}
+
+// Contains checks whether specified time is within the window.
+// Window spanning midnight belongs to the day it starts on. Window with malformed time of day contains nothing.
+func (w *ChiMaintenanceWindow) Contains(t time.Time) bool {
+	if w == nil {
+		return false
+	}
+
+	t = t.UTC()
+	start, err := parseTimeOfDay(w.Start, 0)
+	if err != nil {
+		return false
+	}
+	end, err := parseTimeOfDay(w.End, 24*time.Hour)
+	if err != nil {
+		return false
+	}
+	now := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+	day := t.Weekday()
+
+	switch {
+	case start < end:
+		if (now < start) || (now >= end) {
+			return false
+		}
+	case now >= start:
+		// Spanning midnight, before midnight
+	case now < end:
+		// Spanning midnight, after midnight, the window started the day before
+		day = (day + 6) % 7
+	default:
+		return false
+	}
+
+	return w.hasDay(day)
+}
+
+// hasDay checks whether window is open on specified day of week
+func (w *ChiMaintenanceWindow) hasDay(day time.Weekday) bool {
+	if len(w.Days) == 0 {
+		return true
+	}
+	for _, d := range w.Days {
+		if (len(d) >= 3) && strings.EqualFold(d[:3], day.String()[:3]) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseTimeOfDay parses "HH:MM" time of day, empty value is replaced with the specified default
+func parseTimeOfDay(value string, _default time.Duration) (time.Duration, error) {
+	if value == "" {
+		return _default, nil
+	}
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
@@ -0,0 +1,48 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMaintenanceWindowContains(t *testing.T) {
+	// 2024-06-01 is Saturday
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2024, time.June, day, hour, minute, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		name     string
+		window   ChiMaintenanceWindow
+		t        time.Time
+		contains bool
+	}{
+		{name: "any time", window: ChiMaintenanceWindow{}, t: at(3, 12, 0), contains: true},
+		{name: "within", window: ChiMaintenanceWindow{Start: "02:00", End: "06:00"}, t: at(3, 2, 0), contains: true},
+		{name: "end excluded", window: ChiMaintenanceWindow{Start: "02:00", End: "06:00"}, t: at(3, 6, 0), contains: false},
+		{name: "day", window: ChiMaintenanceWindow{Days: []string{"saturday"}, Start: "02:00", End: "06:00"}, t: at(1, 3, 0), contains: true},
+		{name: "other day", window: ChiMaintenanceWindow{Days: []string{"Sat"}, Start: "02:00", End: "06:00"}, t: at(2, 3, 0), contains: false},
+		{name: "midnight before", window: ChiMaintenanceWindow{Days: []string{"Sat"}, Start: "22:00", End: "04:00"}, t: at(1, 23, 0), contains: true},
+		{name: "midnight after", window: ChiMaintenanceWindow{Days: []string{"Sat"}, Start: "22:00", End: "04:00"}, t: at(2, 3, 0), contains: true},
+		{name: "midnight after other day", window: ChiMaintenanceWindow{Days: []string{"Sat"}, Start: "22:00", End: "04:00"}, t: at(1, 3, 0), contains: false},
+		{name: "malformed", window: ChiMaintenanceWindow{Start: "2am"}, t: at(1, 3, 0), contains: false},
+	}
+	for _, test := range tests {
+		if contains := test.window.Contains(test.t); contains != test.contains {
+			t.Errorf("%s: got %v want %v", test.name, contains, test.contains)
+		}
+	}
+}
@@ -30,6 +30,86 @@ func (shard *ChiShard) InheritTemplatesFrom(cluster *Cluster) {
 	shard.Templates.HandleDeprecatedFields()
 }
 
+// InheritResourcesFrom inherits resources from specified cluster
+func (shard *ChiShard) InheritResourcesFrom(cluster *Cluster) {
+	shard.Resources = MergeResourceRequirements(shard.Resources, cluster.Resources.DeepCopy())
+}
+
+// MarkWriterHost marks the host which takes writes addressed to the shard.
+// It is the first host of the write tier, or the first host in case shard has read tier hosts only.
+func (shard *ChiShard) MarkWriterHost() {
+	var writer *ChiHost
+	for _, host := range shard.Hosts {
+		host.Runtime.Writer = false
+		if (writer == nil) && !host.IsReadTier() {
+			writer = host
+		}
+	}
+	if (writer == nil) && (len(shard.Hosts) > 0) {
+		writer = shard.Hosts[0]
+	}
+	if writer != nil {
+		writer.Runtime.Writer = true
+	}
+}
+
+// MarkSpotHosts marks hosts of the shard running on spot nodes, which are the last hosts of the shard
+func (shard *ChiShard) MarkSpotHosts(spot *ChiClusterSpot) {
+	first := len(shard.Hosts) - spot.GetReplicas(len(shard.Hosts))
+	for i, host := range shard.Hosts {
+		host.Runtime.Spot = i >= first
+	}
+}
+
+// HostsReadTierLast gets hosts of the shard ordered so that hosts of the read tier go last
+func (shard *ChiShard) HostsReadTierLast() []*ChiHost {
+	hosts := make([]*ChiHost, 0, len(shard.Hosts))
+	for _, host := range shard.Hosts {
+		if !host.IsReadTier() {
+			hosts = append(hosts, host)
+		}
+	}
+	for _, host := range shard.Hosts {
+		if host.IsReadTier() {
+			hosts = append(hosts, host)
+		}
+	}
+	return hosts
+}
+
+// InheritZonesFrom inherits zones from specified cluster
+func (shard *ChiShard) InheritZonesFrom(cluster *Cluster) {
+	if shard.Zones == nil {
+		shard.Zones = cluster.Zones.DeepCopy()
+	}
+}
+
+// GetReplicaZone gets zone assigned to the replica of the shard.
+// Replicas are spread across shard zones round-robin, starting from the zone shifted by shard index,
+// so first replicas of the shards do not pile up in the same zone.
+func (shard *ChiShard) GetReplicaZone(shardIndex, replicaIndex int) *ChiPodTemplateZone {
+	if shard.Zones == nil {
+		return nil
+	}
+	zones := shard.Zones.DeepCopy()
+	if len(zones.Values) == 0 {
+		if (len(zones.Tolerations) == 0) && (len(zones.MatchExpressions) == 0) {
+			return nil
+		}
+		// Zones specify tolerations and node selector requirements only
+		return &ChiPodTemplateZone{
+			Tolerations:      zones.Tolerations,
+			MatchExpressions: zones.MatchExpressions,
+		}
+	}
+	return &ChiPodTemplateZone{
+		Key:              zones.Key,
+		Values:           []string{zones.Values[(shardIndex+replicaIndex)%len(zones.Values)]},
+		Tolerations:      zones.Tolerations,
+		MatchExpressions: zones.MatchExpressions,
+	}
+}
+
 // GetServiceTemplate gets service template
 func (shard *ChiShard) GetServiceTemplate() (*ChiServiceTemplate, bool) {
 	if !shard.Templates.HasShardServiceTemplate() {
@@ -133,3 +213,11 @@ func (shard *ChiShard) GetWeight() int {
 	}
 	return 0
 }
+
+// IsFrozen checks whether shard is frozen for maintenance
+func (shard *ChiShard) IsFrozen() bool {
+	if shard == nil {
+		return false
+	}
+	return shard.Frozen.Value()
+}
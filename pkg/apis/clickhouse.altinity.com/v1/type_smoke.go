@@ -0,0 +1,67 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+// ChiSmokeTest defines suite of validation queries run on each host after it is updated
+type ChiSmokeTest struct {
+	Queries []ChiSmokeTestQuery `json:"queries,omitempty" yaml:"queries,omitempty"`
+}
+
+// ChiSmokeTestQuery defines validation query returning single value.
+// In case no value is expected, the value returned after update has to be equal to the one returned before update
+type ChiSmokeTestQuery struct {
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+	// SQL may contain macros, ex.: {cluster}
+	SQL    string `json:"sql,omitempty"    yaml:"sql,omitempty"`
+	Expect string `json:"expect,omitempty" yaml:"expect,omitempty"`
+}
+
+// HasExpect checks whether query has expected value specified
+func (q *ChiSmokeTestQuery) HasExpect() bool {
+	return q.Expect != ""
+}
+
+// GetQueries gets queries of the suite
+func (t *ChiSmokeTest) GetQueries() []ChiSmokeTestQuery {
+	if t == nil {
+		return nil
+	}
+	return t.Queries
+}
+
+// IsEnabled checks whether there are any queries to run
+func (t *ChiSmokeTest) IsEnabled() bool {
+	return len(t.GetQueries()) > 0
+}
+
+// MergeFrom merges from specified smoke test. Suite is merged as a whole
+func (t *ChiSmokeTest) MergeFrom(from *ChiSmokeTest, _type MergeType) *ChiSmokeTest {
+	if from == nil {
+		return t
+	}
+
+	switch _type {
+	case MergeTypeFillEmptyValues:
+		if !t.IsEnabled() {
+			return from.DeepCopy()
+		}
+	case MergeTypeOverrideByNonEmptyValues:
+		if from.IsEnabled() {
+			return from.DeepCopy()
+		}
+	}
+
+	return t
+}
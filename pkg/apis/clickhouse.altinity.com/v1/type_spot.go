@@ -0,0 +1,61 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import (
+	core "k8s.io/api/core/v1"
+)
+
+// Default time pods on spot nodes tolerate their node being not ready or unreachable before they are evicted
+const (
+	SpotEvictionSecondsDefault = 30
+)
+
+// ChiClusterSpot defines replicas of each shard running on spot (preemptible) nodes
+type ChiClusterSpot struct {
+	// Replicas specifies number of replicas of each shard running on spot nodes, the last replicas of a shard are designated.
+	// At least one replica of each shard is kept on on-demand nodes
+	Replicas int `json:"replicas,omitempty"        yaml:"replicas,omitempty"`
+	// NodeSelector specifies labels of spot nodes
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"    yaml:"nodeSelector,omitempty"`
+	// Tolerations are added to pods of spot replicas, ex.: to tolerate taints of spot nodes
+	Tolerations []core.Toleration `json:"tolerations,omitempty"     yaml:"tolerations,omitempty"`
+	// EvictionSeconds specifies how long pods of spot replicas stay bound to not ready or unreachable node
+	EvictionSeconds *int64 `json:"evictionSeconds,omitempty" yaml:"evictionSeconds,omitempty"`
+}
+
+// GetReplicas gets number of spot replicas out of specified number of replicas of a shard
+func (s *ChiClusterSpot) GetReplicas(replicasCount int) int {
+	if s == nil {
+		return 0
+	}
+	replicas := s.Replicas
+	if replicas > replicasCount-1 {
+		// Keep at least one on-demand replica
+		replicas = replicasCount - 1
+	}
+	if replicas < 0 {
+		return 0
+	}
+	return replicas
+}
+
+// GetEvictionSeconds gets how long pods of spot replicas stay bound to not ready or unreachable node
+func (s *ChiClusterSpot) GetEvictionSeconds() int64 {
+	if (s == nil) || (s.EvictionSeconds == nil) {
+		return SpotEvictionSecondsDefault
+	}
+	return *s.EvictionSeconds
+}
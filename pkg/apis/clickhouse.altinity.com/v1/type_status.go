@@ -74,6 +74,21 @@ type ChiStatus struct {
 	NormalizedCHICompleted *ClickHouseInstallation `json:"normalizedCompleted,omitempty"    yaml:"normalizedCompleted,omitempty"`
 	HostsWithTablesCreated []string                `json:"hostsWithTablesCreated,omitempty" yaml:"hostsWithTablesCreated,omitempty"`
 	UsedTemplates          []*ChiTemplateRef       `json:"usedTemplates,omitempty"          yaml:"usedTemplates,omitempty"`
+	Conditions             []ChiCondition          `json:"conditions,omitempty"             yaml:"conditions,omitempty"`
+	History                []ChiHistoryEntry       `json:"history,omitempty"                yaml:"history,omitempty"`
+	ObservedGeneration     int64                   `json:"observedGeneration,omitempty"     yaml:"observedGeneration,omitempty"`
+	Dependencies           []string                `json:"dependencies,omitempty"           yaml:"dependencies,omitempty"`
+	Footprint              *ChiFootprint           `json:"footprint,omitempty"              yaml:"footprint,omitempty"`
+	Observation            *ChiObservation         `json:"observation,omitempty"            yaml:"observation,omitempty"`
+	Approval               *ChiApproval            `json:"approval,omitempty"               yaml:"approval,omitempty"`
+	Rebalancing            []ChiRebalancingStatus  `json:"rebalancing,omitempty"            yaml:"rebalancing,omitempty"`
+
+	// CertificateRotations specifies history of managed certificate rotations, the latest first
+	CertificateRotations []ChiCertificateRotation `json:"certificateRotations,omitempty" yaml:"certificateRotations,omitempty"`
+	// PortAllocations specifies ports allocated to hosts by the Allocated port distribution
+	PortAllocations []ChiPortAllocation `json:"portAllocations,omitempty" yaml:"portAllocations,omitempty"`
+	// HostsHealth specifies recent health-checks of hosts
+	HostsHealth []ChiHostHealth `json:"hostsHealth,omitempty" yaml:"hostsHealth,omitempty"`
 
 	mu sync.RWMutex `json:"-" yaml:"-"`
 }
@@ -86,6 +101,13 @@ type CopyCHIStatusOptions struct {
 	MainFields        bool
 	WholeStatus       bool
 	InheritableFields bool
+	Observation       bool
+	Conditions        bool
+	Rebalancing       bool
+	// CertificateRotations specifies whether history of certificate rotations is copied
+	CertificateRotations bool
+	// HostsHealth specifies whether health of hosts is copied
+	HostsHealth bool
 }
 
 // FillStatusParams is a struct used to fill status params
@@ -154,6 +176,85 @@ func (s *ChiStatus) SetAndPushError(err string) {
 	})
 }
 
+// SetCondition sets status condition
+func (s *ChiStatus) SetCondition(condition ChiCondition) {
+	doWithWriteLock(s, func(s *ChiStatus) {
+		s.Conditions = setConditionNoSync(s.Conditions, condition)
+	})
+}
+
+// SetRebalancing sets rebalancing status of a cluster
+func (s *ChiStatus) SetRebalancing(rebalancing ChiRebalancingStatus) {
+	doWithWriteLock(s, func(s *ChiStatus) {
+		s.Rebalancing = setRebalancingNoSync(s.Rebalancing, rebalancing)
+	})
+}
+
+// SetCertificateRotation sets certificate rotation of a cluster
+func (s *ChiStatus) SetCertificateRotation(rotation ChiCertificateRotation) {
+	doWithWriteLock(s, func(s *ChiStatus) {
+		s.CertificateRotations = setCertificateRotationNoSync(s.CertificateRotations, rotation)
+	})
+}
+
+// SetHostsHealth sets health of hosts, replacing the one of all hosts
+func (s *ChiStatus) SetHostsHealth(health []ChiHostHealth) {
+	doWithWriteLock(s, func(s *ChiStatus) {
+		s.HostsHealth = health
+	})
+}
+
+// PushHistory pushes spec generation reconcile history entry
+func (s *ChiStatus) PushHistory(entry ChiHistoryEntry) {
+	doWithWriteLock(s, func(s *ChiStatus) {
+		pushHistoryNoSync(s, entry)
+	})
+}
+
+// FinishHistory sets outcome of the latest spec generation reconcile history entry
+func (s *ChiStatus) FinishHistory(outcome string) {
+	doWithWriteLock(s, func(s *ChiStatus) {
+		finishHistoryNoSync(s, outcome)
+	})
+}
+
+// PushDependency pushes versioned object the CHI depends on
+func (s *ChiStatus) PushDependency(dependency string) {
+	doWithWriteLock(s, func(s *ChiStatus) {
+		if !util.InArray(dependency, s.Dependencies) {
+			s.Dependencies = append(s.Dependencies, dependency)
+		}
+	})
+}
+
+// SetObservedGeneration sets generation which was reconciled successfully
+func (s *ChiStatus) SetObservedGeneration(generation int64) {
+	doWithWriteLock(s, func(s *ChiStatus) {
+		s.ObservedGeneration = generation
+	})
+}
+
+// SetFootprint sets total resources requested by the CHI
+func (s *ChiStatus) SetFootprint(footprint *ChiFootprint) {
+	doWithWriteLock(s, func(s *ChiStatus) {
+		s.Footprint = footprint
+	})
+}
+
+// SetObservation sets observation made in observe mode
+func (s *ChiStatus) SetObservation(observation *ChiObservation) {
+	doWithWriteLock(s, func(s *ChiStatus) {
+		s.Observation = observation
+	})
+}
+
+// SetApproval sets disruptive actions pending approval
+func (s *ChiStatus) SetApproval(approval *ChiApproval) {
+	doWithWriteLock(s, func(s *ChiStatus) {
+		s.Approval = approval
+	})
+}
+
 // PushHostTablesCreated pushes host to the list of hosts with created tables
 func (s *ChiStatus) PushHostTablesCreated(host string) {
 	doWithWriteLock(s, func(s *ChiStatus) {
@@ -298,6 +399,7 @@ func (s *ChiStatus) ReconcileComplete() {
 		s.Status = StatusCompleted
 		s.Action = ""
 		pushTaskIDCompletedNoSync(s)
+		finishHistoryNoSync(s, StatusCompleted)
 	})
 }
 
@@ -310,6 +412,7 @@ func (s *ChiStatus) ReconcileAbort() {
 		s.Status = StatusAborted
 		s.Action = ""
 		pushTaskIDCompletedNoSync(s)
+		finishHistoryNoSync(s, StatusAborted)
 	})
 }
 
@@ -344,6 +447,35 @@ func (s *ChiStatus) CopyFrom(f *ChiStatus, opts CopyCHIStatusOptions) {
 				s.Actions = from.Actions
 				s.Errors = from.Errors
 				s.HostsWithTablesCreated = from.HostsWithTablesCreated
+				s.Conditions = from.Conditions
+				s.History = from.History
+				s.ObservedGeneration = from.ObservedGeneration
+				s.Observation = from.Observation
+				s.Approval = from.Approval
+				s.Rebalancing = from.Rebalancing
+				s.CertificateRotations = from.CertificateRotations
+				s.PortAllocations = from.PortAllocations
+				s.HostsHealth = from.HostsHealth
+			}
+
+			if opts.Observation {
+				s.Observation = from.Observation
+			}
+
+			if opts.Conditions {
+				s.Conditions = from.Conditions
+			}
+
+			if opts.Rebalancing {
+				s.Rebalancing = from.Rebalancing
+			}
+
+			if opts.CertificateRotations {
+				s.CertificateRotations = from.CertificateRotations
+			}
+
+			if opts.HostsHealth {
+				s.HostsHealth = from.HostsHealth
 			}
 
 			if opts.Actions {
@@ -393,6 +525,13 @@ func (s *ChiStatus) CopyFrom(f *ChiStatus, opts CopyCHIStatusOptions) {
 				s.FQDNs = from.FQDNs
 				s.Endpoint = from.Endpoint
 				s.NormalizedCHI = from.NormalizedCHI
+				s.Conditions = from.Conditions
+				s.History = from.History
+				s.ObservedGeneration = from.ObservedGeneration
+				s.Dependencies = from.Dependencies
+				s.Footprint = from.Footprint
+				s.Approval = from.Approval
+				s.PortAllocations = from.PortAllocations
 			}
 
 			if opts.Normalized {
@@ -428,6 +567,17 @@ func (s *ChiStatus) CopyFrom(f *ChiStatus, opts CopyCHIStatusOptions) {
 				s.Endpoint = from.Endpoint
 				s.NormalizedCHI = from.NormalizedCHI
 				s.NormalizedCHICompleted = from.NormalizedCHICompleted
+				s.Conditions = from.Conditions
+				s.History = from.History
+				s.ObservedGeneration = from.ObservedGeneration
+				s.Dependencies = from.Dependencies
+				s.Footprint = from.Footprint
+				s.Observation = from.Observation
+				s.Approval = from.Approval
+				s.Rebalancing = from.Rebalancing
+				s.CertificateRotations = from.CertificateRotations
+				s.PortAllocations = from.PortAllocations
+				s.HostsHealth = from.HostsHealth
 			}
 		})
 	})
@@ -657,6 +807,146 @@ func (s *ChiStatus) GetHostsWithTablesCreated() []string {
 	})
 }
 
+// GetConditions gets status conditions
+func (s *ChiStatus) GetConditions() []ChiCondition {
+	var conditions []ChiCondition
+	doWithReadLock(s, func(s *ChiStatus) {
+		conditions = append(conditions, s.Conditions...)
+	})
+	return conditions
+}
+
+// GetObservedGeneration gets generation which was reconciled successfully
+func (s *ChiStatus) GetObservedGeneration() int64 {
+	var generation int64
+	doWithReadLock(s, func(s *ChiStatus) {
+		generation = s.ObservedGeneration
+	})
+	return generation
+}
+
+// GetDependencies gets versioned objects the CHI depends on
+func (s *ChiStatus) GetDependencies() []string {
+	return getStringArrWithReadLock(s, func(s *ChiStatus) []string {
+		return s.Dependencies
+	})
+}
+
+// GetFootprint gets total resources requested by the CHI
+func (s *ChiStatus) GetFootprint() *ChiFootprint {
+	var footprint *ChiFootprint
+	doWithReadLock(s, func(s *ChiStatus) {
+		footprint = s.Footprint
+	})
+	return footprint
+}
+
+// GetObservation gets observation made in observe mode
+func (s *ChiStatus) GetObservation() *ChiObservation {
+	var observation *ChiObservation
+	doWithReadLock(s, func(s *ChiStatus) {
+		observation = s.Observation
+	})
+	return observation
+}
+
+// GetApproval gets disruptive actions pending approval
+func (s *ChiStatus) GetApproval() *ChiApproval {
+	var approval *ChiApproval
+	doWithReadLock(s, func(s *ChiStatus) {
+		approval = s.Approval
+	})
+	return approval
+}
+
+// GetHistory gets spec generations reconcile history
+func (s *ChiStatus) GetHistory() []ChiHistoryEntry {
+	var history []ChiHistoryEntry
+	doWithReadLock(s, func(s *ChiStatus) {
+		history = append(history, s.History...)
+	})
+	return history
+}
+
+// GetCondition gets status condition of specified type
+func (s *ChiStatus) GetCondition(_type string) (ChiCondition, bool) {
+	for _, condition := range s.GetConditions() {
+		if condition.Type == _type {
+			return condition, true
+		}
+	}
+	return ChiCondition{}, false
+}
+
+// GetRebalancing gets rebalancing statuses of all clusters
+func (s *ChiStatus) GetRebalancing() []ChiRebalancingStatus {
+	var rebalancing []ChiRebalancingStatus
+	doWithReadLock(s, func(s *ChiStatus) {
+		rebalancing = append(rebalancing, s.Rebalancing...)
+	})
+	return rebalancing
+}
+
+// SetPortAllocations sets ports allocated to hosts
+func (s *ChiStatus) SetPortAllocations(allocations []ChiPortAllocation) {
+	doWithWriteLock(s, func(s *ChiStatus) {
+		s.PortAllocations = allocations
+	})
+}
+
+// GetPortAllocations gets ports allocated to hosts
+func (s *ChiStatus) GetPortAllocations() []ChiPortAllocation {
+	var allocations []ChiPortAllocation
+	doWithReadLock(s, func(s *ChiStatus) {
+		allocations = append(allocations, s.PortAllocations...)
+	})
+	return allocations
+}
+
+// GetClusterRebalancing gets rebalancing status of the cluster
+func (s *ChiStatus) GetClusterRebalancing(cluster string) (ChiRebalancingStatus, bool) {
+	for _, rebalancing := range s.GetRebalancing() {
+		if rebalancing.Cluster == cluster {
+			return rebalancing, true
+		}
+	}
+	return ChiRebalancingStatus{}, false
+}
+
+// GetCertificateRotations gets history of certificate rotations, the latest first
+func (s *ChiStatus) GetCertificateRotations() []ChiCertificateRotation {
+	var rotations []ChiCertificateRotation
+	doWithReadLock(s, func(s *ChiStatus) {
+		rotations = append(rotations, s.CertificateRotations...)
+	})
+	return rotations
+}
+
+// GetClusterCertificateRotation gets the latest certificate rotation of the cluster
+func (s *ChiStatus) GetClusterCertificateRotation(cluster string) (ChiCertificateRotation, bool) {
+	for _, rotation := range s.GetCertificateRotations() {
+		if rotation.Cluster == cluster {
+			return rotation, true
+		}
+	}
+	return ChiCertificateRotation{}, false
+}
+
+// GetHostHealth gets health of the host of the cluster
+func (s *ChiStatus) GetHostHealth(cluster, host string) (ChiHostHealth, bool) {
+	var health ChiHostHealth
+	found := false
+	doWithReadLock(s, func(s *ChiStatus) {
+		for _, h := range s.HostsHealth {
+			if (h.Cluster == cluster) && (h.Host == host) {
+				health, found = h, true
+				return
+			}
+		}
+	})
+	return health, found
+}
+
 // Begin helpers
 
 func doWithWriteLock(s *ChiStatus, f func(s *ChiStatus)) {
@@ -14,10 +14,85 @@
 
 package v1
 
+import (
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
 // StorageManagement defines storage management config
 type StorageManagement struct {
 	PVCProvisioner   PVCProvisioner   `json:"provisioner,omitempty"   yaml:"provisioner,omitempty"`
 	PVCReclaimPolicy PVCReclaimPolicy `json:"reclaimPolicy,omitempty" yaml:"reclaimPolicy,omitempty"`
+	// DiskPressureThreshold specifies percentage of used disk space at which DiskPressure condition is raised.
+	// Zero means disk usage is not monitored.
+	DiskPressureThreshold int `json:"diskPressureThreshold,omitempty" yaml:"diskPressureThreshold,omitempty"`
+	// AutoExpansion specifies how PVC is expanded automatically under disk pressure
+	AutoExpansion *StorageAutoExpansion `json:"autoExpansion,omitempty" yaml:"autoExpansion,omitempty"`
+}
+
+// AutoExpansionStepPercentDefault specifies default step of automatic PVC expansion
+const AutoExpansionStepPercentDefault = 20
+
+// StorageAutoExpansion defines automatic PVC expansion config
+type StorageAutoExpansion struct {
+	Enabled *StringBool `json:"enabled,omitempty"     yaml:"enabled,omitempty"`
+	// StepPercent specifies by how many percent storage request is increased at once
+	StepPercent int `json:"stepPercent,omitempty" yaml:"stepPercent,omitempty"`
+	// MaxSize specifies storage request PVC is not expanded beyond
+	MaxSize string `json:"maxSize,omitempty"     yaml:"maxSize,omitempty"`
+}
+
+// IsEnabled checks whether auto expansion is enabled
+func (e *StorageAutoExpansion) IsEnabled() bool {
+	if e == nil {
+		return false
+	}
+	return e.Enabled.Value()
+}
+
+// GetMaxSize gets max size as a quantity
+func (e *StorageAutoExpansion) GetMaxSize() (resource.Quantity, bool) {
+	if e == nil {
+		return resource.Quantity{}, false
+	}
+	maxSize, err := resource.ParseQuantity(e.MaxSize)
+	if err != nil {
+		return resource.Quantity{}, false
+	}
+	return maxSize, true
+}
+
+// MergeFrom merges from specified object
+func (e *StorageAutoExpansion) MergeFrom(from *StorageAutoExpansion, _type MergeType) *StorageAutoExpansion {
+	if from == nil {
+		return e
+	}
+
+	if e == nil {
+		e = &StorageAutoExpansion{}
+	}
+
+	switch _type {
+	case MergeTypeFillEmptyValues:
+		e.Enabled = e.Enabled.MergeFrom(from.Enabled)
+		if e.StepPercent == 0 {
+			e.StepPercent = from.StepPercent
+		}
+		if e.MaxSize == "" {
+			e.MaxSize = from.MaxSize
+		}
+	case MergeTypeOverrideByNonEmptyValues:
+		if from.Enabled.HasValue() {
+			e.Enabled = from.Enabled
+		}
+		if from.StepPercent != 0 {
+			e.StepPercent = from.StepPercent
+		}
+		if from.MaxSize != "" {
+			e.MaxSize = from.MaxSize
+		}
+	}
+
+	return e
 }
 
 // NewStorageManagement creates new StorageManagement
@@ -53,6 +128,10 @@ func (storageManagement *StorageManagement) mergeFromFillEmptyValues(from *Stora
 	if storageManagement.PVCReclaimPolicy == PVCReclaimPolicyUnspecified {
 		storageManagement.PVCReclaimPolicy = from.PVCReclaimPolicy
 	}
+	if storageManagement.DiskPressureThreshold == 0 {
+		storageManagement.DiskPressureThreshold = from.DiskPressureThreshold
+	}
+	storageManagement.AutoExpansion = storageManagement.AutoExpansion.MergeFrom(from.AutoExpansion, MergeTypeFillEmptyValues)
 	return storageManagement
 }
 
@@ -64,5 +143,9 @@ func (storageManagement *StorageManagement) mergeFromOverwriteByNonEmptyValues(f
 	if from.PVCReclaimPolicy != PVCReclaimPolicyUnspecified {
 		storageManagement.PVCReclaimPolicy = from.PVCReclaimPolicy
 	}
+	if from.DiskPressureThreshold != 0 {
+		storageManagement.DiskPressureThreshold = from.DiskPressureThreshold
+	}
+	storageManagement.AutoExpansion = storageManagement.AutoExpansion.MergeFrom(from.AutoExpansion, MergeTypeOverrideByNonEmptyValues)
 	return storageManagement
 }
@@ -83,6 +83,22 @@ func (templateNames *ChiTemplateNames) GetLogVolumeClaimTemplate() string {
 	return templateNames.LogVolumeClaimTemplate
 }
 
+// HasSystemLogVolumeClaimTemplate checks whether system log volume claim template is specified
+func (templateNames *ChiTemplateNames) HasSystemLogVolumeClaimTemplate() bool {
+	if templateNames == nil {
+		return false
+	}
+	return len(templateNames.SystemLogVolumeClaimTemplate) > 0
+}
+
+// GetSystemLogVolumeClaimTemplate gets system log volume claim template
+func (templateNames *ChiTemplateNames) GetSystemLogVolumeClaimTemplate() string {
+	if templateNames == nil {
+		return ""
+	}
+	return templateNames.SystemLogVolumeClaimTemplate
+}
+
 // HasServiceTemplate checks whether service template is specified
 func (templateNames *ChiTemplateNames) HasServiceTemplate() bool {
 	if templateNames == nil {
@@ -147,6 +163,38 @@ func (templateNames *ChiTemplateNames) GetReplicaServiceTemplate() string {
 	return templateNames.ReplicaServiceTemplate
 }
 
+// HasReaderServiceTemplate checks whether reader service template is specified
+func (templateNames *ChiTemplateNames) HasReaderServiceTemplate() bool {
+	if templateNames == nil {
+		return false
+	}
+	return len(templateNames.ReaderServiceTemplate) > 0
+}
+
+// GetReaderServiceTemplate gets reader service template
+func (templateNames *ChiTemplateNames) GetReaderServiceTemplate() string {
+	if templateNames == nil {
+		return ""
+	}
+	return templateNames.ReaderServiceTemplate
+}
+
+// HasWriterServiceTemplate checks whether writer service template is specified
+func (templateNames *ChiTemplateNames) HasWriterServiceTemplate() bool {
+	if templateNames == nil {
+		return false
+	}
+	return len(templateNames.WriterServiceTemplate) > 0
+}
+
+// GetWriterServiceTemplate gets writer service template
+func (templateNames *ChiTemplateNames) GetWriterServiceTemplate() string {
+	if templateNames == nil {
+		return ""
+	}
+	return templateNames.WriterServiceTemplate
+}
+
 // HandleDeprecatedFields helps to deal with deprecated fields
 func (templateNames *ChiTemplateNames) HandleDeprecatedFields() {
 	if templateNames == nil {
@@ -191,6 +239,9 @@ func (templateNames *ChiTemplateNames) mergeFromFillEmptyValues(from *ChiTemplat
 	if templateNames.LogVolumeClaimTemplate == "" {
 		templateNames.LogVolumeClaimTemplate = from.LogVolumeClaimTemplate
 	}
+	if templateNames.SystemLogVolumeClaimTemplate == "" {
+		templateNames.SystemLogVolumeClaimTemplate = from.SystemLogVolumeClaimTemplate
+	}
 	if templateNames.VolumeClaimTemplate == "" {
 		templateNames.VolumeClaimTemplate = from.VolumeClaimTemplate
 	}
@@ -206,6 +257,12 @@ func (templateNames *ChiTemplateNames) mergeFromFillEmptyValues(from *ChiTemplat
 	if templateNames.ReplicaServiceTemplate == "" {
 		templateNames.ReplicaServiceTemplate = from.ReplicaServiceTemplate
 	}
+	if templateNames.ReaderServiceTemplate == "" {
+		templateNames.ReaderServiceTemplate = from.ReaderServiceTemplate
+	}
+	if templateNames.WriterServiceTemplate == "" {
+		templateNames.WriterServiceTemplate = from.WriterServiceTemplate
+	}
 	return templateNames
 }
 
@@ -223,6 +280,9 @@ func (templateNames *ChiTemplateNames) mergeFromOverwriteByNonEmptyValues(from *
 	if from.LogVolumeClaimTemplate != "" {
 		templateNames.LogVolumeClaimTemplate = from.LogVolumeClaimTemplate
 	}
+	if from.SystemLogVolumeClaimTemplate != "" {
+		templateNames.SystemLogVolumeClaimTemplate = from.SystemLogVolumeClaimTemplate
+	}
 	if from.VolumeClaimTemplate != "" {
 		templateNames.VolumeClaimTemplate = from.VolumeClaimTemplate
 	}
@@ -238,5 +298,11 @@ func (templateNames *ChiTemplateNames) mergeFromOverwriteByNonEmptyValues(from *
 	if from.ReplicaServiceTemplate != "" {
 		templateNames.ReplicaServiceTemplate = from.ReplicaServiceTemplate
 	}
+	if from.ReaderServiceTemplate != "" {
+		templateNames.ReaderServiceTemplate = from.ReaderServiceTemplate
+	}
+	if from.WriterServiceTemplate != "" {
+		templateNames.WriterServiceTemplate = from.WriterServiceTemplate
+	}
 	return templateNames
 }
@@ -51,6 +51,13 @@ func (templates *ChiTemplates) GetServiceTemplates() []ChiServiceTemplate {
 	return templates.ServiceTemplates
 }
 
+func (templates *ChiTemplates) GetClusterTemplates() []ChiClusterTemplate {
+	if templates == nil {
+		return nil
+	}
+	return templates.ClusterTemplates
+}
+
 // Len returns accumulated len of all templates
 func (templates *ChiTemplates) Len() int {
 	if templates == nil {
@@ -61,7 +68,8 @@ func (templates *ChiTemplates) Len() int {
 		len(templates.HostTemplates) +
 		len(templates.PodTemplates) +
 		len(templates.VolumeClaimTemplates) +
-		len(templates.ServiceTemplates)
+		len(templates.ServiceTemplates) +
+		len(templates.ClusterTemplates)
 }
 
 // MergeFrom merges from specified object
@@ -93,6 +101,7 @@ func (templates *ChiTemplates) MergeFrom(_from any, _type MergeType) *ChiTemplat
 	templates.mergePodTemplates(from)
 	templates.mergeVolumeClaimTemplates(from)
 	templates.mergeServiceTemplates(from)
+	templates.mergeClusterTemplates(from)
 
 	return templates
 }
@@ -234,6 +243,39 @@ func (templates *ChiTemplates) mergeServiceTemplates(from *ChiTemplates) {
 	}
 }
 
+// mergeClusterTemplates merges cluster templates section
+func (templates *ChiTemplates) mergeClusterTemplates(from *ChiTemplates) {
+	if len(from.ClusterTemplates) == 0 {
+		return
+	}
+
+	// We have templates to merge from
+	// Loop over all 'from' templates and either copy it in case no such template in receiver or merge it
+	for fromIndex := range from.ClusterTemplates {
+		fromTemplate := &from.ClusterTemplates[fromIndex]
+
+		// Try to find entry with the same name among local templates in receiver
+		sameNameFound := false
+		for toIndex := range templates.ClusterTemplates {
+			toTemplate := &templates.ClusterTemplates[toIndex]
+			if toTemplate.Name == fromTemplate.Name {
+				// Receiver already have such a template
+				sameNameFound = true
+				// Merge `to` template with `from` template
+				_ = mergo.Merge(toTemplate, *fromTemplate, mergo.WithSliceDeepMerge)
+				// Receiver `to` template is processed
+				break
+			}
+		}
+
+		if !sameNameFound {
+			// Receiver does not have template with such a name
+			// Append template from `from`
+			templates.ClusterTemplates = append(templates.ClusterTemplates, *fromTemplate.DeepCopy())
+		}
+	}
+}
+
 // GetHostTemplatesIndex returns index of host templates
 func (templates *ChiTemplates) GetHostTemplatesIndex() *HostTemplatesIndex {
 	if templates == nil {
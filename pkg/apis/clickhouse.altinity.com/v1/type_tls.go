@@ -0,0 +1,129 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import (
+	"time"
+
+	"github.com/altinity/clickhouse-operator/pkg/util"
+)
+
+// Default lifetime of certificates managed by the operator
+const (
+	TLSValidityDaysDefault    = 365
+	TLSRenewBeforeDaysDefault = 30
+)
+
+// Possible certificate rotation statuses
+const (
+	CertificateRotationStatusInProgress = "InProgress"
+	CertificateRotationStatusCompleted  = "Completed"
+)
+
+const (
+	maxCertificateRotations = 10
+)
+
+// ChiClusterTLS defines interserver TLS of the cluster
+type ChiClusterTLS struct {
+	// Managed specifies whether certificates of hosts of the cluster are issued and rotated by the operator
+	Managed *StringBool `json:"managed,omitempty"         yaml:"managed,omitempty"`
+	// ValidityDays specifies lifetime of issued certificates
+	ValidityDays int `json:"validityDays,omitempty"    yaml:"validityDays,omitempty"`
+	// RenewBeforeDays specifies how long before expiry certificates are rotated
+	RenewBeforeDays int `json:"renewBeforeDays,omitempty" yaml:"renewBeforeDays,omitempty"`
+}
+
+// IsManaged checks whether certificates are managed by the operator
+func (t *ChiClusterTLS) IsManaged() bool {
+	if t == nil {
+		return false
+	}
+	return t.Managed.Value()
+}
+
+// GetValidity gets lifetime of issued certificates
+func (t *ChiClusterTLS) GetValidity() time.Duration {
+	if t == nil {
+		return 0
+	}
+	return time.Duration(t.ValidityDays) * 24 * time.Hour
+}
+
+// GetRenewBefore gets how long before expiry certificates are rotated
+func (t *ChiClusterTLS) GetRenewBefore() time.Duration {
+	if t == nil {
+		return 0
+	}
+	return time.Duration(t.RenewBeforeDays) * 24 * time.Hour
+}
+
+// ChiCertificateRotation describes rotation of managed certificate of a cluster
+type ChiCertificateRotation struct {
+	Cluster string `json:"cluster"              yaml:"cluster"`
+	Status  string `json:"status"               yaml:"status"`
+	// NotAfter specifies expiry of the new certificate
+	NotAfter string `json:"notAfter,omitempty"   yaml:"notAfter,omitempty"`
+	// Hosts specifies hosts reloaded with the new certificate
+	Hosts      []string `json:"hosts,omitempty"      yaml:"hosts,omitempty"`
+	Error      string   `json:"error,omitempty"      yaml:"error,omitempty"`
+	StartTime  string   `json:"startTime,omitempty"  yaml:"startTime,omitempty"`
+	UpdateTime string   `json:"updateTime,omitempty" yaml:"updateTime,omitempty"`
+}
+
+// NewChiCertificateRotation creates new rotation of the cluster certificate
+func NewChiCertificateRotation(cluster string, notAfter time.Time) ChiCertificateRotation {
+	now := time.Now().UTC().Format(time.RFC3339)
+	return ChiCertificateRotation{
+		Cluster:    cluster,
+		Status:     CertificateRotationStatusInProgress,
+		NotAfter:   notAfter.UTC().Format(time.RFC3339),
+		StartTime:  now,
+		UpdateTime: now,
+	}
+}
+
+// IsInProgress checks whether rotation is in progress
+func (r ChiCertificateRotation) IsInProgress() bool {
+	return r.Status == CertificateRotationStatusInProgress
+}
+
+// IsHostReloaded checks whether host is reloaded with the new certificate
+func (r ChiCertificateRotation) IsHostReloaded(name string) bool {
+	return util.InArray(name, r.Hosts)
+}
+
+// GetStartTime gets time rotation started at
+func (r ChiCertificateRotation) GetStartTime() time.Time {
+	t, _ := time.Parse(time.RFC3339, r.StartTime)
+	return t
+}
+
+// setCertificateRotationNoSync sets rotation, replacing the same rotation of the cluster or pushing it as the latest one.
+// At most maxCertificateRotations entries are kept
+func setCertificateRotationNoSync(rotations []ChiCertificateRotation, entry ChiCertificateRotation) []ChiCertificateRotation {
+	entry.UpdateTime = time.Now().UTC().Format(time.RFC3339)
+	for i := range rotations {
+		if (rotations[i].Cluster == entry.Cluster) && (rotations[i].StartTime == entry.StartTime) {
+			rotations[i] = entry
+			return rotations
+		}
+	}
+	rotations = append([]ChiCertificateRotation{entry}, rotations...)
+	if len(rotations) > maxCertificateRotations {
+		rotations = rotations[:maxCertificateRotations]
+	}
+	return rotations
+}
@@ -0,0 +1,50 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import "strconv"
+
+// InsertQuorumMajority specifies insert quorum of the majority of replicas of a shard
+const InsertQuorumMajority = "majority"
+
+// ChiWriteReliability defines defaults of write-related settings of the cluster's default profile
+type ChiWriteReliability struct {
+	// InsertQuorum is either number of replicas or "majority" of replicas of a shard
+	InsertQuorum string `json:"insertQuorum,omitempty" yaml:"insertQuorum,omitempty"`
+	// InsertQuorumTimeout specifies quorum write timeout in milliseconds
+	InsertQuorumTimeout         *int64      `json:"insertQuorumTimeout,omitempty"         yaml:"insertQuorumTimeout,omitempty"`
+	SelectSequentialConsistency *StringBool `json:"selectSequentialConsistency,omitempty" yaml:"selectSequentialConsistency,omitempty"`
+}
+
+// GetInsertQuorum gets insert quorum for a shard with specified number of replicas
+func (w *ChiWriteReliability) GetInsertQuorum(replicasCount int) string {
+	if w == nil {
+		return "0"
+	}
+	if w.InsertQuorum == InsertQuorumMajority {
+		return strconv.Itoa(replicasCount/2 + 1)
+	}
+	return w.InsertQuorum
+}
+
+// HasWriteReliability checks whether any cluster of the CHI specifies write reliability defaults
+func (chi *ClickHouseInstallation) HasWriteReliability() bool {
+	found := false
+	chi.WalkClusters(func(cluster *Cluster) error {
+		found = found || (cluster.WriteReliability != nil)
+		return nil
+	})
+	return found
+}
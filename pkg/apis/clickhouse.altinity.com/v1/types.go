@@ -102,13 +102,18 @@ type ChiSpec struct {
 	Stop                   *StringBool       `json:"stop,omitempty"                   yaml:"stop,omitempty"`
 	Restart                string            `json:"restart,omitempty"                yaml:"restart,omitempty"`
 	Troubleshoot           *StringBool       `json:"troubleshoot,omitempty"           yaml:"troubleshoot,omitempty"`
+	Debug                  *ChiDebug         `json:"debug,omitempty"                  yaml:"debug,omitempty"`
 	NamespaceDomainPattern string            `json:"namespaceDomainPattern,omitempty" yaml:"namespaceDomainPattern,omitempty"`
+	Timezone               string            `json:"timezone,omitempty"               yaml:"timezone,omitempty"`
 	Templating             *ChiTemplating    `json:"templating,omitempty"             yaml:"templating,omitempty"`
 	Reconciling            *ChiReconciling   `json:"reconciling,omitempty"            yaml:"reconciling,omitempty"`
 	Defaults               *ChiDefaults      `json:"defaults,omitempty"               yaml:"defaults,omitempty"`
 	Configuration          *Configuration    `json:"configuration,omitempty"          yaml:"configuration,omitempty"`
 	Templates              *ChiTemplates     `json:"templates,omitempty"              yaml:"templates,omitempty"`
 	UseTemplates           []*ChiTemplateRef `json:"useTemplates,omitempty"           yaml:"useTemplates,omitempty"`
+	Chproxy                *ChiChproxy       `json:"chproxy,omitempty"                yaml:"chproxy,omitempty"`
+	Logging                *ChiLogging       `json:"logging,omitempty"                yaml:"logging,omitempty"`
+	Fork                   *ChiFork          `json:"fork,omitempty"                   yaml:"fork,omitempty"`
 }
 
 // ChiTemplateRef defines UseTemplate section of ClickHouseInstallation resource
@@ -425,7 +430,8 @@ func (t *ChiCleanup) DefaultUnknownObjects() *ChiObjectsCleanup {
 		SetStatefulSet(ObjectsCleanupDelete).
 		SetPVC(ObjectsCleanupDelete).
 		SetConfigMap(ObjectsCleanupDelete).
-		SetService(ObjectsCleanupDelete)
+		SetService(ObjectsCleanupDelete).
+		SetSecret(ObjectsCleanupDelete)
 }
 
 // GetReconcileFailedObjects gets failed objects cleanup
@@ -442,7 +448,8 @@ func (t *ChiCleanup) DefaultReconcileFailedObjects() *ChiObjectsCleanup {
 		SetStatefulSet(ObjectsCleanupRetain).
 		SetPVC(ObjectsCleanupRetain).
 		SetConfigMap(ObjectsCleanupRetain).
-		SetService(ObjectsCleanupRetain)
+		SetService(ObjectsCleanupRetain).
+		SetSecret(ObjectsCleanupRetain)
 }
 
 // SetDefaults set defaults for cleanup
@@ -463,6 +470,10 @@ type ChiReconciling struct {
 	ConfigMapPropagationTimeout int `json:"configMapPropagationTimeout,omitempty" yaml:"configMapPropagationTimeout,omitempty"`
 	// Cleanup specifies cleanup behavior
 	Cleanup *ChiCleanup `json:"cleanup,omitempty" yaml:"cleanup,omitempty"`
+	// SmokeTest specifies validation queries run on each host after it is updated
+	SmokeTest *ChiSmokeTest `json:"smokeTest,omitempty" yaml:"smokeTest,omitempty"`
+	// Queries specifies timeouts and retries of SQL queries run by the operator on hosts
+	Queries *ChiQueries `json:"queries,omitempty" yaml:"queries,omitempty"`
 }
 
 // NewChiReconciling creates new reconciling
@@ -500,6 +511,8 @@ func (t *ChiReconciling) MergeFrom(from *ChiReconciling, _type MergeType) *ChiRe
 	}
 
 	t.Cleanup = t.Cleanup.MergeFrom(from.Cleanup, _type)
+	t.SmokeTest = t.SmokeTest.MergeFrom(from.SmokeTest, _type)
+	t.Queries = t.Queries.MergeFrom(from.Queries, _type)
 
 	return t
 }
@@ -531,6 +544,22 @@ func (t *ChiReconciling) SetPolicy(p string) {
 	t.Policy = p
 }
 
+// GetSmokeTest gets smoke test
+func (t *ChiReconciling) GetSmokeTest() *ChiSmokeTest {
+	if t == nil {
+		return nil
+	}
+	return t.SmokeTest
+}
+
+// GetQueries gets queries
+func (t *ChiReconciling) GetQueries() *ChiQueries {
+	if t == nil {
+		return nil
+	}
+	return t.Queries
+}
+
 // GetConfigMapPropagationTimeout gets config map propagation timeout
 func (t *ChiReconciling) GetConfigMapPropagationTimeout() int {
 	if t == nil {
@@ -590,6 +619,11 @@ type ChiTemplateNames struct {
 	ClusterServiceTemplate  string `json:"clusterServiceTemplate,omitempty"  yaml:"clusterServiceTemplate,omitempty"`
 	ShardServiceTemplate    string `json:"shardServiceTemplate,omitempty"    yaml:"shardServiceTemplate,omitempty"`
 	ReplicaServiceTemplate  string `json:"replicaServiceTemplate,omitempty"  yaml:"replicaServiceTemplate,omitempty"`
+	ReaderServiceTemplate   string `json:"readerServiceTemplate,omitempty"   yaml:"readerServiceTemplate,omitempty"`
+	WriterServiceTemplate   string `json:"writerServiceTemplate,omitempty"   yaml:"writerServiceTemplate,omitempty"`
+
+	// SystemLogVolumeClaimTemplate specifies volume claim template for system.*_log tables
+	SystemLogVolumeClaimTemplate string `json:"systemLogVolumeClaimTemplate,omitempty" yaml:"systemLogVolumeClaimTemplate,omitempty"`
 
 	// VolumeClaimTemplate is deprecated in favor of DataVolumeClaimTemplate and LogVolumeClaimTemplate
 	// !!! DEPRECATED !!!
@@ -604,8 +638,18 @@ type ChiShard struct {
 	InternalReplication *StringBool       `json:"internalReplication,omitempty" yaml:"internalReplication,omitempty"`
 	Settings            *Settings         `json:"settings,omitempty"            yaml:"settings,omitempty"`
 	Files               *Settings         `json:"files,omitempty"               yaml:"files,omitempty"`
+	Macros              *Settings         `json:"macros,omitempty"              yaml:"macros,omitempty"`
 	Templates           *ChiTemplateNames `json:"templates,omitempty"           yaml:"templates,omitempty"`
 	ReplicasCount       int               `json:"replicasCount,omitempty"       yaml:"replicasCount,omitempty"`
+	// HostPorts specifies ports of hosts of the shard, override ports of the cluster
+	HostPorts `json:",inline" yaml:",inline"`
+	// Zones specifies zones replicas of the shard are spread across
+	Zones *ChiPodTemplateZone `json:"zones,omitempty" yaml:"zones,omitempty"`
+	// Resources specifies resources of ClickHouse container of hosts of the shard
+	Resources *core.ResourceRequirements `json:"resources,omitempty" yaml:"resources,omitempty"`
+	// Frozen excludes hosts of the shard from reconcile, so they are neither restarted nor receive changes of personal config.
+	// Config shared by all hosts, common and users ConfigMaps, is still updated
+	Frozen *StringBool `json:"frozen,omitempty" yaml:"frozen,omitempty"`
 	// TODO refactor into map[string]ChiHost
 	Hosts []*ChiHost `json:"replicas,omitempty" yaml:"replicas,omitempty"`
 
@@ -626,8 +670,19 @@ type ChiReplica struct {
 	Name        string            `json:"name,omitempty"        yaml:"name,omitempty"`
 	Settings    *Settings         `json:"settings,omitempty"    yaml:"settings,omitempty"`
 	Files       *Settings         `json:"files,omitempty"       yaml:"files,omitempty"`
+	Macros      *Settings         `json:"macros,omitempty"      yaml:"macros,omitempty"`
 	Templates   *ChiTemplateNames `json:"templates,omitempty"   yaml:"templates,omitempty"`
 	ShardsCount int               `json:"shardsCount,omitempty" yaml:"shardsCount,omitempty"`
+	// HostPorts specifies ports of hosts of the replica, override ports of the shard and the cluster
+	HostPorts `json:",inline" yaml:",inline"`
+	// Tier specifies tier of the hosts of the replica
+	Tier string `json:"tier,omitempty" yaml:"tier,omitempty"`
+	// Priority specifies priority of the hosts of the replica in remote_servers
+	Priority *int `json:"priority,omitempty" yaml:"priority,omitempty"`
+	// Resources specifies resources of ClickHouse container of hosts of the replica
+	Resources *core.ResourceRequirements `json:"resources,omitempty" yaml:"resources,omitempty"`
+	// Annotations specifies annotations of pods of hosts of the replica
+	Annotations map[string]string `json:"annotations,omitempty" yaml:"annotations,omitempty"`
 	// TODO refactor into map[string]ChiHost
 	Hosts []*ChiHost `json:"shards,omitempty" yaml:"shards,omitempty"`
 
@@ -666,6 +721,12 @@ type ChiHostTemplate struct {
 	Spec             ChiHost               `json:"spec,omitempty"             yaml:"spec,omitempty"`
 }
 
+// ChiClusterTemplate defines named cluster spec, which clusters refer to by `clusterTemplate`
+type ChiClusterTemplate struct {
+	Name string  `json:"name,omitempty" yaml:"name,omitempty"`
+	Spec Cluster `json:"spec,omitempty" yaml:"spec,omitempty"`
+}
+
 // ChiPortDistribution defines port distribution
 type ChiPortDistribution struct {
 	Type string `json:"type,omitempty"   yaml:"type,omitempty"`
@@ -685,6 +746,8 @@ type ChiTemplates struct {
 	PodTemplates         []ChiPodTemplate         `json:"podTemplates,omitempty"         yaml:"podTemplates,omitempty"`
 	VolumeClaimTemplates []ChiVolumeClaimTemplate `json:"volumeClaimTemplates,omitempty" yaml:"volumeClaimTemplates,omitempty"`
 	ServiceTemplates     []ChiServiceTemplate     `json:"serviceTemplates,omitempty"     yaml:"serviceTemplates,omitempty"`
+	// ClusterTemplates specifies named cluster specs to be instantiated by clusters of the configuration
+	ClusterTemplates []ChiClusterTemplate `json:"clusterTemplates,omitempty" yaml:"clusterTemplates,omitempty"`
 
 	// Index maps template name to template itself
 	HostTemplatesIndex        *HostTemplatesIndex        `json:",omitempty" yaml:",omitempty" testdiff:"ignore"`
@@ -699,14 +762,41 @@ type ChiPodTemplate struct {
 	GenerateName    string               `json:"generateName,omitempty"    yaml:"generateName,omitempty"`
 	Zone            ChiPodTemplateZone   `json:"zone,omitempty"            yaml:"zone,omitempty"`
 	PodDistribution []ChiPodDistribution `json:"podDistribution,omitempty" yaml:"podDistribution,omitempty"`
-	ObjectMeta      meta.ObjectMeta      `json:"metadata,omitempty"        yaml:"metadata,omitempty"`
-	Spec            core.PodSpec         `json:"spec,omitempty"            yaml:"spec,omitempty"`
-}
-
-// ChiPodTemplateZone defines pod template zone
+	// Architecture specifies CPU architecture of nodes pods are scheduled on, ex.: amd64, arm64
+	Architecture string `json:"architecture,omitempty" yaml:"architecture,omitempty"`
+	// Container customizes ClickHouse container without specifying the whole container in the Spec
+	Container *ChiPodTemplateContainer `json:"container,omitempty"       yaml:"container,omitempty"`
+	// Sidecars are containers run alongside ClickHouse container, ex.: log shippers, backup agents, chproxy
+	Sidecars []core.Container `json:"sidecars,omitempty"        yaml:"sidecars,omitempty"`
+	// InitContainers run before ClickHouse container starts, ex.: permissions fixups, config fetching, schema seeding
+	InitContainers []core.Container `json:"initContainers,omitempty"  yaml:"initContainers,omitempty"`
+	ObjectMeta     meta.ObjectMeta  `json:"metadata,omitempty"        yaml:"metadata,omitempty"`
+	Spec           core.PodSpec     `json:"spec,omitempty"            yaml:"spec,omitempty"`
+}
+
+// ChiPodTemplateContainer defines customization applied on top of ClickHouse container, either default or specified
+type ChiPodTemplateContainer struct {
+	// Images maps CPU architecture to the image of the container built for it
+	Images map[string]string `json:"images,omitempty"            yaml:"images,omitempty"`
+	// Command overrides entrypoint of the container
+	Command []string `json:"command,omitempty"           yaml:"command,omitempty"`
+	// Args overrides arguments of the entrypoint
+	Args []string `json:"args,omitempty"              yaml:"args,omitempty"`
+	// ExtraVolumes are added to the Pod
+	ExtraVolumes []core.Volume `json:"extraVolumes,omitempty"      yaml:"extraVolumes,omitempty"`
+	// ExtraVolumeMounts are added to the container
+	ExtraVolumeMounts []core.VolumeMount `json:"extraVolumeMounts,omitempty" yaml:"extraVolumeMounts,omitempty"`
+}
+
+// ChiPodTemplateZone defines pod template zone.
+// Also used by clusters and shards to specify zones replicas are spread across, and by hosts to specify assigned zone.
 type ChiPodTemplateZone struct {
 	Key    string   `json:"key,omitempty"    yaml:"key,omitempty"`
 	Values []string `json:"values,omitempty" yaml:"values,omitempty"`
+	// Tolerations allow pods to be scheduled on tainted nodes of the zone, ex.: dedicated node pool
+	Tolerations []core.Toleration `json:"tolerations,omitempty" yaml:"tolerations,omitempty"`
+	// MatchExpressions are additional node selector requirements of the zone, ex.: ssd In [true]
+	MatchExpressions []core.NodeSelectorRequirement `json:"matchExpressions,omitempty" yaml:"matchExpressions,omitempty"`
 }
 
 // ChiPodDistribution defines pod distribution
@@ -715,6 +805,9 @@ type ChiPodDistribution struct {
 	Scope       string `json:"scope,omitempty"       yaml:"scope,omitempty"`
 	Number      int    `json:"number,omitempty"      yaml:"number,omitempty"`
 	TopologyKey string `json:"topologyKey,omitempty" yaml:"topologyKey,omitempty"`
+	// WhenUnsatisfiable specifies how TopologySpread distribution treats pods not satisfying it.
+	// Either DoNotSchedule or ScheduleAnyway
+	WhenUnsatisfiable string `json:"whenUnsatisfiable,omitempty" yaml:"whenUnsatisfiable,omitempty"`
 }
 
 // ChiServiceTemplate defines CHI service template
@@ -51,535 +51,548 @@ func (in CHISelector) DeepCopy() CHISelector {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ChiCleanup) DeepCopyInto(out *ChiCleanup) {
+func (in *ChiApproval) DeepCopyInto(out *ChiApproval) {
 	*out = *in
-	if in.UnknownObjects != nil {
-		in, out := &in.UnknownObjects, &out.UnknownObjects
-		*out = new(ChiObjectsCleanup)
-		**out = **in
-	}
-	if in.ReconcileFailedObjects != nil {
-		in, out := &in.ReconcileFailedObjects, &out.ReconcileFailedObjects
-		*out = new(ChiObjectsCleanup)
-		**out = **in
+	if in.Actions != nil {
+		in, out := &in.Actions, &out.Actions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiCleanup.
-func (in *ChiCleanup) DeepCopy() *ChiCleanup {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiApproval.
+func (in *ChiApproval) DeepCopy() *ChiApproval {
 	if in == nil {
 		return nil
 	}
-	out := new(ChiCleanup)
+	out := new(ChiApproval)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ChiClusterAddress) DeepCopyInto(out *ChiClusterAddress) {
+func (in *ChiCertificateRotation) DeepCopyInto(out *ChiCertificateRotation) {
 	*out = *in
+	if in.Hosts != nil {
+		in, out := &in.Hosts, &out.Hosts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiClusterAddress.
-func (in *ChiClusterAddress) DeepCopy() *ChiClusterAddress {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiCertificateRotation.
+func (in *ChiCertificateRotation) DeepCopy() *ChiCertificateRotation {
 	if in == nil {
 		return nil
 	}
-	out := new(ChiClusterAddress)
+	out := new(ChiCertificateRotation)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ChiClusterLayout) DeepCopyInto(out *ChiClusterLayout) {
+func (in *ChiChproxy) DeepCopyInto(out *ChiChproxy) {
 	*out = *in
-	if in.Shards != nil {
-		in, out := &in.Shards, &out.Shards
-		*out = make([]ChiShard, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(StringBool)
+		**out = **in
 	}
 	if in.Replicas != nil {
 		in, out := &in.Replicas, &out.Replicas
-		*out = make([]ChiReplica, len(*in))
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = make([]ChiChproxyUser, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	if in.HostsField != nil {
-		in, out := &in.HostsField, &out.HostsField
-		*out = new(HostsField)
-		(*in).DeepCopyInto(*out)
+	if in.Caches != nil {
+		in, out := &in.Caches, &out.Caches
+		*out = make([]ChiChproxyCache, len(*in))
+		copy(*out, *in)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiClusterLayout.
-func (in *ChiClusterLayout) DeepCopy() *ChiClusterLayout {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiChproxy.
+func (in *ChiChproxy) DeepCopy() *ChiChproxy {
 	if in == nil {
 		return nil
 	}
-	out := new(ChiClusterLayout)
+	out := new(ChiChproxy)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ChiDefaults) DeepCopyInto(out *ChiDefaults) {
+func (in *ChiChproxyCache) DeepCopyInto(out *ChiChproxyCache) {
 	*out = *in
-	if in.ReplicasUseFQDN != nil {
-		in, out := &in.ReplicasUseFQDN, &out.ReplicasUseFQDN
-		*out = new(StringBool)
-		**out = **in
-	}
-	if in.DistributedDDL != nil {
-		in, out := &in.DistributedDDL, &out.DistributedDDL
-		*out = new(ChiDistributedDDL)
-		**out = **in
-	}
-	if in.StorageManagement != nil {
-		in, out := &in.StorageManagement, &out.StorageManagement
-		*out = new(StorageManagement)
-		**out = **in
-	}
-	if in.Templates != nil {
-		in, out := &in.Templates, &out.Templates
-		*out = new(ChiTemplateNames)
-		**out = **in
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiDefaults.
-func (in *ChiDefaults) DeepCopy() *ChiDefaults {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiChproxyCache.
+func (in *ChiChproxyCache) DeepCopy() *ChiChproxyCache {
 	if in == nil {
 		return nil
 	}
-	out := new(ChiDefaults)
+	out := new(ChiChproxyCache)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ChiDistributedDDL) DeepCopyInto(out *ChiDistributedDDL) {
+func (in *ChiChproxyUser) DeepCopyInto(out *ChiChproxyUser) {
 	*out = *in
+	if in.AllowedNetworks != nil {
+		in, out := &in.AllowedNetworks, &out.AllowedNetworks
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiDistributedDDL.
-func (in *ChiDistributedDDL) DeepCopy() *ChiDistributedDDL {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiChproxyUser.
+func (in *ChiChproxyUser) DeepCopy() *ChiChproxyUser {
 	if in == nil {
 		return nil
 	}
-	out := new(ChiDistributedDDL)
+	out := new(ChiChproxyUser)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ChiHost) DeepCopyInto(out *ChiHost) {
+func (in *ChiCleanup) DeepCopyInto(out *ChiCleanup) {
 	*out = *in
-	if in.Insecure != nil {
-		in, out := &in.Insecure, &out.Insecure
-		*out = new(StringBool)
-		**out = **in
-	}
-	if in.Secure != nil {
-		in, out := &in.Secure, &out.Secure
-		*out = new(StringBool)
+	if in.UnknownObjects != nil {
+		in, out := &in.UnknownObjects, &out.UnknownObjects
+		*out = new(ChiObjectsCleanup)
 		**out = **in
 	}
-	if in.Settings != nil {
-		in, out := &in.Settings, &out.Settings
-		*out = new(Settings)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Files != nil {
-		in, out := &in.Files, &out.Files
-		*out = new(Settings)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Templates != nil {
-		in, out := &in.Templates, &out.Templates
-		*out = new(ChiTemplateNames)
+	if in.ReconcileFailedObjects != nil {
+		in, out := &in.ReconcileFailedObjects, &out.ReconcileFailedObjects
+		*out = new(ChiObjectsCleanup)
 		**out = **in
 	}
-	in.Runtime.DeepCopyInto(&out.Runtime)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiHost.
-func (in *ChiHost) DeepCopy() *ChiHost {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiCleanup.
+func (in *ChiCleanup) DeepCopy() *ChiCleanup {
 	if in == nil {
 		return nil
 	}
-	out := new(ChiHost)
+	out := new(ChiCleanup)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ChiHostAddress) DeepCopyInto(out *ChiHostAddress) {
+func (in *ChiClusterAddress) DeepCopyInto(out *ChiClusterAddress) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiHostAddress.
-func (in *ChiHostAddress) DeepCopy() *ChiHostAddress {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiClusterAddress.
+func (in *ChiClusterAddress) DeepCopy() *ChiClusterAddress {
 	if in == nil {
 		return nil
 	}
-	out := new(ChiHostAddress)
+	out := new(ChiClusterAddress)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ChiHostConfig) DeepCopyInto(out *ChiHostConfig) {
+func (in *ChiClusterLayout) DeepCopyInto(out *ChiClusterLayout) {
 	*out = *in
+	if in.Shards != nil {
+		in, out := &in.Shards, &out.Shards
+		*out = make([]ChiShard, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = make([]ChiReplica, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ShardOverrides != nil {
+		in, out := &in.ShardOverrides, &out.ShardOverrides
+		*out = make([]ChiShardOverride, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.HostsField != nil {
+		in, out := &in.HostsField, &out.HostsField
+		*out = new(HostsField)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiHostConfig.
-func (in *ChiHostConfig) DeepCopy() *ChiHostConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiClusterLayout.
+func (in *ChiClusterLayout) DeepCopy() *ChiClusterLayout {
 	if in == nil {
 		return nil
 	}
-	out := new(ChiHostConfig)
+	out := new(ChiClusterLayout)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ChiHostReconcileAttributes) DeepCopyInto(out *ChiHostReconcileAttributes) {
+func (in *ChiClusterRebalancing) DeepCopyInto(out *ChiClusterRebalancing) {
 	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(StringBool)
+		**out = **in
+	}
+	if in.Tables != nil {
+		in, out := &in.Tables, &out.Tables
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Drain != nil {
+		in, out := &in.Drain, &out.Drain
+		*out = new(StringBool)
+		**out = **in
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiHostReconcileAttributes.
-func (in *ChiHostReconcileAttributes) DeepCopy() *ChiHostReconcileAttributes {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiClusterRebalancing.
+func (in *ChiClusterRebalancing) DeepCopy() *ChiClusterRebalancing {
 	if in == nil {
 		return nil
 	}
-	out := new(ChiHostReconcileAttributes)
+	out := new(ChiClusterRebalancing)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ChiHostReconcileAttributesCounters) DeepCopyInto(out *ChiHostReconcileAttributesCounters) {
+func (in *ChiClusterScheduledRestart) DeepCopyInto(out *ChiClusterScheduledRestart) {
 	*out = *in
-	if in.status != nil {
-		in, out := &in.status, &out.status
-		*out = make(map[ObjectStatus]int, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(StringBool)
+		**out = **in
+	}
+	if in.Window != nil {
+		in, out := &in.Window, &out.Window
+		*out = new(ChiMaintenanceWindow)
+		(*in).DeepCopyInto(*out)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiHostReconcileAttributesCounters.
-func (in *ChiHostReconcileAttributesCounters) DeepCopy() *ChiHostReconcileAttributesCounters {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiClusterScheduledRestart.
+func (in *ChiClusterScheduledRestart) DeepCopy() *ChiClusterScheduledRestart {
 	if in == nil {
 		return nil
 	}
-	out := new(ChiHostReconcileAttributesCounters)
+	out := new(ChiClusterScheduledRestart)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ChiHostRuntime) DeepCopyInto(out *ChiHostRuntime) {
+func (in *ChiClusterSpot) DeepCopyInto(out *ChiClusterSpot) {
 	*out = *in
-	out.Address = in.Address
-	out.Config = in.Config
-	if in.Version != nil {
-		in, out := &in.Version, &out.Version
-		*out = new(swversion.SoftWareVersion)
-		**out = **in
-	}
-	if in.reconcileAttributes != nil {
-		in, out := &in.reconcileAttributes, &out.reconcileAttributes
-		*out = new(ChiHostReconcileAttributes)
-		**out = **in
-	}
-	if in.CurStatefulSet != nil {
-		in, out := &in.CurStatefulSet, &out.CurStatefulSet
-		*out = new(appsv1.StatefulSet)
-		(*in).DeepCopyInto(*out)
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
 	}
-	if in.DesiredStatefulSet != nil {
-		in, out := &in.DesiredStatefulSet, &out.DesiredStatefulSet
-		*out = new(appsv1.StatefulSet)
-		(*in).DeepCopyInto(*out)
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
-	if in.CHI != nil {
-		in, out := &in.CHI, &out.CHI
-		*out = new(ClickHouseInstallation)
-		(*in).DeepCopyInto(*out)
+	if in.EvictionSeconds != nil {
+		in, out := &in.EvictionSeconds, &out.EvictionSeconds
+		*out = new(int64)
+		**out = **in
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiHostRuntime.
-func (in *ChiHostRuntime) DeepCopy() *ChiHostRuntime {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiClusterSpot.
+func (in *ChiClusterSpot) DeepCopy() *ChiClusterSpot {
 	if in == nil {
 		return nil
 	}
-	out := new(ChiHostRuntime)
+	out := new(ChiClusterSpot)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ChiHostTemplate) DeepCopyInto(out *ChiHostTemplate) {
+func (in *ChiClusterTLS) DeepCopyInto(out *ChiClusterTLS) {
 	*out = *in
-	if in.PortDistribution != nil {
-		in, out := &in.PortDistribution, &out.PortDistribution
-		*out = make([]ChiPortDistribution, len(*in))
-		copy(*out, *in)
+	if in.Managed != nil {
+		in, out := &in.Managed, &out.Managed
+		*out = new(StringBool)
+		**out = **in
 	}
-	in.Spec.DeepCopyInto(&out.Spec)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiHostTemplate.
-func (in *ChiHostTemplate) DeepCopy() *ChiHostTemplate {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiClusterTLS.
+func (in *ChiClusterTLS) DeepCopy() *ChiClusterTLS {
 	if in == nil {
 		return nil
 	}
-	out := new(ChiHostTemplate)
+	out := new(ChiClusterTLS)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ChiObjectsCleanup) DeepCopyInto(out *ChiObjectsCleanup) {
+func (in *ChiClusterTemplate) DeepCopyInto(out *ChiClusterTemplate) {
 	*out = *in
+	in.Spec.DeepCopyInto(&out.Spec)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiObjectsCleanup.
-func (in *ChiObjectsCleanup) DeepCopy() *ChiObjectsCleanup {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiClusterTemplate.
+func (in *ChiClusterTemplate) DeepCopy() *ChiClusterTemplate {
 	if in == nil {
 		return nil
 	}
-	out := new(ChiObjectsCleanup)
+	out := new(ChiClusterTemplate)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ChiPodDistribution) DeepCopyInto(out *ChiPodDistribution) {
+func (in *ChiCondition) DeepCopyInto(out *ChiCondition) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiPodDistribution.
-func (in *ChiPodDistribution) DeepCopy() *ChiPodDistribution {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiCondition.
+func (in *ChiCondition) DeepCopy() *ChiCondition {
 	if in == nil {
 		return nil
 	}
-	out := new(ChiPodDistribution)
+	out := new(ChiCondition)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ChiPodTemplate) DeepCopyInto(out *ChiPodTemplate) {
+func (in *ChiCoreDumpVolume) DeepCopyInto(out *ChiCoreDumpVolume) {
 	*out = *in
-	in.Zone.DeepCopyInto(&out.Zone)
-	if in.PodDistribution != nil {
-		in, out := &in.PodDistribution, &out.PodDistribution
-		*out = make([]ChiPodDistribution, len(*in))
-		copy(*out, *in)
-	}
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiPodTemplate.
-func (in *ChiPodTemplate) DeepCopy() *ChiPodTemplate {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiCoreDumpVolume.
+func (in *ChiCoreDumpVolume) DeepCopy() *ChiCoreDumpVolume {
 	if in == nil {
 		return nil
 	}
-	out := new(ChiPodTemplate)
+	out := new(ChiCoreDumpVolume)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ChiPodTemplateZone) DeepCopyInto(out *ChiPodTemplateZone) {
+func (in *ChiDebug) DeepCopyInto(out *ChiDebug) {
 	*out = *in
-	if in.Values != nil {
-		in, out := &in.Values, &out.Values
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	if in.CoreDump != nil {
+		in, out := &in.CoreDump, &out.CoreDump
+		*out = new(StringBool)
+		**out = **in
 	}
-	return
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiPodTemplateZone.
-func (in *ChiPodTemplateZone) DeepCopy() *ChiPodTemplateZone {
-	if in == nil {
-		return nil
+	if in.CoreDumpVolume != nil {
+		in, out := &in.CoreDumpVolume, &out.CoreDumpVolume
+		*out = new(ChiCoreDumpVolume)
+		**out = **in
+	}
+	if in.CoreDumpPattern != nil {
+		in, out := &in.CoreDumpPattern, &out.CoreDumpPattern
+		*out = new(StringBool)
+		**out = **in
+	}
+	if in.IntrospectionFunctions != nil {
+		in, out := &in.IntrospectionFunctions, &out.IntrospectionFunctions
+		*out = new(StringBool)
+		**out = **in
 	}
-	out := new(ChiPodTemplateZone)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ChiPortDistribution) DeepCopyInto(out *ChiPortDistribution) {
-	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiPortDistribution.
-func (in *ChiPortDistribution) DeepCopy() *ChiPortDistribution {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiDebug.
+func (in *ChiDebug) DeepCopy() *ChiDebug {
 	if in == nil {
 		return nil
 	}
-	out := new(ChiPortDistribution)
+	out := new(ChiDebug)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ChiReconciling) DeepCopyInto(out *ChiReconciling) {
+func (in *ChiDefaults) DeepCopyInto(out *ChiDefaults) {
 	*out = *in
-	if in.Cleanup != nil {
-		in, out := &in.Cleanup, &out.Cleanup
-		*out = new(ChiCleanup)
+	if in.ReplicasUseFQDN != nil {
+		in, out := &in.ReplicasUseFQDN, &out.ReplicasUseFQDN
+		*out = new(StringBool)
+		**out = **in
+	}
+	if in.DistributedDDL != nil {
+		in, out := &in.DistributedDDL, &out.DistributedDDL
+		*out = new(ChiDistributedDDL)
+		**out = **in
+	}
+	if in.StorageManagement != nil {
+		in, out := &in.StorageManagement, &out.StorageManagement
+		*out = new(StorageManagement)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Templates != nil {
+		in, out := &in.Templates, &out.Templates
+		*out = new(ChiTemplateNames)
+		**out = **in
+	}
+	if in.AutoTuning != nil {
+		in, out := &in.AutoTuning, &out.AutoTuning
+		*out = new(StringBool)
+		**out = **in
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ManagedProfiles != nil {
+		in, out := &in.ManagedProfiles, &out.ManagedProfiles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.HostNetwork != nil {
+		in, out := &in.HostNetwork, &out.HostNetwork
+		*out = new(StringBool)
+		**out = **in
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiReconciling.
-func (in *ChiReconciling) DeepCopy() *ChiReconciling {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiDefaults.
+func (in *ChiDefaults) DeepCopy() *ChiDefaults {
 	if in == nil {
 		return nil
 	}
-	out := new(ChiReconciling)
+	out := new(ChiDefaults)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ChiReplica) DeepCopyInto(out *ChiReplica) {
+func (in *ChiDistributedDDL) DeepCopyInto(out *ChiDistributedDDL) {
 	*out = *in
-	if in.Settings != nil {
-		in, out := &in.Settings, &out.Settings
-		*out = new(Settings)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Files != nil {
-		in, out := &in.Files, &out.Files
-		*out = new(Settings)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Templates != nil {
-		in, out := &in.Templates, &out.Templates
-		*out = new(ChiTemplateNames)
-		**out = **in
-	}
-	if in.Hosts != nil {
-		in, out := &in.Hosts, &out.Hosts
-		*out = make([]*ChiHost, len(*in))
-		for i := range *in {
-			if (*in)[i] != nil {
-				in, out := &(*in)[i], &(*out)[i]
-				*out = new(ChiHost)
-				(*in).DeepCopyInto(*out)
-			}
-		}
-	}
-	in.Runtime.DeepCopyInto(&out.Runtime)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiReplica.
-func (in *ChiReplica) DeepCopy() *ChiReplica {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiDistributedDDL.
+func (in *ChiDistributedDDL) DeepCopy() *ChiDistributedDDL {
 	if in == nil {
 		return nil
 	}
-	out := new(ChiReplica)
+	out := new(ChiDistributedDDL)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ChiReplicaAddress) DeepCopyInto(out *ChiReplicaAddress) {
+func (in *ChiFootprint) DeepCopyInto(out *ChiFootprint) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiReplicaAddress.
-func (in *ChiReplicaAddress) DeepCopy() *ChiReplicaAddress {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiFootprint.
+func (in *ChiFootprint) DeepCopy() *ChiFootprint {
 	if in == nil {
 		return nil
 	}
-	out := new(ChiReplicaAddress)
+	out := new(ChiFootprint)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ChiReplicaRuntime) DeepCopyInto(out *ChiReplicaRuntime) {
+func (in *ChiFork) DeepCopyInto(out *ChiFork) {
 	*out = *in
-	out.Address = in.Address
-	if in.CHI != nil {
-		in, out := &in.CHI, &out.CHI
-		*out = new(ClickHouseInstallation)
-		(*in).DeepCopyInto(*out)
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiReplicaRuntime.
-func (in *ChiReplicaRuntime) DeepCopy() *ChiReplicaRuntime {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiFork.
+func (in *ChiFork) DeepCopy() *ChiFork {
 	if in == nil {
 		return nil
 	}
-	out := new(ChiReplicaRuntime)
+	out := new(ChiFork)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ChiServiceTemplate) DeepCopyInto(out *ChiServiceTemplate) {
+func (in *ChiHistoryEntry) DeepCopyInto(out *ChiHistoryEntry) {
 	*out = *in
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiServiceTemplate.
-func (in *ChiServiceTemplate) DeepCopy() *ChiServiceTemplate {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiHistoryEntry.
+func (in *ChiHistoryEntry) DeepCopy() *ChiHistoryEntry {
 	if in == nil {
 		return nil
 	}
-	out := new(ChiServiceTemplate)
+	out := new(ChiHistoryEntry)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ChiShard) DeepCopyInto(out *ChiShard) {
+func (in *ChiHost) DeepCopyInto(out *ChiHost) {
 	*out = *in
-	if in.Weight != nil {
-		in, out := &in.Weight, &out.Weight
-		*out = new(int)
+	if in.Insecure != nil {
+		in, out := &in.Insecure, &out.Insecure
+		*out = new(StringBool)
 		**out = **in
 	}
-	if in.InternalReplication != nil {
-		in, out := &in.InternalReplication, &out.InternalReplication
+	if in.Secure != nil {
+		in, out := &in.Secure, &out.Secure
 		*out = new(StringBool)
 		**out = **in
 	}
@@ -593,448 +606,423 @@ func (in *ChiShard) DeepCopyInto(out *ChiShard) {
 		*out = new(Settings)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Macros != nil {
+		in, out := &in.Macros, &out.Macros
+		*out = new(Settings)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Templates != nil {
 		in, out := &in.Templates, &out.Templates
 		*out = new(ChiTemplateNames)
 		**out = **in
 	}
-	if in.Hosts != nil {
-		in, out := &in.Hosts, &out.Hosts
-		*out = make([]*ChiHost, len(*in))
-		for i := range *in {
-			if (*in)[i] != nil {
-				in, out := &(*in)[i], &(*out)[i]
-				*out = new(ChiHost)
-				(*in).DeepCopyInto(*out)
-			}
+	if in.Zone != nil {
+		in, out := &in.Zone, &out.Zone
+		*out = new(ChiPodTemplateZone)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Priority != nil {
+		in, out := &in.Priority, &out.Priority
+		*out = new(int)
+		**out = **in
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
 		}
 	}
 	in.Runtime.DeepCopyInto(&out.Runtime)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiShard.
-func (in *ChiShard) DeepCopy() *ChiShard {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiHost.
+func (in *ChiHost) DeepCopy() *ChiHost {
 	if in == nil {
 		return nil
 	}
-	out := new(ChiShard)
+	out := new(ChiHost)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ChiShardAddress) DeepCopyInto(out *ChiShardAddress) {
+func (in *ChiHostAddress) DeepCopyInto(out *ChiHostAddress) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiShardAddress.
-func (in *ChiShardAddress) DeepCopy() *ChiShardAddress {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiHostAddress.
+func (in *ChiHostAddress) DeepCopy() *ChiHostAddress {
 	if in == nil {
 		return nil
 	}
-	out := new(ChiShardAddress)
+	out := new(ChiHostAddress)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ChiShardRuntime) DeepCopyInto(out *ChiShardRuntime) {
+func (in *ChiHostConfig) DeepCopyInto(out *ChiHostConfig) {
 	*out = *in
-	out.Address = in.Address
-	if in.CHI != nil {
-		in, out := &in.CHI, &out.CHI
-		*out = new(ClickHouseInstallation)
-		(*in).DeepCopyInto(*out)
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiShardRuntime.
-func (in *ChiShardRuntime) DeepCopy() *ChiShardRuntime {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiHostConfig.
+func (in *ChiHostConfig) DeepCopy() *ChiHostConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(ChiShardRuntime)
+	out := new(ChiHostConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ChiSpec) DeepCopyInto(out *ChiSpec) {
+func (in *ChiHostHealth) DeepCopyInto(out *ChiHostHealth) {
 	*out = *in
-	if in.TaskID != nil {
-		in, out := &in.TaskID, &out.TaskID
-		*out = new(string)
-		**out = **in
-	}
-	if in.Stop != nil {
-		in, out := &in.Stop, &out.Stop
-		*out = new(StringBool)
-		**out = **in
-	}
-	if in.Troubleshoot != nil {
-		in, out := &in.Troubleshoot, &out.Troubleshoot
-		*out = new(StringBool)
-		**out = **in
-	}
-	if in.Templating != nil {
-		in, out := &in.Templating, &out.Templating
-		*out = new(ChiTemplating)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Reconciling != nil {
-		in, out := &in.Reconciling, &out.Reconciling
-		*out = new(ChiReconciling)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Defaults != nil {
-		in, out := &in.Defaults, &out.Defaults
-		*out = new(ChiDefaults)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Configuration != nil {
-		in, out := &in.Configuration, &out.Configuration
-		*out = new(Configuration)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Templates != nil {
-		in, out := &in.Templates, &out.Templates
-		*out = new(ChiTemplates)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.UseTemplates != nil {
-		in, out := &in.UseTemplates, &out.UseTemplates
-		*out = make([]*ChiTemplateRef, len(*in))
-		for i := range *in {
-			if (*in)[i] != nil {
-				in, out := &(*in)[i], &(*out)[i]
-				*out = new(ChiTemplateRef)
-				**out = **in
-			}
-		}
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiSpec.
-func (in *ChiSpec) DeepCopy() *ChiSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiHostHealth.
+func (in *ChiHostHealth) DeepCopy() *ChiHostHealth {
 	if in == nil {
 		return nil
 	}
-	out := new(ChiSpec)
+	out := new(ChiHostHealth)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ChiStatus) DeepCopyInto(out *ChiStatus) {
+func (in *ChiHostReconcileAttributes) DeepCopyInto(out *ChiHostReconcileAttributes) {
 	*out = *in
-	if in.TaskIDsStarted != nil {
-		in, out := &in.TaskIDsStarted, &out.TaskIDsStarted
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	if in.TaskIDsCompleted != nil {
-		in, out := &in.TaskIDsCompleted, &out.TaskIDsCompleted
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiHostReconcileAttributes.
+func (in *ChiHostReconcileAttributes) DeepCopy() *ChiHostReconcileAttributes {
+	if in == nil {
+		return nil
 	}
-	if in.Actions != nil {
-		in, out := &in.Actions, &out.Actions
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	out := new(ChiHostReconcileAttributes)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChiHostReconcileAttributesCounters) DeepCopyInto(out *ChiHostReconcileAttributesCounters) {
+	*out = *in
+	if in.status != nil {
+		in, out := &in.status, &out.status
+		*out = make(map[ObjectStatus]int, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
 	}
-	if in.Errors != nil {
-		in, out := &in.Errors, &out.Errors
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiHostReconcileAttributesCounters.
+func (in *ChiHostReconcileAttributesCounters) DeepCopy() *ChiHostReconcileAttributesCounters {
+	if in == nil {
+		return nil
 	}
-	if in.Pods != nil {
-		in, out := &in.Pods, &out.Pods
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	out := new(ChiHostReconcileAttributesCounters)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChiHostRuntime) DeepCopyInto(out *ChiHostRuntime) {
+	*out = *in
+	out.Address = in.Address
+	out.Config = in.Config
+	if in.Version != nil {
+		in, out := &in.Version, &out.Version
+		*out = new(swversion.SoftWareVersion)
+		**out = **in
 	}
-	if in.PodIPs != nil {
-		in, out := &in.PodIPs, &out.PodIPs
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	if in.reconcileAttributes != nil {
+		in, out := &in.reconcileAttributes, &out.reconcileAttributes
+		*out = new(ChiHostReconcileAttributes)
+		**out = **in
 	}
-	if in.FQDNs != nil {
-		in, out := &in.FQDNs, &out.FQDNs
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	if in.CurStatefulSet != nil {
+		in, out := &in.CurStatefulSet, &out.CurStatefulSet
+		*out = new(appsv1.StatefulSet)
+		(*in).DeepCopyInto(*out)
 	}
-	if in.NormalizedCHI != nil {
-		in, out := &in.NormalizedCHI, &out.NormalizedCHI
-		*out = new(ClickHouseInstallation)
+	if in.DesiredStatefulSet != nil {
+		in, out := &in.DesiredStatefulSet, &out.DesiredStatefulSet
+		*out = new(appsv1.StatefulSet)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.NormalizedCHICompleted != nil {
-		in, out := &in.NormalizedCHICompleted, &out.NormalizedCHICompleted
+	if in.CHI != nil {
+		in, out := &in.CHI, &out.CHI
 		*out = new(ClickHouseInstallation)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.HostsWithTablesCreated != nil {
-		in, out := &in.HostsWithTablesCreated, &out.HostsWithTablesCreated
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	if in.UsedTemplates != nil {
-		in, out := &in.UsedTemplates, &out.UsedTemplates
-		*out = make([]*ChiTemplateRef, len(*in))
-		for i := range *in {
-			if (*in)[i] != nil {
-				in, out := &(*in)[i], &(*out)[i]
-				*out = new(ChiTemplateRef)
-				**out = **in
-			}
-		}
-	}
-	out.mu = in.mu
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiStatus.
-func (in *ChiStatus) DeepCopy() *ChiStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiHostRuntime.
+func (in *ChiHostRuntime) DeepCopy() *ChiHostRuntime {
 	if in == nil {
 		return nil
 	}
-	out := new(ChiStatus)
+	out := new(ChiHostRuntime)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ChiTemplateNames) DeepCopyInto(out *ChiTemplateNames) {
+func (in *ChiHostTemplate) DeepCopyInto(out *ChiHostTemplate) {
 	*out = *in
+	if in.PortDistribution != nil {
+		in, out := &in.PortDistribution, &out.PortDistribution
+		*out = make([]ChiPortDistribution, len(*in))
+		copy(*out, *in)
+	}
+	in.Spec.DeepCopyInto(&out.Spec)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiTemplateNames.
-func (in *ChiTemplateNames) DeepCopy() *ChiTemplateNames {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiHostTemplate.
+func (in *ChiHostTemplate) DeepCopy() *ChiHostTemplate {
 	if in == nil {
 		return nil
 	}
-	out := new(ChiTemplateNames)
+	out := new(ChiHostTemplate)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ChiTemplateRef) DeepCopyInto(out *ChiTemplateRef) {
+func (in *ChiLogging) DeepCopyInto(out *ChiLogging) {
 	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(StringBool)
+		**out = **in
+	}
+	if in.Structured != nil {
+		in, out := &in.Structured, &out.Structured
+		*out = new(StringBool)
+		**out = **in
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiTemplateRef.
-func (in *ChiTemplateRef) DeepCopy() *ChiTemplateRef {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiLogging.
+func (in *ChiLogging) DeepCopy() *ChiLogging {
 	if in == nil {
 		return nil
 	}
-	out := new(ChiTemplateRef)
+	out := new(ChiLogging)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ChiTemplates) DeepCopyInto(out *ChiTemplates) {
+func (in *ChiLogicalCluster) DeepCopyInto(out *ChiLogicalCluster) {
 	*out = *in
-	if in.HostTemplates != nil {
-		in, out := &in.HostTemplates, &out.HostTemplates
-		*out = make([]ChiHostTemplate, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.PodTemplates != nil {
-		in, out := &in.PodTemplates, &out.PodTemplates
-		*out = make([]ChiPodTemplate, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.VolumeClaimTemplates != nil {
-		in, out := &in.VolumeClaimTemplates, &out.VolumeClaimTemplates
-		*out = make([]ChiVolumeClaimTemplate, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.ServiceTemplates != nil {
-		in, out := &in.ServiceTemplates, &out.ServiceTemplates
-		*out = make([]ChiServiceTemplate, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.HostTemplatesIndex != nil {
-		in, out := &in.HostTemplatesIndex, &out.HostTemplatesIndex
-		*out = new(HostTemplatesIndex)
-		(*in).DeepCopyInto(*out)
+	if in.Clusters != nil {
+		in, out := &in.Clusters, &out.Clusters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.PodTemplatesIndex != nil {
-		in, out := &in.PodTemplatesIndex, &out.PodTemplatesIndex
-		*out = new(PodTemplatesIndex)
-		(*in).DeepCopyInto(*out)
+	if in.Shards != nil {
+		in, out := &in.Shards, &out.Shards
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.VolumeClaimTemplatesIndex != nil {
-		in, out := &in.VolumeClaimTemplatesIndex, &out.VolumeClaimTemplatesIndex
-		*out = new(VolumeClaimTemplatesIndex)
-		(*in).DeepCopyInto(*out)
+	if in.Hosts != nil {
+		in, out := &in.Hosts, &out.Hosts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.ServiceTemplatesIndex != nil {
-		in, out := &in.ServiceTemplatesIndex, &out.ServiceTemplatesIndex
-		*out = new(ServiceTemplatesIndex)
-		(*in).DeepCopyInto(*out)
+	if in.InternalReplication != nil {
+		in, out := &in.InternalReplication, &out.InternalReplication
+		*out = new(StringBool)
+		**out = **in
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiTemplates.
-func (in *ChiTemplates) DeepCopy() *ChiTemplates {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiLogicalCluster.
+func (in *ChiLogicalCluster) DeepCopy() *ChiLogicalCluster {
 	if in == nil {
 		return nil
 	}
-	out := new(ChiTemplates)
+	out := new(ChiLogicalCluster)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ChiTemplating) DeepCopyInto(out *ChiTemplating) {
+func (in *ChiMaintenanceWindow) DeepCopyInto(out *ChiMaintenanceWindow) {
 	*out = *in
-	if in.CHISelector != nil {
-		in, out := &in.CHISelector, &out.CHISelector
-		*out = make(CHISelector, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	if in.Days != nil {
+		in, out := &in.Days, &out.Days
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiTemplating.
-func (in *ChiTemplating) DeepCopy() *ChiTemplating {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiMaintenanceWindow.
+func (in *ChiMaintenanceWindow) DeepCopy() *ChiMaintenanceWindow {
 	if in == nil {
 		return nil
 	}
-	out := new(ChiTemplating)
+	out := new(ChiMaintenanceWindow)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ChiVolumeClaimTemplate) DeepCopyInto(out *ChiVolumeClaimTemplate) {
+func (in *ChiObjectsCleanup) DeepCopyInto(out *ChiObjectsCleanup) {
 	*out = *in
-	out.StorageManagement = in.StorageManagement
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiVolumeClaimTemplate.
-func (in *ChiVolumeClaimTemplate) DeepCopy() *ChiVolumeClaimTemplate {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiObjectsCleanup.
+func (in *ChiObjectsCleanup) DeepCopy() *ChiObjectsCleanup {
 	if in == nil {
 		return nil
 	}
-	out := new(ChiVolumeClaimTemplate)
+	out := new(ChiObjectsCleanup)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ChiZookeeperConfig) DeepCopyInto(out *ChiZookeeperConfig) {
+func (in *ChiObservation) DeepCopyInto(out *ChiObservation) {
 	*out = *in
-	if in.Nodes != nil {
-		in, out := &in.Nodes, &out.Nodes
-		*out = make([]ChiZookeeperNode, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.Missing != nil {
+		in, out := &in.Missing, &out.Missing
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Drifted != nil {
+		in, out := &in.Drifted, &out.Drifted
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiZookeeperConfig.
-func (in *ChiZookeeperConfig) DeepCopy() *ChiZookeeperConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiObservation.
+func (in *ChiObservation) DeepCopy() *ChiObservation {
 	if in == nil {
 		return nil
 	}
-	out := new(ChiZookeeperConfig)
+	out := new(ChiObservation)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ChiZookeeperNode) DeepCopyInto(out *ChiZookeeperNode) {
+func (in *ChiPodDistribution) DeepCopyInto(out *ChiPodDistribution) {
 	*out = *in
-	if in.Secure != nil {
-		in, out := &in.Secure, &out.Secure
-		*out = new(StringBool)
-		**out = **in
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiZookeeperNode.
-func (in *ChiZookeeperNode) DeepCopy() *ChiZookeeperNode {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiPodDistribution.
+func (in *ChiPodDistribution) DeepCopy() *ChiPodDistribution {
 	if in == nil {
 		return nil
 	}
-	out := new(ChiZookeeperNode)
+	out := new(ChiPodDistribution)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClickHouseInstallation) DeepCopyInto(out *ClickHouseInstallation) {
+func (in *ChiPodTemplate) DeepCopyInto(out *ChiPodTemplate) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	if in.Status != nil {
-		in, out := &in.Status, &out.Status
-		*out = new(ChiStatus)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.runtime != nil {
-		in, out := &in.runtime, &out.runtime
-		*out = new(ClickHouseInstallationRuntime)
+	in.Zone.DeepCopyInto(&out.Zone)
+	if in.PodDistribution != nil {
+		in, out := &in.PodDistribution, &out.PodDistribution
+		*out = make([]ChiPodDistribution, len(*in))
+		copy(*out, *in)
+	}
+	if in.Container != nil {
+		in, out := &in.Container, &out.Container
+		*out = new(ChiPodTemplateContainer)
 		(*in).DeepCopyInto(*out)
 	}
-	out.statusCreatorMutex = in.statusCreatorMutex
-	out.runtimeCreatorMutex = in.runtimeCreatorMutex
+	if in.Sidecars != nil {
+		in, out := &in.Sidecars, &out.Sidecars
+		*out = make([]corev1.Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.InitContainers != nil {
+		in, out := &in.InitContainers, &out.InitContainers
+		*out = make([]corev1.Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClickHouseInstallation.
-func (in *ClickHouseInstallation) DeepCopy() *ClickHouseInstallation {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiPodTemplate.
+func (in *ChiPodTemplate) DeepCopy() *ChiPodTemplate {
 	if in == nil {
 		return nil
 	}
-	out := new(ClickHouseInstallation)
+	out := new(ChiPodTemplate)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ClickHouseInstallation) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClickHouseInstallationList) DeepCopyInto(out *ClickHouseInstallationList) {
+func (in *ChiPodTemplateContainer) DeepCopyInto(out *ChiPodTemplateContainer) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]ClickHouseInstallation, len(*in))
+	if in.Images != nil {
+		in, out := &in.Images, &out.Images
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Command != nil {
+		in, out := &in.Command, &out.Command
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Args != nil {
+		in, out := &in.Args, &out.Args
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExtraVolumes != nil {
+		in, out := &in.ExtraVolumes, &out.ExtraVolumes
+		*out = make([]corev1.Volume, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ExtraVolumeMounts != nil {
+		in, out := &in.ExtraVolumeMounts, &out.ExtraVolumeMounts
+		*out = make([]corev1.VolumeMount, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -1042,208 +1030,167 @@ func (in *ClickHouseInstallationList) DeepCopyInto(out *ClickHouseInstallationLi
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClickHouseInstallationList.
-func (in *ClickHouseInstallationList) DeepCopy() *ClickHouseInstallationList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiPodTemplateContainer.
+func (in *ChiPodTemplateContainer) DeepCopy() *ChiPodTemplateContainer {
 	if in == nil {
 		return nil
 	}
-	out := new(ClickHouseInstallationList)
+	out := new(ChiPodTemplateContainer)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ClickHouseInstallationList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClickHouseInstallationRuntime) DeepCopyInto(out *ClickHouseInstallationRuntime) {
+func (in *ChiPodTemplateZone) DeepCopyInto(out *ChiPodTemplateZone) {
 	*out = *in
-	if in.attributes != nil {
-		in, out := &in.attributes, &out.attributes
-		*out = new(ComparableAttributes)
-		(*in).DeepCopyInto(*out)
+	if in.Values != nil {
+		in, out := &in.Values, &out.Values
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.MatchExpressions != nil {
+		in, out := &in.MatchExpressions, &out.MatchExpressions
+		*out = make([]corev1.NodeSelectorRequirement, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClickHouseInstallationRuntime.
-func (in *ClickHouseInstallationRuntime) DeepCopy() *ClickHouseInstallationRuntime {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiPodTemplateZone.
+func (in *ChiPodTemplateZone) DeepCopy() *ChiPodTemplateZone {
 	if in == nil {
 		return nil
 	}
-	out := new(ClickHouseInstallationRuntime)
+	out := new(ChiPodTemplateZone)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClickHouseInstallationTemplate) DeepCopyInto(out *ClickHouseInstallationTemplate) {
+func (in *ChiPortAllocation) DeepCopyInto(out *ChiPortAllocation) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	if in.Status != nil {
-		in, out := &in.Status, &out.Status
-		*out = new(ChiStatus)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.runtime != nil {
-		in, out := &in.runtime, &out.runtime
-		*out = new(ClickHouseInstallationRuntime)
-		(*in).DeepCopyInto(*out)
+	if in.Ports != nil {
+		in, out := &in.Ports, &out.Ports
+		*out = make([]int32, len(*in))
+		copy(*out, *in)
 	}
-	out.statusCreatorMutex = in.statusCreatorMutex
-	out.runtimeCreatorMutex = in.runtimeCreatorMutex
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClickHouseInstallationTemplate.
-func (in *ClickHouseInstallationTemplate) DeepCopy() *ClickHouseInstallationTemplate {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiPortAllocation.
+func (in *ChiPortAllocation) DeepCopy() *ChiPortAllocation {
 	if in == nil {
 		return nil
 	}
-	out := new(ClickHouseInstallationTemplate)
+	out := new(ChiPortAllocation)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ClickHouseInstallationTemplate) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClickHouseInstallationTemplateList) DeepCopyInto(out *ClickHouseInstallationTemplateList) {
+func (in *ChiPortDistribution) DeepCopyInto(out *ChiPortDistribution) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]ClickHouseInstallationTemplate, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClickHouseInstallationTemplateList.
-func (in *ClickHouseInstallationTemplateList) DeepCopy() *ClickHouseInstallationTemplateList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiPortDistribution.
+func (in *ChiPortDistribution) DeepCopy() *ChiPortDistribution {
 	if in == nil {
 		return nil
 	}
-	out := new(ClickHouseInstallationTemplateList)
+	out := new(ChiPortDistribution)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ClickHouseInstallationTemplateList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClickHouseOperatorConfiguration) DeepCopyInto(out *ClickHouseOperatorConfiguration) {
+func (in *ChiQueries) DeepCopyInto(out *ChiQueries) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClickHouseOperatorConfiguration.
-func (in *ClickHouseOperatorConfiguration) DeepCopy() *ClickHouseOperatorConfiguration {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiQueries.
+func (in *ChiQueries) DeepCopy() *ChiQueries {
 	if in == nil {
 		return nil
 	}
-	out := new(ClickHouseOperatorConfiguration)
+	out := new(ChiQueries)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ClickHouseOperatorConfiguration) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClickHouseOperatorConfigurationList) DeepCopyInto(out *ClickHouseOperatorConfigurationList) {
+func (in *ChiRebalancingStatus) DeepCopyInto(out *ChiRebalancingStatus) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]ClickHouseOperatorConfiguration, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.Shards != nil {
+		in, out := &in.Shards, &out.Shards
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClickHouseOperatorConfigurationList.
-func (in *ClickHouseOperatorConfigurationList) DeepCopy() *ClickHouseOperatorConfigurationList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiRebalancingStatus.
+func (in *ChiRebalancingStatus) DeepCopy() *ChiRebalancingStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ClickHouseOperatorConfigurationList)
+	out := new(ChiRebalancingStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ClickHouseOperatorConfigurationList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Cluster) DeepCopyInto(out *Cluster) {
+func (in *ChiReconciling) DeepCopyInto(out *ChiReconciling) {
 	*out = *in
-	if in.Zookeeper != nil {
-		in, out := &in.Zookeeper, &out.Zookeeper
-		*out = new(ChiZookeeperConfig)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Settings != nil {
-		in, out := &in.Settings, &out.Settings
-		*out = new(Settings)
+	if in.Cleanup != nil {
+		in, out := &in.Cleanup, &out.Cleanup
+		*out = new(ChiCleanup)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.Files != nil {
-		in, out := &in.Files, &out.Files
-		*out = new(Settings)
+	if in.SmokeTest != nil {
+		in, out := &in.SmokeTest, &out.SmokeTest
+		*out = new(ChiSmokeTest)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.Templates != nil {
-		in, out := &in.Templates, &out.Templates
-		*out = new(ChiTemplateNames)
+	if in.Queries != nil {
+		in, out := &in.Queries, &out.Queries
+		*out = new(ChiQueries)
 		**out = **in
 	}
-	if in.SchemaPolicy != nil {
-		in, out := &in.SchemaPolicy, &out.SchemaPolicy
-		*out = new(SchemaPolicy)
-		**out = **in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiReconciling.
+func (in *ChiReconciling) DeepCopy() *ChiReconciling {
+	if in == nil {
+		return nil
 	}
-	if in.Insecure != nil {
-		in, out := &in.Insecure, &out.Insecure
-		*out = new(StringBool)
+	out := new(ChiReconciling)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChiRemoteReplica) DeepCopyInto(out *ChiRemoteReplica) {
+	*out = *in
+	if in.Hosts != nil {
+		in, out := &in.Hosts, &out.Hosts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.CHI != nil {
+		in, out := &in.CHI, &out.CHI
+		*out = new(ChiRemoteReplicaCHI)
 		**out = **in
 	}
 	if in.Secure != nil {
@@ -1251,754 +1198,2286 @@ func (in *Cluster) DeepCopyInto(out *Cluster) {
 		*out = new(StringBool)
 		**out = **in
 	}
-	if in.Secret != nil {
-		in, out := &in.Secret, &out.Secret
-		*out = new(ClusterSecret)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Layout != nil {
-		in, out := &in.Layout, &out.Layout
-		*out = new(ChiClusterLayout)
-		(*in).DeepCopyInto(*out)
+	if in.Compression != nil {
+		in, out := &in.Compression, &out.Compression
+		*out = new(StringBool)
+		**out = **in
 	}
-	in.Runtime.DeepCopyInto(&out.Runtime)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Cluster.
-func (in *Cluster) DeepCopy() *Cluster {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiRemoteReplica.
+func (in *ChiRemoteReplica) DeepCopy() *ChiRemoteReplica {
 	if in == nil {
 		return nil
 	}
-	out := new(Cluster)
+	out := new(ChiRemoteReplica)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterRuntime) DeepCopyInto(out *ClusterRuntime) {
+func (in *ChiRemoteReplicaCHI) DeepCopyInto(out *ChiRemoteReplicaCHI) {
 	*out = *in
-	out.Address = in.Address
-	if in.CHI != nil {
-		in, out := &in.CHI, &out.CHI
-		*out = new(ClickHouseInstallation)
-		(*in).DeepCopyInto(*out)
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterRuntime.
-func (in *ClusterRuntime) DeepCopy() *ClusterRuntime {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiRemoteReplicaCHI.
+func (in *ChiRemoteReplicaCHI) DeepCopy() *ChiRemoteReplicaCHI {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterRuntime)
+	out := new(ChiRemoteReplicaCHI)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterSecret) DeepCopyInto(out *ClusterSecret) {
+func (in *ChiReplica) DeepCopyInto(out *ChiReplica) {
 	*out = *in
-	if in.Auto != nil {
-		in, out := &in.Auto, &out.Auto
-		*out = new(StringBool)
+	if in.Settings != nil {
+		in, out := &in.Settings, &out.Settings
+		*out = new(Settings)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Files != nil {
+		in, out := &in.Files, &out.Files
+		*out = new(Settings)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Macros != nil {
+		in, out := &in.Macros, &out.Macros
+		*out = new(Settings)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Templates != nil {
+		in, out := &in.Templates, &out.Templates
+		*out = new(ChiTemplateNames)
 		**out = **in
 	}
-	if in.ValueFrom != nil {
-		in, out := &in.ValueFrom, &out.ValueFrom
-		*out = new(DataSource)
+	out.HostPorts = in.HostPorts
+	if in.Priority != nil {
+		in, out := &in.Priority, &out.Priority
+		*out = new(int)
+		**out = **in
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Hosts != nil {
+		in, out := &in.Hosts, &out.Hosts
+		*out = make([]*ChiHost, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(ChiHost)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	in.Runtime.DeepCopyInto(&out.Runtime)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSecret.
-func (in *ClusterSecret) DeepCopy() *ClusterSecret {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiReplica.
+func (in *ChiReplica) DeepCopy() *ChiReplica {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterSecret)
+	out := new(ChiReplica)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ComparableAttributes) DeepCopyInto(out *ComparableAttributes) {
+func (in *ChiReplicaAddress) DeepCopyInto(out *ChiReplicaAddress) {
 	*out = *in
-	if in.AdditionalEnvVars != nil {
-		in, out := &in.AdditionalEnvVars, &out.AdditionalEnvVars
-		*out = make([]corev1.EnvVar, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiReplicaAddress.
+func (in *ChiReplicaAddress) DeepCopy() *ChiReplicaAddress {
+	if in == nil {
+		return nil
 	}
-	if in.AdditionalVolumes != nil {
-		in, out := &in.AdditionalVolumes, &out.AdditionalVolumes
-		*out = make([]corev1.Volume, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	out := new(ChiReplicaAddress)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChiReplicaRuntime) DeepCopyInto(out *ChiReplicaRuntime) {
+	*out = *in
+	out.Address = in.Address
+	if in.CHI != nil {
+		in, out := &in.CHI, &out.CHI
+		*out = new(ClickHouseInstallation)
+		(*in).DeepCopyInto(*out)
 	}
-	if in.AdditionalVolumeMounts != nil {
-		in, out := &in.AdditionalVolumeMounts, &out.AdditionalVolumeMounts
-		*out = make([]corev1.VolumeMount, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiReplicaRuntime.
+func (in *ChiReplicaRuntime) DeepCopy() *ChiReplicaRuntime {
+	if in == nil {
+		return nil
 	}
+	out := new(ChiReplicaRuntime)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChiReplicatedDatabase) DeepCopyInto(out *ChiReplicatedDatabase) {
+	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComparableAttributes.
-func (in *ComparableAttributes) DeepCopy() *ComparableAttributes {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiReplicatedDatabase.
+func (in *ChiReplicatedDatabase) DeepCopy() *ChiReplicatedDatabase {
 	if in == nil {
 		return nil
 	}
-	out := new(ComparableAttributes)
+	out := new(ChiReplicatedDatabase)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ConfigCRSource) DeepCopyInto(out *ConfigCRSource) {
+func (in *ChiServiceTemplate) DeepCopyInto(out *ChiServiceTemplate) {
 	*out = *in
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigCRSource.
-func (in *ConfigCRSource) DeepCopy() *ConfigCRSource {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiServiceTemplate.
+func (in *ChiServiceTemplate) DeepCopy() *ChiServiceTemplate {
 	if in == nil {
 		return nil
 	}
-	out := new(ConfigCRSource)
+	out := new(ChiServiceTemplate)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Configuration) DeepCopyInto(out *Configuration) {
+func (in *ChiShard) DeepCopyInto(out *ChiShard) {
 	*out = *in
-	if in.Zookeeper != nil {
-		in, out := &in.Zookeeper, &out.Zookeeper
-		*out = new(ChiZookeeperConfig)
-		(*in).DeepCopyInto(*out)
+	if in.Weight != nil {
+		in, out := &in.Weight, &out.Weight
+		*out = new(int)
+		**out = **in
 	}
-	if in.Users != nil {
-		in, out := &in.Users, &out.Users
+	if in.InternalReplication != nil {
+		in, out := &in.InternalReplication, &out.InternalReplication
+		*out = new(StringBool)
+		**out = **in
+	}
+	if in.Settings != nil {
+		in, out := &in.Settings, &out.Settings
 		*out = new(Settings)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.Profiles != nil {
-		in, out := &in.Profiles, &out.Profiles
+	if in.Files != nil {
+		in, out := &in.Files, &out.Files
 		*out = new(Settings)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.Quotas != nil {
-		in, out := &in.Quotas, &out.Quotas
+	if in.Macros != nil {
+		in, out := &in.Macros, &out.Macros
 		*out = new(Settings)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.Settings != nil {
-		in, out := &in.Settings, &out.Settings
-		*out = new(Settings)
+	if in.Templates != nil {
+		in, out := &in.Templates, &out.Templates
+		*out = new(ChiTemplateNames)
+		**out = **in
+	}
+	out.HostPorts = in.HostPorts
+	if in.Zones != nil {
+		in, out := &in.Zones, &out.Zones
+		*out = new(ChiPodTemplateZone)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Frozen != nil {
+		in, out := &in.Frozen, &out.Frozen
+		*out = new(StringBool)
+		**out = **in
+	}
+	if in.Hosts != nil {
+		in, out := &in.Hosts, &out.Hosts
+		*out = make([]*ChiHost, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(ChiHost)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	in.Runtime.DeepCopyInto(&out.Runtime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiShard.
+func (in *ChiShard) DeepCopy() *ChiShard {
+	if in == nil {
+		return nil
+	}
+	out := new(ChiShard)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChiShardAddress) DeepCopyInto(out *ChiShardAddress) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiShardAddress.
+func (in *ChiShardAddress) DeepCopy() *ChiShardAddress {
+	if in == nil {
+		return nil
+	}
+	out := new(ChiShardAddress)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChiShardOverride) DeepCopyInto(out *ChiShardOverride) {
+	*out = *in
+	if in.Shards != nil {
+		in, out := &in.Shards, &out.Shards
+		*out = make([]int, len(*in))
+		copy(*out, *in)
+	}
+	if in.Templates != nil {
+		in, out := &in.Templates, &out.Templates
+		*out = new(ChiTemplateNames)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiShardOverride.
+func (in *ChiShardOverride) DeepCopy() *ChiShardOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(ChiShardOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChiShardRuntime) DeepCopyInto(out *ChiShardRuntime) {
+	*out = *in
+	out.Address = in.Address
+	if in.CHI != nil {
+		in, out := &in.CHI, &out.CHI
+		*out = new(ClickHouseInstallation)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.Files != nil {
-		in, out := &in.Files, &out.Files
-		*out = new(Settings)
-		(*in).DeepCopyInto(*out)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiShardRuntime.
+func (in *ChiShardRuntime) DeepCopy() *ChiShardRuntime {
+	if in == nil {
+		return nil
+	}
+	out := new(ChiShardRuntime)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChiSmokeTest) DeepCopyInto(out *ChiSmokeTest) {
+	*out = *in
+	if in.Queries != nil {
+		in, out := &in.Queries, &out.Queries
+		*out = make([]ChiSmokeTestQuery, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiSmokeTest.
+func (in *ChiSmokeTest) DeepCopy() *ChiSmokeTest {
+	if in == nil {
+		return nil
+	}
+	out := new(ChiSmokeTest)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChiSmokeTestQuery) DeepCopyInto(out *ChiSmokeTestQuery) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiSmokeTestQuery.
+func (in *ChiSmokeTestQuery) DeepCopy() *ChiSmokeTestQuery {
+	if in == nil {
+		return nil
+	}
+	out := new(ChiSmokeTestQuery)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChiSpec) DeepCopyInto(out *ChiSpec) {
+	*out = *in
+	if in.TaskID != nil {
+		in, out := &in.TaskID, &out.TaskID
+		*out = new(string)
+		**out = **in
+	}
+	if in.Stop != nil {
+		in, out := &in.Stop, &out.Stop
+		*out = new(StringBool)
+		**out = **in
+	}
+	if in.Troubleshoot != nil {
+		in, out := &in.Troubleshoot, &out.Troubleshoot
+		*out = new(StringBool)
+		**out = **in
+	}
+	if in.Debug != nil {
+		in, out := &in.Debug, &out.Debug
+		*out = new(ChiDebug)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Templating != nil {
+		in, out := &in.Templating, &out.Templating
+		*out = new(ChiTemplating)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Reconciling != nil {
+		in, out := &in.Reconciling, &out.Reconciling
+		*out = new(ChiReconciling)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Defaults != nil {
+		in, out := &in.Defaults, &out.Defaults
+		*out = new(ChiDefaults)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Configuration != nil {
+		in, out := &in.Configuration, &out.Configuration
+		*out = new(Configuration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Templates != nil {
+		in, out := &in.Templates, &out.Templates
+		*out = new(ChiTemplates)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UseTemplates != nil {
+		in, out := &in.UseTemplates, &out.UseTemplates
+		*out = make([]*ChiTemplateRef, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(ChiTemplateRef)
+				**out = **in
+			}
+		}
+	}
+	if in.Chproxy != nil {
+		in, out := &in.Chproxy, &out.Chproxy
+		*out = new(ChiChproxy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Logging != nil {
+		in, out := &in.Logging, &out.Logging
+		*out = new(ChiLogging)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Fork != nil {
+		in, out := &in.Fork, &out.Fork
+		*out = new(ChiFork)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiSpec.
+func (in *ChiSpec) DeepCopy() *ChiSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ChiSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChiStatus) DeepCopyInto(out *ChiStatus) {
+	*out = *in
+	if in.TaskIDsStarted != nil {
+		in, out := &in.TaskIDsStarted, &out.TaskIDsStarted
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.TaskIDsCompleted != nil {
+		in, out := &in.TaskIDsCompleted, &out.TaskIDsCompleted
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Actions != nil {
+		in, out := &in.Actions, &out.Actions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Errors != nil {
+		in, out := &in.Errors, &out.Errors
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Pods != nil {
+		in, out := &in.Pods, &out.Pods
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PodIPs != nil {
+		in, out := &in.PodIPs, &out.PodIPs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.FQDNs != nil {
+		in, out := &in.FQDNs, &out.FQDNs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NormalizedCHI != nil {
+		in, out := &in.NormalizedCHI, &out.NormalizedCHI
+		*out = new(ClickHouseInstallation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NormalizedCHICompleted != nil {
+		in, out := &in.NormalizedCHICompleted, &out.NormalizedCHICompleted
+		*out = new(ClickHouseInstallation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HostsWithTablesCreated != nil {
+		in, out := &in.HostsWithTablesCreated, &out.HostsWithTablesCreated
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.UsedTemplates != nil {
+		in, out := &in.UsedTemplates, &out.UsedTemplates
+		*out = make([]*ChiTemplateRef, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(ChiTemplateRef)
+				**out = **in
+			}
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]ChiCondition, len(*in))
+		copy(*out, *in)
+	}
+	if in.History != nil {
+		in, out := &in.History, &out.History
+		*out = make([]ChiHistoryEntry, len(*in))
+		copy(*out, *in)
+	}
+	if in.Dependencies != nil {
+		in, out := &in.Dependencies, &out.Dependencies
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Footprint != nil {
+		in, out := &in.Footprint, &out.Footprint
+		*out = new(ChiFootprint)
+		**out = **in
+	}
+	if in.Observation != nil {
+		in, out := &in.Observation, &out.Observation
+		*out = new(ChiObservation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Approval != nil {
+		in, out := &in.Approval, &out.Approval
+		*out = new(ChiApproval)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Rebalancing != nil {
+		in, out := &in.Rebalancing, &out.Rebalancing
+		*out = make([]ChiRebalancingStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.CertificateRotations != nil {
+		in, out := &in.CertificateRotations, &out.CertificateRotations
+		*out = make([]ChiCertificateRotation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PortAllocations != nil {
+		in, out := &in.PortAllocations, &out.PortAllocations
+		*out = make([]ChiPortAllocation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.HostsHealth != nil {
+		in, out := &in.HostsHealth, &out.HostsHealth
+		*out = make([]ChiHostHealth, len(*in))
+		copy(*out, *in)
+	}
+	out.mu = in.mu
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiStatus.
+func (in *ChiStatus) DeepCopy() *ChiStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ChiStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChiTemplateNames) DeepCopyInto(out *ChiTemplateNames) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiTemplateNames.
+func (in *ChiTemplateNames) DeepCopy() *ChiTemplateNames {
+	if in == nil {
+		return nil
+	}
+	out := new(ChiTemplateNames)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChiTemplateRef) DeepCopyInto(out *ChiTemplateRef) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiTemplateRef.
+func (in *ChiTemplateRef) DeepCopy() *ChiTemplateRef {
+	if in == nil {
+		return nil
+	}
+	out := new(ChiTemplateRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChiTemplates) DeepCopyInto(out *ChiTemplates) {
+	*out = *in
+	if in.HostTemplates != nil {
+		in, out := &in.HostTemplates, &out.HostTemplates
+		*out = make([]ChiHostTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PodTemplates != nil {
+		in, out := &in.PodTemplates, &out.PodTemplates
+		*out = make([]ChiPodTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.VolumeClaimTemplates != nil {
+		in, out := &in.VolumeClaimTemplates, &out.VolumeClaimTemplates
+		*out = make([]ChiVolumeClaimTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ServiceTemplates != nil {
+		in, out := &in.ServiceTemplates, &out.ServiceTemplates
+		*out = make([]ChiServiceTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ClusterTemplates != nil {
+		in, out := &in.ClusterTemplates, &out.ClusterTemplates
+		*out = make([]ChiClusterTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.HostTemplatesIndex != nil {
+		in, out := &in.HostTemplatesIndex, &out.HostTemplatesIndex
+		*out = new(HostTemplatesIndex)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodTemplatesIndex != nil {
+		in, out := &in.PodTemplatesIndex, &out.PodTemplatesIndex
+		*out = new(PodTemplatesIndex)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.VolumeClaimTemplatesIndex != nil {
+		in, out := &in.VolumeClaimTemplatesIndex, &out.VolumeClaimTemplatesIndex
+		*out = new(VolumeClaimTemplatesIndex)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ServiceTemplatesIndex != nil {
+		in, out := &in.ServiceTemplatesIndex, &out.ServiceTemplatesIndex
+		*out = new(ServiceTemplatesIndex)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiTemplates.
+func (in *ChiTemplates) DeepCopy() *ChiTemplates {
+	if in == nil {
+		return nil
+	}
+	out := new(ChiTemplates)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChiTemplating) DeepCopyInto(out *ChiTemplating) {
+	*out = *in
+	if in.CHISelector != nil {
+		in, out := &in.CHISelector, &out.CHISelector
+		*out = make(CHISelector, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiTemplating.
+func (in *ChiTemplating) DeepCopy() *ChiTemplating {
+	if in == nil {
+		return nil
+	}
+	out := new(ChiTemplating)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChiVolumeClaimTemplate) DeepCopyInto(out *ChiVolumeClaimTemplate) {
+	*out = *in
+	in.StorageManagement.DeepCopyInto(&out.StorageManagement)
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiVolumeClaimTemplate.
+func (in *ChiVolumeClaimTemplate) DeepCopy() *ChiVolumeClaimTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(ChiVolumeClaimTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChiWriteReliability) DeepCopyInto(out *ChiWriteReliability) {
+	*out = *in
+	if in.InsertQuorumTimeout != nil {
+		in, out := &in.InsertQuorumTimeout, &out.InsertQuorumTimeout
+		*out = new(int64)
+		**out = **in
+	}
+	if in.SelectSequentialConsistency != nil {
+		in, out := &in.SelectSequentialConsistency, &out.SelectSequentialConsistency
+		*out = new(StringBool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiWriteReliability.
+func (in *ChiWriteReliability) DeepCopy() *ChiWriteReliability {
+	if in == nil {
+		return nil
+	}
+	out := new(ChiWriteReliability)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChiZookeeperConfig) DeepCopyInto(out *ChiZookeeperConfig) {
+	*out = *in
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = make([]ChiZookeeperNode, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiZookeeperConfig.
+func (in *ChiZookeeperConfig) DeepCopy() *ChiZookeeperConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ChiZookeeperConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChiZookeeperNode) DeepCopyInto(out *ChiZookeeperNode) {
+	*out = *in
+	if in.Secure != nil {
+		in, out := &in.Secure, &out.Secure
+		*out = new(StringBool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiZookeeperNode.
+func (in *ChiZookeeperNode) DeepCopy() *ChiZookeeperNode {
+	if in == nil {
+		return nil
+	}
+	out := new(ChiZookeeperNode)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClickHouseInstallation) DeepCopyInto(out *ClickHouseInstallation) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	if in.Status != nil {
+		in, out := &in.Status, &out.Status
+		*out = new(ChiStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.runtime != nil {
+		in, out := &in.runtime, &out.runtime
+		*out = new(ClickHouseInstallationRuntime)
+		(*in).DeepCopyInto(*out)
+	}
+	out.statusCreatorMutex = in.statusCreatorMutex
+	out.runtimeCreatorMutex = in.runtimeCreatorMutex
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClickHouseInstallation.
+func (in *ClickHouseInstallation) DeepCopy() *ClickHouseInstallation {
+	if in == nil {
+		return nil
+	}
+	out := new(ClickHouseInstallation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClickHouseInstallation) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClickHouseInstallationList) DeepCopyInto(out *ClickHouseInstallationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClickHouseInstallation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClickHouseInstallationList.
+func (in *ClickHouseInstallationList) DeepCopy() *ClickHouseInstallationList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClickHouseInstallationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClickHouseInstallationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClickHouseInstallationRuntime) DeepCopyInto(out *ClickHouseInstallationRuntime) {
+	*out = *in
+	if in.attributes != nil {
+		in, out := &in.attributes, &out.attributes
+		*out = new(ComparableAttributes)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClickHouseInstallationRuntime.
+func (in *ClickHouseInstallationRuntime) DeepCopy() *ClickHouseInstallationRuntime {
+	if in == nil {
+		return nil
+	}
+	out := new(ClickHouseInstallationRuntime)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClickHouseInstallationTemplate) DeepCopyInto(out *ClickHouseInstallationTemplate) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	if in.Status != nil {
+		in, out := &in.Status, &out.Status
+		*out = new(ChiStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.runtime != nil {
+		in, out := &in.runtime, &out.runtime
+		*out = new(ClickHouseInstallationRuntime)
+		(*in).DeepCopyInto(*out)
+	}
+	out.statusCreatorMutex = in.statusCreatorMutex
+	out.runtimeCreatorMutex = in.runtimeCreatorMutex
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClickHouseInstallationTemplate.
+func (in *ClickHouseInstallationTemplate) DeepCopy() *ClickHouseInstallationTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(ClickHouseInstallationTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClickHouseInstallationTemplate) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClickHouseInstallationTemplateList) DeepCopyInto(out *ClickHouseInstallationTemplateList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClickHouseInstallationTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClickHouseInstallationTemplateList.
+func (in *ClickHouseInstallationTemplateList) DeepCopy() *ClickHouseInstallationTemplateList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClickHouseInstallationTemplateList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClickHouseInstallationTemplateList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClickHouseOperatorConfiguration) DeepCopyInto(out *ClickHouseOperatorConfiguration) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClickHouseOperatorConfiguration.
+func (in *ClickHouseOperatorConfiguration) DeepCopy() *ClickHouseOperatorConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(ClickHouseOperatorConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClickHouseOperatorConfiguration) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClickHouseOperatorConfigurationList) DeepCopyInto(out *ClickHouseOperatorConfigurationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClickHouseOperatorConfiguration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClickHouseOperatorConfigurationList.
+func (in *ClickHouseOperatorConfigurationList) DeepCopy() *ClickHouseOperatorConfigurationList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClickHouseOperatorConfigurationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClickHouseOperatorConfigurationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Cluster) DeepCopyInto(out *Cluster) {
+	*out = *in
+	if in.Zookeeper != nil {
+		in, out := &in.Zookeeper, &out.Zookeeper
+		*out = new(ChiZookeeperConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Settings != nil {
+		in, out := &in.Settings, &out.Settings
+		*out = new(Settings)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Files != nil {
+		in, out := &in.Files, &out.Files
+		*out = new(Settings)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Macros != nil {
+		in, out := &in.Macros, &out.Macros
+		*out = new(Settings)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Templates != nil {
+		in, out := &in.Templates, &out.Templates
+		*out = new(ChiTemplateNames)
+		**out = **in
+	}
+	if in.SchemaPolicy != nil {
+		in, out := &in.SchemaPolicy, &out.SchemaPolicy
+		*out = new(SchemaPolicy)
+		**out = **in
+	}
+	if in.Insecure != nil {
+		in, out := &in.Insecure, &out.Insecure
+		*out = new(StringBool)
+		**out = **in
+	}
+	if in.Secure != nil {
+		in, out := &in.Secure, &out.Secure
+		*out = new(StringBool)
+		**out = **in
+	}
+	if in.Secret != nil {
+		in, out := &in.Secret, &out.Secret
+		*out = new(ClusterSecret)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Layout != nil {
+		in, out := &in.Layout, &out.Layout
+		*out = new(ChiClusterLayout)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Zones != nil {
+		in, out := &in.Zones, &out.Zones
+		*out = new(ChiPodTemplateZone)
+		(*in).DeepCopyInto(*out)
+	}
+	out.HostPorts = in.HostPorts
+	if in.RemoteReplicas != nil {
+		in, out := &in.RemoteReplicas, &out.RemoteReplicas
+		*out = make([]ChiRemoteReplica, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.WriteReliability != nil {
+		in, out := &in.WriteReliability, &out.WriteReliability
+		*out = new(ChiWriteReliability)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ReplicatedDatabases != nil {
+		in, out := &in.ReplicatedDatabases, &out.ReplicatedDatabases
+		*out = make([]ChiReplicatedDatabase, len(*in))
+		copy(*out, *in)
+	}
+	if in.Rebalancing != nil {
+		in, out := &in.Rebalancing, &out.Rebalancing
+		*out = new(ChiClusterRebalancing)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ScheduledRestart != nil {
+		in, out := &in.ScheduledRestart, &out.ScheduledRestart
+		*out = new(ChiClusterScheduledRestart)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HostNetwork != nil {
+		in, out := &in.HostNetwork, &out.HostNetwork
+		*out = new(StringBool)
+		**out = **in
+	}
+	if in.Spot != nil {
+		in, out := &in.Spot, &out.Spot
+		*out = new(ChiClusterSpot)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(ChiClusterTLS)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	in.Runtime.DeepCopyInto(&out.Runtime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Cluster.
+func (in *Cluster) DeepCopy() *Cluster {
+	if in == nil {
+		return nil
+	}
+	out := new(Cluster)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterRuntime) DeepCopyInto(out *ClusterRuntime) {
+	*out = *in
+	out.Address = in.Address
+	if in.CHI != nil {
+		in, out := &in.CHI, &out.CHI
+		*out = new(ClickHouseInstallation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ShardExpander != nil {
+		in, out := &in.ShardExpander, &out.ShardExpander
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterRuntime.
+func (in *ClusterRuntime) DeepCopy() *ClusterRuntime {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterRuntime)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterSecret) DeepCopyInto(out *ClusterSecret) {
+	*out = *in
+	if in.Auto != nil {
+		in, out := &in.Auto, &out.Auto
+		*out = new(StringBool)
+		**out = **in
+	}
+	if in.ValueFrom != nil {
+		in, out := &in.ValueFrom, &out.ValueFrom
+		*out = new(DataSource)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSecret.
+func (in *ClusterSecret) DeepCopy() *ClusterSecret {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterSecret)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComparableAttributes) DeepCopyInto(out *ComparableAttributes) {
+	*out = *in
+	if in.AdditionalEnvVars != nil {
+		in, out := &in.AdditionalEnvVars, &out.AdditionalEnvVars
+		*out = make([]corev1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AdditionalVolumes != nil {
+		in, out := &in.AdditionalVolumes, &out.AdditionalVolumes
+		*out = make([]corev1.Volume, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AdditionalVolumeMounts != nil {
+		in, out := &in.AdditionalVolumeMounts, &out.AdditionalVolumeMounts
+		*out = make([]corev1.VolumeMount, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComparableAttributes.
+func (in *ComparableAttributes) DeepCopy() *ComparableAttributes {
+	if in == nil {
+		return nil
+	}
+	out := new(ComparableAttributes)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigCRSource) DeepCopyInto(out *ConfigCRSource) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigCRSource.
+func (in *ConfigCRSource) DeepCopy() *ConfigCRSource {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigCRSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Configuration) DeepCopyInto(out *Configuration) {
+	*out = *in
+	if in.Zookeeper != nil {
+		in, out := &in.Zookeeper, &out.Zookeeper
+		*out = new(ChiZookeeperConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = new(Settings)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Profiles != nil {
+		in, out := &in.Profiles, &out.Profiles
+		*out = new(Settings)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Quotas != nil {
+		in, out := &in.Quotas, &out.Quotas
+		*out = new(Settings)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Settings != nil {
+		in, out := &in.Settings, &out.Settings
+		*out = new(Settings)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Files != nil {
+		in, out := &in.Files, &out.Files
+		*out = new(Settings)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Macros != nil {
+		in, out := &in.Macros, &out.Macros
+		*out = new(Settings)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Clusters != nil {
+		in, out := &in.Clusters, &out.Clusters
+		*out = make([]*Cluster, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(Cluster)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	if in.LogicalClusters != nil {
+		in, out := &in.LogicalClusters, &out.LogicalClusters
+		*out = make([]ChiLogicalCluster, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Configuration.
+func (in *Configuration) DeepCopy() *Configuration {
+	if in == nil {
+		return nil
+	}
+	out := new(Configuration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CopyCHIOptions) DeepCopyInto(out *CopyCHIOptions) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CopyCHIOptions.
+func (in *CopyCHIOptions) DeepCopy() *CopyCHIOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(CopyCHIOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CopyCHIStatusOptions) DeepCopyInto(out *CopyCHIStatusOptions) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CopyCHIStatusOptions.
+func (in *CopyCHIStatusOptions) DeepCopy() *CopyCHIStatusOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(CopyCHIStatusOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CycleAddress) DeepCopyInto(out *CycleAddress) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CycleAddress.
+func (in *CycleAddress) DeepCopy() *CycleAddress {
+	if in == nil {
+		return nil
+	}
+	out := new(CycleAddress)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CycleSpec) DeepCopyInto(out *CycleSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CycleSpec.
+func (in *CycleSpec) DeepCopy() *CycleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CycleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataSource) DeepCopyInto(out *DataSource) {
+	*out = *in
+	if in.SecretKeyRef != nil {
+		in, out := &in.SecretKeyRef, &out.SecretKeyRef
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataSource.
+func (in *DataSource) DeepCopy() *DataSource {
+	if in == nil {
+		return nil
+	}
+	out := new(DataSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FillStatusParams) DeepCopyInto(out *FillStatusParams) {
+	*out = *in
+	if in.Pods != nil {
+		in, out := &in.Pods, &out.Pods
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.FQDNs != nil {
+		in, out := &in.FQDNs, &out.FQDNs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NormalizedCHI != nil {
+		in, out := &in.NormalizedCHI, &out.NormalizedCHI
+		*out = new(ClickHouseInstallation)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FillStatusParams.
+func (in *FillStatusParams) DeepCopy() *FillStatusParams {
+	if in == nil {
+		return nil
+	}
+	out := new(FillStatusParams)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostAddress) DeepCopyInto(out *HostAddress) {
+	*out = *in
+	if in.CHIScopeAddress != nil {
+		in, out := &in.CHIScopeAddress, &out.CHIScopeAddress
+		*out = new(ScopeAddress)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ClusterScopeAddress != nil {
+		in, out := &in.ClusterScopeAddress, &out.ClusterScopeAddress
+		*out = new(ScopeAddress)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostAddress.
+func (in *HostAddress) DeepCopy() *HostAddress {
+	if in == nil {
+		return nil
+	}
+	out := new(HostAddress)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostPorts) DeepCopyInto(out *HostPorts) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostPorts.
+func (in *HostPorts) DeepCopy() *HostPorts {
+	if in == nil {
+		return nil
+	}
+	out := new(HostPorts)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostTemplatesIndex) DeepCopyInto(out *HostTemplatesIndex) {
+	*out = *in
+	if in.templates != nil {
+		in, out := &in.templates, &out.templates
+		*out = make(map[string]*ChiHostTemplate, len(*in))
+		for key, val := range *in {
+			var outVal *ChiHostTemplate
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = new(ChiHostTemplate)
+				(*in).DeepCopyInto(*out)
+			}
+			(*out)[key] = outVal
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostTemplatesIndex.
+func (in *HostTemplatesIndex) DeepCopy() *HostTemplatesIndex {
+	if in == nil {
+		return nil
+	}
+	out := new(HostTemplatesIndex)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostsField) DeepCopyInto(out *HostsField) {
+	*out = *in
+	if in.Field != nil {
+		in, out := &in.Field, &out.Field
+		*out = make([][]*ChiHost, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = make([]*ChiHost, len(*in))
+				for i := range *in {
+					if (*in)[i] != nil {
+						in, out := &(*in)[i], &(*out)[i]
+						*out = new(ChiHost)
+						(*in).DeepCopyInto(*out)
+					}
+				}
+			}
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostsField.
+func (in *HostsField) DeepCopy() *HostsField {
+	if in == nil {
+		return nil
+	}
+	out := new(HostsField)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObjectAddress) DeepCopyInto(out *ObjectAddress) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectAddress.
+func (in *ObjectAddress) DeepCopy() *ObjectAddress {
+	if in == nil {
+		return nil
+	}
+	out := new(ObjectAddress)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatorConfig) DeepCopyInto(out *OperatorConfig) {
+	*out = *in
+	in.Runtime.DeepCopyInto(&out.Runtime)
+	in.Watch.DeepCopyInto(&out.Watch)
+	in.ClickHouse.DeepCopyInto(&out.ClickHouse)
+	in.Template.DeepCopyInto(&out.Template)
+	in.Reconcile.DeepCopyInto(&out.Reconcile)
+	in.Annotation.DeepCopyInto(&out.Annotation)
+	in.Label.DeepCopyInto(&out.Label)
+	out.StatefulSet = in.StatefulSet
+	in.Pod.DeepCopyInto(&out.Pod)
+	in.Notifications.DeepCopyInto(&out.Notifications)
+	out.Logger = in.Logger
+	if in.WatchNamespaces != nil {
+		in, out := &in.WatchNamespaces, &out.WatchNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.CHConfigUserDefaultNetworksIP != nil {
+		in, out := &in.CHConfigUserDefaultNetworksIP, &out.CHConfigUserDefaultNetworksIP
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.IncludeIntoPropagationAnnotations != nil {
+		in, out := &in.IncludeIntoPropagationAnnotations, &out.IncludeIntoPropagationAnnotations
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExcludeFromPropagationAnnotations != nil {
+		in, out := &in.ExcludeFromPropagationAnnotations, &out.ExcludeFromPropagationAnnotations
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.IncludeIntoPropagationLabels != nil {
+		in, out := &in.IncludeIntoPropagationLabels, &out.IncludeIntoPropagationLabels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExcludeFromPropagationLabels != nil {
+		in, out := &in.ExcludeFromPropagationLabels, &out.ExcludeFromPropagationLabels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorConfig.
+func (in *OperatorConfig) DeepCopy() *OperatorConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatorConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatorConfigAnnotation) DeepCopyInto(out *OperatorConfigAnnotation) {
+	*out = *in
+	if in.Include != nil {
+		in, out := &in.Include, &out.Include
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Exclude != nil {
+		in, out := &in.Exclude, &out.Exclude
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorConfigAnnotation.
+func (in *OperatorConfigAnnotation) DeepCopy() *OperatorConfigAnnotation {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatorConfigAnnotation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatorConfigCHI) DeepCopyInto(out *OperatorConfigCHI) {
+	*out = *in
+	if in.ClusterScopeNamespaces != nil {
+		in, out := &in.ClusterScopeNamespaces, &out.ClusterScopeNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.Runtime.DeepCopyInto(&out.Runtime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorConfigCHI.
+func (in *OperatorConfigCHI) DeepCopy() *OperatorConfigCHI {
+	if in == nil {
+		return nil
 	}
-	if in.Clusters != nil {
-		in, out := &in.Clusters, &out.Clusters
-		*out = make([]*Cluster, len(*in))
+	out := new(OperatorConfigCHI)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatorConfigCHIRuntime) DeepCopyInto(out *OperatorConfigCHIRuntime) {
+	*out = *in
+	if in.TemplateFiles != nil {
+		in, out := &in.TemplateFiles, &out.TemplateFiles
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Templates != nil {
+		in, out := &in.Templates, &out.Templates
+		*out = make([]*ClickHouseInstallation, len(*in))
 		for i := range *in {
 			if (*in)[i] != nil {
 				in, out := &(*in)[i], &(*out)[i]
-				*out = new(Cluster)
+				*out = new(ClickHouseInstallation)
 				(*in).DeepCopyInto(*out)
 			}
 		}
 	}
+	out.mutex = in.mutex
+	if in.Template != nil {
+		in, out := &in.Template, &out.Template
+		*out = new(ClickHouseInstallation)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Configuration.
-func (in *Configuration) DeepCopy() *Configuration {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorConfigCHIRuntime.
+func (in *OperatorConfigCHIRuntime) DeepCopy() *OperatorConfigCHIRuntime {
 	if in == nil {
 		return nil
 	}
-	out := new(Configuration)
+	out := new(OperatorConfigCHIRuntime)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CopyCHIOptions) DeepCopyInto(out *CopyCHIOptions) {
+func (in *OperatorConfigClickHouse) DeepCopyInto(out *OperatorConfigClickHouse) {
 	*out = *in
+	in.Config.DeepCopyInto(&out.Config)
+	in.ConfigRestartPolicy.DeepCopyInto(&out.ConfigRestartPolicy)
+	out.Access = in.Access
+	out.Metrics = in.Metrics
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CopyCHIOptions.
-func (in *CopyCHIOptions) DeepCopy() *CopyCHIOptions {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorConfigClickHouse.
+func (in *OperatorConfigClickHouse) DeepCopy() *OperatorConfigClickHouse {
 	if in == nil {
 		return nil
 	}
-	out := new(CopyCHIOptions)
+	out := new(OperatorConfigClickHouse)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CopyCHIStatusOptions) DeepCopyInto(out *CopyCHIStatusOptions) {
+func (in *OperatorConfigConfig) DeepCopyInto(out *OperatorConfigConfig) {
 	*out = *in
+	in.File.DeepCopyInto(&out.File)
+	in.User.DeepCopyInto(&out.User)
+	out.Network = in.Network
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CopyCHIStatusOptions.
-func (in *CopyCHIStatusOptions) DeepCopy() *CopyCHIStatusOptions {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorConfigConfig.
+func (in *OperatorConfigConfig) DeepCopy() *OperatorConfigConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(CopyCHIStatusOptions)
+	out := new(OperatorConfigConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CycleAddress) DeepCopyInto(out *CycleAddress) {
+func (in *OperatorConfigDefault) DeepCopyInto(out *OperatorConfigDefault) {
 	*out = *in
+	if in.NetworksIP != nil {
+		in, out := &in.NetworksIP, &out.NetworksIP
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CycleAddress.
-func (in *CycleAddress) DeepCopy() *CycleAddress {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorConfigDefault.
+func (in *OperatorConfigDefault) DeepCopy() *OperatorConfigDefault {
 	if in == nil {
 		return nil
 	}
-	out := new(CycleAddress)
+	out := new(OperatorConfigDefault)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CycleSpec) DeepCopyInto(out *CycleSpec) {
+func (in *OperatorConfigFile) DeepCopyInto(out *OperatorConfigFile) {
 	*out = *in
+	out.Path = in.Path
+	in.Runtime.DeepCopyInto(&out.Runtime)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CycleSpec.
-func (in *CycleSpec) DeepCopy() *CycleSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorConfigFile.
+func (in *OperatorConfigFile) DeepCopy() *OperatorConfigFile {
 	if in == nil {
 		return nil
 	}
-	out := new(CycleSpec)
+	out := new(OperatorConfigFile)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DataSource) DeepCopyInto(out *DataSource) {
+func (in *OperatorConfigFileRuntime) DeepCopyInto(out *OperatorConfigFileRuntime) {
 	*out = *in
-	if in.SecretKeyRef != nil {
-		in, out := &in.SecretKeyRef, &out.SecretKeyRef
-		*out = new(corev1.SecretKeySelector)
-		(*in).DeepCopyInto(*out)
+	if in.CommonConfigFiles != nil {
+		in, out := &in.CommonConfigFiles, &out.CommonConfigFiles
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.HostConfigFiles != nil {
+		in, out := &in.HostConfigFiles, &out.HostConfigFiles
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.UsersConfigFiles != nil {
+		in, out := &in.UsersConfigFiles, &out.UsersConfigFiles
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataSource.
-func (in *DataSource) DeepCopy() *DataSource {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorConfigFileRuntime.
+func (in *OperatorConfigFileRuntime) DeepCopy() *OperatorConfigFileRuntime {
 	if in == nil {
 		return nil
 	}
-	out := new(DataSource)
+	out := new(OperatorConfigFileRuntime)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FillStatusParams) DeepCopyInto(out *FillStatusParams) {
+func (in *OperatorConfigLabel) DeepCopyInto(out *OperatorConfigLabel) {
 	*out = *in
-	if in.Pods != nil {
-		in, out := &in.Pods, &out.Pods
+	if in.Include != nil {
+		in, out := &in.Include, &out.Include
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	if in.FQDNs != nil {
-		in, out := &in.FQDNs, &out.FQDNs
+	if in.Exclude != nil {
+		in, out := &in.Exclude, &out.Exclude
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	if in.NormalizedCHI != nil {
-		in, out := &in.NormalizedCHI, &out.NormalizedCHI
-		*out = new(ClickHouseInstallation)
-		(*in).DeepCopyInto(*out)
-	}
+	out.Runtime = in.Runtime
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FillStatusParams.
-func (in *FillStatusParams) DeepCopy() *FillStatusParams {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorConfigLabel.
+func (in *OperatorConfigLabel) DeepCopy() *OperatorConfigLabel {
 	if in == nil {
 		return nil
 	}
-	out := new(FillStatusParams)
+	out := new(OperatorConfigLabel)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HostAddress) DeepCopyInto(out *HostAddress) {
+func (in *OperatorConfigNotificationSink) DeepCopyInto(out *OperatorConfigNotificationSink) {
 	*out = *in
-	if in.CHIScopeAddress != nil {
-		in, out := &in.CHIScopeAddress, &out.CHIScopeAddress
-		*out = new(ScopeAddress)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.ClusterScopeAddress != nil {
-		in, out := &in.ClusterScopeAddress, &out.ClusterScopeAddress
-		*out = new(ScopeAddress)
-		(*in).DeepCopyInto(*out)
+	if in.Events != nil {
+		in, out := &in.Events, &out.Events
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostAddress.
-func (in *HostAddress) DeepCopy() *HostAddress {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorConfigNotificationSink.
+func (in *OperatorConfigNotificationSink) DeepCopy() *OperatorConfigNotificationSink {
 	if in == nil {
 		return nil
 	}
-	out := new(HostAddress)
+	out := new(OperatorConfigNotificationSink)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HostTemplatesIndex) DeepCopyInto(out *HostTemplatesIndex) {
+func (in *OperatorConfigNotifications) DeepCopyInto(out *OperatorConfigNotifications) {
 	*out = *in
-	if in.templates != nil {
-		in, out := &in.templates, &out.templates
-		*out = make(map[string]*ChiHostTemplate, len(*in))
-		for key, val := range *in {
-			var outVal *ChiHostTemplate
-			if val == nil {
-				(*out)[key] = nil
-			} else {
-				in, out := &val, &outVal
-				*out = new(ChiHostTemplate)
-				(*in).DeepCopyInto(*out)
-			}
-			(*out)[key] = outVal
+	if in.Sinks != nil {
+		in, out := &in.Sinks, &out.Sinks
+		*out = make([]OperatorConfigNotificationSink, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostTemplatesIndex.
-func (in *HostTemplatesIndex) DeepCopy() *HostTemplatesIndex {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorConfigNotifications.
+func (in *OperatorConfigNotifications) DeepCopy() *OperatorConfigNotifications {
 	if in == nil {
 		return nil
 	}
-	out := new(HostTemplatesIndex)
+	out := new(OperatorConfigNotifications)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HostsField) DeepCopyInto(out *HostsField) {
+func (in *OperatorConfigPod) DeepCopyInto(out *OperatorConfigPod) {
 	*out = *in
-	if in.Field != nil {
-		in, out := &in.Field, &out.Field
-		*out = make([][]*ChiHost, len(*in))
+	in.AntiAffinity.DeepCopyInto(&out.AntiAffinity)
+	if in.Zones != nil {
+		in, out := &in.Zones, &out.Zones
+		*out = make([]OperatorConfigPodZone, len(*in))
 		for i := range *in {
-			if (*in)[i] != nil {
-				in, out := &(*in)[i], &(*out)[i]
-				*out = make([]*ChiHost, len(*in))
-				for i := range *in {
-					if (*in)[i] != nil {
-						in, out := &(*in)[i], &(*out)[i]
-						*out = new(ChiHost)
-						(*in).DeepCopyInto(*out)
-					}
-				}
-			}
+			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ImagePullSecrets != nil {
+		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
+		*out = make([]corev1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostsField.
-func (in *HostsField) DeepCopy() *HostsField {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorConfigPod.
+func (in *OperatorConfigPod) DeepCopy() *OperatorConfigPod {
 	if in == nil {
 		return nil
 	}
-	out := new(HostsField)
+	out := new(OperatorConfigPod)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ObjectAddress) DeepCopyInto(out *ObjectAddress) {
+func (in *OperatorConfigPodAntiAffinity) DeepCopyInto(out *OperatorConfigPodAntiAffinity) {
 	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Required != nil {
+		in, out := &in.Required, &out.Required
+		*out = new(StringBool)
+		**out = **in
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectAddress.
-func (in *ObjectAddress) DeepCopy() *ObjectAddress {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorConfigPodAntiAffinity.
+func (in *OperatorConfigPodAntiAffinity) DeepCopy() *OperatorConfigPodAntiAffinity {
 	if in == nil {
 		return nil
 	}
-	out := new(ObjectAddress)
+	out := new(OperatorConfigPodAntiAffinity)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OperatorConfig) DeepCopyInto(out *OperatorConfig) {
+func (in *OperatorConfigPodZone) DeepCopyInto(out *OperatorConfigPodZone) {
 	*out = *in
-	in.Runtime.DeepCopyInto(&out.Runtime)
-	in.Watch.DeepCopyInto(&out.Watch)
-	in.ClickHouse.DeepCopyInto(&out.ClickHouse)
-	in.Template.DeepCopyInto(&out.Template)
-	in.Reconcile.DeepCopyInto(&out.Reconcile)
-	in.Annotation.DeepCopyInto(&out.Annotation)
-	in.Label.DeepCopyInto(&out.Label)
-	out.StatefulSet = in.StatefulSet
-	out.Pod = in.Pod
-	out.Logger = in.Logger
-	if in.WatchNamespaces != nil {
-		in, out := &in.WatchNamespaces, &out.WatchNamespaces
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	if in.CHConfigUserDefaultNetworksIP != nil {
-		in, out := &in.CHConfigUserDefaultNetworksIP, &out.CHConfigUserDefaultNetworksIP
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	if in.IncludeIntoPropagationAnnotations != nil {
-		in, out := &in.IncludeIntoPropagationAnnotations, &out.IncludeIntoPropagationAnnotations
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	if in.ExcludeFromPropagationAnnotations != nil {
-		in, out := &in.ExcludeFromPropagationAnnotations, &out.ExcludeFromPropagationAnnotations
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	if in.IncludeIntoPropagationLabels != nil {
-		in, out := &in.IncludeIntoPropagationLabels, &out.IncludeIntoPropagationLabels
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	if in.MatchLabels != nil {
+		in, out := &in.MatchLabels, &out.MatchLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
 	}
-	if in.ExcludeFromPropagationLabels != nil {
-		in, out := &in.ExcludeFromPropagationLabels, &out.ExcludeFromPropagationLabels
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorConfig.
-func (in *OperatorConfig) DeepCopy() *OperatorConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorConfigPodZone.
+func (in *OperatorConfigPodZone) DeepCopy() *OperatorConfigPodZone {
 	if in == nil {
 		return nil
 	}
-	out := new(OperatorConfig)
+	out := new(OperatorConfigPodZone)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OperatorConfigAnnotation) DeepCopyInto(out *OperatorConfigAnnotation) {
+func (in *OperatorConfigReconcile) DeepCopyInto(out *OperatorConfigReconcile) {
 	*out = *in
-	if in.Include != nil {
-		in, out := &in.Include, &out.Include
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	if in.Exclude != nil {
-		in, out := &in.Exclude, &out.Exclude
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
+	out.Runtime = in.Runtime
+	out.StatefulSet = in.StatefulSet
+	in.Host.DeepCopyInto(&out.Host)
+	in.Preflight.DeepCopyInto(&out.Preflight)
+	in.Drift.DeepCopyInto(&out.Drift)
+	in.Readonly.DeepCopyInto(&out.Readonly)
+	in.Health.DeepCopyInto(&out.Health)
+	in.Coordination.DeepCopyInto(&out.Coordination)
+	out.Policy = in.Policy
+	in.Impersonation.DeepCopyInto(&out.Impersonation)
+	in.Freeze.DeepCopyInto(&out.Freeze)
+	out.Resync = in.Resync
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorConfigAnnotation.
-func (in *OperatorConfigAnnotation) DeepCopy() *OperatorConfigAnnotation {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorConfigReconcile.
+func (in *OperatorConfigReconcile) DeepCopy() *OperatorConfigReconcile {
 	if in == nil {
 		return nil
 	}
-	out := new(OperatorConfigAnnotation)
+	out := new(OperatorConfigReconcile)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OperatorConfigCHI) DeepCopyInto(out *OperatorConfigCHI) {
+func (in *OperatorConfigReconcileCoordination) DeepCopyInto(out *OperatorConfigReconcileCoordination) {
 	*out = *in
-	in.Runtime.DeepCopyInto(&out.Runtime)
+	if in.Wait != nil {
+		in, out := &in.Wait, &out.Wait
+		*out = new(StringBool)
+		**out = **in
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorConfigCHI.
-func (in *OperatorConfigCHI) DeepCopy() *OperatorConfigCHI {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorConfigReconcileCoordination.
+func (in *OperatorConfigReconcileCoordination) DeepCopy() *OperatorConfigReconcileCoordination {
 	if in == nil {
 		return nil
 	}
-	out := new(OperatorConfigCHI)
+	out := new(OperatorConfigReconcileCoordination)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OperatorConfigCHIRuntime) DeepCopyInto(out *OperatorConfigCHIRuntime) {
+func (in *OperatorConfigReconcileDrift) DeepCopyInto(out *OperatorConfigReconcileDrift) {
 	*out = *in
-	if in.TemplateFiles != nil {
-		in, out := &in.TemplateFiles, &out.TemplateFiles
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.Templates != nil {
-		in, out := &in.Templates, &out.Templates
-		*out = make([]*ClickHouseInstallation, len(*in))
-		for i := range *in {
-			if (*in)[i] != nil {
-				in, out := &(*in)[i], &(*out)[i]
-				*out = new(ClickHouseInstallation)
-				(*in).DeepCopyInto(*out)
-			}
-		}
-	}
-	out.mutex = in.mutex
-	if in.Template != nil {
-		in, out := &in.Template, &out.Template
-		*out = new(ClickHouseInstallation)
-		(*in).DeepCopyInto(*out)
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(StringBool)
+		**out = **in
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorConfigCHIRuntime.
-func (in *OperatorConfigCHIRuntime) DeepCopy() *OperatorConfigCHIRuntime {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorConfigReconcileDrift.
+func (in *OperatorConfigReconcileDrift) DeepCopy() *OperatorConfigReconcileDrift {
 	if in == nil {
 		return nil
 	}
-	out := new(OperatorConfigCHIRuntime)
+	out := new(OperatorConfigReconcileDrift)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OperatorConfigClickHouse) DeepCopyInto(out *OperatorConfigClickHouse) {
+func (in *OperatorConfigReconcileFreeze) DeepCopyInto(out *OperatorConfigReconcileFreeze) {
 	*out = *in
-	in.Config.DeepCopyInto(&out.Config)
-	in.ConfigRestartPolicy.DeepCopyInto(&out.ConfigRestartPolicy)
-	out.Access = in.Access
-	out.Metrics = in.Metrics
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorConfigClickHouse.
-func (in *OperatorConfigClickHouse) DeepCopy() *OperatorConfigClickHouse {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorConfigReconcileFreeze.
+func (in *OperatorConfigReconcileFreeze) DeepCopy() *OperatorConfigReconcileFreeze {
 	if in == nil {
 		return nil
 	}
-	out := new(OperatorConfigClickHouse)
+	out := new(OperatorConfigReconcileFreeze)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OperatorConfigConfig) DeepCopyInto(out *OperatorConfigConfig) {
+func (in *OperatorConfigReconcileHealth) DeepCopyInto(out *OperatorConfigReconcileHealth) {
 	*out = *in
-	in.File.DeepCopyInto(&out.File)
-	in.User.DeepCopyInto(&out.User)
-	out.Network = in.Network
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(StringBool)
+		**out = **in
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorConfigConfig.
-func (in *OperatorConfigConfig) DeepCopy() *OperatorConfigConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorConfigReconcileHealth.
+func (in *OperatorConfigReconcileHealth) DeepCopy() *OperatorConfigReconcileHealth {
 	if in == nil {
 		return nil
 	}
-	out := new(OperatorConfigConfig)
+	out := new(OperatorConfigReconcileHealth)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OperatorConfigDefault) DeepCopyInto(out *OperatorConfigDefault) {
+func (in *OperatorConfigReconcileHost) DeepCopyInto(out *OperatorConfigReconcileHost) {
 	*out = *in
-	if in.NetworksIP != nil {
-		in, out := &in.NetworksIP, &out.NetworksIP
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
+	in.Wait.DeepCopyInto(&out.Wait)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorConfigDefault.
-func (in *OperatorConfigDefault) DeepCopy() *OperatorConfigDefault {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorConfigReconcileHost.
+func (in *OperatorConfigReconcileHost) DeepCopy() *OperatorConfigReconcileHost {
 	if in == nil {
 		return nil
 	}
-	out := new(OperatorConfigDefault)
+	out := new(OperatorConfigReconcileHost)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OperatorConfigFile) DeepCopyInto(out *OperatorConfigFile) {
+func (in *OperatorConfigReconcileHostWait) DeepCopyInto(out *OperatorConfigReconcileHostWait) {
 	*out = *in
-	out.Path = in.Path
-	in.Runtime.DeepCopyInto(&out.Runtime)
+	if in.Exclude != nil {
+		in, out := &in.Exclude, &out.Exclude
+		*out = new(StringBool)
+		**out = **in
+	}
+	if in.Queries != nil {
+		in, out := &in.Queries, &out.Queries
+		*out = new(StringBool)
+		**out = **in
+	}
+	if in.Include != nil {
+		in, out := &in.Include, &out.Include
+		*out = new(StringBool)
+		**out = **in
+	}
+	if in.Tables != nil {
+		in, out := &in.Tables, &out.Tables
+		*out = new(StringBool)
+		**out = **in
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorConfigFile.
-func (in *OperatorConfigFile) DeepCopy() *OperatorConfigFile {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorConfigReconcileHostWait.
+func (in *OperatorConfigReconcileHostWait) DeepCopy() *OperatorConfigReconcileHostWait {
 	if in == nil {
 		return nil
 	}
-	out := new(OperatorConfigFile)
+	out := new(OperatorConfigReconcileHostWait)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OperatorConfigFileRuntime) DeepCopyInto(out *OperatorConfigFileRuntime) {
+func (in *OperatorConfigReconcileImpersonation) DeepCopyInto(out *OperatorConfigReconcileImpersonation) {
 	*out = *in
-	if in.CommonConfigFiles != nil {
-		in, out := &in.CommonConfigFiles, &out.CommonConfigFiles
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.HostConfigFiles != nil {
-		in, out := &in.HostConfigFiles, &out.HostConfigFiles
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.UsersConfigFiles != nil {
-		in, out := &in.UsersConfigFiles, &out.UsersConfigFiles
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(StringBool)
+		**out = **in
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorConfigFileRuntime.
-func (in *OperatorConfigFileRuntime) DeepCopy() *OperatorConfigFileRuntime {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorConfigReconcileImpersonation.
+func (in *OperatorConfigReconcileImpersonation) DeepCopy() *OperatorConfigReconcileImpersonation {
 	if in == nil {
 		return nil
 	}
-	out := new(OperatorConfigFileRuntime)
+	out := new(OperatorConfigReconcileImpersonation)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OperatorConfigLabel) DeepCopyInto(out *OperatorConfigLabel) {
+func (in *OperatorConfigReconcilePolicy) DeepCopyInto(out *OperatorConfigReconcilePolicy) {
 	*out = *in
-	if in.Include != nil {
-		in, out := &in.Include, &out.Include
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	if in.Exclude != nil {
-		in, out := &in.Exclude, &out.Exclude
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	out.Runtime = in.Runtime
+	out.Rules = in.Rules
+	out.Webhook = in.Webhook
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorConfigLabel.
-func (in *OperatorConfigLabel) DeepCopy() *OperatorConfigLabel {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorConfigReconcilePolicy.
+func (in *OperatorConfigReconcilePolicy) DeepCopy() *OperatorConfigReconcilePolicy {
 	if in == nil {
 		return nil
 	}
-	out := new(OperatorConfigLabel)
+	out := new(OperatorConfigReconcilePolicy)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OperatorConfigReconcile) DeepCopyInto(out *OperatorConfigReconcile) {
+func (in *OperatorConfigReconcilePolicyRules) DeepCopyInto(out *OperatorConfigReconcilePolicyRules) {
 	*out = *in
-	out.Runtime = in.Runtime
-	out.StatefulSet = in.StatefulSet
-	in.Host.DeepCopyInto(&out.Host)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorConfigReconcile.
-func (in *OperatorConfigReconcile) DeepCopy() *OperatorConfigReconcile {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorConfigReconcilePolicyRules.
+func (in *OperatorConfigReconcilePolicyRules) DeepCopy() *OperatorConfigReconcilePolicyRules {
 	if in == nil {
 		return nil
 	}
-	out := new(OperatorConfigReconcile)
+	out := new(OperatorConfigReconcilePolicyRules)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OperatorConfigReconcileHost) DeepCopyInto(out *OperatorConfigReconcileHost) {
+func (in *OperatorConfigReconcilePolicyWebhook) DeepCopyInto(out *OperatorConfigReconcilePolicyWebhook) {
 	*out = *in
-	in.Wait.DeepCopyInto(&out.Wait)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorConfigReconcileHost.
-func (in *OperatorConfigReconcileHost) DeepCopy() *OperatorConfigReconcileHost {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorConfigReconcilePolicyWebhook.
+func (in *OperatorConfigReconcilePolicyWebhook) DeepCopy() *OperatorConfigReconcilePolicyWebhook {
 	if in == nil {
 		return nil
 	}
-	out := new(OperatorConfigReconcileHost)
+	out := new(OperatorConfigReconcilePolicyWebhook)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OperatorConfigReconcileHostWait) DeepCopyInto(out *OperatorConfigReconcileHostWait) {
+func (in *OperatorConfigReconcilePreflight) DeepCopyInto(out *OperatorConfigReconcilePreflight) {
 	*out = *in
-	if in.Exclude != nil {
-		in, out := &in.Exclude, &out.Exclude
+	if in.Quotas != nil {
+		in, out := &in.Quotas, &out.Quotas
 		*out = new(StringBool)
 		**out = **in
 	}
-	if in.Queries != nil {
-		in, out := &in.Queries, &out.Queries
+	if in.Storage != nil {
+		in, out := &in.Storage, &out.Storage
 		*out = new(StringBool)
 		**out = **in
 	}
-	if in.Include != nil {
-		in, out := &in.Include, &out.Include
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
 		*out = new(StringBool)
 		**out = **in
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorConfigReconcileHostWait.
-func (in *OperatorConfigReconcileHostWait) DeepCopy() *OperatorConfigReconcileHostWait {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorConfigReconcilePreflight.
+func (in *OperatorConfigReconcilePreflight) DeepCopy() *OperatorConfigReconcilePreflight {
 	if in == nil {
 		return nil
 	}
-	out := new(OperatorConfigReconcileHostWait)
+	out := new(OperatorConfigReconcilePreflight)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatorConfigReconcileReadonly) DeepCopyInto(out *OperatorConfigReconcileReadonly) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(StringBool)
+		**out = **in
+	}
+	if in.Restart != nil {
+		in, out := &in.Restart, &out.Restart
+		*out = new(StringBool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorConfigReconcileReadonly.
+func (in *OperatorConfigReconcileReadonly) DeepCopy() *OperatorConfigReconcileReadonly {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatorConfigReconcileReadonly)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatorConfigReconcileResync) DeepCopyInto(out *OperatorConfigReconcileResync) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorConfigReconcileResync.
+func (in *OperatorConfigReconcileResync) DeepCopy() *OperatorConfigReconcileResync {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatorConfigReconcileResync)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -2140,6 +3619,7 @@ func (in *OperatorConfigWatch) DeepCopyInto(out *OperatorConfigWatch) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	in.Runtime.DeepCopyInto(&out.Runtime)
 	return
 }
 
@@ -2153,6 +3633,25 @@ func (in *OperatorConfigWatch) DeepCopy() *OperatorConfigWatch {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatorConfigWatchRuntime) DeepCopyInto(out *OperatorConfigWatchRuntime) {
+	*out = *in
+	if in.LabelSelector != nil {
+		out.LabelSelector = in.LabelSelector.DeepCopySelector()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorConfigWatchRuntime.
+func (in *OperatorConfigWatchRuntime) DeepCopy() *OperatorConfigWatchRuntime {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatorConfigWatchRuntime)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PodTemplatesIndex) DeepCopyInto(out *PodTemplatesIndex) {
 	*out = *in
@@ -2394,9 +3893,35 @@ func (in *SettingsUser) DeepCopy() *SettingsUser {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageAutoExpansion) DeepCopyInto(out *StorageAutoExpansion) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(StringBool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageAutoExpansion.
+func (in *StorageAutoExpansion) DeepCopy() *StorageAutoExpansion {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageAutoExpansion)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StorageManagement) DeepCopyInto(out *StorageManagement) {
 	*out = *in
+	if in.AutoExpansion != nil {
+		in, out := &in.AutoExpansion, &out.AutoExpansion
+		*out = new(StorageAutoExpansion)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
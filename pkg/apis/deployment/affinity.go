@@ -36,6 +36,8 @@ const (
 	PodDistributionMaxNumberPerNodeEqualsReplicasCount = 2000000000
 	// Shortcuts section
 	PodDistributionCircularReplication = "CircularReplication"
+	// Topology spread section
+	PodDistributionTopologySpread = "TopologySpread"
 
 	PodDistributionScopeUnspecified = "Unspecified"
 	// Pods from different ClickHouseInstallation.Cluster.Shard can co-exist on one node
@@ -59,4 +61,6 @@ const (
 const (
 	PortDistributionUnspecified       = "Unspecified"
 	PortDistributionClusterScopeIndex = "ClusterScopeIndex"
+	// PortDistributionAllocated allocates unique ports to each host, allocation is recorded in CHI status
+	PortDistributionAllocated = "Allocated"
 )
@@ -48,6 +48,11 @@ const (
 	WATCH_NAMESPACE = "WATCH_NAMESPACE"
 	// WATCH_NAMESPACES and WATCH_NAMESPACE specifies what namespaces to watch
 	WATCH_NAMESPACES = "WATCH_NAMESPACES"
+	// WATCH_LABEL_SELECTOR specifies label selector of CHIs to watch
+	WATCH_LABEL_SELECTOR = "WATCH_LABEL_SELECTOR"
+
+	// RECONCILE_MODE specifies whether operator applies desired state or only observes CHIs
+	RECONCILE_MODE = "RECONCILE_MODE"
 
 	// CHOP_CONFIG path to clickhouse operator configuration file
 	CHOP_CONFIG = "CHOP_CONFIG"
@@ -133,6 +133,27 @@ const (
 			disk,
 			reason
     `
+
+	queryQuotaUsageSQL = `
+		SELECT
+			quota_name,
+			quota_key,
+			toString(assumeNotNull(duration))   AS duration,
+			resource.1                          AS resource,
+			toString(assumeNotNull(resource.2)) AS used,
+			toString(assumeNotNull(resource.3)) AS max
+		FROM system.quotas_usage
+		ARRAY JOIN [
+			('queries',        toFloat64(queries),        toFloat64(max_queries)),
+			('errors',         toFloat64(errors),         toFloat64(max_errors)),
+			('result_rows',    toFloat64(result_rows),    toFloat64(max_result_rows)),
+			('result_bytes',   toFloat64(result_bytes),   toFloat64(max_result_bytes)),
+			('read_rows',      toFloat64(read_rows),      toFloat64(max_read_rows)),
+			('read_bytes',     toFloat64(read_bytes),     toFloat64(max_read_bytes)),
+			('execution_time', toFloat64(execution_time), toFloat64(max_execution_time))
+		] AS resource
+		WHERE (duration IS NOT NULL) AND (resource.2 IS NOT NULL) AND (resource.3 IS NOT NULL)
+	`
 )
 
 // ClickHouseMetricsFetcher specifies clickhouse fetcher object
@@ -248,6 +269,21 @@ func (f *ClickHouseMetricsFetcher) getClickHouseQueryDetachedParts(ctx context.C
 	)
 }
 
+// getClickHouseQueryQuotaUsage requests usage of limited resources of quotas from ClickHouse
+func (f *ClickHouseMetricsFetcher) getClickHouseQueryQuotaUsage(ctx context.Context) (Table, error) {
+	return f.clickHouseQueryScanRows(
+		ctx,
+		queryQuotaUsageSQL,
+		func(rows *sql.Rows, data *Table) error {
+			var quota, key, duration, resource, used, max string
+			if err := rows.Scan(&quota, &key, &duration, &resource, &used, &max); err == nil {
+				*data = append(*data, []string{quota, key, duration, resource, used, max})
+			}
+			return nil
+		},
+	)
+}
+
 // ScanFunction defines function to scan rows
 type ScanFunction func(rows *sql.Rows, data *Table) error
 
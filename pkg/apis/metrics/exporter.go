@@ -173,7 +173,7 @@ func (e *Exporter) collectHostMetrics(ctx context.Context, chi *WatchedCHI, host
 	writer := NewCHIPrometheusWriter(c, chi, host)
 
 	wg := sync.WaitGroup{}
-	wg.Add(6)
+	wg.Add(7)
 	go func(ctx context.Context, host *WatchedHost, fetcher *ClickHouseMetricsFetcher, writer *CHIPrometheusWriter) {
 		e.collectHostSystemMetrics(ctx, host, fetcher, writer)
 		wg.Done()
@@ -198,6 +198,10 @@ func (e *Exporter) collectHostMetrics(ctx context.Context, chi *WatchedCHI, host
 		e.collectHostDetachedPartsMetrics(ctx, host, fetcher, writer)
 		wg.Done()
 	}(ctx, host, fetcher, writer)
+	go func(ctx context.Context, host *WatchedHost, fetcher *ClickHouseMetricsFetcher, writer *CHIPrometheusWriter) {
+		e.collectHostQuotaUsageMetrics(ctx, host, fetcher, writer)
+		wg.Done()
+	}(ctx, host, fetcher, writer)
 	wg.Wait()
 }
 
@@ -330,6 +334,27 @@ func (e *Exporter) collectHostDetachedPartsMetrics(
 	}
 }
 
+func (e *Exporter) collectHostQuotaUsageMetrics(
+	ctx context.Context,
+	host *WatchedHost,
+	fetcher *ClickHouseMetricsFetcher,
+	writer *CHIPrometheusWriter,
+) {
+	log.V(1).Infof("Querying quota usage for host %s", host.Hostname)
+	start := time.Now()
+	quotas, err := fetcher.getClickHouseQueryQuotaUsage(ctx)
+	elapsed := time.Now().Sub(start)
+	if err == nil {
+		log.V(1).Infof("Extracted [%s] %d quota resources for host %s", elapsed, len(quotas), host.Hostname)
+		writer.WriteQuotaUsage(quotas)
+		writer.WriteOKFetch("system.quotas_usage")
+	} else {
+		// In case of an error fetching data from clickhouse store CHI name in e.cleanup
+		log.Warningf("Error [%s] querying system.quotas_usage for host %s err: %s", elapsed, host.Hostname, err)
+		writer.WriteErrorFetch("system.quotas_usage")
+	}
+}
+
 // getWatchedCHI serves HTTP request to get list of watched CHIs
 func (e *Exporter) getWatchedCHI(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
@@ -201,6 +201,22 @@ func (w *CHIPrometheusWriter) WriteDetachedParts(data [][]string) {
 	}
 }
 
+// WriteQuotaUsage writes usage and limits of quota resources
+func (w *CHIPrometheusWriter) WriteQuotaUsage(data [][]string) {
+	for _, metric := range data {
+		labelNames := []string{"quota", "quota_key", "duration", "resource"}
+		labelValues := []string{metric[0], metric[1], metric[2], metric[3]}
+		w.writeSingleMetricToPrometheus(
+			"metric_QuotaUsed", "Used amount of the quota resource within the current interval from system.quotas_usage",
+			prometheus.GaugeValue, metric[4],
+			labelNames, labelValues)
+		w.writeSingleMetricToPrometheus(
+			"metric_QuotaMax", "Limit of the quota resource within an interval from system.quotas_usage",
+			prometheus.GaugeValue, metric[5],
+			labelNames, labelValues)
+	}
+}
+
 // WriteErrorFetch writes error fetch
 func (w *CHIPrometheusWriter) WriteErrorFetch(fetchType string) {
 	labelNames := []string{"fetch_type"}
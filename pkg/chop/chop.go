@@ -18,11 +18,8 @@ import (
 	"flag"
 	"fmt"
 
-	kube "k8s.io/client-go/kubernetes"
-
 	log "github.com/altinity/clickhouse-operator/pkg/announcer"
 	"github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
-	chopclientset "github.com/altinity/clickhouse-operator/pkg/client/clientset/versioned"
 )
 
 // CHOp defines ClickHouse Operator
@@ -42,15 +39,14 @@ func NewCHOp(
 	version string,
 	commit string,
 	date string,
-	kubeClient *kube.Clientset,
-	chopClient *chopclientset.Clientset,
+	source ConfigSource,
 	initConfigFilePath string,
 ) *CHOp {
 	return &CHOp{
 		Version:       version,
 		Commit:        commit,
 		Date:          date,
-		ConfigManager: NewConfigManager(kubeClient, chopClient, initConfigFilePath),
+		ConfigManager: NewConfigManager(source, initConfigFilePath),
 	}
 }
 
@@ -62,6 +58,19 @@ func (c *CHOp) Init() error {
 	return c.ConfigManager.Init()
 }
 
+// Reload reads operator config from all sources again, so config changes are applied without restart.
+// Prepare functions complete the new config before it is put in use
+func (c *CHOp) Reload(prepare ...func(config *v1.OperatorConfig)) error {
+	if c == nil {
+		return fmt.Errorf("chop not created")
+	}
+	if err := c.ConfigManager.Reload(prepare...); err != nil {
+		return err
+	}
+	c.SetupLog()
+	return nil
+}
+
 // Config returns operator config
 func (c *CHOp) Config() *v1.OperatorConfig {
 	if c == nil {
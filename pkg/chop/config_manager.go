@@ -23,23 +23,31 @@ import (
 	"os/user"
 	"path/filepath"
 	"sort"
+	"sync"
 
 	"github.com/kubernetes-sigs/yaml"
-	kube "k8s.io/client-go/kubernetes"
+	core "k8s.io/api/core/v1"
 
 	log "github.com/altinity/clickhouse-operator/pkg/announcer"
 	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
-	chopClientSet "github.com/altinity/clickhouse-operator/pkg/client/clientset/versioned"
-	"github.com/altinity/clickhouse-operator/pkg/controller"
 )
 
+// ConfigSource provides access to Kubernetes objects operator's configuration is built from.
+// It is an interface, so configuration can be built without Kubernetes client machinery.
+type ConfigSource interface {
+	// ListConfigs lists ClickHouseOperatorConfiguration objects in the namespace
+	ListConfigs(ctx context.Context, namespace string) (*api.ClickHouseOperatorConfigurationList, error)
+	// GetSecret gets the secret
+	GetSecret(ctx context.Context, namespace, name string) (*core.Secret, error)
+}
+
 // ConfigManager specifies configuration manager in charge of operator's configuration
 type ConfigManager struct {
-	// kubeClient is a k8s client
-	kubeClient *kube.Clientset
+	// mutex guards config and lists of configs it is built from, which are replaced by reload
+	mutex sync.RWMutex
 
-	// chopClient is a k8s client able to communicate with operator's Custom Resources
-	chopClient *chopClientSet.Clientset
+	// source provides access to Custom Resources and Secrets with configuration
+	source ConfigSource
 
 	// chopConfigList is a list of available operator configurations
 	chopConfigList *api.ClickHouseOperatorConfigurationList
@@ -64,13 +72,11 @@ type ConfigManager struct {
 
 // NewConfigManager creates new ConfigManager
 func NewConfigManager(
-	kubeClient *kube.Clientset,
-	chopClient *chopClientSet.Clientset,
+	source ConfigSource,
 	initConfigFilePath string,
 ) *ConfigManager {
 	return &ConfigManager{
-		kubeClient:         kubeClient,
-		chopClient:         chopClient,
+		source:             source,
 		initConfigFilePath: initConfigFilePath,
 	}
 }
@@ -118,13 +124,37 @@ func (cm *ConfigManager) Init() error {
 
 // Config is an access wrapper
 func (cm *ConfigManager) Config() *api.OperatorConfig {
+	cm.mutex.RLock()
+	defer cm.mutex.RUnlock()
 	return cm.config
 }
 
+// Reload reads config from all sources again and replaces the final config with the newly built one.
+// Newly built config is completed by prepare functions, if any, before it replaces the final config,
+// so users never see it partially built. Config in use by now is not modified, thus it stays consistent for its current users.
+func (cm *ConfigManager) Reload(prepare ...func(config *api.OperatorConfig)) error {
+	next := NewConfigManager(cm.source, cm.initConfigFilePath)
+	if err := next.Init(); err != nil {
+		return err
+	}
+	for _, f := range prepare {
+		f(next.config)
+	}
+
+	cm.mutex.Lock()
+	defer cm.mutex.Unlock()
+	cm.runtimeParams = next.runtimeParams
+	cm.chopConfigList = next.chopConfigList
+	cm.crConfigs = next.crConfigs
+	cm.config = next.config
+
+	return nil
+}
+
 // getAllCRBasedConfigs reads all ClickHouseOperatorConfiguration objects in specified namespace
 func (cm *ConfigManager) getAllCRBasedConfigs(namespace string) {
-	// We need to have chop kube client available in order to fetch ClickHouseOperatorConfiguration objects
-	if cm.chopClient == nil {
+	// We need to have config source available in order to fetch ClickHouseOperatorConfiguration objects
+	if cm.source == nil {
 		return
 	}
 
@@ -132,7 +162,7 @@ func (cm *ConfigManager) getAllCRBasedConfigs(namespace string) {
 
 	// Get list of ClickHouseOperatorConfiguration objects
 	var err error
-	if cm.chopConfigList, err = cm.chopClient.ClickhouseV1().ClickHouseOperatorConfigurations(namespace).List(context.TODO(), controller.NewListOptions()); err != nil {
+	if cm.chopConfigList, err = cm.source.ListConfigs(context.TODO(), namespace); err != nil {
 		log.V(1).F().Error("Error read ClickHouseOperatorConfigurations in namespace '%s'. Err: %v", namespace, err)
 		return
 	}
@@ -194,6 +224,12 @@ func (cm *ConfigManager) buildUnifiedConfig() {
 
 // IsConfigListed checks whether specified ClickHouseOperatorConfiguration is listed in list of ClickHouseOperatorConfiguration(s)
 func (cm *ConfigManager) IsConfigListed(config *api.ClickHouseOperatorConfiguration) bool {
+	cm.mutex.RLock()
+	defer cm.mutex.RUnlock()
+	if cm.chopConfigList == nil {
+		return false
+	}
+
 	for i := range cm.chopConfigList.Items {
 		chOperatorConfiguration := &cm.chopConfigList.Items[i]
 
@@ -400,6 +436,12 @@ func (cm *ConfigManager) fetchSecretCredentials() {
 		return
 	}
 
+	// We need to have config source available in order to fetch the secret
+	if cm.source == nil {
+		cm.config.ClickHouse.Access.Secret.Runtime.Error = fmt.Sprintf("No config source to fetch secret '%s'", name)
+		return
+	}
+
 	// We have secret name specified, let's move on and read credentials
 
 	// Figure out namespace where to look for the secret
@@ -420,7 +462,7 @@ func (cm *ConfigManager) fetchSecretCredentials() {
 		return
 	}
 
-	secret, err := cm.kubeClient.CoreV1().Secrets(namespace).Get(context.TODO(), name, controller.NewGetOptions())
+	secret, err := cm.source.GetSecret(context.TODO(), namespace, name)
 	if err != nil {
 		cm.config.ClickHouse.Access.Secret.Runtime.Error = err.Error()
 		log.V(1).Warning("Unable to fetch secret: '%s/%s'", namespace, name)
@@ -0,0 +1,145 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chop
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/apis/deployment"
+)
+
+// configSource serves ClickHouseOperatorConfiguration objects from memory
+type configSource struct {
+	configs []*api.ClickHouseOperatorConfiguration
+}
+
+func (s *configSource) ListConfigs(_ context.Context, namespace string) (*api.ClickHouseOperatorConfigurationList, error) {
+	list := &api.ClickHouseOperatorConfigurationList{}
+	for _, config := range s.configs {
+		if config.Namespace == namespace {
+			list.Items = append(list.Items, api.ClickHouseOperatorConfiguration{})
+			config.DeepCopyInto(&list.Items[len(list.Items)-1])
+		}
+	}
+	return list, nil
+}
+
+func (s *configSource) GetSecret(_ context.Context, namespace, name string) (*core.Secret, error) {
+	return nil, fmt.Errorf("secret %s/%s not found", namespace, name)
+}
+
+func newConfig(name, resourceVersion string, timeout uint64) *api.ClickHouseOperatorConfiguration {
+	config := &api.ClickHouseOperatorConfiguration{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace:       "operator",
+			Name:            name,
+			ResourceVersion: resourceVersion,
+		},
+	}
+	config.Spec.Reconcile.StatefulSet.Update.Timeout = timeout
+	return config
+}
+
+func TestConfigManagerReload(t *testing.T) {
+	t.Setenv(deployment.OPERATOR_POD_NAMESPACE, "operator")
+	source := &configSource{
+		configs: []*api.ClickHouseOperatorConfiguration{newConfig("timeouts", "1", 100)},
+	}
+	cm := NewConfigManager(source, filepath.Join("..", "..", "config", "config.yaml"))
+	if err := cm.Init(); err != nil {
+		t.Fatalf("unable to init config err: %v", err)
+	}
+	initial := cm.Config()
+	if timeout := initial.Reconcile.StatefulSet.Update.Timeout; timeout != 100 {
+		t.Fatalf("got timeout %d want 100", timeout)
+	}
+
+	updated := newConfig("timeouts", "2", 200)
+	if cm.IsConfigListed(updated) {
+		t.Errorf("updated config is reported as listed before reload")
+	}
+	source.configs = []*api.ClickHouseOperatorConfiguration{updated}
+	if err := cm.Reload(); err != nil {
+		t.Fatalf("unable to reload config err: %v", err)
+	}
+
+	if timeout := cm.Config().Reconcile.StatefulSet.Update.Timeout; timeout != 200 {
+		t.Errorf("got timeout %d want 200 after reload", timeout)
+	}
+	if !cm.IsConfigListed(updated) {
+		t.Errorf("updated config is not listed after reload")
+	}
+	// Config in use before reload stays intact
+	if timeout := initial.Reconcile.StatefulSet.Update.Timeout; timeout != 100 {
+		t.Errorf("got timeout %d want 100 in the config replaced by reload", timeout)
+	}
+}
+
+func TestConfigManagerReloadPrepare(t *testing.T) {
+	t.Setenv(deployment.OPERATOR_POD_NAMESPACE, "operator")
+	source := &configSource{
+		configs: []*api.ClickHouseOperatorConfiguration{newConfig("timeouts", "1", 100)},
+	}
+	cm := NewConfigManager(source, filepath.Join("..", "..", "config", "config.yaml"))
+	if err := cm.Init(); err != nil {
+		t.Fatalf("unable to init config err: %v", err)
+	}
+	initial := cm.Config()
+
+	template := &api.ClickHouseInstallation{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace: "operator",
+			Name:      "enlisted",
+		},
+	}
+	ref := &api.ChiTemplateRef{Name: "enlisted"}
+
+	// Readers never see reloaded config without templates enlisted by prepare function
+	done := make(chan struct{})
+	failed := make(chan string, 1)
+	go func() {
+		defer close(done)
+		for i := 0; i < 1000; i++ {
+			if config := cm.Config(); (config != initial) && (config.FindTemplate(ref, "operator") == nil) {
+				failed <- "reloaded config is in use without template"
+				return
+			}
+		}
+	}()
+
+	source.configs = []*api.ClickHouseOperatorConfiguration{newConfig("timeouts", "2", 200)}
+	if err := cm.Reload(func(config *api.OperatorConfig) {
+		config.AddCHITemplate(template)
+	}); err != nil {
+		t.Fatalf("unable to reload config err: %v", err)
+	}
+	<-done
+
+	select {
+	case msg := <-failed:
+		t.Error(msg)
+	default:
+	}
+	if cm.Config().FindTemplate(ref, "operator") == nil {
+		t.Errorf("template is not enlisted into reloaded config")
+	}
+}
@@ -0,0 +1,47 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chop
+
+import (
+	"os"
+
+	log "github.com/altinity/clickhouse-operator/pkg/announcer"
+	"github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/version"
+)
+
+var chop *CHOp
+
+// New creates chop instance
+// source can be nil, in this case CHOp will not be able to use any Custom Resource(s) and Secret(s) with configuration
+func New(source ConfigSource, initCHOpConfigFilePath string) {
+	// Create operator instance
+	chop = NewCHOp(version.Version, version.GitSHA, version.BuiltAt, source, initCHOpConfigFilePath)
+	if err := chop.Init(); err != nil {
+		log.F().Fatal("Unable to init CHOP instance %v", err)
+		os.Exit(1)
+	}
+	chop.SetupLog()
+}
+
+// Get gets global CHOp
+func Get() *CHOp {
+	return chop
+}
+
+// Config gets global CHOp config
+func Config() *v1.OperatorConfig {
+	return Get().Config()
+}
@@ -0,0 +1,51 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kube
+
+import (
+	"context"
+
+	core "k8s.io/api/core/v1"
+	kube "k8s.io/client-go/kubernetes"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/chop"
+	chopclientset "github.com/altinity/clickhouse-operator/pkg/client/clientset/versioned"
+	"github.com/altinity/clickhouse-operator/pkg/controller"
+)
+
+// ConfigSource provides operator's configuration objects via k8s API clients
+type ConfigSource struct {
+	kubeClient *kube.Clientset
+	chopClient *chopclientset.Clientset
+}
+
+// NewConfigSource creates new ConfigSource
+func NewConfigSource(kubeClient *kube.Clientset, chopClient *chopclientset.Clientset) chop.ConfigSource {
+	return &ConfigSource{
+		kubeClient: kubeClient,
+		chopClient: chopClient,
+	}
+}
+
+// ListConfigs lists ClickHouseOperatorConfiguration objects in the namespace
+func (s *ConfigSource) ListConfigs(ctx context.Context, namespace string) (*api.ClickHouseOperatorConfigurationList, error) {
+	return s.chopClient.ClickhouseV1().ClickHouseOperatorConfigurations(namespace).List(ctx, controller.NewListOptions())
+}
+
+// GetSecret gets the secret
+func (s *ConfigSource) GetSecret(ctx context.Context, namespace, name string) (*core.Secret, error) {
+	return s.kubeClient.CoreV1().Secrets(namespace).Get(ctx, name, controller.NewGetOptions())
+}
@@ -12,27 +12,29 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-package chop
+package kube
 
 import (
 	"fmt"
-	"github.com/altinity/clickhouse-operator/pkg/apis/deployment"
 	"os"
 	"os/user"
 	"path/filepath"
 	"strconv"
 
 	apiextensions "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	"k8s.io/client-go/dynamic"
 	kube "k8s.io/client-go/kubernetes"
 	kuberest "k8s.io/client-go/rest"
 	kubeclientcmd "k8s.io/client-go/tools/clientcmd"
 
 	log "github.com/altinity/clickhouse-operator/pkg/announcer"
-	v1 "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/apis/deployment"
 	chopclientset "github.com/altinity/clickhouse-operator/pkg/client/clientset/versioned"
-	"github.com/altinity/clickhouse-operator/pkg/version"
 )
 
+// restConfig is the config clientsets are created with, it is kept in order to create impersonated clientsets
+var restConfig *kuberest.Config
+
 // getKubeConfig creates kuberest.Config object based on current environment
 func getKubeConfig(kubeConfigFile, masterURL string) (*kuberest.Config, error) {
 	if len(kubeConfigFile) > 0 {
@@ -101,6 +103,8 @@ func GetClientset(kubeConfigFile, masterURL string) (
 		kubeConfig.Burst = int(parsedBurst)
 	}
 
+	restConfig = kubeConfig
+
 	kubeClientset, err := kube.NewForConfig(kubeConfig)
 	if err != nil {
 		log.F().Fatal("Unable to initialize kubernetes API clientset: %s", err.Error())
@@ -119,26 +123,29 @@ func GetClientset(kubeConfigFile, masterURL string) (
 	return kubeClientset, apiextensionsClientset, chopClientset
 }
 
-var chop *CHOp
-
-// New creates chop instance
-// chopClient can be nil, in this case CHOp will not be able to use any ConfigMap(s) with configuration
-func New(kubeClient *kube.Clientset, chopClient *chopclientset.Clientset, initCHOpConfigFilePath string) {
-	// Create operator instance
-	chop = NewCHOp(version.Version, version.GitSHA, version.BuiltAt, kubeClient, chopClient, initCHOpConfigFilePath)
-	if err := chop.Init(); err != nil {
-		log.F().Fatal("Unable to init CHOP instance %v", err)
-		os.Exit(1)
+// GetImpersonatedKubeClientset gets k8s API client acting on behalf of the specified user
+func GetImpersonatedKubeClientset(username string) (*kube.Clientset, error) {
+	if restConfig == nil {
+		return nil, fmt.Errorf("kube config is not initialized")
 	}
-	chop.SetupLog()
-}
-
-// Get gets global CHOp
-func Get() *CHOp {
-	return chop
+	config := kuberest.CopyConfig(restConfig)
+	config.Impersonate = kuberest.ImpersonationConfig{
+		UserName: username,
+	}
+	return kube.NewForConfig(config)
 }
 
-// Config gets global CHOp config
-func Config() *v1.OperatorConfig {
-	return Get().Config()
+// GetDynamicClient gets k8s API dynamic client used for resources without typed clients, such as volume snapshots.
+// Client acts on behalf of the specified user, in case user is specified
+func GetDynamicClient(username string) (dynamic.Interface, error) {
+	if restConfig == nil {
+		return nil, fmt.Errorf("kube config is not initialized")
+	}
+	config := kuberest.CopyConfig(restConfig)
+	if username != "" {
+		config.Impersonate = kuberest.ImpersonationConfig{
+			UserName: username,
+		}
+	}
+	return dynamic.NewForConfig(config)
 }
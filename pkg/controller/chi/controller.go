@@ -27,6 +27,7 @@ import (
 	core "k8s.io/api/core/v1"
 	apiExtensions "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	utilRuntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -41,6 +42,7 @@ import (
 
 	log "github.com/altinity/clickhouse-operator/pkg/announcer"
 	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/apis/deployment"
 	"github.com/altinity/clickhouse-operator/pkg/apis/metrics"
 	"github.com/altinity/clickhouse-operator/pkg/chop"
 	chopClientSet "github.com/altinity/clickhouse-operator/pkg/client/clientset/versioned"
@@ -126,7 +128,7 @@ func (c *Controller) addEventHandlersCHI(
 	chopInformerFactory.Clickhouse().V1().ClickHouseInstallations().Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc: func(obj interface{}) {
 			chi := obj.(*api.ClickHouseInstallation)
-			if !chop.Config().IsWatchedNamespace(chi.Namespace) {
+			if !chop.Config().IsWatchedNamespace(chi.Namespace) || !chop.Config().IsWatchedLabels(chi.Labels) {
 				return
 			}
 			log.V(3).M(chi).Info("chiInformer.AddFunc")
@@ -135,7 +137,7 @@ func (c *Controller) addEventHandlersCHI(
 		UpdateFunc: func(old, new interface{}) {
 			oldChi := old.(*api.ClickHouseInstallation)
 			newChi := new.(*api.ClickHouseInstallation)
-			if !chop.Config().IsWatchedNamespace(newChi.Namespace) {
+			if !chop.Config().IsWatchedNamespace(newChi.Namespace) || !chop.Config().IsWatchedLabels(newChi.Labels) {
 				return
 			}
 			log.V(3).M(newChi).Info("chiInformer.UpdateFunc")
@@ -143,7 +145,7 @@ func (c *Controller) addEventHandlersCHI(
 		},
 		DeleteFunc: func(obj interface{}) {
 			chi := obj.(*api.ClickHouseInstallation)
-			if !chop.Config().IsWatchedNamespace(chi.Namespace) {
+			if !chop.Config().IsWatchedNamespace(chi.Namespace) || !chop.Config().IsWatchedLabels(chi.Labels) {
 				return
 			}
 			log.V(3).M(chi).Info("chiInformer.DeleteFunc")
@@ -455,7 +457,27 @@ func (c *Controller) addEventHandlers(
 
 // isTrackedObject checks whether operator is interested in changes of this object
 func (c *Controller) isTrackedObject(objectMeta *meta.ObjectMeta) bool {
-	return chop.Config().IsWatchedNamespace(objectMeta.Namespace) && model.IsCHOPGeneratedObject(objectMeta)
+	return chop.Config().IsWatchedNamespace(objectMeta.Namespace) && model.IsCHOPGeneratedObject(objectMeta) && c.isWatchedOwner(objectMeta)
+}
+
+// isWatchedOwner checks whether CHI the object belongs to matches watch label selector,
+// so objects of CHIs managed by another operator are not tracked
+func (c *Controller) isWatchedOwner(objectMeta *meta.ObjectMeta) bool {
+	if chop.Config().Watch.LabelSelector == "" {
+		// In case no label selector specified - all CHIs are watched
+		return true
+	}
+
+	name, err := model.GetCHINameFromObjectMeta(objectMeta)
+	if err != nil {
+		return false
+	}
+	chi, err := c.chiLister.ClickHouseInstallations(objectMeta.Namespace).Get(name)
+	if err != nil {
+		// CHI is not known, nothing to reconcile the object against
+		return false
+	}
+	return chop.Config().IsWatchedLabels(chi.Labels)
 }
 
 // Run syncs caches, starts workers
@@ -637,14 +659,11 @@ func (c *Controller) deleteWatchAsync(chi *metrics.WatchedCHI) {
 func (c *Controller) addChopConfig(chopConfig *api.ClickHouseOperatorConfiguration) error {
 	if chop.Get().ConfigManager.IsConfigListed(chopConfig) {
 		log.V(1).M(chopConfig).F().Info("already known config - do nothing")
-	} else {
-		log.V(1).M(chopConfig).F().Info("new, previously unknown config, need to apply")
-		// TODO
-		// NEED REFACTORING
-		// os.Exit(0)
+		return nil
 	}
 
-	return nil
+	log.V(1).M(chopConfig).F().Info("new, previously unknown config, need to apply")
+	return c.reloadChopConfig(chopConfig)
 }
 
 // updateChopConfig
@@ -656,23 +675,49 @@ func (c *Controller) updateChopConfig(old, new *api.ClickHouseOperatorConfigurat
 	}
 
 	log.V(2).M(new).F().Info("ResourceVersion change: %s to %s", old.ObjectMeta.ResourceVersion, new.ObjectMeta.ResourceVersion)
-	// TODO
-	// NEED REFACTORING
-	//os.Exit(0)
-
-	return nil
+	return c.reloadChopConfig(new)
 }
 
-// deleteChit deletes CHIT
+// deleteChopConfig deletes CHOp config
 func (c *Controller) deleteChopConfig(chopConfig *api.ClickHouseOperatorConfiguration) error {
 	log.V(2).M(chopConfig).F().P()
-	// TODO
-	// NEED REFACTORING
-	//os.Exit(0)
+	return c.reloadChopConfig(chopConfig)
+}
+
+// reloadChopConfig hot-reloads operator's config, in case changed config is one of the operator's config sources
+func (c *Controller) reloadChopConfig(chopConfig *api.ClickHouseOperatorConfiguration) error {
+	// Config Custom Resources are read from the namespace where the operator runs only
+	namespace, ok := chop.Get().ConfigManager.GetRuntimeParam(deployment.OPERATOR_POD_NAMESPACE)
+	if !ok || (chopConfig.Namespace != namespace) {
+		log.V(1).M(chopConfig).F().Info("config is not located in the operator's namespace - skip it")
+		return nil
+	}
+
+	// Templates provided by ClickHouseInstallationTemplate resources are not among config sources,
+	// they are enlisted into the new config before it is put in use
+	if err := chop.Get().Reload(c.enlistCHITemplates); err != nil {
+		log.V(1).M(chopConfig).F().Error("unable to reload config. err: %v", err)
+		return err
+	}
 
+	log.V(1).M(chopConfig).F().Info("config reloaded")
 	return nil
 }
 
+// enlistCHITemplates inserts all watched ClickHouseInstallationTemplate resources into templates catalog of the config
+func (c *Controller) enlistCHITemplates(config *api.OperatorConfig) {
+	chits, err := c.chitLister.List(labels.Everything())
+	if err != nil {
+		log.V(1).F().Error("unable to list CHITs. err: %v", err)
+		return
+	}
+	for _, chit := range chits {
+		if config.IsWatchedNamespace(chit.Namespace) {
+			config.AddCHITemplate((*api.ClickHouseInstallation)(chit))
+		}
+	}
+}
+
 type patchFinalizers struct {
 	Op    string   `json:"op"`
 	Path  string   `json:"path"`
@@ -0,0 +1,77 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"testing"
+
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/chop"
+	model "github.com/altinity/clickhouse-operator/pkg/model/chi"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/render"
+)
+
+func TestIsTrackedObject(t *testing.T) {
+	render.Init("")
+	config := chop.Config()
+	saved := config.Watch
+	defer func() { config.Watch = saved }()
+
+	newCHI := func(name string, labels map[string]string) *api.ClickHouseInstallation {
+		return &api.ClickHouseInstallation{ObjectMeta: meta.ObjectMeta{Namespace: "test", Name: name, Labels: labels}}
+	}
+	c := newIntrospectionTestController(t,
+		newCHI("own", map[string]string{"team": "a"}),
+		newCHI("foreign", map[string]string{"team": "b"}),
+	)
+	// Object generated by the operator for the CHI
+	object := func(chi string) *meta.ObjectMeta {
+		return &meta.ObjectMeta{
+			Namespace: "test",
+			Name:      "chi-" + chi + "-0-0",
+			Labels: map[string]string{
+				model.LabelAppName: model.LabelAppValue,
+				model.LabelCHIName: chi,
+			},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		selector string
+		object   *meta.ObjectMeta
+		tracked  bool
+	}{
+		{name: "no selector", selector: "", object: object("foreign"), tracked: true},
+		{name: "not generated", selector: "", object: &meta.ObjectMeta{Namespace: "test", Name: "other"}, tracked: false},
+		{name: "own chi", selector: "team=a", object: object("own"), tracked: true},
+		{name: "foreign chi", selector: "team=a", object: object("foreign"), tracked: false},
+		{name: "unknown chi", selector: "team=a", object: object("absent"), tracked: false},
+	}
+	for _, test := range tests {
+		selector, err := labels.Parse(test.selector)
+		if err != nil {
+			t.Fatalf("%s: unable to parse selector err: %v", test.name, err)
+		}
+		config.Watch.LabelSelector = test.selector
+		config.Watch.Runtime.LabelSelector = selector
+		if tracked := c.isTrackedObject(test.object); tracked != test.tracked {
+			t.Errorf("%s: got %v want %v", test.name, tracked, test.tracked)
+		}
+	}
+}
@@ -42,7 +42,11 @@ func (c *Controller) createStatefulSet(ctx context.Context, host *api.ChiHost) E
 	statefulSet := host.Runtime.DesiredStatefulSet
 
 	log.V(1).Info("Create StatefulSet %s/%s", statefulSet.Namespace, statefulSet.Name)
-	if _, err := c.kubeClient.AppsV1().StatefulSets(statefulSet.Namespace).Create(ctx, statefulSet, controller.NewCreateOptions()); err != nil {
+	client, err := c.kube(statefulSet.Namespace)
+	if err != nil {
+		return errCRUDAbort
+	}
+	if _, err := client.AppsV1().StatefulSets(statefulSet.Namespace).Create(ctx, statefulSet, controller.NewCreateOptions()); err != nil {
 		log.V(1).M(host).F().Error("StatefulSet create failed. err: %v", err)
 		return errCRUDRecreate
 	}
@@ -72,7 +76,11 @@ func (c *Controller) updateStatefulSet(
 	}
 
 	// Apply newStatefulSet and wait for Generation to change
-	updatedStatefulSet, err := c.kubeClient.AppsV1().StatefulSets(newStatefulSet.Namespace).Update(ctx, newStatefulSet, controller.NewUpdateOptions())
+	client, err := c.kube(newStatefulSet.Namespace)
+	if err != nil {
+		return errCRUDAbort
+	}
+	updatedStatefulSet, err := client.AppsV1().StatefulSets(newStatefulSet.Namespace).Update(ctx, newStatefulSet, controller.NewUpdateOptions())
 	if err != nil {
 		log.V(1).M(host).F().Error("StatefulSet update failed. err: %v", err)
 		diff, equal := messagediff.DeepDiff(oldStatefulSet.Spec, newStatefulSet.Spec)
@@ -152,11 +160,16 @@ func (c *Controller) updatePersistentVolumeClaim(ctx context.Context, pvc *core.
 		return nil, fmt.Errorf("task is done")
 	}
 
-	_, err := c.kubeClient.CoreV1().PersistentVolumeClaims(pvc.Namespace).Get(ctx, pvc.Name, controller.NewGetOptions())
+	client, err := c.kube(pvc.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = c.kubeClient.CoreV1().PersistentVolumeClaims(pvc.Namespace).Get(ctx, pvc.Name, controller.NewGetOptions())
 	if err != nil {
 		if apiErrors.IsNotFound(err) {
 			// This is not an error per se, means PVC is not created (yet)?
-			_, err = c.kubeClient.CoreV1().PersistentVolumeClaims(pvc.Namespace).Create(ctx, pvc, controller.NewCreateOptions())
+			_, err = client.CoreV1().PersistentVolumeClaims(pvc.Namespace).Create(ctx, pvc, controller.NewCreateOptions())
 			if err != nil {
 				log.V(1).M(pvc).F().Error("unable to Create PVC err: %v", err)
 			}
@@ -167,7 +180,7 @@ func (c *Controller) updatePersistentVolumeClaim(ctx context.Context, pvc *core.
 		return nil, err
 	}
 
-	pvcUpdated, err := c.kubeClient.CoreV1().PersistentVolumeClaims(pvc.Namespace).Update(ctx, pvc, controller.NewUpdateOptions())
+	pvcUpdated, err := client.CoreV1().PersistentVolumeClaims(pvc.Namespace).Update(ctx, pvc, controller.NewUpdateOptions())
 	if err == nil {
 		return pvcUpdated, err
 	}
@@ -254,7 +267,9 @@ func (c *Controller) onStatefulSetUpdateFailed(ctx context.Context, rollbackStat
 		// it is the only way to go. Just delete Pod and StatefulSet will recreated Pod with current .spec
 		// This will rollback Pod to previous .spec
 		statefulSet.Spec = *rollbackStatefulSet.Spec.DeepCopy()
-		statefulSet, _ = c.kubeClient.AppsV1().StatefulSets(namespace).Update(ctx, statefulSet, controller.NewUpdateOptions())
+		if client, err := c.kube(namespace); err == nil {
+			statefulSet, _ = client.AppsV1().StatefulSets(namespace).Update(ctx, statefulSet, controller.NewUpdateOptions())
+		}
 		_ = c.statefulSetDeletePod(ctx, statefulSet, host)
 
 		return c.shouldContinueOnUpdateFailed()
@@ -309,7 +324,11 @@ func (c *Controller) createSecret(ctx context.Context, secret *core.Secret) erro
 	}
 
 	log.V(1).Info("Create Secret %s/%s", secret.Namespace, secret.Name)
-	if _, err := c.kubeClient.CoreV1().Secrets(secret.Namespace).Create(ctx, secret, controller.NewCreateOptions()); err != nil {
+	client, err := c.kube(secret.Namespace)
+	if err != nil {
+		return err
+	}
+	if _, err := client.CoreV1().Secrets(secret.Namespace).Create(ctx, secret, controller.NewCreateOptions()); err != nil {
 		// Unable to create StatefulSet at all
 		log.V(1).Error("Create Secret %s/%s failed err:%v", secret.Namespace, secret.Name, err)
 		return err
@@ -57,13 +57,16 @@ func (c *Controller) deleteConfigMapsCHI(ctx context.Context, chi *api.ClickHous
 	// chi-b3d29f-common-usersd    0      61s
 	// service/clickhouse-example-01         LoadBalancer   10.106.183.200   <pending>     8123:31607/TCP,9000:31492/TCP,9009:31357/TCP   33s   clickhouse.altinity.com/chi=example-01
 
-	var err error
+	client, err := c.kube(chi.Namespace)
+	if err != nil {
+		return err
+	}
 
 	configMapCommon := model.CreateConfigMapCommonName(chi)
 	configMapCommonUsersName := model.CreateConfigMapCommonUsersName(chi)
 
 	// Delete ConfigMap
-	err = c.kubeClient.CoreV1().ConfigMaps(chi.Namespace).Delete(ctx, configMapCommon, controller.NewDeleteOptions())
+	err = client.CoreV1().ConfigMaps(chi.Namespace).Delete(ctx, configMapCommon, controller.NewDeleteOptions())
 	switch {
 	case err == nil:
 		log.V(1).M(chi).Info("OK delete ConfigMap %s/%s", chi.Namespace, configMapCommon)
@@ -73,7 +76,7 @@ func (c *Controller) deleteConfigMapsCHI(ctx context.Context, chi *api.ClickHous
 		log.V(1).M(chi).F().Error("FAIL delete ConfigMap %s/%s err:%v", chi.Namespace, configMapCommon, err)
 	}
 
-	err = c.kubeClient.CoreV1().ConfigMaps(chi.Namespace).Delete(ctx, configMapCommonUsersName, controller.NewDeleteOptions())
+	err = client.CoreV1().ConfigMaps(chi.Namespace).Delete(ctx, configMapCommonUsersName, controller.NewDeleteOptions())
 	switch {
 	case err == nil:
 		log.V(1).M(chi).Info("OK delete ConfigMap %s/%s", chi.Namespace, configMapCommonUsersName)
@@ -96,7 +99,11 @@ func (c *Controller) statefulSetDeletePod(ctx context.Context, statefulSet *apps
 
 	name := model.CreatePodName(statefulSet)
 	log.V(1).M(host).Info("Delete Pod %s/%s", statefulSet.Namespace, name)
-	err := c.kubeClient.CoreV1().Pods(statefulSet.Namespace).Delete(ctx, name, controller.NewDeleteOptions())
+	client, err := c.kube(statefulSet.Namespace)
+	if err != nil {
+		return err
+	}
+	err = client.CoreV1().Pods(statefulSet.Namespace).Delete(ctx, name, controller.NewDeleteOptions())
 	if err == nil {
 		log.V(1).M(host).Info("OK delete Pod %s/%s", statefulSet.Namespace, name)
 	} else if apiErrors.IsNotFound(err) {
@@ -126,7 +133,11 @@ func (c *Controller) deleteStatefulSet(ctx context.Context, host *api.ChiHost) e
 	namespace := host.Runtime.Address.Namespace
 	log.V(1).M(host).F().Info("%s/%s", namespace, name)
 
-	var err error
+	client, err := c.kube(namespace)
+	if err != nil {
+		return err
+	}
+
 	host.Runtime.CurStatefulSet, err = c.getStatefulSet(host)
 	if err != nil {
 		// Unable to fetch cur StatefulSet, but this is not necessarily an error yet
@@ -142,7 +153,7 @@ func (c *Controller) deleteStatefulSet(ctx context.Context, host *api.ChiHost) e
 	// This is the proper and graceful way to delete StatefulSet
 	var zero int32 = 0
 	host.Runtime.CurStatefulSet.Spec.Replicas = &zero
-	if _, err := c.kubeClient.AppsV1().StatefulSets(namespace).Update(ctx, host.Runtime.CurStatefulSet, controller.NewUpdateOptions()); err != nil {
+	if _, err := client.AppsV1().StatefulSets(namespace).Update(ctx, host.Runtime.CurStatefulSet, controller.NewUpdateOptions()); err != nil {
 		log.V(1).M(host).Error("UNABLE to update StatefulSet %s/%s", namespace, name)
 		return err
 	}
@@ -151,7 +162,7 @@ func (c *Controller) deleteStatefulSet(ctx context.Context, host *api.ChiHost) e
 	_ = c.waitHostReady(ctx, host)
 
 	// And now delete empty StatefulSet
-	if err := c.kubeClient.AppsV1().StatefulSets(namespace).Delete(ctx, name, controller.NewDeleteOptions()); err == nil {
+	if err := client.AppsV1().StatefulSets(namespace).Delete(ctx, name, controller.NewDeleteOptions()); err == nil {
 		log.V(1).M(host).Info("OK delete StatefulSet %s/%s", namespace, name)
 		c.waitHostDeleted(host)
 	} else if apiErrors.IsNotFound(err) {
@@ -193,6 +204,11 @@ func (c *Controller) deletePVC(ctx context.Context, host *api.ChiHost) error {
 	defer log.V(2).M(host).E().P()
 
 	namespace := host.Runtime.Address.Namespace
+	client, err := c.kube(namespace)
+	if err != nil {
+		return err
+	}
+
 	c.walkDiscoveredPVCs(host, func(pvc *core.PersistentVolumeClaim) {
 		if util.IsContextDone(ctx) {
 			log.V(2).Info("task is done")
@@ -209,7 +225,7 @@ func (c *Controller) deletePVC(ctx context.Context, host *api.ChiHost) error {
 		}
 
 		// Delete PVC
-		if err := c.kubeClient.CoreV1().PersistentVolumeClaims(namespace).Delete(ctx, pvc.Name, controller.NewDeleteOptions()); err == nil {
+		if err := client.CoreV1().PersistentVolumeClaims(namespace).Delete(ctx, pvc.Name, controller.NewDeleteOptions()); err == nil {
 			log.V(1).M(host).Info("OK delete PVC %s/%s", namespace, pvc.Name)
 		} else if apiErrors.IsNotFound(err) {
 			log.V(1).M(host).Info("NEUTRAL not found PVC %s/%s", namespace, pvc.Name)
@@ -232,7 +248,11 @@ func (c *Controller) deleteConfigMap(ctx context.Context, host *api.ChiHost) err
 	namespace := host.Runtime.Address.Namespace
 	log.V(1).M(host).F().Info("%s/%s", namespace, name)
 
-	if err := c.kubeClient.CoreV1().ConfigMaps(namespace).Delete(ctx, name, controller.NewDeleteOptions()); err == nil {
+	client, err := c.kube(namespace)
+	if err != nil {
+		return err
+	}
+	if err := client.CoreV1().ConfigMaps(namespace).Delete(ctx, name, controller.NewDeleteOptions()); err == nil {
 		log.V(1).M(host).Info("OK delete ConfigMap %s/%s", namespace, name)
 	} else if apiErrors.IsNotFound(err) {
 		log.V(1).M(host).Info("NEUTRAL not found ConfigMap %s/%s", namespace, name)
@@ -324,7 +344,11 @@ func (c *Controller) deleteServiceIfExists(ctx context.Context, namespace, name
 	}
 
 	// Delete service
-	err = c.kubeClient.CoreV1().Services(namespace).Delete(ctx, name, controller.NewDeleteOptions())
+	client, err := c.kube(namespace)
+	if err != nil {
+		return err
+	}
+	err = client.CoreV1().Services(namespace).Delete(ctx, name, controller.NewDeleteOptions())
 	if err == nil {
 		log.V(1).M(namespace, name).F().Info("OK delete Service: %s/%s", namespace, name)
 	} else {
@@ -363,7 +387,11 @@ func (c *Controller) deleteSecretIfExists(ctx context.Context, namespace, name s
 	}
 
 	// Delete
-	err = c.kubeClient.CoreV1().Secrets(namespace).Delete(ctx, name, controller.NewDeleteOptions())
+	client, err := c.kube(namespace)
+	if err != nil {
+		return err
+	}
+	err = client.CoreV1().Secrets(namespace).Delete(ctx, name, controller.NewDeleteOptions())
 	if err == nil {
 		log.V(1).M(namespace, name).Info("OK delete Secret/%s", namespace, name)
 	} else {
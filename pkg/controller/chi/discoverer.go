@@ -17,12 +17,14 @@ package chi
 import (
 	"context"
 
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	log "github.com/altinity/clickhouse-operator/pkg/announcer"
 	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
 	"github.com/altinity/clickhouse-operator/pkg/controller"
 	model "github.com/altinity/clickhouse-operator/pkg/model/chi"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/creator"
 	"github.com/altinity/clickhouse-operator/pkg/util"
 )
 
@@ -42,6 +44,8 @@ func (c *Controller) discovery(ctx context.Context, chi *api.ClickHouseInstallat
 	// Comment out PV
 	//c.discoveryPVs(ctx, r, chi, opts)
 	c.discoveryPDBs(ctx, r, chi, opts)
+	c.discoveryDeployments(ctx, r, chi, opts)
+	c.discoveryVolumeSnapshots(ctx, r, chi, opts)
 	return r
 }
 
@@ -150,3 +154,46 @@ func (c *Controller) discoveryPDBs(ctx context.Context, r *model.Registry, chi *
 		r.RegisterPDB(obj.ObjectMeta)
 	}
 }
+
+func (c *Controller) discoveryDeployments(ctx context.Context, r *model.Registry, chi *api.ClickHouseInstallation, opts meta.ListOptions) {
+	list, err := c.kubeClient.AppsV1().Deployments(chi.Namespace).List(ctx, opts)
+	if err != nil {
+		log.M(chi).F().Error("FAIL list Deployment err: %v", err)
+		return
+	}
+	if list == nil {
+		log.M(chi).F().Error("FAIL list Deployment list is nil")
+		return
+	}
+	for _, obj := range list.Items {
+		r.RegisterDeployment(obj.ObjectMeta)
+	}
+}
+
+func (c *Controller) discoveryVolumeSnapshots(ctx context.Context, r *model.Registry, chi *api.ClickHouseInstallation, opts meta.ListOptions) {
+	client, err := c.dynamic(chi.Namespace)
+	if err != nil {
+		log.M(chi).F().Error("FAIL list VolumeSnapshot err: %v", err)
+		return
+	}
+	list, err := client.Resource(creator.VolumeSnapshotGroupVersionResource).Namespace(chi.Namespace).List(ctx, opts)
+	if apiErrors.IsNotFound(err) {
+		// VolumeSnapshot API is not installed, so there are no snapshots to discover
+		return
+	}
+	if err != nil {
+		log.M(chi).F().Error("FAIL list VolumeSnapshot err: %v", err)
+		return
+	}
+	if list == nil {
+		log.M(chi).F().Error("FAIL list VolumeSnapshot list is nil")
+		return
+	}
+	for _, obj := range list.Items {
+		r.RegisterVolumeSnapshot(meta.ObjectMeta{
+			Name:      obj.GetName(),
+			Namespace: obj.GetNamespace(),
+			Labels:    obj.GetLabels(),
+		})
+	}
+}
@@ -36,6 +36,27 @@ var (
 	errPVCIsLost            ErrorDataPersistence = errors.New("pvc is lost")
 )
 
+// ErrorPreflight specifies errors of the pre-flight capacity checks
+type ErrorPreflight error
+
+var (
+	errPreflightFailed ErrorPreflight = errors.New("preflight check failed")
+)
+
+// ErrorPolicy specifies errors of the policy checks
+type ErrorPolicy error
+
+var (
+	errPolicyRejected ErrorPolicy = errors.New("rejected by policy")
+)
+
+// ErrorDrain specifies errors of the drain of removed shards
+type ErrorDrain error
+
+var (
+	errDrainFailed ErrorDrain = errors.New("drain of removed shards failed")
+)
+
 func errIsDataLoss(err error) bool {
 	switch err {
 	case errPVCWithLostPVDeleted:
@@ -0,0 +1,254 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+
+	log "github.com/altinity/clickhouse-operator/pkg/announcer"
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/render"
+)
+
+// IntrospectionPath specifies path CHI introspection API is served at.
+// GET <path> lists CHIs, GET <path><namespace>/<name> describes the CHI,
+// GET <path><namespace>/<name>/topology?cluster=<cluster>&shards=<count>&replicas=<count> simulates topology change of the CHI
+const IntrospectionPath = "/api/v1/chi/"
+
+// chiSummary describes CHI in the list of CHIs
+type chiSummary struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Status    string `json:"status,omitempty"`
+	TaskID    string `json:"taskID,omitempty"`
+	Hosts     int    `json:"hosts"`
+	Errors    int    `json:"errors"`
+}
+
+// chiIntrospection describes CHI in details
+type chiIntrospection struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	// Spec is normalized spec of the most recent completed reconcile
+	Spec      *api.ChiSpec           `json:"spec,omitempty"`
+	Hosts     []hostIntrospection    `json:"hosts,omitempty"`
+	Reconcile reconcileIntrospection `json:"reconcile"`
+	// Errors lists recent reconcile errors, most recent first
+	Errors []string `json:"errors,omitempty"`
+}
+
+// hostIntrospection describes host of the CHI
+type hostIntrospection struct {
+	Cluster string `json:"cluster"`
+	Shard   string `json:"shard"`
+	Name    string `json:"name"`
+}
+
+// reconcileIntrospection describes reconcile state of the CHI
+type reconcileIntrospection struct {
+	Status             string             `json:"status,omitempty"`
+	TaskID             string             `json:"taskID,omitempty"`
+	Action             string             `json:"action,omitempty"`
+	Generation         int64              `json:"generation"`
+	ObservedGeneration int64              `json:"observedGeneration"`
+	HostsCompleted     int                `json:"hostsCompleted"`
+	HostsFailed        int                `json:"hostsFailed"`
+	Conditions         []api.ChiCondition `json:"conditions,omitempty"`
+	Approval           *api.ChiApproval   `json:"approval,omitempty"`
+}
+
+// IntrospectionHandler creates HTTP handler serving CHI introspection API.
+// Requests are required to be authenticated with bearer token equal to the specified one.
+func (c *Controller) IntrospectionHandler(token string) http.Handler {
+	return &introspectionHandler{
+		controller: c,
+		token:      token,
+	}
+}
+
+type introspectionHandler struct {
+	controller *Controller
+	token      string
+}
+
+// ServeHTTP is an interface method to serve HTTP requests
+func (h *introspectionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !h.isAuthenticated(r) {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		http.Error(w, "401 unauthorized.", http.StatusUnauthorized)
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "Sorry, only GET method is supported.", http.StatusMethodNotAllowed)
+		return
+	}
+
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, IntrospectionPath), "/")
+	switch parts := strings.Split(path, "/"); {
+	case path == "":
+		h.list(w)
+	case len(parts) == 2:
+		h.describe(w, parts[0], parts[1])
+	case (len(parts) == 3) && (parts[2] == "topology"):
+		h.simulateTopology(w, r, parts[0], parts[1])
+	default:
+		http.Error(w, "404 not found.", http.StatusNotFound)
+	}
+}
+
+// isAuthenticated checks whether request carries expected bearer token
+func (h *introspectionHandler) isAuthenticated(r *http.Request) bool {
+	if h.token == "" {
+		// No token - no access
+		return false
+	}
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return subtle.ConstantTimeCompare([]byte(token), []byte(h.token)) == 1
+}
+
+// list serves list of all CHIs
+func (h *introspectionHandler) list(w http.ResponseWriter) {
+	chis, err := h.controller.chiLister.List(labels.Everything())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	res := make([]chiSummary, 0, len(chis))
+	for _, chi := range chis {
+		res = append(res, chiSummary{
+			Namespace: chi.Namespace,
+			Name:      chi.Name,
+			Status:    chi.Status.GetStatus(),
+			TaskID:    chi.Status.GetTaskID(),
+			Hosts:     chi.Status.GetHostsCount(),
+			Errors:    len(chi.Status.GetErrors()),
+		})
+	}
+	writeJSON(w, res)
+}
+
+// describe serves details of the CHI
+func (h *introspectionHandler) describe(w http.ResponseWriter, namespace, name string) {
+	chi, err := h.controller.chiLister.ClickHouseInstallations(namespace).Get(name)
+	switch {
+	case apiErrors.IsNotFound(err):
+		http.Error(w, "404 not found.", http.StatusNotFound)
+		return
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, newCHIIntrospection(chi))
+}
+
+// simulateTopology serves projected outcome of the topology change of the CHI
+func (h *introspectionHandler) simulateTopology(w http.ResponseWriter, r *http.Request, namespace, name string) {
+	change := &render.TopologyChange{
+		Cluster: r.URL.Query().Get("cluster"),
+	}
+	for param, count := range map[string]*int{"shards": &change.Shards, "replicas": &change.Replicas} {
+		value := r.URL.Query().Get(param)
+		if value == "" {
+			continue
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("400 bad request. %s: %v", param, err), http.StatusBadRequest)
+			return
+		}
+		*count = n
+	}
+
+	chi, err := h.controller.chiLister.ClickHouseInstallations(namespace).Get(name)
+	switch {
+	case apiErrors.IsNotFound(err):
+		http.Error(w, "404 not found.", http.StatusNotFound)
+		return
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	simulation, err := render.SimulateTopology(chi, change, nil)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("400 bad request. %v", err), http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, simulation)
+}
+
+// newCHIIntrospection builds introspection of the CHI
+func newCHIIntrospection(chi *api.ClickHouseInstallation) *chiIntrospection {
+	res := &chiIntrospection{
+		Namespace: chi.Namespace,
+		Name:      chi.Name,
+		Reconcile: reconcileIntrospection{
+			Status:             chi.Status.GetStatus(),
+			TaskID:             chi.Status.GetTaskID(),
+			Action:             chi.Status.GetAction(),
+			Generation:         chi.Generation,
+			ObservedGeneration: chi.Status.GetObservedGeneration(),
+			HostsCompleted:     chi.Status.GetHostsCompletedCount(),
+			HostsFailed:        chi.Status.GetHostsFailedCount(),
+			Conditions:         chi.Status.GetConditions(),
+			Approval:           chi.Status.GetApproval(),
+		},
+		Errors: chi.Status.GetErrors(),
+	}
+
+	normalized := chi.Status.GetNormalizedCHICompleted()
+	if normalized == nil {
+		return res
+	}
+	res.Spec = &normalized.Spec
+	if normalized.Spec.Configuration == nil {
+		return res
+	}
+	// Runtime addresses are not persisted in status, so walk the layout explicitly
+	for _, cluster := range normalized.Spec.Configuration.Clusters {
+		if cluster.Layout == nil {
+			continue
+		}
+		for i := range cluster.Layout.Shards {
+			shard := &cluster.Layout.Shards[i]
+			for _, host := range shard.Hosts {
+				res.Hosts = append(res.Hosts, hostIntrospection{
+					Cluster: cluster.Name,
+					Shard:   shard.Name,
+					Name:    host.Name,
+				})
+			}
+		}
+	}
+	return res
+}
+
+// writeJSON writes value as JSON response
+func writeJSON(w http.ResponseWriter, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(value); err != nil {
+		log.V(1).Warning("unable to write introspection response. err: %v", err)
+	}
+}
@@ -0,0 +1,132 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	chopListers "github.com/altinity/clickhouse-operator/pkg/client/listers/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/render"
+)
+
+func newIntrospectionTestController(t *testing.T, chis ...*api.ClickHouseInstallation) *Controller {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	for _, chi := range chis {
+		if err := indexer.Add(chi); err != nil {
+			t.Fatalf("unable to add chi err: %v", err)
+		}
+	}
+	return &Controller{
+		chiLister: chopListers.NewClickHouseInstallationLister(indexer),
+	}
+}
+
+func TestIntrospectionHandler(t *testing.T) {
+	chi := &api.ClickHouseInstallation{
+		ObjectMeta: meta.ObjectMeta{Namespace: "test", Name: "demo"},
+		Spec: api.ChiSpec{
+			Configuration: &api.Configuration{
+				Clusters: []*api.Cluster{{Name: "main"}},
+			},
+		},
+		Status: &api.ChiStatus{
+			Status: api.StatusCompleted,
+			Errors: []string{"recent error"},
+			NormalizedCHICompleted: &api.ClickHouseInstallation{
+				Spec: api.ChiSpec{
+					Configuration: &api.Configuration{
+						Clusters: []*api.Cluster{
+							{
+								Name: "main",
+								Layout: &api.ChiClusterLayout{
+									Shards: []api.ChiShard{
+										{Name: "0", Hosts: []*api.ChiHost{{Name: "0-0"}, {Name: "0-1"}}},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	handler := newIntrospectionTestController(t, chi).IntrospectionHandler("secret")
+
+	serve := func(path, token string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, path, nil)
+		if token != "" {
+			r.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w
+	}
+
+	if w := serve(IntrospectionPath, ""); w.Code != http.StatusUnauthorized {
+		t.Errorf("no token: got %d want %d", w.Code, http.StatusUnauthorized)
+	}
+	if w := serve(IntrospectionPath, "wrong"); w.Code != http.StatusUnauthorized {
+		t.Errorf("wrong token: got %d want %d", w.Code, http.StatusUnauthorized)
+	}
+	if w := serve(IntrospectionPath+"test/absent", "secret"); w.Code != http.StatusNotFound {
+		t.Errorf("absent chi: got %d want %d", w.Code, http.StatusNotFound)
+	}
+
+	w := serve(IntrospectionPath, "secret")
+	var list []chiSummary
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil || len(list) != 1 || list[0].Name != "demo" {
+		t.Errorf("list: unexpected response %d %s", w.Code, w.Body.String())
+	}
+
+	w = serve(IntrospectionPath+"test/demo", "secret")
+	var details chiIntrospection
+	if err := json.Unmarshal(w.Body.Bytes(), &details); err != nil {
+		t.Fatalf("describe: unexpected response %d %s", w.Code, w.Body.String())
+	}
+	if len(details.Hosts) != 2 || details.Hosts[1].Name != "0-1" || details.Hosts[1].Cluster != "main" {
+		t.Errorf("describe: unexpected hosts %v", details.Hosts)
+	}
+	if details.Reconcile.Status != api.StatusCompleted || len(details.Errors) != 1 {
+		t.Errorf("describe: unexpected reconcile state %v errors %v", details.Reconcile, details.Errors)
+	}
+
+	w = serve(IntrospectionPath+"test/demo/topology?replicas=2", "secret")
+	var simulation render.TopologySimulation
+	if err := json.Unmarshal(w.Body.Bytes(), &simulation); err != nil || w.Code != http.StatusOK {
+		t.Fatalf("topology: unexpected response %d %s", w.Code, w.Body.String())
+	}
+	if simulation.Delta.Pods != 1 || simulation.AddedHosts != 1 {
+		t.Errorf("topology: unexpected simulation %v", simulation)
+	}
+	if w := serve(IntrospectionPath+"test/demo/topology?shards=many", "secret"); w.Code != http.StatusBadRequest {
+		t.Errorf("topology: bad shards count: got %d want %d", w.Code, http.StatusBadRequest)
+	}
+	if w := serve(IntrospectionPath+"test/demo/topology?shards=1000000", "secret"); w.Code != http.StatusBadRequest {
+		t.Errorf("topology: too many shards: got %d want %d", w.Code, http.StatusBadRequest)
+	}
+	if w := serve(IntrospectionPath+"test/demo/topology?replicas=1000000", "secret"); w.Code != http.StatusBadRequest {
+		t.Errorf("topology: too many replicas: got %d want %d", w.Code, http.StatusBadRequest)
+	}
+	if w := serve(IntrospectionPath+"test/demo/topology?cluster=absent&shards=2", "secret"); w.Code != http.StatusBadRequest {
+		t.Errorf("topology: absent cluster: got %d want %d", w.Code, http.StatusBadRequest)
+	}
+}
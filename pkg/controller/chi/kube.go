@@ -0,0 +1,68 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"fmt"
+
+	"k8s.io/client-go/dynamic"
+	kube "k8s.io/client-go/kubernetes"
+
+	log "github.com/altinity/clickhouse-operator/pkg/announcer"
+	"github.com/altinity/clickhouse-operator/pkg/chop"
+	chopKube "github.com/altinity/clickhouse-operator/pkg/chop/kube"
+)
+
+// kube gets k8s API client to be used to write resources into the specified namespace.
+// In case impersonation is enabled, client acts on behalf of the service account of the namespace,
+// so CHI can not make the operator modify resources the service account is not allowed to modify.
+// In case impersonated client can not be built error is returned, falling back to the operator's own identity
+// would defeat the purpose of impersonation, so only write into the namespace fails
+func (c *Controller) kube(namespace string) (kube.Interface, error) {
+	user := chop.Config().GetImpersonatedUser(namespace)
+	if user == "" {
+		return c.kubeClient, nil
+	}
+
+	if client, ok := c.impersonatedKubeClients.Load(user); ok {
+		return client.(kube.Interface), nil
+	}
+
+	client, err := chopKube.GetImpersonatedKubeClientset(user)
+	if err != nil {
+		log.V(1).F().Error("Unable to initialize kubernetes API clientset impersonating %s: %v", user, err)
+		return nil, fmt.Errorf("unable to initialize kubernetes API clientset impersonating %s: %w", user, err)
+	}
+	actual, _ := c.impersonatedKubeClients.LoadOrStore(user, client)
+	return actual.(kube.Interface), nil
+}
+
+// dynamic gets k8s API dynamic client to be used to write resources into the specified namespace.
+// Impersonation is applied the same way as for the typed client
+func (c *Controller) dynamic(namespace string) (dynamic.Interface, error) {
+	user := chop.Config().GetImpersonatedUser(namespace)
+
+	if client, ok := c.dynamicClients.Load(user); ok {
+		return client.(dynamic.Interface), nil
+	}
+
+	client, err := chopKube.GetDynamicClient(user)
+	if err != nil {
+		log.V(1).F().Error("Unable to initialize kubernetes API dynamic client for user '%s': %v", user, err)
+		return nil, fmt.Errorf("unable to initialize kubernetes API dynamic client for user '%s': %w", user, err)
+	}
+	actual, _ := c.dynamicClients.LoadOrStore(user, client)
+	return actual.(dynamic.Interface), nil
+}
@@ -0,0 +1,49 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"context"
+	"testing"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/chop"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/builder"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/render"
+)
+
+func TestKubeImpersonationFailure(t *testing.T) {
+	render.Init("")
+	config := chop.Config()
+	saved := config.Reconcile.Impersonation
+	config.Reconcile.Impersonation.Enabled = api.NewStringBool(true)
+	config.Reconcile.Impersonation.ServiceAccount = "clickhouse-operator"
+	defer func() { config.Reconcile.Impersonation = saved }()
+
+	// No kube config is available in tests, so impersonated clients can not be built
+	c := &Controller{}
+	if _, err := c.kube("test"); err == nil {
+		t.Errorf("kube: expected error")
+	}
+	if _, err := c.dynamic("test"); err == nil {
+		t.Errorf("dynamic: expected error")
+	}
+
+	// Failure is returned to the caller instead of terminating the operator
+	chi := builder.NewCHI("test", "impersonated")
+	if err := c.deleteConfigMapsCHI(context.Background(), chi); err == nil {
+		t.Errorf("deleteConfigMapsCHI: expected error")
+	}
+}
@@ -70,6 +70,12 @@ func (c *Controller) labelMyObjectsTree(ctx context.Context) error {
 		return nil
 	}
 
+	if chop.Config().IsObserveMode() {
+		// Nothing is applied in observe mode, operator's own objects included
+		log.V(1).F().Info("Observe mode, will not label operator objects")
+		return nil
+	}
+
 	// What pod does operator run in?
 	name, ok1 := chop.Get().ConfigManager.GetRuntimeParam(deployment.OPERATOR_POD_NAME)
 	namespace, ok2 := chop.Get().ConfigManager.GetRuntimeParam(deployment.OPERATOR_POD_NAMESPACE)
@@ -263,7 +269,11 @@ func (c *Controller) appendLabelReadyOnPod(ctx context.Context, host *api.ChiHos
 
 	if model.AppendLabelReady(&pod.ObjectMeta) {
 		// Modified, need to update
-		_, err = c.kubeClient.CoreV1().Pods(pod.Namespace).Update(ctx, pod, controller.NewUpdateOptions())
+		client, err := c.kube(pod.Namespace)
+		if err != nil {
+			return err
+		}
+		_, err = client.CoreV1().Pods(pod.Namespace).Update(ctx, pod, controller.NewUpdateOptions())
 		if err != nil {
 			log.M(host).F().Error("FAIL setting 'ready' label for host %s err:%v", host.Runtime.Address.NamespaceNameString(), err)
 			return err
@@ -297,7 +307,11 @@ func (c *Controller) deleteLabelReadyPod(ctx context.Context, host *api.ChiHost)
 
 	if model.DeleteLabelReady(&pod.ObjectMeta) {
 		// Modified, need to update
-		_, err = c.kubeClient.CoreV1().Pods(pod.Namespace).Update(ctx, pod, controller.NewUpdateOptions())
+		client, err := c.kube(pod.Namespace)
+		if err != nil {
+			return err
+		}
+		_, err = client.CoreV1().Pods(pod.Namespace).Update(ctx, pod, controller.NewUpdateOptions())
 		return err
 	}
 
@@ -319,7 +333,11 @@ func (c *Controller) appendAnnotationReadyOnService(ctx context.Context, host *a
 
 	if model.AppendAnnotationReady(&svc.ObjectMeta) {
 		// Modified, need to update
-		_, err = c.kubeClient.CoreV1().Services(svc.Namespace).Update(ctx, svc, controller.NewUpdateOptions())
+		client, err := c.kube(svc.Namespace)
+		if err != nil {
+			return err
+		}
+		_, err = client.CoreV1().Services(svc.Namespace).Update(ctx, svc, controller.NewUpdateOptions())
 		if err != nil {
 			log.M(host).F().Error("FAIL setting 'ready' annotation for host service %s err:%v", host.Runtime.Address.NamespaceNameString(), err)
 			return err
@@ -353,7 +371,11 @@ func (c *Controller) deleteAnnotationReadyService(ctx context.Context, host *api
 
 	if model.DeleteAnnotationReady(&svc.ObjectMeta) {
 		// Modified, need to update
-		_, err = c.kubeClient.CoreV1().Services(svc.Namespace).Update(ctx, svc, controller.NewUpdateOptions())
+		client, err := c.kube(svc.Namespace)
+		if err != nil {
+			return err
+		}
+		_, err = client.CoreV1().Services(svc.Namespace).Update(ctx, svc, controller.NewUpdateOptions())
 		return err
 	}
 
@@ -16,9 +16,12 @@ package chi
 
 import (
 	"context"
+	"sync"
 
+	"go.opentelemetry.io/otel/attribute"
 	otelApi "go.opentelemetry.io/otel/metric"
 
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
 	"github.com/altinity/clickhouse-operator/pkg/metrics"
 )
 
@@ -50,6 +53,36 @@ type Metrics struct {
 	PodAddEvents    otelApi.Int64Counter
 	PodUpdateEvents otelApi.Int64Counter
 	PodDeleteEvents otelApi.Int64Counter
+
+	// CHIRequestedCPU is a total number of CPU cores requested by all hosts of a CHI
+	CHIRequestedCPU otelApi.Float64ObservableGauge
+	// CHIRequestedMemory is a total amount of memory requested by all hosts of a CHI
+	CHIRequestedMemory otelApi.Float64ObservableGauge
+	// CHIRequestedStorage is a total amount of storage requested by all hosts of a CHI
+	CHIRequestedStorage otelApi.Float64ObservableGauge
+
+	// CHIObservedMissingObjects is a number of objects of a CHI which are missing, as observed in observe mode
+	CHIObservedMissingObjects otelApi.Int64ObservableGauge
+	// CHIObservedDriftedObjects is a number of objects of a CHI which differ from desired, as observed in observe mode
+	CHIObservedDriftedObjects otelApi.Int64ObservableGauge
+	// CHIObservedPlannedActions is 1 in case there are actions planned for a CHI, as observed in observe mode
+	CHIObservedPlannedActions otelApi.Int64ObservableGauge
+}
+
+// chiGauges keeps per-CHI values, to be reported by observable gauges
+var chiGauges = struct {
+	sync.RWMutex
+	items map[string]*chiGauge
+}{
+	items: make(map[string]*chiGauge),
+}
+
+// chiGauge is a set of per-CHI values
+type chiGauge struct {
+	namespace   string
+	name        string
+	footprint   *api.ChiFootprint
+	observation *api.ChiObservation
 }
 
 var m *Metrics
@@ -119,6 +152,72 @@ func createMetrics() *Metrics {
 		otelApi.WithUnit("items"),
 	)
 
+	CHIRequestedCPU, _ := metrics.Meter().Float64ObservableGauge(
+		"clickhouse_operator_chi_requested_cpu",
+		otelApi.WithDescription("number of CPU cores requested by all hosts of CHI"),
+		otelApi.WithUnit("{cpu}"),
+	)
+	CHIRequestedMemory, _ := metrics.Meter().Float64ObservableGauge(
+		"clickhouse_operator_chi_requested_memory",
+		otelApi.WithDescription("amount of memory requested by all hosts of CHI"),
+		otelApi.WithUnit("By"),
+	)
+	CHIRequestedStorage, _ := metrics.Meter().Float64ObservableGauge(
+		"clickhouse_operator_chi_requested_storage",
+		otelApi.WithDescription("amount of storage requested by all hosts of CHI"),
+		otelApi.WithUnit("By"),
+	)
+	CHIObservedMissingObjects, _ := metrics.Meter().Int64ObservableGauge(
+		"clickhouse_operator_chi_observed_missing_objects",
+		otelApi.WithDescription("number of objects of CHI which are missing, as observed in observe mode"),
+		otelApi.WithUnit("items"),
+	)
+	CHIObservedDriftedObjects, _ := metrics.Meter().Int64ObservableGauge(
+		"clickhouse_operator_chi_observed_drifted_objects",
+		otelApi.WithDescription("number of objects of CHI which differ from desired, as observed in observe mode"),
+		otelApi.WithUnit("items"),
+	)
+	CHIObservedPlannedActions, _ := metrics.Meter().Int64ObservableGauge(
+		"clickhouse_operator_chi_observed_planned_actions",
+		otelApi.WithDescription("whether there are actions planned for CHI, as observed in observe mode"),
+		otelApi.WithUnit("items"),
+	)
+
+	_, _ = metrics.Meter().RegisterCallback(
+		func(ctx context.Context, o otelApi.Observer) error {
+			chiGauges.RLock()
+			defer chiGauges.RUnlock()
+			for _, item := range chiGauges.items {
+				attributes := otelApi.WithAttributes(
+					attribute.String("namespace", item.namespace),
+					attribute.String("chi", item.name),
+				)
+				if item.footprint != nil {
+					cpu, memory, storage := item.footprint.GetCPU(), item.footprint.GetMemory(), item.footprint.GetStorage()
+					o.ObserveFloat64(CHIRequestedCPU, cpu.AsApproximateFloat64(), attributes)
+					o.ObserveFloat64(CHIRequestedMemory, memory.AsApproximateFloat64(), attributes)
+					o.ObserveFloat64(CHIRequestedStorage, storage.AsApproximateFloat64(), attributes)
+				}
+				if item.observation != nil {
+					planned := int64(0)
+					if item.observation.HasActions() {
+						planned = 1
+					}
+					o.ObserveInt64(CHIObservedMissingObjects, int64(len(item.observation.GetMissing())), attributes)
+					o.ObserveInt64(CHIObservedDriftedObjects, int64(len(item.observation.GetDrifted())), attributes)
+					o.ObserveInt64(CHIObservedPlannedActions, planned, attributes)
+				}
+			}
+			return nil
+		},
+		CHIRequestedCPU,
+		CHIRequestedMemory,
+		CHIRequestedStorage,
+		CHIObservedMissingObjects,
+		CHIObservedDriftedObjects,
+		CHIObservedPlannedActions,
+	)
+
 	return &Metrics{
 		CHIReconcilesStarted:   CHIReconcilesStarted,
 		CHIReconcilesCompleted: CHIReconcilesCompleted,
@@ -134,6 +233,14 @@ func createMetrics() *Metrics {
 		PodAddEvents:    PodAddEvents,
 		PodUpdateEvents: PodUpdateEvents,
 		PodDeleteEvents: PodDeleteEvents,
+
+		CHIRequestedCPU:     CHIRequestedCPU,
+		CHIRequestedMemory:  CHIRequestedMemory,
+		CHIRequestedStorage: CHIRequestedStorage,
+
+		CHIObservedMissingObjects: CHIObservedMissingObjects,
+		CHIObservedDriftedObjects: CHIObservedDriftedObjects,
+		CHIObservedPlannedActions: CHIObservedPlannedActions,
 	}
 }
 
@@ -182,3 +289,33 @@ func metricsPodUpdate(ctx context.Context) {
 func metricsPodDelete(ctx context.Context) {
 	ensureMetrics().PodDeleteEvents.Add(ctx, 1)
 }
+
+// ensureCHIGauge gets per-CHI values set, creating it in case it does not exist. Lock has to be held
+func ensureCHIGauge(chi *api.ClickHouseInstallation) *chiGauge {
+	key := chi.Namespace + "/" + chi.Name
+	if _, ok := chiGauges.items[key]; !ok {
+		chiGauges.items[key] = &chiGauge{
+			namespace: chi.Namespace,
+			name:      chi.Name,
+		}
+	}
+	return chiGauges.items[key]
+}
+
+func metricsCHIFootprintSet(chi *api.ClickHouseInstallation) {
+	ensureMetrics()
+	chiGauges.Lock()
+	defer chiGauges.Unlock()
+	ensureCHIGauge(chi).footprint = chi.EnsureStatus().GetFootprint()
+}
+func metricsCHIObservationSet(chi *api.ClickHouseInstallation) {
+	ensureMetrics()
+	chiGauges.Lock()
+	defer chiGauges.Unlock()
+	ensureCHIGauge(chi).observation = chi.EnsureStatus().GetObservation()
+}
+func metricsCHIDelete(chi *api.ClickHouseInstallation) {
+	chiGauges.Lock()
+	defer chiGauges.Unlock()
+	delete(chiGauges.items, chi.Namespace+"/"+chi.Name)
+}
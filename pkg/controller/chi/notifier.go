@@ -0,0 +1,160 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	log "github.com/altinity/clickhouse-operator/pkg/announcer"
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/chop"
+	model "github.com/altinity/clickhouse-operator/pkg/model/chi"
+)
+
+const (
+	// Lifecycle events notification sinks can subscribe to
+	notificationUpgradeStarted   = "UpgradeStarted"
+	notificationUpgradeCompleted = "UpgradeCompleted"
+	notificationUpgradeFailed    = "UpgradeFailed"
+	notificationScaleDownBlocked = "ScaleDownBlocked"
+	notificationHostUnhealthy    = "HostUnhealthy"
+)
+
+// notification is the payload webhook sinks receive
+type notification struct {
+	Event     string    `json:"event"`
+	Namespace string    `json:"namespace"`
+	Name      string    `json:"name"`
+	Host      string    `json:"host,omitempty"`
+	Message   string    `json:"message"`
+	Time      time.Time `json:"time"`
+}
+
+// slackMessage is the payload of Slack incoming webhook
+type slackMessage struct {
+	Text string `json:"text"`
+}
+
+// newNotification creates notification about the event of the CHI
+func newNotification(chi *api.ClickHouseInstallation, event, host, message string) *notification {
+	return &notification{
+		Event:     event,
+		Namespace: chi.Namespace,
+		Name:      chi.Name,
+		Host:      host,
+		Message:   message,
+		Time:      time.Now(),
+	}
+}
+
+// String makes human-readable representation of the notification
+func (n *notification) String() string {
+	subject := n.Namespace + "/" + n.Name
+	if n.Host != "" {
+		subject += " host " + n.Host
+	}
+	return fmt.Sprintf("[%s] %s: %s", subject, n.Event, n.Message)
+}
+
+// payload makes body of the notification to be posted to the sink
+func (n *notification) payload(sink *api.OperatorConfigNotificationSink) ([]byte, error) {
+	if sink.Type == api.NotificationSinkTypeSlack {
+		return json.Marshal(&slackMessage{Text: n.String()})
+	}
+	return json.Marshal(n)
+}
+
+// notify delivers the event of the CHI to all subscribed sinks.
+// Delivery is asynchronous and best-effort, failures are logged only and never affect reconcile.
+func (c *Controller) notify(chi *api.ClickHouseInstallation, event, host, message string) {
+	n := newNotification(chi, event, host, message)
+	for i := range chop.Config().Notifications.Sinks {
+		sink := chop.Config().Notifications.Sinks[i]
+		if !sink.IsSubscribed(event) {
+			continue
+		}
+		go func() {
+			if err := sendNotification(context.Background(), &sink, n); err != nil {
+				log.V(1).M(chi).F().Warning("unable to deliver %s notification to sink %s. err: %v", event, sink.Name, err)
+			}
+		}()
+	}
+}
+
+// sendNotification posts the notification to the sink
+func sendNotification(ctx context.Context, sink *api.OperatorConfigNotificationSink, n *notification) error {
+	body, err := n.payload(sink)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, sink.Timeout)
+	defer cancel()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, sink.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if (response.StatusCode < http.StatusOK) || (response.StatusCode >= http.StatusMultipleChoices) {
+		return fmt.Errorf("unexpected status: %s", response.Status)
+	}
+	return nil
+}
+
+// getClickHouseUpgrade describes changes of ClickHouse image of hosts present in both old and new CHI.
+// Empty string means no upgrade
+func getClickHouseUpgrade(old, new *api.ClickHouseInstallation) string {
+	if (old == nil) || (new == nil) {
+		return ""
+	}
+
+	images := make(map[string]string)
+	old.WalkHosts(func(host *api.ChiHost) error {
+		images[host.GetName()] = model.HostGetClickHouseImage(host)
+		return nil
+	})
+
+	changes := make(map[string]int)
+	new.WalkHosts(func(host *api.ChiHost) error {
+		from, found := images[host.GetName()]
+		to := model.HostGetClickHouseImage(host)
+		if found && (from != to) {
+			changes[fmt.Sprintf("%s -> %s", from, to)]++
+		}
+		return nil
+	})
+
+	var upgrades []string
+	for change, hosts := range changes {
+		upgrades = append(upgrades, fmt.Sprintf("%s on %d host(s)", change, hosts))
+	}
+	sort.Strings(upgrades)
+	return strings.Join(upgrades, ", ")
+}
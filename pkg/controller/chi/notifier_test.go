@@ -0,0 +1,94 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/builder"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/normalizer"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/render"
+)
+
+func TestGetClickHouseUpgrade(t *testing.T) {
+	render.Init("")
+	normalize := func(image string) *api.ClickHouseInstallation {
+		chi := builder.NewCHI("test", "upgrade",
+			builder.WithPodTemplates(builder.NewPodTemplate("pod", image)),
+			builder.WithDefaultPodTemplate("pod"),
+			builder.WithCluster(builder.NewCluster("main", builder.WithReplicas(2))),
+		)
+		normalized, err := normalizer.NewNormalizer(render.NoSecrets).CreateTemplatedCHI(chi, normalizer.NewOptions())
+		if err != nil {
+			t.Fatalf("unable to normalize err: %v", err)
+		}
+		return normalized
+	}
+	old := normalize("clickhouse/clickhouse-server:23.3")
+
+	if upgrade := getClickHouseUpgrade(old, old); upgrade != "" {
+		t.Errorf("got upgrade %q of unchanged CHI", upgrade)
+	}
+	if upgrade := getClickHouseUpgrade(nil, old); upgrade != "" {
+		t.Errorf("got upgrade %q of new CHI", upgrade)
+	}
+	want := "clickhouse/clickhouse-server:23.3 -> clickhouse/clickhouse-server:23.8 on 2 host(s)"
+	if upgrade := getClickHouseUpgrade(old, normalize("clickhouse/clickhouse-server:23.8")); upgrade != want {
+		t.Errorf("got upgrade %q want %q", upgrade, want)
+	}
+}
+
+func TestSendNotification(t *testing.T) {
+	var bodies []map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := map[string]string{}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		bodies = append(bodies, body)
+	}))
+	defer server.Close()
+
+	chi := builder.NewCHI("test", "notify")
+	n := newNotification(chi, notificationHostUnhealthy, "chi-notify-main-0-0", "ClickHouse is not alive")
+	for _, typ := range []string{api.NotificationSinkTypeWebhook, api.NotificationSinkTypeSlack} {
+		sink := &api.OperatorConfigNotificationSink{Type: typ, URL: server.URL, Timeout: time.Second}
+		if err := sendNotification(context.Background(), sink, n); err != nil {
+			t.Fatalf("%s: unable to send notification err: %v", typ, err)
+		}
+	}
+
+	if (bodies[0]["event"] != notificationHostUnhealthy) || (bodies[0]["host"] != "chi-notify-main-0-0") {
+		t.Errorf("got webhook notification %v", bodies[0])
+	}
+	if text := "[test/notify host chi-notify-main-0-0] HostUnhealthy: ClickHouse is not alive"; bodies[1]["text"] != text {
+		t.Errorf("got slack message %q want %q", bodies[1]["text"], text)
+	}
+}
+
+func TestNotificationSinkIsSubscribed(t *testing.T) {
+	all := &api.OperatorConfigNotificationSink{}
+	some := &api.OperatorConfigNotificationSink{Events: []string{notificationUpgradeFailed}}
+	if !all.IsSubscribed(notificationUpgradeStarted) {
+		t.Errorf("sink without events is not subscribed to all events")
+	}
+	if some.IsSubscribed(notificationUpgradeStarted) || !some.IsSubscribed(notificationUpgradeFailed) {
+		t.Errorf("sink is subscribed to wrong events")
+	}
+}
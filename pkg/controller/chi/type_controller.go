@@ -15,6 +15,7 @@
 package chi
 
 import (
+	"sync"
 	"time"
 
 	kube "k8s.io/client-go/kubernetes"
@@ -35,7 +36,15 @@ import (
 type Controller struct {
 	// kubeClient used to Create() k8s resources as c.kubeClient.AppsV1().StatefulSets(namespace).Create(name)
 	kubeClient kube.Interface
-	extClient  apiExtensions.Interface
+	// impersonatedKubeClients keeps per-namespace clients used to write resources in case impersonation is enabled
+	impersonatedKubeClients sync.Map
+	// dynamicClients keeps per-user dynamic clients used to write resources without typed clients
+	dynamicClients sync.Map
+	// readonlyReplicas keeps time each readonly replica was first detected at, keyed by host FQDN and table name
+	readonlyReplicas sync.Map
+	// resyncChecks keeps time each periodic check was last run at, keyed by CHI namespace/name and check name
+	resyncChecks sync.Map
+	extClient    apiExtensions.Interface
 	// chopClient used to Update() CRD k8s resource as c.chopClient.ClickhouseV1().ClickHouseInstallations(chi.Namespace).Update(chiCopy)
 	chopClient chopClientSet.Interface
 
@@ -0,0 +1,153 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"context"
+	"fmt"
+
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	log "github.com/altinity/clickhouse-operator/pkg/announcer"
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/chop"
+	model "github.com/altinity/clickhouse-operator/pkg/model/chi"
+	"github.com/altinity/clickhouse-operator/pkg/util"
+)
+
+const (
+	approvalReasonNotRequired = "NoDisruptiveActions"
+	approvalReasonApproved    = "Approved"
+	approvalReasonPending     = "ApprovalPending"
+)
+
+// approvalGate holds disruptive actions of the reconcile plan which are pending approval
+type approvalGate struct {
+	actions map[string]bool
+}
+
+// newApprovalGate creates new gate holding disruptive actions of the plan
+func newApprovalGate(plan *model.Plan) *approvalGate {
+	g := &approvalGate{
+		actions: make(map[string]bool),
+	}
+	plan.Walk(func(action *model.PlannedAction) {
+		g.actions[approvalKey(action.Kind, action.Name)] = true
+	})
+	return g
+}
+
+// approvalKey builds key of the action's object
+func approvalKey(kind model.EntityType, name string) string {
+	return fmt.Sprintf("%s %s", kind, name)
+}
+
+// isPending checks whether there are disruptive actions pending approval
+func (g *approvalGate) isPending() bool {
+	return (g != nil) && (len(g.actions) > 0)
+}
+
+// blocksHost checks whether the host has disruptive actions pending approval - either restart or StatefulSet update
+func (g *approvalGate) blocksHost(host *api.ChiHost) bool {
+	if !g.isPending() {
+		return false
+	}
+	statefulSet := meta.ObjectMeta{
+		Namespace: host.Runtime.Address.Namespace,
+		Name:      model.CreateStatefulSetName(host),
+	}
+	return g.actions[approvalKey(model.Host, host.GetName())] ||
+		g.actions[approvalKey(model.StatefulSet, util.NamespaceNameString(statefulSet))]
+}
+
+// checkApproval builds reconcile plan of the CHI and holds its disruptive actions unless the plan is approved.
+// Plan is approved by either annotating CHI with plan's hash or by setting plan's hash into status.approval.approved
+func (w *worker) checkApproval(ctx context.Context, chi *api.ClickHouseInstallation, ap *model.ActionPlan) {
+	if util.IsContextDone(ctx) {
+		log.V(2).Info("task is done")
+		return
+	}
+
+	if !chop.Config().IsApproveMode() {
+		return
+	}
+
+	w.gateDisruptiveActions(chi, w.buildPlan(ctx, chi, ap).Disruptive())
+}
+
+// gateDisruptiveActions holds disruptive actions of the plan unless the plan is approved
+func (w *worker) gateDisruptiveActions(chi *api.ClickHouseInstallation, disruptive *model.Plan) {
+	plan := disruptive.Hash()
+
+	switch {
+	case disruptive.Len() == 0:
+		chi.EnsureStatus().SetApproval(nil)
+		chi.EnsureStatus().SetCondition(
+			api.NewChiCondition(api.ConditionPlanApproved, api.ConditionTrue, approvalReasonNotRequired, ""),
+		)
+	case isPlanApproved(chi, plan):
+		w.a.V(1).M(chi).F().Info("disruptive actions of plan %s are approved:\n%s", plan, disruptive)
+		chi.EnsureStatus().SetApproval(nil)
+		chi.EnsureStatus().SetCondition(
+			api.NewChiCondition(api.ConditionPlanApproved, api.ConditionTrue, approvalReasonApproved, plan),
+		)
+	default:
+		w.task.approval = newApprovalGate(disruptive)
+		approval := api.NewChiApproval(plan, disruptive.Lines())
+		approval.Approved = chi.EnsureStatus().GetApproval().GetApproved()
+		chi.EnsureStatus().SetApproval(approval)
+		message := fmt.Sprintf("%d disruptive actions pending approval, annotate CHI with %s=%s to approve",
+			disruptive.Len(), model.AnnotationApprovedPlan, plan)
+		chi.EnsureStatus().SetCondition(
+			api.NewChiCondition(api.ConditionPlanApproved, api.ConditionFalse, approvalReasonPending, message),
+		)
+		w.a.V(1).WithEvent(chi, eventActionReconcile, eventReasonReconcileInProgress).
+			M(chi).F().
+			Warning("%s:\n%s", message, disruptive)
+	}
+}
+
+// isPendingPlanApproved checks whether plan pending approval is approved already, so its actions are to be applied
+func isPendingPlanApproved(chi *api.ClickHouseInstallation) bool {
+	if !chop.Config().IsApproveMode() {
+		return false
+	}
+	return isPlanApproved(chi, chi.EnsureStatus().GetApproval().GetPlan())
+}
+
+// isPlanApproved checks whether plan is approved either by annotation or by status
+func isPlanApproved(chi *api.ClickHouseInstallation, plan string) bool {
+	if plan == "" {
+		return false
+	}
+	return (chi.Annotations[model.AnnotationApprovedPlan] == plan) || chi.EnsureStatus().GetApproval().IsApproved(plan)
+}
+
+// isApprovalChanged checks whether user has approved a plan, which requires reconcile even for the same generation
+func (w *worker) isApprovalChanged(old, new *api.ClickHouseInstallation) bool {
+	if !chop.Config().IsApproveMode() || !w.areUsableOldAndNew(old, new) {
+		return false
+	}
+
+	oldApproved := old.Annotations[model.AnnotationApprovedPlan]
+	newApproved := new.Annotations[model.AnnotationApprovedPlan]
+	if (newApproved != "") && (newApproved != oldApproved) {
+		return true
+	}
+
+	oldApproved = old.EnsureStatus().GetApproval().GetApproved()
+	newApproved = new.EnsureStatus().GetApproval().GetApproved()
+	return (newApproved != "") && (newApproved != oldApproved)
+}
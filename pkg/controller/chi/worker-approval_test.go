@@ -0,0 +1,95 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"context"
+	"testing"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/apis/deployment"
+	"github.com/altinity/clickhouse-operator/pkg/chop"
+	model "github.com/altinity/clickhouse-operator/pkg/model/chi"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/builder"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/render"
+)
+
+func TestApprovedPlanIsReconciled(t *testing.T) {
+	render.Init("")
+	config := chop.Config()
+	saved := config.Reconcile.Mode
+	config.Reconcile.Mode = api.ReconcileModeApprove
+	defer func() { config.Reconcile.Mode = saved }()
+
+	ctx := context.Background()
+	w := &worker{a: NewAnnouncer()}
+	disruptive := model.NewPlan().Add(model.PlannedActionRestart, model.Host, "0-0", "configuration change requires restart")
+	plan := disruptive.Hash()
+
+	// Reconcile of the generation completes with disruptive actions held
+	held := builder.NewCHI("test", "approval", builder.WithCluster(builder.NewCluster("main")))
+	held.Generation = 2
+	ip, _ := chop.Get().ConfigManager.GetRuntimeParam(deployment.OPERATOR_POD_IP)
+	held.EnsureStatus().Fill(&api.FillStatusParams{CHOpIP: ip})
+	w.gateDisruptiveActions(held, disruptive)
+	if !w.task.approval.isPending() || (held.EnsureStatus().GetApproval().GetPlan() != plan) {
+		t.Fatalf("plan %s has to be held pending approval", plan)
+	}
+	held.EnsureStatus().SetObservedGeneration(held.Generation)
+	held.EnsureStatus().PushDependency(model.CreateMetadataDependency(held))
+	held.EnsureStatus().ReconcileComplete()
+	if !w.isCHIObserved(ctx, held) {
+		t.Errorf("generation with plan held has to be observed")
+	}
+
+	// Approval of another plan does not trigger reconcile
+	stale := held.DeepCopy()
+	stale.Annotations = map[string]string{model.AnnotationApprovedPlan: "stale"}
+	if !w.isCHIObserved(ctx, stale) {
+		t.Errorf("generation with another plan approved has to be observed")
+	}
+
+	approvedByAnnotation := held.DeepCopy()
+	approvedByAnnotation.Annotations = map[string]string{model.AnnotationApprovedPlan: plan}
+	approvedByStatus := held.DeepCopy()
+	approvedByStatus.EnsureStatus().GetApproval().Approved = plan
+
+	for name, approved := range map[string]*api.ClickHouseInstallation{
+		"annotation": approvedByAnnotation,
+		"status":     approvedByStatus,
+	} {
+		// Approval does not change generation, still the CHI has to be reconciled
+		if w.isCHIObserved(ctx, approved) {
+			t.Errorf("%s: generation with held plan approved must not be observed", name)
+		}
+		if !w.isApprovalChanged(held, approved) {
+			t.Errorf("%s: approval has to be noticed by reconcile", name)
+		}
+
+		// Reconcile of the approved CHI applies disruptive actions of the plan
+		w.task = task{}
+		w.gateDisruptiveActions(approved, disruptive)
+		if w.task.approval.isPending() {
+			t.Errorf("%s: approved plan must not be held", name)
+		}
+		if approved.EnsureStatus().GetApproval() != nil {
+			t.Errorf("%s: approval has to be cleared once plan is applied", name)
+		}
+		approved.EnsureStatus().ReconcileComplete()
+		if !w.isCHIObserved(ctx, approved) {
+			t.Errorf("%s: generation with approved plan applied has to be observed", name)
+		}
+	}
+}
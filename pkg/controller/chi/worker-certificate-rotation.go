@@ -0,0 +1,174 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	log "github.com/altinity/clickhouse-operator/pkg/announcer"
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/controller"
+	model "github.com/altinity/clickhouse-operator/pkg/model/chi"
+	"github.com/altinity/clickhouse-operator/pkg/model/k8s"
+	"github.com/altinity/clickhouse-operator/pkg/util"
+)
+
+// certificateRotationPropagationDelay specifies how long it takes kubelet to refresh mounted Secret,
+// hosts are reloaded only after new certificates have reached their pods
+const certificateRotationPropagationDelay = 2 * time.Minute
+
+// rotateCertificates rotates managed certificates of clusters of the already reconciled CHI, which are close to expiry.
+// New certificate is signed by the same CA, so hosts keep trusting each other while they are reloaded one by one.
+// Within a shard hosts are reloaded one at a time and only in case all replicas of the shard are ready, so the shard keeps quorum.
+func (w *worker) rotateCertificates(ctx context.Context, chi *api.ClickHouseInstallation) {
+	if util.IsContextDone(ctx) {
+		log.V(2).Info("task is done")
+		return
+	}
+
+	// Ancestor is the CHI as it was reconciled the last time
+	if !chi.HasAncestor() || chi.IsStopped() {
+		return
+	}
+	normalized := w.normalize(chi.GetAncestor())
+
+	now := time.Now()
+	normalized.WalkClusters(func(cluster *api.Cluster) error {
+		if !cluster.TLS.IsManaged() {
+			return nil
+		}
+		rotation, found := chi.EnsureStatus().GetClusterCertificateRotation(cluster.Name)
+		if !found || !rotation.IsInProgress() {
+			var started bool
+			if rotation, started = w.startCertificateRotation(ctx, cluster, now); !started {
+				return nil
+			}
+			w.updateCertificateRotationStatus(ctx, chi, rotation)
+		}
+		if w.reloadCertificates(ctx, cluster, &rotation, now) {
+			w.updateCertificateRotationStatus(ctx, chi, rotation)
+		}
+		return nil
+	})
+}
+
+// startCertificateRotation issues new certificate of the cluster, in case the current one is close to expiry
+func (w *worker) startCertificateRotation(ctx context.Context, cluster *api.Cluster, now time.Time) (api.ChiCertificateRotation, bool) {
+	namespace, name := cluster.Runtime.Address.Namespace, model.CreateClusterTLSSecretName(cluster)
+	secret, err := w.c.kubeClient.CoreV1().Secrets(namespace).Get(ctx, name, controller.NewGetOptions())
+	if err != nil {
+		w.a.V(1).M(cluster).F().Warning("unable to get TLS Secret %s/%s err: %v", namespace, name, err)
+		return api.ChiCertificateRotation{}, false
+	}
+	if notAfter, err := model.GetCertificateNotAfter(secret.Data); (err == nil) && now.Add(cluster.TLS.GetRenewBefore()).Before(notAfter) {
+		// Certificate is not due for rotation yet
+		return api.ChiCertificateRotation{}, false
+	}
+
+	data, err := model.CreateClusterCertificate(cluster, secret.Data, now)
+	if err != nil {
+		w.a.V(1).M(cluster).F().Error("unable to issue certificate of the cluster: %s err: %v", cluster.Name, err)
+		return api.ChiCertificateRotation{}, false
+	}
+	client, err := w.c.kube(namespace)
+	if err != nil {
+		return api.ChiCertificateRotation{}, false
+	}
+	secret = secret.DeepCopy()
+	secret.Data = data
+	if _, err := client.CoreV1().Secrets(namespace).Update(ctx, secret, controller.NewUpdateOptions()); err != nil {
+		w.a.V(1).M(cluster).F().Error("unable to update TLS Secret %s/%s err: %v", namespace, name, err)
+		return api.ChiCertificateRotation{}, false
+	}
+
+	notAfter, _ := model.GetCertificateNotAfter(data)
+	w.a.V(1).
+		WithEvent(cluster.Runtime.CHI, eventActionUpdate, eventReasonUpdateStarted).
+		M(cluster).F().
+		Info("certificate of the cluster %s is rotated, valid till %s", cluster.Name, notAfter.Format(time.RFC3339))
+	return api.NewChiCertificateRotation(cluster.Name, notAfter), true
+}
+
+// reloadCertificates reloads hosts of the cluster which are not reloaded with the new certificate yet.
+// Returns whether rotation has changed
+func (w *worker) reloadCertificates(ctx context.Context, cluster *api.Cluster, rotation *api.ChiCertificateRotation, now time.Time) bool {
+	if now.Sub(rotation.GetStartTime()) < certificateRotationPropagationDelay {
+		// Pods may still have the previous certificate mounted
+		return false
+	}
+
+	prev := rotation.DeepCopy()
+	rotation.Error = ""
+	pending := false
+	cluster.WalkShards(func(index int, shard *api.ChiShard) error {
+		for _, host := range shard.Hosts {
+			if rotation.IsHostReloaded(host.GetName()) || host.IsStopped() {
+				// Stopped host picks the new certificate up on start
+				continue
+			}
+			if util.IsContextDone(ctx) {
+				pending = true
+				return nil
+			}
+			if err := w.reloadHostCertificate(ctx, shard, host); err != nil {
+				// Remaining hosts of the shard are left till the next run
+				rotation.Error = err.Error()
+				pending = true
+				return nil
+			}
+			rotation.Hosts = append(rotation.Hosts, host.GetName())
+		}
+		return nil
+	})
+
+	if !pending {
+		rotation.Status = api.CertificateRotationStatusCompleted
+		w.a.V(1).
+			WithEvent(cluster.Runtime.CHI, eventActionUpdate, eventReasonUpdateCompleted).
+			M(cluster).F().
+			Info("certificate rotation of the cluster %s is completed", cluster.Name)
+	}
+
+	return (rotation.Status != prev.Status) || (rotation.Error != prev.Error) || (len(rotation.Hosts) != len(prev.Hosts))
+}
+
+// reloadHostCertificate reloads the host in case all replicas of its shard are ready
+func (w *worker) reloadHostCertificate(ctx context.Context, shard *api.ChiShard, host *api.ChiHost) error {
+	for _, replica := range shard.Hosts {
+		if replica.IsStopped() {
+			continue
+		}
+		if pod, err := w.c.getPod(replica); (err != nil) || !k8s.IsPodReady(pod) {
+			return fmt.Errorf("host %s is not ready, shard %s can not afford reload of host %s", replica.GetName(), shard.Name, host.GetName())
+		}
+	}
+	if err := w.ensureClusterSchemer(host).HostReloadConfig(ctx, host); err != nil {
+		return fmt.Errorf("unable to reload host %s err: %v", host.GetName(), err)
+	}
+	return nil
+}
+
+// updateCertificateRotationStatus stores certificate rotation of a cluster in the CHI status
+func (w *worker) updateCertificateRotationStatus(ctx context.Context, chi *api.ClickHouseInstallation, rotation api.ChiCertificateRotation) {
+	chi.EnsureStatus().SetCertificateRotation(rotation)
+	_ = w.c.updateCHIObjectStatus(ctx, chi, UpdateCHIStatusOptions{
+		TolerateAbsence: true,
+		CopyCHIStatusOptions: api.CopyCHIStatusOptions{
+			CertificateRotations: true,
+		},
+	})
+}
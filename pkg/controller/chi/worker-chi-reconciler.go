@@ -51,6 +51,12 @@ func (w *worker) reconcileCHI(ctx context.Context, old, new *api.ClickHouseInsta
 	switch {
 	case w.isAfterFinalizerInstalled(old, new):
 		w.a.M(new).F().Info("isAfterFinalizerInstalled - continue reconcile-1")
+	case w.isApprovalChanged(old, new):
+		w.a.M(new).F().Info("isApprovalChanged - continue reconcile-1")
+	case w.isRestartRequested(new):
+		w.a.M(new).F().Info("isRestartRequested - continue reconcile-1")
+	case w.isFreezeLifted(new):
+		w.a.M(new).F().Info("isFreezeLifted - continue reconcile-1")
 	case w.isGenerationTheSame(old, new):
 		w.a.M(new).F().Info("isGenerationTheSame() - nothing to do here, exit")
 		return nil
@@ -89,6 +95,8 @@ func (w *worker) reconcileCHI(ctx context.Context, old, new *api.ClickHouseInsta
 		w.a.M(new).F().Info("ActionPlan has actions - continue reconcile")
 	case w.isAfterFinalizerInstalled(old, new):
 		w.a.M(new).F().Info("isAfterFinalizerInstalled - continue reconcile-2")
+	case w.isRestartRequested(new):
+		w.a.M(new).F().Info("isRestartRequested - continue reconcile-2")
 	default:
 		w.a.M(new).F().Info("ActionPlan has no actions and not finalizer - nothing to do")
 		return nil
@@ -101,17 +109,43 @@ func (w *worker) reconcileCHI(ctx context.Context, old, new *api.ClickHouseInsta
 
 	w.newTask(new)
 	w.markReconcileStart(ctx, new, actionPlan)
+	upgrade := getClickHouseUpgrade(old, new)
+	if upgrade != "" {
+		w.c.notify(new, notificationUpgradeStarted, "", upgrade)
+	}
 	w.excludeStoppedCHIFromMonitoring(new)
 	w.walkHosts(ctx, new, actionPlan)
+	w.checkApproval(ctx, new, actionPlan)
+	w.checkFreeze(ctx, new, actionPlan)
+	w.checkPortConflicts(ctx, new)
+	w.fillNodeAddresses(ctx, new)
+
+	err := w.checkPolicy(ctx, new)
+	if err == nil {
+		err = w.preflight(ctx, new)
+	}
+	if err == nil {
+		err = w.drainRemovedShards(ctx, new)
+	}
+	if err == nil {
+		w.waitCoordination(ctx, new)
+		err = w.reconcile(ctx, new)
+	}
 
-	if err := w.reconcile(ctx, new); err != nil {
+	if err != nil {
 		// Something went wrong
 		w.a.WithEvent(new, eventActionReconcile, eventReasonReconcileFailed).
 			WithStatusError(new).
 			M(new).F().
 			Error("FAILED to reconcile CHI err: %v", err)
 		w.markReconcileCompletedUnsuccessfully(ctx, new, err)
-		if errors.Is(err, errCRUDAbort) {
+		if upgrade != "" {
+			w.c.notify(new, notificationUpgradeFailed, "", fmt.Sprintf("%s: %v", upgrade, err))
+		}
+		if errors.Is(err, errDrainFailed) {
+			w.c.notify(new, notificationScaleDownBlocked, "", err.Error())
+		}
+		if errors.Is(err, errCRUDAbort) || errors.Is(err, errPreflightFailed) || errors.Is(err, errPolicyRejected) || errors.Is(err, errDrainFailed) {
 			metricsCHIReconcilesAborted(ctx)
 		}
 	} else {
@@ -121,11 +155,20 @@ func (w *worker) reconcileCHI(ctx context.Context, old, new *api.ClickHouseInsta
 			log.V(2).Info("task is done")
 			return nil
 		}
-		w.clean(ctx, new)
-		w.dropReplicas(ctx, new, actionPlan)
+		if w.task.approval.isPending() {
+			w.a.V(1).M(new).F().Info("disruptive actions are pending approval, skip clean and drop replicas")
+		} else {
+			w.clean(ctx, new)
+			w.dropReplicas(ctx, new, actionPlan)
+		}
 		w.addCHIToMonitoring(new)
 		w.waitForIPAddresses(ctx, new)
 		w.finalizeReconcileAndMarkCompleted(ctx, new)
+		w.startRebalancing(ctx, new)
+
+		if upgrade != "" {
+			w.c.notify(new, notificationUpgradeCompleted, "", upgrade)
+		}
 
 		metricsCHIReconcilesCompleted(ctx)
 		metricsCHIReconcilesTimings(ctx, time.Now().Sub(startTime).Seconds())
@@ -195,6 +238,10 @@ func (w *worker) reconcileCHIAuxObjectsPreliminary(ctx context.Context, chi *api
 	if err := w.reconcileCHIConfigMapUsers(ctx, chi); err != nil {
 		w.a.F().Error("failed to reconcile config map users. err: %v", err)
 	}
+	// 4. CHI logging ConfigMap
+	if err := w.reconcileCHIConfigMapLogging(ctx, chi); err != nil {
+		w.a.F().Error("failed to reconcile config map logging. err: %v", err)
+	}
 
 	return nil
 }
@@ -239,7 +286,34 @@ func (w *worker) reconcileCHIAuxObjectsFinal(ctx context.Context, chi *api.Click
 	defer w.a.V(2).M(chi).E().P()
 
 	// CHI ConfigMaps with update
-	return w.reconcileCHIConfigMapCommon(ctx, chi, nil)
+	if err := w.reconcileCHIConfigMapCommon(ctx, chi, nil); err != nil {
+		return err
+	}
+
+	// Connection details, topology and chproxy refer to hosts, which are in place by now
+	if err := w.reconcileConnectionSecret(ctx, chi); err != nil {
+		return err
+	}
+	if err := w.reconcileCHIConfigMapTopology(ctx, chi); err != nil {
+		return err
+	}
+	return w.reconcileChproxy(ctx, chi)
+}
+
+// reconcileConnectionSecret reconciles Secret with connection details of the CHI
+func (w *worker) reconcileConnectionSecret(ctx context.Context, chi *api.ClickHouseInstallation) error {
+	if util.IsContextDone(ctx) {
+		log.V(2).Info("task is done")
+		return nil
+	}
+
+	secret := w.task.creator.CreateSecretConnection()
+	if err := w.reconcileSecretData(ctx, secret); err != nil {
+		w.task.registryFailed.RegisterSecret(secret.ObjectMeta)
+		return err
+	}
+	w.task.registryReconciled.RegisterSecret(secret.ObjectMeta)
+	return nil
 }
 
 // reconcileCHIConfigMapCommon reconciles all CHI's common ConfigMap
@@ -285,6 +359,43 @@ func (w *worker) reconcileCHIConfigMapUsers(ctx context.Context, chi *api.ClickH
 	return err
 }
 
+// reconcileCHIConfigMapLogging reconciles CHI's log shipping agent ConfigMap, in case logging is enabled
+func (w *worker) reconcileCHIConfigMapLogging(ctx context.Context, chi *api.ClickHouseInstallation) error {
+	if util.IsContextDone(ctx) {
+		log.V(2).Info("task is done")
+		return nil
+	}
+
+	configMap := w.task.creator.CreateConfigMapCHILogging()
+	if configMap == nil {
+		return nil
+	}
+	err := w.reconcileConfigMap(ctx, chi, configMap)
+	if err == nil {
+		w.task.registryReconciled.RegisterConfigMap(configMap.ObjectMeta)
+	} else {
+		w.task.registryFailed.RegisterConfigMap(configMap.ObjectMeta)
+	}
+	return err
+}
+
+// reconcileCHIConfigMapTopology reconciles ConfigMap with cluster topology published for external consumers
+func (w *worker) reconcileCHIConfigMapTopology(ctx context.Context, chi *api.ClickHouseInstallation) error {
+	if util.IsContextDone(ctx) {
+		log.V(2).Info("task is done")
+		return nil
+	}
+
+	configMap := w.task.creator.CreateConfigMapCHITopology()
+	err := w.reconcileConfigMap(ctx, chi, configMap)
+	if err == nil {
+		w.task.registryReconciled.RegisterConfigMap(configMap.ObjectMeta)
+	} else {
+		w.task.registryFailed.RegisterConfigMap(configMap.ObjectMeta)
+	}
+	return err
+}
+
 // reconcileHostConfigMap reconciles host's personal ConfigMap
 func (w *worker) reconcileHostConfigMap(ctx context.Context, host *api.ChiHost) error {
 	if util.IsContextDone(ctx) {
@@ -472,8 +583,17 @@ func (w *worker) reconcileCluster(ctx context.Context, cluster *api.Cluster) err
 	w.a.V(2).M(cluster).S().P()
 	defer w.a.V(2).M(cluster).E().P()
 
-	// Add ChkCluster's Service
-	if service := w.task.creator.CreateServiceCluster(cluster); service != nil {
+	// Add ChkCluster's Services
+	for _, service := range []*core.Service{
+		w.task.creator.CreateServiceCluster(cluster),
+		w.task.creator.CreateServiceClusterReadTier(cluster),
+		w.task.creator.CreateServiceClusterReader(cluster),
+		w.task.creator.CreateServiceClusterWriter(cluster),
+	} {
+		if service == nil {
+			// This is not a problem, cluster Services may be omitted
+			continue
+		}
 		if err := w.reconcileService(ctx, cluster.Runtime.CHI, service); err == nil {
 			w.task.registryReconciled.RegisterService(service.ObjectMeta)
 		} else {
@@ -483,7 +603,18 @@ func (w *worker) reconcileCluster(ctx context.Context, cluster *api.Cluster) err
 
 	// Add ChkCluster's Auto Secret
 	if cluster.Secret.Source() == api.ClusterSecretSourceAuto {
-		if secret := w.task.creator.CreateClusterSecret(model.CreateClusterAutoSecretName(cluster)); secret != nil {
+		if secret := w.task.creator.CreateClusterSecret(cluster); secret != nil {
+			if err := w.reconcileSecret(ctx, cluster.Runtime.CHI, secret); err == nil {
+				w.task.registryReconciled.RegisterSecret(secret.ObjectMeta)
+			} else {
+				w.task.registryFailed.RegisterSecret(secret.ObjectMeta)
+			}
+		}
+	}
+
+	// Add ChkCluster's managed TLS Secret. Existing certificates are kept intact, they are renewed by rotation
+	if cluster.TLS.IsManaged() {
+		if secret := w.task.creator.CreateClusterTLSSecret(cluster); secret != nil {
 			if err := w.reconcileSecret(ctx, cluster.Runtime.CHI, secret); err == nil {
 				w.task.registryReconciled.RegisterSecret(secret.ObjectMeta)
 			} else {
@@ -550,6 +681,21 @@ func (w *worker) reconcileShardsAndHosts(ctx context.Context, shards []*api.ChiS
 		opts = &ReconcileShardsAndHostsOptions{}
 	}
 
+	// Hosts of a zoned cluster are updated zone by zone, so a bad change is contained within one zone
+	if zones := getReconcileZones(shards); !opts.FullFanOut() && (len(zones) > 1) {
+		return w.reconcileShardsAndHostsByZones(ctx, shards, zones, opts)
+	}
+
+	return w.reconcileShardsWith(ctx, shards, opts, w.reconcileShardWithHosts)
+}
+
+// reconcileShardsWith runs specified reconcile function on each shard with respect to concurrency settings
+func (w *worker) reconcileShardsWith(
+	ctx context.Context,
+	shards []*api.ChiShard,
+	opts *ReconcileShardsAndHostsOptions,
+	f func(ctx context.Context, shard *api.ChiShard) error,
+) error {
 	// Which shard to start concurrent processing with
 	var startShard int
 	if opts.FullFanOut() {
@@ -561,7 +707,7 @@ func (w *worker) reconcileShardsAndHosts(ctx context.Context, shards []*api.ChiS
 		// This gives us some early indicator on whether the reconciliation would fail,
 		// and for large clusters it is a small price to pay before performing concurrent fan-out.
 		w.a.V(1).Info("starting first shard separately")
-		if err := w.reconcileShardWithHosts(ctx, shards[0]); err != nil {
+		if err := f(ctx, shards[0]); err != nil {
 			w.a.V(1).Warning("first shard failed, skipping rest of shards due to an error: %v", err)
 			return err
 		}
@@ -592,7 +738,7 @@ func (w *worker) reconcileShardsAndHosts(ctx context.Context, shards []*api.ChiS
 			shard := concurrentlyProcessedShards[j]
 			go func() {
 				defer wg.Done()
-				if e := w.reconcileShardWithHosts(ctx, shard); e != nil {
+				if e := f(ctx, shard); e != nil {
 					errLock.Lock()
 					err = e
 					errLock.Unlock()
@@ -613,8 +759,8 @@ func (w *worker) reconcileShardWithHosts(ctx context.Context, shard *api.ChiShar
 	if err := w.reconcileShard(ctx, shard); err != nil {
 		return err
 	}
-	for replicaIndex := range shard.Hosts {
-		host := shard.Hosts[replicaIndex]
+	// Read tier hosts are updated last, so reads are served by them while write tier is rolled out
+	for _, host := range shard.HostsReadTierLast() {
 		if err := w.reconcileHost(ctx, host); err != nil {
 			return err
 		}
@@ -662,14 +808,26 @@ func (w *worker) reconcileHost(ctx context.Context, host *api.ChiHost) error {
 	w.a.V(2).M(host).S().P()
 	defer w.a.V(2).M(host).E().P()
 
-	metricsHostReconcilesStarted(ctx)
-	startTime := time.Now()
-
 	if host.IsFirst() {
 		w.reconcileCHIServicePreliminary(ctx, host.GetCHI())
 		defer w.reconcileCHIServiceFinal(ctx, host.GetCHI())
 	}
 
+	// Skipped hosts are not accounted as started, since they are never completed
+	if w.task.approval.blocksHost(host) {
+		w.a.V(1).M(host).F().Info("Reconcile Host skipped, disruptive actions are pending approval. Host: %s", host.GetName())
+		return nil
+	}
+
+	if host.IsFrozen() {
+		w.a.V(1).M(host).F().Info("Reconcile Host skipped, shard is frozen. Host: %s", host.GetName())
+		w.registerFrozenHost(host, w.task.registryReconciled)
+		return nil
+	}
+
+	metricsHostReconcilesStarted(ctx)
+	startTime := time.Now()
+
 	// Check whether ClickHouse is running and accessible and what version is available
 	if version, err := w.getHostClickHouseVersion(ctx, host, versionOptions{skipNew: true, skipStoppedAncestor: true}); err == nil {
 		w.a.V(1).
@@ -688,6 +846,9 @@ func (w *worker) reconcileHost(ctx context.Context, host *api.ChiHost) error {
 	// Create artifacts
 	w.prepareHostStatefulSetWithStatus(ctx, host, false)
 
+	// Results of smoke test queries are compared with the ones taken before the update
+	smokeTestBaseline := w.runSmokeTestBaseline(ctx, host)
+
 	if err := w.excludeHost(ctx, host); err != nil {
 		metricsHostReconcilesErrors(ctx)
 		w.a.V(1).
@@ -706,6 +867,9 @@ func (w *worker) reconcileHost(ctx context.Context, host *api.ChiHost) error {
 		return err
 	}
 
+	// Volumes of the forked host have to be in place before StatefulSet is created
+	forked := w.forkHostVolumes(ctx, host)
+
 	w.a.V(1).
 		M(host).F().
 		Info("Reconcile PVCs and check possible data loss for host: %s", host.GetName())
@@ -751,7 +915,18 @@ func (w *worker) reconcileHost(ctx context.Context, host *api.ChiHost) error {
 			M(host).F().
 			Warning("Check host for ClickHouse availability before migrating tables. Host: %s Failed to get ClickHouse version: %s", host.GetName(), version)
 	}
-	_ = w.migrateTables(ctx, host, migrateTableOpts)
+	w.reloadHostConfiguration(ctx, host)
+	if err := w.migrateTables(ctx, host, migrateTableOpts); (err != nil) && w.shouldWaitTables() {
+		// Host without tables would fail distributed queries and queries coming through services
+		metricsHostReconcilesErrors(ctx)
+		w.a.V(1).
+			M(host).F().
+			Warning("Reconcile Host interrupted, host is not included until tables are created. Host: %s Err: %v", host.GetName(), err)
+		return err
+	}
+	if forked {
+		w.restoreForkedReplicas(ctx, host)
+	}
 
 	if err := w.includeHost(ctx, host); err != nil {
 		metricsHostReconcilesErrors(ctx)
@@ -775,6 +950,17 @@ func (w *worker) reconcileHost(ctx context.Context, host *api.ChiHost) error {
 			WithStatusAction(host.GetCHI()).
 			M(host).F().
 			Warning("Reconcile Host completed. Host: %s Failed to get ClickHouse version: %s", host.GetName(), version)
+		w.c.notify(host.GetCHI(), notificationHostUnhealthy, host.GetName(), "ClickHouse is not alive after reconcile")
+	}
+
+	if err := w.runSmokeTest(ctx, host, smokeTestBaseline); err != nil {
+		metricsHostReconcilesErrors(ctx)
+		w.a.V(1).
+			WithEvent(host.GetCHI(), eventActionReconcile, eventReasonReconcileFailed).
+			WithStatusAction(host.GetCHI()).
+			M(host).F().
+			Warning("Reconcile Host interrupted, smoke test failed. Host: %s Err: %v", host.GetName(), err)
+		return err
 	}
 
 	now := time.Now()
@@ -803,13 +989,33 @@ func (w *worker) reconcileHost(ctx context.Context, host *api.ChiHost) error {
 	return nil
 }
 
+// registerFrozenHost registers objects of the frozen host, so they are kept as they are and not purged as unknown objects
+func (w *worker) registerFrozenHost(host *api.ChiHost, registry *model.Registry) {
+	statefulSet := w.task.creator.CreateStatefulSet(host, false)
+	registry.RegisterStatefulSet(statefulSet.ObjectMeta)
+	for _, pvc := range getHostPVCs(host, statefulSet) {
+		registry.RegisterPVC(pvc)
+	}
+	if configMap := w.task.creator.CreateConfigMapHost(host); configMap != nil {
+		registry.RegisterConfigMap(configMap.ObjectMeta)
+	}
+	if service := w.task.creator.CreateServiceHost(host); service != nil {
+		registry.RegisterService(service.ObjectMeta)
+	}
+}
+
 // reconcilePDB reconciles PodDisruptionBudget
 func (w *worker) reconcilePDB(ctx context.Context, cluster *api.Cluster, pdb *policy.PodDisruptionBudget) error {
+	client, err := w.c.kube(pdb.Namespace)
+	if err != nil {
+		return err
+	}
+
 	cur, err := w.c.kubeClient.PolicyV1().PodDisruptionBudgets(pdb.Namespace).Get(ctx, pdb.Name, controller.NewGetOptions())
 	switch {
 	case err == nil:
 		pdb.ResourceVersion = cur.ResourceVersion
-		_, err := w.c.kubeClient.PolicyV1().PodDisruptionBudgets(pdb.Namespace).Update(ctx, pdb, controller.NewUpdateOptions())
+		_, err := client.PolicyV1().PodDisruptionBudgets(pdb.Namespace).Update(ctx, pdb, controller.NewUpdateOptions())
 		if err == nil {
 			log.V(1).Info("PDB updated: %s/%s", pdb.Namespace, pdb.Name)
 		} else {
@@ -817,7 +1023,7 @@ func (w *worker) reconcilePDB(ctx context.Context, cluster *api.Cluster, pdb *po
 			return nil
 		}
 	case apiErrors.IsNotFound(err):
-		_, err := w.c.kubeClient.PolicyV1().PodDisruptionBudgets(pdb.Namespace).Create(ctx, pdb, controller.NewCreateOptions())
+		_, err := client.PolicyV1().PodDisruptionBudgets(pdb.Namespace).Create(ctx, pdb, controller.NewCreateOptions())
 		if err == nil {
 			log.V(1).Info("PDB created: %s/%s", pdb.Namespace, pdb.Name)
 		} else {
@@ -937,9 +1143,9 @@ func (w *worker) reconcileSecret(ctx context.Context, chi *api.ClickHouseInstall
 	defer w.a.V(2).M(chi).E().Info(secret.Name)
 
 	// Check whether this object already exists
-	if _, err := w.c.getSecret(secret); err == nil {
-		// We have Secret - try to update it
-		return nil
+	if cur, err := w.c.getSecret(secret); err == nil {
+		// We have Secret - adopt it, keeping secret data intact
+		return w.adoptSecret(ctx, chi, cur, secret)
 	}
 
 	// Secret not found or broken. Try to recreate
@@ -956,6 +1162,67 @@ func (w *worker) reconcileSecret(ctx context.Context, chi *api.ClickHouseInstall
 	return err
 }
 
+// reconcileSecretData reconciles Secret with data generated by the operator, such as chproxy config.
+// Unlike cluster secrets, data is kept up to date
+func (w *worker) reconcileSecretData(ctx context.Context, secret *core.Secret) error {
+	client, err := w.c.kube(secret.Namespace)
+	if err != nil {
+		return err
+	}
+
+	cur, err := w.c.kubeClient.CoreV1().Secrets(secret.Namespace).Get(ctx, secret.Name, controller.NewGetOptions())
+	switch {
+	case err == nil:
+		secret.ResourceVersion = cur.ResourceVersion
+		_, err := client.CoreV1().Secrets(secret.Namespace).Update(ctx, secret, controller.NewUpdateOptions())
+		if err == nil {
+			log.V(1).Info("Secret updated: %s/%s", secret.Namespace, secret.Name)
+		} else {
+			log.Error("FAILED to update Secret: %s/%s err: %v", secret.Namespace, secret.Name, err)
+			return err
+		}
+	case apiErrors.IsNotFound(err):
+		_, err := client.CoreV1().Secrets(secret.Namespace).Create(ctx, secret, controller.NewCreateOptions())
+		if err == nil {
+			log.V(1).Info("Secret created: %s/%s", secret.Namespace, secret.Name)
+		} else {
+			log.Error("FAILED create Secret: %s/%s err: %v", secret.Namespace, secret.Name, err)
+			return err
+		}
+	default:
+		log.Error("FAILED get Secret: %s/%s err: %v", secret.Namespace, secret.Name, err)
+		return err
+	}
+
+	return nil
+}
+
+// adoptSecret makes existing Secret carry ownership labels and owner references of the desired Secret,
+// so Secrets created before ownership tracking are pruned as well
+func (w *worker) adoptSecret(ctx context.Context, chi *api.ClickHouseInstallation, cur, secret *core.Secret) error {
+	if util.MapHasKeys(cur.Labels, model.LabelSecret) && (len(cur.OwnerReferences) > 0) {
+		// Already adopted
+		return nil
+	}
+
+	cur = cur.DeepCopy()
+	cur.Labels = util.MergeStringMapsOverwrite(cur.Labels, secret.Labels)
+	if len(cur.OwnerReferences) == 0 {
+		cur.OwnerReferences = secret.OwnerReferences
+	}
+	client, err := w.c.kube(cur.Namespace)
+	if err != nil {
+		return err
+	}
+	if _, err := client.CoreV1().Secrets(cur.Namespace).Update(ctx, cur, controller.NewUpdateOptions()); err != nil {
+		w.a.V(1).M(chi).F().Warning("unable to adopt Secret %s/%s err: %v", cur.Namespace, cur.Name, err)
+		return err
+	}
+
+	w.a.V(1).M(chi).F().Info("Adopted Secret %s/%s", cur.Namespace, cur.Name)
+	return nil
+}
+
 func (w *worker) dumpStatefulSetDiff(host *api.ChiHost, cur, new *apps.StatefulSet) {
 	if cur == nil {
 		w.a.V(1).M(host).Info("Cur StatefulSet is not available, nothing to compare to")
@@ -1269,7 +1536,7 @@ func (w *worker) reconcilePVC(
 		return nil, fmt.Errorf("task is done")
 	}
 
-	w.applyPVCResourcesRequests(pvc, template)
+	w.applyPVCResourcesRequests(host, pvc, template)
 	pvc = w.task.creator.PreparePersistentVolumeClaim(pvc, host, template)
 	return w.c.updatePersistentVolumeClaim(ctx, pvc)
 }
@@ -0,0 +1,68 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/client-go/kubernetes/fake"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/builder"
+	chiCreator "github.com/altinity/clickhouse-operator/pkg/model/chi/creator"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/normalizer"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/render"
+)
+
+func TestReconcileFrozenHost(t *testing.T) {
+	render.Init("")
+	cluster := builder.NewCluster("main")
+	cluster.Layout = &api.ChiClusterLayout{
+		Shards: []api.ChiShard{{}, {Frozen: api.NewStringBool(true)}},
+	}
+	chi, err := normalizer.NewNormalizer(render.NoSecrets).CreateTemplatedCHI(
+		builder.NewCHI("test", "frozen", builder.WithCluster(cluster)),
+		normalizer.NewOptions(),
+	)
+	if err != nil {
+		t.Fatalf("unable to normalize err: %v", err)
+	}
+
+	client := fake.NewSimpleClientset()
+	w := &worker{c: &Controller{kubeClient: client}, a: NewAnnouncer()}
+	w.task = newTask(chiCreator.NewCreator(chi))
+
+	host := chi.FindCluster("main").GetShard(1).FirstHost()
+	if err := w.reconcileHost(context.Background(), host); err != nil {
+		t.Fatalf("unable to reconcile host err: %v", err)
+	}
+
+	// Objects of the frozen host are neither created nor updated, nor even read
+	if actions := client.Actions(); len(actions) > 0 {
+		t.Errorf("frozen host must not be touched, got actions %v", actions)
+	}
+	// However, they are registered as reconciled, so they are not purged as unknown objects
+	registry := w.task.registryReconciled
+	if !registry.HasStatefulSet(w.task.creator.CreateStatefulSet(host, false).ObjectMeta) {
+		t.Errorf("StatefulSet of the frozen host has to be registered")
+	}
+	if !registry.HasConfigMap(w.task.creator.CreateConfigMapHost(host).ObjectMeta) {
+		t.Errorf("ConfigMap of the frozen host has to be registered")
+	}
+	if !registry.HasService(w.task.creator.CreateServiceHost(host).ObjectMeta) {
+		t.Errorf("Service of the frozen host has to be registered")
+	}
+}
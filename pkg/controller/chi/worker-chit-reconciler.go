@@ -15,9 +15,13 @@
 package chi
 
 import (
+	"k8s.io/apimachinery/pkg/labels"
+
 	log "github.com/altinity/clickhouse-operator/pkg/announcer"
 	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
 	"github.com/altinity/clickhouse-operator/pkg/chop"
+	model "github.com/altinity/clickhouse-operator/pkg/model/chi"
+	templatesNormalizer "github.com/altinity/clickhouse-operator/pkg/model/chi/normalizer/templates"
 )
 
 func (w *worker) shouldUpdateCHITList() bool {
@@ -25,7 +29,7 @@ func (w *worker) shouldUpdateCHITList() bool {
 	switch chop.Config().Template.CHI.Policy {
 	case api.OperatorConfigCHIPolicyReadOnStart:
 		update = w.isJustStarted()
-	case api.OperatorConfigCHIPolicyApplyOnNextReconcile:
+	case api.OperatorConfigCHIPolicyApplyOnNextReconcile, api.OperatorConfigCHIPolicyApplyImmediately:
 		update = true
 	default:
 		update = false
@@ -38,6 +42,7 @@ func (w *worker) addChit(chit *api.ClickHouseInstallationTemplate) error {
 	if w.shouldUpdateCHITList() {
 		log.V(1).M(chit).F().Info("Add CHIT: %s/%s", chit.Namespace, chit.Name)
 		chop.Config().AddCHITemplate((*api.ClickHouseInstallation)(chit))
+		w.reconcileCHITDependents(chit)
 	} else {
 		log.V(1).M(chit).F().Info("CHIT will not be added: %s/%s", chit.Namespace, chit.Name)
 	}
@@ -56,6 +61,7 @@ func (w *worker) updateChit(old, new *api.ClickHouseInstallationTemplate) error
 	if w.shouldUpdateCHITList() {
 		log.V(1).M(new).F().Info("Update CHIT: %s/%s", new.Namespace, new.Name)
 		chop.Config().UpdateCHITemplate((*api.ClickHouseInstallation)(new))
+		w.reconcileCHITDependents(new)
 	} else {
 		log.V(1).M(new).F().Info("CHIT will not be updated: %s/%s", new.Namespace, new.Name)
 	}
@@ -69,8 +75,49 @@ func (w *worker) deleteChit(chit *api.ClickHouseInstallationTemplate) error {
 	if w.shouldUpdateCHITList() {
 		log.V(1).M(chit).F().Info("Delete CHIT: %s/%s", chit.Namespace, chit.Name)
 		chop.Config().DeleteCHITemplate((*api.ClickHouseInstallation)(chit))
+		w.reconcileCHITDependents(chit)
 	} else {
 		log.V(1).M(chit).F().Info("CHIT will not be deleted: %s/%s", chit.Namespace, chit.Name)
 	}
 	return nil
 }
+
+// reconcileCHITDependents enqueues reconcile of CHIs depending on the template, in case templates are applied immediately.
+// Reconcile of a CHI is skipped by the worker in case neither its spec nor the templates it depends on have changed.
+func (w *worker) reconcileCHITDependents(chit *api.ClickHouseInstallationTemplate) {
+	if chop.Config().Template.CHI.Policy != api.OperatorConfigCHIPolicyApplyImmediately {
+		return
+	}
+
+	chis, err := w.c.chiLister.List(labels.Everything())
+	if err != nil {
+		log.V(1).M(chit).F().Error("unable to list CHIs depending on CHIT: %s/%s err: %v", chit.Namespace, chit.Name, err)
+		return
+	}
+	for _, chi := range chis {
+		if !chop.Config().IsWatchedNamespace(chi.Namespace) || !chop.Config().IsWatchedLabels(chi.Labels) {
+			continue
+		}
+		if isCHITDependent(chi, chit) {
+			log.V(1).M(chi).F().Info("CHIT %s/%s changed, reconcile CHI: %s/%s", chit.Namespace, chit.Name, chi.Namespace, chi.Name)
+			w.c.enqueueObject(NewReconcileCHI(reconcileAdd, nil, chi.DeepCopy()))
+		}
+	}
+}
+
+// isCHITDependent checks whether the CHI was normalized with the template or the template is applicable to the CHI now
+func isCHITDependent(chi *api.ClickHouseInstallation, chit *api.ClickHouseInstallationTemplate) bool {
+	for _, observed := range chi.GetStatus().GetDependencies() {
+		dependency, ok := model.ParseDependency(observed)
+		if ok && (dependency.Kind == model.DependencyKindTemplate) &&
+			(dependency.Namespace == chit.Namespace) && (dependency.Name == chit.Name) {
+			return true
+		}
+	}
+	for _, template := range templatesNormalizer.ListApplicableTemplates(chi, true) {
+		if (template.Namespace == chit.Namespace) && (template.Name == chit.Name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,52 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"testing"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/chop"
+	model "github.com/altinity/clickhouse-operator/pkg/model/chi"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/builder"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/render"
+)
+
+func TestIsCHITDependent(t *testing.T) {
+	render.Init("")
+	used := builder.NewCHI("test", "used")
+	removed := builder.NewCHI("test", "removed")
+	other := builder.NewCHI("test", "other")
+	chop.Config().AddCHITemplate(used)
+	defer chop.Config().DeleteCHITemplate(used)
+
+	chi := builder.NewCHI("test", "chi", builder.WithUseTemplates("used"))
+	chi.EnsureStatus().PushDependency(model.CreateTemplateDependency(removed))
+
+	tests := []struct {
+		template  *api.ClickHouseInstallation
+		dependent bool
+	}{
+		{template: used, dependent: true},
+		{template: removed, dependent: true},
+		{template: other, dependent: false},
+	}
+	for _, test := range tests {
+		chit := (*api.ClickHouseInstallationTemplate)(test.template)
+		if dependent := isCHITDependent(chi, chit); dependent != test.dependent {
+			t.Errorf("%s: got dependent %v want %v", chit.Name, dependent, test.dependent)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"context"
+
+	apps "k8s.io/api/apps/v1"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+
+	log "github.com/altinity/clickhouse-operator/pkg/announcer"
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/controller"
+	"github.com/altinity/clickhouse-operator/pkg/util"
+)
+
+// reconcileChproxy reconciles chproxy config, Deployment and Service of the CHI.
+// Called after hosts are reconciled, so chproxy config lists hosts which are already in place.
+// Objects of disabled chproxy are not registered as reconciled, so they are purged.
+func (w *worker) reconcileChproxy(ctx context.Context, chi *api.ClickHouseInstallation) error {
+	if util.IsContextDone(ctx) {
+		log.V(2).Info("task is done")
+		return nil
+	}
+
+	if !chi.Spec.Chproxy.IsEnabled() {
+		return nil
+	}
+
+	w.a.V(2).M(chi).S().P()
+	defer w.a.V(2).M(chi).E().P()
+
+	if secret := w.task.creator.CreateSecretChproxy(); secret != nil {
+		if err := w.reconcileSecretData(ctx, secret); err == nil {
+			w.task.registryReconciled.RegisterSecret(secret.ObjectMeta)
+		} else {
+			w.task.registryFailed.RegisterSecret(secret.ObjectMeta)
+			return err
+		}
+	}
+
+	if deployment := w.task.creator.CreateDeploymentChproxy(); deployment != nil {
+		if err := w.reconcileDeployment(ctx, deployment); err == nil {
+			w.task.registryReconciled.RegisterDeployment(deployment.ObjectMeta)
+		} else {
+			w.task.registryFailed.RegisterDeployment(deployment.ObjectMeta)
+			return err
+		}
+	}
+
+	if service := w.task.creator.CreateServiceChproxy(); service != nil {
+		if err := w.reconcileService(ctx, chi, service); err == nil {
+			w.task.registryReconciled.RegisterService(service.ObjectMeta)
+		} else {
+			w.task.registryFailed.RegisterService(service.ObjectMeta)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// reconcileDeployment reconciles apps.Deployment
+func (w *worker) reconcileDeployment(ctx context.Context, deployment *apps.Deployment) error {
+	client, err := w.c.kube(deployment.Namespace)
+	if err != nil {
+		return err
+	}
+
+	cur, err := w.c.kubeClient.AppsV1().Deployments(deployment.Namespace).Get(ctx, deployment.Name, controller.NewGetOptions())
+	switch {
+	case err == nil:
+		deployment.ResourceVersion = cur.ResourceVersion
+		_, err := client.AppsV1().Deployments(deployment.Namespace).Update(ctx, deployment, controller.NewUpdateOptions())
+		if err == nil {
+			log.V(1).Info("Deployment updated: %s/%s", deployment.Namespace, deployment.Name)
+		} else {
+			log.Error("FAILED to update Deployment: %s/%s err: %v", deployment.Namespace, deployment.Name, err)
+			return err
+		}
+	case apiErrors.IsNotFound(err):
+		_, err := client.AppsV1().Deployments(deployment.Namespace).Create(ctx, deployment, controller.NewCreateOptions())
+		if err == nil {
+			log.V(1).Info("Deployment created: %s/%s", deployment.Namespace, deployment.Name)
+		} else {
+			log.Error("FAILED create Deployment: %s/%s err: %v", deployment.Namespace, deployment.Name, err)
+			return err
+		}
+	default:
+		log.Error("FAILED get Deployment: %s/%s err: %v", deployment.Namespace, deployment.Name, err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,91 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	log "github.com/altinity/clickhouse-operator/pkg/announcer"
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/chop"
+	model "github.com/altinity/clickhouse-operator/pkg/model/chi"
+	"github.com/altinity/clickhouse-operator/pkg/util"
+)
+
+const (
+	configDriftReasonDetected = "ConfigDriftDetected"
+	configDriftReasonInSync   = "ConfigInSync"
+)
+
+// checkConfigDrift compares configuration loaded by hosts of the already reconciled CHI with the generated one.
+// In case any host runs with stale configuration, e.g. missed configuration reload, ConfigDrift condition is set to True.
+func (w *worker) checkConfigDrift(ctx context.Context, chi *api.ClickHouseInstallation) {
+	if util.IsContextDone(ctx) {
+		log.V(2).Info("task is done")
+		return
+	}
+
+	// Ancestor is the CHI as it was reconciled the last time
+	if !chop.Config().Reconcile.Drift.Enabled.Value() || !chi.HasAncestor() || chi.IsStopped() {
+		return
+	}
+	normalized := w.normalize(chi.GetAncestor())
+
+	var drift []string
+	normalized.WalkHosts(func(host *api.ChiHost) error {
+		drift = append(drift, w.checkHostConfigDrift(ctx, host)...)
+		return nil
+	})
+
+	condition := api.NewChiCondition(api.ConditionConfigDrift, api.ConditionFalse, configDriftReasonInSync, "")
+	if len(drift) > 0 {
+		condition = api.NewChiCondition(api.ConditionConfigDrift, api.ConditionTrue, configDriftReasonDetected, strings.Join(drift, "; "))
+	}
+	if cur, found := chi.EnsureStatus().GetCondition(api.ConditionConfigDrift); found &&
+		(cur.Status == condition.Status) && (cur.Message == condition.Message) {
+		// Nothing changed, no need to update status
+		return
+	}
+
+	w.a.V(1).M(chi).F().Info("config drift: %s %s", condition.Status, condition.Message)
+	target := chi.DeepCopy()
+	target.EnsureStatus().SetCondition(condition)
+	_ = w.c.updateCHIObjectStatus(ctx, target, UpdateCHIStatusOptions{
+		TolerateAbsence: true,
+		CopyCHIStatusOptions: api.CopyCHIStatusOptions{
+			Conditions: true,
+		},
+	})
+}
+
+// checkHostConfigDrift compares configuration loaded by the host with the generated one and returns list of mismatches
+func (w *worker) checkHostConfigDrift(ctx context.Context, host *api.ChiHost) (drift []string) {
+	if host.IsStopped() {
+		return nil
+	}
+
+	loaded, err := w.ensureClusterSchemer(host).HostLoadedConfig(ctx, host)
+	if err != nil {
+		w.a.V(1).M(host).F().Warning("unable to get loaded config of the host: %s err: %v", host.GetName(), err)
+		return nil
+	}
+
+	for _, mismatch := range model.GetHostConfigDrift(host, loaded) {
+		drift = append(drift, fmt.Sprintf("host %s %s", host.GetName(), mismatch))
+	}
+	return drift
+}
@@ -0,0 +1,187 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+
+	log "github.com/altinity/clickhouse-operator/pkg/announcer"
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/chop"
+	"github.com/altinity/clickhouse-operator/pkg/controller"
+	"github.com/altinity/clickhouse-operator/pkg/util"
+)
+
+const (
+	coordinationReasonReady    = "CoordinationQuorumReady"
+	coordinationReasonNotReady = "CoordinationQuorumNotReady"
+)
+
+// coordinationQuorum describes members of coordination ensemble backing a Service
+type coordinationQuorum struct {
+	replicas int32
+	ready    int32
+}
+
+// isReady checks whether majority of members is ready.
+// Service not backed by a StatefulSet is not managed in the cluster and is considered to be ready.
+func (q *coordinationQuorum) isReady() bool {
+	return (q.replicas == 0) || (q.ready >= q.replicas/2+1)
+}
+
+// waitCoordination waits for in-cluster ClickHouse Keeper or ZooKeeper ensembles referenced by hosts to be added
+// to have quorum of ready members, so hosts do not crash-loop in case CHI and its Keeper are created together.
+// In case quorum is not reached in time, CoordinationReady condition is set to False and reconcile proceeds.
+func (w *worker) waitCoordination(ctx context.Context, chi *api.ClickHouseInstallation) {
+	if util.IsContextDone(ctx) {
+		log.V(2).Info("task is done")
+		return
+	}
+
+	if !chop.Config().Reconcile.Coordination.Wait.Value() {
+		return
+	}
+
+	// Existing hosts are already connected to their coordination, so only hosts to be added are considered
+	services := make(map[string]bool)
+	chi.WalkHosts(func(host *api.ChiHost) error {
+		if !host.GetReconcileAttributes().IsAdd() || (host.GetZookeeper() == nil) {
+			return nil
+		}
+		for _, node := range host.GetZookeeper().Nodes {
+			if namespace, name, ok := getCoordinationService(node.Host, chi.Namespace); ok {
+				services[namespace+"/"+name] = true
+			}
+		}
+		return nil
+	})
+	if len(services) == 0 {
+		return
+	}
+
+	var keys []string
+	for service := range services {
+		keys = append(keys, service)
+	}
+	sort.Strings(keys)
+
+	var problems []string
+	for _, service := range keys {
+		parts := strings.SplitN(service, "/", 2)
+		if err := w.c.waitCoordinationQuorum(ctx, parts[0], parts[1]); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", service, err))
+		}
+	}
+
+	if len(problems) > 0 {
+		message := strings.Join(problems, "; ")
+		w.a.V(1).M(chi).F().Warning("coordination quorum is not reached, proceed with reconcile. %s", message)
+		chi.EnsureStatus().SetCondition(
+			api.NewChiCondition(api.ConditionCoordinationReady, api.ConditionFalse, coordinationReasonNotReady, message),
+		)
+	} else {
+		w.a.V(1).M(chi).F().Info("coordination quorum is ready for %d service(s)", len(services))
+		chi.EnsureStatus().SetCondition(
+			api.NewChiCondition(api.ConditionCoordinationReady, api.ConditionTrue, coordinationReasonReady, ""),
+		)
+	}
+	_ = w.c.updateCHIObjectStatus(ctx, chi, UpdateCHIStatusOptions{
+		CopyCHIStatusOptions: api.CopyCHIStatusOptions{
+			Conditions: true,
+		},
+	})
+}
+
+// getCoordinationService gets namespace and name of the Service coordination node host refers to.
+// Hosts which are not in-cluster Service names, e.g. IP addresses or external FQDNs, are skipped.
+func getCoordinationService(host, namespace string) (string, string, bool) {
+	parts := strings.Split(host, ".")
+	switch {
+	case len(parts) == 1:
+		// <service>
+		return namespace, parts[0], true
+	case (len(parts) == 2) || (parts[2] == "svc"):
+		// <service>.<namespace>[.svc[.<cluster domain>]]
+		return parts[1], parts[0], true
+	case (len(parts) >= 4) && (parts[3] == "svc"):
+		// <pod>.<headless service>.<namespace>.svc[.<cluster domain>]
+		return parts[2], parts[1], true
+	}
+	return "", "", false
+}
+
+// waitCoordinationQuorum polls coordination ensemble behind the Service until majority of its members is ready
+func (c *Controller) waitCoordinationQuorum(ctx context.Context, namespace, name string) error {
+	opts := controller.NewPollerOptions().FromConfig(chop.Config()).SetGetErrorTimeout(0)
+	if timeout := chop.Config().Reconcile.Coordination.Timeout; timeout > 0 {
+		opts.Timeout = time.Duration(timeout) * time.Second
+	}
+	return controller.Poll(
+		ctx,
+		namespace, name,
+		opts,
+		&controller.PollerFunctions{
+			Get: func(_ctx context.Context) (any, error) {
+				return c.getCoordinationQuorum(_ctx, namespace, name)
+			},
+			IsDone: func(_ctx context.Context, a any) bool {
+				return a.(*coordinationQuorum).isReady()
+			},
+			ShouldContinue: func(_ctx context.Context, _ any, err error) bool {
+				// Service may be not created yet, in case Keeper is created along with the CHI
+				return apiErrors.IsNotFound(err)
+			},
+		},
+		nil,
+	)
+}
+
+// getCoordinationQuorum gets members of StatefulSets backing the Service
+func (c *Controller) getCoordinationQuorum(ctx context.Context, namespace, name string) (*coordinationQuorum, error) {
+	service, err := c.kubeClient.CoreV1().Services(namespace).Get(ctx, name, controller.NewGetOptions())
+	if err != nil {
+		return nil, err
+	}
+	quorum := &coordinationQuorum{}
+	if len(service.Spec.Selector) == 0 {
+		return quorum, nil
+	}
+
+	statefulSets, err := c.kubeClient.AppsV1().StatefulSets(namespace).List(ctx, controller.NewListOptions())
+	if err != nil {
+		return nil, err
+	}
+	selector := labels.SelectorFromSet(service.Spec.Selector)
+	for i := range statefulSets.Items {
+		statefulSet := &statefulSets.Items[i]
+		if !selector.Matches(labels.Set(statefulSet.Spec.Template.Labels)) {
+			continue
+		}
+		replicas := int32(1)
+		if statefulSet.Spec.Replicas != nil {
+			replicas = *statefulSet.Spec.Replicas
+		}
+		quorum.replicas += replicas
+		quorum.ready += statefulSet.Status.ReadyReplicas
+	}
+	return quorum, nil
+}
@@ -0,0 +1,59 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"testing"
+)
+
+func TestGetCoordinationService(t *testing.T) {
+	tests := []struct {
+		host      string
+		namespace string
+		name      string
+		ok        bool
+	}{
+		{host: "keeper", namespace: "default", name: "keeper", ok: true},
+		{host: "keeper.coordination", namespace: "coordination", name: "keeper", ok: true},
+		{host: "keeper.coordination.svc.cluster.local", namespace: "coordination", name: "keeper", ok: true},
+		{host: "keeper-0.keeper-headless.coordination.svc.cluster.local", namespace: "coordination", name: "keeper-headless", ok: true},
+		{host: "zk1.example.com", ok: false},
+		{host: "10.0.0.1", ok: false},
+	}
+	for _, test := range tests {
+		namespace, name, ok := getCoordinationService(test.host, "default")
+		if (namespace != test.namespace) || (name != test.name) || (ok != test.ok) {
+			t.Errorf("%s: got %s/%s %v, want %s/%s %v", test.host, namespace, name, ok, test.namespace, test.name, test.ok)
+		}
+	}
+}
+
+func TestCoordinationQuorumIsReady(t *testing.T) {
+	tests := []struct {
+		quorum coordinationQuorum
+		ready  bool
+	}{
+		{quorum: coordinationQuorum{replicas: 0, ready: 0}, ready: true},
+		{quorum: coordinationQuorum{replicas: 1, ready: 0}, ready: false},
+		{quorum: coordinationQuorum{replicas: 3, ready: 1}, ready: false},
+		{quorum: coordinationQuorum{replicas: 3, ready: 2}, ready: true},
+		{quorum: coordinationQuorum{replicas: 4, ready: 2}, ready: false},
+	}
+	for _, test := range tests {
+		if ready := test.quorum.isReady(); ready != test.ready {
+			t.Errorf("%d/%d: got %v, want %v", test.quorum.ready, test.quorum.replicas, ready, test.ready)
+		}
+	}
+}
@@ -21,11 +21,14 @@ import (
 	core "k8s.io/api/core/v1"
 	apiErrors "k8s.io/apimachinery/pkg/api/errors"
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kube "k8s.io/client-go/kubernetes"
 
 	log "github.com/altinity/clickhouse-operator/pkg/announcer"
 	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/chop"
 	"github.com/altinity/clickhouse-operator/pkg/controller"
 	model "github.com/altinity/clickhouse-operator/pkg/model/chi"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/creator"
 	"github.com/altinity/clickhouse-operator/pkg/model/chi/normalizer"
 	"github.com/altinity/clickhouse-operator/pkg/util"
 )
@@ -119,15 +122,29 @@ func shouldPurgePDB(chi *api.ClickHouseInstallation, reconcileFailedObjs *model.
 	return true
 }
 
+func shouldPurgeDeployment(chi *api.ClickHouseInstallation, reconcileFailedObjs *model.Registry, m meta.ObjectMeta) bool {
+	return true
+}
+
+// shouldPurgeVolumeSnapshot checks whether VolumeSnapshot is to be purged.
+// VolumeSnapshot keeps data of a PVC, so it follows cleanup policy of PVCs.
+func shouldPurgeVolumeSnapshot(chi *api.ClickHouseInstallation, reconcileFailedObjs *model.Registry, m meta.ObjectMeta) bool {
+	if reconcileFailedObjs.HasVolumeSnapshot(m) {
+		return chi.GetReconciling().GetCleanup().GetReconcileFailedObjects().GetPVC() == api.ObjectsCleanupDelete
+	}
+	return chi.GetReconciling().GetCleanup().GetUnknownObjects().GetPVC() == api.ObjectsCleanupDelete
+}
+
 func (w *worker) purgeStatefulSet(
 	ctx context.Context,
+	client kube.Interface,
 	chi *api.ClickHouseInstallation,
 	reconcileFailedObjs *model.Registry,
 	m meta.ObjectMeta,
 ) int {
 	if shouldPurgeStatefulSet(chi, reconcileFailedObjs, m) {
 		w.a.V(1).M(m).F().Info("Delete StatefulSet: %s/%s", m.Namespace, m.Name)
-		if err := w.c.kubeClient.AppsV1().StatefulSets(m.Namespace).Delete(ctx, m.Name, controller.NewDeleteOptions()); err != nil {
+		if err := client.AppsV1().StatefulSets(m.Namespace).Delete(ctx, m.Name, controller.NewDeleteOptions()); err != nil {
 			w.a.V(1).M(m).F().Error("FAILED to delete StatefulSet: %s/%s, err: %v", m.Namespace, m.Name, err)
 		}
 		return 1
@@ -137,6 +154,7 @@ func (w *worker) purgeStatefulSet(
 
 func (w *worker) purgePVC(
 	ctx context.Context,
+	client kube.Interface,
 	chi *api.ClickHouseInstallation,
 	reconcileFailedObjs *model.Registry,
 	m meta.ObjectMeta,
@@ -144,7 +162,7 @@ func (w *worker) purgePVC(
 	if shouldPurgePVC(chi, reconcileFailedObjs, m) {
 		if model.GetReclaimPolicy(m) == api.PVCReclaimPolicyDelete {
 			w.a.V(1).M(m).F().Info("Delete PVC: %s/%s", m.Namespace, m.Name)
-			if err := w.c.kubeClient.CoreV1().PersistentVolumeClaims(m.Namespace).Delete(ctx, m.Name, controller.NewDeleteOptions()); err != nil {
+			if err := client.CoreV1().PersistentVolumeClaims(m.Namespace).Delete(ctx, m.Name, controller.NewDeleteOptions()); err != nil {
 				w.a.V(1).M(m).F().Error("FAILED to delete PVC: %s/%s, err: %v", m.Namespace, m.Name, err)
 			}
 		}
@@ -153,13 +171,14 @@ func (w *worker) purgePVC(
 
 func (w *worker) purgeConfigMap(
 	ctx context.Context,
+	client kube.Interface,
 	chi *api.ClickHouseInstallation,
 	reconcileFailedObjs *model.Registry,
 	m meta.ObjectMeta,
 ) {
 	if shouldPurgeConfigMap(chi, reconcileFailedObjs, m) {
 		w.a.V(1).M(m).F().Info("Delete ConfigMap: %s/%s", m.Namespace, m.Name)
-		if err := w.c.kubeClient.CoreV1().ConfigMaps(m.Namespace).Delete(ctx, m.Name, controller.NewDeleteOptions()); err != nil {
+		if err := client.CoreV1().ConfigMaps(m.Namespace).Delete(ctx, m.Name, controller.NewDeleteOptions()); err != nil {
 			w.a.V(1).M(m).F().Error("FAILED to delete ConfigMap: %s/%s, err: %v", m.Namespace, m.Name, err)
 		}
 	}
@@ -167,13 +186,14 @@ func (w *worker) purgeConfigMap(
 
 func (w *worker) purgeService(
 	ctx context.Context,
+	client kube.Interface,
 	chi *api.ClickHouseInstallation,
 	reconcileFailedObjs *model.Registry,
 	m meta.ObjectMeta,
 ) {
 	if shouldPurgeService(chi, reconcileFailedObjs, m) {
 		w.a.V(1).M(m).F().Info("Delete Service: %s/%s", m.Namespace, m.Name)
-		if err := w.c.kubeClient.CoreV1().Services(m.Namespace).Delete(ctx, m.Name, controller.NewDeleteOptions()); err != nil {
+		if err := client.CoreV1().Services(m.Namespace).Delete(ctx, m.Name, controller.NewDeleteOptions()); err != nil {
 			w.a.V(1).M(m).F().Error("FAILED to delete Service: %s/%s, err: %v", m.Namespace, m.Name, err)
 		}
 	}
@@ -181,13 +201,14 @@ func (w *worker) purgeService(
 
 func (w *worker) purgeSecret(
 	ctx context.Context,
+	client kube.Interface,
 	chi *api.ClickHouseInstallation,
 	reconcileFailedObjs *model.Registry,
 	m meta.ObjectMeta,
 ) {
 	if shouldPurgeSecret(chi, reconcileFailedObjs, m) {
 		w.a.V(1).M(m).F().Info("Delete Secret: %s/%s", m.Namespace, m.Name)
-		if err := w.c.kubeClient.CoreV1().Secrets(m.Namespace).Delete(ctx, m.Name, controller.NewDeleteOptions()); err != nil {
+		if err := client.CoreV1().Secrets(m.Namespace).Delete(ctx, m.Name, controller.NewDeleteOptions()); err != nil {
 			w.a.V(1).M(m).F().Error("FAILED to delete Secret: %s/%s, err: %v", m.Namespace, m.Name, err)
 		}
 	}
@@ -195,18 +216,53 @@ func (w *worker) purgeSecret(
 
 func (w *worker) purgePDB(
 	ctx context.Context,
+	client kube.Interface,
 	chi *api.ClickHouseInstallation,
 	reconcileFailedObjs *model.Registry,
 	m meta.ObjectMeta,
 ) {
 	if shouldPurgePDB(chi, reconcileFailedObjs, m) {
 		w.a.V(1).M(m).F().Info("Delete PDB: %s/%s", m.Namespace, m.Name)
-		if err := w.c.kubeClient.PolicyV1().PodDisruptionBudgets(m.Namespace).Delete(ctx, m.Name, controller.NewDeleteOptions()); err != nil {
+		if err := client.PolicyV1().PodDisruptionBudgets(m.Namespace).Delete(ctx, m.Name, controller.NewDeleteOptions()); err != nil {
 			w.a.V(1).M(m).F().Error("FAILED to delete PDB: %s/%s, err: %v", m.Namespace, m.Name, err)
 		}
 	}
 }
 
+func (w *worker) purgeDeployment(
+	ctx context.Context,
+	client kube.Interface,
+	chi *api.ClickHouseInstallation,
+	reconcileFailedObjs *model.Registry,
+	m meta.ObjectMeta,
+) {
+	if shouldPurgeDeployment(chi, reconcileFailedObjs, m) {
+		w.a.V(1).M(m).F().Info("Delete Deployment: %s/%s", m.Namespace, m.Name)
+		if err := client.AppsV1().Deployments(m.Namespace).Delete(ctx, m.Name, controller.NewDeleteOptions()); err != nil {
+			w.a.V(1).M(m).F().Error("FAILED to delete Deployment: %s/%s, err: %v", m.Namespace, m.Name, err)
+		}
+	}
+}
+
+func (w *worker) purgeVolumeSnapshot(
+	ctx context.Context,
+	chi *api.ClickHouseInstallation,
+	reconcileFailedObjs *model.Registry,
+	m meta.ObjectMeta,
+) {
+	if shouldPurgeVolumeSnapshot(chi, reconcileFailedObjs, m) {
+		client, err := w.c.dynamic(m.Namespace)
+		if err != nil {
+			w.a.V(1).M(m).F().Error("FAILED to delete VolumeSnapshot: %s/%s, err: %v", m.Namespace, m.Name, err)
+			return
+		}
+		w.a.V(1).M(m).F().Info("Delete VolumeSnapshot: %s/%s", m.Namespace, m.Name)
+		if err := client.Resource(creator.VolumeSnapshotGroupVersionResource).Namespace(m.Namespace).Delete(ctx, m.Name, controller.NewDeleteOptions()); err != nil {
+			w.a.V(1).M(m).F().Error("FAILED to delete VolumeSnapshot: %s/%s, err: %v", m.Namespace, m.Name, err)
+		}
+	}
+}
+
 // purge
 func (w *worker) purge(
 	ctx context.Context,
@@ -219,20 +275,30 @@ func (w *worker) purge(
 		return cnt
 	}
 
+	client, err := w.c.kube(chi.Namespace)
+	if err != nil {
+		w.a.V(1).M(chi).F().Error("FAILED to purge, err: %v", err)
+		return cnt
+	}
+
 	reg.Walk(func(entityType model.EntityType, m meta.ObjectMeta) {
 		switch entityType {
 		case model.StatefulSet:
-			cnt += w.purgeStatefulSet(ctx, chi, reconcileFailedObjs, m)
+			cnt += w.purgeStatefulSet(ctx, client, chi, reconcileFailedObjs, m)
 		case model.PVC:
-			w.purgePVC(ctx, chi, reconcileFailedObjs, m)
+			w.purgePVC(ctx, client, chi, reconcileFailedObjs, m)
 		case model.ConfigMap:
-			w.purgeConfigMap(ctx, chi, reconcileFailedObjs, m)
+			w.purgeConfigMap(ctx, client, chi, reconcileFailedObjs, m)
 		case model.Service:
-			w.purgeService(ctx, chi, reconcileFailedObjs, m)
+			w.purgeService(ctx, client, chi, reconcileFailedObjs, m)
 		case model.Secret:
-			w.purgeSecret(ctx, chi, reconcileFailedObjs, m)
+			w.purgeSecret(ctx, client, chi, reconcileFailedObjs, m)
 		case model.PDB:
-			w.purgePDB(ctx, chi, reconcileFailedObjs, m)
+			w.purgePDB(ctx, client, chi, reconcileFailedObjs, m)
+		case model.Deployment:
+			w.purgeDeployment(ctx, client, chi, reconcileFailedObjs, m)
+		case model.VolumeSnapshot:
+			w.purgeVolumeSnapshot(ctx, chi, reconcileFailedObjs, m)
 		}
 	})
 	return cnt
@@ -245,6 +311,12 @@ func (w *worker) discoveryAndDeleteCHI(ctx context.Context, chi *api.ClickHouseI
 		return nil
 	}
 
+	if chop.Config().IsObserveMode() {
+		// Nothing is deleted in observe mode
+		metricsCHIDelete(chi)
+		return nil
+	}
+
 	objs := w.c.discovery(ctx, chi)
 	if objs.NumStatefulSet() > 0 {
 		chi.WalkHosts(func(host *api.ChiHost) error {
@@ -298,6 +370,8 @@ func (w *worker) deleteCHIProtocol(ctx context.Context, chi *api.ClickHouseInsta
 
 	// Exclude this CHI from monitoring
 	w.c.deleteWatch(chi)
+	metricsCHIDelete(chi)
+	w.forgetResyncChecks(chi)
 
 	// Delete Service
 	_ = w.c.deleteServiceCHI(ctx, chi)
@@ -342,7 +416,7 @@ func (w *worker) canDropReplica(host *api.ChiHost, opts ...*dropReplicaOptions)
 		// Replica's state has to be kept in Zookeeper for retained volumes.
 		// ClickHouse expects to have state of the non-empty replica in-place when replica rejoins.
 		if model.GetReclaimPolicy(pvc.ObjectMeta) == api.PVCReclaimPolicyRetain {
-			w.a.V(1).F().Info("PVC: %s/%s blocks drop replica. Reclaim policy: %s", api.PVCReclaimPolicyRetain.String())
+			w.a.V(1).F().Info("PVC: %s/%s blocks drop replica. Reclaim policy: %s", pvc.Namespace, pvc.Name, api.PVCReclaimPolicyRetain.String())
 			can = false
 		}
 	})
@@ -675,7 +749,11 @@ func (w *worker) deletePVC(ctx context.Context, pvc *core.PersistentVolumeClaim)
 	defer w.a.V(1).M(pvc).F().E().Info("delete PVC with lost PV end: %s/%s", pvc.Namespace, pvc.Name)
 
 	w.a.V(2).M(pvc).F().Info("PVC with lost PV about to be deleted: %s/%s", pvc.Namespace, pvc.Name)
-	w.c.kubeClient.CoreV1().PersistentVolumeClaims(pvc.Namespace).Delete(ctx, pvc.Name, controller.NewDeleteOptions())
+	client, err := w.c.kube(pvc.Namespace)
+	if err != nil {
+		return false
+	}
+	_ = client.CoreV1().PersistentVolumeClaims(pvc.Namespace).Delete(ctx, pvc.Name, controller.NewDeleteOptions())
 
 	for i := 0; i < 360; i++ {
 
@@ -0,0 +1,92 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicFake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/controller"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/builder"
+	chiCreator "github.com/altinity/clickhouse-operator/pkg/model/chi/creator"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/normalizer"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/render"
+)
+
+func TestPurgeVolumeSnapshot(t *testing.T) {
+	for _, tc := range []struct {
+		pvcCleanup string
+		purged     bool
+	}{
+		{pvcCleanup: api.ObjectsCleanupDelete, purged: true},
+		{pvcCleanup: api.ObjectsCleanupRetain, purged: false},
+	} {
+		render.Init("")
+		input := builder.NewCHI("test", "snapshots", builder.WithCluster(builder.NewCluster("main")))
+		input.Spec.Reconciling = &api.ChiReconciling{
+			Cleanup: &api.ChiCleanup{
+				UnknownObjects: api.NewChiObjectsCleanup().SetPVC(tc.pvcCleanup),
+			},
+		}
+		chi, err := normalizer.NewNormalizer(render.NoSecrets).CreateTemplatedCHI(input, normalizer.NewOptions())
+		if err != nil {
+			t.Fatalf("unable to normalize err: %v", err)
+		}
+
+		creator := chiCreator.NewCreator(chi)
+		snapshot := creator.CreateForkVolumeSnapshot(chi.FirstHost(), "data-fork", "data-source")
+		unrelated := &unstructured.Unstructured{}
+		unrelated.SetGroupVersionKind(snapshot.GroupVersionKind())
+		unrelated.SetName("unrelated")
+		unrelated.SetNamespace(chi.Namespace)
+		dynamicClient := dynamicFake.NewSimpleDynamicClientWithCustomListKinds(
+			runtime.NewScheme(),
+			map[schema.GroupVersionResource]string{
+				chiCreator.VolumeSnapshotGroupVersionResource: chiCreator.VolumeSnapshotKind + "List",
+			},
+			snapshot,
+			unrelated,
+		)
+
+		w := &worker{c: &Controller{kubeClient: fake.NewSimpleClientset()}, a: NewAnnouncer()}
+		w.c.dynamicClients.Store("", dynamicClient)
+		w.task = newTask(creator)
+
+		objs := w.c.discovery(context.Background(), chi)
+		if objs.NumVolumeSnapshot() != 1 {
+			t.Fatalf("%s: only VolumeSnapshot of the CHI has to be discovered, got %s", tc.pvcCleanup, objs)
+		}
+		w.purge(context.Background(), chi, objs, nil)
+
+		list, err := dynamicClient.Resource(chiCreator.VolumeSnapshotGroupVersionResource).Namespace(chi.Namespace).List(context.Background(), controller.NewListOptions())
+		if err != nil {
+			t.Fatalf("unable to list err: %v", err)
+		}
+		expected := 1
+		if !tc.purged {
+			expected = 2
+		}
+		if len(list.Items) != expected {
+			t.Errorf("%s: expected %d VolumeSnapshots left, got %d", tc.pvcCleanup, expected, len(list.Items))
+		}
+	}
+}
@@ -0,0 +1,174 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	core "k8s.io/api/core/v1"
+
+	log "github.com/altinity/clickhouse-operator/pkg/announcer"
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/controller"
+	model "github.com/altinity/clickhouse-operator/pkg/model/chi"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/schemer"
+	"github.com/altinity/clickhouse-operator/pkg/util"
+)
+
+const (
+	diskUsageReasonHigh   = "DiskUsageHigh"
+	diskUsageReasonNormal = "DiskUsageNormal"
+)
+
+// checkDiskUsage checks disk usage of hosts of the already reconciled CHI.
+// In case used space of any disk exceeds the threshold, DiskPressure condition is set to True
+// and PVC of the disk is expanded, in case auto expansion is enabled.
+func (w *worker) checkDiskUsage(ctx context.Context, chi *api.ClickHouseInstallation) {
+	if util.IsContextDone(ctx) {
+		log.V(2).Info("task is done")
+		return
+	}
+
+	// Ancestor is the CHI as it was reconciled the last time
+	if !chi.HasAncestor() || chi.IsStopped() || !model.IsDiskUsageMonitored(chi.GetAncestor()) {
+		return
+	}
+	normalized := w.normalize(chi.GetAncestor())
+
+	var problems []string
+	normalized.WalkHosts(func(host *api.ChiHost) error {
+		problems = append(problems, w.checkHostDiskUsage(ctx, host)...)
+		return nil
+	})
+
+	condition := api.NewChiCondition(api.ConditionDiskPressure, api.ConditionFalse, diskUsageReasonNormal, "")
+	if len(problems) > 0 {
+		condition = api.NewChiCondition(api.ConditionDiskPressure, api.ConditionTrue, diskUsageReasonHigh, strings.Join(problems, "; "))
+	}
+	if cur, found := chi.EnsureStatus().GetCondition(api.ConditionDiskPressure); found &&
+		(cur.Status == condition.Status) && (cur.Message == condition.Message) {
+		// Nothing changed, no need to update status
+		return
+	}
+
+	w.a.V(1).M(chi).F().Info("disk pressure: %s %s", condition.Status, condition.Message)
+	target := chi.DeepCopy()
+	target.EnsureStatus().SetCondition(condition)
+	_ = w.c.updateCHIObjectStatus(ctx, target, UpdateCHIStatusOptions{
+		TolerateAbsence: true,
+		CopyCHIStatusOptions: api.CopyCHIStatusOptions{
+			Conditions: true,
+		},
+	})
+}
+
+// checkHostDiskUsage checks disk usage of the host and returns list of disks under pressure
+func (w *worker) checkHostDiskUsage(ctx context.Context, host *api.ChiHost) (problems []string) {
+	if host.IsStopped() {
+		return nil
+	}
+
+	disks, err := w.ensureClusterSchemer(host).HostDisks(ctx, host)
+	if err != nil {
+		w.a.V(1).M(host).F().Warning("unable to get disks of the host: %s err: %v", host.GetName(), err)
+		return nil
+	}
+	host.Runtime.CurStatefulSet, _ = w.c.getStatefulSet(host, false)
+
+	for _, disk := range disks {
+		volumeMount := w.getDiskVolumeMount(host, disk)
+		var template *api.ChiVolumeClaimTemplate
+		if volumeMount != nil {
+			template, _ = model.GetVolumeClaimTemplate(host, volumeMount)
+		}
+
+		threshold := model.GetDiskPressureThreshold(host, template)
+		if (threshold == 0) || (disk.GetUsedPercent() < threshold) {
+			continue
+		}
+
+		problems = append(problems, fmt.Sprintf(
+			"host %s disk %s used %d%% threshold %d%%",
+			host.GetName(), disk.Name, disk.GetUsedPercent(), threshold,
+		))
+		if template != nil {
+			w.expandPVC(ctx, host, volumeMount, template)
+		}
+	}
+	return problems
+}
+
+// getDiskVolumeMount finds volume mount the disk resides on, the longest mount path wins
+func (w *worker) getDiskVolumeMount(host *api.ChiHost, disk *schemer.Disk) (res *core.VolumeMount) {
+	host.WalkVolumeMounts(api.CurStatefulSet, func(volumeMount *core.VolumeMount) {
+		mountPath := strings.TrimSuffix(volumeMount.MountPath, "/") + "/"
+		if !strings.HasPrefix(disk.Path, mountPath) {
+			return
+		}
+		if (res == nil) || (len(volumeMount.MountPath) > len(res.MountPath)) {
+			res = volumeMount
+		}
+	})
+	return res
+}
+
+// expandPVC expands PVC of the volume mount by one step of auto expansion
+func (w *worker) expandPVC(
+	ctx context.Context,
+	host *api.ChiHost,
+	volumeMount *core.VolumeMount,
+	template *api.ChiVolumeClaimTemplate,
+) {
+	expansion := model.GetAutoExpansion(host, template)
+	if !expansion.IsEnabled() {
+		return
+	}
+
+	pvcName, ok := model.CreatePVCNameByVolumeMount(host, volumeMount)
+	if !ok {
+		return
+	}
+	namespace := host.Runtime.Address.Namespace
+	pvc, err := w.c.kubeClient.CoreV1().PersistentVolumeClaims(namespace).Get(ctx, pvcName, controller.NewGetOptions())
+	if err != nil {
+		w.a.V(1).M(host).F().Warning("unable to get PVC %s/%s err: %v", namespace, pvcName, err)
+		return
+	}
+
+	requested := pvc.Spec.Resources.Requests[core.ResourceStorage]
+	if capacity, ok := pvc.Status.Capacity[core.ResourceStorage]; ok && (capacity.Cmp(requested) < 0) {
+		w.a.V(1).M(host).F().Info("PVC %s/%s is being resized already", namespace, pvcName)
+		return
+	}
+
+	size, ok := model.GetAutoExpandedStorageSize(requested, expansion)
+	if !ok {
+		w.a.V(1).M(host).F().Warning("PVC %s/%s has reached max size %s, unable to expand", namespace, pvcName, expansion.MaxSize)
+		return
+	}
+
+	if pvc.Spec.Resources.Requests == nil {
+		pvc.Spec.Resources.Requests = core.ResourceList{}
+	}
+	pvc.Spec.Resources.Requests[core.ResourceStorage] = size
+	if _, err := w.c.updatePersistentVolumeClaim(ctx, pvc); err == nil {
+		w.a.V(1).
+			WithEvent(host.GetCHI(), eventActionUpdate, eventReasonUpdateCompleted).
+			M(host).F().
+			Info("PVC %s/%s expanded from %s to %s", namespace, pvcName, requested.String(), size.String())
+	}
+}
@@ -0,0 +1,173 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"context"
+	"fmt"
+
+	log "github.com/altinity/clickhouse-operator/pkg/announcer"
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/schemer"
+	"github.com/altinity/clickhouse-operator/pkg/util"
+)
+
+// drainRemovedShards moves partitions of shards removed from clusters with drain enabled to the remaining shards.
+// Removed shards are still running, as reconcile has not touched them yet, but are excluded from remote_servers first.
+// In case drain fails, errDrainFailed is returned, so reconcile is aborted and removed shards are kept.
+func (w *worker) drainRemovedShards(ctx context.Context, chi *api.ClickHouseInstallation) error {
+	if util.IsContextDone(ctx) {
+		log.V(2).Info("task is done")
+		return nil
+	}
+
+	ancestor := chi.GetAncestor()
+	if (ancestor == nil) || chi.IsStopped() || w.task.approval.isPending() {
+		// Nothing is to be drained or removal of shards is not approved yet
+		return nil
+	}
+
+	draining := make(map[string]bool)
+	var err error
+	chi.WalkClusters(func(cluster *api.Cluster) error {
+		if (err != nil) || !cluster.Rebalancing.IsDrainEnabled() {
+			return nil
+		}
+		old := ancestor.FindCluster(cluster.Name)
+		if old == nil {
+			return nil
+		}
+
+		var removed, remaining []*api.ChiShard
+		old.WalkShards(func(index int, shard *api.ChiShard) error {
+			if cluster.FindShard(shard.Name) == nil {
+				removed = append(removed, shard)
+			} else {
+				remaining = append(remaining, shard)
+			}
+			return nil
+		})
+		if len(removed) == 0 {
+			return nil
+		}
+
+		draining[cluster.Name] = true
+		err = w.drainShards(ctx, chi, cluster, removed, remaining)
+		return nil
+	})
+
+	// Drain of shards which are not removed anymore is cancelled
+	for _, rebalancing := range chi.EnsureStatus().GetRebalancing() {
+		if (rebalancing.Status == api.RebalancingStatusDraining) && !draining[rebalancing.Cluster] {
+			rebalancing.Status = api.RebalancingStatusCancelled
+			w.updateRebalancingStatus(ctx, chi, rebalancing)
+		}
+	}
+
+	return err
+}
+
+// drainShards moves all partitions of removed shards of the cluster to the remaining ones
+func (w *worker) drainShards(
+	ctx context.Context,
+	chi *api.ClickHouseInstallation,
+	cluster *api.Cluster,
+	removed []*api.ChiShard,
+	remaining []*api.ChiShard,
+) error {
+	var names []string
+	for _, shard := range removed {
+		names = append(names, shard.Name)
+	}
+	w.a.V(1).M(chi).F().Info("drain shards %v of cluster %s", names, cluster.Name)
+	rebalancing := api.NewChiDrainStatus(cluster.Name, names)
+	w.updateRebalancingStatus(ctx, chi, rebalancing)
+
+	err := w.excludeShardsFromClickHouseCluster(ctx, chi, removed)
+	if err == nil {
+		err = w.drainShardsPartitions(ctx, chi, cluster, &rebalancing, removed, remaining)
+	}
+	if err == nil {
+		w.a.V(1).M(chi).F().Info("drain of shards %v of cluster %s completed", names, cluster.Name)
+		rebalancing.Status = api.RebalancingStatusDrained
+		rebalancing.Error = ""
+	} else {
+		w.a.V(1).M(chi).F().Warning("drain of shards %v of cluster %s failed. err: %v", names, cluster.Name, err)
+		rebalancing.Error = err.Error()
+		err = fmt.Errorf("%w: cluster %s: %v", errDrainFailed, cluster.Name, err)
+	}
+	w.updateRebalancingStatus(ctx, chi, rebalancing)
+
+	return err
+}
+
+// excludeShardsFromClickHouseCluster removes shards from remote_servers and waits for ClickHouse to pick-up the change,
+// so Distributed tables stop writing to the shards and rows written to the shards are not lost by the drain
+func (w *worker) excludeShardsFromClickHouseCluster(ctx context.Context, chi *api.ClickHouseInstallation, shards []*api.ChiShard) error {
+	// Shards removed from the CHI are absent in its remote_servers
+	if err := w.reconcileCHIConfigMapCommon(ctx, chi, w.options()); err != nil {
+		return err
+	}
+	var err error
+	for _, shard := range shards {
+		shard.WalkHosts(func(host *api.ChiHost) error {
+			if err == nil {
+				err = w.waitHostNotInCluster(ctx, host)
+			}
+			return nil
+		})
+	}
+	return err
+}
+
+// drainShardsPartitions plans and runs moves of partitions of removed shards to the remaining ones
+func (w *worker) drainShardsPartitions(
+	ctx context.Context,
+	chi *api.ClickHouseInstallation,
+	cluster *api.Cluster,
+	rebalancing *api.ChiRebalancingStatus,
+	removed []*api.ChiShard,
+	remaining []*api.ChiShard,
+) error {
+	collect := func(shards []*api.ChiShard) (res []*schemer.ShardPartitions, err error) {
+		for _, shard := range shards {
+			partitions, err := w.getShardPartitions(ctx, shard, cluster.Rebalancing)
+			if err != nil {
+				return nil, err
+			}
+			if partitions != nil {
+				res = append(res, partitions)
+			}
+		}
+		return res, nil
+	}
+
+	sources, err := collect(removed)
+	if err != nil {
+		return err
+	}
+	targets, err := collect(remaining)
+	if err != nil {
+		return err
+	}
+
+	moves, err := schemer.PlanDrain(sources, targets)
+	if err != nil {
+		return err
+	}
+	rebalancing.PartitionsPending = len(moves)
+
+	return w.movePartitions(ctx, chi, cluster, rebalancing, moves)
+}
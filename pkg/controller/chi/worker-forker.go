@@ -0,0 +1,202 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	core "k8s.io/api/core/v1"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sLabels "k8s.io/apimachinery/pkg/labels"
+
+	log "github.com/altinity/clickhouse-operator/pkg/announcer"
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/controller"
+	model "github.com/altinity/clickhouse-operator/pkg/model/chi"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/creator"
+	"github.com/altinity/clickhouse-operator/pkg/util"
+)
+
+// forkHostVolumes clones volumes of the host being added to a forked CHI from snapshots of volumes
+// of the host with the same cluster, shard and replica in the source CHI.
+// PVCs are created in advance, so StatefulSet adopts them instead of provisioning empty volumes.
+// Returns whether any volume of the host is cloned.
+func (w *worker) forkHostVolumes(ctx context.Context, host *api.ChiHost) (forked bool) {
+	if util.IsContextDone(ctx) {
+		log.V(2).Info("task is done")
+		return false
+	}
+
+	chi := host.GetCHI()
+	if !chi.Spec.Fork.HasSource() || !host.GetReconcileAttributes().IsAdd() {
+		return false
+	}
+	source := chi.Spec.Fork.GetSource()
+	if source == chi.Name {
+		w.a.V(1).M(host).F().Warning("CHI can not be forked from itself")
+		return false
+	}
+
+	sourcePodName, err := w.getForkSourcePodName(host, source)
+	if err != nil {
+		w.a.V(1).M(host).F().Warning("Host %s is not forked, volumes are provisioned empty. Err: %v", host.GetName(), err)
+		return false
+	}
+
+	host.WalkVolumeMounts(api.DesiredStatefulSet, func(volumeMount *core.VolumeMount) {
+		if util.IsContextDone(ctx) {
+			return
+		}
+		volumeClaimTemplate, ok := model.GetVolumeClaimTemplate(host, volumeMount)
+		if !ok {
+			// Not a volume built from VolumeClaimTemplate, nothing to clone
+			return
+		}
+		if err := w.forkHostVolume(ctx, host, volumeClaimTemplate, sourcePodName); err != nil {
+			w.a.V(1).M(host).F().Warning("Volume %s of host %s is not forked. Err: %v", volumeClaimTemplate.Name, host.GetName(), err)
+			return
+		}
+		forked = true
+	})
+
+	return forked
+}
+
+// getForkSourcePodName gets name of the pod of the host with the same cluster, shard and replica in the source CHI
+func (w *worker) getForkSourcePodName(host *api.ChiHost, source string) (string, error) {
+	selector := k8sLabels.SelectorFromSet(model.GetSelectorHostScopeOfCHI(host, source))
+	statefulSets, err := w.c.statefulSetLister.StatefulSets(host.Runtime.Address.Namespace).List(selector)
+	if err != nil {
+		return "", err
+	}
+	if len(statefulSets) != 1 {
+		return "", fmt.Errorf("found %d StatefulSets of the host in the source CHI %s", len(statefulSets), source)
+	}
+	return model.CreatePodName(statefulSets[0]), nil
+}
+
+// forkHostVolume creates snapshot of the source PVC and PVC of the host restored from the snapshot
+func (w *worker) forkHostVolume(
+	ctx context.Context,
+	host *api.ChiHost,
+	volumeClaimTemplate *api.ChiVolumeClaimTemplate,
+	sourcePodName string,
+) error {
+	namespace := host.Runtime.Address.Namespace
+	pvcName := model.CreatePVCNameByVolumeClaimTemplate(host, volumeClaimTemplate)
+	if _, err := w.c.kubeClient.CoreV1().PersistentVolumeClaims(namespace).Get(ctx, pvcName, controller.NewGetOptions()); err == nil {
+		// PVC is in place already, it is either forked before or provisioned otherwise
+		return nil
+	}
+
+	// PVC names of the source CHI are built the same way
+	sourcePVCName := volumeClaimTemplate.Name + "-" + sourcePodName
+	sourcePVC, err := w.c.kubeClient.CoreV1().PersistentVolumeClaims(namespace).Get(ctx, sourcePVCName, controller.NewGetOptions())
+	if err != nil {
+		return err
+	}
+
+	snapshot := w.task.creator.CreateForkVolumeSnapshot(host, model.CreateForkVolumeSnapshotName(pvcName), sourcePVC.Name)
+	client, err := w.c.dynamic(namespace)
+	if err != nil {
+		return err
+	}
+	_, err = client.Resource(creator.VolumeSnapshotGroupVersionResource).Namespace(namespace).Create(ctx, snapshot, controller.NewCreateOptions())
+	if (err != nil) && !apiErrors.IsAlreadyExists(err) {
+		return err
+	}
+	// Snapshot is kept while the volume is being restored from it, afterwards it is purged as an unknown object
+	w.task.registryReconciled.RegisterVolumeSnapshot(meta.ObjectMeta{Name: snapshot.GetName(), Namespace: snapshot.GetNamespace()})
+	w.a.V(1).M(host).F().Info("Volume snapshot %s/%s of PVC %s created", namespace, snapshot.GetName(), sourcePVC.Name)
+
+	pvc := w.task.creator.CreateForkPVC(pvcName, host, &volumeClaimTemplate.Spec, snapshot.GetName())
+	// Volume restored from the snapshot can not be smaller than the source one
+	if size, ok := sourcePVC.Status.Capacity[core.ResourceStorage]; ok && (size.Cmp(*pvc.Spec.Resources.Requests.Storage()) > 0) {
+		if pvc.Spec.Resources.Requests == nil {
+			pvc.Spec.Resources.Requests = core.ResourceList{}
+		}
+		pvc.Spec.Resources.Requests[core.ResourceStorage] = size
+	}
+	pvc = w.task.creator.PreparePersistentVolumeClaim(pvc, host, volumeClaimTemplate)
+	if _, err := w.c.updatePersistentVolumeClaim(ctx, pvc); err != nil {
+		return err
+	}
+	w.task.registryReconciled.RegisterPVC(pvc.ObjectMeta)
+	w.a.V(1).M(host).F().Info("PVC %s/%s restored from volume snapshot %s", namespace, pvc.Name, snapshot.GetName())
+
+	return nil
+}
+
+// restoreForkedReplicas restores ZooKeeper metadata of replicated tables of the forked host.
+// Paths of replicated tables built with macros, such as {installation} or {zookeeper_path}, differ in the forked CHI,
+// so such tables have no metadata in ZooKeeper and are readonly. Metadata is restored from the local parts.
+// Tables with paths used by replicas of other installations, e.g. the source CHI, are left readonly.
+func (w *worker) restoreForkedReplicas(ctx context.Context, host *api.ChiHost) {
+	if util.IsContextDone(ctx) {
+		log.V(2).Info("task is done")
+		return
+	}
+
+	schemer := w.ensureClusterSchemer(host)
+	replicas, err := schemer.HostReadonlyReplicas(ctx, host)
+	if err != nil {
+		w.a.V(1).M(host).F().Warning("Unable to fetch readonly replicas of forked host %s. Err: %v", host.GetName(), err)
+		return
+	}
+
+	// Replicas of the forked CHI are named after hostnames of its hosts
+	var own []string
+	host.GetCHI().WalkHosts(func(h *api.ChiHost) error {
+		own = append(own, model.CreatePodHostname(h))
+		return nil
+	})
+
+	for _, replica := range replicas {
+		if strings.Contains(replica.ZookeeperPath, ":") {
+			// Table is replicated via auxiliary ZooKeeper, which can not be checked
+			w.a.V(1).M(host).F().Warning("Table %s of forked host %s is left readonly, auxiliary ZooKeeper is used", replica.GetTableName(), host.GetName())
+			continue
+		}
+		replicaNames, _, err := schemer.HostZookeeperChildren(ctx, host, path.Join(replica.ZookeeperPath, "replicas"))
+		if err != nil {
+			w.a.V(1).M(host).F().Warning("Unable to check ZooKeeper path of table %s of forked host %s. Err: %v", replica.GetTableName(), host.GetName(), err)
+			continue
+		}
+		if util.InArray(replica.ReplicaName, replicaNames) {
+			// Replica has metadata in ZooKeeper, it is readonly for another reason
+			continue
+		}
+		var foreign []string
+		for _, replicaName := range replicaNames {
+			if !util.InArray(replicaName, own) {
+				foreign = append(foreign, replicaName)
+			}
+		}
+		if len(foreign) > 0 {
+			w.a.V(1).M(host).F().Warning(
+				"Table %s of forked host %s is left readonly, ZooKeeper path %s is used by other replicas: %v",
+				replica.GetTableName(), host.GetName(), replica.ZookeeperPath, foreign,
+			)
+			continue
+		}
+		if err := schemer.HostRestoreReplica(ctx, host, replica); err != nil {
+			w.a.V(1).M(host).F().Warning("Unable to restore replica of table %s of forked host %s. Err: %v", replica.GetTableName(), host.GetName(), err)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	log "github.com/altinity/clickhouse-operator/pkg/announcer"
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/chop"
+	model "github.com/altinity/clickhouse-operator/pkg/model/chi"
+	"github.com/altinity/clickhouse-operator/pkg/util"
+)
+
+const (
+	freezeReasonFrozen = "FreezeInEffect"
+	freezeReasonLifted = "FreezeLifted"
+)
+
+// isFrozen checks whether disruptive actions on the CHI are frozen by the operator config at the moment
+func isFrozen(chi *api.ClickHouseInstallation) bool {
+	_, frozen := chop.Config().GetFreezeUntil(chi.Namespace, time.Now())
+	return frozen
+}
+
+// checkFreeze holds disruptive actions of the reconcile plan in case CHI's namespace is frozen by the operator config.
+// Held actions are applied by the first reconcile after the freeze is lifted
+func (w *worker) checkFreeze(ctx context.Context, chi *api.ClickHouseInstallation, ap *model.ActionPlan) {
+	if util.IsContextDone(ctx) {
+		log.V(2).Info("task is done")
+		return
+	}
+
+	until, frozen := chop.Config().GetFreezeUntil(chi.Namespace, time.Now())
+	var disruptive *model.Plan
+	if frozen {
+		disruptive = w.buildPlan(ctx, chi, ap).Disruptive()
+	}
+
+	if !frozen || (disruptive.Len() == 0) {
+		// Nothing is held
+		if _, found := chi.EnsureStatus().GetCondition(api.ConditionFrozen); found {
+			chi.EnsureStatus().SetCondition(
+				api.NewChiCondition(api.ConditionFrozen, api.ConditionFalse, freezeReasonLifted, ""),
+			)
+		}
+		return
+	}
+
+	// Freeze takes precedence over approval, the same gate holds all disruptive actions
+	w.task.approval = newApprovalGate(disruptive)
+	message := fmt.Sprintf("%d disruptive actions held by freeze until %s", disruptive.Len(), until.Format(time.RFC3339))
+	chi.EnsureStatus().SetCondition(
+		api.NewChiCondition(api.ConditionFrozen, api.ConditionTrue, freezeReasonFrozen, message),
+	)
+	w.a.V(1).WithEvent(chi, eventActionReconcile, eventReasonReconcileInProgress).
+		M(chi).F().
+		Warning("%s:\n%s", message, disruptive)
+}
+
+// isFreezeLifted checks whether CHI was reconciled under freeze which is not in effect anymore,
+// so disruptive actions held by the freeze are to be applied
+func (w *worker) isFreezeLifted(chi *api.ClickHouseInstallation) bool {
+	condition, found := chi.EnsureStatus().GetCondition(api.ConditionFrozen)
+	return found && condition.IsTrue() && !isFrozen(chi)
+}
@@ -0,0 +1,78 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"context"
+	"time"
+
+	log "github.com/altinity/clickhouse-operator/pkg/announcer"
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/chop"
+	"github.com/altinity/clickhouse-operator/pkg/util"
+)
+
+// checkHostsHealth checks hosts of the already reconciled CHI for being alive and keeps results of recent checks
+// of each host in the status, so flapping hosts are told apart from down ones
+func (w *worker) checkHostsHealth(ctx context.Context, chi *api.ClickHouseInstallation) {
+	if util.IsContextDone(ctx) {
+		log.V(2).Info("task is done")
+		return
+	}
+
+	// Ancestor is the CHI as it was reconciled the last time
+	config := chop.Config().Reconcile.Health
+	if !config.Enabled.Value() || !chi.HasAncestor() || chi.IsStopped() {
+		return
+	}
+	normalized := w.normalize(chi.GetAncestor())
+
+	now := time.Now()
+	var hosts []api.ChiHostHealth
+	normalized.WalkHosts(func(host *api.ChiHost) error {
+		cluster := host.Runtime.Address.ClusterName
+		health, _ := chi.EnsureStatus().GetHostHealth(cluster, host.GetName())
+		health.Cluster, health.Host = cluster, host.GetName()
+		state := health.State
+
+		_, err := w.ensureClusterSchemer(host).HostClickHouseVersion(ctx, host)
+		health.Record(err, now, config.Window, config.FlapThreshold, config.DownThreshold)
+		if health.State != state {
+			w.a.V(1).M(host).F().Info("host %s health: %s => %s checks: %s", host.GetName(), state, health.State, health.Checks)
+			if health.IsDown() || health.IsFlapping() {
+				w.c.notify(chi, notificationHostUnhealthy, host.GetName(), "host is "+health.State+": "+health.Error)
+			}
+		}
+
+		hosts = append(hosts, health)
+		return nil
+	})
+
+	target := chi.DeepCopy()
+	target.EnsureStatus().SetHostsHealth(hosts)
+	_ = w.c.updateCHIObjectStatus(ctx, target, UpdateCHIStatusOptions{
+		TolerateAbsence: true,
+		CopyCHIStatusOptions: api.CopyCHIStatusOptions{
+			HostsHealth: true,
+		},
+	})
+	chi.EnsureStatus().SetHostsHealth(hosts)
+}
+
+// isHostFlapping checks whether the host of the CHI is known to be flapping
+func isHostFlapping(chi *api.ClickHouseInstallation, host *api.ChiHost) bool {
+	health, found := chi.EnsureStatus().GetHostHealth(host.Runtime.Address.ClusterName, host.GetName())
+	return found && health.IsFlapping()
+}
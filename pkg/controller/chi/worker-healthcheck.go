@@ -0,0 +1,67 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/chop"
+	chopKube "github.com/altinity/clickhouse-operator/pkg/chop/kube"
+	model "github.com/altinity/clickhouse-operator/pkg/model/chi"
+	"github.com/altinity/clickhouse-operator/pkg/model/k8s"
+	"github.com/altinity/clickhouse-operator/pkg/util"
+)
+
+// execHostQuery runs SQL query by clickhouse-client executed inside the host's pod.
+// Used for health-checks in case network policies do not allow the operator to reach ClickHouse ports.
+func (w *worker) execHostQuery(ctx context.Context, host *api.ChiHost, sql string) (string, error) {
+	pod, err := w.c.getPod(host)
+	if err != nil {
+		return "", err
+	}
+	container, ok := k8s.PodSpecContainerGet(&pod.Spec, model.ClickHouseContainerName, 0)
+	if !ok {
+		return "", fmt.Errorf("no ClickHouse container in pod %s/%s", pod.Namespace, pod.Name)
+	}
+
+	timeout := util.ReasonableDuration(
+		host.GetCHI().GetReconciling().GetQueries().GetQueryTimeout(),
+		chop.Config().ClickHouse.Access.Timeouts.Query,
+	)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	return chopKube.ExecPod(ctx, w.c.kubeClient, pod.Namespace, pod.Name, container.Name, createClickHouseClientCommand(host, sql))
+}
+
+// createClickHouseClientCommand creates clickhouse-client command running SQL query over the local connection
+func createClickHouseClientCommand(host *api.ChiHost, sql string) []string {
+	command := []string{
+		"clickhouse-client",
+		"--host", "127.0.0.1",
+		"--user", chop.Config().ClickHouse.Access.Username,
+		"--password", chop.Config().ClickHouse.Access.Password,
+	}
+	switch {
+	case api.IsPortAssigned(host.TCPPort):
+		command = append(command, "--port", strconv.Itoa(int(host.TCPPort)))
+	case api.IsPortAssigned(host.TLSPort):
+		command = append(command, "--secure", "--port", strconv.Itoa(int(host.TLSPort)))
+	}
+	return append(command, "--query", sql)
+}
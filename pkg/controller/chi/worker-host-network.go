@@ -0,0 +1,50 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"context"
+
+	log "github.com/altinity/clickhouse-operator/pkg/announcer"
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	model "github.com/altinity/clickhouse-operator/pkg/model/chi"
+	"github.com/altinity/clickhouse-operator/pkg/util"
+)
+
+// fillNodeAddresses fills addresses of nodes pods of hosts in host network run on,
+// so remote_servers address these hosts by their nodes. Hosts, pods of which are not scheduled yet,
+// are addressed by their names until the next reconcile
+func (w *worker) fillNodeAddresses(ctx context.Context, chi *api.ClickHouseInstallation) {
+	if util.IsContextDone(ctx) {
+		log.V(2).Info("task is done")
+		return
+	}
+	if (w.c == nil) || (w.c.podLister == nil) {
+		return
+	}
+
+	chi.WalkHosts(func(host *api.ChiHost) error {
+		if !host.IsHostNetwork() {
+			return nil
+		}
+		pod, err := w.c.podLister.Pods(host.Runtime.Address.Namespace).Get(model.CreatePodName(host))
+		if err != nil {
+			log.V(2).M(host).F().Info("pod of host %s is not found err: %v", host.GetName(), err)
+			return nil
+		}
+		host.Runtime.NodeAddress = pod.Status.HostIP
+		return nil
+	})
+}
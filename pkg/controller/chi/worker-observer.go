@@ -0,0 +1,208 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"context"
+	"fmt"
+
+	apps "k8s.io/api/apps/v1"
+	core "k8s.io/api/core/v1"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	log "github.com/altinity/clickhouse-operator/pkg/announcer"
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/controller"
+	model "github.com/altinity/clickhouse-operator/pkg/model/chi"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/creator"
+	"github.com/altinity/clickhouse-operator/pkg/model/k8s"
+	"github.com/altinity/clickhouse-operator/pkg/util"
+	"github.com/altinity/clickhouse-operator/pkg/version"
+)
+
+// observeCHI computes desired state of the CHI and compares it with the actual state.
+// Nothing is applied, planned actions, missing and drifted objects are reported in status and metrics only.
+func (w *worker) observeCHI(ctx context.Context, old, new *api.ClickHouseInstallation) error {
+	if util.IsContextDone(ctx) {
+		log.V(2).Info("task is done")
+		return nil
+	}
+
+	w.a.V(1).M(new).S().P()
+	defer w.a.V(1).M(new).E().P()
+
+	new, actionPlan := w.prepareDesired(new)
+	w.newTask(new)
+
+	observation := api.NewChiObservation(version.Version, new.Generation, actionPlan.Summary())
+	w.observeObjects(ctx, new, observation)
+
+	w.a.V(1).M(new).F().Info(
+		"observed CHI: %s/%s actions: %q missing: %v drifted: %v",
+		new.Namespace, new.Name, observation.Actions, observation.GetMissing(), observation.GetDrifted(),
+	)
+
+	new.EnsureStatus().SetObservation(observation)
+	_ = w.c.updateCHIObjectStatus(ctx, new, UpdateCHIStatusOptions{
+		TolerateAbsence: true,
+		CopyCHIStatusOptions: api.CopyCHIStatusOptions{
+			Observation: true,
+		},
+	})
+	metricsCHIObservationSet(new)
+
+	return nil
+}
+
+// observeObjects compares desired objects of the CHI with the existing ones
+func (w *worker) observeObjects(ctx context.Context, chi *api.ClickHouseInstallation, observation *api.ChiObservation) {
+	observeConfigMap := func(configMap *core.ConfigMap) {
+		if configMap == nil {
+			return
+		}
+		cur, err := w.c.getConfigMap(&configMap.ObjectMeta, true)
+		w.observeObject(observation, "ConfigMap", configMap.ObjectMeta, cur, err)
+	}
+	observeService := func(service *core.Service) {
+		if service == nil {
+			return
+		}
+		cur, err := w.c.getService(service)
+		w.observeObject(observation, "Service", service.ObjectMeta, cur, err)
+	}
+	observeSecret := func(secret *core.Secret) {
+		if secret == nil {
+			return
+		}
+		cur, err := w.c.getSecret(secret)
+		w.observeObject(observation, "Secret", secret.ObjectMeta, cur, err)
+	}
+
+	observeConfigMap(w.task.creator.CreateConfigMapCHICommon(nil))
+	observeConfigMap(w.task.creator.CreateConfigMapCHICommonUsers())
+	observeConfigMap(w.task.creator.CreateConfigMapCHILogging())
+	observeConfigMap(w.task.creator.CreateConfigMapCHITopology())
+	if !chi.IsStopped() {
+		observeService(w.task.creator.CreateServiceCHI())
+	}
+	observeSecret(w.task.creator.CreateSecretConnection())
+
+	chi.WalkClusters(func(cluster *api.Cluster) error {
+		observeService(w.task.creator.CreateServiceCluster(cluster))
+		observeService(w.task.creator.CreateServiceClusterReadTier(cluster))
+		observeService(w.task.creator.CreateServiceClusterReader(cluster))
+		observeService(w.task.creator.CreateServiceClusterWriter(cluster))
+		if cluster.Secret.Source() == api.ClusterSecretSourceAuto {
+			observeSecret(w.task.creator.CreateClusterSecret(cluster))
+		}
+		if cluster.TLS.IsManaged() {
+			observeSecret(w.task.creator.CreateClusterTLSSecret(cluster))
+		}
+
+		pdb := w.task.creator.NewPodDisruptionBudget(cluster)
+		cur, err := w.c.kubeClient.PolicyV1().PodDisruptionBudgets(pdb.Namespace).Get(ctx, pdb.Name, controller.NewGetOptions())
+		w.observeObject(observation, "PodDisruptionBudget", pdb.ObjectMeta, cur, err)
+		return nil
+	})
+	chi.WalkShards(func(shard *api.ChiShard) error {
+		observeService(w.task.creator.CreateServiceShard(shard))
+		return nil
+	})
+	chi.WalkHosts(func(host *api.ChiHost) error {
+		observeConfigMap(w.task.creator.CreateConfigMapHost(host))
+		observeService(w.task.creator.CreateServiceHost(host))
+
+		statefulSet := w.task.creator.CreateStatefulSet(host, false)
+		cur, err := w.c.getStatefulSet(host)
+		w.observeObject(observation, "StatefulSet", statefulSet.ObjectMeta, cur, err)
+
+		w.observePVCs(ctx, host, statefulSet, observation)
+		return nil
+	})
+
+	observeSecret(w.task.creator.CreateSecretChproxy())
+	if deployment := w.task.creator.CreateDeploymentChproxy(); deployment != nil {
+		cur, err := w.c.kubeClient.AppsV1().Deployments(deployment.Namespace).Get(ctx, deployment.Name, controller.NewGetOptions())
+		w.observeObject(observation, "Deployment", deployment.ObjectMeta, cur, err)
+	}
+	observeService(w.task.creator.CreateServiceChproxy())
+}
+
+// observePVCs reports PVCs mounted by the desired StatefulSet of the host, which the operator would create or resize
+func (w *worker) observePVCs(ctx context.Context, host *api.ChiHost, statefulSet *apps.StatefulSet, observation *api.ChiObservation) {
+	observed := make(map[string]bool)
+	k8s.StatefulSetWalkContainers(statefulSet, func(container *core.Container) {
+		for i := range container.VolumeMounts {
+			w.observePVC(ctx, host, &container.VolumeMounts[i], observed, observation)
+		}
+	})
+}
+
+// observePVC reports PVC of the volume mount, in case mount refers to a VolumeClaimTemplate
+func (w *worker) observePVC(
+	ctx context.Context,
+	host *api.ChiHost,
+	volumeMount *core.VolumeMount,
+	observed map[string]bool,
+	observation *api.ChiObservation,
+) {
+	pvcName, ok := model.CreatePVCNameByVolumeMount(host, volumeMount)
+	if !ok || observed[pvcName] {
+		return
+	}
+	volumeClaimTemplate, ok := model.GetVolumeClaimTemplate(host, volumeMount)
+	if !ok {
+		return
+	}
+	observed[pvcName] = true
+
+	namespace := host.Runtime.Address.Namespace
+	name := fmt.Sprintf("PersistentVolumeClaim %s/%s", namespace, pvcName)
+	cur, err := w.c.kubeClient.CoreV1().PersistentVolumeClaims(namespace).Get(ctx, pvcName, controller.NewGetOptions())
+	switch {
+	case apiErrors.IsNotFound(err):
+		// PVCs provisioned by the StatefulSet are not written by the operator until they exist
+		if creator.OperatorShouldCreatePVC(host, volumeClaimTemplate) {
+			observation.AddMissing(name)
+		}
+	case err != nil:
+		w.a.V(1).M(host).F().Warning("unable to get %s err: %v", name, err)
+	default:
+		// Existing PVCs are only ever grown by the operator
+		desired := volumeClaimTemplate.Spec.Resources.Requests[core.ResourceStorage]
+		current := cur.Spec.Resources.Requests[core.ResourceStorage]
+		if desired.Cmp(current) > 0 {
+			observation.AddDrifted(name)
+		}
+	}
+}
+
+// observeObject reports object as either missing or drifted, based on the result of fetching current object
+func (w *worker) observeObject(observation *api.ChiObservation, kind string, desired meta.ObjectMeta, cur meta.Object, err error) {
+	name := fmt.Sprintf("%s %s", kind, util.NamespaceNameString(desired))
+	switch {
+	case apiErrors.IsNotFound(err):
+		observation.AddMissing(name)
+	case err != nil:
+		w.a.V(1).M(desired).F().Warning("unable to get %s err: %v", name, err)
+	default:
+		curVersion, _ := model.GetObjectVersion(meta.ObjectMeta{Labels: cur.GetLabels()})
+		desiredVersion, _ := model.GetObjectVersion(desired)
+		if curVersion != desiredVersion {
+			observation.AddDrifted(name)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubeInformers "k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	model "github.com/altinity/clickhouse-operator/pkg/model/chi"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/builder"
+	chiCreator "github.com/altinity/clickhouse-operator/pkg/model/chi/creator"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/normalizer"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/render"
+)
+
+func newObservedCHI(t *testing.T) *api.ClickHouseInstallation {
+	render.Init("")
+	chi, err := normalizer.NewNormalizer(render.NoSecrets).CreateTemplatedCHI(
+		builder.NewCHI("test", "observed",
+			builder.WithCluster(builder.NewCluster("main", builder.WithDataVolumeClaimTemplate("data"))),
+			builder.WithVolumeClaimTemplates(api.ChiVolumeClaimTemplate{
+				Name:              "data",
+				StorageManagement: api.StorageManagement{PVCProvisioner: api.PVCProvisionerOperator},
+				Spec: core.PersistentVolumeClaimSpec{
+					Resources: core.ResourceRequirements{
+						Requests: core.ResourceList{core.ResourceStorage: resource.MustParse("10Gi")},
+					},
+				},
+			}),
+		),
+		normalizer.NewOptions(),
+	)
+	if err != nil {
+		t.Fatalf("unable to normalize err: %v", err)
+	}
+	return chi
+}
+
+// newObserverWorker creates worker reading objects from the client, listers see no objects at all
+func newObserverWorker(chi *api.ClickHouseInstallation, client *fake.Clientset) *worker {
+	informers := kubeInformers.NewSharedInformerFactory(client, 0)
+	w := &worker{
+		c: &Controller{
+			kubeClient:        client,
+			serviceLister:     informers.Core().V1().Services().Lister(),
+			configMapLister:   informers.Core().V1().ConfigMaps().Lister(),
+			statefulSetLister: informers.Apps().V1().StatefulSets().Lister(),
+		},
+		a: NewAnnouncer(),
+	}
+	w.task = newTask(chiCreator.NewCreator(chi))
+	return w
+}
+
+func hasObserved(list []string, kind string) bool {
+	for _, name := range list {
+		if strings.HasPrefix(name, kind+" ") {
+			return true
+		}
+	}
+	return false
+}
+
+func TestObserveObjectsMissing(t *testing.T) {
+	chi := newObservedCHI(t)
+	client := fake.NewSimpleClientset()
+	w := newObserverWorker(chi, client)
+
+	observation := api.NewChiObservation("", chi.Generation, "")
+	w.observeObjects(context.Background(), chi, observation)
+
+	// Every kind written by the reconciler is reported
+	for _, kind := range []string{
+		"ConfigMap",
+		"Service",
+		"StatefulSet",
+		"Secret",
+		"PodDisruptionBudget",
+		"PersistentVolumeClaim",
+	} {
+		if !hasObserved(observation.GetMissing(), kind) {
+			t.Errorf("missing %s has to be reported, got %v", kind, observation.GetMissing())
+		}
+	}
+	// Nothing is written
+	for _, action := range client.Actions() {
+		if action.GetVerb() != "get" {
+			t.Errorf("only reads are expected, got %v", action)
+		}
+	}
+}
+
+func TestObserveObjectsPVCDrift(t *testing.T) {
+	for _, tc := range []struct {
+		size    string
+		drifted bool
+	}{
+		{size: "5Gi", drifted: true},
+		{size: "10Gi", drifted: false},
+		{size: "20Gi", drifted: false},
+	} {
+		chi := newObservedCHI(t)
+		host := chi.FirstHost()
+		client := fake.NewSimpleClientset(&core.PersistentVolumeClaim{
+			ObjectMeta: meta.ObjectMeta{
+				Name:      "data-" + model.CreatePodName(host),
+				Namespace: chi.Namespace,
+			},
+			Spec: core.PersistentVolumeClaimSpec{
+				Resources: core.ResourceRequirements{
+					Requests: core.ResourceList{core.ResourceStorage: resource.MustParse(tc.size)},
+				},
+			},
+		})
+		w := newObserverWorker(chi, client)
+
+		observation := api.NewChiObservation("", chi.Generation, "")
+		w.observeObjects(context.Background(), chi, observation)
+
+		if hasObserved(observation.GetMissing(), "PersistentVolumeClaim") {
+			t.Errorf("%s: existing PVC must not be reported as missing", tc.size)
+		}
+		if drifted := hasObserved(observation.GetDrifted(), "PersistentVolumeClaim"); drifted != tc.drifted {
+			t.Errorf("%s: expected drifted %t, got %v", tc.size, tc.drifted, observation.GetDrifted())
+		}
+	}
+}
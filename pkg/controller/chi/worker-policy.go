@@ -0,0 +1,368 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	core "k8s.io/api/core/v1"
+
+	log "github.com/altinity/clickhouse-operator/pkg/announcer"
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/apis/deployment"
+	"github.com/altinity/clickhouse-operator/pkg/chop"
+	model "github.com/altinity/clickhouse-operator/pkg/model/chi"
+	"github.com/altinity/clickhouse-operator/pkg/model/k8s"
+	"github.com/altinity/clickhouse-operator/pkg/util"
+)
+
+const (
+	policyReasonCompliant = "PolicyCompliant"
+	policyReasonWarnings  = "PolicyWarnings"
+	policyReasonRejected  = "PolicyRejected"
+)
+
+// Pod distribution types which spread hosts of the CHI over nodes
+var policyAntiAffinityDistributions = []string{
+	deployment.PodDistributionClickHouseAntiAffinity,
+	deployment.PodDistributionShardAntiAffinity,
+	deployment.PodDistributionReplicaAntiAffinity,
+	deployment.PodDistributionMaxNumberPerNode,
+	deployment.PodDistributionCircularReplication,
+	deployment.PodDistributionTopologySpread,
+}
+
+// policyReview is sent to the policy webhook
+type policyReview struct {
+	CHI *policyReviewCHI `json:"chi"`
+}
+
+// policyReviewCHI is a minimal view of the CHI sent to the policy webhook.
+// Configuration, users with their passwords included, and annotations, which may carry the whole CHI, are not sent.
+type policyReviewCHI struct {
+	Namespace            string                       `json:"namespace"`
+	Name                 string                       `json:"name"`
+	Generation           int64                        `json:"generation"`
+	Labels               map[string]string            `json:"labels,omitempty"`
+	Clusters             []policyReviewCluster        `json:"clusters,omitempty"`
+	PodTemplates         []api.ChiPodTemplate         `json:"podTemplates,omitempty"`
+	VolumeClaimTemplates []api.ChiVolumeClaimTemplate `json:"volumeClaimTemplates,omitempty"`
+}
+
+// policyReviewCluster describes layout of the cluster sent to the policy webhook
+type policyReviewCluster struct {
+	Name     string `json:"name"`
+	Shards   int    `json:"shards"`
+	Replicas int    `json:"replicas"`
+}
+
+// newPolicyReviewCHI creates minimal view of the CHI to be sent to the policy webhook
+func newPolicyReviewCHI(chi *api.ClickHouseInstallation) *policyReviewCHI {
+	review := &policyReviewCHI{
+		Namespace:  chi.Namespace,
+		Name:       chi.Name,
+		Generation: chi.Generation,
+		Labels:     chi.Labels,
+	}
+	chi.WalkClusters(func(cluster *api.Cluster) error {
+		review.Clusters = append(review.Clusters, policyReviewCluster{
+			Name:     cluster.Name,
+			Shards:   cluster.Layout.ShardsCount,
+			Replicas: cluster.Layout.ReplicasCount,
+		})
+		return nil
+	})
+	if chi.Spec.Templates != nil {
+		for i := range chi.Spec.Templates.PodTemplates {
+			podTemplate := chi.Spec.Templates.PodTemplates[i].DeepCopy()
+			// Values of env vars may carry credentials, references to Secrets are kept
+			for _, containers := range [][]core.Container{podTemplate.Spec.InitContainers, podTemplate.Spec.Containers} {
+				for j := range containers {
+					for k := range containers[j].Env {
+						containers[j].Env[k].Value = ""
+					}
+				}
+			}
+			review.PodTemplates = append(review.PodTemplates, *podTemplate)
+		}
+		review.VolumeClaimTemplates = chi.Spec.Templates.VolumeClaimTemplates
+	}
+	return review
+}
+
+// policyReviewResult is expected to be received from the policy webhook
+type policyReviewResult struct {
+	Allowed  bool     `json:"allowed"`
+	Message  string   `json:"message,omitempty"`
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// policyRule is a built-in policy rule. Returns list of violations found
+type policyRule func(chi *api.ClickHouseInstallation) []string
+
+// checkPolicy verifies CHI complies with built-in policy rules and external policy webhook.
+// Violations of rules with 'warn' action are reported, violations of rules with 'reject' action
+// abort reconcile with errPolicyRejected. PolicyCompliant condition is set accordingly.
+func (w *worker) checkPolicy(ctx context.Context, chi *api.ClickHouseInstallation) error {
+	if util.IsContextDone(ctx) {
+		log.V(2).Info("task is done")
+		return nil
+	}
+
+	var warnings, rejections []string
+	apply := func(action string, violations []string) {
+		switch action {
+		case api.PolicyActionWarn:
+			warnings = append(warnings, violations...)
+		case api.PolicyActionReject:
+			rejections = append(rejections, violations...)
+		}
+	}
+
+	rules := chop.Config().Reconcile.Policy.Rules
+	for _, r := range []struct {
+		action string
+		rule   policyRule
+	}{
+		{rules.ResourceLimits, policyResourceLimits},
+		{rules.AntiAffinity, policyAntiAffinity},
+		{rules.Backups, policyBackups},
+	} {
+		if r.action != api.PolicyActionIgnore {
+			apply(r.action, r.rule(chi))
+		}
+	}
+
+	if chop.Config().Reconcile.Policy.Webhook.URL != "" {
+		result, err := w.callPolicyWebhook(ctx, chi)
+		switch {
+		case err != nil:
+			w.a.V(1).M(chi).F().Warning("unable to call policy webhook. err: %v", err)
+			apply(chop.Config().Reconcile.Policy.Webhook.FailurePolicy, []string{fmt.Sprintf("policy webhook failed: %v", err)})
+		case !result.Allowed:
+			apply(api.PolicyActionWarn, result.Warnings)
+			apply(api.PolicyActionReject, []string{fmt.Sprintf("rejected by policy webhook: %s", result.Message)})
+		default:
+			apply(api.PolicyActionWarn, result.Warnings)
+		}
+	}
+
+	switch {
+	case len(rejections) > 0:
+		message := strings.Join(append(rejections, warnings...), "; ")
+		chi.EnsureStatus().SetCondition(
+			api.NewChiCondition(api.ConditionPolicyCompliant, api.ConditionFalse, policyReasonRejected, message),
+		)
+		return fmt.Errorf("%w: %s", errPolicyRejected, message)
+	case len(warnings) > 0:
+		message := strings.Join(warnings, "; ")
+		w.a.V(1).WithEvent(chi, eventActionReconcile, eventReasonReconcileInProgress).
+			M(chi).F().
+			Warning("CHI does not comply with policy: %s", message)
+		chi.EnsureStatus().SetCondition(
+			api.NewChiCondition(api.ConditionPolicyCompliant, api.ConditionFalse, policyReasonWarnings, message),
+		)
+	default:
+		chi.EnsureStatus().SetCondition(
+			api.NewChiCondition(api.ConditionPolicyCompliant, api.ConditionTrue, policyReasonCompliant, ""),
+		)
+	}
+
+	return nil
+}
+
+// callPolicyWebhook posts CHI to the policy webhook and returns review result
+func (w *worker) callPolicyWebhook(ctx context.Context, chi *api.ClickHouseInstallation) (*policyReviewResult, error) {
+	return callPolicyWebhook(ctx, &chop.Config().Reconcile.Policy.Webhook, chi)
+}
+
+// newPolicyWebhookClient creates HTTP client to call the policy webhook with
+func newPolicyWebhookClient(webhook *api.OperatorConfigReconcilePolicyWebhook) (*http.Client, error) {
+	client := &http.Client{
+		Timeout: webhook.Timeout,
+	}
+	if webhook.CABundle == "" {
+		return client, nil
+	}
+
+	rootCAs := x509.NewCertPool()
+	if !rootCAs.AppendCertsFromPEM([]byte(webhook.CABundle)) {
+		return nil, fmt.Errorf("unable to parse CA bundle of the policy webhook")
+	}
+	client.Transport = &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		TLSClientConfig: &tls.Config{
+			RootCAs: rootCAs,
+		},
+	}
+	return client, nil
+}
+
+// callPolicyWebhook posts minimal view of the CHI to the policy webhook and returns review result
+func callPolicyWebhook(
+	ctx context.Context,
+	webhook *api.OperatorConfigReconcilePolicyWebhook,
+	chi *api.ClickHouseInstallation,
+) (*policyReviewResult, error) {
+	client, err := newPolicyWebhookClient(webhook)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := json.Marshal(&policyReview{CHI: newPolicyReviewCHI(chi)})
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	if webhook.BearerTokenFile != "" {
+		// Token is read on each call, so rotated tokens are picked up
+		token, err := os.ReadFile(webhook.BearerTokenFile)
+		if err != nil {
+			return nil, err
+		}
+		request.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status: %s", response.Status)
+	}
+
+	result := &policyReviewResult{}
+	if err := json.NewDecoder(response.Body).Decode(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// policyResourceLimits requires CPU and memory limits on all containers of all hosts
+func policyResourceLimits(chi *api.ClickHouseInstallation) (violations []string) {
+	policyWalkPodTemplates(chi, func(host *api.ChiHost, podTemplate *api.ChiPodTemplate) {
+		if podTemplate == nil {
+			if resources := model.HostGetClickHouseContainerResources(host); !policyHasLimits(resources) {
+				violations = append(violations, fmt.Sprintf("host %s has no resource limits", host.GetName()))
+			}
+			return
+		}
+		main, _ := k8s.PodSpecContainerGet(&podTemplate.Spec, model.ClickHouseContainerName, 0)
+		for i := range podTemplate.Spec.Containers {
+			container := &podTemplate.Spec.Containers[i]
+			resources := &container.Resources
+			if container == main {
+				// Resources specified for the host in CHI spec take precedence
+				resources = model.HostGetClickHouseContainerResources(host)
+			}
+			for _, name := range []core.ResourceName{core.ResourceCPU, core.ResourceMemory} {
+				if _, ok := resources.Limits[name]; !ok {
+					violations = append(violations, fmt.Sprintf(
+						"PodTemplate %s: container %s has no %s limit", podTemplate.Name, container.Name, name,
+					))
+				}
+			}
+		}
+	})
+	return violations
+}
+
+// policyHasLimits checks whether both CPU and memory limits are specified
+func policyHasLimits(resources *core.ResourceRequirements) bool {
+	if resources == nil {
+		return false
+	}
+	_, cpu := resources.Limits[core.ResourceCPU]
+	_, memory := resources.Limits[core.ResourceMemory]
+	return cpu && memory
+}
+
+// policyAntiAffinity requires hosts to be spread over nodes either by pod anti-affinity or by pod distribution
+func policyAntiAffinity(chi *api.ClickHouseInstallation) (violations []string) {
+	policyWalkPodTemplates(chi, func(host *api.ChiHost, podTemplate *api.ChiPodTemplate) {
+		if podTemplate == nil {
+			violations = append(violations, fmt.Sprintf("host %s has no pod anti-affinity", host.GetName()))
+			return
+		}
+		if (podTemplate.Spec.Affinity != nil) && (podTemplate.Spec.Affinity.PodAntiAffinity != nil) {
+			return
+		}
+		for _, distribution := range podTemplate.PodDistribution {
+			if util.InArray(distribution.Type, policyAntiAffinityDistributions) {
+				return
+			}
+		}
+		violations = append(violations, fmt.Sprintf("PodTemplate %s has no pod anti-affinity", podTemplate.Name))
+	})
+	return violations
+}
+
+// policyBackups requires backup sidecar container on all hosts.
+// Backup container is the one named by the backup container annotation of the pod template.
+func policyBackups(chi *api.ClickHouseInstallation) (violations []string) {
+	policyWalkPodTemplates(chi, func(host *api.ChiHost, podTemplate *api.ChiPodTemplate) {
+		if podTemplate == nil {
+			violations = append(violations, fmt.Sprintf("host %s has no backup container", host.GetName()))
+			return
+		}
+		name, ok := podTemplate.ObjectMeta.Annotations[model.AnnotationBackupContainer]
+		if !ok {
+			violations = append(violations, fmt.Sprintf(
+				"PodTemplate %s has no backup container, annotation %s is not specified", podTemplate.Name, model.AnnotationBackupContainer,
+			))
+			return
+		}
+		if !k8s.PodSpecHasContainer(&podTemplate.Spec, name) {
+			violations = append(violations, fmt.Sprintf(
+				"PodTemplate %s has no backup container %s", podTemplate.Name, name,
+			))
+		}
+	})
+	return violations
+}
+
+// policyWalkPodTemplates calls f for each pod template used by the CHI hosts, each template is visited once.
+// Hosts without pod template are visited with nil pod template.
+func policyWalkPodTemplates(chi *api.ClickHouseInstallation, f func(host *api.ChiHost, podTemplate *api.ChiPodTemplate)) {
+	var visited []string
+	chi.WalkHosts(func(host *api.ChiHost) error {
+		podTemplate, ok := host.GetPodTemplate()
+		if !ok {
+			f(host, nil)
+			return nil
+		}
+		if util.InArray(podTemplate.Name, visited) {
+			return nil
+		}
+		visited = append(visited, podTemplate.Name)
+		f(host, podTemplate)
+		return nil
+	})
+}
@@ -0,0 +1,134 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	model "github.com/altinity/clickhouse-operator/pkg/model/chi"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/builder"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/normalizer"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/render"
+)
+
+func newPolicyCHI(t *testing.T, podTemplate api.ChiPodTemplate) *api.ClickHouseInstallation {
+	render.Init("")
+	input := builder.NewCHI("test", "policy",
+		builder.WithCluster(builder.NewCluster("main", builder.WithPodTemplate(podTemplate.Name))),
+		builder.WithPodTemplates(podTemplate),
+	)
+	input.Spec.Configuration.Users = api.NewSettings().Set("admin/password", api.NewSettingScalar("top-secret"))
+	chi, err := normalizer.NewNormalizer(render.NoSecrets).CreateTemplatedCHI(input, normalizer.NewOptions())
+	if err != nil {
+		t.Fatalf("unable to normalize err: %v", err)
+	}
+	return chi
+}
+
+func TestPolicyBackups(t *testing.T) {
+	sidecar := core.Container{Name: "sidecar", Image: "altinity/clickhouse-backup:2.2.7"}
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		violations  int
+	}{
+		{name: "backup image without annotation", violations: 1},
+		{name: "annotated container", annotations: map[string]string{model.AnnotationBackupContainer: "sidecar"}, violations: 0},
+		{name: "annotated container is missing", annotations: map[string]string{model.AnnotationBackupContainer: "backup"}, violations: 1},
+	}
+	for _, test := range tests {
+		chi := newPolicyCHI(t, api.ChiPodTemplate{
+			Name:       "pod",
+			ObjectMeta: meta.ObjectMeta{Annotations: test.annotations},
+			Spec: core.PodSpec{
+				Containers: []core.Container{{Name: model.ClickHouseContainerName}, sidecar},
+			},
+		})
+		if violations := policyBackups(chi); len(violations) != test.violations {
+			t.Errorf("%s: got violations %v want %d", test.name, violations, test.violations)
+		}
+	}
+}
+
+func TestCallPolicyWebhook(t *testing.T) {
+	var received []byte
+	server := httptest.NewTLSServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if request.Header.Get("Authorization") != "Bearer token-1" {
+			writer.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		received, _ = io.ReadAll(request.Body)
+		_, _ = writer.Write([]byte(`{"allowed": false, "message": "no way"}`))
+	}))
+	defer server.Close()
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenFile, []byte("token-1\n"), 0600); err != nil {
+		t.Fatalf("unable to write token err: %v", err)
+	}
+	caBundle := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+
+	chi := newPolicyCHI(t, api.ChiPodTemplate{
+		Name: "pod",
+		Spec: core.PodSpec{
+			Containers: []core.Container{{
+				Name: model.ClickHouseContainerName,
+				Env:  []core.EnvVar{{Name: "S3_SECRET", Value: "env-secret"}},
+			}},
+		},
+	})
+
+	// CA of the webhook is not trusted by the system
+	webhook := &api.OperatorConfigReconcilePolicyWebhook{URL: server.URL, Timeout: time.Second, BearerTokenFile: tokenFile}
+	if _, err := callPolicyWebhook(context.Background(), webhook, chi); err == nil {
+		t.Errorf("webhook has to be verified with the CA bundle")
+	}
+
+	webhook.CABundle = caBundle
+	result, err := callPolicyWebhook(context.Background(), webhook, chi)
+	if err != nil {
+		t.Fatalf("unable to call webhook err: %v", err)
+	}
+	if result.Allowed || (result.Message != "no way") {
+		t.Errorf("unexpected result %+v", result)
+	}
+
+	for _, secret := range []string{"top-secret", "env-secret", "password"} {
+		if strings.Contains(string(received), secret) {
+			t.Errorf("%s is sent to the webhook: %s", secret, received)
+		}
+	}
+	review := &policyReview{}
+	if err := json.Unmarshal(received, review); err != nil {
+		t.Fatalf("unable to unmarshal review err: %v", err)
+	}
+	if (review.CHI.Name != "policy") || (len(review.CHI.Clusters) != 1) || (len(review.CHI.PodTemplates) != 1) {
+		t.Errorf("unexpected review %s", received)
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/labels"
+
+	log "github.com/altinity/clickhouse-operator/pkg/announcer"
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/util"
+)
+
+const (
+	portConflictReasonFound    = "PortsAllocatedTwice"
+	portConflictReasonResolved = "PortsUnique"
+)
+
+// listOtherCHIs lists CHIs known to the operator except the specified one
+func (w *worker) listOtherCHIs(chi *api.ClickHouseInstallation) []*api.ClickHouseInstallation {
+	if (w.c == nil) || (w.c.chiLister == nil) {
+		return nil
+	}
+	chis, err := w.c.chiLister.List(labels.Everything())
+	if err != nil {
+		log.V(1).M(chi).F().Warning("unable to list CHIs err: %v", err)
+		return nil
+	}
+	var others []*api.ClickHouseInstallation
+	for _, other := range chis {
+		if (other.Namespace == chi.Namespace) && (other.Name == chi.Name) {
+			continue
+		}
+		others = append(others, other)
+	}
+	return others
+}
+
+// getReservedHostPorts gets ports allocated to hosts of other CHIs.
+// Nodes pods are scheduled on are not known in advance, so all CHIs are considered to share nodes
+func (w *worker) getReservedHostPorts(chi *api.ClickHouseInstallation) []int32 {
+	if chi == nil {
+		return nil
+	}
+	var ports []int32
+	for _, other := range w.listOtherCHIs(chi) {
+		for _, allocation := range other.Status.GetPortAllocations() {
+			ports = append(ports, allocation.Ports...)
+		}
+	}
+	return ports
+}
+
+// findPortConflicts lists hosts of the CHI, ports of which are allocated to hosts of other CHIs as well
+func findPortConflicts(chi *api.ClickHouseInstallation, others []*api.ClickHouseInstallation) []string {
+	var conflicts []string
+	for _, allocation := range chi.EnsureStatus().GetPortAllocations() {
+		for _, other := range others {
+			for _, otherAllocation := range other.Status.GetPortAllocations() {
+				for _, port := range allocation.Ports {
+					if otherAllocation.HasPort(port) {
+						conflicts = append(conflicts, fmt.Sprintf("%s:%d is allocated to %s/%s", allocation.Host, port, other.Namespace, otherAllocation.Host))
+					}
+				}
+			}
+		}
+	}
+	sort.Strings(conflicts)
+	return conflicts
+}
+
+// checkPortConflicts reports hosts, ports of which are allocated to hosts of other CHIs as well.
+// Such hosts fail to start on the same nodes. Allocated ports are kept, so conflicts are resolved by the user
+func (w *worker) checkPortConflicts(ctx context.Context, chi *api.ClickHouseInstallation) {
+	if util.IsContextDone(ctx) {
+		log.V(2).Info("task is done")
+		return
+	}
+
+	conflicts := findPortConflicts(chi, w.listOtherCHIs(chi))
+	if len(conflicts) == 0 {
+		if _, found := chi.EnsureStatus().GetCondition(api.ConditionPortConflict); found {
+			chi.EnsureStatus().SetCondition(
+				api.NewChiCondition(api.ConditionPortConflict, api.ConditionFalse, portConflictReasonResolved, ""),
+			)
+		}
+		return
+	}
+
+	message := strings.Join(conflicts, ", ")
+	chi.EnsureStatus().SetCondition(
+		api.NewChiCondition(api.ConditionPortConflict, api.ConditionTrue, portConflictReasonFound, message),
+	)
+	w.a.V(1).WithEvent(chi, eventActionReconcile, eventReasonReconcileInProgress).
+		M(chi).F().
+		Warning("ports of hosts are allocated to hosts of other CHIs: %s", message)
+}
@@ -0,0 +1,449 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	core "k8s.io/api/core/v1"
+	storage "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	log "github.com/altinity/clickhouse-operator/pkg/announcer"
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/chop"
+	"github.com/altinity/clickhouse-operator/pkg/controller"
+	model "github.com/altinity/clickhouse-operator/pkg/model/chi"
+	"github.com/altinity/clickhouse-operator/pkg/model/k8s"
+	"github.com/altinity/clickhouse-operator/pkg/util"
+)
+
+const (
+	preflightReasonPassed = "PreflightPassed"
+	preflightReasonFailed = "PreflightFailed"
+)
+
+// Annotations used to mark default StorageClass
+var defaultStorageClassAnnotations = []string{
+	"storageclass.kubernetes.io/is-default-class",
+	"storageclass.beta.kubernetes.io/is-default-class",
+}
+
+// preflight verifies there is enough capacity in the cluster for the hosts to be added.
+// Hosts have to be already marked by walkHosts.
+// In case any check fails, CapacityAvailable condition is set to False and errPreflightFailed is returned,
+// so reconcile is aborted instead of leaving Pods in Pending state.
+func (w *worker) preflight(ctx context.Context, chi *api.ClickHouseInstallation) error {
+	if util.IsContextDone(ctx) {
+		log.V(2).Info("task is done")
+		return nil
+	}
+
+	var hosts []*api.ChiHost
+	chi.WalkHosts(func(host *api.ChiHost) error {
+		if host.GetReconcileAttributes().IsAdd() {
+			hosts = append(hosts, host)
+		}
+		return nil
+	})
+	if len(hosts) == 0 {
+		// Nothing is to be added, existing hosts already have their resources
+		return nil
+	}
+
+	var problems []string
+	if chop.Config().Reconcile.Preflight.Quotas.Value() {
+		problems = append(problems, w.preflightQuotas(ctx, chi, hosts)...)
+	}
+	if chop.Config().Reconcile.Preflight.Storage.Value() {
+		problems = append(problems, w.preflightStorage(ctx, chi, hosts)...)
+	}
+	if chop.Config().Reconcile.Preflight.Nodes.Value() {
+		problems = append(problems, w.preflightNodes(ctx, chi, hosts)...)
+	}
+
+	if len(problems) > 0 {
+		message := strings.Join(problems, "; ")
+		chi.EnsureStatus().SetCondition(
+			api.NewChiCondition(api.ConditionCapacityAvailable, api.ConditionFalse, preflightReasonFailed, message),
+		)
+		return fmt.Errorf("%w: %s", errPreflightFailed, message)
+	}
+
+	if condition, found := chi.EnsureStatus().GetCondition(api.ConditionCapacityAvailable); !found || !condition.IsTrue() {
+		chi.EnsureStatus().SetCondition(
+			api.NewChiCondition(api.ConditionCapacityAvailable, api.ConditionTrue, preflightReasonPassed, ""),
+		)
+		_ = w.c.updateCHIObjectStatus(ctx, chi, UpdateCHIStatusOptions{
+			CopyCHIStatusOptions: api.CopyCHIStatusOptions{
+				MainFields: true,
+			},
+		})
+	}
+
+	w.a.V(1).M(chi).F().Info("preflight checks passed for %d host(s) to be added", len(hosts))
+	return nil
+}
+
+// preflightQuotas checks namespace ResourceQuotas have enough room for Pods and PVCs of the hosts to be added
+func (w *worker) preflightQuotas(ctx context.Context, chi *api.ClickHouseInstallation, hosts []*api.ChiHost) (problems []string) {
+	quotas, err := w.c.kubeClient.CoreV1().ResourceQuotas(chi.Namespace).List(ctx, controller.NewListOptions())
+	if err != nil {
+		w.a.V(1).M(chi).F().Warning("unable to list ResourceQuotas, skip quotas check. err: %v", err)
+		return nil
+	}
+
+	required := preflightRequiredResources(hosts)
+	for i := range quotas.Items {
+		quota := &quotas.Items[i]
+		for name, requested := range required {
+			hard, ok := quota.Status.Hard[name]
+			if !ok {
+				continue
+			}
+			available := hard.DeepCopy()
+			if used, ok := quota.Status.Used[name]; ok {
+				available.Sub(used)
+			}
+			if requested.Cmp(available) > 0 {
+				problems = append(problems, fmt.Sprintf(
+					"ResourceQuota %s: %s requested %s, available %s",
+					quota.Name, name, requested.String(), available.String(),
+				))
+			}
+		}
+	}
+	return problems
+}
+
+// preflightRequiredResources sums up resources accounted by ResourceQuota for the hosts to be added
+func preflightRequiredResources(hosts []*api.ChiHost) core.ResourceList {
+	required := core.ResourceList{}
+	add := func(name core.ResourceName, quantity resource.Quantity) {
+		total := required[name]
+		total.Add(quantity)
+		required[name] = total
+	}
+
+	for _, host := range hosts {
+		add(core.ResourcePods, *resource.NewQuantity(1, resource.DecimalSI))
+
+		for _, resources := range preflightHostContainersResources(host) {
+			for _, name := range []core.ResourceName{core.ResourceCPU, core.ResourceMemory} {
+				if quantity, ok := resources.Requests[name]; ok {
+					add(name, quantity)
+					add(core.ResourceName("requests."+string(name)), quantity)
+				}
+				if quantity, ok := resources.Limits[name]; ok {
+					add(core.ResourceName("limits."+string(name)), quantity)
+				}
+			}
+		}
+
+		for _, template := range model.HostGetVolumeClaimTemplates(host) {
+			storageSize := template.Spec.Resources.Requests[core.ResourceStorage]
+			add(core.ResourcePersistentVolumeClaims, *resource.NewQuantity(1, resource.DecimalSI))
+			add(core.ResourceRequestsStorage, storageSize)
+			if className := template.Spec.StorageClassName; (className != nil) && (*className != "") {
+				prefix := *className + ".storageclass.storage.k8s.io/"
+				add(core.ResourceName(prefix+string(core.ResourcePersistentVolumeClaims)), *resource.NewQuantity(1, resource.DecimalSI))
+				add(core.ResourceName(prefix+string(core.ResourceRequestsStorage)), storageSize)
+			}
+		}
+	}
+	return required
+}
+
+// preflightHostContainersResources gets resources of all containers of the host,
+// resources of ClickHouse container take into account resources specified for the host in CHI spec
+func preflightHostContainersResources(host *api.ChiHost) (res []*core.ResourceRequirements) {
+	clickhouse := model.HostGetClickHouseContainerResources(host)
+	if podTemplate, ok := host.GetPodTemplate(); ok {
+		main, _ := k8s.PodSpecContainerGet(&podTemplate.Spec, model.ClickHouseContainerName, 0)
+		for i := range podTemplate.Spec.Containers {
+			if container := &podTemplate.Spec.Containers[i]; container != main {
+				res = append(res, &container.Resources)
+			}
+		}
+	}
+	if clickhouse != nil {
+		res = append(res, clickhouse)
+	}
+	return res
+}
+
+// preflightStorage checks StorageClasses requested by VolumeClaimTemplates exist.
+// In case no StorageClass is requested, either default StorageClass or enough available PersistentVolumes are required.
+func (w *worker) preflightStorage(ctx context.Context, chi *api.ClickHouseInstallation, hosts []*api.ChiHost) (problems []string) {
+	// How many PVCs each VolumeClaimTemplate is going to produce
+	var templates []*api.ChiVolumeClaimTemplate
+	claims := make(map[string]int)
+	for _, host := range hosts {
+		for _, template := range model.HostGetVolumeClaimTemplates(host) {
+			if claims[template.Name] == 0 {
+				templates = append(templates, template)
+			}
+			claims[template.Name]++
+		}
+	}
+	if len(templates) == 0 {
+		return nil
+	}
+
+	classes, err := w.c.kubeClient.StorageV1().StorageClasses().List(ctx, controller.NewListOptions())
+	if err != nil {
+		w.a.V(1).M(chi).F().Warning("unable to list StorageClasses, skip storage check. err: %v", err)
+		return nil
+	}
+
+	for _, template := range templates {
+		name := template.Name
+		className := template.Spec.StorageClassName
+		switch {
+		case (className != nil) && (*className != ""):
+			if !preflightHasStorageClass(classes.Items, *className) {
+				problems = append(problems, fmt.Sprintf(
+					"VolumeClaimTemplate %s: StorageClass %s not found", name, *className,
+				))
+			}
+		case (className == nil) && preflightHasDefaultStorageClass(classes.Items):
+			// Default StorageClass would provision volumes
+		default:
+			// Static provisioning, PVs have to be available beforehand
+			available, err := w.preflightCountAvailablePVs(ctx, template)
+			if err != nil {
+				w.a.V(1).M(chi).F().Warning("unable to list PersistentVolumes, skip storage check. err: %v", err)
+				continue
+			}
+			if available < claims[name] {
+				problems = append(problems, fmt.Sprintf(
+					"VolumeClaimTemplate %s: no StorageClass to provision volumes, %d PersistentVolume(s) required, %d available",
+					name, claims[name], available,
+				))
+			}
+		}
+	}
+	return problems
+}
+
+// preflightHasStorageClass checks whether StorageClass with specified name is in the list
+func preflightHasStorageClass(classes []storage.StorageClass, name string) bool {
+	for i := range classes {
+		if classes[i].Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// preflightHasDefaultStorageClass checks whether there is default StorageClass in the list
+func preflightHasDefaultStorageClass(classes []storage.StorageClass) bool {
+	for i := range classes {
+		for _, annotation := range defaultStorageClassAnnotations {
+			if classes[i].Annotations[annotation] == "true" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// preflightCountAvailablePVs counts available PersistentVolumes without StorageClass,
+// large enough to be bound to PVC made from the VolumeClaimTemplate
+func (w *worker) preflightCountAvailablePVs(ctx context.Context, template *api.ChiVolumeClaimTemplate) (int, error) {
+	pvs, err := w.c.kubeClient.CoreV1().PersistentVolumes().List(ctx, controller.NewListOptions())
+	if err != nil {
+		return 0, err
+	}
+
+	requested := template.Spec.Resources.Requests[core.ResourceStorage]
+	available := 0
+	for i := range pvs.Items {
+		pv := &pvs.Items[i]
+		if pv.Status.Phase != core.VolumeAvailable {
+			continue
+		}
+		if pv.Spec.StorageClassName != "" {
+			continue
+		}
+		if capacity, ok := pv.Spec.Capacity[core.ResourceStorage]; ok && (capacity.Cmp(requested) >= 0) {
+			available++
+		}
+	}
+	return available, nil
+}
+
+// preflightNodes checks there are schedulable Nodes matching zone, node selector, required node affinity
+// and tolerations of pod templates
+func (w *worker) preflightNodes(ctx context.Context, chi *api.ClickHouseInstallation, hosts []*api.ChiHost) (problems []string) {
+	nodes, err := w.c.kubeClient.CoreV1().Nodes().List(ctx, controller.NewListOptions())
+	if err != nil {
+		w.a.V(1).M(chi).F().Warning("unable to list Nodes, skip nodes check. err: %v", err)
+		return nil
+	}
+
+	for _, host := range hosts {
+		podTemplate, ok := host.GetPodTemplate()
+		if !ok {
+			continue
+		}
+		problem := fmt.Sprintf(
+			"PodTemplate %s: no schedulable Node matches zone, node selector, node affinity and tolerations", podTemplate.Name,
+		)
+		if util.InArray(problem, problems) {
+			continue
+		}
+
+		// Pod spec is rendered per host, since zone, spot and other placement settings are applied on top of the template
+		podSpec := &w.task.creator.CreateStatefulSet(host, false).Spec.Template.Spec
+		found := false
+		for i := range nodes.Items {
+			if preflightNodeMatches(&nodes.Items[i], podSpec) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			problems = append(problems, problem)
+		}
+	}
+	return problems
+}
+
+// preflightNodeMatches checks whether Node is ready, schedulable, matches node selector and required node affinity
+// of the pod, zone included, and its taints are tolerated by the pod
+func preflightNodeMatches(node *core.Node, podSpec *core.PodSpec) bool {
+	if node.Spec.Unschedulable {
+		return false
+	}
+
+	ready := false
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == core.NodeReady {
+			ready = condition.Status == core.ConditionTrue
+		}
+	}
+	if !ready {
+		return false
+	}
+
+	for key, value := range podSpec.NodeSelector {
+		if node.Labels[key] != value {
+			return false
+		}
+	}
+
+	if !preflightNodeMatchesAffinity(node, podSpec.Affinity) {
+		return false
+	}
+
+	return preflightNodeTaintsTolerated(node, podSpec.Tolerations)
+}
+
+// preflightNodeMatchesAffinity checks whether Node matches any of required node selector terms of the affinity
+func preflightNodeMatchesAffinity(node *core.Node, affinity *core.Affinity) bool {
+	if (affinity == nil) || (affinity.NodeAffinity == nil) {
+		return true
+	}
+	required := affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+	if required == nil {
+		return true
+	}
+
+	// Terms are ORed, requirements of a term are ANDed
+	for i := range required.NodeSelectorTerms {
+		term := &required.NodeSelectorTerms[i]
+		if (len(term.MatchExpressions) == 0) && (len(term.MatchFields) == 0) {
+			// Empty term matches no objects
+			continue
+		}
+		fields := map[string]string{
+			"metadata.name": node.Name,
+		}
+		if preflightNodeSelectorRequirementsMatch(term.MatchExpressions, node.Labels) &&
+			preflightNodeSelectorRequirementsMatch(term.MatchFields, fields) {
+			return true
+		}
+	}
+	return false
+}
+
+// preflightNodeSelectorRequirementsMatch checks whether all requirements match specified labels
+func preflightNodeSelectorRequirementsMatch(requirements []core.NodeSelectorRequirement, labels map[string]string) bool {
+	for _, requirement := range requirements {
+		value, ok := labels[requirement.Key]
+		switch requirement.Operator {
+		case core.NodeSelectorOpIn:
+			if !ok || !util.InArray(value, requirement.Values) {
+				return false
+			}
+		case core.NodeSelectorOpNotIn:
+			if ok && util.InArray(value, requirement.Values) {
+				return false
+			}
+		case core.NodeSelectorOpExists:
+			if !ok {
+				return false
+			}
+		case core.NodeSelectorOpDoesNotExist:
+			if ok {
+				return false
+			}
+		case core.NodeSelectorOpGt, core.NodeSelectorOpLt:
+			if !ok || (len(requirement.Values) != 1) {
+				return false
+			}
+			actual, err1 := strconv.ParseInt(value, 10, 64)
+			bound, err2 := strconv.ParseInt(requirement.Values[0], 10, 64)
+			if (err1 != nil) || (err2 != nil) {
+				return false
+			}
+			if (requirement.Operator == core.NodeSelectorOpGt) && !(actual > bound) {
+				return false
+			}
+			if (requirement.Operator == core.NodeSelectorOpLt) && !(actual < bound) {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// preflightNodeTaintsTolerated checks whether all taints of the Node, which prevent scheduling, are tolerated
+func preflightNodeTaintsTolerated(node *core.Node, tolerations []core.Toleration) bool {
+	for i := range node.Spec.Taints {
+		taint := &node.Spec.Taints[i]
+		if taint.Effect == core.TaintEffectPreferNoSchedule {
+			// Does not prevent scheduling
+			continue
+		}
+		tolerated := false
+		for j := range tolerations {
+			if tolerations[j].ToleratesTaint(taint) {
+				tolerated = true
+				break
+			}
+		}
+		if !tolerated {
+			return false
+		}
+	}
+	return true
+}
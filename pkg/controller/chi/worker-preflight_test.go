@@ -0,0 +1,201 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"context"
+	"testing"
+
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/builder"
+	chiCreator "github.com/altinity/clickhouse-operator/pkg/model/chi/creator"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/normalizer"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/render"
+)
+
+func newPreflightNode(labels map[string]string, taints ...core.Taint) *core.Node {
+	return &core.Node{
+		ObjectMeta: meta.ObjectMeta{
+			Name:   "node-1",
+			Labels: labels,
+		},
+		Spec: core.NodeSpec{
+			Taints: taints,
+		},
+		Status: core.NodeStatus{
+			Conditions: []core.NodeCondition{{Type: core.NodeReady, Status: core.ConditionTrue}},
+		},
+	}
+}
+
+func newPreflightAffinity(terms ...core.NodeSelectorTerm) *core.Affinity {
+	return &core.Affinity{
+		NodeAffinity: &core.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &core.NodeSelector{
+				NodeSelectorTerms: terms,
+			},
+		},
+	}
+}
+
+func TestPreflightNodeMatches(t *testing.T) {
+	dedicated := core.Taint{Key: "dedicated", Value: "clickhouse", Effect: core.TaintEffectNoSchedule}
+	tests := []struct {
+		name    string
+		node    *core.Node
+		podSpec core.PodSpec
+		matches bool
+	}{
+		{
+			name:    "plain node",
+			node:    newPreflightNode(nil),
+			matches: true,
+		},
+		{
+			name:    "untolerated taint",
+			node:    newPreflightNode(nil, dedicated),
+			matches: false,
+		},
+		{
+			name: "tolerated taint",
+			node: newPreflightNode(nil, dedicated),
+			podSpec: core.PodSpec{
+				Tolerations: []core.Toleration{{Key: "dedicated", Operator: core.TolerationOpEqual, Value: "clickhouse", Effect: core.TaintEffectNoSchedule}},
+			},
+			matches: true,
+		},
+		{
+			name: "taint tolerated with another value",
+			node: newPreflightNode(nil, dedicated),
+			podSpec: core.PodSpec{
+				Tolerations: []core.Toleration{{Key: "dedicated", Operator: core.TolerationOpEqual, Value: "kafka"}},
+			},
+			matches: false,
+		},
+		{
+			name:    "prefer no schedule taint",
+			node:    newPreflightNode(nil, core.Taint{Key: "spot", Effect: core.TaintEffectPreferNoSchedule}),
+			matches: true,
+		},
+		{
+			name: "node selector",
+			node: newPreflightNode(map[string]string{"disk": "hdd"}),
+			podSpec: core.PodSpec{
+				NodeSelector: map[string]string{"disk": "ssd"},
+			},
+			matches: false,
+		},
+		{
+			name: "affinity In matched",
+			node: newPreflightNode(map[string]string{"zone": "a"}),
+			podSpec: core.PodSpec{
+				Affinity: newPreflightAffinity(core.NodeSelectorTerm{
+					MatchExpressions: []core.NodeSelectorRequirement{{Key: "zone", Operator: core.NodeSelectorOpIn, Values: []string{"a", "b"}}},
+				}),
+			},
+			matches: true,
+		},
+		{
+			name: "affinity In not matched",
+			node: newPreflightNode(map[string]string{"zone": "c"}),
+			podSpec: core.PodSpec{
+				Affinity: newPreflightAffinity(core.NodeSelectorTerm{
+					MatchExpressions: []core.NodeSelectorRequirement{{Key: "zone", Operator: core.NodeSelectorOpIn, Values: []string{"a", "b"}}},
+				}),
+			},
+			matches: false,
+		},
+		{
+			name: "affinity terms are ORed",
+			node: newPreflightNode(map[string]string{"zone": "c"}),
+			podSpec: core.PodSpec{
+				Affinity: newPreflightAffinity(
+					core.NodeSelectorTerm{
+						MatchExpressions: []core.NodeSelectorRequirement{{Key: "zone", Operator: core.NodeSelectorOpIn, Values: []string{"a"}}},
+					},
+					core.NodeSelectorTerm{
+						MatchExpressions: []core.NodeSelectorRequirement{{Key: "zone", Operator: core.NodeSelectorOpNotIn, Values: []string{"a"}}},
+					},
+				),
+			},
+			matches: true,
+		},
+		{
+			name: "affinity requirements are ANDed",
+			node: newPreflightNode(map[string]string{"zone": "a", "cpu": "8"}),
+			podSpec: core.PodSpec{
+				Affinity: newPreflightAffinity(core.NodeSelectorTerm{
+					MatchExpressions: []core.NodeSelectorRequirement{
+						{Key: "zone", Operator: core.NodeSelectorOpExists},
+						{Key: "cpu", Operator: core.NodeSelectorOpGt, Values: []string{"16"}},
+					},
+				}),
+			},
+			matches: false,
+		},
+		{
+			name: "affinity match fields",
+			node: newPreflightNode(nil),
+			podSpec: core.PodSpec{
+				Affinity: newPreflightAffinity(core.NodeSelectorTerm{
+					MatchFields: []core.NodeSelectorRequirement{{Key: "metadata.name", Operator: core.NodeSelectorOpIn, Values: []string{"node-2"}}},
+				}),
+			},
+			matches: false,
+		},
+	}
+	for _, test := range tests {
+		if matches := preflightNodeMatches(test.node, &test.podSpec); matches != test.matches {
+			t.Errorf("%s: got matches %v want %v", test.name, matches, test.matches)
+		}
+	}
+}
+
+func TestPreflightNodesZone(t *testing.T) {
+	render.Init("")
+	chi, err := normalizer.NewNormalizer(render.NoSecrets).CreateTemplatedCHI(
+		builder.NewCHI("test", "preflight",
+			builder.WithCluster(builder.NewCluster("main", builder.WithPodTemplate("pod"))),
+			builder.WithPodTemplates(api.ChiPodTemplate{
+				Name: "pod",
+				Zone: api.ChiPodTemplateZone{Key: "zone", Values: []string{"a"}},
+			}),
+		),
+		normalizer.NewOptions(),
+	)
+	if err != nil {
+		t.Fatalf("unable to normalize err: %v", err)
+	}
+
+	for _, test := range []struct {
+		zone     string
+		problems int
+	}{
+		{zone: "a", problems: 0},
+		{zone: "b", problems: 1},
+	} {
+		client := fake.NewSimpleClientset(newPreflightNode(map[string]string{"zone": test.zone}))
+		w := &worker{c: &Controller{kubeClient: client}, a: NewAnnouncer()}
+		w.task = newTask(chiCreator.NewCreator(chi))
+
+		if problems := w.preflightNodes(context.Background(), chi, []*api.ChiHost{chi.FirstHost()}); len(problems) != test.problems {
+			t.Errorf("zone %s: got problems %v want %d", test.zone, problems, test.problems)
+		}
+	}
+}
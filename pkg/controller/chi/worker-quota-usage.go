@@ -0,0 +1,96 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	log "github.com/altinity/clickhouse-operator/pkg/announcer"
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/util"
+)
+
+const (
+	quotaUsageReasonHigh   = "QuotaUsageHigh"
+	quotaUsageReasonNormal = "QuotaUsageNormal"
+)
+
+// checkQuotaUsage checks quota usage of hosts of the already reconciled CHI.
+// In case usage of any limited resource of any quota exceeds the threshold, QuotaPressure condition is set to True
+func (w *worker) checkQuotaUsage(ctx context.Context, chi *api.ClickHouseInstallation) {
+	if util.IsContextDone(ctx) {
+		log.V(2).Info("task is done")
+		return
+	}
+
+	// Ancestor is the CHI as it was reconciled the last time
+	if !chi.HasAncestor() || chi.IsStopped() || (chi.GetAncestor().Spec.Defaults.GetQuotaUsageThreshold() == 0) {
+		return
+	}
+	normalized := w.normalize(chi.GetAncestor())
+	threshold := normalized.Spec.Defaults.GetQuotaUsageThreshold()
+
+	var problems []string
+	normalized.WalkHosts(func(host *api.ChiHost) error {
+		problems = append(problems, w.checkHostQuotaUsage(ctx, host, threshold)...)
+		return nil
+	})
+
+	condition := api.NewChiCondition(api.ConditionQuotaPressure, api.ConditionFalse, quotaUsageReasonNormal, "")
+	if len(problems) > 0 {
+		condition = api.NewChiCondition(api.ConditionQuotaPressure, api.ConditionTrue, quotaUsageReasonHigh, strings.Join(problems, "; "))
+	}
+	if cur, found := chi.EnsureStatus().GetCondition(api.ConditionQuotaPressure); found &&
+		(cur.Status == condition.Status) && (cur.Message == condition.Message) {
+		// Nothing changed, no need to update status
+		return
+	}
+
+	w.a.V(1).M(chi).F().Info("quota pressure: %s %s", condition.Status, condition.Message)
+	target := chi.DeepCopy()
+	target.EnsureStatus().SetCondition(condition)
+	_ = w.c.updateCHIObjectStatus(ctx, target, UpdateCHIStatusOptions{
+		TolerateAbsence: true,
+		CopyCHIStatusOptions: api.CopyCHIStatusOptions{
+			Conditions: true,
+		},
+	})
+}
+
+// checkHostQuotaUsage checks quota usage of the host and returns list of quota resources under pressure
+func (w *worker) checkHostQuotaUsage(ctx context.Context, host *api.ChiHost, threshold int) (problems []string) {
+	if host.IsStopped() {
+		return nil
+	}
+
+	usages, err := w.ensureClusterSchemer(host).HostQuotaUsage(ctx, host)
+	if err != nil {
+		w.a.V(1).M(host).F().Warning("unable to get quota usage of the host: %s err: %v", host.GetName(), err)
+		return nil
+	}
+
+	for _, usage := range usages {
+		if usage.GetUsedPercent() < threshold {
+			continue
+		}
+		problems = append(problems, fmt.Sprintf(
+			"host %s quota %s key %s interval %ds %s used %d%% threshold %d%%",
+			host.GetName(), usage.Quota, usage.Key, usage.Duration, usage.Resource, usage.GetUsedPercent(), threshold,
+		))
+	}
+	return problems
+}
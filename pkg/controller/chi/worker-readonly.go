@@ -0,0 +1,131 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	log "github.com/altinity/clickhouse-operator/pkg/announcer"
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/chop"
+	"github.com/altinity/clickhouse-operator/pkg/util"
+)
+
+const (
+	readonlyReasonDetected = "ReadonlyReplicasDetected"
+	readonlyReasonNone     = "NoReadonlyReplicas"
+)
+
+// checkReadonlyReplicas checks hosts of the already reconciled CHI for replicated tables being in readonly mode,
+// e.g. due to ZooKeeper session loss or metadata mismatch. In case any is found, ReadonlyReplicas condition is set to True.
+// Replicas staying readonly longer than the configured delay are restarted, in case automatic restart is enabled.
+func (w *worker) checkReadonlyReplicas(ctx context.Context, chi *api.ClickHouseInstallation) {
+	if util.IsContextDone(ctx) {
+		log.V(2).Info("task is done")
+		return
+	}
+
+	// Ancestor is the CHI as it was reconciled the last time
+	if !chop.Config().Reconcile.Readonly.Enabled.Value() || !chi.HasAncestor() || chi.IsStopped() {
+		return
+	}
+	normalized := w.normalize(chi.GetAncestor())
+
+	var problems []string
+	normalized.WalkHosts(func(host *api.ChiHost) error {
+		problems = append(problems, w.checkHostReadonlyReplicas(ctx, host, isHostFlapping(chi, host))...)
+		return nil
+	})
+
+	condition := api.NewChiCondition(api.ConditionReadonlyReplicas, api.ConditionFalse, readonlyReasonNone, "")
+	if len(problems) > 0 {
+		condition = api.NewChiCondition(api.ConditionReadonlyReplicas, api.ConditionTrue, readonlyReasonDetected, strings.Join(problems, "; "))
+	}
+	if cur, found := chi.EnsureStatus().GetCondition(api.ConditionReadonlyReplicas); found &&
+		(cur.Status == condition.Status) && (cur.Message == condition.Message) {
+		// Nothing changed, no need to update status
+		return
+	}
+
+	w.a.V(1).M(chi).F().Info("readonly replicas: %s %s", condition.Status, condition.Message)
+	if condition.Status == api.ConditionTrue {
+		w.c.notify(chi, notificationHostUnhealthy, "", "readonly replicas: "+condition.Message)
+	}
+	target := chi.DeepCopy()
+	target.EnsureStatus().SetCondition(condition)
+	_ = w.c.updateCHIObjectStatus(ctx, target, UpdateCHIStatusOptions{
+		TolerateAbsence: true,
+		CopyCHIStatusOptions: api.CopyCHIStatusOptions{
+			Conditions: true,
+		},
+	})
+}
+
+// checkHostReadonlyReplicas checks the host for readonly replicas, restarts the ones being readonly for too long
+// and returns list of replicas which are still readonly.
+// Replicas of flapping host are not restarted, since they are readonly due to the host being unstable
+func (w *worker) checkHostReadonlyReplicas(ctx context.Context, host *api.ChiHost, flapping bool) (problems []string) {
+	if host.IsStopped() {
+		return nil
+	}
+
+	replicas, err := w.ensureClusterSchemer(host).HostReadonlyReplicas(ctx, host)
+	if err != nil {
+		w.a.V(1).M(host).F().Warning("unable to get readonly replicas of the host: %s err: %v", host.GetName(), err)
+		return nil
+	}
+
+	config := chop.Config().Reconcile.Readonly
+	delay := time.Duration(config.RestartDelay) * time.Second
+	prefix := host.Runtime.Address.FQDN + "/"
+	now := time.Now()
+	frozen := isFrozen(host.GetCHI())
+
+	var readonly []string
+	for _, replica := range replicas {
+		key := prefix + replica.GetTableName()
+		readonly = append(readonly, key)
+		since, _ := w.c.readonlyReplicas.LoadOrStore(key, now)
+
+		// Replicas of frozen shards and of frozen namespaces are left for manual maintenance
+		if config.Restart.Value() && !host.IsFrozen() && !frozen && !flapping && (now.Sub(since.(time.Time)) >= delay) {
+			err := w.ensureClusterSchemer(host).HostRestartReplica(ctx, host, replica)
+			if err == nil {
+				w.a.V(1).
+					WithEvent(host.GetCHI(), eventActionUpdate, eventReasonUpdateCompleted).
+					M(host).F().
+					Info("host %s table %s was readonly since %s, replica restarted", host.GetName(), replica.GetTableName(), since.(time.Time).Format(time.RFC3339))
+				w.c.readonlyReplicas.Delete(key)
+				continue
+			}
+			w.a.V(1).M(host).F().Warning("unable to restart replica of %s on the host: %s err: %v", replica.GetTableName(), host.GetName(), err)
+		}
+
+		problems = append(problems, fmt.Sprintf("host %s table %s is readonly", host.GetName(), replica.GetTableName()))
+	}
+
+	// Forget replicas of the host which are not readonly anymore
+	w.c.readonlyReplicas.Range(func(key, _ interface{}) bool {
+		if strings.HasPrefix(key.(string), prefix) && !util.InArray(key.(string), readonly) {
+			w.c.readonlyReplicas.Delete(key)
+		}
+		return true
+	})
+
+	return problems
+}
@@ -0,0 +1,246 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	log "github.com/altinity/clickhouse-operator/pkg/announcer"
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/schemer"
+	"github.com/altinity/clickhouse-operator/pkg/util"
+)
+
+// startRebalancing starts rebalancing of clusters shards were added to by the reconcile
+// and runs the first step of all rebalancing in progress
+func (w *worker) startRebalancing(ctx context.Context, chi *api.ClickHouseInstallation) {
+	if util.IsContextDone(ctx) {
+		log.V(2).Info("task is done")
+		return
+	}
+
+	ancestor := chi.GetAncestor()
+	chi.WalkClusters(func(cluster *api.Cluster) error {
+		if !cluster.Rebalancing.IsEnabled() || (ancestor == nil) {
+			return nil
+		}
+		// Brand-new cluster has no historical data to be moved
+		old := ancestor.FindCluster(cluster.Name)
+		if old == nil {
+			return nil
+		}
+
+		var added []string
+		cluster.WalkShards(func(index int, shard *api.ChiShard) error {
+			if old.FindShard(shard.Name) == nil {
+				added = append(added, shard.Name)
+			}
+			return nil
+		})
+		if len(added) == 0 {
+			return nil
+		}
+
+		rebalancing, found := chi.EnsureStatus().GetClusterRebalancing(cluster.Name)
+		if found && rebalancing.IsInProgress() {
+			rebalancing.Shards = util.MergeStringArrays(rebalancing.Shards, added)
+		} else {
+			rebalancing = api.NewChiRebalancingStatus(cluster.Name, added)
+		}
+		w.a.V(1).M(chi).F().Info("start rebalancing of cluster %s to shards %v", cluster.Name, rebalancing.Shards)
+		chi.EnsureStatus().SetRebalancing(rebalancing)
+		return nil
+	})
+
+	w.rebalance(ctx, chi)
+}
+
+// continueRebalancing runs next step of rebalancing in progress of the already reconciled CHI
+func (w *worker) continueRebalancing(ctx context.Context, chi *api.ClickHouseInstallation) {
+	inProgress := false
+	for _, rebalancing := range chi.GetStatus().GetRebalancing() {
+		inProgress = inProgress || rebalancing.IsInProgress()
+	}
+	if !inProgress || !chi.HasAncestor() {
+		return
+	}
+
+	// Ancestor is the CHI as it was reconciled the last time
+	normalized := w.normalize(chi.GetAncestor())
+	normalized.EnsureStatus().CopyFrom(chi.GetStatus(), api.CopyCHIStatusOptions{
+		Rebalancing: true,
+	})
+	w.rebalance(ctx, normalized)
+}
+
+// rebalance runs one step of each rebalancing in progress of the normalized CHI
+func (w *worker) rebalance(ctx context.Context, chi *api.ClickHouseInstallation) {
+	if isFrozen(chi) {
+		// Data is not moved during freeze, rebalancing proceeds once the freeze is lifted
+		return
+	}
+	for _, rebalancing := range chi.EnsureStatus().GetRebalancing() {
+		if !rebalancing.IsInProgress() {
+			continue
+		}
+		if util.IsContextDone(ctx) {
+			log.V(2).Info("task is done")
+			return
+		}
+
+		cluster := chi.FindCluster(rebalancing.Cluster)
+		switch {
+		case (cluster == nil) || !cluster.Rebalancing.IsEnabled() || chi.IsStopped():
+			// Cluster may be removed or renamed while rebalancing is in progress
+			w.a.V(1).M(chi).F().Info("rebalancing of cluster %s is cancelled", rebalancing.Cluster)
+			rebalancing.Status = api.RebalancingStatusCancelled
+		case !isRebalancingPauseElapsed(rebalancing, cluster.Rebalancing.GetPause(), time.Now()):
+			// Status is left untouched, as its update time marks the previous move
+			continue
+		default:
+			w.rebalanceCluster(ctx, chi, cluster, &rebalancing)
+		}
+		w.updateRebalancingStatus(ctx, chi, rebalancing)
+	}
+}
+
+// rebalanceCluster moves limited number of partitions from old shards of the cluster to the added ones
+func (w *worker) rebalanceCluster(
+	ctx context.Context,
+	chi *api.ClickHouseInstallation,
+	cluster *api.Cluster,
+	rebalancing *api.ChiRebalancingStatus,
+) {
+	var sources, targets []*schemer.ShardPartitions
+	var err error
+	cluster.WalkShards(func(index int, shard *api.ChiShard) error {
+		if (err != nil) || shard.IsFrozen() {
+			// Frozen shards neither give nor receive partitions
+			return nil
+		}
+		var partitions *schemer.ShardPartitions
+		if partitions, err = w.getShardPartitions(ctx, shard, cluster.Rebalancing); (err != nil) || (partitions == nil) {
+			return nil
+		}
+		if rebalancing.IsTargetShard(shard.Name) {
+			targets = append(targets, partitions)
+		} else {
+			sources = append(sources, partitions)
+		}
+		return nil
+	})
+	if err != nil {
+		w.a.V(1).M(chi).F().Warning("rebalancing of cluster %s failed. err: %v", cluster.Name, err)
+		rebalancing.Error = err.Error()
+		return
+	}
+
+	moves := schemer.PlanRebalance(sources, targets, cluster.Rebalancing.PartitionsPerRun)
+	if len(moves) == 0 {
+		w.a.V(1).M(chi).F().Info("rebalancing of cluster %s completed", cluster.Name)
+		rebalancing.Status = api.RebalancingStatusCompleted
+		rebalancing.PartitionsPending = 0
+		rebalancing.Error = ""
+		return
+	}
+
+	// Only the first planned move is run, the rest are left to the next resyncs, so the worker is not blocked for long
+	rebalancing.PartitionsPending = len(moves)
+	_ = w.movePartitions(ctx, chi, cluster, rebalancing, moves[:1])
+}
+
+// isRebalancingPauseElapsed checks whether pause since the previous step of the rebalancing is over
+func isRebalancingPauseElapsed(rebalancing api.ChiRebalancingStatus, pause time.Duration, now time.Time) bool {
+	if pause <= 0 {
+		return true
+	}
+	updated, err := time.Parse(time.RFC3339, rebalancing.UpdateTime)
+	if err != nil {
+		return true
+	}
+	return now.Sub(updated) >= pause
+}
+
+// getShardPartitions gets partitions subject to rebalancing of the shard.
+// Nil is returned for shard without hosts.
+func (w *worker) getShardPartitions(
+	ctx context.Context,
+	shard *api.ChiShard,
+	rebalancing *api.ChiClusterRebalancing,
+) (*schemer.ShardPartitions, error) {
+	host := shard.FirstHost()
+	if host == nil {
+		return nil, nil
+	}
+	partitions, err := w.ensureClusterSchemer(host).HostShardPartitions(ctx, host)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get partitions of shard %s: %w", shard.Name, err)
+	}
+	partitions.Partitions = filterRebalancedPartitions(rebalancing, partitions.Partitions)
+	return partitions, nil
+}
+
+// movePartitions moves partitions one by one, reporting progress in the rebalancing status of the cluster
+func (w *worker) movePartitions(
+	ctx context.Context,
+	chi *api.ClickHouseInstallation,
+	cluster *api.Cluster,
+	rebalancing *api.ChiRebalancingStatus,
+	moves []*schemer.PartitionMove,
+) error {
+	for i, move := range moves {
+		if i > 0 && util.WaitContextDoneOrTimeout(ctx, cluster.Rebalancing.GetPause()) {
+			log.V(2).Info("task is done")
+			return ctx.Err()
+		}
+		if err := w.ensureClusterSchemer(move.To.Host).MovePartition(ctx, move); err != nil {
+			w.a.V(1).M(chi).F().Warning(
+				"unable to move partition %s of %s in cluster %s. err: %v",
+				move.Partition.ID, move.Partition.GetTableName(), cluster.Name, err,
+			)
+			rebalancing.Error = err.Error()
+			return err
+		}
+		rebalancing.PartitionsMoved++
+		rebalancing.BytesMoved += int64(move.Partition.Bytes)
+		rebalancing.PartitionsPending--
+		rebalancing.Error = ""
+		w.updateRebalancingStatus(ctx, chi, *rebalancing)
+	}
+	return nil
+}
+
+// filterRebalancedPartitions selects partitions of tables subject to rebalancing
+func filterRebalancedPartitions(rebalancing *api.ChiClusterRebalancing, partitions []*schemer.Partition) (res []*schemer.Partition) {
+	for _, partition := range partitions {
+		if rebalancing.IncludesTable(partition.Database, partition.Table) {
+			res = append(res, partition)
+		}
+	}
+	return res
+}
+
+// updateRebalancingStatus stores rebalancing status of a cluster in the CHI status
+func (w *worker) updateRebalancingStatus(ctx context.Context, chi *api.ClickHouseInstallation, rebalancing api.ChiRebalancingStatus) {
+	chi.EnsureStatus().SetRebalancing(rebalancing)
+	_ = w.c.updateCHIObjectStatus(ctx, chi, UpdateCHIStatusOptions{
+		TolerateAbsence: true,
+		CopyCHIStatusOptions: api.CopyCHIStatusOptions{
+			Rebalancing: true,
+		},
+	})
+}
@@ -0,0 +1,79 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/chop"
+)
+
+// resyncCheck specifies periodic check done on resync of already reconciled CHI
+type resyncCheck struct {
+	name string
+	// interval specifies minimal number of seconds between two runs of the check for the same CHI
+	interval int
+	run      func(ctx context.Context, chi *api.ClickHouseInstallation)
+}
+
+// getResyncChecks gets periodic checks along with their intervals
+func (w *worker) getResyncChecks() []resyncCheck {
+	intervals := chop.Config().Reconcile.Resync
+	return []resyncCheck{
+		{name: "diskUsage", interval: intervals.DiskUsage, run: w.checkDiskUsage},
+		{name: "quotaUsage", interval: intervals.QuotaUsage, run: w.checkQuotaUsage},
+		{name: "configDrift", interval: intervals.ConfigDrift, run: w.checkConfigDrift},
+		{name: "hostsHealth", interval: intervals.HostsHealth, run: w.checkHostsHealth},
+		{name: "readonlyReplicas", interval: intervals.ReadonlyReplicas, run: w.checkReadonlyReplicas},
+		{name: "rebalancing", interval: intervals.Rebalancing, run: w.continueRebalancing},
+		{name: "scheduledRestart", interval: intervals.ScheduledRestart, run: w.restartScheduledHosts},
+		{name: "certificateRotation", interval: intervals.CertificateRotation, run: w.rotateCertificates},
+	}
+}
+
+// runResyncChecks runs periodic checks of already reconciled CHI, which are due
+func (w *worker) runResyncChecks(ctx context.Context, chi *api.ClickHouseInstallation) {
+	now := time.Now()
+	for _, check := range w.getResyncChecks() {
+		if w.isResyncCheckDue(chi, check, now) {
+			check.run(ctx, chi)
+		}
+	}
+}
+
+// isResyncCheckDue checks whether interval of the check has passed since its last run for the CHI.
+// Due check is considered to be run at the specified time
+func (w *worker) isResyncCheckDue(chi *api.ClickHouseInstallation, check resyncCheck, now time.Time) bool {
+	key := chi.Namespace + "/" + chi.Name + "/" + check.name
+	if last, ok := w.c.resyncChecks.Load(key); ok && (now.Sub(last.(time.Time)) < time.Duration(check.interval)*time.Second) {
+		return false
+	}
+	w.c.resyncChecks.Store(key, now)
+	return true
+}
+
+// forgetResyncChecks forgets last runs of periodic checks of the CHI
+func (w *worker) forgetResyncChecks(chi *api.ClickHouseInstallation) {
+	prefix := chi.Namespace + "/" + chi.Name + "/"
+	w.c.resyncChecks.Range(func(key, _ interface{}) bool {
+		if strings.HasPrefix(key.(string), prefix) {
+			w.c.resyncChecks.Delete(key)
+		}
+		return true
+	})
+}
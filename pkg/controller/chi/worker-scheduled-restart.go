@@ -0,0 +1,105 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"context"
+	"time"
+
+	log "github.com/altinity/clickhouse-operator/pkg/announcer"
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/model/k8s"
+	"github.com/altinity/clickhouse-operator/pkg/util"
+)
+
+// restartScheduledHosts restarts hosts of the already reconciled CHI which have been running longer than
+// the scheduled restart interval of their clusters. Within the maintenance window at most one host per shard is restarted
+// at a time, the one running the longest, and only in case all replicas of the shard are ready, so the shard keeps quorum.
+func (w *worker) restartScheduledHosts(ctx context.Context, chi *api.ClickHouseInstallation) {
+	if util.IsContextDone(ctx) {
+		log.V(2).Info("task is done")
+		return
+	}
+
+	// Ancestor is the CHI as it was reconciled the last time
+	if !chi.HasAncestor() || chi.IsStopped() || isFrozen(chi) {
+		return
+	}
+	normalized := w.normalize(chi.GetAncestor())
+
+	now := time.Now()
+	normalized.WalkClusters(func(cluster *api.Cluster) error {
+		restart := cluster.ScheduledRestart
+		if !restart.IsEnabled() || !restart.IsInWindow(now) {
+			return nil
+		}
+		cluster.WalkShards(func(index int, shard *api.ChiShard) error {
+			// Replicas of frozen shards are left for manual maintenance
+			if !shard.IsFrozen() {
+				w.restartScheduledShardHost(ctx, shard, restart, now)
+			}
+			return nil
+		})
+		return nil
+	})
+}
+
+// restartScheduledShardHost restarts the host of the shard running the longest, in case it is due for restart
+func (w *worker) restartScheduledShardHost(
+	ctx context.Context,
+	shard *api.ChiShard,
+	restart *api.ChiClusterScheduledRestart,
+	now time.Time,
+) {
+	// Restart of the only replica makes the shard unavailable
+	if len(shard.Hosts) < 2 {
+		return
+	}
+
+	var due *api.ChiHost
+	var dueSince time.Time
+	for _, host := range shard.Hosts {
+		pod, err := w.c.getPod(host)
+		if (err != nil) || !k8s.IsPodReady(pod) || (pod.Status.StartTime == nil) {
+			// Some replica is not ready, may be the one restarted previously, shard can not afford another restart
+			return
+		}
+		since := pod.Status.StartTime.Time
+		if now.Sub(since) < restart.GetInterval() {
+			continue
+		}
+		if (due == nil) || since.Before(dueSince) {
+			due = host
+			dueSince = since
+		}
+	}
+	if due == nil {
+		return
+	}
+
+	statefulSet, err := w.c.getStatefulSetByHost(due)
+	if err != nil {
+		w.a.V(1).M(due).F().Warning("unable to get StatefulSet of the host: %s err: %v", due.GetName(), err)
+		return
+	}
+	if err := w.c.statefulSetDeletePod(ctx, statefulSet, due); err != nil {
+		w.a.V(1).M(due).F().Warning("unable to restart host: %s err: %v", due.GetName(), err)
+		return
+	}
+	w.a.V(1).
+		WithEvent(due.GetCHI(), eventActionUpdate, eventReasonUpdateCompleted).
+		M(due).F().
+		Info("host %s was running since %s, scheduled restart", due.GetName(), dueSince.Format(time.RFC3339))
+}
@@ -0,0 +1,280 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"context"
+	"fmt"
+
+	apps "k8s.io/api/apps/v1"
+	core "k8s.io/api/core/v1"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	log "github.com/altinity/clickhouse-operator/pkg/announcer"
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/controller"
+	model "github.com/altinity/clickhouse-operator/pkg/model/chi"
+	"github.com/altinity/clickhouse-operator/pkg/util"
+)
+
+// Simulate computes ordered list of actions reconcile of the CHI is going to perform,
+// given current state of the cluster. Nothing is applied.
+func (c *Controller) Simulate(ctx context.Context, chi *api.ClickHouseInstallation) *model.Plan {
+	return c.newWorker(nil, true).simulateCHI(ctx, chi.DeepCopy())
+}
+
+// simulateCHI computes ordered list of actions reconcile of the CHI is going to perform
+func (w *worker) simulateCHI(ctx context.Context, chi *api.ClickHouseInstallation) *model.Plan {
+	if util.IsContextDone(ctx) {
+		log.V(2).Info("task is done")
+		return nil
+	}
+
+	w.a.V(1).M(chi).S().P()
+	defer w.a.V(1).M(chi).E().P()
+
+	chi, ap := w.prepareDesired(chi)
+	w.newTask(chi)
+	w.walkHosts(ctx, chi, ap)
+
+	plan := w.buildPlan(ctx, chi, ap)
+	w.a.V(1).M(chi).F().Info("simulated CHI: %s/%s planned actions: %d\n%s", chi.Namespace, chi.Name, plan.Len(), plan)
+	return plan
+}
+
+// buildPlan builds ordered list of actions reconcile of the normalized CHI is going to perform.
+// Expects task to be created and hosts to be walked already.
+func (w *worker) buildPlan(ctx context.Context, chi *api.ClickHouseInstallation, ap *model.ActionPlan) *model.Plan {
+	plan := model.NewPlan()
+	desired := model.NewRegistry()
+	w.planCHI(ctx, chi, plan, desired)
+	w.planRemoved(ap, plan)
+	w.planCleanup(ctx, chi, plan, desired)
+	return plan
+}
+
+// prepareDesired normalizes CHI along with its ancestor and builds action plan between them
+func (w *worker) prepareDesired(chi *api.ClickHouseInstallation) (*api.ClickHouseInstallation, *model.ActionPlan) {
+	var old *api.ClickHouseInstallation
+	if chi.HasAncestor() {
+		old = chi.GetAncestor()
+	}
+	old = w.normalize(old)
+	chi = w.normalize(chi)
+	chi.SetAncestor(old)
+
+	ap := model.NewActionPlan(old, chi)
+	w.logActionPlan(ap)
+	return chi, ap
+}
+
+// planCHI plans objects of the CHI in the same order reconcile processes them
+func (w *worker) planCHI(ctx context.Context, chi *api.ClickHouseInstallation, plan *model.Plan, desired *model.Registry) {
+	planConfigMap := func(configMap *core.ConfigMap) {
+		if configMap == nil {
+			return
+		}
+		desired.RegisterConfigMap(configMap.ObjectMeta)
+		cur, err := w.c.getConfigMap(&configMap.ObjectMeta, true)
+		planObject(plan, model.ConfigMap, configMap.ObjectMeta, cur, err)
+	}
+	planService := func(service *core.Service) {
+		if service == nil {
+			return
+		}
+		desired.RegisterService(service.ObjectMeta)
+		cur, err := w.c.getService(service)
+		planObject(plan, model.Service, service.ObjectMeta, cur, err)
+	}
+
+	planConfigMap(w.task.creator.CreateConfigMapCHICommon(nil))
+	planConfigMap(w.task.creator.CreateConfigMapCHICommonUsers())
+	planConfigMap(w.task.creator.CreateConfigMapCHILogging())
+	planConfigMap(w.task.creator.CreateConfigMapCHITopology())
+	if !chi.IsStopped() {
+		planService(w.task.creator.CreateServiceCHI())
+	}
+
+	chi.WalkClusters(func(cluster *api.Cluster) error {
+		planService(w.task.creator.CreateServiceCluster(cluster))
+		planService(w.task.creator.CreateServiceClusterReadTier(cluster))
+		planService(w.task.creator.CreateServiceClusterReader(cluster))
+		planService(w.task.creator.CreateServiceClusterWriter(cluster))
+
+		if cluster.Secret.Source() == api.ClusterSecretSourceAuto {
+			secret := w.task.creator.CreateClusterSecret(cluster)
+			desired.RegisterSecret(secret.ObjectMeta)
+			if _, err := w.c.getSecret(secret); apiErrors.IsNotFound(err) {
+				plan.Add(model.PlannedActionCreate, model.Secret, util.NamespaceNameString(secret.ObjectMeta), "")
+			}
+		}
+
+		pdb := w.task.creator.NewPodDisruptionBudget(cluster)
+		desired.RegisterPDB(pdb.ObjectMeta)
+		if _, err := w.c.kubeClient.PolicyV1().PodDisruptionBudgets(pdb.Namespace).Get(ctx, pdb.Name, controller.NewGetOptions()); apiErrors.IsNotFound(err) {
+			plan.Add(model.PlannedActionCreate, model.PDB, util.NamespaceNameString(pdb.ObjectMeta), "")
+		}
+
+		cluster.WalkShards(func(index int, shard *api.ChiShard) error {
+			planService(w.task.creator.CreateServiceShard(shard))
+			shard.WalkHosts(func(host *api.ChiHost) error {
+				if host.IsFrozen() {
+					// Frozen hosts are kept as they are
+					w.registerFrozenHost(host, desired)
+					return nil
+				}
+				planConfigMap(w.task.creator.CreateConfigMapHost(host))
+				w.planHost(ctx, host, plan, desired)
+				planService(w.task.creator.CreateServiceHost(host))
+				return nil
+			})
+			return nil
+		})
+		return nil
+	})
+
+	w.planChproxy(ctx, plan, desired, planService)
+}
+
+// planChproxy plans chproxy config, Deployment and Service of the CHI
+func (w *worker) planChproxy(ctx context.Context, plan *model.Plan, desired *model.Registry, planService func(*core.Service)) {
+	if secret := w.task.creator.CreateSecretChproxy(); secret != nil {
+		desired.RegisterSecret(secret.ObjectMeta)
+		if _, err := w.c.getSecret(secret); apiErrors.IsNotFound(err) {
+			plan.Add(model.PlannedActionCreate, model.Secret, util.NamespaceNameString(secret.ObjectMeta), "")
+		}
+	}
+	if deployment := w.task.creator.CreateDeploymentChproxy(); deployment != nil {
+		desired.RegisterDeployment(deployment.ObjectMeta)
+		cur, err := w.c.kubeClient.AppsV1().Deployments(deployment.Namespace).Get(ctx, deployment.Name, controller.NewGetOptions())
+		planObject(plan, model.Deployment, deployment.ObjectMeta, cur, err)
+	}
+	planService(w.task.creator.CreateServiceChproxy())
+}
+
+// planHost plans StatefulSet, PVCs, restart and schema migration of the host
+func (w *worker) planHost(ctx context.Context, host *api.ChiHost, plan *model.Plan, desired *model.Registry) {
+	statefulSet := w.task.creator.CreateStatefulSet(host, false)
+	desired.RegisterStatefulSet(statefulSet.ObjectMeta)
+	cur, err := w.c.getStatefulSet(&statefulSet.ObjectMeta, true)
+	if (err == nil) && w.shouldForceRestartHost(host) {
+		plan.Add(model.PlannedActionRestart, model.Host, host.GetName(), "configuration change requires restart")
+	}
+	planObject(plan, model.StatefulSet, statefulSet.ObjectMeta, cur, err)
+
+	for _, pvc := range getHostPVCs(host, statefulSet) {
+		desired.RegisterPVC(pvc)
+		if _, err := w.c.kubeClient.CoreV1().PersistentVolumeClaims(pvc.Namespace).Get(ctx, pvc.Name, controller.NewGetOptions()); apiErrors.IsNotFound(err) {
+			plan.Add(model.PlannedActionCreate, model.PVC, util.NamespaceNameString(pvc), "")
+		}
+	}
+
+	if w.shouldPlanMigrateTables(host) {
+		plan.Add(model.PlannedActionDDL, model.Host, host.GetName(), "create tables")
+	}
+}
+
+// getHostPVCs gets PVCs of the host, mounted by containers of the StatefulSet
+func getHostPVCs(host *api.ChiHost, statefulSet *apps.StatefulSet) (pvcs []meta.ObjectMeta) {
+	for i := range statefulSet.Spec.Template.Spec.Containers {
+		container := &statefulSet.Spec.Template.Spec.Containers[i]
+		for j := range container.VolumeMounts {
+			if name, ok := model.CreatePVCNameByVolumeMount(host, &container.VolumeMounts[j]); ok {
+				pvcs = append(pvcs, meta.ObjectMeta{Namespace: host.Runtime.Address.Namespace, Name: name})
+			}
+		}
+	}
+	return pvcs
+}
+
+// shouldPlanMigrateTables checks whether tables are going to be created on the host
+func (w *worker) shouldPlanMigrateTables(host *api.ChiHost) bool {
+	if host.IsStopped() || !host.GetReconcileAttributes().IsAdd() || model.HostHasTablesCreated(host) {
+		return false
+	}
+	// Tables are migrated from existing hosts, in case all hosts are new there is nothing to migrate
+	existing := false
+	host.GetCHI().WalkHosts(func(h *api.ChiHost) error {
+		existing = existing || !h.GetReconcileAttributes().IsAdd()
+		return nil
+	})
+	return existing
+}
+
+// planRemoved plans DDL on hosts removed from the CHI
+func (w *worker) planRemoved(ap *model.ActionPlan, plan *model.Plan) {
+	ap.WalkRemoved(
+		func(cluster *api.Cluster) {
+		},
+		func(shard *api.ChiShard) {
+		},
+		func(host *api.ChiHost) {
+			plan.Add(model.PlannedActionDDL, model.Host, host.GetName(), "drop replica")
+		},
+	)
+}
+
+// planCleanup plans deletion of existing objects which are not desired anymore, according to cleanup policy
+func (w *worker) planCleanup(ctx context.Context, chi *api.ClickHouseInstallation, plan *model.Plan, desired *model.Registry) {
+	objs := w.c.discovery(ctx, chi)
+	objs.Subtract(desired)
+	failed := model.NewRegistry()
+	objs.Walk(func(entityType model.EntityType, m meta.ObjectMeta) {
+		if shouldPurge(chi, failed, entityType, m) {
+			plan.Add(model.PlannedActionDelete, entityType, util.NamespaceNameString(m), "not in spec")
+		}
+	})
+}
+
+// shouldPurge checks whether object of specified entity type is going to be purged
+func shouldPurge(chi *api.ClickHouseInstallation, reconcileFailedObjs *model.Registry, entityType model.EntityType, m meta.ObjectMeta) bool {
+	switch entityType {
+	case model.StatefulSet:
+		return shouldPurgeStatefulSet(chi, reconcileFailedObjs, m)
+	case model.PVC:
+		return shouldPurgePVC(chi, reconcileFailedObjs, m)
+	case model.ConfigMap:
+		return shouldPurgeConfigMap(chi, reconcileFailedObjs, m)
+	case model.Service:
+		return shouldPurgeService(chi, reconcileFailedObjs, m)
+	case model.Secret:
+		return shouldPurgeSecret(chi, reconcileFailedObjs, m)
+	case model.PDB:
+		return shouldPurgePDB(chi, reconcileFailedObjs, m)
+	case model.Deployment:
+		return shouldPurgeDeployment(chi, reconcileFailedObjs, m)
+	case model.VolumeSnapshot:
+		return shouldPurgeVolumeSnapshot(chi, reconcileFailedObjs, m)
+	}
+	return false
+}
+
+// planObject plans creation of missing object or update of the object with outdated version
+func planObject(plan *model.Plan, kind model.EntityType, desired meta.ObjectMeta, cur meta.Object, err error) {
+	name := util.NamespaceNameString(desired)
+	switch {
+	case apiErrors.IsNotFound(err):
+		plan.Add(model.PlannedActionCreate, kind, name, "")
+	case err != nil:
+		plan.Add(model.PlannedActionUpdate, kind, name, fmt.Sprintf("unable to get current object: %v", err))
+	default:
+		curVersion, _ := model.GetObjectVersion(meta.ObjectMeta{Labels: cur.GetLabels()})
+		desiredVersion, _ := model.GetObjectVersion(desired)
+		if curVersion != desiredVersion {
+			plan.Add(model.PlannedActionUpdate, kind, name, "")
+		}
+	}
+}
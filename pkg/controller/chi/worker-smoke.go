@@ -0,0 +1,88 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"context"
+	"fmt"
+
+	log "github.com/altinity/clickhouse-operator/pkg/announcer"
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/util"
+)
+
+// shouldRunSmokeTest checks whether smoke test has to be run on the host being reconciled
+func (w *worker) shouldRunSmokeTest(host *api.ChiHost) bool {
+	if !host.GetCHI().Spec.Reconciling.GetSmokeTest().IsEnabled() {
+		return false
+	}
+	// Host which is not changed is not verified
+	return host.GetReconcileAttributes().GetStatus() != api.ObjectStatusSame
+}
+
+// runSmokeTestBaseline runs smoke test queries without expected value on the host before it is updated.
+// Returned results are used as expected ones after the update. Failed queries are not verified after the update
+func (w *worker) runSmokeTestBaseline(ctx context.Context, host *api.ChiHost) map[string]string {
+	if !w.shouldRunSmokeTest(host) || (host.GetReconcileAttributes().GetStatus() == api.ObjectStatusNew) {
+		return nil
+	}
+
+	baseline := make(map[string]string)
+	for _, query := range host.GetCHI().Spec.Reconciling.GetSmokeTest().GetQueries() {
+		if util.IsContextDone(ctx) {
+			log.V(2).Info("task is done")
+			return nil
+		}
+		if query.HasExpect() {
+			continue
+		}
+		value, err := w.ensureClusterSchemer(host).HostSmokeTestQuery(ctx, host, &query)
+		if err != nil {
+			w.a.V(1).M(host).F().Warning("smoke test query %s baseline is unavailable on the host: %s err: %v", query.Name, host.GetName(), err)
+			continue
+		}
+		baseline[query.Name] = value
+	}
+	return baseline
+}
+
+// runSmokeTest runs smoke test queries on the updated host and verifies results
+// either against expected values or against the baseline taken before the update
+func (w *worker) runSmokeTest(ctx context.Context, host *api.ChiHost, baseline map[string]string) error {
+	if !w.shouldRunSmokeTest(host) {
+		return nil
+	}
+
+	for _, query := range host.GetCHI().Spec.Reconciling.GetSmokeTest().GetQueries() {
+		if util.IsContextDone(ctx) {
+			log.V(2).Info("task is done")
+			return nil
+		}
+		value, err := w.ensureClusterSchemer(host).HostSmokeTestQuery(ctx, host, &query)
+		if err != nil {
+			return fmt.Errorf("smoke test query %s failed: %v", query.Name, err)
+		}
+		expect, ok := query.Expect, query.HasExpect()
+		if !ok {
+			expect, ok = baseline[query.Name]
+		}
+		if ok && (value != expect) {
+			return fmt.Errorf("smoke test query %s returned %q, expected %q", query.Name, value, expect)
+		}
+	}
+
+	w.a.V(1).M(host).F().Info("smoke test passed on the host: %s", host.GetName())
+	return nil
+}
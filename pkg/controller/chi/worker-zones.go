@@ -0,0 +1,91 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"context"
+	"strings"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+)
+
+// reconcileShardsAndHostsByZones reconciles shards and then hosts of the shards zone by zone.
+// Within each zone shards are processed the same way as in case of no zones, starting with the first shard separately
+func (w *worker) reconcileShardsAndHostsByZones(
+	ctx context.Context,
+	shards []*api.ChiShard,
+	zones []string,
+	opts *ReconcileShardsAndHostsOptions,
+) error {
+	for _, shard := range shards {
+		if err := w.reconcileShard(ctx, shard); err != nil {
+			return err
+		}
+	}
+
+	for _, zone := range zones {
+		w.a.V(1).Info("Starting hosts of zone: %s", zone)
+		err := w.reconcileShardsWith(ctx, shards, opts, func(ctx context.Context, shard *api.ChiShard) error {
+			for _, host := range getShardHostsOfZone(shard, zone) {
+				if err := w.reconcileHost(ctx, host); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			w.a.V(1).Warning("Skipping rest of zones due to an error in zone %s: %v", zone, err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getReconcileZones gets zones of hosts of the shards in order of appearance.
+// Hosts not assigned to any zone form a zone of their own with an empty name
+func getReconcileZones(shards []*api.ChiShard) []string {
+	var zones []string
+	seen := make(map[string]bool)
+	for _, shard := range shards {
+		for _, host := range shard.Hosts {
+			zone := getHostZone(host)
+			if !seen[zone] {
+				seen[zone] = true
+				zones = append(zones, zone)
+			}
+		}
+	}
+	return zones
+}
+
+// getShardHostsOfZone gets hosts of the shard in the zone, read tier hosts are placed last
+func getShardHostsOfZone(shard *api.ChiShard, zone string) []*api.ChiHost {
+	var hosts []*api.ChiHost
+	for _, host := range shard.HostsReadTierLast() {
+		if getHostZone(host) == zone {
+			hosts = append(hosts, host)
+		}
+	}
+	return hosts
+}
+
+// getHostZone gets name of the zone the host is scheduled to
+func getHostZone(host *api.ChiHost) string {
+	if host.Zone == nil {
+		return ""
+	}
+	return strings.Join(host.Zone.Values, ",")
+}
@@ -37,10 +37,12 @@ import (
 	model "github.com/altinity/clickhouse-operator/pkg/model/chi"
 	chiCreator "github.com/altinity/clickhouse-operator/pkg/model/chi/creator"
 	"github.com/altinity/clickhouse-operator/pkg/model/chi/normalizer"
+	templatesNormalizer "github.com/altinity/clickhouse-operator/pkg/model/chi/normalizer/templates"
 	"github.com/altinity/clickhouse-operator/pkg/model/chi/schemer"
 	"github.com/altinity/clickhouse-operator/pkg/model/clickhouse"
 	"github.com/altinity/clickhouse-operator/pkg/model/k8s"
 	"github.com/altinity/clickhouse-operator/pkg/util"
+	"github.com/altinity/clickhouse-operator/pkg/version"
 )
 
 // FinalizerName specifies name of the finalizer to be used with CHI
@@ -65,6 +67,7 @@ type task struct {
 	registryFailed     *model.Registry
 	cmUpdate           time.Time
 	start              time.Time
+	approval           *approvalGate
 }
 
 // newTask creates new context
@@ -114,6 +117,41 @@ func (w *worker) isConfigurationChangeRequiresReboot(host *api.ChiHost) bool {
 	return model.IsConfigurationChangeRequiresReboot(host)
 }
 
+// reloadHostConfiguration applies dictionaries and executable user-defined functions changes
+// on the running host by reloading them instead of restarting the host
+func (w *worker) reloadHostConfiguration(ctx context.Context, host *api.ChiHost) {
+	if host.IsStopped() || host.GetCHI().IsRollingUpdate() || w.isRestartRequested(host.GetCHI()) {
+		return
+	}
+
+	reloadDictionaries := model.IsConfigurationChangeRequiresDictionariesReload(host)
+	reloadFunctions := model.IsConfigurationChangeRequiresFunctionsReload(host)
+	if !reloadDictionaries && !reloadFunctions {
+		return
+	}
+
+	// Reload has to see new files, so wait for ConfigMap to be propagated into the pod
+	if w.waitConfigMapPropagation(ctx, host) {
+		log.V(2).Info("task is done")
+		return
+	}
+
+	if reloadDictionaries {
+		if err := w.ensureClusterSchemer(host).HostReloadDictionaries(ctx, host); err == nil {
+			w.a.V(1).M(host).F().Info("Dictionaries reloaded. Host: %s", host.GetName())
+		} else {
+			w.a.V(1).M(host).F().Warning("Unable to reload dictionaries. Host: %s Err: %v", host.GetName(), err)
+		}
+	}
+	if reloadFunctions {
+		if err := w.ensureClusterSchemer(host).HostReloadFunctions(ctx, host); err == nil {
+			w.a.V(1).M(host).F().Info("Functions reloaded. Host: %s", host.GetName())
+		} else {
+			w.a.V(1).M(host).F().Warning("Unable to reload functions. Host: %s Err: %v", host.GetName(), err)
+		}
+	}
+}
+
 // shouldForceRestartHost checks whether cluster requires hosts restart
 func (w *worker) shouldForceRestartHost(host *api.ChiHost) bool {
 	// RollingUpdate purpose is to always shut the host down.
@@ -123,6 +161,11 @@ func (w *worker) shouldForceRestartHost(host *api.ChiHost) bool {
 		return true
 	}
 
+	if w.isRestartRequested(host.GetCHI()) && (host.GetReconcileAttributes().GetStatus() != api.ObjectStatusNew) {
+		w.a.V(1).M(host).F().Info("Restart annotation requires force restart. Host: %s", host.GetName())
+		return true
+	}
+
 	if host.GetReconcileAttributes().GetStatus() == api.ObjectStatusNew {
 		w.a.V(1).M(host).F().Info("Host is new, no restart applicable. Host: %s", host.GetName())
 		return false
@@ -284,6 +327,10 @@ func (w *worker) processReconcilePod(ctx context.Context, cmd *ReconcilePod) err
 }
 
 func (w *worker) processDropDns(ctx context.Context, cmd *DropDns) error {
+	if chop.Config().IsObserveMode() {
+		// Nothing is applied in observe mode
+		return nil
+	}
 	if chi, err := w.createCHIFromObjectMeta(cmd.initiator, false, normalizer.NewOptions()); err == nil {
 		w.a.V(2).M(cmd.initiator).Info("flushing DNS for CHI %s", chi.Name)
 		_ = w.ensureClusterSchemer(chi.FirstHost()).CHIDropDnsCache(ctx, chi)
@@ -327,7 +374,10 @@ func (w *worker) processItem(ctx context.Context, item interface{}) error {
 // normalize
 func (w *worker) normalize(c *api.ClickHouseInstallation) *api.ClickHouseInstallation {
 
-	chi, err := w.normalizer.CreateTemplatedCHI(c, normalizer.NewOptions())
+	reserved := w.getReservedHostPorts(c)
+	opts := normalizer.NewOptions()
+	opts.ReservedHostPorts = reserved
+	chi, err := w.normalizer.CreateTemplatedCHI(c, opts)
 	if err != nil {
 		w.a.WithEvent(chi, eventActionReconcile, eventReasonReconcileFailed).
 			WithStatusError(chi).
@@ -337,8 +387,9 @@ func (w *worker) normalize(c *api.ClickHouseInstallation) *api.ClickHouseInstall
 
 	ips := w.c.getPodsIPs(chi)
 	w.a.V(1).M(chi).Info("IPs of the CHI normalizer %s/%s: len: %d %v", chi.Namespace, chi.Name, len(ips), ips)
-	opts := normalizer.NewOptions()
+	opts = normalizer.NewOptions()
 	opts.DefaultUserAdditionalIPs = ips
+	opts.ReservedHostPorts = reserved
 
 	chi, err = w.normalizer.CreateTemplatedCHI(c, opts)
 	if err != nil {
@@ -382,6 +433,10 @@ func (w *worker) ensureFinalizer(ctx context.Context, chi *api.ClickHouseInstall
 
 // updateEndpoints updates endpoints
 func (w *worker) updateEndpoints(ctx context.Context, old, new *core.Endpoints) error {
+	if chop.Config().IsObserveMode() {
+		// Nothing is applied in observe mode
+		return nil
+	}
 
 	if chi, err := w.createCHIFromObjectMeta(&new.ObjectMeta, false, normalizer.NewOptions()); err == nil {
 		w.a.V(1).M(chi).Info("updating endpoints for CHI-1 %s", chi.Name)
@@ -425,12 +480,27 @@ func (w *worker) updateCHI(ctx context.Context, old, new *api.ClickHouseInstalla
 	if update && (old.ObjectMeta.ResourceVersion == new.ObjectMeta.ResourceVersion) {
 		// No need to react
 		w.a.V(3).M(new).F().Info("ResourceVersion did not change: %s", new.ObjectMeta.ResourceVersion)
+		// Periodic resync is used to keep an eye on disk and quota usage, config drift, health of hosts and readonly replicas,
+		// to move rebalancing on, to restart hosts on schedule, to rotate certificates
+		// and to apply disruptive actions held by the freeze once it is lifted
+		if !chop.Config().IsObserveMode() {
+			if w.isFreezeLifted(new) {
+				w.a.V(1).M(new).F().Info("freeze is lifted, reconcile held disruptive actions")
+				return w.reconcileCHI(ctx, old, new)
+			}
+			w.runResyncChecks(ctx, new)
+		}
 		return nil
 	}
 
 	w.a.V(1).M(new).S().P()
 	defer w.a.V(1).M(new).E().P()
 
+	if chop.Config().IsObserveMode() {
+		// Nothing is applied in observe mode, neither finalizer nor reconcile
+		return w.observeCHI(ctx, old, new)
+	}
+
 	if w.ensureFinalizer(context.Background(), new) {
 		w.a.M(new).F().Info("finalizer installed, let's restart reconcile cycle. CHI: %s/%s", new.Namespace, new.Name)
 		w.a.M(new).F().Info("---------------------------------------------------------------------")
@@ -460,6 +530,12 @@ func (w *worker) updateCHI(ctx context.Context, old, new *api.ClickHouseInstalla
 		return nil
 	}
 
+	if w.isCHIObserved(ctx, new) {
+		// Neither spec nor dependencies changed since the last successful reconcile
+		w.a.V(1).M(new).F().Info("Will not reconcile already observed generation. Generation %d", new.Generation)
+		return nil
+	}
+
 	if util.IsContextDone(ctx) {
 		log.V(2).Info("task is done")
 		return nil
@@ -529,6 +605,73 @@ func (w *worker) isCleanRestart(chi *api.ClickHouseInstallation) bool {
 	return generationIsOk
 }
 
+// isCHIObserved checks whether the generation of the CHI was already reconciled successfully
+// by the same operator and neither metadata of the CHI nor templates and secrets the CHI depends on has changed since then
+func (w *worker) isCHIObserved(ctx context.Context, chi *api.ClickHouseInstallation) bool {
+	status := chi.EnsureStatus()
+	switch {
+	case status.GetStatus() != api.StatusCompleted:
+		return false
+	case w.isRestartRequested(chi):
+		return false
+	case isPendingPlanApproved(chi):
+		// Approval of the plan held by the completed reconcile does not change generation
+		return false
+	case status.GetObservedGeneration() != chi.Generation:
+		return false
+	case status.GetCHOpVersion() != version.Version:
+		// Operator was upgraded, generated objects may differ
+		return false
+	}
+	if ip, _ := chop.Get().ConfigManager.GetRuntimeParam(deployment.OPERATOR_POD_IP); ip != status.GetCHOpIP() {
+		// Operator IP is used in generated configuration
+		return false
+	}
+
+	// Build current dependencies list
+	var current []string
+	for _, template := range templatesNormalizer.ListApplicableTemplates(chi, true) {
+		current = append(current, model.CreateTemplateDependency(template))
+	}
+	// Labels and annotations are propagated to CHI objects, however their change does not change generation
+	current = append(current, model.CreateMetadataDependency(chi))
+	for _, observed := range status.GetDependencies() {
+		dependency, ok := model.ParseDependency(observed)
+		if !ok || (dependency.Kind != model.DependencyKindSecret) {
+			continue
+		}
+		secret, err := w.c.kubeClient.CoreV1().Secrets(dependency.Namespace).Get(ctx, dependency.Name, controller.NewGetOptions())
+		if err != nil {
+			// Secret is not available anymore, treat as a change
+			return false
+		}
+		current = append(current, model.CreateSecretDependency(secret))
+	}
+
+	observed := status.GetDependencies()
+	if len(current) != len(observed) {
+		return false
+	}
+	for _, dependency := range current {
+		if !util.InArray(dependency, observed) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isRestartRequested checks whether user has changed restart annotation since the last completed reconcile,
+// which requires rolling restart of all hosts even for the same generation
+func (w *worker) isRestartRequested(chi *api.ClickHouseInstallation) bool {
+	requested := chi.Annotations[model.AnnotationRestart]
+	if (requested == "") || !chi.HasAncestor() {
+		// Hosts of the CHI never reconciled are created anyway
+		return false
+	}
+	return requested != chi.GetAncestor().Annotations[model.AnnotationRestart]
+}
+
 // areUsableOldAndNew checks whether there are old and new usable
 func (w *worker) areUsableOldAndNew(old, new *api.ClickHouseInstallation) bool {
 	if old == nil {
@@ -649,6 +792,7 @@ func (w *worker) markReconcileStart(ctx context.Context, chi *api.ClickHouseInst
 
 	// Write desired normalized CHI with initialized .Status, so it would be possible to monitor progress
 	chi.EnsureStatus().ReconcileStart(ap.GetRemovedHostsNum())
+	chi.EnsureStatus().PushHistory(api.NewChiHistoryEntry(chi.Generation, chi.Spec.GetTaskID(), ap.Summary()))
 	_ = w.c.updateCHIObjectStatus(ctx, chi, UpdateCHIStatusOptions{
 		CopyCHIStatusOptions: api.CopyCHIStatusOptions{
 			MainFields: true,
@@ -681,9 +825,16 @@ func (w *worker) finalizeReconcileAndMarkCompleted(ctx context.Context, _chi *ap
 		opts.DefaultUserAdditionalIPs = ips
 		if chi, err := w.createCHIFromObjectMeta(&_chi.ObjectMeta, true, opts); err == nil {
 			w.a.V(1).M(chi).Info("Update users IPS-2")
-			chi.SetAncestor(chi.GetTarget())
+			if w.task.approval.isPending() {
+				// Keep ancestor, so disruptive actions are planned again once approved
+				w.a.V(1).M(chi).F().Info("disruptive actions are pending approval, ancestor is kept")
+			} else {
+				chi.SetAncestor(chi.GetTarget())
+			}
 			chi.SetTarget(nil)
+			chi.EnsureStatus().SetObservedGeneration(chi.Generation)
 			chi.EnsureStatus().ReconcileComplete()
+			metricsCHIFootprintSet(chi)
 			// TODO unify with update endpoints
 			w.newTask(chi)
 			w.reconcileCHIConfigMapUsers(ctx, chi)
@@ -718,6 +869,14 @@ func (w *worker) markReconcileCompletedUnsuccessfully(ctx context.Context, chi *
 		chi.EnsureStatus().ReconcileComplete()
 	case errors.Is(err, errCRUDAbort):
 		chi.EnsureStatus().ReconcileAbort()
+	case errors.Is(err, errPreflightFailed):
+		chi.EnsureStatus().ReconcileAbort()
+	case errors.Is(err, errPolicyRejected):
+		chi.EnsureStatus().ReconcileAbort()
+	case errors.Is(err, errDrainFailed):
+		chi.EnsureStatus().ReconcileAbort()
+	default:
+		chi.EnsureStatus().FinishHistory(api.HistoryOutcomeFailed)
 	}
 	w.c.updateCHIObjectStatus(ctx, chi, UpdateCHIStatusOptions{
 		CopyCHIStatusOptions: api.CopyCHIStatusOptions{
@@ -906,6 +1065,18 @@ func (w *worker) migrateTables(ctx context.Context, host *api.ChiHost, opts ...*
 		return nil
 	}
 
+	// Replicated databases are created on each host, since databases may be added to existing clusters.
+	// Creation is idempotent, tables of these databases are replicated by ClickHouse itself
+	if err := w.ensureClusterSchemer(host).HostCreateReplicatedDatabases(ctx, host); err != nil {
+		w.a.V(1).
+			WithEvent(host.GetCHI(), eventActionCreate, eventReasonCreateFailed).
+			WithStatusAction(host.GetCHI()).
+			M(host).F().
+			Error("ERROR create replicated databases on shard/host:%d/%d cluster:%s err:%v",
+				host.Runtime.Address.ShardIndex, host.Runtime.Address.ReplicaIndex, host.Runtime.Address.ClusterName, err)
+		return err
+	}
+
 	if !w.shouldMigrateTables(host, opts...) {
 		w.a.V(1).
 			M(host).F().
@@ -1231,6 +1402,11 @@ func (w *worker) shouldWaitQueries(host *api.ChiHost) bool {
 	return false
 }
 
+// shouldWaitTables determines whether host has to have tables created before it is included into cluster and services
+func (w *worker) shouldWaitTables() bool {
+	return chop.Config().Reconcile.Host.Wait.Tables.Value()
+}
+
 // shouldWaitIncludeHost determines whether reconciler should wait for the host to be included into cluster
 func (w *worker) shouldWaitIncludeHost(host *api.ChiHost) bool {
 	status := host.GetReconcileAttributes().GetStatus()
@@ -1300,7 +1476,11 @@ func (w *worker) updateConfigMap(ctx context.Context, chi *api.ClickHouseInstall
 		return nil
 	}
 
-	updatedConfigMap, err := w.c.kubeClient.CoreV1().ConfigMaps(configMap.Namespace).Update(ctx, configMap, controller.NewUpdateOptions())
+	client, err := w.c.kube(configMap.Namespace)
+	if err != nil {
+		return err
+	}
+	updatedConfigMap, err := client.CoreV1().ConfigMaps(configMap.Namespace).Update(ctx, configMap, controller.NewUpdateOptions())
 	if err == nil {
 		w.a.V(1).
 			WithEvent(chi, eventActionUpdate, eventReasonUpdateCompleted).
@@ -1328,7 +1508,11 @@ func (w *worker) createConfigMap(ctx context.Context, chi *api.ClickHouseInstall
 		return nil
 	}
 
-	_, err := w.c.kubeClient.CoreV1().ConfigMaps(configMap.Namespace).Create(ctx, configMap, controller.NewCreateOptions())
+	client, err := w.c.kube(configMap.Namespace)
+	if err != nil {
+		return err
+	}
+	_, err = client.CoreV1().ConfigMaps(configMap.Namespace).Create(ctx, configMap, controller.NewCreateOptions())
 	if err == nil {
 		w.a.V(1).
 			WithEvent(chi, eventActionCreate, eventReasonCreateCompleted).
@@ -1444,7 +1628,11 @@ func (w *worker) updateService(
 	// And only now we are ready to actually update the service with new version of the service
 	//
 
-	_, err := w.c.kubeClient.CoreV1().Services(newService.Namespace).Update(ctx, newService, controller.NewUpdateOptions())
+	client, err := w.c.kube(newService.Namespace)
+	if err != nil {
+		return err
+	}
+	_, err = client.CoreV1().Services(newService.Namespace).Update(ctx, newService, controller.NewUpdateOptions())
 	if err == nil {
 		w.a.V(1).
 			WithEvent(chi, eventActionUpdate, eventReasonUpdateCompleted).
@@ -1452,7 +1640,7 @@ func (w *worker) updateService(
 			M(chi).F().
 			Info("Update Service success: %s/%s", newService.Namespace, newService.Name)
 	} else {
-		w.a.M(chi).F().Error("Update Service fail: %s/%s failed with error %v", newService.Namespace, newService.Name)
+		w.a.M(chi).F().Error("Update Service fail: %s/%s failed with error %v", newService.Namespace, newService.Name, err)
 	}
 
 	return err
@@ -1465,7 +1653,11 @@ func (w *worker) createService(ctx context.Context, chi *api.ClickHouseInstallat
 		return nil
 	}
 
-	_, err := w.c.kubeClient.CoreV1().Services(service.Namespace).Create(ctx, service, controller.NewCreateOptions())
+	client, err := w.c.kube(service.Namespace)
+	if err != nil {
+		return err
+	}
+	_, err = client.CoreV1().Services(service.Namespace).Create(ctx, service, controller.NewCreateOptions())
 	if err == nil {
 		w.a.V(1).
 			WithEvent(chi, eventActionCreate, eventReasonCreateCompleted).
@@ -1490,7 +1682,11 @@ func (w *worker) createSecret(ctx context.Context, chi *api.ClickHouseInstallati
 		return nil
 	}
 
-	_, err := w.c.kubeClient.CoreV1().Secrets(secret.Namespace).Create(ctx, secret, controller.NewCreateOptions())
+	client, err := w.c.kube(secret.Namespace)
+	if err != nil {
+		return err
+	}
+	_, err = client.CoreV1().Secrets(secret.Namespace).Create(ctx, secret, controller.NewCreateOptions())
 	if err == nil {
 		w.a.V(1).
 			WithEvent(chi, eventActionCreate, eventReasonCreateCompleted).
@@ -1759,10 +1955,21 @@ func (w *worker) recreateStatefulSet(ctx context.Context, host *api.ChiHost, reg
 
 // applyPVCResourcesRequests
 func (w *worker) applyPVCResourcesRequests(
+	host *api.ChiHost,
 	pvc *core.PersistentVolumeClaim,
 	template *api.ChiVolumeClaimTemplate,
 ) bool {
-	return w.applyResourcesList(pvc.Spec.Resources.Requests, template.Spec.Resources.Requests)
+	desired := template.Spec.Resources.Requests
+	if model.GetAutoExpansion(host, template).IsEnabled() {
+		// Storage of automatically expanded PVC is not shrunk back to the template
+		cur, curOk := pvc.Spec.Resources.Requests[core.ResourceStorage]
+		requested, requestedOk := desired[core.ResourceStorage]
+		if curOk && requestedOk && (cur.Cmp(requested) > 0) {
+			desired = desired.DeepCopy()
+			desired[core.ResourceStorage] = cur
+		}
+	}
+	return w.applyResourcesList(pvc.Spec.Resources.Requests, desired)
 }
 
 // applyResourcesList
@@ -1829,6 +2036,14 @@ func (w *worker) ensureClusterSchemer(host *api.ChiHost) *schemer.ClusterSchemer
 	}
 	// Make base cluster connection params
 	clusterConnectionParams := clickhouse.NewClusterConnectionParamsFromCHOpConfig(chop.Config())
+	// Adjust base cluster connection params with per-CHI timeouts
+	queries := host.GetCHI().GetReconciling().GetQueries()
+	if timeout := queries.GetConnectTimeout(); timeout > 0 {
+		clusterConnectionParams.SetConnectTimeout(timeout)
+	}
+	if timeout := queries.GetQueryTimeout(); timeout > 0 {
+		clusterConnectionParams.SetQueryTimeout(timeout)
+	}
 	// Adjust base cluster connection params with per-host props
 	switch clusterConnectionParams.Scheme {
 	case api.ChSchemeAuto:
@@ -1845,7 +2060,10 @@ func (w *worker) ensureClusterSchemer(host *api.ChiHost) *schemer.ClusterSchemer
 	case api.ChSchemeHTTPS:
 		clusterConnectionParams.Port = int(host.HTTPSPort)
 	}
-	w.schemer = schemer.NewClusterSchemer(clusterConnectionParams, host.Runtime.Version)
+	w.schemer = schemer.NewClusterSchemer(clusterConnectionParams, host.Runtime.Version).SetQueries(queries)
+	if chop.Config().IsHealthCheckModeExec() {
+		w.schemer.SetHealthChecker(w.execHostQuery)
+	}
 
 	return w.schemer
 }
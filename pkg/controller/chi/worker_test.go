@@ -0,0 +1,228 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/apis/deployment"
+	"github.com/altinity/clickhouse-operator/pkg/chop"
+	model "github.com/altinity/clickhouse-operator/pkg/model/chi"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/builder"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/normalizer"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/render"
+)
+
+func TestIsRestartRequested(t *testing.T) {
+	render.Init("")
+	w := &worker{}
+	normalize := func(restart string) *api.ClickHouseInstallation {
+		chi := builder.NewCHI("test", "restart", builder.WithCluster(builder.NewCluster("main")))
+		if restart != "" {
+			chi.Annotations = map[string]string{model.AnnotationRestart: restart}
+		}
+		normalized, err := normalizer.NewNormalizer(render.NoSecrets).CreateTemplatedCHI(chi, normalizer.NewOptions())
+		if err != nil {
+			t.Fatalf("unable to normalize err: %v", err)
+		}
+		return normalized
+	}
+
+	tests := []struct {
+		ancestor  string
+		restart   string
+		requested bool
+	}{
+		{ancestor: "", restart: "", requested: false},
+		{ancestor: "", restart: "1", requested: true},
+		{ancestor: "1", restart: "1", requested: false},
+		{ancestor: "1", restart: "2", requested: true},
+		{ancestor: "1", restart: "", requested: false},
+	}
+	for _, test := range tests {
+		chi := normalize(test.restart)
+		chi.SetAncestor(normalize(test.ancestor))
+		if requested := w.isRestartRequested(chi); requested != test.requested {
+			t.Errorf("ancestor %q restart %q: got requested %v want %v", test.ancestor, test.restart, requested, test.requested)
+		}
+	}
+
+	// CHI never reconciled has no hosts to restart
+	if w.isRestartRequested(normalize("1")) {
+		t.Errorf("restart must not be requested for CHI without ancestor")
+	}
+}
+
+func TestIsCHIObservedMetadata(t *testing.T) {
+	render.Init("")
+	ctx := context.Background()
+	w := &worker{}
+	ip, _ := chop.Get().ConfigManager.GetRuntimeParam(deployment.OPERATOR_POD_IP)
+
+	// Reconciled CHI keeps dependencies of the normalized one in status
+	reconciled := func(labels, annotations map[string]string) *api.ClickHouseInstallation {
+		chi := builder.NewCHI("test", "metadata", builder.WithCluster(builder.NewCluster("main")))
+		chi.Labels = labels
+		chi.Annotations = annotations
+		return chi
+	}
+	normalized, err := normalizer.NewNormalizer(render.NoSecrets).CreateTemplatedCHI(reconciled(map[string]string{"team": "a"}, nil), normalizer.NewOptions())
+	if err != nil {
+		t.Fatalf("unable to normalize err: %v", err)
+	}
+	status := &api.ChiStatus{}
+	for _, dependency := range normalized.EnsureStatus().GetDependencies() {
+		status.PushDependency(dependency)
+	}
+	status.Fill(&api.FillStatusParams{CHOpIP: ip})
+	status.ReconcileComplete()
+
+	tests := []struct {
+		name        string
+		labels      map[string]string
+		annotations map[string]string
+		observed    bool
+	}{
+		{name: "unchanged", labels: map[string]string{"team": "a"}, observed: true},
+		{name: "label changed", labels: map[string]string{"team": "b"}, observed: false},
+		{name: "annotation added", labels: map[string]string{"team": "a"}, annotations: map[string]string{"owner": "b"}, observed: false},
+		{name: "skipped annotation added", labels: map[string]string{"team": "a"}, annotations: map[string]string{model.AnnotationRestart: "1"}, observed: true},
+	}
+	for _, test := range tests {
+		chi := reconciled(test.labels, test.annotations)
+		chi.Status = status
+		if observed := w.isCHIObserved(ctx, chi); observed != test.observed {
+			t.Errorf("%s: got observed %v want %v", test.name, observed, test.observed)
+		}
+	}
+}
+
+func TestIsResyncCheckDue(t *testing.T) {
+	w := &worker{c: &Controller{}}
+	chi := builder.NewCHI("test", "resync")
+	check := resyncCheck{name: "diskUsage", interval: 60}
+	start := time.Now()
+
+	tests := []struct {
+		after time.Duration
+		due   bool
+	}{
+		{after: 0, due: true},
+		{after: 30 * time.Second, due: false},
+		{after: 60 * time.Second, due: true},
+		{after: 90 * time.Second, due: false},
+		{after: 121 * time.Second, due: true},
+	}
+	for _, test := range tests {
+		if due := w.isResyncCheckDue(chi, check, start.Add(test.after)); due != test.due {
+			t.Errorf("after %s: got due %v want %v", test.after, due, test.due)
+		}
+	}
+
+	// Checks are tracked independently
+	if !w.isResyncCheckDue(chi, resyncCheck{name: "configDrift", interval: 300}, start.Add(90*time.Second)) {
+		t.Errorf("check never run has to be due")
+	}
+	// Checks of deleted CHI are forgotten
+	w.forgetResyncChecks(chi)
+	if !w.isResyncCheckDue(chi, check, start.Add(122*time.Second)) {
+		t.Errorf("forgotten check has to be due")
+	}
+}
+
+func TestFindPortConflicts(t *testing.T) {
+	chi := builder.NewCHI("test", "own")
+	chi.EnsureStatus().SetPortAllocations([]api.ChiPortAllocation{
+		{Host: "chi-own-main-0-0", Slot: 0, Ports: []int32{10000, 11000}},
+		{Host: "chi-own-main-1-0", Slot: 1, Ports: []int32{10001, 11001}},
+	})
+	other := builder.NewCHI("test", "other")
+	other.EnsureStatus().SetPortAllocations([]api.ChiPortAllocation{
+		{Host: "chi-other-main-0-0", Slot: 1, Ports: []int32{10001, 12001}},
+	})
+	unrelated := builder.NewCHI("test", "unrelated")
+
+	conflicts := findPortConflicts(chi, []*api.ClickHouseInstallation{other, unrelated})
+	want := []string{"chi-own-main-1-0:10001 is allocated to test/chi-other-main-0-0"}
+	if !reflect.DeepEqual(conflicts, want) {
+		t.Errorf("got conflicts %v want %v", conflicts, want)
+	}
+	if conflicts := findPortConflicts(chi, []*api.ClickHouseInstallation{unrelated}); len(conflicts) != 0 {
+		t.Errorf("got unexpected conflicts %v", conflicts)
+	}
+}
+
+func TestGetReconcileZones(t *testing.T) {
+	render.Init("")
+	chi := builder.NewCHI("test", "zones", builder.WithCluster(builder.NewCluster("main",
+		builder.WithShards(2),
+		builder.WithReplicas(3),
+		builder.WithZones("a", "b", "c"),
+	)))
+	normalized, err := normalizer.NewNormalizer(render.NoSecrets).CreateTemplatedCHI(chi, normalizer.NewOptions())
+	if err != nil {
+		t.Fatalf("unable to normalize err: %v", err)
+	}
+
+	var shards []*api.ChiShard
+	normalized.FindCluster("main").WalkShards(func(_ int, shard *api.ChiShard) error {
+		shards = append(shards, shard)
+		return nil
+	})
+	if zones := getReconcileZones(shards); !reflect.DeepEqual(zones, []string{"a", "b", "c"}) {
+		t.Fatalf("got zones %v want [a b c]", zones)
+	}
+
+	for _, zone := range []string{"a", "b", "c"} {
+		count := 0
+		for _, shard := range shards {
+			for _, host := range getShardHostsOfZone(shard, zone) {
+				count++
+				if getHostZone(host) != zone {
+					t.Errorf("zone %s: got host %s of zone %s", zone, host.GetName(), getHostZone(host))
+				}
+			}
+		}
+		if count != 2 {
+			t.Errorf("zone %s: got %d hosts want 2", zone, count)
+		}
+	}
+}
+
+func TestIsRebalancingPauseElapsed(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	updated := now.Add(-30 * time.Second).Format(time.RFC3339)
+
+	tests := []struct {
+		updateTime string
+		pause      time.Duration
+		elapsed    bool
+	}{
+		{updateTime: updated, pause: 0, elapsed: true},
+		{updateTime: updated, pause: 30 * time.Second, elapsed: true},
+		{updateTime: updated, pause: time.Minute, elapsed: false},
+		{updateTime: "", pause: time.Minute, elapsed: true},
+	}
+	for _, test := range tests {
+		rebalancing := api.ChiRebalancingStatus{UpdateTime: test.updateTime}
+		if elapsed := isRebalancingPauseElapsed(rebalancing, test.pause, now); elapsed != test.elapsed {
+			t.Errorf("update time %q pause %v: got elapsed %v want %v", test.updateTime, test.pause, elapsed, test.elapsed)
+		}
+	}
+}
@@ -32,6 +32,7 @@ import (
 	log "github.com/altinity/clickhouse-operator/pkg/announcer"
 	apiChk "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse-keeper.altinity.com/v1"
 	apiChi "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/chop"
 	"github.com/altinity/clickhouse-operator/pkg/model/chi/normalizer"
 	//	apiChi "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
 	model "github.com/altinity/clickhouse-operator/pkg/model/chk"
@@ -120,6 +121,12 @@ func (r *ChkReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 		return ctrl.Result{}, err
 	}
 
+	if chop.Config().IsObserveMode() {
+		// Status carries normalized CHK, which is used as an ancestor and thus has to be written by an applying reconcile
+		log.V(1).M(new).F().Info("Observe mode, status is not updated. CHK: %s/%s", new.Namespace, new.Name)
+		return ctrl.Result{}, nil
+	}
+
 	if err := r.reconcileClusterStatus(new); err != nil {
 		log.V(1).Error("Error during reconcile status. f: %s err: %s", getFunctionName(r.reconcileClusterStatus), err)
 		return reconcile.Result{}, err
@@ -229,6 +236,19 @@ func (r *ChkReconciler) reconcile(
 		return err
 	}
 	err = r.Client.Get(context.TODO(), getNamespacedName(new), cur)
+	if chop.Config().IsObserveMode() {
+		// Nothing is applied in observe mode, planned action is reported only
+		switch {
+		case apiErrors.IsNotFound(err):
+			log.V(1).Info("Observe mode, would create new " + name)
+			return nil
+		case err != nil:
+			return err
+		case updater != nil:
+			log.V(1).Info("Observe mode, would update existing " + name)
+		}
+		return nil
+	}
 	if err != nil && apiErrors.IsNotFound(err) {
 		log.V(1).Info("Creating new " + name)
 
@@ -15,6 +15,10 @@
 package chi
 
 import (
+	"fmt"
+	"sort"
+	"strings"
+
 	"gopkg.in/d4l3k/messagediff.v1"
 
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -239,6 +243,73 @@ func (ap *ActionPlan) GetNewHostsNum() int {
 	return ap.new.HostsCount()
 }
 
+// GetAddedHostsNum - how many hosts would be added
+func (ap *ActionPlan) GetAddedHostsNum() int {
+	var count int
+	ap.WalkAdded(
+		func(cluster *api.Cluster) {
+			count += cluster.HostsCount()
+		},
+		func(shard *api.ChiShard) {
+			count += shard.HostsCount()
+		},
+		func(host *api.ChiHost) {
+			count++
+		},
+	)
+	return count
+}
+
+// Summary provides short one-line summary of the ActionPlan - which spec sections are changed
+// and how many hosts are added and removed
+func (ap *ActionPlan) Summary() string {
+	if !ap.HasActionsToDo() {
+		return ""
+	}
+
+	// Collect top-level spec sections touched by the diff
+	var sections []string
+	collect := func(paths map[*messagediff.Path]interface{}) {
+		for path := range paths {
+			if len(*path) == 0 {
+				continue
+			}
+			section := strings.TrimPrefix((*path)[0].String(), ".")
+			if !util.InArray(section, sections) {
+				sections = append(sections, section)
+			}
+		}
+	}
+	if ap.specDiff != nil {
+		collect(ap.specDiff.Added)
+		collect(ap.specDiff.Removed)
+		collect(ap.specDiff.Modified)
+	}
+	sort.Strings(sections)
+
+	var parts []string
+	if len(sections) > 0 {
+		parts = append(parts, "spec: "+strings.Join(sections, ","))
+	}
+	if !ap.labelsEqual {
+		parts = append(parts, "labels")
+	}
+	if !ap.deletionTimestampEqual {
+		parts = append(parts, "deletion timestamp")
+	}
+	if !ap.finalizersEqual {
+		parts = append(parts, "finalizers")
+	}
+	if added := ap.GetAddedHostsNum(); added > 0 {
+		parts = append(parts, fmt.Sprintf("hosts added: %d", added))
+	}
+	if removed := ap.GetRemovedHostsNum(); removed > 0 {
+		parts = append(parts, fmt.Sprintf("hosts removed: %d", removed))
+	}
+
+	return strings.Join(parts, "; ")
+}
+
 // GetRemovedHostsNum - how many hosts would be removed
 func (ap *ActionPlan) GetRemovedHostsNum() int {
 	var count int
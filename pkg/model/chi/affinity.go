@@ -15,6 +15,8 @@
 package chi
 
 import (
+	"sort"
+
 	"gopkg.in/d4l3k/messagediff.v1"
 
 	core "k8s.io/api/core/v1"
@@ -22,29 +24,33 @@ import (
 
 	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
 	"github.com/altinity/clickhouse-operator/pkg/apis/deployment"
+	"github.com/altinity/clickhouse-operator/pkg/chop"
 	"github.com/altinity/clickhouse-operator/pkg/util"
 )
 
 // NewAffinity creates new Affinity struct
 func NewAffinity(template *api.ChiPodTemplate) *core.Affinity {
-	// Pod node affinity scheduling rules.
-	nodeAffinity := newNodeAffinity(template)
-	// Pod affinity scheduling rules. Ex.: co-locate this pod in the same node, zone, etc
-	podAffinity := newPodAffinity(template)
-	// Pod anti-affinity scheduling rules. Ex.: avoid putting this pod in the same node, zone, etc
-	podAntiAffinity := newPodAntiAffinity(template)
+	affinity := &core.Affinity{
+		// Pod node affinity scheduling rules.
+		NodeAffinity: newNodeAffinity(template),
+	}
+
+	// Pod affinity and anti-affinity scheduling rules are provided by pod distribution scenarios.
+	// Ex.: co-locate this pod in the same node, zone, etc or avoid putting this pod in the same node, zone, etc
+	for i := range template.PodDistribution {
+		podDistribution := &template.PodDistribution[i]
+		if scenario, ok := GetPodDistributionScenario(podDistribution.Type); ok {
+			scenario.Apply(affinity, podDistribution)
+		}
+	}
 
 	// At least one affinity has to be reasonable
-	if (nodeAffinity == nil) && (podAffinity == nil) && (podAntiAffinity == nil) {
+	if (affinity.NodeAffinity == nil) && (affinity.PodAffinity == nil) && (affinity.PodAntiAffinity == nil) {
 		// Neither Affinity nor AntiAffinity specified
 		return nil
 	}
 
-	return &core.Affinity{
-		NodeAffinity:    nodeAffinity,
-		PodAffinity:     podAffinity,
-		PodAntiAffinity: podAntiAffinity,
-	}
+	return affinity
 }
 
 // MergeAffinity merges from src into dst and returns dst
@@ -76,7 +82,8 @@ func MergeAffinity(dst *core.Affinity, src *core.Affinity) *core.Affinity {
 
 // newNodeAffinity
 func newNodeAffinity(template *api.ChiPodTemplate) *core.NodeAffinity {
-	if template.Zone.Key == "" {
+	requirements := getZoneNodeSelectorRequirements(&template.Zone)
+	if len(requirements) == 0 {
 		return nil
 	}
 
@@ -85,13 +92,7 @@ func newNodeAffinity(template *api.ChiPodTemplate) *core.NodeAffinity {
 			NodeSelectorTerms: []core.NodeSelectorTerm{
 				{
 					// A list of node selector requirements by node's labels.
-					MatchExpressions: []core.NodeSelectorRequirement{
-						{
-							Key:      template.Zone.Key,
-							Operator: core.NodeSelectorOpIn,
-							Values:   template.Zone.Values,
-						},
-					},
+					MatchExpressions: requirements,
 					// A list of node selector requirements by node's fields.
 					//MatchFields: []core.NodeSelectorRequirement{
 					//	core.NodeSelectorRequirement{},
@@ -228,108 +229,6 @@ func mergeNodeAffinity(dst *core.NodeAffinity, src *core.NodeAffinity) *core.Nod
 	return dst
 }
 
-// newPodAffinity
-func newPodAffinity(template *api.ChiPodTemplate) *core.PodAffinity {
-	// Return podAffinity only in case something was added into it
-	added := false
-	podAffinity := &core.PodAffinity{}
-
-	for i := range template.PodDistribution {
-		podDistribution := &template.PodDistribution[i]
-		switch podDistribution.Type {
-		case deployment.PodDistributionNamespaceAffinity:
-			added = true
-			podAffinity.PreferredDuringSchedulingIgnoredDuringExecution = append(
-				podAffinity.PreferredDuringSchedulingIgnoredDuringExecution,
-				newWeightedPodAffinityTermWithMatchLabels(
-					1,
-					podDistribution,
-					map[string]string{
-						LabelNamespace: macrosNamespace,
-					},
-				),
-			)
-		case deployment.PodDistributionClickHouseInstallationAffinity:
-			added = true
-			podAffinity.PreferredDuringSchedulingIgnoredDuringExecution = append(
-				podAffinity.PreferredDuringSchedulingIgnoredDuringExecution,
-				newWeightedPodAffinityTermWithMatchLabels(
-					1,
-					podDistribution,
-					map[string]string{
-						LabelCHIName: macrosChiName,
-					},
-				),
-			)
-		case deployment.PodDistributionClusterAffinity:
-			added = true
-			podAffinity.PreferredDuringSchedulingIgnoredDuringExecution = append(
-				podAffinity.PreferredDuringSchedulingIgnoredDuringExecution,
-				newWeightedPodAffinityTermWithMatchLabels(
-					1,
-					podDistribution,
-					map[string]string{
-						LabelClusterName: macrosClusterName,
-					},
-				),
-			)
-		case deployment.PodDistributionShardAffinity:
-			added = true
-			podAffinity.PreferredDuringSchedulingIgnoredDuringExecution = append(
-				podAffinity.PreferredDuringSchedulingIgnoredDuringExecution,
-				newWeightedPodAffinityTermWithMatchLabels(
-					1,
-					podDistribution,
-					map[string]string{
-						LabelShardName: macrosShardName,
-					},
-				),
-			)
-		case deployment.PodDistributionReplicaAffinity:
-			added = true
-			podAffinity.PreferredDuringSchedulingIgnoredDuringExecution = append(
-				podAffinity.PreferredDuringSchedulingIgnoredDuringExecution,
-				newWeightedPodAffinityTermWithMatchLabels(
-					1,
-					podDistribution,
-					map[string]string{
-						LabelReplicaName: macrosReplicaName,
-					},
-				),
-			)
-		case deployment.PodDistributionPreviousTailAffinity:
-			// Newer k8s insists on Required for this Affinity
-			added = true
-			podAffinity.RequiredDuringSchedulingIgnoredDuringExecution = append(
-				podAffinity.RequiredDuringSchedulingIgnoredDuringExecution,
-				newPodAffinityTermWithMatchLabels(
-					podDistribution,
-					map[string]string{
-						LabelClusterScopeIndex: macrosClusterScopeCycleHeadPointsToPreviousCycleTail,
-					},
-				),
-			)
-			podAffinity.PreferredDuringSchedulingIgnoredDuringExecution = append(
-				podAffinity.PreferredDuringSchedulingIgnoredDuringExecution,
-				newWeightedPodAffinityTermWithMatchLabels(
-					1,
-					podDistribution,
-					map[string]string{
-						LabelClusterScopeIndex: macrosClusterScopeCycleHeadPointsToPreviousCycleTail,
-					},
-				),
-			)
-		}
-	}
-
-	if added {
-		// Has something to return
-		return podAffinity
-	}
-
-	return nil
-}
-
 func getPodAffinityTerms(affinity *core.PodAffinity) []core.PodAffinityTerm {
 	if affinity == nil {
 		return nil
@@ -492,134 +391,6 @@ func newMatchLabels(
 	return util.MergeStringMapsOverwrite(matchLabels, scopeLabels)
 }
 
-// newPodAntiAffinity
-func newPodAntiAffinity(template *api.ChiPodTemplate) *core.PodAntiAffinity {
-	// Return podAntiAffinity only in case something was added into it
-	added := false
-	podAntiAffinity := &core.PodAntiAffinity{}
-
-	// PodDistribution
-	for i := range template.PodDistribution {
-		podDistribution := &template.PodDistribution[i]
-		switch podDistribution.Type {
-		case deployment.PodDistributionClickHouseAntiAffinity:
-			added = true
-			podAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution = append(
-				podAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution,
-				newPodAffinityTermWithMatchLabels(
-					podDistribution,
-					newMatchLabels(
-						podDistribution,
-						map[string]string{
-							LabelAppName: LabelAppValue,
-						},
-					),
-				),
-			)
-		case deployment.PodDistributionMaxNumberPerNode:
-			added = true
-			podAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution = append(
-				podAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution,
-				newPodAffinityTermWithMatchLabels(
-					podDistribution,
-					newMatchLabels(
-						podDistribution,
-						map[string]string{
-							LabelClusterScopeCycleIndex: macrosClusterScopeCycleIndex,
-						},
-					),
-				),
-			)
-		case deployment.PodDistributionShardAntiAffinity:
-			added = true
-			podAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution = append(
-				podAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution,
-				newPodAffinityTermWithMatchLabels(
-					podDistribution,
-					newMatchLabels(
-						podDistribution,
-						map[string]string{
-							LabelShardName: macrosShardName,
-						},
-					),
-				),
-			)
-		case deployment.PodDistributionReplicaAntiAffinity:
-			added = true
-			podAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution = append(
-				podAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution,
-				newPodAffinityTermWithMatchLabels(
-					podDistribution,
-					newMatchLabels(
-						podDistribution,
-						map[string]string{
-							LabelReplicaName: macrosReplicaName,
-						},
-					),
-				),
-			)
-		case deployment.PodDistributionAnotherNamespaceAntiAffinity:
-			added = true
-			podAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution = append(
-				podAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution,
-				newPodAffinityTermWithMatchExpressions(
-					podDistribution,
-					[]meta.LabelSelectorRequirement{
-						{
-							Key:      LabelNamespace,
-							Operator: meta.LabelSelectorOpNotIn,
-							Values: []string{
-								macrosNamespace,
-							},
-						},
-					},
-				),
-			)
-		case deployment.PodDistributionAnotherClickHouseInstallationAntiAffinity:
-			added = true
-			podAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution = append(
-				podAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution,
-				newPodAffinityTermWithMatchExpressions(
-					podDistribution,
-					[]meta.LabelSelectorRequirement{
-						{
-							Key:      LabelCHIName,
-							Operator: meta.LabelSelectorOpNotIn,
-							Values: []string{
-								macrosChiName,
-							},
-						},
-					},
-				),
-			)
-		case deployment.PodDistributionAnotherClusterAntiAffinity:
-			added = true
-			podAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution = append(
-				podAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution,
-				newPodAffinityTermWithMatchExpressions(
-					podDistribution,
-					[]meta.LabelSelectorRequirement{
-						{
-							Key:      LabelClusterName,
-							Operator: meta.LabelSelectorOpNotIn,
-							Values: []string{
-								macrosClusterName,
-							},
-						},
-					},
-				),
-			)
-		}
-	}
-
-	if added {
-		// Has something to return
-		return podAntiAffinity
-	}
-
-	return nil
-}
-
 func getPodAntiAffinityTerms(affinity *core.PodAntiAffinity) []core.PodAffinityTerm {
 	if affinity == nil {
 		return nil
@@ -849,6 +620,198 @@ func PrepareAffinity(podTemplate *api.ChiPodTemplate, host *api.ChiHost) {
 	}
 }
 
+// ApplyHostZone restricts pod template to the zone the host is assigned to and makes it tolerate taints of the zone.
+// Zone requirement is added to each node selector term, since terms are ORed and requirements within a term are ANDed.
+func ApplyHostZone(podTemplate *api.ChiPodTemplate, host *api.ChiHost) {
+	if (podTemplate == nil) || (host.Zone == nil) {
+		return
+	}
+
+	AppendTolerations(podTemplate, host.Zone.Tolerations...)
+	appendNodeSelectorRequirements(podTemplate, getZoneNodeSelectorRequirements(host.Zone)...)
+}
+
+// getZoneNodeSelectorRequirements gets node selector requirements of the zone - zone key itself followed by match expressions.
+// All requirements are ANDed, so zone values select alternatives, while match expressions narrow them down
+func getZoneNodeSelectorRequirements(zone *api.ChiPodTemplateZone) []core.NodeSelectorRequirement {
+	var requirements []core.NodeSelectorRequirement
+	if zone.Key != "" {
+		requirements = append(requirements, core.NodeSelectorRequirement{
+			Key:      zone.Key,
+			Operator: core.NodeSelectorOpIn,
+			Values:   zone.Values,
+		})
+	}
+	for i := range zone.MatchExpressions {
+		requirements = append(requirements, *zone.MatchExpressions[i].DeepCopy())
+	}
+	return requirements
+}
+
+// ApplyNamespaceZone restricts pod template to nodes approved for the namespace in operator's configuration.
+// Node labels already required by the pod template, e.g. by its zone, are considered to be overridden and are skipped
+func ApplyNamespaceZone(podTemplate *api.ChiPodTemplate, host *api.ChiHost) {
+	if podTemplate == nil {
+		return
+	}
+
+	AppendTolerations(podTemplate, chop.Config().GetPodZoneTolerations(host.GetCHI().Namespace)...)
+
+	labels := chop.Config().GetPodZoneLabels(host.GetCHI().Namespace)
+	// Sort keys, so pod template is stable across reconciles
+	var keys []string
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var requirements []core.NodeSelectorRequirement
+	for _, key := range keys {
+		if isNodeLabelRequired(podTemplate, key) {
+			continue
+		}
+		requirements = append(requirements, core.NodeSelectorRequirement{
+			Key:      key,
+			Operator: core.NodeSelectorOpIn,
+			Values:   []string{labels[key]},
+		})
+	}
+	appendNodeSelectorRequirements(podTemplate, requirements...)
+}
+
+// ApplyArchitecture restricts pod template to nodes of the architecture the pod template is built for
+func ApplyArchitecture(podTemplate *api.ChiPodTemplate) {
+	if (podTemplate == nil) || (podTemplate.Architecture == "") {
+		return
+	}
+	if isNodeLabelRequired(podTemplate, core.LabelArchStable) {
+		// Architecture is already selected explicitly
+		return
+	}
+
+	appendNodeSelectorRequirements(podTemplate, core.NodeSelectorRequirement{
+		Key:      core.LabelArchStable,
+		Operator: core.NodeSelectorOpIn,
+		Values:   []string{podTemplate.Architecture},
+	})
+}
+
+// appendNodeSelectorRequirements appends requirements to each required node selector term of the pod template
+func appendNodeSelectorRequirements(podTemplate *api.ChiPodTemplate, requirements ...core.NodeSelectorRequirement) {
+	if len(requirements) == 0 {
+		return
+	}
+
+	if podTemplate.Spec.Affinity == nil {
+		podTemplate.Spec.Affinity = &core.Affinity{}
+	}
+	if podTemplate.Spec.Affinity.NodeAffinity == nil {
+		podTemplate.Spec.Affinity.NodeAffinity = &core.NodeAffinity{}
+	}
+	nodeAffinity := podTemplate.Spec.Affinity.NodeAffinity
+	if nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution = &core.NodeSelector{}
+	}
+	nodeSelector := nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+	if len(nodeSelector.NodeSelectorTerms) == 0 {
+		nodeSelector.NodeSelectorTerms = append(nodeSelector.NodeSelectorTerms, core.NodeSelectorTerm{})
+	}
+	// Terms are ORed, so each of them has to carry requirements
+	for i := range nodeSelector.NodeSelectorTerms {
+		term := &nodeSelector.NodeSelectorTerms[i]
+		term.MatchExpressions = append(term.MatchExpressions, requirements...)
+	}
+}
+
+// AppendTolerations appends tolerations to the pod template, unless the pod template tolerates the same taints already
+func AppendTolerations(podTemplate *api.ChiPodTemplate, tolerations ...core.Toleration) {
+	for i := range tolerations {
+		toleration := &tolerations[i]
+		tolerated := false
+		for j := range podTemplate.Spec.Tolerations {
+			if podTemplate.Spec.Tolerations[j].MatchToleration(toleration) {
+				tolerated = true
+				break
+			}
+		}
+		if !tolerated {
+			podTemplate.Spec.Tolerations = append(podTemplate.Spec.Tolerations, *toleration.DeepCopy())
+		}
+	}
+}
+
+// isNodeLabelRequired checks whether pod template requires node label by node selector or by node affinity
+func isNodeLabelRequired(podTemplate *api.ChiPodTemplate, key string) bool {
+	if _, ok := podTemplate.Spec.NodeSelector[key]; ok {
+		return true
+	}
+	if podTemplate.Spec.Affinity == nil {
+		return false
+	}
+	for _, term := range getNodeSelectorTerms(podTemplate.Spec.Affinity.NodeAffinity) {
+		for _, requirement := range term.MatchExpressions {
+			if requirement.Key == key {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ApplyCHIAntiAffinity spreads pods of the host away from pods of other CHIs
+// sharing a label listed in operator's configuration
+func ApplyCHIAntiAffinity(podTemplate *api.ChiPodTemplate, host *api.ChiHost) {
+	if podTemplate == nil {
+		return
+	}
+
+	config := chop.Config().Pod.AntiAffinity
+	chi := host.GetCHI()
+	// Only labels propagated to pods are able to be matched
+	labels := util.CopyMapFilter(chi.Labels, chop.Config().Label.Include, chop.Config().Label.Exclude)
+	for _, label := range config.Labels {
+		value, ok := labels[label]
+		if !ok {
+			continue
+		}
+
+		term := core.PodAffinityTerm{
+			LabelSelector: &meta.LabelSelector{
+				MatchExpressions: []meta.LabelSelectorRequirement{
+					{
+						Key:      label,
+						Operator: meta.LabelSelectorOpIn,
+						Values:   []string{value},
+					},
+					{
+						Key:      LabelCHIName,
+						Operator: meta.LabelSelectorOpNotIn,
+						Values:   []string{labelsNamer.getNamePartCHIName(chi)},
+					},
+				},
+			},
+			// CHIs of all namespaces are spread
+			NamespaceSelector: &meta.LabelSelector{},
+			TopologyKey:       config.TopologyKey,
+		}
+
+		if podTemplate.Spec.Affinity == nil {
+			podTemplate.Spec.Affinity = &core.Affinity{}
+		}
+		if config.Required.Value() {
+			podTemplate.Spec.Affinity.PodAntiAffinity = appendPodAntiAffinityTerm(podTemplate.Spec.Affinity.PodAntiAffinity, &term)
+		} else {
+			podTemplate.Spec.Affinity.PodAntiAffinity = appendWeightedPodAntiAffinityTerm(
+				podTemplate.Spec.Affinity.PodAntiAffinity,
+				&core.WeightedPodAffinityTerm{
+					Weight:          1,
+					PodAffinityTerm: term,
+				},
+			)
+		}
+	}
+}
+
 // processNodeSelector
 func processNodeSelector(nodeSelector *core.NodeSelector, host *api.ChiHost) {
 	if nodeSelector == nil {
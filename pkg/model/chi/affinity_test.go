@@ -0,0 +1,200 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi_test
+
+import (
+	"strings"
+	"testing"
+
+	core "k8s.io/api/core/v1"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/chop"
+	model "github.com/altinity/clickhouse-operator/pkg/model/chi"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/builder"
+)
+
+// getNodeSelectorRequirements gets requirements of the only required node selector term of the pod template
+func getNodeSelectorRequirements(t *testing.T, podTemplate *api.ChiPodTemplate) []core.NodeSelectorRequirement {
+	t.Helper()
+	affinity := podTemplate.Spec.Affinity
+	if (affinity == nil) || (affinity.NodeAffinity == nil) || (affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil) {
+		t.Fatalf("pod template %s has no required node affinity", podTemplate.Name)
+	}
+	terms := affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+	if len(terms) != 1 {
+		t.Fatalf("pod template %s: unexpected node selector terms %v", podTemplate.Name, terms)
+	}
+	return terms[0].MatchExpressions
+}
+
+func TestApplyHostZone(t *testing.T) {
+	cluster := builder.NewCluster("spread",
+		builder.WithShards(2),
+		builder.WithReplicas(2),
+		builder.WithZones("us-east-1a", "us-east-1b", "us-east-1c"),
+	)
+	chi := normalize(t, builder.NewCHI("test", "zones", builder.WithCluster(cluster)))
+
+	want := map[string]string{
+		"0-0": "us-east-1a",
+		"0-1": "us-east-1b",
+		"1-0": "us-east-1b",
+		"1-1": "us-east-1c",
+	}
+	chi.WalkHosts(func(host *api.ChiHost) error {
+		podTemplate := &api.ChiPodTemplate{Name: host.GetName()}
+		model.ApplyHostZone(podTemplate, host)
+		requirements := getNodeSelectorRequirements(t, podTemplate)
+		if len(requirements) != 1 {
+			t.Fatalf("host %s: unexpected node selector requirements %v", host.GetName(), requirements)
+		}
+		requirement := requirements[0]
+		if (requirement.Key != core.LabelTopologyZone) || (len(requirement.Values) != 1) || (requirement.Values[0] != want[host.GetName()]) {
+			t.Errorf("host %s: got zone %v want %s", host.GetName(), requirement, want[host.GetName()])
+		}
+		return nil
+	})
+}
+
+func TestApplyCHIAntiAffinity(t *testing.T) {
+	config := chop.Config()
+	saved := config.Pod.AntiAffinity
+	config.Pod.AntiAffinity.Labels = []string{"tenant"}
+	defer func() { config.Pod.AntiAffinity = saved }()
+
+	input := builder.NewCHI("test", "tenant", builder.WithCluster(builder.NewCluster("main")))
+	input.Labels = map[string]string{"tenant": "acme"}
+	chi := normalize(t, input)
+	podTemplate := &api.ChiPodTemplate{}
+	model.ApplyCHIAntiAffinity(podTemplate, chi.FirstHost())
+	affinity := podTemplate.Spec.Affinity
+	if (affinity == nil) || (affinity.PodAntiAffinity == nil) ||
+		(len(affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution) != 1) {
+		t.Fatalf("anti-affinity is not applied: %v", affinity)
+	}
+	term := affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution[0].PodAffinityTerm
+	if term.TopologyKey != core.LabelHostname || term.NamespaceSelector == nil || len(term.LabelSelector.MatchExpressions) != 2 {
+		t.Errorf("unexpected anti-affinity term: %v", term)
+	}
+	if expr := term.LabelSelector.MatchExpressions[1]; expr.Key != model.LabelCHIName || expr.Values[0] != "tenant" {
+		t.Errorf("own pods are not excluded: %v", expr)
+	}
+
+	chi = normalize(t, builder.NewCHI("test", "plain", builder.WithCluster(builder.NewCluster("main"))))
+	podTemplate = &api.ChiPodTemplate{}
+	model.ApplyCHIAntiAffinity(podTemplate, chi.FirstHost())
+	if affinity := podTemplate.Spec.Affinity; (affinity != nil) && (affinity.PodAntiAffinity != nil) {
+		t.Errorf("unexpected anti-affinity of CHI without label: %v", affinity.PodAntiAffinity)
+	}
+}
+
+func TestApplyNamespaceZone(t *testing.T) {
+	config := chop.Config()
+	saved := config.Pod.Zones
+	config.Pod.Zones = []api.OperatorConfigPodZone{
+		{
+			Namespaces:  []string{"prod-.*"},
+			MatchLabels: map[string]string{"node-pool": "clickhouse", "dedicated": "yes"},
+		},
+	}
+	defer func() { config.Pod.Zones = saved }()
+
+	chi := normalize(t, builder.NewCHI("prod-eu", "zoned", builder.WithCluster(builder.NewCluster("main"))))
+	podTemplate := &api.ChiPodTemplate{Name: "pinned"}
+	podTemplate.Spec.NodeSelector = map[string]string{"dedicated": "no"}
+	model.ApplyNamespaceZone(podTemplate, chi.FirstHost())
+	requirements := getNodeSelectorRequirements(t, podTemplate)
+	// Node label required by the pod template overrides the zone
+	if (len(requirements) != 1) || (requirements[0].Key != "node-pool") || (requirements[0].Values[0] != "clickhouse") {
+		t.Errorf("unexpected node selector requirements: %v", requirements)
+	}
+
+	chi = normalize(t, builder.NewCHI("dev", "plain", builder.WithCluster(builder.NewCluster("main"))))
+	podTemplate = &api.ChiPodTemplate{Name: "plain"}
+	model.ApplyNamespaceZone(podTemplate, chi.FirstHost())
+	if affinity := podTemplate.Spec.Affinity; (affinity != nil) && (affinity.NodeAffinity != nil) {
+		t.Errorf("unexpected node affinity of CHI of other namespace: %v", affinity.NodeAffinity)
+	}
+}
+
+func TestApplyArchitecture(t *testing.T) {
+	podTemplate := &api.ChiPodTemplate{Name: "arm", Architecture: "arm64"}
+	model.ApplyArchitecture(podTemplate)
+	requirements := getNodeSelectorRequirements(t, podTemplate)
+	if (len(requirements) != 1) || (requirements[0].Key != core.LabelArchStable) || (requirements[0].Values[0] != "arm64") {
+		t.Errorf("unexpected node selector requirements: %v", requirements)
+	}
+
+	// Architecture selected explicitly is kept
+	podTemplate = &api.ChiPodTemplate{Name: "pinned", Architecture: "arm64"}
+	podTemplate.Spec.NodeSelector = map[string]string{core.LabelArchStable: "amd64"}
+	model.ApplyArchitecture(podTemplate)
+	if affinity := podTemplate.Spec.Affinity; (affinity != nil) && (affinity.NodeAffinity != nil) {
+		t.Errorf("unexpected node affinity of pod template with architecture selected: %v", affinity.NodeAffinity)
+	}
+}
+
+func TestApplyZoneTolerations(t *testing.T) {
+	dedicated := core.Toleration{Key: "dedicated", Operator: core.TolerationOpEqual, Value: "clickhouse", Effect: core.TaintEffectNoSchedule}
+	config := chop.Config()
+	saved := config.Pod.Zones
+	config.Pod.Zones = []api.OperatorConfigPodZone{
+		{
+			Namespaces:  []string{"prod"},
+			Tolerations: []core.Toleration{dedicated},
+		},
+	}
+	defer func() { config.Pod.Zones = saved }()
+
+	spot := core.Toleration{Key: "spot", Operator: core.TolerationOpExists, Effect: core.TaintEffectNoSchedule}
+	cluster := builder.NewCluster("main", builder.WithReplicas(2), builder.WithZones("a", "b"))
+	cluster.Zones.Tolerations = []core.Toleration{dedicated, spot}
+	chi := normalize(t, builder.NewCHI("prod", "tainted", builder.WithCluster(cluster)))
+	chi.WalkHosts(func(host *api.ChiHost) error {
+		podTemplate := &api.ChiPodTemplate{Name: host.GetName()}
+		model.ApplyHostZone(podTemplate, host)
+		model.ApplyNamespaceZone(podTemplate, host)
+		tolerations := podTemplate.Spec.Tolerations
+		// Tolerations of the same taint are not duplicated
+		if len(tolerations) != 2 || !tolerations[0].MatchToleration(&dedicated) || !tolerations[1].MatchToleration(&spot) {
+			t.Errorf("host %s: unexpected tolerations: %v", host.GetName(), tolerations)
+		}
+		return nil
+	})
+}
+
+func TestApplyHostZoneMatchExpressions(t *testing.T) {
+	ssd := core.NodeSelectorRequirement{Key: "ssd", Operator: core.NodeSelectorOpIn, Values: []string{"yes", "true"}}
+	spot := core.NodeSelectorRequirement{Key: "spot", Operator: core.NodeSelectorOpDoesNotExist, Values: []string{"ignored"}}
+	malformed := core.NodeSelectorRequirement{Key: "disk", Operator: core.NodeSelectorOpIn}
+	cluster := builder.NewCluster("main", builder.WithReplicas(2), builder.WithZones("a", "b"))
+	cluster.Zones.MatchExpressions = []core.NodeSelectorRequirement{spot, malformed, ssd}
+	chi := normalize(t, builder.NewCHI("test", "ssd", builder.WithCluster(cluster)))
+
+	chi.WalkHosts(func(host *api.ChiHost) error {
+		podTemplate := &api.ChiPodTemplate{Name: host.GetName()}
+		model.ApplyHostZone(podTemplate, host)
+		// Zone key goes first, match expressions are sorted and malformed ones are skipped
+		requirements := getNodeSelectorRequirements(t, podTemplate)
+		if len(requirements) != 3 ||
+			requirements[0].Key != core.LabelTopologyZone ||
+			requirements[1].Key != "spot" || len(requirements[1].Values) != 0 ||
+			requirements[2].Key != "ssd" || strings.Join(requirements[2].Values, ",") != "true,yes" {
+			t.Errorf("host %s: unexpected node selector requirements: %v", host.GetName(), requirements)
+		}
+		return nil
+	})
+}
@@ -17,11 +17,22 @@ package chi
 import (
 	core "k8s.io/api/core/v1"
 
+	"github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com"
 	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
 	"github.com/altinity/clickhouse-operator/pkg/chop"
 	"github.com/altinity/clickhouse-operator/pkg/util"
 )
 
+// Set of kubernetes annotations used by the operator
+const (
+	// AnnotationApprovedPlan specifies hash of the reconcile plan approved by the user
+	AnnotationApprovedPlan = clickhouse_altinity_com.APIGroupName + "/" + "approved-plan"
+	// AnnotationRestart specifies arbitrary value, change of which requests rolling restart of all hosts
+	AnnotationRestart = clickhouse_altinity_com.APIGroupName + "/" + "restart"
+	// AnnotationBackupContainer specifies name of the backup sidecar container of the pod template
+	AnnotationBackupContainer = clickhouse_altinity_com.APIGroupName + "/" + "backup-container"
+)
+
 // Annotator is an entity which can annotate CHI artifacts
 type Annotator struct {
 	chi *api.ClickHouseInstallation
@@ -42,6 +53,22 @@ func (a *Annotator) GetConfigMapCHICommon() map[string]string {
 	)
 }
 
+// GetConfigMapCHILogging
+func (a *Annotator) GetConfigMapCHILogging() map[string]string {
+	return util.MergeStringMapsOverwrite(
+		a.getCHIScope(),
+		nil,
+	)
+}
+
+// GetConfigMapCHITopology
+func (a *Annotator) GetConfigMapCHITopology() map[string]string {
+	return util.MergeStringMapsOverwrite(
+		a.getCHIScope(),
+		nil,
+	)
+}
+
 // GetConfigMapCHICommonUsers
 func (a *Annotator) GetConfigMapCHICommonUsers() map[string]string {
 	return util.MergeStringMapsOverwrite(
@@ -66,6 +93,16 @@ func (a *Annotator) GetServiceCHI(chi *api.ClickHouseInstallation) map[string]st
 	)
 }
 
+// GetChproxy
+func (a *Annotator) GetChproxy() map[string]string {
+	return a.getCHIScope()
+}
+
+// GetSecretConnection gets annotations of the Secret with connection details of the CHI
+func (a *Annotator) GetSecretConnection() map[string]string {
+	return a.getCHIScope()
+}
+
 // GetServiceCluster
 func (a *Annotator) GetServiceCluster(cluster *api.Cluster) map[string]string {
 	return util.MergeStringMapsOverwrite(
@@ -92,25 +129,27 @@ func (a *Annotator) GetServiceHost(host *api.ChiHost) map[string]string {
 
 // getCHIScope gets annotations for CHI-scoped object
 func (a *Annotator) getCHIScope() map[string]string {
-	// Combine generated annotations and CHI-provided annotations
-	return a.filterOutPredefined(a.appendCHIProvidedTo(nil))
+	// Combine generated, CHI-provided and custom annotations
+	return a.filterOutPredefined(appendCustomTo(a.appendCHIProvidedTo(nil), a.chi.Spec.Defaults.GetAnnotations()))
 }
 
 // GetClusterScope gets annotations for Cluster-scoped object
 func (a *Annotator) GetClusterScope(cluster *api.Cluster) map[string]string {
-	// Combine generated annotations and CHI-provided annotations
-	return a.filterOutPredefined(a.appendCHIProvidedTo(nil))
+	// Combine generated, CHI-provided and custom annotations
+	return a.filterOutPredefined(appendCustomTo(a.appendCHIProvidedTo(nil), cluster.GetAnnotations()))
 }
 
 // getShardScope gets annotations for Shard-scoped object
 func (a *Annotator) getShardScope(shard *api.ChiShard) map[string]string {
-	// Combine generated annotations and CHI-provided annotations
-	return a.filterOutPredefined(a.appendCHIProvidedTo(nil))
+	// Combine generated, CHI-provided and custom annotations
+	cluster := a.chi.FindCluster(shard.Runtime.Address.ClusterName)
+	return a.filterOutPredefined(appendCustomTo(a.appendCHIProvidedTo(nil), cluster.GetAnnotations()))
 }
 
 // GetHostScope gets annotations for Host-scoped object
 func (a *Annotator) GetHostScope(host *api.ChiHost) map[string]string {
-	return a.filterOutPredefined(a.appendCHIProvidedTo(nil))
+	cluster := a.chi.FindCluster(host.Runtime.Address.ClusterName)
+	return a.filterOutPredefined(appendCustomTo(a.appendCHIProvidedTo(nil), cluster.GetAnnotations()))
 }
 
 // filterOutPredefined filters out predefined values
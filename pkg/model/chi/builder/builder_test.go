@@ -0,0 +1,92 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package builder_test
+
+import (
+	"os"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/builder"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/render"
+)
+
+func TestMain(m *testing.M) {
+	render.Init("../../../../config/config.yaml")
+	os.Exit(m.Run())
+}
+
+func TestBuilder(t *testing.T) {
+	chi := builder.NewCHI("test", "builder",
+		builder.WithPodTemplates(builder.NewPodTemplate("clickhouse", "clickhouse/clickhouse-server:23.8")),
+		builder.WithVolumeClaimTemplates(builder.NewVolumeClaimTemplate("data", resource.MustParse("10Gi"))),
+		builder.WithCluster(builder.NewCluster("main",
+			builder.WithShards(2),
+			builder.WithReplicas(2),
+			builder.WithPodTemplate("clickhouse"),
+			builder.WithDataVolumeClaimTemplate("data"),
+		)),
+	)
+	// Cluster with the same name replaces existing one
+	builder.ApplyCHI(chi, builder.WithCluster(builder.NewCluster("main", builder.WithShards(3), builder.WithPodTemplate("clickhouse"))))
+
+	if got := len(chi.Spec.Configuration.Clusters); got != 1 {
+		t.Fatalf("clusters: got %d want %d", got, 1)
+	}
+
+	m, err := render.Render(chi, nil)
+	if err != nil {
+		t.Fatalf("unable to render err: %v", err)
+	}
+	if got := len(m.StatefulSets); got != 3 {
+		t.Fatalf("stateful sets: got %d want %d", got, 3)
+	}
+	for _, sts := range m.StatefulSets {
+		if image := sts.Spec.Template.Spec.Containers[0].Image; image != "clickhouse/clickhouse-server:23.8" {
+			t.Errorf("stateful set %s image: got %s", sts.Name, image)
+		}
+	}
+}
+
+func TestShardTemplates(t *testing.T) {
+	chi := builder.NewCHI("test", "hot",
+		builder.WithPodTemplates(
+			builder.NewPodTemplate("regular", "clickhouse/clickhouse-server:23.8"),
+			builder.NewPodTemplate("big", "clickhouse/clickhouse-server:24.3"),
+		),
+		builder.WithCluster(builder.NewCluster("main",
+			builder.WithShards(3),
+			builder.WithPodTemplate("regular"),
+			builder.WithShardTemplates(&api.ChiTemplateNames{PodTemplate: "big"}, 1),
+		)),
+	)
+
+	m, err := render.Render(chi, nil)
+	if err != nil {
+		t.Fatalf("unable to render err: %v", err)
+	}
+	want := map[string]string{
+		"chi-hot-main-0-0": "clickhouse/clickhouse-server:23.8",
+		"chi-hot-main-1-0": "clickhouse/clickhouse-server:24.3",
+		"chi-hot-main-2-0": "clickhouse/clickhouse-server:23.8",
+	}
+	for _, sts := range m.StatefulSets {
+		if got := sts.Spec.Template.Spec.Containers[0].Image; got != want[sts.Name] {
+			t.Errorf("stateful set %s: got image %s want %s", sts.Name, got, want[sts.Name])
+		}
+	}
+}
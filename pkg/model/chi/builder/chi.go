@@ -0,0 +1,208 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package builder provides helpers to create and manipulate CHI resources programmatically.
+// Objects built are ready to be submitted with the typed clientset from pkg/client.
+package builder
+
+import (
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+)
+
+// CHIOption modifies CHI
+type CHIOption func(chi *api.ClickHouseInstallation)
+
+// NewCHI creates new CHI with specified options applied
+func NewCHI(namespace, name string, options ...CHIOption) *api.ClickHouseInstallation {
+	chi := &api.ClickHouseInstallation{
+		TypeMeta: meta.TypeMeta{
+			Kind:       api.ClickHouseInstallationCRDResourceKind,
+			APIVersion: api.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: meta.ObjectMeta{
+			Namespace: namespace,
+			Name:      name,
+		},
+	}
+	return ApplyCHI(chi, options...)
+}
+
+// ApplyCHI applies options to existing CHI
+func ApplyCHI(chi *api.ClickHouseInstallation, options ...CHIOption) *api.ClickHouseInstallation {
+	for _, option := range options {
+		option(chi)
+	}
+	return chi
+}
+
+// WithCluster adds cluster to the CHI. Cluster with the same name is replaced
+func WithCluster(cluster *api.Cluster) CHIOption {
+	return func(chi *api.ClickHouseInstallation) {
+		if chi.Spec.Configuration == nil {
+			chi.Spec.Configuration = api.NewConfiguration()
+		}
+		for i := range chi.Spec.Configuration.Clusters {
+			if chi.Spec.Configuration.Clusters[i].Name == cluster.Name {
+				chi.Spec.Configuration.Clusters[i] = cluster
+				return
+			}
+		}
+		chi.Spec.Configuration.Clusters = append(chi.Spec.Configuration.Clusters, cluster)
+	}
+}
+
+// WithLogicalClusters adds logical clusters to the CHI. Logical cluster with the same name is replaced
+func WithLogicalClusters(logicalClusters ...api.ChiLogicalCluster) CHIOption {
+	return func(chi *api.ClickHouseInstallation) {
+		if chi.Spec.Configuration == nil {
+			chi.Spec.Configuration = api.NewConfiguration()
+		}
+		for _, logicalCluster := range logicalClusters {
+			if existing := chi.Spec.Configuration.FindLogicalCluster(logicalCluster.Name); existing != nil {
+				*existing = logicalCluster
+			} else {
+				chi.Spec.Configuration.LogicalClusters = append(chi.Spec.Configuration.LogicalClusters, logicalCluster)
+			}
+		}
+	}
+}
+
+// WithFiles adds files to the CHI configuration. File with the same name is replaced
+func WithFiles(files map[string]string) CHIOption {
+	return func(chi *api.ClickHouseInstallation) {
+		if chi.Spec.Configuration == nil {
+			chi.Spec.Configuration = api.NewConfiguration()
+		}
+		if chi.Spec.Configuration.Files == nil {
+			chi.Spec.Configuration.Files = api.NewSettings()
+		}
+		chi.Spec.Configuration.Files.SetScalarsFromMap(files)
+	}
+}
+
+// WithPodTemplates adds pod templates to the CHI. Template with the same name is replaced
+func WithPodTemplates(templates ...api.ChiPodTemplate) CHIOption {
+	return func(chi *api.ClickHouseInstallation) {
+		ensureTemplates(chi)
+		for _, template := range templates {
+			replaced := false
+			for i := range chi.Spec.Templates.PodTemplates {
+				if chi.Spec.Templates.PodTemplates[i].Name == template.Name {
+					chi.Spec.Templates.PodTemplates[i] = template
+					replaced = true
+				}
+			}
+			if !replaced {
+				chi.Spec.Templates.PodTemplates = append(chi.Spec.Templates.PodTemplates, template)
+			}
+		}
+	}
+}
+
+// WithVolumeClaimTemplates adds volume claim templates to the CHI. Template with the same name is replaced
+func WithVolumeClaimTemplates(templates ...api.ChiVolumeClaimTemplate) CHIOption {
+	return func(chi *api.ClickHouseInstallation) {
+		ensureTemplates(chi)
+		for _, template := range templates {
+			replaced := false
+			for i := range chi.Spec.Templates.VolumeClaimTemplates {
+				if chi.Spec.Templates.VolumeClaimTemplates[i].Name == template.Name {
+					chi.Spec.Templates.VolumeClaimTemplates[i] = template
+					replaced = true
+				}
+			}
+			if !replaced {
+				chi.Spec.Templates.VolumeClaimTemplates = append(chi.Spec.Templates.VolumeClaimTemplates, template)
+			}
+		}
+	}
+}
+
+// WithHostTemplates adds host templates to the CHI. Template with the same name is replaced
+func WithHostTemplates(templates ...api.ChiHostTemplate) CHIOption {
+	return func(chi *api.ClickHouseInstallation) {
+		ensureTemplates(chi)
+		for _, template := range templates {
+			replaced := false
+			for i := range chi.Spec.Templates.HostTemplates {
+				if chi.Spec.Templates.HostTemplates[i].Name == template.Name {
+					chi.Spec.Templates.HostTemplates[i] = template
+					replaced = true
+				}
+			}
+			if !replaced {
+				chi.Spec.Templates.HostTemplates = append(chi.Spec.Templates.HostTemplates, template)
+			}
+		}
+	}
+}
+
+// WithServiceTemplates adds service templates to the CHI. Template with the same name is replaced
+func WithServiceTemplates(templates ...api.ChiServiceTemplate) CHIOption {
+	return func(chi *api.ClickHouseInstallation) {
+		ensureTemplates(chi)
+		for _, template := range templates {
+			replaced := false
+			for i := range chi.Spec.Templates.ServiceTemplates {
+				if chi.Spec.Templates.ServiceTemplates[i].Name == template.Name {
+					chi.Spec.Templates.ServiceTemplates[i] = template
+					replaced = true
+				}
+			}
+			if !replaced {
+				chi.Spec.Templates.ServiceTemplates = append(chi.Spec.Templates.ServiceTemplates, template)
+			}
+		}
+	}
+}
+
+// WithDefaultPodTemplate specifies pod template used by all hosts of the CHI by default
+func WithDefaultPodTemplate(name string) CHIOption {
+	return func(chi *api.ClickHouseInstallation) {
+		if chi.Spec.Defaults == nil {
+			chi.Spec.Defaults = api.NewChiDefaults()
+		}
+		if chi.Spec.Defaults.Templates == nil {
+			chi.Spec.Defaults.Templates = api.NewChiTemplateNames()
+		}
+		chi.Spec.Defaults.Templates.PodTemplate = name
+	}
+}
+
+// WithUseTemplates specifies CHI templates (CHITs) to be applied to the CHI
+func WithUseTemplates(names ...string) CHIOption {
+	return func(chi *api.ClickHouseInstallation) {
+		for _, name := range names {
+			chi.Spec.UseTemplates = append(chi.Spec.UseTemplates, &api.ChiTemplateRef{
+				Name: name,
+			})
+		}
+	}
+}
+
+// WithStop specifies whether the CHI is stopped
+func WithStop(stop bool) CHIOption {
+	return func(chi *api.ClickHouseInstallation) {
+		chi.Spec.Stop = api.NewStringBool(stop)
+	}
+}
+
+// ensureTemplates ensures templates section of the CHI is in place
+func ensureTemplates(chi *api.ClickHouseInstallation) {
+	if chi.Spec.Templates == nil {
+		chi.Spec.Templates = api.NewChiTemplates()
+	}
+}
@@ -0,0 +1,221 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package builder
+
+import (
+	core "k8s.io/api/core/v1"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+)
+
+// ClusterOption modifies cluster
+type ClusterOption func(cluster *api.Cluster)
+
+// NewCluster creates new cluster with specified options applied
+func NewCluster(name string, options ...ClusterOption) *api.Cluster {
+	cluster := &api.Cluster{
+		Name: name,
+	}
+	return ApplyCluster(cluster, options...)
+}
+
+// ApplyCluster applies options to existing cluster
+func ApplyCluster(cluster *api.Cluster, options ...ClusterOption) *api.Cluster {
+	for _, option := range options {
+		option(cluster)
+	}
+	return cluster
+}
+
+// WithShards specifies number of shards in the cluster
+func WithShards(count int) ClusterOption {
+	return func(cluster *api.Cluster) {
+		ensureLayout(cluster)
+		cluster.Layout.ShardsCount = count
+	}
+}
+
+// WithReplicas specifies number of replicas in each shard of the cluster
+func WithReplicas(count int) ClusterOption {
+	return func(cluster *api.Cluster) {
+		ensureLayout(cluster)
+		cluster.Layout.ReplicasCount = count
+	}
+}
+
+// WithShardTemplates overrides templates of shards with specified indices
+func WithShardTemplates(templates *api.ChiTemplateNames, shards ...int) ClusterOption {
+	return func(cluster *api.Cluster) {
+		ensureLayout(cluster)
+		cluster.Layout.ShardOverrides = append(cluster.Layout.ShardOverrides, api.ChiShardOverride{
+			Shards:    shards,
+			Templates: templates,
+		})
+	}
+}
+
+// WithPodTemplate specifies pod template used by hosts of the cluster
+func WithPodTemplate(name string) ClusterOption {
+	return func(cluster *api.Cluster) {
+		ensureTemplateNames(cluster)
+		cluster.Templates.PodTemplate = name
+	}
+}
+
+// WithDataVolumeClaimTemplate specifies volume claim template used for data of hosts of the cluster
+func WithDataVolumeClaimTemplate(name string) ClusterOption {
+	return func(cluster *api.Cluster) {
+		ensureTemplateNames(cluster)
+		cluster.Templates.DataVolumeClaimTemplate = name
+	}
+}
+
+// WithSystemLogVolumeClaimTemplate specifies volume claim template used for system log tables of hosts of the cluster
+func WithSystemLogVolumeClaimTemplate(name string) ClusterOption {
+	return func(cluster *api.Cluster) {
+		ensureTemplateNames(cluster)
+		cluster.Templates.SystemLogVolumeClaimTemplate = name
+	}
+}
+
+// WithHostTemplate specifies host template used by hosts of the cluster
+func WithHostTemplate(name string) ClusterOption {
+	return func(cluster *api.Cluster) {
+		ensureTemplateNames(cluster)
+		cluster.Templates.HostTemplate = name
+	}
+}
+
+// WithServiceTemplate specifies service template used by hosts of the cluster
+func WithServiceTemplate(name string) ClusterOption {
+	return func(cluster *api.Cluster) {
+		ensureTemplateNames(cluster)
+		cluster.Templates.ServiceTemplate = name
+	}
+}
+
+// WithReplicaServiceTemplate specifies service template of the per-host Services of the cluster
+func WithReplicaServiceTemplate(name string) ClusterOption {
+	return func(cluster *api.Cluster) {
+		ensureTemplateNames(cluster)
+		cluster.Templates.ReplicaServiceTemplate = name
+	}
+}
+
+// WithReaderServiceTemplate specifies service template of the reader Service of the cluster
+func WithReaderServiceTemplate(name string) ClusterOption {
+	return func(cluster *api.Cluster) {
+		ensureTemplateNames(cluster)
+		cluster.Templates.ReaderServiceTemplate = name
+	}
+}
+
+// WithWriterServiceTemplate specifies service template of the writer Service of the cluster
+func WithWriterServiceTemplate(name string) ClusterOption {
+	return func(cluster *api.Cluster) {
+		ensureTemplateNames(cluster)
+		cluster.Templates.WriterServiceTemplate = name
+	}
+}
+
+// WithZookeeper specifies ZooKeeper nodes used by the cluster
+func WithZookeeper(nodes ...api.ChiZookeeperNode) ClusterOption {
+	return func(cluster *api.Cluster) {
+		if cluster.Zookeeper == nil {
+			cluster.Zookeeper = api.NewChiZookeeperConfig()
+		}
+		cluster.Zookeeper.Nodes = nodes
+	}
+}
+
+// WithZones specifies zones replicas of each shard are spread across, round-robin
+func WithZones(zones ...string) ClusterOption {
+	return func(cluster *api.Cluster) {
+		cluster.Zones = &api.ChiPodTemplateZone{
+			Values: zones,
+		}
+	}
+}
+
+// WithReadTierReplicas specifies replicas with specified indices to be of the read tier
+func WithReadTierReplicas(replicas ...int) ClusterOption {
+	return func(cluster *api.Cluster) {
+		ensureLayout(cluster)
+		for _, replica := range replicas {
+			for len(cluster.Layout.Replicas) <= replica {
+				cluster.Layout.Replicas = append(cluster.Layout.Replicas, api.ChiReplica{})
+			}
+			cluster.Layout.Replicas[replica].Tier = api.HostTierRead
+		}
+	}
+}
+
+// WithReplicaPriority specifies priority of the replica with specified index in remote servers
+func WithReplicaPriority(replica, priority int) ClusterOption {
+	return func(cluster *api.Cluster) {
+		ensureLayout(cluster)
+		for len(cluster.Layout.Replicas) <= replica {
+			cluster.Layout.Replicas = append(cluster.Layout.Replicas, api.ChiReplica{})
+		}
+		cluster.Layout.Replicas[replica].Priority = &priority
+	}
+}
+
+// WithResources specifies resources of ClickHouse container of hosts of the cluster
+func WithResources(resources core.ResourceRequirements) ClusterOption {
+	return func(cluster *api.Cluster) {
+		cluster.Resources = resources.DeepCopy()
+	}
+}
+
+// WithHostResources specifies resources of ClickHouse container of the host with specified shard and replica indices.
+// Number of replicas specified for the cluster before is kept for the shard.
+func WithHostResources(shard, replica int, resources core.ResourceRequirements) ClusterOption {
+	return func(cluster *api.Cluster) {
+		ensureLayout(cluster)
+		for len(cluster.Layout.Shards) <= shard {
+			cluster.Layout.Shards = append(cluster.Layout.Shards, api.ChiShard{})
+		}
+		s := &cluster.Layout.Shards[shard]
+		for len(s.Hosts) <= replica {
+			s.Hosts = append(s.Hosts, &api.ChiHost{})
+		}
+		s.Hosts[replica].Resources = resources.DeepCopy()
+		if cluster.Layout.ReplicasCount > len(s.Hosts) {
+			s.ReplicasCount = cluster.Layout.ReplicasCount
+		}
+	}
+}
+
+// ensureLayout ensures layout section of the cluster is in place
+func ensureLayout(cluster *api.Cluster) {
+	if cluster.Layout == nil {
+		cluster.Layout = api.NewChiClusterLayout()
+	}
+}
+
+// ensureTemplateNames ensures templates section of the cluster is in place
+func ensureTemplateNames(cluster *api.Cluster) {
+	if cluster.Templates == nil {
+		cluster.Templates = api.NewChiTemplateNames()
+	}
+}
+
+// WithSpot specifies replicas of each shard running on spot nodes
+func WithSpot(spot api.ChiClusterSpot) ClusterOption {
+	return func(cluster *api.Cluster) {
+		cluster.Spot = spot.DeepCopy()
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package builder
+
+import (
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	model "github.com/altinity/clickhouse-operator/pkg/model/chi"
+)
+
+// NewPodTemplate creates new pod template running ClickHouse of the specified image
+func NewPodTemplate(name, image string) api.ChiPodTemplate {
+	return api.ChiPodTemplate{
+		Name: name,
+		Spec: core.PodSpec{
+			Containers: []core.Container{
+				{
+					Name:  model.ClickHouseContainerName,
+					Image: image,
+				},
+			},
+		},
+	}
+}
+
+// NewVolumeClaimTemplate creates new volume claim template requesting storage of the specified size
+func NewVolumeClaimTemplate(name string, size resource.Quantity) api.ChiVolumeClaimTemplate {
+	return api.ChiVolumeClaimTemplate{
+		Name: name,
+		Spec: core.PersistentVolumeClaimSpec{
+			AccessModes: []core.PersistentVolumeAccessMode{
+				core.ReadWriteOnce,
+			},
+			Resources: core.ResourceRequirements{
+				Requests: core.ResourceList{
+					core.ResourceStorage: size,
+				},
+			},
+		},
+	}
+}
+
+// NewServiceTemplate creates new service template exposing default http and tcp ports of ClickHouse
+func NewServiceTemplate(name string) api.ChiServiceTemplate {
+	return api.ChiServiceTemplate{
+		Name: name,
+		Spec: core.ServiceSpec{
+			Ports: []core.ServicePort{
+				{
+					Name: model.ChDefaultHTTPPortName,
+					Port: model.ChDefaultHTTPPortNumber,
+				},
+				{
+					Name: model.ChDefaultTCPPortName,
+					Port: model.ChDefaultTCPPortNumber,
+				},
+			},
+			Type: core.ServiceTypeClusterIP,
+		},
+	}
+}
@@ -0,0 +1,178 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package bundle exports a CHI into a local development bundle, so developers are able to run
+// a scaled-down copy of a cluster with docker-compose or kind.
+package bundle
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/kubernetes-sigs/yaml"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/normalizer"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/render"
+)
+
+const (
+	// FileCompose specifies path of docker-compose file within the bundle
+	FileCompose = "docker-compose.yaml"
+	// FileKind specifies path of scaled-down CHI manifest to be applied to kind cluster with the operator installed
+	FileKind = "kind/clickhouse-installation.yaml"
+	// DirConfig specifies directory within the bundle ClickHouse config files are placed into
+	DirConfig = "config"
+
+	defaultZookeeperImage = "zookeeper:3.8"
+)
+
+// Options specifies how the bundle is built
+type Options struct {
+	// MaxShards limits number of shards in each cluster, 0 means no limit
+	MaxShards int
+	// MaxReplicas limits number of replicas in each shard, 0 means no limit
+	MaxReplicas int
+	// ZookeeperImage specifies image ZooKeeper nodes referenced by the CHI are run with
+	ZookeeperImage string
+}
+
+// NewOptions creates new Options
+func NewOptions() *Options {
+	return &Options{
+		ZookeeperImage: defaultZookeeperImage,
+	}
+}
+
+// Bundle specifies files of the bundle
+type Bundle struct {
+	// Files maps path relative to the bundle root to file content
+	Files map[string][]byte
+	// Warnings lists parts of the CHI not reproduced in the bundle
+	Warnings []string
+}
+
+// Build builds bundle out of the CHI
+func Build(chi *api.ClickHouseInstallation, options *Options) (*Bundle, error) {
+	if options == nil {
+		options = NewOptions()
+	}
+	render.Init("")
+
+	chi = scaleDown(chi, options)
+	normalized, err := normalizer.NewNormalizer(render.NoSecrets).CreateTemplatedCHI(chi, normalizer.NewOptions())
+	if err != nil {
+		return nil, err
+	}
+
+	bundle := &Bundle{
+		Files: make(map[string][]byte),
+	}
+	if err := bundle.addCompose(normalized, render.RenderNormalized(normalized), options); err != nil {
+		return nil, err
+	}
+	if err := bundle.addKind(chi); err != nil {
+		return nil, err
+	}
+	return bundle, nil
+}
+
+// Paths returns sorted paths of the bundle files
+func (b *Bundle) Paths() []string {
+	var paths []string
+	for path := range b.Files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// Write writes the bundle into the directory
+func (b *Bundle) Write(dir string) error {
+	for _, path := range b.Paths() {
+		file := filepath.Join(dir, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(file, b.Files[path], 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addKind adds scaled-down CHI manifest
+func (b *Bundle) addKind(chi *api.ClickHouseInstallation) error {
+	data, err := yaml.Marshal(chi.Copy(api.CopyCHIOptions{
+		SkipStatus:        true,
+		SkipManagedFields: true,
+	}))
+	if err != nil {
+		return err
+	}
+	b.Files[FileKind] = data
+	return nil
+}
+
+// scaleDown returns copy of the CHI with layout limited according to the options
+func scaleDown(chi *api.ClickHouseInstallation, options *Options) *api.ClickHouseInstallation {
+	chi = chi.DeepCopy()
+	// Runtime-only metadata makes no sense in the bundle
+	chi.ObjectMeta.ResourceVersion = ""
+	chi.ObjectMeta.UID = ""
+	chi.ObjectMeta.Generation = 0
+	chi.ObjectMeta.OwnerReferences = nil
+	if chi.Spec.Configuration == nil {
+		return chi
+	}
+
+	for _, cluster := range chi.Spec.Configuration.Clusters {
+		if cluster.Layout == nil {
+			continue
+		}
+		layout := cluster.Layout
+		layout.ShardsCount = limit(layout.ShardsCount, options.MaxShards)
+		layout.ReplicasCount = limit(layout.ReplicasCount, options.MaxReplicas)
+		if options.MaxShards > 0 && len(layout.Shards) > options.MaxShards {
+			layout.Shards = layout.Shards[:options.MaxShards]
+		}
+		if options.MaxReplicas > 0 && len(layout.Replicas) > options.MaxReplicas {
+			layout.Replicas = layout.Replicas[:options.MaxReplicas]
+		}
+		for i := range layout.Shards {
+			shard := &layout.Shards[i]
+			shard.ReplicasCount = limit(shard.ReplicasCount, options.MaxReplicas)
+			if options.MaxReplicas > 0 && len(shard.Hosts) > options.MaxReplicas {
+				shard.Hosts = shard.Hosts[:options.MaxReplicas]
+			}
+		}
+		for i := range layout.Replicas {
+			replica := &layout.Replicas[i]
+			replica.ShardsCount = limit(replica.ShardsCount, options.MaxShards)
+			if options.MaxShards > 0 && len(replica.Hosts) > options.MaxShards {
+				replica.Hosts = replica.Hosts[:options.MaxShards]
+			}
+		}
+	}
+	return chi
+}
+
+// limit limits value with max, 0 max means no limit
+func limit(value, max int) int {
+	if (max > 0) && (value > max) {
+		return max
+	}
+	return value
+}
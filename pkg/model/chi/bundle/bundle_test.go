@@ -0,0 +1,105 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bundle_test
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/kubernetes-sigs/yaml"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/builder"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/bundle"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/render"
+)
+
+func TestMain(m *testing.M) {
+	render.Init("../../../../config/config.yaml")
+	os.Exit(m.Run())
+}
+
+func TestBuild(t *testing.T) {
+	chi := builder.NewCHI("prod", "events",
+		builder.WithCluster(builder.NewCluster("main",
+			builder.WithShards(3),
+			builder.WithReplicas(3),
+			builder.WithZookeeper(api.ChiZookeeperNode{Host: "zookeeper.zoo"}),
+		)),
+	)
+
+	options := bundle.NewOptions()
+	options.MaxShards = 1
+	options.MaxReplicas = 2
+	b, err := bundle.Build(chi, options)
+	if err != nil {
+		t.Fatalf("unable to build bundle err: %v", err)
+	}
+
+	var compose struct {
+		Services map[string]struct {
+			Image    string   `json:"image"`
+			Volumes  []string `json:"volumes"`
+			Ports    []string `json:"ports"`
+			Networks map[string]struct {
+				Aliases []string `json:"aliases"`
+			} `json:"networks"`
+		} `json:"services"`
+	}
+	if err := yaml.Unmarshal(b.Files[bundle.FileCompose], &compose); err != nil {
+		t.Fatalf("unable to parse %s err: %v", bundle.FileCompose, err)
+	}
+
+	// 1 shard x 2 replicas and ZooKeeper node
+	if got := len(compose.Services); got != 3 {
+		t.Fatalf("services: got %d want %d\n%s", got, 3, b.Files[bundle.FileCompose])
+	}
+	zk, ok := compose.Services["zookeeper-1"]
+	if !ok || zk.Networks["default"].Aliases[0] != "zookeeper.zoo" {
+		t.Errorf("zookeeper service is not aliased as referenced by the CHI\n%s", b.Files[bundle.FileCompose])
+	}
+
+	published := 0
+	for name, service := range compose.Services {
+		if !strings.HasPrefix(name, "chi-") {
+			continue
+		}
+		if len(service.Ports) > 0 {
+			published++
+		}
+		for _, volume := range service.Volumes {
+			dir := strings.TrimPrefix(strings.Split(volume, ":")[0], "./")
+			found := false
+			for _, path := range b.Paths() {
+				found = found || strings.HasPrefix(path, dir+"/")
+			}
+			if !found {
+				t.Errorf("service %s mounts %s having no files", name, dir)
+			}
+		}
+	}
+	if published != 1 {
+		t.Errorf("published hosts: got %d want %d", published, 1)
+	}
+
+	var kind api.ClickHouseInstallation
+	if err := yaml.Unmarshal(b.Files[bundle.FileKind], &kind); err != nil {
+		t.Fatalf("unable to parse %s err: %v", bundle.FileKind, err)
+	}
+	if layout := kind.Spec.Configuration.Clusters[0].Layout; layout.ShardsCount != 1 || layout.ReplicasCount != 2 {
+		t.Errorf("kind layout: got %dx%d want 1x2", layout.ShardsCount, layout.ReplicasCount)
+	}
+}
@@ -0,0 +1,214 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bundle
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/kubernetes-sigs/yaml"
+
+	core "k8s.io/api/core/v1"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	model "github.com/altinity/clickhouse-operator/pkg/model/chi"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/render"
+	"github.com/altinity/clickhouse-operator/pkg/model/k8s"
+	"github.com/altinity/clickhouse-operator/pkg/util"
+)
+
+// compose specifies docker-compose file
+type compose struct {
+	Services map[string]*composeService `json:"services"`
+}
+
+// composeService specifies service of docker-compose file
+type composeService struct {
+	Image       string                     `json:"image"`
+	Hostname    string                     `json:"hostname,omitempty"`
+	Environment map[string]string          `json:"environment,omitempty"`
+	Volumes     []string                   `json:"volumes,omitempty"`
+	Ports       []string                   `json:"ports,omitempty"`
+	DependsOn   []string                   `json:"depends_on,omitempty"`
+	Networks    map[string]*composeNetwork `json:"networks,omitempty"`
+}
+
+// composeNetwork specifies network attachment of docker-compose service
+type composeNetwork struct {
+	Aliases []string `json:"aliases,omitempty"`
+}
+
+// addCompose adds docker-compose file running ClickHouse hosts and ZooKeeper nodes of the CHI
+func (b *Bundle) addCompose(chi *api.ClickHouseInstallation, manifests *render.Manifests, options *Options) error {
+	c := &compose{
+		Services: make(map[string]*composeService),
+	}
+
+	zookeepers := b.addComposeZookeepers(c, chi, options)
+
+	for _, configMap := range manifests.ConfigMaps {
+		for file, content := range configMap.Data {
+			b.Files[path.Join(DirConfig, configMap.Name, file)] = []byte(content)
+		}
+	}
+
+	first := true
+	chi.WalkHosts(func(host *api.ChiHost) error {
+		name := model.CreateStatefulSetName(host)
+		for _, statefulSet := range manifests.StatefulSets {
+			if statefulSet.Name == name {
+				c.Services[name] = b.newComposeHost(host, &statefulSet.Spec.Template.Spec, manifests, first)
+				c.Services[name].DependsOn = zookeepers
+				first = false
+			}
+		}
+		return nil
+	})
+
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return err
+	}
+	b.Files[FileCompose] = data
+	return nil
+}
+
+// newComposeHost creates docker-compose service running the host.
+// Ports of the first host are published, so the cluster is reachable from the developer's machine.
+func (b *Bundle) newComposeHost(host *api.ChiHost, podSpec *core.PodSpec, manifests *render.Manifests, publish bool) *composeService {
+	service := &composeService{
+		Hostname: model.CreatePodHostname(host),
+		Networks: map[string]*composeNetwork{
+			"default": {
+				Aliases: util.Unique([]string{
+					model.CreateInstanceHostname(host),
+					model.CreateFQDN(host),
+				}),
+			},
+		},
+	}
+
+	container, ok := k8s.PodSpecContainerGet(podSpec, model.ClickHouseContainerName, 0)
+	if !ok {
+		b.warn("host %s has no ClickHouse container", host.GetName())
+		return service
+	}
+	service.Image = container.Image
+
+	for _, env := range container.Env {
+		if value, ok := b.resolveEnv(host, &env, manifests); ok {
+			if service.Environment == nil {
+				service.Environment = make(map[string]string)
+			}
+			service.Environment[env.Name] = value
+		}
+	}
+
+	for _, mount := range container.VolumeMounts {
+		for _, volume := range podSpec.Volumes {
+			if volume.Name != mount.Name {
+				continue
+			}
+			if volume.ConfigMap == nil {
+				// Data volumes are not reproduced, containers keep data inside
+				continue
+			}
+			service.Volumes = append(service.Volumes, fmt.Sprintf("./%s:%s:ro", path.Join(DirConfig, volume.ConfigMap.Name), mount.MountPath))
+		}
+	}
+
+	if publish {
+		for _, port := range container.Ports {
+			service.Ports = append(service.Ports, fmt.Sprintf("%d:%d", port.ContainerPort, port.ContainerPort))
+		}
+	}
+
+	return service
+}
+
+// resolveEnv resolves value of the env var, secret references are resolved with rendered secrets only
+func (b *Bundle) resolveEnv(host *api.ChiHost, env *core.EnvVar, manifests *render.Manifests) (string, bool) {
+	if env.ValueFrom == nil {
+		return env.Value, true
+	}
+	if ref := env.ValueFrom.SecretKeyRef; ref != nil {
+		for _, secret := range manifests.Secrets {
+			if secret.Name != ref.Name {
+				continue
+			}
+			if value, ok := secret.StringData[ref.Key]; ok {
+				return value, true
+			}
+			if value, ok := secret.Data[ref.Key]; ok {
+				return string(value), true
+			}
+		}
+	}
+	b.warn("host %s env var %s is not resolved", host.GetName(), env.Name)
+	return "", false
+}
+
+// addComposeZookeepers adds ZooKeeper nodes referenced by the CHI and returns names of services added
+func (b *Bundle) addComposeZookeepers(c *compose, chi *api.ClickHouseInstallation, options *Options) []string {
+	var nodes []api.ChiZookeeperNode
+	seen := make(map[string]bool)
+	chi.WalkClusters(func(cluster *api.Cluster) error {
+		if cluster.Zookeeper == nil {
+			return nil
+		}
+		for _, node := range cluster.Zookeeper.Nodes {
+			if !seen[node.Host] {
+				seen[node.Host] = true
+				nodes = append(nodes, node)
+			}
+		}
+		return nil
+	})
+
+	var names, servers []string
+	for i, node := range nodes {
+		port := node.Port
+		if port == 0 {
+			port = 2181
+		}
+		names = append(names, fmt.Sprintf("zookeeper-%d", i+1))
+		servers = append(servers, fmt.Sprintf("server.%d=%s:2888:3888;%d", i+1, names[i], port))
+	}
+
+	for i, node := range nodes {
+		c.Services[names[i]] = &composeService{
+			Image:    options.ZookeeperImage,
+			Hostname: names[i],
+			Environment: map[string]string{
+				"ZOO_MY_ID":   fmt.Sprintf("%d", i+1),
+				"ZOO_SERVERS": strings.Join(servers, " "),
+			},
+			Networks: map[string]*composeNetwork{
+				"default": {
+					// ClickHouse config refers to ZooKeeper by the host specified in the CHI
+					Aliases: []string{node.Host},
+				},
+			},
+		}
+	}
+
+	return names
+}
+
+// warn appends warning to the bundle
+func (b *Bundle) warn(format string, args ...interface{}) {
+	b.Warnings = append(b.Warnings, fmt.Sprintf(format, args...))
+}
@@ -18,24 +18,33 @@ import api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity
 
 const (
 	xmlTagYandex = "yandex"
+	// systemLogsStoragePolicy is the name of both disk and storage policy used for system log tables
+	systemLogsStoragePolicy = "system_logs"
 )
 
 const (
-	configMacros        = "macros"
-	configHostnamePorts = "hostname-ports"
-	configProfiles      = "profiles"
-	configQuotas        = "quotas"
-	configRemoteServers = "remote_servers"
-	configSettings      = "settings"
-	configUsers         = "users"
-	configZookeeper     = "zookeeper"
+	configAutoTuning         = "auto-tuning"
+	configMacros             = "macros"
+	configHostnamePorts      = "hostname-ports"
+	configLogging            = "logging"
+	configManagedProfiles    = "managed-profiles"
+	configProfiles           = "profiles"
+	configQuotas             = "quotas"
+	configRemoteServers      = "remote_servers"
+	configReplicatedDatabase = "replicated-database"
+	configSettings           = "settings"
+	configSystemLogs         = "system-logs"
+	configUsers              = "users"
+	configWriteReliability   = "write-reliability"
+	configZookeeper          = "zookeeper"
 )
 
 const (
 	// DirPathCommonConfig specifies full path to folder, where generated common XML files for ClickHouse would be placed
 	// for the following sections:
-	// 1. remote servers
-	// 2. operator-provided additional config files
+	// 1. host files shared by all hosts
+	// 2. remote servers
+	// 3. operator-provided additional config files
 	DirPathCommonConfig = "/etc/clickhouse-server/" + api.CommonConfigDir + "/"
 
 	// DirPathUsersConfig specifies full path to folder, where generated users XML files for ClickHouse would be placed
@@ -53,6 +62,7 @@ const (
 	// 3. settings
 	// 4. files
 	// 5. operator-provided additional config files
+	// Files which are the same for all hosts are placed into common config instead
 	DirPathHostConfig = "/etc/clickhouse-server/" + api.HostConfigDir + "/"
 
 	// DirPathSecretFilesConfig specifies full path to folder, where secrets are mounted
@@ -64,6 +74,12 @@ const (
 	// DirPathClickHouseLog  specifies full path of data folder where ClickHouse would place its log files
 	DirPathClickHouseLog = "/var/log/clickhouse-server"
 
+	// DirPathClickHouseSystemLogs specifies full path of folder where system log tables volume is mounted
+	DirPathClickHouseSystemLogs = "/var/lib/clickhouse-system-logs"
+
+	// DirPathClickHouseCoreDumps specifies full path of folder where core dumps volume is mounted
+	DirPathClickHouseCoreDumps = "/var/lib/clickhouse-core-dumps"
+
 	// DirPathDockerEntrypointInit specified full path of docker-entrypoint-initdb.d
 	// For more details please check: https://github.com/ClickHouse/ClickHouse/issues/3319
 	DirPathDockerEntrypointInit = "/docker-entrypoint-initdb.d"
@@ -86,6 +102,8 @@ const (
 	ClickHouseContainerName = "clickhouse"
 	// ClickHouseLogContainerName specifies name of the logger container in the pod
 	ClickHouseLogContainerName = "clickhouse-log"
+	// CoreDumpPatternContainerName specifies name of the init container pointing node's kernel.core_pattern into core dumps volume
+	CoreDumpPatternContainerName = "core-dump-pattern"
 )
 
 const (
@@ -102,6 +120,20 @@ const (
 	ChDefaultInterserverHTTPPortNumber = int32(9009)
 )
 
+const (
+	// ChDefaultInterserverHTTPSPortNumber specifies interserver port of clusters with managed TLS
+	ChDefaultInterserverHTTPSPortNumber = int32(9010)
+
+	// DirPathTLS specifies full path to folder, where managed certificates of the cluster are mounted
+	DirPathTLS = "/etc/clickhouse-server/tls/"
+
+	// Files of managed certificates of the cluster
+	TLSCertificateFile  = "tls.crt"
+	TLSPrivateKeyFile   = "tls.key"
+	TLSCAFile           = "ca.crt"
+	TLSCAPrivateKeyFile = "ca.key"
+)
+
 const (
 	// ZkDefaultPort specifies Zookeeper default port
 	ZkDefaultPort = 2181
@@ -15,6 +15,8 @@
 package chi
 
 import (
+	"sync"
+
 	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
 	"github.com/altinity/clickhouse-operator/pkg/util"
 )
@@ -25,6 +27,10 @@ type ClickHouseConfigFilesGenerator struct {
 	chConfigGenerator *ClickHouseConfigGenerator
 	// clickhouse-operator configuration
 	chopConfig *api.OperatorConfig
+
+	// Host config files shared by all hosts are computed once per CHI and reused for every host
+	hostSharedOnce sync.Once
+	hostShared     map[string]string
 }
 
 // NewClickHouseConfigFilesGenerator creates new clickhouse configuration generator object
@@ -78,11 +84,17 @@ func (c *ClickHouseConfigFilesGenerator) CreateConfigFilesGroupCommon(options *C
 	}
 	commonConfigSections := make(map[string]string)
 	// commonConfigSections maps section name to section XML chopConfig of the following sections:
-	// 1. remote servers
-	// 2. common settings
-	// 3. common files
+	// 1. host files shared by all hosts
+	// 2. remote servers
+	// 3. common settings
+	// 4. logging
+	// 5. common files
+	for filename, content := range c.getConfigFilesGroupHostShared() {
+		commonConfigSections[createConfigHostSharedFilename(filename)] = content
+	}
 	util.IncludeNonEmpty(commonConfigSections, createConfigSectionFilename(configRemoteServers), c.chConfigGenerator.GetRemoteServers(options.GetRemoteServersGeneratorOptions()))
 	util.IncludeNonEmpty(commonConfigSections, createConfigSectionFilename(configSettings), c.chConfigGenerator.GetSettingsGlobal())
+	util.IncludeNonEmpty(commonConfigSections, createConfigSectionFilename(configLogging), c.chConfigGenerator.GetLogging())
 	util.MergeStringMapsOverwrite(commonConfigSections, c.chConfigGenerator.GetSectionFromFiles(api.SectionCommon, true, nil))
 	// Extra user-specified config files
 	util.MergeStringMapsOverwrite(commonConfigSections, c.chopConfig.ClickHouse.Config.File.Runtime.CommonConfigFiles)
@@ -98,9 +110,15 @@ func (c *ClickHouseConfigFilesGenerator) CreateConfigFilesGroupUsers() map[strin
 	// 2. quotas
 	// 3. profiles
 	// 4. user files
+	// 5. auto-tuned profile settings
+	// 6. profiles shipped with the operator
 	util.IncludeNonEmpty(commonUsersConfigSections, createConfigSectionFilename(configUsers), c.chConfigGenerator.GetUsers())
 	util.IncludeNonEmpty(commonUsersConfigSections, createConfigSectionFilename(configQuotas), c.chConfigGenerator.GetQuotas())
 	util.IncludeNonEmpty(commonUsersConfigSections, createConfigSectionFilename(configProfiles), c.chConfigGenerator.GetProfiles())
+	util.IncludeNonEmpty(commonUsersConfigSections, createConfigSectionFilename(configManagedProfiles), c.chConfigGenerator.GetManagedProfiles())
+	util.IncludeNonEmpty(commonUsersConfigSections, createConfigSectionFilename(configAutoTuning), c.chConfigGenerator.GetAutoTuningProfile())
+	util.IncludeNonEmpty(commonUsersConfigSections, createConfigSectionFilename(configWriteReliability), c.chConfigGenerator.GetWriteReliabilityProfile())
+	util.IncludeNonEmpty(commonUsersConfigSections, createConfigSectionFilename(configReplicatedDatabase), c.chConfigGenerator.GetReplicatedDatabaseProfile())
 	util.MergeStringMapsOverwrite(commonUsersConfigSections, c.chConfigGenerator.GetSectionFromFiles(api.SectionUsers, false, nil))
 	// Extra user-specified config files
 	util.MergeStringMapsOverwrite(commonUsersConfigSections, c.chopConfig.ClickHouse.Config.File.Runtime.UsersConfigFiles)
@@ -108,14 +126,59 @@ func (c *ClickHouseConfigFilesGenerator) CreateConfigFilesGroupUsers() map[strin
 	return commonUsersConfigSections
 }
 
-// CreateConfigFilesGroupHost creates host config files
+// CreateConfigFilesGroupHost creates host config files personal for the host.
+// Files which are the same for all hosts go to common config files, so a change of them touches one ConfigMap only.
 func (c *ClickHouseConfigFilesGenerator) CreateConfigFilesGroupHost(host *api.ChiHost) map[string]string {
+	hostConfigSections := c.createConfigFilesGroupHostAll(host)
+	for filename := range c.getConfigFilesGroupHostShared() {
+		delete(hostConfigSections, filename)
+	}
+
+	return hostConfigSections
+}
+
+// getConfigFilesGroupHostShared gets host config files, which are the same for all hosts of the CHI.
+// Files are rendered for all hosts on first call only, later calls reuse the result.
+func (c *ClickHouseConfigFilesGenerator) getConfigFilesGroupHostShared() map[string]string {
+	c.hostSharedOnce.Do(func() {
+		c.hostShared = c.createConfigFilesGroupHostShared()
+	})
+	return c.hostShared
+}
+
+// createConfigFilesGroupHostShared creates host config files, which are the same for all hosts of the CHI
+func (c *ClickHouseConfigFilesGenerator) createConfigFilesGroupHostShared() map[string]string {
+	var shared map[string]string
+	c.chConfigGenerator.chi.WalkHosts(func(host *api.ChiHost) error {
+		files := c.createConfigFilesGroupHostAll(host)
+		if shared == nil {
+			shared = files
+			// Macros and ports are personal by nature, even in case of a single host
+			delete(shared, createConfigSectionFilename(configMacros))
+			delete(shared, createConfigSectionFilename(configHostnamePorts))
+			return nil
+		}
+		for filename, content := range shared {
+			if hostContent, ok := files[filename]; !ok || (hostContent != content) {
+				delete(shared, filename)
+			}
+		}
+		return nil
+	})
+
+	return shared
+}
+
+// createConfigFilesGroupHostAll creates all host config files, including the ones shared by all hosts
+func (c *ClickHouseConfigFilesGenerator) createConfigFilesGroupHostAll(host *api.ChiHost) map[string]string {
 	// Prepare for this replica deployment chopConfig files map as filename->content
 	hostConfigSections := make(map[string]string)
 	util.IncludeNonEmpty(hostConfigSections, createConfigSectionFilename(configMacros), c.chConfigGenerator.GetHostMacros(host))
 	util.IncludeNonEmpty(hostConfigSections, createConfigSectionFilename(configHostnamePorts), c.chConfigGenerator.GetHostHostnameAndPorts(host))
 	util.IncludeNonEmpty(hostConfigSections, createConfigSectionFilename(configZookeeper), c.chConfigGenerator.GetHostZookeeper(host))
 	util.IncludeNonEmpty(hostConfigSections, createConfigSectionFilename(configSettings), c.chConfigGenerator.GetSettings(host))
+	util.IncludeNonEmpty(hostConfigSections, createConfigSectionFilename(configAutoTuning), c.chConfigGenerator.GetHostAutoTuning(host))
+	util.IncludeNonEmpty(hostConfigSections, createConfigSectionFilename(configSystemLogs), c.chConfigGenerator.GetHostSystemLogs(host))
 	util.MergeStringMapsOverwrite(hostConfigSections, c.chConfigGenerator.GetSectionFromFiles(api.SectionHost, true, host))
 	// Extra user-specified config files
 	util.MergeStringMapsOverwrite(hostConfigSections, c.chopConfig.ClickHouse.Config.File.Runtime.HostConfigFiles)
@@ -128,3 +191,9 @@ func (c *ClickHouseConfigFilesGenerator) CreateConfigFilesGroupHost(host *api.Ch
 func createConfigSectionFilename(section string) string {
 	return "chop-generated-" + section + ".xml"
 }
+
+// createConfigHostSharedFilename creates filename of a host configuration file shared by all hosts.
+// Common files are merged by ClickHouse after host files, so the prefix keeps shared host files ahead of common ones
+func createConfigHostSharedFilename(filename string) string {
+	return "00-host-" + filename
+}
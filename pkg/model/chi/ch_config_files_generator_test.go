@@ -0,0 +1,131 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi_test
+
+import (
+	"strings"
+	"testing"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/chop"
+	model "github.com/altinity/clickhouse-operator/pkg/model/chi"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/builder"
+)
+
+func TestCreateConfigFilesGroups(t *testing.T) {
+	chi := normalize(t, builder.NewCHI("test", "files",
+		builder.WithCluster(builder.NewCluster("main", builder.WithReplicas(2))),
+		builder.WithFiles(map[string]string{
+			"config.d/custom.xml": "<yandex><custom/></yandex>",
+			"users.d/extra.xml":   "<yandex><users/></yandex>",
+			"conf.d/host.xml":     "<yandex><host/></yandex>",
+			"source.csv":          "a,b,c",
+		}),
+	))
+	generator := model.NewClickHouseConfigFilesGenerator(model.NewClickHouseConfigGenerator(chi), chop.Config())
+
+	want := map[string]map[string]string{
+		"common": {
+			"custom.xml": "<yandex><custom/></yandex>",
+			"source.csv": "a,b,c",
+			// Host file is the same for all hosts, so it is shared via the common group
+			"00-host-host.xml": "<yandex><host/></yandex>",
+		},
+		"users": {
+			"extra.xml": "<yandex><users/></yandex>",
+		},
+		"0-0": {
+			"host.xml": "",
+		},
+		"0-1": {
+			"host.xml": "",
+		},
+	}
+	groups := map[string]map[string]string{
+		"common": generator.CreateConfigFilesGroupCommon(nil),
+		"users":  generator.CreateConfigFilesGroupUsers(),
+		"0-0":    generator.CreateConfigFilesGroupHost(chi.FindCluster("main").FindHost(0, 0)),
+		"0-1":    generator.CreateConfigFilesGroupHost(chi.FindCluster("main").FindHost(0, 1)),
+	}
+	for group, files := range want {
+		for file, content := range files {
+			if got := groups[group][file]; got != content {
+				t.Errorf("%s: got %s %q want %q", group, file, got, content)
+			}
+		}
+	}
+}
+
+func TestCreateConfigFilesGroupUsersManagedProfiles(t *testing.T) {
+	input := builder.NewCHI("test", "managed", builder.WithCluster(builder.NewCluster("main")))
+	input.Spec.Defaults = &api.ChiDefaults{
+		ManagedProfiles: []string{"heavy-analytics", "unknown", "heavy-analytics"},
+	}
+	input.Spec.Configuration.Profiles = api.NewSettings().
+		Set("heavy-analytics/max_execution_time", api.NewSettingScalar("600"))
+	chi := normalize(t, input)
+	users := model.NewClickHouseConfigFilesGenerator(model.NewClickHouseConfigGenerator(chi), chop.Config()).CreateConfigFilesGroupUsers()
+
+	managedFile, profilesFile := "chop-generated-managed-profiles.xml", "chop-generated-profiles.xml"
+	managed := users[managedFile]
+	if strings.Count(managed, "<heavy-analytics>") != 1 || strings.Contains(managed, "unknown") {
+		t.Errorf("managed profiles are not normalized:\n%s", managed)
+	}
+	if !strings.Contains(managed, "<max_bytes_before_external_group_by>10000000000</max_bytes_before_external_group_by>") {
+		t.Errorf("managed profile settings are not generated:\n%s", managed)
+	}
+	// Explicitly specified settings are in the file sorted after the managed one, so they take precedence
+	if !strings.Contains(users[profilesFile], "<max_execution_time>600</max_execution_time>") || (managedFile > profilesFile) {
+		t.Errorf("explicitly specified profile settings do not take precedence:\n%s", users[profilesFile])
+	}
+}
+
+func TestCreateConfigFilesGroupHostShared(t *testing.T) {
+	cluster := builder.NewCluster("main",
+		builder.WithReplicas(2),
+		builder.WithZookeeper(api.ChiZookeeperNode{Host: "keeper", Port: 2181}),
+	)
+	cluster.Settings = api.NewSettings().Set("max_concurrent_queries", api.NewSettingScalar("100"))
+	cluster.Layout.Replicas = []api.ChiReplica{
+		{},
+		{Settings: api.NewSettings().Set("max_concurrent_queries", api.NewSettingScalar("50"))},
+	}
+	chi := normalize(t, builder.NewCHI("test", "shared", builder.WithCluster(cluster)))
+	generator := model.NewClickHouseConfigFilesGenerator(model.NewClickHouseConfigGenerator(chi), chop.Config())
+
+	// Host files equal for all hosts go to the common group, the ones differing between hosts stay personal
+	zookeeper := "chop-generated-zookeeper.xml"
+	settings := "chop-generated-settings.xml"
+	macros := "chop-generated-macros.xml"
+	common := generator.CreateConfigFilesGroupCommon(nil)
+	if !strings.Contains(common["00-host-"+zookeeper], "<host>keeper</host>") {
+		t.Errorf("common group does not contain shared %s:\n%v", zookeeper, common)
+	}
+	if _, ok := common["00-host-"+settings]; ok {
+		t.Errorf("common group contains %s differing between hosts", settings)
+	}
+	chi.WalkHosts(func(host *api.ChiHost) error {
+		files := generator.CreateConfigFilesGroupHost(host)
+		if _, ok := files[zookeeper]; ok {
+			t.Errorf("host %s: group contains shared %s", host.GetName(), zookeeper)
+		}
+		for _, filename := range []string{settings, macros} {
+			if _, ok := files[filename]; !ok {
+				t.Errorf("host %s: group does not contain personal %s", host.GetName(), filename)
+			}
+		}
+		return nil
+	})
+}
@@ -17,6 +17,8 @@ package chi
 import (
 	"bytes"
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 
 	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
@@ -33,6 +35,12 @@ const (
 	// 2. Cluster with all shards (1 replica). Used to gather/scatter data over all replicas.
 	OneShardAllReplicasClusterName = "all-replicated"
 	AllShardsOneReplicaClusterName = "all-sharded"
+
+	// ZookeeperPathMacroName specifies macro holding ZooKeeper path of replicated tables of the host's cluster
+	ZookeeperPathMacroName = "zookeeper_path"
+	// ReplicatedDatabaseZookeeperPathPrefixDefault specifies prefix of default ZooKeeper path of replicated databases,
+	// followed by the name of the database
+	ReplicatedDatabaseZookeeperPathPrefixDefault = "/clickhouse/{installation}/{cluster}/databases/"
 )
 
 // ClickHouseConfigGenerator generates ClickHouse configuration files content for specified CHI
@@ -169,7 +177,9 @@ type RemoteServersGeneratorOptions struct {
 	exclude struct {
 		attributes *api.ChiHostReconcileAttributes
 		hosts      []*api.ChiHost
+		secrets    bool
 	}
+	useFQDN bool
 }
 
 // NewRemoteServersGeneratorOptions creates new remote-servers generator options
@@ -207,6 +217,26 @@ func (o *RemoteServersGeneratorOptions) ExcludeReconcileAttributes(attrs *api.Ch
 	return o
 }
 
+// ExcludeSecrets specifies to omit cluster secrets, so the result can be published to third parties
+func (o *RemoteServersGeneratorOptions) ExcludeSecrets() *RemoteServersGeneratorOptions {
+	if o == nil {
+		return o
+	}
+
+	o.exclude.secrets = true
+	return o
+}
+
+// UseFQDN specifies to address hosts by FQDN, so the result is usable from outside of the namespace
+func (o *RemoteServersGeneratorOptions) UseFQDN() *RemoteServersGeneratorOptions {
+	if o == nil {
+		return o
+	}
+
+	o.useFQDN = true
+	return o
+}
+
 // Exclude tells whether to exclude the host
 func (o *RemoteServersGeneratorOptions) Exclude(host *api.ChiHost) bool {
 	if o == nil {
@@ -259,7 +289,13 @@ func (o *RemoteServersGeneratorOptions) String() string {
 	for _, host := range o.exclude.hosts {
 		hostnames = append(hostnames, host.Name)
 	}
-	return fmt.Sprintf("exclude hosts: %s, attributes: %s", "["+strings.Join(hostnames, ",")+"]", o.exclude.attributes)
+	return fmt.Sprintf(
+		"exclude hosts: %s, attributes: %s, secrets: %t, use FQDN: %t",
+		"["+strings.Join(hostnames, ",")+"]",
+		o.exclude.attributes,
+		o.exclude.secrets,
+		o.useFQDN,
+	)
 }
 
 // defaultRemoteServersGeneratorOptions
@@ -302,11 +338,16 @@ func (c *ClickHouseConfigGenerator) ShardHostsNum(shard *api.ChiShard, options *
 	return num
 }
 
-func (c *ClickHouseConfigGenerator) getRemoteServersReplica(host *api.ChiHost, b *bytes.Buffer) {
+// readTierReplicaPriority specifies priority of read tier replicas in remote servers.
+// Replicas with greater value are less preferred, default priority is 1.
+const readTierReplicaPriority = 100
+
+func (c *ClickHouseConfigGenerator) getRemoteServersReplica(host *api.ChiHost, options *RemoteServersGeneratorOptions, b *bytes.Buffer) {
 	// <replica>
 	//		<host>XXX</host>
 	//		<port>XXX</port>
 	//		<secure>XXX</secure>
+	//		<priority>XXX</priority>
 	// </replica>
 	var port int32
 	if host.IsSecure() {
@@ -315,9 +356,60 @@ func (c *ClickHouseConfigGenerator) getRemoteServersReplica(host *api.ChiHost, b
 		port = host.TCPPort
 	}
 	util.Iline(b, 16, "<replica>")
-	util.Iline(b, 16, "    <host>%s</host>", c.getRemoteServersReplicaHostname(host))
+	hostname := c.getRemoteServersReplicaHostname(host)
+	if options != nil && options.useFQDN {
+		hostname = CreateFQDN(host)
+	}
+	util.Iline(b, 16, "    <host>%s</host>", hostname)
 	util.Iline(b, 16, "    <port>%d</port>", port)
 	util.Iline(b, 16, "    <secure>%d</secure>", c.getSecure(host))
+	switch {
+	case host.HasPriority():
+		// Explicitly specified priority allows to shift traffic onto the replica gradually
+		util.Iline(b, 16, "    <priority>%d</priority>", host.GetPriority())
+	case host.IsReadTier():
+		// Lower priority keeps distributed writes on write tier replicas while they are available
+		util.Iline(b, 16, "    <priority>%d</priority>", readTierReplicaPriority)
+	}
+	util.Iline(b, 16, "</replica>")
+}
+
+// remoteReplicaPriority specifies priority of replicas living outside of the CHI in remote servers.
+// Such replicas are reached over WAN, so they are the least preferred ones
+const remoteReplicaPriority = 200
+
+func (c *ClickHouseConfigGenerator) getRemoteServersRemoteReplica(
+	remoteReplica *api.ChiRemoteReplica,
+	shard *api.ChiShard,
+	shardIndex int,
+	b *bytes.Buffer,
+) {
+	// <replica>
+	//		<host>XXX</host>
+	//		<port>XXX</port>
+	//		<secure>XXX</secure>
+	//		<compression>XXX</compression>
+	//		<priority>XXX</priority>
+	// </replica>
+	hostname, ok := remoteReplica.GetHost(shardIndex)
+	switch {
+	case ok:
+	case remoteReplica.CHI != nil:
+		hostname = CreateRemoteReplicaFQDN(remoteReplica.CHI, shard)
+	default:
+		// Remote replica has no endpoint for this shard
+		return
+	}
+
+	if remoteReplica.Region != "" {
+		util.Iline(b, 16, "<!-- Remote replica %s in region %s -->", remoteReplica.Name, remoteReplica.Region)
+	}
+	util.Iline(b, 16, "<replica>")
+	util.Iline(b, 16, "    <host>%s</host>", hostname)
+	util.Iline(b, 16, "    <port>%d</port>", remoteReplica.Port)
+	util.Iline(b, 16, "    <secure>%s</secure>", remoteReplica.Secure.CastTo01(true))
+	util.Iline(b, 16, "    <compression>%s</compression>", remoteReplica.Compression.CastToStringTrueFalse(true))
+	util.Iline(b, 16, "    <priority>%d</priority>", remoteReplicaPriority)
 	util.Iline(b, 16, "</replica>")
 }
 
@@ -346,13 +438,15 @@ func (c *ClickHouseConfigGenerator) GetRemoteServers(options *RemoteServersGener
 		util.Iline(b, 8, "<%s>", cluster.Name)
 
 		// <secret>VALUE</secret>
-		switch cluster.Secret.Source() {
-		case api.ClusterSecretSourcePlaintext:
-			// Secret value is explicitly specified
-			util.Iline(b, 12, "<secret>%s</secret>", cluster.Secret.Value)
-		case api.ClusterSecretSourceSecretRef, api.ClusterSecretSourceAuto:
-			// Use secret via ENV var from secret
-			util.Iline(b, 12, `<secret from_env="%s" />`, InternodeClusterSecretEnvName)
+		if !options.exclude.secrets {
+			switch cluster.Secret.Source() {
+			case api.ClusterSecretSourcePlaintext:
+				// Secret value is explicitly specified
+				util.Iline(b, 12, "<secret>%s</secret>", cluster.Secret.Value)
+			case api.ClusterSecretSourceSecretRef, api.ClusterSecretSourceAuto:
+				// Use secret via ENV var from secret
+				util.Iline(b, 12, `<secret from_env="%s" />`, InternodeClusterSecretEnvName)
+			}
 		}
 
 		// Build each shard XML
@@ -374,10 +468,13 @@ func (c *ClickHouseConfigGenerator) GetRemoteServers(options *RemoteServersGener
 
 			shard.WalkHosts(func(host *api.ChiHost) error {
 				if options.Include(host) {
-					c.getRemoteServersReplica(host, b)
+					c.getRemoteServersReplica(host, options, b)
 				}
 				return nil
 			})
+			for i := range cluster.RemoteReplicas {
+				c.getRemoteServersRemoteReplica(&cluster.RemoteReplicas[i], shard, index, b)
+			}
 
 			// </shard>
 			util.Iline(b, 12, "</shard>")
@@ -407,7 +504,7 @@ func (c *ClickHouseConfigGenerator) GetRemoteServers(options *RemoteServersGener
 		util.Iline(b, 8, "        <internal_replication>true</internal_replication>")
 		c.chi.WalkHosts(func(host *api.ChiHost) error {
 			if options.Include(host) {
-				c.getRemoteServersReplica(host, b)
+				c.getRemoteServersReplica(host, options, b)
 			}
 			return nil
 		})
@@ -429,7 +526,7 @@ func (c *ClickHouseConfigGenerator) GetRemoteServers(options *RemoteServersGener
 				util.Iline(b, 12, "<shard>")
 				util.Iline(b, 12, "    <internal_replication>false</internal_replication>")
 
-				c.getRemoteServersReplica(host, b)
+				c.getRemoteServersReplica(host, options, b)
 
 				// </shard>
 				util.Iline(b, 12, "</shard>")
@@ -440,6 +537,15 @@ func (c *ClickHouseConfigGenerator) GetRemoteServers(options *RemoteServersGener
 		util.Iline(b, 8, "</%s>", clusterName)
 	}
 
+	// Logical clusters
+
+	if len(c.chi.Spec.Configuration.LogicalClusters) > 0 {
+		util.Iline(b, 8, "<!-- Logical clusters -->")
+		for i := range c.chi.Spec.Configuration.LogicalClusters {
+			c.getRemoteServersLogicalCluster(&c.chi.Spec.Configuration.LogicalClusters[i], options, b)
+		}
+	}
+
 	// 		</remote_servers>
 	// </yandex>
 	util.Iline(b, 0, "    </remote_servers>")
@@ -448,6 +554,146 @@ func (c *ClickHouseConfigGenerator) GetRemoteServers(options *RemoteServersGener
 	return b.String()
 }
 
+// logicalClusterShard is a group of hosts which forms one shard of a logical cluster
+type logicalClusterShard struct {
+	internalReplication *api.StringBool
+	hosts               []*api.ChiHost
+}
+
+// getLogicalClusterShards groups hosts selected into logical cluster into shards according to the layout
+func (c *ClickHouseConfigGenerator) getLogicalClusterShards(
+	logicalCluster *api.ChiLogicalCluster,
+	options *RemoteServersGeneratorOptions,
+) (shards []*logicalClusterShard) {
+	include := func(host *api.ChiHost) bool {
+		return logicalCluster.IncludesHost(host) && options.Include(host)
+	}
+
+	switch logicalCluster.Layout {
+	case api.LogicalClusterLayoutShards:
+		c.chi.WalkClusters(func(cluster *api.Cluster) error {
+			if !logicalCluster.IncludesCluster(cluster.Name) {
+				return nil
+			}
+			cluster.WalkShards(func(index int, shard *api.ChiShard) error {
+				internalReplication := shard.InternalReplication
+				if logicalCluster.InternalReplication.HasValue() {
+					internalReplication = logicalCluster.InternalReplication
+				}
+				group := &logicalClusterShard{internalReplication: internalReplication}
+				shard.WalkHosts(func(host *api.ChiHost) error {
+					if include(host) {
+						group.hosts = append(group.hosts, host)
+					}
+					return nil
+				})
+				if len(group.hosts) > 0 {
+					shards = append(shards, group)
+				}
+				return nil
+			})
+			return nil
+		})
+	case api.LogicalClusterLayoutShardPerHost:
+		c.chi.WalkHosts(func(host *api.ChiHost) error {
+			if include(host) {
+				shards = append(shards, &logicalClusterShard{
+					internalReplication: logicalCluster.InternalReplication,
+					hosts:               []*api.ChiHost{host},
+				})
+			}
+			return nil
+		})
+	default:
+		group := &logicalClusterShard{internalReplication: logicalCluster.InternalReplication}
+		c.chi.WalkHosts(func(host *api.ChiHost) error {
+			if include(host) {
+				group.hosts = append(group.hosts, host)
+			}
+			return nil
+		})
+		if len(group.hosts) > 0 {
+			shards = append(shards, group)
+		}
+	}
+
+	return shards
+}
+
+// getRemoteServersLogicalCluster builds remote servers entry of the logical cluster
+func (c *ClickHouseConfigGenerator) getRemoteServersLogicalCluster(
+	logicalCluster *api.ChiLogicalCluster,
+	options *RemoteServersGeneratorOptions,
+	b *bytes.Buffer,
+) {
+	shards := c.getLogicalClusterShards(logicalCluster, options)
+	if len(shards) < 1 {
+		// Skip empty cluster
+		return
+	}
+
+	// <my_cluster_name>
+	util.Iline(b, 8, "<%s>", logicalCluster.Name)
+	for _, shard := range shards {
+		// <shard>
+		//     <internal_replication>
+		util.Iline(b, 12, "<shard>")
+		util.Iline(b, 12, "    <internal_replication>%s</internal_replication>", shard.internalReplication.CastToStringTrueFalse(true))
+		for _, host := range shard.hosts {
+			c.getRemoteServersReplica(host, options, b)
+		}
+		// </shard>
+		util.Iline(b, 12, "</shard>")
+	}
+	// </my_cluster_name>
+	util.Iline(b, 8, "</%s>", logicalCluster.Name)
+}
+
+// GetRemoteServersReplicasNum counts replicas of each cluster of remote servers, the same way system.clusters lists them
+func (c *ClickHouseConfigGenerator) GetRemoteServersReplicasNum(options *RemoteServersGeneratorOptions) map[string]int {
+	if options == nil {
+		options = defaultRemoteServersGeneratorOptions()
+	}
+
+	res := make(map[string]int)
+	c.chi.WalkClusters(func(cluster *api.Cluster) error {
+		cluster.WalkShards(func(index int, shard *api.ChiShard) error {
+			if num := c.ShardHostsNum(shard, options); num > 0 {
+				res[cluster.Name] += num + len(cluster.RemoteReplicas)
+			}
+			return nil
+		})
+		return nil
+	})
+	if num := c.CHIHostsNum(options); num > 0 {
+		res[OneShardAllReplicasClusterName] = num
+		res[AllShardsOneReplicaClusterName] = num
+	}
+	for i := range c.chi.Spec.Configuration.LogicalClusters {
+		logicalCluster := &c.chi.Spec.Configuration.LogicalClusters[i]
+		for _, shard := range c.getLogicalClusterShards(logicalCluster, options) {
+			res[logicalCluster.Name] += len(shard.hosts)
+		}
+	}
+
+	return res
+}
+
+// IsReservedMacroName checks whether macro is generated by the operator and thus can not be specified by user
+func IsReservedMacroName(name string) bool {
+	switch name {
+	case
+		"installation",
+		"cluster",
+		"shard",
+		"replica",
+		ZookeeperPathMacroName,
+		AllShardsOneReplicaClusterName + "-shard":
+		return true
+	}
+	return false
+}
+
 // GetHostMacros creates "macros.xml" content
 func (c *ClickHouseConfigGenerator) GetHostMacros(host *api.ChiHost) string {
 	b := &bytes.Buffer{}
@@ -478,6 +724,18 @@ func (c *ClickHouseConfigGenerator) GetHostMacros(host *api.ChiHost) string {
 	// full deployment id is unique to identify replica within the cluster
 	util.Iline(b, 8, "<replica>%s</replica>", CreatePodHostname(host))
 
+	// <zookeeper_path></zookeeper_path> macro
+	if path := c.getMacrosZookeeperPath(host); path != "" {
+		util.Iline(b, 8, "<%s>%s</%[1]s>", ZookeeperPathMacroName, path)
+	}
+
+	// User-defined macros inherited from CHI, cluster, shard and replica
+	names := host.Macros.Names()
+	sort.Strings(names)
+	for _, name := range names {
+		util.Iline(b, 8, "<%s>%s</%[1]s>", name, host.Macros.Get(name).ScalarString())
+	}
+
 	// 		</macros>
 	// </yandex>
 	util.Iline(b, 0, "    </macros>")
@@ -486,6 +744,24 @@ func (c *ClickHouseConfigGenerator) GetHostMacros(host *api.ChiHost) string {
 	return b.String()
 }
 
+// GetHostMacrosValues gets macros of the host the same as in "macros.xml", as macro name to substitution map
+func (c *ClickHouseConfigGenerator) GetHostMacrosValues(host *api.ChiHost) map[string]string {
+	res := map[string]string{
+		"installation": host.Runtime.Address.CHIName,
+		AllShardsOneReplicaClusterName + "-shard": strconv.Itoa(host.Runtime.Address.CHIScopeIndex),
+		"cluster": host.Runtime.Address.ClusterName,
+		"shard":   host.Runtime.Address.ShardName,
+		"replica": CreatePodHostname(host),
+	}
+	if path := c.getMacrosZookeeperPath(host); path != "" {
+		res[ZookeeperPathMacroName] = path
+	}
+	for _, name := range host.Macros.Names() {
+		res[name] = host.Macros.Get(name).ScalarString()
+	}
+	return res
+}
+
 // GetHostHostnameAndPorts creates "ports.xml" content
 func (c *ClickHouseConfigGenerator) GetHostHostnameAndPorts(host *api.ChiHost) string {
 
@@ -497,13 +773,14 @@ func (c *ClickHouseConfigGenerator) GetHostHostnameAndPorts(host *api.ChiHost) s
 	if host.TCPPort != ChDefaultTCPPortNumber {
 		util.Iline(b, 4, "<tcp_port>%d</tcp_port>", host.TCPPort)
 	}
-	if host.TLSPort != ChDefaultTLSPortNumber {
+	// Secure ports are not opened by default config of ClickHouse, so secure host has to open them explicitly
+	if (host.TLSPort != ChDefaultTLSPortNumber) || (host.IsSecure() && api.IsPortAssigned(host.TLSPort)) {
 		util.Iline(b, 4, "<tcp_port_secure>%d</tcp_port_secure>", host.TLSPort)
 	}
 	if host.HTTPPort != ChDefaultHTTPPortNumber {
 		util.Iline(b, 4, "<http_port>%d</http_port>", host.HTTPPort)
 	}
-	if host.HTTPSPort != ChDefaultHTTPSPortNumber {
+	if (host.HTTPSPort != ChDefaultHTTPSPortNumber) || (host.IsSecure() && api.IsPortAssigned(host.HTTPSPort)) {
 		util.Iline(b, 4, "<https_port>%d</https_port>", host.HTTPSPort)
 	}
 
@@ -512,6 +789,9 @@ func (c *ClickHouseConfigGenerator) GetHostHostnameAndPorts(host *api.ChiHost) s
 	if host.InterserverHTTPPort != ChDefaultInterserverHTTPPortNumber {
 		util.Iline(b, 4, "<interserver_http_port>%d</interserver_http_port>", host.InterserverHTTPPort)
 	}
+	if cluster := host.GetCluster(); (cluster != nil) && cluster.TLS.IsManaged() {
+		c.getHostManagedTLS(b)
+	}
 
 	// </yandex>
 	util.Iline(b, 0, "</"+xmlTagYandex+">")
@@ -519,6 +799,304 @@ func (c *ClickHouseConfigGenerator) GetHostHostnameAndPorts(host *api.ChiHost) s
 	return b.String()
 }
 
+// getHostManagedTLS writes interserver TLS config with certificates managed by the operator.
+// ClickHouse reloads certificates on SYSTEM RELOAD CONFIG, so rotation does not need a restart
+func (c *ClickHouseConfigGenerator) getHostManagedTLS(b *bytes.Buffer) {
+	// <interserver_https_port>9010</interserver_https_port>
+	// <openSSL>
+	//     <server>
+	//         <certificateFile>/etc/clickhouse-server/tls/tls.crt</certificateFile>
+	//         <privateKeyFile>/etc/clickhouse-server/tls/tls.key</privateKeyFile>
+	//         <caConfig>/etc/clickhouse-server/tls/ca.crt</caConfig>
+	//         <verificationMode>relaxed</verificationMode>
+	//     </server>
+	//     <client>
+	//         <caConfig>/etc/clickhouse-server/tls/ca.crt</caConfig>
+	//         <verificationMode>relaxed</verificationMode>
+	//     </client>
+	// </openSSL>
+	util.Iline(b, 4, "<interserver_https_port>%d</interserver_https_port>", ChDefaultInterserverHTTPSPortNumber)
+	util.Iline(b, 4, "<openSSL>")
+	util.Iline(b, 8, "<server>")
+	util.Iline(b, 12, "<certificateFile>%s</certificateFile>", DirPathTLS+TLSCertificateFile)
+	util.Iline(b, 12, "<privateKeyFile>%s</privateKeyFile>", DirPathTLS+TLSPrivateKeyFile)
+	util.Iline(b, 12, "<caConfig>%s</caConfig>", DirPathTLS+TLSCAFile)
+	util.Iline(b, 12, "<verificationMode>relaxed</verificationMode>")
+	util.Iline(b, 8, "</server>")
+	util.Iline(b, 8, "<client>")
+	util.Iline(b, 12, "<caConfig>%s</caConfig>", DirPathTLS+TLSCAFile)
+	util.Iline(b, 12, "<verificationMode>relaxed</verificationMode>")
+	util.Iline(b, 8, "</client>")
+	util.Iline(b, 4, "</openSSL>")
+}
+
+// autoTuningSetting specifies how setting value is derived from the number of CPUs
+type autoTuningSetting struct {
+	name   string
+	perCPU int
+	min    int
+}
+
+// value calculates setting value for specified number of CPUs
+func (s autoTuningSetting) value(cpus int) int {
+	if value := s.perCPU * cpus; value > s.min {
+		return value
+	}
+	return s.min
+}
+
+// autoTuningServerSettings lists server settings derived from the number of CPUs of the host
+var autoTuningServerSettings = []autoTuningSetting{
+	{name: "background_pool_size", perCPU: 2, min: 2},
+	{name: "background_move_pool_size", perCPU: 1, min: 2},
+	{name: "background_fetches_pool_size", perCPU: 1, min: 2},
+	{name: "background_common_pool_size", perCPU: 1, min: 2},
+	{name: "background_schedule_pool_size", perCPU: 8, min: 16},
+	{name: "background_distributed_schedule_pool_size", perCPU: 2, min: 4},
+}
+
+// autoTuningProfileSettings lists default profile settings derived from the number of CPUs of the smallest host
+var autoTuningProfileSettings = []autoTuningSetting{
+	{name: "max_threads", perCPU: 1, min: 1},
+}
+
+// GetHostAutoTuning creates "auto-tuning.xml" content with server settings derived from the host's CPUs
+func (c *ClickHouseConfigGenerator) GetHostAutoTuning(host *api.ChiHost) string {
+	if !c.chi.Spec.Defaults.AutoTuning.IsTrue() {
+		return ""
+	}
+
+	cpus := HostGetCPUs(host)
+	if cpus < 1 {
+		// No CPUs specified, nothing to tune on
+		return ""
+	}
+
+	b := &bytes.Buffer{}
+	// <yandex>
+	util.Iline(b, 0, "<"+xmlTagYandex+">")
+	for _, setting := range autoTuningServerSettings {
+		util.Iline(b, 4, "<%s>%d</%[1]s>", setting.name, setting.value(cpus))
+	}
+	// </yandex>
+	util.Iline(b, 0, "</"+xmlTagYandex+">")
+
+	return b.String()
+}
+
+// GetAutoTuningProfile creates "auto-tuning.xml" content with default profile settings derived from the CPUs
+// of the smallest host, since profiles are shared by all hosts
+func (c *ClickHouseConfigGenerator) GetAutoTuningProfile() string {
+	if !c.chi.Spec.Defaults.AutoTuning.IsTrue() {
+		return ""
+	}
+
+	cpus := 0
+	c.chi.WalkHosts(func(host *api.ChiHost) error {
+		if hostCPUs := HostGetCPUs(host); (hostCPUs > 0) && ((cpus == 0) || (hostCPUs < cpus)) {
+			cpus = hostCPUs
+		}
+		return nil
+	})
+	if cpus < 1 {
+		// No CPUs specified, nothing to tune on
+		return ""
+	}
+
+	b := &bytes.Buffer{}
+	// <yandex>
+	//     <profiles>
+	//         <default>
+	util.Iline(b, 0, "<"+xmlTagYandex+">")
+	util.Iline(b, 4, "<profiles>")
+	util.Iline(b, 8, "<default>")
+	for _, setting := range autoTuningProfileSettings {
+		util.Iline(b, 12, "<%s>%d</%[1]s>", setting.name, setting.value(cpus))
+	}
+	//         </default>
+	//     </profiles>
+	// </yandex>
+	util.Iline(b, 8, "</default>")
+	util.Iline(b, 4, "</profiles>")
+	util.Iline(b, 0, "</"+xmlTagYandex+">")
+
+	return b.String()
+}
+
+// writeReliabilityProfileSettings maps default profile settings to ENV vars carrying cluster-specific values
+var writeReliabilityProfileSettings = []struct {
+	name string
+	env  string
+}{
+	{name: "insert_quorum", env: InsertQuorumEnvName},
+	{name: "insert_quorum_timeout", env: InsertQuorumTimeoutEnvName},
+	{name: "select_sequential_consistency", env: SelectSequentialConsistencyEnvName},
+}
+
+// GetWriteReliabilityProfile creates "write-reliability.xml" content with default profile settings.
+// Profiles are shared by all hosts, so values differing between clusters are taken from ENV vars of the host
+func (c *ClickHouseConfigGenerator) GetWriteReliabilityProfile() string {
+	if !c.chi.HasWriteReliability() {
+		return ""
+	}
+
+	b := &bytes.Buffer{}
+	// <yandex>
+	//     <profiles>
+	//         <default>
+	util.Iline(b, 0, "<"+xmlTagYandex+">")
+	util.Iline(b, 4, "<profiles>")
+	util.Iline(b, 8, "<default>")
+	for _, setting := range writeReliabilityProfileSettings {
+		if c.chi.Spec.Configuration.Profiles.Has("default/" + setting.name) {
+			// Explicitly specified profile settings take precedence
+			continue
+		}
+		util.Iline(b, 12, `<%s from_env="%s" />`, setting.name, setting.env)
+	}
+	//         </default>
+	//     </profiles>
+	// </yandex>
+	util.Iline(b, 8, "</default>")
+	util.Iline(b, 4, "</profiles>")
+	util.Iline(b, 0, "</"+xmlTagYandex+">")
+
+	return b.String()
+}
+
+// replicatedDatabaseProfileSettings lists default profile settings required to create databases of Replicated engine
+var replicatedDatabaseProfileSettings = []managedProfileSetting{
+	{name: "allow_experimental_database_replicated", value: "1"},
+}
+
+// GetReplicatedDatabaseProfile creates "replicated-database.xml" content with default profile settings
+// required by replicated databases of the clusters
+func (c *ClickHouseConfigGenerator) GetReplicatedDatabaseProfile() string {
+	if !c.chi.HasReplicatedDatabases() {
+		return ""
+	}
+
+	b := &bytes.Buffer{}
+	// <yandex>
+	//     <profiles>
+	//         <default>
+	util.Iline(b, 0, "<"+xmlTagYandex+">")
+	util.Iline(b, 4, "<profiles>")
+	util.Iline(b, 8, "<default>")
+	for _, setting := range replicatedDatabaseProfileSettings {
+		if c.chi.Spec.Configuration.Profiles.Has("default/" + setting.name) {
+			// Explicitly specified profile settings take precedence
+			continue
+		}
+		util.Iline(b, 12, "<%s>%s</%[1]s>", setting.name, setting.value)
+	}
+	//         </default>
+	//     </profiles>
+	// </yandex>
+	util.Iline(b, 8, "</default>")
+	util.Iline(b, 4, "</profiles>")
+	util.Iline(b, 0, "</"+xmlTagYandex+">")
+
+	return b.String()
+}
+
+// GetLogging creates "logging.xml" content, which makes ClickHouse write structured logs for the log shipping agent
+func (c *ClickHouseConfigGenerator) GetLogging() string {
+	if !c.chi.Spec.Logging.IsStructured() {
+		return ""
+	}
+
+	b := &bytes.Buffer{}
+	// <yandex>
+	//     <logger>
+	//         <formatting>
+	//             <type>json</type>
+	//         </formatting>
+	//     </logger>
+	// </yandex>
+	util.Iline(b, 0, "<"+xmlTagYandex+">")
+	util.Iline(b, 4, "<logger>")
+	util.Iline(b, 8, "<formatting>")
+	util.Iline(b, 12, "<type>json</type>")
+	util.Iline(b, 8, "</formatting>")
+	util.Iline(b, 4, "</logger>")
+	util.Iline(b, 0, "</"+xmlTagYandex+">")
+
+	return b.String()
+}
+
+// systemLogTables lists system log tables enabled in ClickHouse by default.
+// Tables disabled by default are not listed, since mentioning them in config would enable them.
+var systemLogTables = []string{
+	"query_log",
+	"query_thread_log",
+	"query_views_log",
+	"part_log",
+	"trace_log",
+	"metric_log",
+	"asynchronous_metric_log",
+	"opentelemetry_span_log",
+	"processors_profile_log",
+	"asynchronous_insert_log",
+}
+
+// GetHostSystemLogs creates "system-logs.xml" content, which places system log tables
+// on a dedicated disk backed by the system log volume claim template
+func (c *ClickHouseConfigGenerator) GetHostSystemLogs(host *api.ChiHost) string {
+	if !host.Templates.HasSystemLogVolumeClaimTemplate() {
+		return ""
+	}
+
+	b := &bytes.Buffer{}
+	// <yandex>
+	//     <storage_configuration>
+	//         <disks>
+	//             <system_logs>
+	//                 <path>/var/lib/clickhouse-system-logs/</path>
+	//             </system_logs>
+	//         </disks>
+	//         <policies>
+	//             <system_logs>
+	//                 <volumes>
+	//                     <main>
+	//                         <disk>system_logs</disk>
+	//                     </main>
+	//                 </volumes>
+	//             </system_logs>
+	//         </policies>
+	//     </storage_configuration>
+	//     <query_log>
+	//         <storage_policy>system_logs</storage_policy>
+	//     </query_log>
+	//     ...
+	// </yandex>
+	util.Iline(b, 0, "<"+xmlTagYandex+">")
+	util.Iline(b, 4, "<storage_configuration>")
+	util.Iline(b, 8, "<disks>")
+	util.Iline(b, 12, "<%s>", systemLogsStoragePolicy)
+	util.Iline(b, 16, "<path>%s/</path>", DirPathClickHouseSystemLogs)
+	util.Iline(b, 12, "</%s>", systemLogsStoragePolicy)
+	util.Iline(b, 8, "</disks>")
+	util.Iline(b, 8, "<policies>")
+	util.Iline(b, 12, "<%s>", systemLogsStoragePolicy)
+	util.Iline(b, 16, "<volumes>")
+	util.Iline(b, 20, "<main>")
+	util.Iline(b, 24, "<disk>%s</disk>", systemLogsStoragePolicy)
+	util.Iline(b, 20, "</main>")
+	util.Iline(b, 16, "</volumes>")
+	util.Iline(b, 12, "</%s>", systemLogsStoragePolicy)
+	util.Iline(b, 8, "</policies>")
+	util.Iline(b, 4, "</storage_configuration>")
+	for _, table := range systemLogTables {
+		util.Iline(b, 4, "<%s>", table)
+		util.Iline(b, 8, "<storage_policy>%s</storage_policy>", systemLogsStoragePolicy)
+		util.Iline(b, 4, "</%s>", table)
+	}
+	util.Iline(b, 0, "</"+xmlTagYandex+">")
+
+	return b.String()
+}
+
 // generateXMLConfig creates XML using map[string]string definitions
 func (c *ClickHouseConfigGenerator) generateXMLConfig(settings *api.Settings, prefix string) string {
 	if settings.Len() == 0 {
@@ -546,8 +1124,11 @@ func (c *ClickHouseConfigGenerator) getDistributedDDLPath() string {
 }
 
 // getRemoteServersReplicaHostname returns hostname (podhostname + service or FQDN) for "remote_servers.xml"
-// based on .Spec.Defaults.ReplicasUseFQDN
+// based on .Spec.Defaults.ReplicasUseFQDN. Hosts in host network are addressed by their nodes, once nodes are known
 func (c *ClickHouseConfigGenerator) getRemoteServersReplicaHostname(host *api.ChiHost) string {
+	if host.IsHostNetwork() && (host.Runtime.NodeAddress != "") {
+		return host.Runtime.NodeAddress
+	}
 	return CreateInstanceHostname(host)
 }
 
@@ -564,6 +1145,21 @@ func (c *ClickHouseConfigGenerator) getMacrosInstallation(name string) string {
 	return util.CreateStringID(name, 6)
 }
 
+// getMacrosZookeeperPath expands ZooKeeper path template of the host's cluster with the host's macros.
+// Macros unknown to the operator, such as {database}, {table} or {uuid}, are left for ClickHouse to expand.
+// Empty string is returned in case the cluster has no template, so no macro is rendered
+func (c *ClickHouseConfigGenerator) getMacrosZookeeperPath(host *api.ChiHost) string {
+	cluster := host.GetCluster()
+	if (cluster == nil) || (cluster.ZookeeperPathTemplate == "") {
+		return ""
+	}
+	return strings.NewReplacer(
+		"{installation}", host.Runtime.Address.CHIName,
+		"{cluster}", host.Runtime.Address.ClusterName,
+		"{shard}", host.Runtime.Address.ShardName,
+	).Replace(cluster.ZookeeperPathTemplate)
+}
+
 // getMacrosCluster returns macros value for <cluster-name> macros
 func (c *ClickHouseConfigGenerator) getMacrosCluster(name string) string {
 	return util.CreateStringID(name, 4)
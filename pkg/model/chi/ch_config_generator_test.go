@@ -0,0 +1,528 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	model "github.com/altinity/clickhouse-operator/pkg/model/chi"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/builder"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/normalizer"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/render"
+)
+
+func TestMain(m *testing.M) {
+	render.Init(filepath.Join("..", "..", "..", "config", "config.yaml"))
+	os.Exit(m.Run())
+}
+
+// normalize normalizes CHI the same way the operator does before generating configuration
+func normalize(t *testing.T, chi *api.ClickHouseInstallation) *api.ClickHouseInstallation {
+	t.Helper()
+	normalized, err := normalizer.NewNormalizer(render.NoSecrets).CreateTemplatedCHI(chi, normalizer.NewOptions())
+	if err != nil {
+		t.Fatalf("unable to normalize err: %v", err)
+	}
+	return normalized
+}
+
+func TestGetAutoTuning(t *testing.T) {
+	cpus := func(limit, request string) core.ResourceRequirements {
+		resources := core.ResourceRequirements{}
+		if limit != "" {
+			resources.Limits = core.ResourceList{core.ResourceCPU: resource.MustParse(limit)}
+		}
+		if request != "" {
+			resources.Requests = core.ResourceList{core.ResourceCPU: resource.MustParse(request)}
+		}
+		return resources
+	}
+
+	tests := []struct {
+		name       string
+		autoTuning bool
+		options    []builder.ClusterOption
+		// host and profile list expected settings, nil means no auto-tuning config is generated
+		host    []string
+		profile []string
+	}{
+		{
+			name:       "disabled",
+			autoTuning: false,
+			options:    []builder.ClusterOption{builder.WithResources(cpus("4", ""))},
+		},
+		{
+			name:       "unset resources",
+			autoTuning: true,
+		},
+		{
+			name:       "zero resources",
+			autoTuning: true,
+			options:    []builder.ClusterOption{builder.WithResources(cpus("0", "0"))},
+		},
+		{
+			name:       "limit",
+			autoTuning: true,
+			options:    []builder.ClusterOption{builder.WithResources(cpus("4", "1"))},
+			host: []string{
+				"<background_pool_size>8</background_pool_size>",
+				"<background_move_pool_size>4</background_move_pool_size>",
+				"<background_fetches_pool_size>4</background_fetches_pool_size>",
+				"<background_common_pool_size>4</background_common_pool_size>",
+				"<background_schedule_pool_size>32</background_schedule_pool_size>",
+				"<background_distributed_schedule_pool_size>8</background_distributed_schedule_pool_size>",
+			},
+			profile: []string{"<max_threads>4</max_threads>"},
+		},
+		{
+			name:       "fractional request",
+			autoTuning: true,
+			options:    []builder.ClusterOption{builder.WithResources(cpus("", "1500m"))},
+			host:       []string{"<background_pool_size>4</background_pool_size>"},
+			profile:    []string{"<max_threads>2</max_threads>"},
+		},
+		{
+			name:       "minimums",
+			autoTuning: true,
+			options:    []builder.ClusterOption{builder.WithResources(cpus("1", ""))},
+			host: []string{
+				"<background_pool_size>2</background_pool_size>",
+				"<background_move_pool_size>2</background_move_pool_size>",
+				"<background_schedule_pool_size>16</background_schedule_pool_size>",
+				"<background_distributed_schedule_pool_size>4</background_distributed_schedule_pool_size>",
+			},
+			profile: []string{"<max_threads>1</max_threads>"},
+		},
+		{
+			name:       "smallest host",
+			autoTuning: true,
+			options: []builder.ClusterOption{
+				builder.WithReplicas(2),
+				builder.WithResources(cpus("8", "")),
+				builder.WithHostResources(0, 0, cpus("2", "")),
+			},
+			host:    []string{"<background_pool_size>4</background_pool_size>"},
+			profile: []string{"<max_threads>2</max_threads>"},
+		},
+	}
+	for _, test := range tests {
+		input := builder.NewCHI("test", "tuning", builder.WithCluster(builder.NewCluster("main", test.options...)))
+		input.Spec.Defaults = api.NewChiDefaults()
+		input.Spec.Defaults.AutoTuning = api.NewStringBool(test.autoTuning)
+		chi := normalize(t, input)
+		generator := model.NewClickHouseConfigGenerator(chi)
+
+		for _, check := range []struct {
+			kind     string
+			content  string
+			settings []string
+		}{
+			{kind: "host", content: generator.GetHostAutoTuning(chi.FirstHost()), settings: test.host},
+			{kind: "profile", content: generator.GetAutoTuningProfile(), settings: test.profile},
+		} {
+			if (check.content != "") != (check.settings != nil) {
+				t.Errorf("%s: %s auto-tuning generated %v want %v:\n%s", test.name, check.kind, check.content != "", check.settings != nil, check.content)
+			}
+			for _, setting := range check.settings {
+				if !strings.Contains(check.content, setting) {
+					t.Errorf("%s: %s auto-tuning does not contain %q:\n%s", test.name, check.kind, setting, check.content)
+				}
+			}
+		}
+	}
+}
+
+// getRemoteServersCluster gets section of the cluster in remote servers
+func getRemoteServersCluster(t *testing.T, remoteServers, cluster string) string {
+	t.Helper()
+	start, end := strings.Index(remoteServers, "<"+cluster+">"), strings.Index(remoteServers, "</"+cluster+">")
+	if (start < 0) || (end < start) {
+		t.Fatalf("cluster %s is not found in remote servers:\n%s", cluster, remoteServers)
+	}
+	return remoteServers[start:end]
+}
+
+func TestGetRemoteServersReadTier(t *testing.T) {
+	cluster := builder.NewCluster("main",
+		builder.WithShards(2),
+		builder.WithReplicas(2),
+		builder.WithReadTierReplicas(1),
+	)
+	chi := normalize(t, builder.NewCHI("test", "tiers", builder.WithCluster(cluster)))
+
+	// Read tier replicas are deprioritized, so distributed queries prefer the other ones
+	main := getRemoteServersCluster(t, model.NewClickHouseConfigGenerator(chi).GetRemoteServers(nil), "main")
+	if got := strings.Count(main, "<priority>"); got != 2 {
+		t.Errorf("got %d prioritized replicas want 2:\n%s", got, main)
+	}
+}
+
+func TestGetRemoteServersReplicaPriority(t *testing.T) {
+	cluster := builder.NewCluster("main",
+		builder.WithShards(2),
+		builder.WithReplicas(3),
+		builder.WithReadTierReplicas(1),
+		builder.WithReplicaPriority(2, 10),
+	)
+	chi := normalize(t, builder.NewCHI("test", "priority", builder.WithCluster(cluster)))
+
+	// Explicit priority overrides the read tier default
+	main := getRemoteServersCluster(t, model.NewClickHouseConfigGenerator(chi).GetRemoteServers(nil), "main")
+	if got := strings.Count(main, "<priority>10</priority>"); got != 2 {
+		t.Errorf("got %d replicas with explicit priority want 2:\n%s", got, main)
+	}
+	if got := strings.Count(main, "<priority>"); got != 4 {
+		t.Errorf("got %d prioritized replicas want 4:\n%s", got, main)
+	}
+}
+
+func TestGetRemoteServersLevelPorts(t *testing.T) {
+	cluster := builder.NewCluster("main", builder.WithShards(2), builder.WithReplicas(2))
+	cluster.TCPPort = 9100
+	cluster.Layout.Replicas = []api.ChiReplica{
+		{},
+		{HostPorts: api.HostPorts{TCPPort: 9200, HTTPPort: 8200}},
+	}
+	chi := normalize(t, builder.NewCHI("test", "ports", builder.WithCluster(cluster)))
+
+	main := getRemoteServersCluster(t, model.NewClickHouseConfigGenerator(chi).GetRemoteServers(nil), "main")
+	for _, port := range []string{"<port>9100</port>", "<port>9200</port>"} {
+		if got := strings.Count(main, port); got != 2 {
+			t.Errorf("got %d replicas with %s want 2:\n%s", got, port, main)
+		}
+	}
+}
+
+func TestGetRemoteServersLogicalClusters(t *testing.T) {
+	chi := normalize(t, builder.NewCHI("test", "logical",
+		builder.WithCluster(builder.NewCluster("main", builder.WithShards(2), builder.WithReplicas(2))),
+		builder.WithLogicalClusters(
+			api.ChiLogicalCluster{
+				Name:     "all-replicas-flat",
+				Layout:   api.LogicalClusterLayoutShardPerHost,
+				Clusters: []string{"main"},
+			},
+			api.ChiLogicalCluster{
+				Name:   "first-shard",
+				Shards: []string{"0"},
+			},
+			api.ChiLogicalCluster{
+				Name:     "dangling",
+				Clusters: []string{"unknown"},
+			},
+		),
+	))
+	remoteServers := model.NewClickHouseConfigGenerator(chi).GetRemoteServers(nil)
+
+	flat := getRemoteServersCluster(t, remoteServers, "all-replicas-flat")
+	if got := strings.Count(flat, "<shard>"); got != 4 {
+		t.Errorf("all-replicas-flat: got %d shards want 4", got)
+	}
+	if !strings.Contains(flat, "<internal_replication>false</internal_replication>") {
+		t.Errorf("all-replicas-flat: internal replication is expected to be off")
+	}
+	first := getRemoteServersCluster(t, remoteServers, "first-shard")
+	if got := strings.Count(first, "<shard>"); got != 1 {
+		t.Errorf("first-shard: got %d shards want 1", got)
+	}
+	if got := strings.Count(first, "<replica>"); got != 2 {
+		t.Errorf("first-shard: got %d replicas want 2", got)
+	}
+	if strings.Contains(first, "chi-logical-main-1-") {
+		t.Errorf("first-shard: hosts of other shards are included")
+	}
+	if strings.Contains(remoteServers, "<dangling>") {
+		t.Errorf("logical cluster over unknown cluster is generated")
+	}
+}
+
+func TestGetRemoteServersRemoteReplicas(t *testing.T) {
+	cluster := builder.NewCluster("main", builder.WithShards(2))
+	cluster.RemoteReplicas = []api.ChiRemoteReplica{
+		{
+			Name:   "eu",
+			Region: "eu-west-1",
+			Hosts:  []string{"ch-0.eu.example.com", "ch-1.eu.example.com"},
+		},
+		{
+			Name: "dr",
+			CHI: &api.ChiRemoteReplicaCHI{
+				Name:                   "dr",
+				Namespace:              "backup",
+				NamespaceDomainPattern: "%s.svc.clusterset.local",
+			},
+		},
+	}
+	chi := normalize(t, builder.NewCHI("test", "wan", builder.WithCluster(cluster)))
+
+	main := getRemoteServersCluster(t, model.NewClickHouseConfigGenerator(chi).GetRemoteServers(nil), "main")
+	for _, want := range []string{
+		"<host>ch-1.eu.example.com</host>",
+		"<host>chi-dr-main-1-0.backup.svc.clusterset.local</host>",
+		"<host>chi-wan-main-0-0.test.svc.cluster.local</host>",
+		"<port>9440</port>",
+		"<compression>true</compression>",
+		"<internal_replication>True</internal_replication>",
+	} {
+		if !strings.Contains(main, want) {
+			t.Errorf("remote servers do not contain %q:\n%s", want, main)
+		}
+	}
+	// Remote replicas are deprioritized, so local replicas are preferred
+	if got := strings.Count(main, "<priority>"); got != 4 {
+		t.Errorf("got %d prioritized replicas want 4", got)
+	}
+}
+
+func TestGetWriteReliabilityProfile(t *testing.T) {
+	replicated := builder.NewCluster("main", builder.WithShards(2), builder.WithReplicas(3))
+	replicated.WriteReliability = &api.ChiWriteReliability{
+		SelectSequentialConsistency: api.NewStringBool(true),
+	}
+	chi := normalize(t, builder.NewCHI("test", "quorum",
+		builder.WithCluster(replicated),
+		builder.WithCluster(builder.NewCluster("adhoc")),
+	))
+
+	// Profiles are shared by all hosts, so values of the cluster are taken from ENV vars of the host
+	profile := model.NewClickHouseConfigGenerator(chi).GetWriteReliabilityProfile()
+	if !strings.Contains(profile, `<insert_quorum from_env="CLICKHOUSE_INSERT_QUORUM" />`) {
+		t.Errorf("default profile does not take insert quorum from ENV:\n%s", profile)
+	}
+}
+
+func TestGetHostMacros(t *testing.T) {
+	cluster := builder.NewCluster("main", builder.WithReplicas(2))
+	cluster.Layout.Replicas = []api.ChiReplica{
+		{},
+		{Macros: api.NewSettings().SetScalarsFromMap(map[string]string{"datacenter": "dc2"})},
+	}
+	input := builder.NewCHI("test", "macros", builder.WithCluster(cluster))
+	input.Spec.Configuration.Macros = api.NewSettings().SetScalarsFromMap(map[string]string{
+		"datacenter": "dc1",
+		"layer":      "l0",
+	})
+	chi := normalize(t, input)
+	generator := model.NewClickHouseConfigGenerator(chi)
+
+	// Macros of the replica override macros of the CHI
+	want := map[string]string{
+		"0-0": "<datacenter>dc1</datacenter>",
+		"0-1": "<datacenter>dc2</datacenter>",
+	}
+	chi.WalkHosts(func(host *api.ChiHost) error {
+		macros := generator.GetHostMacros(host)
+		for _, expected := range []string{want[host.GetName()], "<layer>l0</layer>"} {
+			if !strings.Contains(macros, expected) {
+				t.Errorf("host %s: macros do not contain %q:\n%s", host.GetName(), expected, macros)
+			}
+		}
+		return nil
+	})
+}
+
+func TestGetHostMacrosZookeeperPath(t *testing.T) {
+	custom := builder.NewCluster("custom")
+	custom.ZookeeperPathTemplate = "/tables/{installation}/{cluster}/{shard}/{database}"
+	chi := normalize(t, builder.NewCHI("test", "zk", builder.WithCluster(builder.NewCluster("main")), builder.WithCluster(custom)))
+	generator := model.NewClickHouseConfigGenerator(chi)
+
+	// Cluster without template has no macro at all
+	if macros := generator.GetHostMacros(chi.FindCluster("main").FirstHost()); strings.Contains(macros, "<zookeeper_path>") {
+		t.Errorf("unexpected zookeeper_path macro:\n%s", macros)
+	}
+	want := "<zookeeper_path>/tables/zk/custom/0/{database}</zookeeper_path>"
+	if macros := generator.GetHostMacros(chi.FindCluster("custom").FirstHost()); !strings.Contains(macros, want) {
+		t.Errorf("macros do not contain %q:\n%s", want, macros)
+	}
+}
+
+func TestGetHostSystemLogs(t *testing.T) {
+	chi := normalize(t, builder.NewCHI("test", "system-logs",
+		builder.WithVolumeClaimTemplates(
+			builder.NewVolumeClaimTemplate("data", resource.MustParse("100Gi")),
+			builder.NewVolumeClaimTemplate("system-logs", resource.MustParse("10Gi")),
+		),
+		builder.WithCluster(builder.NewCluster("main",
+			builder.WithDataVolumeClaimTemplate("data"),
+			builder.WithSystemLogVolumeClaimTemplate("system-logs"),
+		)),
+	))
+
+	config := model.NewClickHouseConfigGenerator(chi).GetHostSystemLogs(chi.FirstHost())
+	for _, expected := range []string{
+		"<path>" + model.DirPathClickHouseSystemLogs + "/</path>",
+		"<disk>system_logs</disk>",
+		"<query_log>",
+		"<trace_log>",
+		"<storage_policy>system_logs</storage_policy>",
+	} {
+		if !strings.Contains(config, expected) {
+			t.Errorf("system logs config does not contain %s:\n%s", expected, config)
+		}
+	}
+}
+
+func TestGetProfilesAndQuotas(t *testing.T) {
+	input := builder.NewCHI("test", "limits", builder.WithCluster(builder.NewCluster("main")))
+	input.Spec.Configuration.Profiles = api.NewSettings().
+		Set("reporting/max_memory_usage", api.NewSettingScalar("10000000000")).
+		Set("reporting/readonly", api.NewSettingScalar("1"))
+	input.Spec.Configuration.Quotas = api.NewSettings().
+		Set("reporting/interval/duration", api.NewSettingScalar("3600")).
+		Set("reporting/interval/queries", api.NewSettingScalar("10000"))
+	generator := model.NewClickHouseConfigGenerator(normalize(t, input))
+
+	for section, config := range map[string]struct {
+		content  string
+		expected []string
+	}{
+		"profiles": {generator.GetProfiles(), []string{"<reporting>", "<max_memory_usage>10000000000</max_memory_usage>", "<readonly>1</readonly>"}},
+		"quotas":   {generator.GetQuotas(), []string{"<reporting>", "<duration>3600</duration>", "<queries>10000</queries>"}},
+	} {
+		for _, value := range config.expected {
+			if !strings.Contains(config.content, value) {
+				t.Errorf("%s do not contain %s:\n%s", section, value, config.content)
+			}
+		}
+	}
+}
+
+func TestGetHostZookeeper(t *testing.T) {
+	input := builder.NewCHI("test", "zk",
+		builder.WithCluster(builder.NewCluster("main", builder.WithReplicas(2))),
+		builder.WithCluster(builder.NewCluster("own", builder.WithZookeeper(api.ChiZookeeperNode{Host: "own-keeper", Port: 9181}))),
+	)
+	input.Spec.Configuration.Zookeeper = &api.ChiZookeeperConfig{
+		Nodes: []api.ChiZookeeperNode{
+			{Host: "zookeeper-0.zookeepers.zoo", Port: 2181},
+			{Host: "zookeeper-1.zookeepers.zoo", Port: 2181},
+		},
+		SessionTimeoutMs:   30000,
+		OperationTimeoutMs: 10000,
+		Root:               "/clickhouse/zk",
+	}
+	chi := normalize(t, input)
+	generator := model.NewClickHouseConfigGenerator(chi)
+
+	shared := []string{
+		"<host>zookeeper-0.zookeepers.zoo</host>",
+		"<host>zookeeper-1.zookeepers.zoo</host>",
+		"<port>2181</port>",
+		"<session_timeout_ms>30000</session_timeout_ms>",
+		"<operation_timeout_ms>10000</operation_timeout_ms>",
+		"<root>/clickhouse/zk</root>",
+	}
+	want := map[string][]string{
+		"main/0-0": shared,
+		"main/0-1": shared,
+		"own/0-0":  {"<host>own-keeper</host>", "<port>9181</port>"},
+	}
+	chi.WalkHosts(func(host *api.ChiHost) error {
+		name := host.Runtime.Address.ClusterName + "/" + host.GetName()
+		config := generator.GetHostZookeeper(host)
+		for _, value := range want[name] {
+			if !strings.Contains(config, value) {
+				t.Errorf("host %s: zookeeper does not contain %q:\n%s", name, value, config)
+			}
+		}
+		return nil
+	})
+}
+
+func TestGetSettingsGlobal(t *testing.T) {
+	input := builder.NewCHI("test", "settings", builder.WithCluster(builder.NewCluster("main")))
+	input.Spec.Configuration.Settings = api.NewSettings().
+		Set("compression/case/method", api.NewSettingScalar("zstd")).
+		Set("compression/case/min_part_size", api.NewSettingScalar("10000000000")).
+		Set("max_concurrent_queries", api.NewSettingScalar("200"))
+
+	config := model.NewClickHouseConfigGenerator(normalize(t, input)).GetSettingsGlobal()
+	settings := strings.Join(strings.Fields(config), "")
+	for _, expected := range []string{
+		"<compression><case><method>zstd</method><min_part_size>10000000000</min_part_size></case></compression>",
+		"<max_concurrent_queries>200</max_concurrent_queries>",
+	} {
+		if !strings.Contains(settings, expected) {
+			t.Errorf("settings do not contain %s:\n%s", expected, config)
+		}
+	}
+}
+
+func TestGetHostHostnameAndPortsManagedTLS(t *testing.T) {
+	cluster := builder.NewCluster("main", builder.WithReplicas(2))
+	cluster.TLS = &api.ChiClusterTLS{Managed: api.NewStringBool(true)}
+	chi := normalize(t, builder.NewCHI("test", "secure", builder.WithCluster(cluster)))
+	generator := model.NewClickHouseConfigGenerator(chi)
+
+	chi.WalkHosts(func(host *api.ChiHost) error {
+		config := generator.GetHostHostnameAndPorts(host)
+		for _, expected := range []string{
+			"<interserver_https_port>9010</interserver_https_port>",
+			"<certificateFile>" + model.DirPathTLS + model.TLSCertificateFile + "</certificateFile>",
+		} {
+			if !strings.Contains(config, expected) {
+				t.Errorf("host %s: config does not contain %s:\n%s", host.GetName(), expected, config)
+			}
+		}
+		return nil
+	})
+}
+
+func TestGetReplicatedDatabaseProfile(t *testing.T) {
+	replicated := builder.NewCluster("main", builder.WithShards(2), builder.WithReplicas(2))
+	replicated.ReplicatedDatabases = []api.ChiReplicatedDatabase{{Name: "analytics"}}
+	chi := normalize(t, builder.NewCHI("test", "replicated", builder.WithCluster(replicated)))
+
+	profile := model.NewClickHouseConfigGenerator(chi).GetReplicatedDatabaseProfile()
+	if !strings.Contains(profile, "<allow_experimental_database_replicated>1</allow_experimental_database_replicated>") {
+		t.Errorf("default profile does not allow replicated databases:\n%s", profile)
+	}
+
+	plain := normalize(t, builder.NewCHI("test", "plain", builder.WithCluster(builder.NewCluster("main"))))
+	if profile := model.NewClickHouseConfigGenerator(plain).GetReplicatedDatabaseProfile(); profile != "" {
+		t.Errorf("unexpected replicated database profile of CHI without replicated databases:\n%s", profile)
+	}
+}
+
+func TestGetSecureCluster(t *testing.T) {
+	cluster := builder.NewCluster("main", builder.WithReplicas(2))
+	cluster.Secure = api.NewStringBool(true)
+	chi := normalize(t, builder.NewCHI("test", "secure", builder.WithCluster(cluster)))
+	generator := model.NewClickHouseConfigGenerator(chi)
+
+	chi.WalkHosts(func(host *api.ChiHost) error {
+		config := generator.GetHostHostnameAndPorts(host)
+		for _, expected := range []string{"<tcp_port_secure>9440</tcp_port_secure>", "<https_port>8443</https_port>"} {
+			if !strings.Contains(config, expected) {
+				t.Errorf("host %s: config does not contain %s:\n%s", host.GetName(), expected, config)
+			}
+		}
+		return nil
+	})
+
+	// Replicas are addressed via secure port
+	remoteServers := strings.Join(strings.Fields(generator.GetRemoteServers(nil)), "")
+	if !strings.Contains(remoteServers, "<port>9440</port><secure>1</secure>") || strings.Contains(remoteServers, "<secure>0</secure>") {
+		t.Errorf("remote servers do not address replicas via secure port:\n%s", remoteServers)
+	}
+}
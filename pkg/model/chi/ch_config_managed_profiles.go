@@ -0,0 +1,98 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"bytes"
+
+	"github.com/altinity/clickhouse-operator/pkg/util"
+)
+
+// managedProfileSetting is a setting of a profile shipped with the operator
+type managedProfileSetting struct {
+	name  string
+	value string
+}
+
+// managedProfiles lists profiles shipped with the operator, which can be enabled by name in .spec.defaults.managedProfiles.
+// Settings are tested with the operator and change along with it, so best practices arrive with operator upgrades.
+var managedProfiles = map[string][]managedProfileSetting{
+	// Writes are not lost nor duplicated in case of replica or Keeper failures
+	"safe-replicated-writes": {
+		{name: "insert_deduplicate", value: "1"},
+		{name: "insert_distributed_sync", value: "1"},
+		{name: "insert_keeper_max_retries", value: "20"},
+		{name: "insert_keeper_retry_max_backoff_ms", value: "10000"},
+		{name: "async_insert", value: "0"},
+	},
+	// Long-running queries over large data sets spill to disk instead of failing
+	"heavy-analytics": {
+		{name: "max_execution_time", value: "3600"},
+		{name: "max_bytes_before_external_group_by", value: "10000000000"},
+		{name: "max_bytes_before_external_sort", value: "10000000000"},
+		{name: "distributed_aggregation_memory_efficient", value: "1"},
+		{name: "join_algorithm", value: "auto"},
+	},
+	// Queries on small hosts stay within a few gigabytes of memory
+	"low-memory": {
+		{name: "max_threads", value: "2"},
+		{name: "max_memory_usage", value: "2000000000"},
+		{name: "max_bytes_before_external_group_by", value: "1000000000"},
+		{name: "max_bytes_before_external_sort", value: "1000000000"},
+		{name: "input_format_parallel_parsing", value: "0"},
+		{name: "output_format_parallel_formatting", value: "0"},
+	},
+}
+
+// IsManagedProfile checks whether profile with specified name is shipped with the operator
+func IsManagedProfile(name string) bool {
+	_, ok := managedProfiles[name]
+	return ok
+}
+
+// GetManagedProfiles creates "managed-profiles.xml" content with profiles shipped with the operator enabled in the CHI.
+// Profiles specified in .spec.configuration.profiles are rendered into a file sorted after this one,
+// so their settings take precedence over the settings of the managed profiles of the same name.
+func (c *ClickHouseConfigGenerator) GetManagedProfiles() string {
+	if (c.chi.Spec.Defaults == nil) || (len(c.chi.Spec.Defaults.ManagedProfiles) == 0) {
+		return ""
+	}
+
+	b := &bytes.Buffer{}
+	// <yandex>
+	//     <profiles>
+	util.Iline(b, 0, "<"+xmlTagYandex+">")
+	util.Iline(b, 4, "<profiles>")
+	for _, name := range c.chi.Spec.Defaults.ManagedProfiles {
+		settings, ok := managedProfiles[name]
+		if !ok {
+			continue
+		}
+		//         <name>
+		//             <setting>value</setting>
+		//         </name>
+		util.Iline(b, 8, "<%s>", name)
+		for _, setting := range settings {
+			util.Iline(b, 12, "<%s>%s</%[1]s>", setting.name, setting.value)
+		}
+		util.Iline(b, 8, "</%s>", name)
+	}
+	//     </profiles>
+	// </yandex>
+	util.Iline(b, 4, "</profiles>")
+	util.Iline(b, 0, "</"+xmlTagYandex+">")
+
+	return b.String()
+}
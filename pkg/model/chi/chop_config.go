@@ -136,6 +136,8 @@ func isListedChangeRequiresReboot(host *api.ChiHost, paths []string) bool {
 //	        - files/*.xml: "yes"
 //	        - files/config.d/*.xml: "yes"
 //	        - files/config.d/*dict*.xml: "no"
+//	        - files/config.d/*function*.xml: "no"
+//	        - files/config.d/*udf*.xml: "no"
 //
 //	        - profiles/default/background_*_pool_size: "yes"
 //	        - profiles/default/max_*_for_server: "yes"
@@ -253,3 +255,79 @@ func IsConfigurationChangeRequiresReboot(host *api.ChiHost) bool {
 
 	return false
 }
+
+// Set of patterns for configuration paths which changes are applied by reloading dictionaries or
+// executable user-defined functions instead of restarting ClickHouse
+var (
+	configurationReloadDictionariesPaths = []api.Matchable{
+		"settings/dictionaries_config",
+		"files/*dict*",
+	}
+	configurationReloadFunctionsPaths = []api.Matchable{
+		"settings/user_defined_executable_functions_config",
+		"files/*function*",
+		"files/*udf*",
+	}
+)
+
+// listAffectedPaths lists paths modified between two settings, prefixed with the specified section
+func listAffectedPaths(section string, a, b *api.Settings) []string {
+	diff, equal := messagediff.DeepDiff(a, b)
+	if equal {
+		return nil
+	}
+	return api.ListAffectedSettingsPathsFromDiff(a, b, diff, section)
+}
+
+// listConfigurationChangedPaths lists settings and files paths modified in host's configuration
+func listConfigurationChangedPaths(host *api.ChiHost) (paths []string) {
+	var oldSettings, oldFiles, newSettings, newFiles *api.Settings
+	var oldHostSettings, oldHostFiles *api.Settings
+	if host.HasAncestorCHI() {
+		oldSettings = host.GetAncestorCHI().Spec.Configuration.Settings
+		oldFiles = host.GetAncestorCHI().Spec.Configuration.Files.Filter(nil, []api.SettingsSection{api.SectionUsers}, true)
+	}
+	if host.HasCHI() {
+		newSettings = host.GetCHI().Spec.Configuration.Settings
+		newFiles = host.GetCHI().Spec.Configuration.Files.Filter(nil, []api.SettingsSection{api.SectionUsers}, true)
+	}
+	if host.HasAncestor() {
+		oldHostSettings = host.GetAncestor().Settings
+		oldHostFiles = host.GetAncestor().Files.Filter(nil, []api.SettingsSection{api.SectionUsers}, true)
+	}
+	paths = append(paths, listAffectedPaths(configurationRestartPolicyRulesSectionSettings, oldSettings, newSettings)...)
+	paths = append(paths, listAffectedPaths(configurationRestartPolicyRulesSectionFiles, oldFiles, newFiles)...)
+	paths = append(paths, listAffectedPaths(configurationRestartPolicyRulesSectionSettings, oldHostSettings, host.Settings)...)
+	paths = append(paths, listAffectedPaths(configurationRestartPolicyRulesSectionFiles, oldHostFiles, host.Files.Filter(nil, []api.SettingsSection{api.SectionUsers}, true))...)
+	return paths
+}
+
+// isAnyPathMatches checks whether any of the paths matches any of the patterns
+func isAnyPathMatches(paths []string, patterns []api.Matchable) bool {
+	for _, path := range paths {
+		for i := range patterns {
+			if patterns[i].Match(path) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// IsConfigurationChangeRequiresDictionariesReload checks whether configuration changes of an existing host
+// have to be applied by reloading dictionaries
+func IsConfigurationChangeRequiresDictionariesReload(host *api.ChiHost) bool {
+	if !host.HasAncestor() {
+		return false
+	}
+	return isAnyPathMatches(listConfigurationChangedPaths(host), configurationReloadDictionariesPaths)
+}
+
+// IsConfigurationChangeRequiresFunctionsReload checks whether configuration changes of an existing host
+// have to be applied by reloading executable user-defined functions
+func IsConfigurationChangeRequiresFunctionsReload(host *api.ChiHost) bool {
+	if !host.HasAncestor() {
+		return false
+	}
+	return isAnyPathMatches(listConfigurationChangedPaths(host), configurationReloadFunctionsPaths)
+}
@@ -0,0 +1,143 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi_test
+
+import (
+	"testing"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	model "github.com/altinity/clickhouse-operator/pkg/model/chi"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/builder"
+)
+
+func TestConfigurationReload(t *testing.T) {
+	ancestor := map[string]string{
+		"config.d/my_dict.xml":     "<clickhouse><dictionary>a</dictionary></clickhouse>",
+		"config.d/my_function.xml": "<functions><function>a</function></functions>",
+		"config.d/other.xml":       "<clickhouse/>",
+	}
+
+	tests := []struct {
+		name      string
+		file      string
+		dicts     bool
+		functions bool
+		reboot    bool
+	}{
+		{name: "dictionary", file: "config.d/my_dict.xml", dicts: true},
+		{name: "function", file: "config.d/my_function.xml", functions: true},
+		{name: "other", file: "config.d/other.xml", reboot: true},
+	}
+	for _, tt := range tests {
+		files := map[string]string{}
+		for name, content := range ancestor {
+			files[name] = content
+		}
+		files[tt.file] = files[tt.file] + "<!-- changed -->"
+
+		chi := normalize(t, builder.NewCHI("test", "reload", builder.WithCluster(builder.NewCluster("main")), builder.WithFiles(files)))
+		chi.SetAncestor(normalize(t, builder.NewCHI("test", "reload", builder.WithCluster(builder.NewCluster("main")), builder.WithFiles(ancestor))))
+		host := chi.FirstHost()
+		if got := model.IsConfigurationChangeRequiresDictionariesReload(host); got != tt.dicts {
+			t.Errorf("%s: dictionaries reload: got %v want %v", tt.name, got, tt.dicts)
+		}
+		if got := model.IsConfigurationChangeRequiresFunctionsReload(host); got != tt.functions {
+			t.Errorf("%s: functions reload: got %v want %v", tt.name, got, tt.functions)
+		}
+		if got := model.IsConfigurationChangeRequiresReboot(host); got != tt.reboot {
+			t.Errorf("%s: reboot: got %v want %v", tt.name, got, tt.reboot)
+		}
+	}
+}
+
+func TestConfigurationChangeRequiresReboot(t *testing.T) {
+	newCHI := func() *api.ClickHouseInstallation {
+		return builder.NewCHI("test", "reboot", builder.WithCluster(builder.NewCluster("main")))
+	}
+	tests := []struct {
+		name   string
+		change func(chi *api.ClickHouseInstallation)
+		reboot bool
+	}{
+		{
+			// Users are reloaded by ClickHouse on the fly
+			name: "users",
+			change: func(chi *api.ClickHouseInstallation) {
+				chi.Spec.Configuration.Users = api.NewSettings().Set("analyst/password", api.NewSettingScalar("secret"))
+			},
+		},
+		{
+			name: "profile",
+			change: func(chi *api.ClickHouseInstallation) {
+				chi.Spec.Configuration.Profiles = api.NewSettings().Set("reporting/max_memory_usage", api.NewSettingScalar("10000000000"))
+			},
+		},
+		{
+			// Server-wide pools are sized at startup only
+			name: "default profile pool size",
+			change: func(chi *api.ClickHouseInstallation) {
+				chi.Spec.Configuration.Profiles = api.NewSettings().Set("default/background_schedule_pool_size", api.NewSettingScalar("32"))
+			},
+			reboot: true,
+		},
+		{
+			name: "quota",
+			change: func(chi *api.ClickHouseInstallation) {
+				chi.Spec.Configuration.Quotas = api.NewSettings().Set("reporting/interval/queries", api.NewSettingScalar("10000"))
+			},
+		},
+		{
+			name: "zookeeper",
+			change: func(chi *api.ClickHouseInstallation) {
+				chi.Spec.Configuration.Zookeeper = &api.ChiZookeeperConfig{
+					Nodes: []api.ChiZookeeperNode{{Host: "keeper", Port: 9181}},
+				}
+			},
+			reboot: true,
+		},
+		{
+			name: "setting",
+			change: func(chi *api.ClickHouseInstallation) {
+				chi.Spec.Configuration.Settings = api.NewSettings().Set("compression/case/method", api.NewSettingScalar("zstd"))
+			},
+			reboot: true,
+		},
+		{
+			// Listed as applied without restart by the configuration restart policy
+			name: "setting changeable without restart",
+			change: func(chi *api.ClickHouseInstallation) {
+				chi.Spec.Configuration.Settings = api.NewSettings().Set("max_concurrent_queries", api.NewSettingScalar("200"))
+			},
+		},
+		{
+			name:   "config.d file",
+			change: builder.WithFiles(map[string]string{"config.d/custom.xml": "<clickhouse/>"}),
+			reboot: true,
+		},
+		{
+			name:   "users.d file",
+			change: builder.WithFiles(map[string]string{"users.d/extra.xml": "<clickhouse/>"}),
+		},
+	}
+	for _, tt := range tests {
+		input := newCHI()
+		tt.change(input)
+		chi := normalize(t, input)
+		chi.SetAncestor(normalize(t, newCHI()))
+		if got := model.IsConfigurationChangeRequiresReboot(chi.FirstHost()); got != tt.reboot {
+			t.Errorf("%s: reboot: got %v want %v", tt.name, got, tt.reboot)
+		}
+	}
+}
@@ -0,0 +1,193 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/kubernetes-sigs/yaml"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+)
+
+const (
+	// DefaultChproxyDockerImage specifies default chproxy docker image to be used
+	DefaultChproxyDockerImage = "contentsquareplatform/chproxy:v1.26.4"
+	// ChproxyContainerName specifies name of the chproxy container in the pod
+	ChproxyContainerName = "chproxy"
+
+	// ChproxyHTTPPortName specifies name of the chproxy http port
+	ChproxyHTTPPortName = "http"
+	// ChproxyHTTPPortNumber specifies chproxy http port
+	ChproxyHTTPPortNumber = int32(9090)
+
+	// DirPathChproxyConfig specifies full path of folder where chproxy config is mounted
+	DirPathChproxyConfig = "/etc/chproxy"
+	// FileNameChproxyConfig specifies name of the chproxy config file
+	FileNameChproxyConfig = "config.yml"
+	// DirPathChproxyCache specifies full path of folder where chproxy caches are located
+	DirPathChproxyCache = "/var/cache/chproxy"
+
+	// ChproxyDefaultUser specifies name of the chproxy user created in case no users specified
+	ChproxyDefaultUser = "default"
+	// ChproxyDefaultToUser specifies ClickHouse user queries are routed to by default
+	ChproxyDefaultToUser = "default"
+	// ChproxyDefaultCacheMaxSize specifies default max size of the chproxy cache
+	ChproxyDefaultCacheMaxSize = "256Mb"
+	// ChproxyDefaultCacheExpire specifies default expiration of the chproxy cache
+	ChproxyDefaultCacheExpire = "1m"
+)
+
+// chproxyConfig specifies chproxy config file
+type chproxyConfig struct {
+	HackMePlease bool                   `json:"hack_me_please,omitempty"`
+	Server       chproxyConfigServer    `json:"server"`
+	Users        []chproxyConfigUser    `json:"users"`
+	Clusters     []chproxyConfigCluster `json:"clusters"`
+	Caches       []chproxyConfigCache   `json:"caches,omitempty"`
+}
+
+// chproxyConfigServer specifies server section of chproxy config file
+type chproxyConfigServer struct {
+	HTTP chproxyConfigServerHTTP `json:"http"`
+}
+
+// chproxyConfigServerHTTP specifies http server of chproxy config file
+type chproxyConfigServerHTTP struct {
+	ListenAddr string `json:"listen_addr"`
+}
+
+// chproxyConfigUser specifies user of chproxy config file
+type chproxyConfigUser struct {
+	Name                 string   `json:"name"`
+	Password             string   `json:"password,omitempty"`
+	ToCluster            string   `json:"to_cluster"`
+	ToUser               string   `json:"to_user"`
+	Cache                string   `json:"cache,omitempty"`
+	AllowedNetworks      []string `json:"allowed_networks,omitempty"`
+	MaxConcurrentQueries int      `json:"max_concurrent_queries,omitempty"`
+	MaxExecutionTime     string   `json:"max_execution_time,omitempty"`
+}
+
+// chproxyConfigCluster specifies cluster of chproxy config file
+type chproxyConfigCluster struct {
+	Name   string                     `json:"name"`
+	Scheme string                     `json:"scheme"`
+	Nodes  []string                   `json:"nodes"`
+	Users  []chproxyConfigClusterUser `json:"users"`
+}
+
+// chproxyConfigClusterUser specifies ClickHouse user chproxy connects to the cluster with
+type chproxyConfigClusterUser struct {
+	Name     string `json:"name"`
+	Password string `json:"password,omitempty"`
+}
+
+// chproxyConfigCache specifies cache of chproxy config file
+type chproxyConfigCache struct {
+	Name       string                       `json:"name"`
+	Mode       string                       `json:"mode"`
+	FileSystem chproxyConfigCacheFileSystem `json:"file_system"`
+	Expire     string                       `json:"expire,omitempty"`
+}
+
+// chproxyConfigCacheFileSystem specifies file system cache of chproxy config file
+type chproxyConfigCacheFileSystem struct {
+	Dir     string `json:"dir"`
+	MaxSize string `json:"max_size"`
+}
+
+// CreateChproxyConfig creates chproxy config file content of the normalized CHI.
+// Clusters of the config list hosts of the CHI clusters, so config follows topology changes.
+func CreateChproxyConfig(chi *api.ClickHouseInstallation) (string, error) {
+	chproxy := chi.Spec.Chproxy
+	config := &chproxyConfig{
+		Server: chproxyConfigServer{
+			HTTP: chproxyConfigServerHTTP{
+				ListenAddr: fmt.Sprintf(":%d", ChproxyHTTPPortNumber),
+			},
+		},
+	}
+
+	for _, user := range chproxy.Users {
+		config.Users = append(config.Users, chproxyConfigUser{
+			Name:                 user.Name,
+			Password:             user.Password,
+			ToCluster:            user.ToCluster,
+			ToUser:               user.ToUser,
+			Cache:                user.Cache,
+			AllowedNetworks:      user.AllowedNetworks,
+			MaxConcurrentQueries: user.MaxConcurrentQueries,
+			MaxExecutionTime:     user.MaxExecutionTime,
+		})
+		if len(user.AllowedNetworks) == 0 {
+			// chproxy refuses to accept requests from any network without explicit permission.
+			// It is reachable through in-cluster Service only, unless exposed explicitly.
+			config.HackMePlease = true
+		}
+	}
+
+	chi.WalkClusters(func(cluster *api.Cluster) error {
+		c := chproxyConfigCluster{
+			Name:   cluster.Name,
+			Scheme: "http",
+		}
+		cluster.WalkHosts(func(host *api.ChiHost) error {
+			c.Nodes = append(c.Nodes, fmt.Sprintf("%s:%d", CreateFQDN(host), host.HTTPPort))
+			return nil
+		})
+		for _, user := range chproxy.Users {
+			if user.ToCluster != cluster.Name {
+				continue
+			}
+			found := false
+			for _, clusterUser := range c.Users {
+				found = found || (clusterUser.Name == user.ToUser)
+			}
+			if !found {
+				c.Users = append(c.Users, chproxyConfigClusterUser{
+					Name:     user.ToUser,
+					Password: user.ToPassword,
+				})
+			}
+		}
+		if len(c.Users) == 0 {
+			c.Users = append(c.Users, chproxyConfigClusterUser{
+				Name: ChproxyDefaultToUser,
+			})
+		}
+		config.Clusters = append(config.Clusters, c)
+		return nil
+	})
+
+	for _, cache := range chproxy.Caches {
+		config.Caches = append(config.Caches, chproxyConfigCache{
+			Name: cache.Name,
+			Mode: "file_system",
+			FileSystem: chproxyConfigCacheFileSystem{
+				Dir:     path.Join(DirPathChproxyCache, cache.Name),
+				MaxSize: cache.MaxSize,
+			},
+			Expire: cache.Expire,
+		})
+	}
+
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
@@ -0,0 +1,51 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi_test
+
+import (
+	"strings"
+	"testing"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	model "github.com/altinity/clickhouse-operator/pkg/model/chi"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/builder"
+)
+
+func TestCreateChproxyConfig(t *testing.T) {
+	input := builder.NewCHI("test", "proxied", builder.WithCluster(builder.NewCluster("main", builder.WithReplicas(2))))
+	input.Spec.Chproxy = &api.ChiChproxy{
+		Users: []api.ChiChproxyUser{
+			{
+				Name:     "web",
+				Password: "secret",
+			},
+		},
+	}
+	config, err := model.CreateChproxyConfig(normalize(t, input))
+	if err != nil {
+		t.Fatalf("unable to create config err: %v", err)
+	}
+
+	for _, want := range []string{
+		"name: web",
+		"to_cluster: main",
+		"chi-proxied-main-0-0.test.svc.cluster.local:8123",
+		"chi-proxied-main-0-1.test.svc.cluster.local:8123",
+	} {
+		if !strings.Contains(config, want) {
+			t.Errorf("chproxy config does not contain %q:\n%s", want, config)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/chop"
+)
+
+// Keys of connection details published for applications
+const (
+	ConnectionKeyType     = "type"
+	ConnectionKeyProvider = "provider"
+	ConnectionKeyHost     = "host"
+	ConnectionKeyPort     = "port"
+	ConnectionKeyHTTPPort = "http-port"
+	ConnectionKeySecure   = "secure"
+	ConnectionKeyHosts    = "hosts"
+	ConnectionKeyClusters = "clusters"
+	ConnectionKeyUsers    = "users"
+
+	connectionValueType     = "clickhouse"
+	connectionValueProvider = "clickhouse-operator"
+)
+
+// CreateConnectionDetails creates ready-to-use connection details of the CHI.
+// Keys follow the Service Binding specification, so the Secret can be projected into application workloads as is.
+// Passwords are not published, users are referenced by name only.
+func CreateConnectionDetails(chi *api.ClickHouseInstallation) map[string]string {
+	details := map[string]string{
+		ConnectionKeyType:     connectionValueType,
+		ConnectionKeyProvider: connectionValueProvider,
+		ConnectionKeyHost:     CreateCHIServiceFQDN(chi),
+	}
+
+	// Entry point ports are the ones of the first host, since all hosts serve the same protocols
+	if host := chi.FirstHost(); host != nil {
+		port, httpPort := host.TCPPort, host.HTTPPort
+		if host.IsSecure() {
+			port, httpPort = host.TLSPort, host.HTTPSPort
+		}
+		details[ConnectionKeyPort] = strconv.Itoa(int(port))
+		details[ConnectionKeyHTTPPort] = strconv.Itoa(int(httpPort))
+		details[ConnectionKeySecure] = strconv.FormatBool(host.IsSecure())
+	}
+
+	var hosts, clusters []string
+	chi.WalkClusters(func(cluster *api.Cluster) error {
+		clusters = append(clusters, cluster.Name)
+		var clusterHosts []string
+		cluster.WalkHosts(func(host *api.ChiHost) error {
+			clusterHosts = append(clusterHosts, getConnectionHostAddress(host))
+			return nil
+		})
+		hosts = append(hosts, clusterHosts...)
+		details[ConnectionKeyHosts+"-"+cluster.Name] = strings.Join(clusterHosts, ",")
+		return nil
+	})
+	details[ConnectionKeyHosts] = strings.Join(hosts, ",")
+	details[ConnectionKeyClusters] = strings.Join(clusters, ",")
+
+	var users []string
+	for _, user := range chi.Spec.Configuration.Users.Groups() {
+		if user == chop.Config().ClickHouse.Access.Username {
+			// Operator's own user is not meant to be used by applications
+			continue
+		}
+		users = append(users, user)
+	}
+	details[ConnectionKeyUsers] = strings.Join(users, ",")
+
+	return details
+}
+
+// getConnectionHostAddress gets host:port address of the host
+func getConnectionHostAddress(host *api.ChiHost) string {
+	port := host.TCPPort
+	if host.IsSecure() {
+		port = host.TLSPort
+	}
+	return fmt.Sprintf("%s:%d", CreateFQDN(host), port)
+}
@@ -0,0 +1,51 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi_test
+
+import (
+	"strings"
+	"testing"
+
+	model "github.com/altinity/clickhouse-operator/pkg/model/chi"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/builder"
+)
+
+func TestCreateConnectionDetails(t *testing.T) {
+	chi := normalize(t, builder.NewCHI("test", "apps",
+		builder.WithCluster(builder.NewCluster("main", builder.WithShards(2))),
+		builder.WithCluster(builder.NewCluster("events")),
+	))
+
+	details := model.CreateConnectionDetails(chi)
+	for key, want := range map[string]string{
+		model.ConnectionKeyType:              "clickhouse",
+		model.ConnectionKeyHost:              "clickhouse-apps.test.svc.cluster.local",
+		model.ConnectionKeyPort:              "9000",
+		model.ConnectionKeyHTTPPort:          "8123",
+		model.ConnectionKeySecure:            "false",
+		model.ConnectionKeyClusters:          "main,events",
+		model.ConnectionKeyHosts + "-events": "chi-apps-events-0-0.test.svc.cluster.local:9000",
+		model.ConnectionKeyHosts: "chi-apps-main-0-0.test.svc.cluster.local:9000," +
+			"chi-apps-main-1-0.test.svc.cluster.local:9000," +
+			"chi-apps-events-0-0.test.svc.cluster.local:9000",
+	} {
+		if got := details[key]; got != want {
+			t.Errorf("connection details key %s: got %q want %q", key, got, want)
+		}
+	}
+	if users := details[model.ConnectionKeyUsers]; !strings.Contains(users, "default") || strings.Contains(users, "clickhouse_operator") {
+		t.Errorf("connection details users %q must list default user only", users)
+	}
+}
@@ -21,6 +21,37 @@ const (
 
 const (
 	InternodeClusterSecretEnvName = "CLICKHOUSE_INTERNODE_CLUSTER_SECRET"
+	// TimezoneEnvName specifies ENV var used to propagate .spec.timezone into the pod
+	TimezoneEnvName = "TZ"
+	// InsertQuorumEnvName specifies ENV var used to propagate cluster's insert_quorum into the default profile
+	InsertQuorumEnvName = "CLICKHOUSE_INSERT_QUORUM"
+	// InsertQuorumTimeoutEnvName specifies ENV var used to propagate cluster's insert_quorum_timeout into the default profile
+	InsertQuorumTimeoutEnvName = "CLICKHOUSE_INSERT_QUORUM_TIMEOUT"
+	// SelectSequentialConsistencyEnvName specifies ENV var used to propagate cluster's select_sequential_consistency into the default profile
+	SelectSequentialConsistencyEnvName = "CLICKHOUSE_SELECT_SEQUENTIAL_CONSISTENCY"
+)
+
+const (
+	// InsertQuorumTimeoutDefault specifies default insert_quorum_timeout in milliseconds, same as ClickHouse's one
+	InsertQuorumTimeoutDefault = int64(600000)
+)
+
+const (
+	// TimezoneSettingName specifies server setting used to propagate .spec.timezone into the server config
+	TimezoneSettingName = "timezone"
+	// CoreDumpSizeLimitSettingName specifies server setting used to propagate .spec.debug.coreDumpSizeLimit
+	CoreDumpSizeLimitSettingName = "core_dump/size_limit"
+	// CoreDumpSizeLimitDefault specifies default core dump size limit in bytes
+	CoreDumpSizeLimitDefault = "1073741824"
+	// IntrospectionFunctionsProfileSettingName specifies default profile setting used to propagate .spec.debug.introspectionFunctions
+	IntrospectionFunctionsProfileSettingName = "default/allow_introspection_functions"
+)
+
+const (
+	// CoreDumpsVolumeName specifies name of the volume where core dumps are written to
+	CoreDumpsVolumeName = "core-dumps"
+	// CoreDumpPattern specifies kernel.core_pattern pointing into core dumps volume, core files are named by executable, pid and time
+	CoreDumpPattern = DirPathClickHouseCoreDumps + "/core.%e.%p.%t"
 )
 
 // Values for Schema Policy
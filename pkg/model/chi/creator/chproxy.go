@@ -0,0 +1,183 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package creator
+
+import (
+	apps "k8s.io/api/apps/v1"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/altinity/clickhouse-operator/pkg/chop"
+	model "github.com/altinity/clickhouse-operator/pkg/model/chi"
+	"github.com/altinity/clickhouse-operator/pkg/model/k8s"
+	"github.com/altinity/clickhouse-operator/pkg/util"
+)
+
+const (
+	// chproxyConfigVolumeName specifies name of the volume chproxy config is mounted from
+	chproxyConfigVolumeName = "chproxy-config"
+	// chproxyCacheVolumeName specifies name of the volume chproxy caches are located on
+	chproxyCacheVolumeName = "chproxy-cache"
+	// annotationChproxyConfigVersion specifies pod template annotation, which rolls chproxy pods on config change
+	annotationChproxyConfigVersion = "clickhouse.altinity.com/chproxy-config-version"
+)
+
+// CreateSecretChproxy creates Secret with chproxy config, since config contains passwords of the users.
+// Returns nil in case chproxy is not enabled
+func (c *Creator) CreateSecretChproxy() *core.Secret {
+	if !c.chi.Spec.Chproxy.IsEnabled() {
+		return nil
+	}
+	config, err := model.CreateChproxyConfig(c.chi)
+	if err != nil {
+		c.a.V(1).F().Error("unable to create chproxy config err: %v", err)
+		return nil
+	}
+	return &core.Secret{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace:       c.chi.Namespace,
+			Name:            model.CreateChproxyName(c.chi),
+			Labels:          model.Macro(c.chi).Map(c.labels.GetChproxy()),
+			Annotations:     model.Macro(c.chi).Map(c.annotations.GetChproxy()),
+			OwnerReferences: getOwnerReferences(c.chi),
+		},
+		StringData: map[string]string{
+			model.FileNameChproxyConfig: config,
+		},
+		Type: core.SecretTypeOpaque,
+	}
+}
+
+// CreateDeploymentChproxy creates chproxy Deployment.
+// Pods are rolled whenever config changes, so chproxy follows topology of the CHI.
+// Returns nil in case chproxy is not enabled
+func (c *Creator) CreateDeploymentChproxy() *apps.Deployment {
+	secret := c.CreateSecretChproxy()
+	if secret == nil {
+		return nil
+	}
+	chproxy := c.chi.Spec.Chproxy
+	deployment := &apps.Deployment{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace:       c.chi.Namespace,
+			Name:            model.CreateChproxyName(c.chi),
+			Labels:          model.Macro(c.chi).Map(c.labels.GetChproxy()),
+			Annotations:     model.Macro(c.chi).Map(c.annotations.GetChproxy()),
+			OwnerReferences: getOwnerReferences(c.chi),
+		},
+		Spec: apps.DeploymentSpec{
+			Replicas: chproxy.Replicas,
+			Selector: &meta.LabelSelector{
+				MatchLabels: c.labels.GetSelectorChproxy(),
+			},
+			Template: core.PodTemplateSpec{
+				ObjectMeta: meta.ObjectMeta{
+					Labels: c.labels.GetSelectorChproxy(),
+					Annotations: map[string]string{
+						annotationChproxyConfigVersion: util.Fingerprint(secret.StringData),
+					},
+				},
+				Spec: core.PodSpec{
+					Containers: []core.Container{
+						{
+							Name:  model.ChproxyContainerName,
+							Image: chproxy.Image,
+							Args: []string{
+								"-config", model.DirPathChproxyConfig + "/" + model.FileNameChproxyConfig,
+							},
+							Ports: []core.ContainerPort{
+								{
+									Name:          model.ChproxyHTTPPortName,
+									ContainerPort: model.ChproxyHTTPPortNumber,
+									Protocol:      core.ProtocolTCP,
+								},
+							},
+							ReadinessProbe: &core.Probe{
+								ProbeHandler: core.ProbeHandler{
+									TCPSocket: &core.TCPSocketAction{
+										Port: intstr.FromString(model.ChproxyHTTPPortName),
+									},
+								},
+								PeriodSeconds: 5,
+							},
+							VolumeMounts: []core.VolumeMount{
+								{
+									Name:      chproxyConfigVolumeName,
+									MountPath: model.DirPathChproxyConfig,
+									ReadOnly:  true,
+								},
+								{
+									Name:      chproxyCacheVolumeName,
+									MountPath: model.DirPathChproxyCache,
+								},
+							},
+						},
+					},
+					Volumes: []core.Volume{
+						{
+							Name: chproxyConfigVolumeName,
+							VolumeSource: core.VolumeSource{
+								Secret: &core.SecretVolumeSource{
+									SecretName: secret.Name,
+								},
+							},
+						},
+						{
+							Name: chproxyCacheVolumeName,
+							VolumeSource: core.VolumeSource{
+								EmptyDir: &core.EmptyDirVolumeSource{},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	k8s.PodSpecAppendImagePullSecrets(&deployment.Spec.Template.Spec, chop.Config().Pod.ImagePullSecrets...)
+	model.MakeObjectVersion(&deployment.ObjectMeta, deployment)
+	return deployment
+}
+
+// CreateServiceChproxy creates Service in front of chproxy pods.
+// Returns nil in case chproxy is not enabled
+func (c *Creator) CreateServiceChproxy() *core.Service {
+	if !c.chi.Spec.Chproxy.IsEnabled() {
+		return nil
+	}
+	svc := &core.Service{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace:       c.chi.Namespace,
+			Name:            model.CreateChproxyName(c.chi),
+			Labels:          model.Macro(c.chi).Map(c.labels.GetChproxy()),
+			Annotations:     model.Macro(c.chi).Map(c.annotations.GetChproxy()),
+			OwnerReferences: getOwnerReferences(c.chi),
+		},
+		Spec: core.ServiceSpec{
+			Ports: []core.ServicePort{
+				{
+					Name:       model.ChproxyHTTPPortName,
+					Protocol:   core.ProtocolTCP,
+					Port:       model.ChproxyHTTPPortNumber,
+					TargetPort: intstr.FromString(model.ChproxyHTTPPortName),
+				},
+			},
+			Selector: c.labels.GetSelectorChproxy(),
+			Type:     core.ServiceTypeClusterIP,
+		},
+	}
+	model.MakeObjectVersion(&svc.ObjectMeta, svc)
+	return svc
+}
@@ -0,0 +1,47 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package creator_test
+
+import (
+	"testing"
+
+	k8sLabels "k8s.io/apimachinery/pkg/labels"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	model "github.com/altinity/clickhouse-operator/pkg/model/chi"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/builder"
+)
+
+func TestCreateDeploymentChproxy(t *testing.T) {
+	input := builder.NewCHI("test", "proxied", builder.WithCluster(builder.NewCluster("main", builder.WithReplicas(2))))
+	input.Spec.Chproxy = &api.ChiChproxy{}
+	_, c := newCreator(t, input)
+
+	deployment := c.CreateDeploymentChproxy()
+	if deployment.Name != "chproxy-proxied" {
+		t.Errorf("got deployment %s want chproxy-proxied", deployment.Name)
+	}
+	if deployment.Spec.Template.Labels[model.LabelAppName] == model.LabelAppValue {
+		t.Errorf("chproxy pods must not be labelled as ClickHouse pods")
+	}
+
+	service := c.CreateServiceChproxy()
+	if service.Spec.Selector[model.LabelAppName] != model.LabelAppValueChproxy {
+		t.Errorf("chproxy service selector %v does not select chproxy pods", service.Spec.Selector)
+	}
+	if !k8sLabels.SelectorFromSet(service.Spec.Selector).Matches(k8sLabels.Set(deployment.Spec.Template.Labels)) {
+		t.Errorf("chproxy service selector %v does not select deployment pods %v", service.Spec.Selector, deployment.Spec.Template.Labels)
+	}
+}
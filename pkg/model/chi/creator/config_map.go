@@ -75,3 +75,42 @@ func (c *Creator) CreateConfigMapHost(host *api.ChiHost) *core.ConfigMap {
 	model.MakeObjectVersion(&cm.ObjectMeta, cm)
 	return cm
 }
+
+// CreateConfigMapCHILogging creates new core.ConfigMap with config of the log shipping agent.
+// Returns nil in case logging is not enabled
+func (c *Creator) CreateConfigMapCHILogging() *core.ConfigMap {
+	data := model.CreateLoggingConfigFiles(c.chi)
+	if data == nil {
+		return nil
+	}
+	cm := &core.ConfigMap{
+		ObjectMeta: meta.ObjectMeta{
+			Name:            model.CreateConfigMapLoggingName(c.chi),
+			Namespace:       c.chi.Namespace,
+			Labels:          model.Macro(c.chi).Map(c.labels.GetConfigMapCHILogging()),
+			Annotations:     model.Macro(c.chi).Map(c.annotations.GetConfigMapCHILogging()),
+			OwnerReferences: getOwnerReferences(c.chi),
+		},
+		Data: data,
+	}
+	// And after the object is ready we can put version label
+	model.MakeObjectVersion(&cm.ObjectMeta, cm)
+	return cm
+}
+
+// CreateConfigMapCHITopology creates new core.ConfigMap with cluster topology published for external consumers
+func (c *Creator) CreateConfigMapCHITopology() *core.ConfigMap {
+	cm := &core.ConfigMap{
+		ObjectMeta: meta.ObjectMeta{
+			Name:            model.CreateConfigMapTopologyName(c.chi),
+			Namespace:       c.chi.Namespace,
+			Labels:          model.Macro(c.chi).Map(c.labels.GetConfigMapCHITopology()),
+			Annotations:     model.Macro(c.chi).Map(c.annotations.GetConfigMapCHITopology()),
+			OwnerReferences: getOwnerReferences(c.chi),
+		},
+		Data: model.CreateTopologyFiles(c.chi),
+	}
+	// And after the object is ready we can put version label
+	model.MakeObjectVersion(&cm.ObjectMeta, cm)
+	return cm
+}
@@ -0,0 +1,132 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package creator_test
+
+import (
+	"testing"
+
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	model "github.com/altinity/clickhouse-operator/pkg/model/chi"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/builder"
+)
+
+// getObjectVersions gets object versions of the first host stateful set and of the config maps mounted into it
+func getObjectVersions(t *testing.T, input *api.ClickHouseInstallation) map[string]string {
+	t.Helper()
+	chi, c := newCreator(t, input)
+	host := chi.FirstHost()
+	versions := make(map[string]string)
+	for name, objectMeta := range map[string]meta.ObjectMeta{
+		"statefulSet": c.CreateStatefulSet(host, false).ObjectMeta,
+		"common":      c.CreateConfigMapCHICommon(nil).ObjectMeta,
+		"users":       c.CreateConfigMapCHICommonUsers().ObjectMeta,
+		"host":        c.CreateConfigMapHost(host).ObjectMeta,
+	} {
+		version, ok := model.GetObjectVersion(objectMeta)
+		if !ok {
+			t.Fatalf("%s has no object version", name)
+		}
+		versions[name] = version
+	}
+	return versions
+}
+
+// TestConfigurationChangeVersions checks that configuration sections are delivered by their config maps.
+// Stateful set is not changed, restart is decided by the configuration restart policy
+func TestConfigurationChangeVersions(t *testing.T) {
+	newCHI := func() *api.ClickHouseInstallation {
+		return builder.NewCHI("test", "versions", builder.WithCluster(builder.NewCluster("main", builder.WithShards(2))))
+	}
+	tests := []struct {
+		name    string
+		change  func(chi *api.ClickHouseInstallation)
+		changed []string
+	}{
+		{
+			name: "users",
+			change: func(chi *api.ClickHouseInstallation) {
+				chi.Spec.Configuration.Users = api.NewSettings().Set("analyst/password", api.NewSettingScalar("secret"))
+			},
+			changed: []string{"users"},
+		},
+		{
+			name: "profiles and quotas",
+			change: func(chi *api.ClickHouseInstallation) {
+				chi.Spec.Configuration.Profiles = api.NewSettings().Set("reporting/readonly", api.NewSettingScalar("1"))
+				chi.Spec.Configuration.Quotas = api.NewSettings().Set("reporting/interval/queries", api.NewSettingScalar("10000"))
+			},
+			changed: []string{"users"},
+		},
+		{
+			// Zookeeper is the same for all hosts, so it is shared by the common config map
+			name: "zookeeper",
+			change: func(chi *api.ClickHouseInstallation) {
+				chi.Spec.Configuration.Zookeeper = &api.ChiZookeeperConfig{
+					Nodes: []api.ChiZookeeperNode{{Host: "keeper", Port: 9181}},
+				}
+			},
+			changed: []string{"common"},
+		},
+		{
+			name: "settings",
+			change: func(chi *api.ClickHouseInstallation) {
+				chi.Spec.Configuration.Settings = api.NewSettings().Set("max_concurrent_queries", api.NewSettingScalar("200"))
+			},
+			changed: []string{"common"},
+		},
+		{
+			name:    "config.d file",
+			change:  builder.WithFiles(map[string]string{"config.d/custom.xml": "<clickhouse/>"}),
+			changed: []string{"common"},
+		},
+		{
+			name:    "users.d file",
+			change:  builder.WithFiles(map[string]string{"users.d/extra.xml": "<clickhouse/>"}),
+			changed: []string{"users"},
+		},
+	}
+
+	base := getObjectVersions(t, newCHI())
+	for _, tt := range tests {
+		chi := newCHI()
+		tt.change(chi)
+		versions := getObjectVersions(t, chi)
+		for name, version := range versions {
+			changed := false
+			for _, expected := range tt.changed {
+				changed = changed || (expected == name)
+			}
+			if (version != base[name]) != changed {
+				t.Errorf("%s: %s version changed: %v want %v", tt.name, name, version != base[name], changed)
+			}
+		}
+	}
+}
+
+func TestCreateConfigMapCHITopology(t *testing.T) {
+	_, c := newCreator(t, builder.NewCHI("test", "apps", builder.WithCluster(builder.NewCluster("main"))))
+
+	configMap := c.CreateConfigMapCHITopology()
+	if configMap.Name != "chi-apps-topology" {
+		t.Errorf("got config map %s want chi-apps-topology", configMap.Name)
+	}
+	for _, file := range []string{model.FileNameTopologyRemoteServers, model.FileNameTopologyJSON} {
+		if _, ok := configMap.Data[file]; !ok {
+			t.Errorf("config map does not contain %s", file)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package creator_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/creator"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/normalizer"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/render"
+)
+
+func TestMain(m *testing.M) {
+	render.Init(filepath.Join("..", "..", "..", "..", "config", "config.yaml"))
+	os.Exit(m.Run())
+}
+
+// newCreator normalizes CHI the same way the operator does and makes creator of its objects
+func newCreator(t *testing.T, chi *api.ClickHouseInstallation) (*api.ClickHouseInstallation, *creator.Creator) {
+	t.Helper()
+	normalized, err := normalizer.NewNormalizer(render.NoSecrets).CreateTemplatedCHI(chi, normalizer.NewOptions())
+	if err != nil {
+		t.Fatalf("unable to normalize err: %v", err)
+	}
+	return normalized, creator.NewCreator(normalized)
+}
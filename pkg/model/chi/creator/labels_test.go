@@ -0,0 +1,79 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package creator_test
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	model "github.com/altinity/clickhouse-operator/pkg/model/chi"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/builder"
+)
+
+// TestCreatedObjectsCustomLabels checks that custom labels and annotations of the CHI and of its clusters
+// are propagated to child objects, without overriding generated labels
+func TestCreatedObjectsCustomLabels(t *testing.T) {
+	adhoc := builder.NewCluster("adhoc")
+	adhoc.Labels = map[string]string{"cost-center": "adhoc-reports"}
+	input := builder.NewCHI("test", "labeled",
+		builder.WithCluster(builder.NewCluster("main", builder.WithDataVolumeClaimTemplate("data"))),
+		builder.WithCluster(adhoc),
+		builder.WithVolumeClaimTemplates(builder.NewVolumeClaimTemplate("data", resource.MustParse("10Gi"))),
+	)
+	input.Spec.Defaults = &api.ChiDefaults{
+		Labels:      map[string]string{"cost-center": "analytics", model.LabelCHIName: "hijacked"},
+		Annotations: map[string]string{"owner": "data-platform"},
+	}
+	chi, c := newCreator(t, input)
+
+	objects := map[string]meta.ObjectMeta{
+		"ConfigMap common": c.CreateConfigMapCHICommon(nil).ObjectMeta,
+		"ConfigMap users":  c.CreateConfigMapCHICommonUsers().ObjectMeta,
+		"Service CHI":      c.CreateServiceCHI().ObjectMeta,
+	}
+	chi.WalkClusters(func(cluster *api.Cluster) error {
+		host := cluster.FirstHost()
+		statefulSet := c.CreateStatefulSet(host, false)
+		objects["StatefulSet "+cluster.Name] = statefulSet.ObjectMeta
+		objects["Pod "+cluster.Name] = statefulSet.Spec.Template.ObjectMeta
+		for _, pvc := range statefulSet.Spec.VolumeClaimTemplates {
+			objects["PersistentVolumeClaim "+cluster.Name+" "+pvc.Name] = pvc.ObjectMeta
+		}
+		objects["ConfigMap host "+cluster.Name] = c.CreateConfigMapHost(host).ObjectMeta
+		objects["Service host "+cluster.Name] = c.CreateServiceHost(host).ObjectMeta
+		return nil
+	})
+
+	// Custom labels of the cluster take precedence over the ones of the CHI
+	want := map[string]string{"": "analytics", "main": "analytics", "adhoc": "adhoc-reports"}
+	for kind, objectMeta := range objects {
+		cluster := objectMeta.Labels[model.LabelClusterName]
+		if got := objectMeta.Labels["cost-center"]; got != want[cluster] {
+			t.Errorf("%s: got cost-center %q want %q", kind, got, want[cluster])
+		}
+		if objectMeta.Labels[model.LabelCHIName] != "labeled" {
+			t.Errorf("%s: generated label is overridden", kind)
+		}
+		if objectMeta.Annotations["owner"] != "data-platform" {
+			t.Errorf("%s: no owner annotation", kind)
+		}
+	}
+	if _, ok := objects["PersistentVolumeClaim main data"]; !ok {
+		t.Errorf("volume claim template is not created")
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package creator_test
+
+import (
+	"strings"
+	"testing"
+
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sLabels "k8s.io/apimachinery/pkg/labels"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	model "github.com/altinity/clickhouse-operator/pkg/model/chi"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/builder"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/creator"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/normalizer"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/render"
+)
+
+// TestCreatedObjectsOwnership checks that every kind of object created by the operator is discovered by CHI labels,
+// so it is pruned once its source is removed from the spec, and, except PVCs, is owned by the CHI
+func TestCreatedObjectsOwnership(t *testing.T) {
+	render.Init("")
+	cluster := builder.NewCluster("main", builder.WithShards(2))
+	cluster.Templates = &api.ChiTemplateNames{
+		ClusterServiceTemplate: "service",
+		ShardServiceTemplate:   "service",
+	}
+	input := builder.NewCHI("test", "owned",
+		builder.WithCluster(cluster),
+		builder.WithServiceTemplates(api.ChiServiceTemplate{Name: "service"}),
+	)
+	input.Spec.Chproxy = &api.ChiChproxy{}
+	chi, err := normalizer.NewNormalizer(render.NoSecrets).CreateTemplatedCHI(input, normalizer.NewOptions())
+	if err != nil {
+		t.Fatalf("unable to normalize err: %v", err)
+	}
+
+	c := creator.NewCreator(chi)
+	cluster = chi.FindCluster("main")
+	shard := cluster.GetShard(1)
+	host := shard.FirstHost()
+	spec := &core.PersistentVolumeClaimSpec{}
+	snapshot := c.CreateForkVolumeSnapshot(host, "data-fork", "data-source")
+
+	objects := map[string]meta.ObjectMeta{
+		"StatefulSet":                c.CreateStatefulSet(host, false).ObjectMeta,
+		"ConfigMap common":           c.CreateConfigMapCHICommon(nil).ObjectMeta,
+		"ConfigMap users":            c.CreateConfigMapCHICommonUsers().ObjectMeta,
+		"ConfigMap topology":         c.CreateConfigMapCHITopology().ObjectMeta,
+		"ConfigMap host":             c.CreateConfigMapHost(host).ObjectMeta,
+		"Service CHI":                c.CreateServiceCHI().ObjectMeta,
+		"Service cluster":            c.CreateServiceCluster(cluster).ObjectMeta,
+		"Service shard":              c.CreateServiceShard(shard).ObjectMeta,
+		"Service host":               c.CreateServiceHost(host).ObjectMeta,
+		"Service chproxy":            c.CreateServiceChproxy().ObjectMeta,
+		"Secret cluster":             c.CreateClusterSecret(cluster).ObjectMeta,
+		"Secret cluster TLS":         c.CreateClusterTLSSecret(cluster).ObjectMeta,
+		"Secret connection":          c.CreateSecretConnection().ObjectMeta,
+		"Secret chproxy":             c.CreateSecretChproxy().ObjectMeta,
+		"PersistentVolumeClaim":      c.CreatePVC("data", host, spec).ObjectMeta,
+		"PersistentVolumeClaim fork": c.CreateForkPVC("data", host, spec, snapshot.GetName()).ObjectMeta,
+		"PodDisruptionBudget":        c.NewPodDisruptionBudget(cluster).ObjectMeta,
+		"Deployment chproxy":         c.CreateDeploymentChproxy().ObjectMeta,
+		"VolumeSnapshot": {
+			Labels:          snapshot.GetLabels(),
+			OwnerReferences: snapshot.GetOwnerReferences(),
+		},
+	}
+
+	selector := k8sLabels.SelectorFromSet(model.NewLabeler(chi).GetSelectorCHIScope())
+	for kind, objectMeta := range objects {
+		if !selector.Matches(k8sLabels.Set(objectMeta.Labels)) {
+			t.Errorf("%s is not discovered by CHI labels, labels %v", kind, objectMeta.Labels)
+		}
+		if strings.HasPrefix(kind, "PersistentVolumeClaim") {
+			// PVCs are not owned, they outlive the CHI according to reclaim policy
+			continue
+		}
+		if (len(objectMeta.OwnerReferences) != 1) || (objectMeta.OwnerReferences[0].Name != chi.Name) {
+			t.Errorf("%s is not owned by the CHI, owner references %v", kind, objectMeta.OwnerReferences)
+		}
+	}
+}
@@ -0,0 +1,613 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package creator_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	apps "k8s.io/api/apps/v1"
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/chop"
+	model "github.com/altinity/clickhouse-operator/pkg/model/chi"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/builder"
+)
+
+// getContainer gets container of the pod by name
+func getContainer(t *testing.T, podSpec *core.PodSpec, name string) *core.Container {
+	t.Helper()
+	for i := range podSpec.Containers {
+		if podSpec.Containers[i].Name == name {
+			return &podSpec.Containers[i]
+		}
+	}
+	t.Fatalf("container %s is not found", name)
+	return nil
+}
+
+func TestCreateStatefulSetWriteReliability(t *testing.T) {
+	replicated := builder.NewCluster("main", builder.WithShards(2), builder.WithReplicas(3))
+	replicated.WriteReliability = &api.ChiWriteReliability{
+		SelectSequentialConsistency: api.NewStringBool(true),
+	}
+	chi, c := newCreator(t, builder.NewCHI("test", "quorum",
+		builder.WithCluster(replicated),
+		builder.WithCluster(builder.NewCluster("adhoc")),
+	))
+
+	want := map[string]map[string]string{
+		"main": {
+			model.InsertQuorumEnvName:                "2",
+			model.InsertQuorumTimeoutEnvName:         "600000",
+			model.SelectSequentialConsistencyEnvName: "1",
+		},
+		"adhoc": {
+			model.InsertQuorumEnvName:                "0",
+			model.SelectSequentialConsistencyEnvName: "0",
+		},
+	}
+	chi.WalkHosts(func(host *api.ChiHost) error {
+		statefulSet := c.CreateStatefulSet(host, false)
+		env := map[string]string{}
+		for _, envVar := range getContainer(t, &statefulSet.Spec.Template.Spec, model.ClickHouseContainerName).Env {
+			env[envVar.Name] = envVar.Value
+		}
+		for name, value := range want[host.Runtime.Address.ClusterName] {
+			if env[name] != value {
+				t.Errorf("host %s: got %s=%q want %q", host.GetName(), name, env[name], value)
+			}
+		}
+		return nil
+	})
+}
+
+func TestCreateStatefulSetPodTemplateContainer(t *testing.T) {
+	template := builder.NewPodTemplate("custom", "clickhouse/clickhouse-server:23.8")
+	template.Container = &api.ChiPodTemplateContainer{
+		Command: []string{"/custom-entrypoint.sh"},
+		Args:    []string{"--verbose"},
+		ExtraVolumes: []core.Volume{
+			{
+				Name: "geoip",
+				VolumeSource: core.VolumeSource{
+					ConfigMap: &core.ConfigMapVolumeSource{
+						LocalObjectReference: core.LocalObjectReference{Name: "geoip"},
+					},
+				},
+			},
+		},
+		ExtraVolumeMounts: []core.VolumeMount{
+			{Name: "geoip", MountPath: "/var/lib/geoip"},
+		},
+	}
+	chi, c := newCreator(t, builder.NewCHI("test", "container",
+		builder.WithPodTemplates(template),
+		builder.WithCluster(builder.NewCluster("main", builder.WithPodTemplate("custom"))),
+	))
+
+	spec := &c.CreateStatefulSet(chi.FirstHost(), false).Spec.Template.Spec
+	container := getContainer(t, spec, model.ClickHouseContainerName)
+	if container.Image != "clickhouse/clickhouse-server:23.8" {
+		t.Errorf("image of the template is not kept: %s", container.Image)
+	}
+	if !reflect.DeepEqual(container.Command, []string{"/custom-entrypoint.sh"}) || !reflect.DeepEqual(container.Args, []string{"--verbose"}) {
+		t.Errorf("command and args are not customized: %v %v", container.Command, container.Args)
+	}
+	if container.LivenessProbe == nil {
+		t.Errorf("default probes are lost")
+	}
+
+	volumes := 0
+	for _, volume := range spec.Volumes {
+		if volume.Name == "geoip" {
+			volumes++
+		}
+	}
+	if volumes != 1 {
+		t.Errorf("got %d geoip volumes want 1", volumes)
+	}
+	mounted := false
+	for _, mount := range container.VolumeMounts {
+		mounted = mounted || (mount.Name == "geoip" && mount.MountPath == "/var/lib/geoip")
+	}
+	if !mounted {
+		t.Errorf("geoip volume is not mounted: %v", container.VolumeMounts)
+	}
+}
+
+func TestCreateStatefulSetLogging(t *testing.T) {
+	input := builder.NewCHI("test", "logging", builder.WithCluster(builder.NewCluster("main")))
+	input.Spec.Logging = &api.ChiLogging{Agent: api.LoggingAgentVector}
+	chi, c := newCreator(t, input)
+
+	spec := &c.CreateStatefulSet(chi.FirstHost(), false).Spec.Template.Spec
+	mounts := map[string]string{}
+	for _, name := range []string{model.ClickHouseContainerName, model.LoggingContainerName} {
+		for _, mount := range getContainer(t, spec, name).VolumeMounts {
+			if mount.MountPath == model.DirPathClickHouseLog {
+				mounts[name] = mount.Name
+			}
+		}
+	}
+	if (mounts[model.ClickHouseContainerName] == "") || (mounts[model.ClickHouseContainerName] != mounts[model.LoggingContainerName]) {
+		t.Errorf("logs are not shared with logging container: %v", mounts)
+	}
+}
+
+func TestCreateStatefulSetSystemLogVolume(t *testing.T) {
+	chi, c := newCreator(t, builder.NewCHI("test", "system-logs",
+		builder.WithVolumeClaimTemplates(
+			builder.NewVolumeClaimTemplate("data", resource.MustParse("100Gi")),
+			builder.NewVolumeClaimTemplate("system-logs", resource.MustParse("10Gi")),
+		),
+		builder.WithCluster(builder.NewCluster("main",
+			builder.WithDataVolumeClaimTemplate("data"),
+			builder.WithSystemLogVolumeClaimTemplate("system-logs"),
+		)),
+	))
+
+	statefulSet := c.CreateStatefulSet(chi.FirstHost(), false)
+	claimed := false
+	for _, claim := range statefulSet.Spec.VolumeClaimTemplates {
+		claimed = claimed || (claim.Name == "system-logs")
+	}
+	if !claimed {
+		t.Errorf("system log volume claim template is not used")
+	}
+	mounted := false
+	for _, mount := range getContainer(t, &statefulSet.Spec.Template.Spec, model.ClickHouseContainerName).VolumeMounts {
+		mounted = mounted || (mount.Name == "system-logs" && mount.MountPath == model.DirPathClickHouseSystemLogs)
+	}
+	if !mounted {
+		t.Errorf("system log volume is not mounted")
+	}
+}
+
+func TestCreateStatefulSetResources(t *testing.T) {
+	template := builder.NewPodTemplate("custom", "clickhouse/clickhouse-server:23.8")
+	template.Spec.Containers[0].Resources = core.ResourceRequirements{
+		Requests: core.ResourceList{
+			core.ResourceCPU:    resource.MustParse("1"),
+			core.ResourceMemory: resource.MustParse("4Gi"),
+		},
+	}
+	chi, c := newCreator(t, builder.NewCHI("test", "resources",
+		builder.WithPodTemplates(template),
+		builder.WithCluster(builder.NewCluster("main",
+			builder.WithPodTemplate("custom"),
+			builder.WithResources(core.ResourceRequirements{
+				Requests: core.ResourceList{
+					core.ResourceMemory: resource.MustParse("16Gi"),
+				},
+				Limits: core.ResourceList{
+					core.ResourceMemory: resource.MustParse("16Gi"),
+				},
+			}),
+		)),
+	))
+
+	statefulSet := c.CreateStatefulSet(chi.FirstHost(), false)
+	resources := getContainer(t, &statefulSet.Spec.Template.Spec, model.ClickHouseContainerName).Resources
+	for _, tt := range []struct {
+		name string
+		list core.ResourceList
+		key  core.ResourceName
+		want string
+	}{
+		{"requests.cpu", resources.Requests, core.ResourceCPU, "1"},
+		{"requests.memory", resources.Requests, core.ResourceMemory, "16Gi"},
+		{"limits.memory", resources.Limits, core.ResourceMemory, "16Gi"},
+	} {
+		got := tt.list[tt.key]
+		if got.Cmp(resource.MustParse(tt.want)) != 0 {
+			t.Errorf("%s: got %s want %s", tt.name, got.String(), tt.want)
+		}
+	}
+}
+
+func TestCreateStatefulSetCHILabels(t *testing.T) {
+	input := builder.NewCHI("test", "tenant", builder.WithCluster(builder.NewCluster("main")))
+	input.Labels = map[string]string{"tenant": "acme"}
+	chi, c := newCreator(t, input)
+
+	// CHI labels are propagated to pods, so anti-affinity of other CHIs is able to match them
+	statefulSet := c.CreateStatefulSet(chi.FirstHost(), false)
+	if labels := statefulSet.Spec.Template.Labels; labels["tenant"] != "acme" {
+		t.Errorf("tenant label is not propagated to pod: %v", labels)
+	}
+}
+
+func TestCreateStatefulSetHostResources(t *testing.T) {
+	canary := core.ResourceRequirements{
+		Requests: core.ResourceList{
+			core.ResourceCPU: resource.MustParse("8"),
+		},
+	}
+	createStatefulSets := func(options ...builder.ClusterOption) map[string]*apps.StatefulSet {
+		options = append([]builder.ClusterOption{
+			builder.WithReplicas(3),
+			builder.WithResources(core.ResourceRequirements{
+				Requests: core.ResourceList{
+					core.ResourceCPU:    resource.MustParse("2"),
+					core.ResourceMemory: resource.MustParse("8Gi"),
+				},
+			}),
+		}, options...)
+		chi, c := newCreator(t, builder.NewCHI("test", "canary", builder.WithCluster(builder.NewCluster("main", options...))))
+		statefulSets := make(map[string]*apps.StatefulSet)
+		chi.WalkHosts(func(host *api.ChiHost) error {
+			statefulSets[host.GetName()] = c.CreateStatefulSet(host, false)
+			return nil
+		})
+		return statefulSets
+	}
+	base := createStatefulSets()
+	overridden := createStatefulSets(builder.WithHostResources(0, 1, canary))
+
+	// Only the overridden host gets new resources and so is the only one to be updated
+	for name, statefulSet := range overridden {
+		requests := getContainer(t, &statefulSet.Spec.Template.Spec, model.ClickHouseContainerName).Resources.Requests
+		cpu, memory := requests[core.ResourceCPU], requests[core.ResourceMemory]
+		baseVersion, _ := model.GetObjectVersion(base[name].ObjectMeta)
+		version, _ := model.GetObjectVersion(statefulSet.ObjectMeta)
+		if name == "0-1" {
+			if cpu.String() != "8" || memory.String() != "8Gi" {
+				t.Errorf("host %s: got cpu %s memory %s want 8 and 8Gi", name, cpu.String(), memory.String())
+			}
+			if version == baseVersion {
+				t.Errorf("host %s: object version is not changed by resources override", name)
+			}
+		} else {
+			if cpu.String() != "2" || memory.String() != "8Gi" {
+				t.Errorf("host %s: got cpu %s memory %s want 2 and 8Gi", name, cpu.String(), memory.String())
+			}
+			if version != baseVersion {
+				t.Errorf("host %s: object version is changed by resources override of another host", name)
+			}
+		}
+	}
+	if len(overridden) != 3 {
+		t.Errorf("got %d hosts want 3", len(overridden))
+	}
+}
+
+func TestCreateStatefulSetCommonConfig(t *testing.T) {
+	chi, c := newCreator(t, builder.NewCHI("test", "settings", builder.WithCluster(builder.NewCluster("main"))))
+
+	name := model.CreateConfigMapCommonName(chi)
+	statefulSet := c.CreateStatefulSet(chi.FirstHost(), false)
+	mounted := false
+	for _, mount := range getContainer(t, &statefulSet.Spec.Template.Spec, model.ClickHouseContainerName).VolumeMounts {
+		mounted = mounted || (mount.Name == name && mount.MountPath == model.DirPathCommonConfig)
+	}
+	if !mounted {
+		t.Errorf("common config map is not mounted to %s", model.DirPathCommonConfig)
+	}
+}
+
+func TestCreateStatefulSetUseTemplates(t *testing.T) {
+	newTemplate := func(image string) *api.ClickHouseInstallation {
+		return builder.NewCHI("test", "standard",
+			builder.WithPodTemplates(builder.NewPodTemplate("standard-pod", image)),
+			builder.WithDefaultPodTemplate("standard-pod"),
+		)
+	}
+	getVersion := func(template *api.ClickHouseInstallation, useTemplates ...string) string {
+		chop.Config().AddCHITemplate(template)
+		defer chop.Config().DeleteCHITemplate(template)
+		chi, c := newCreator(t, builder.NewCHI("test", "shaped",
+			builder.WithUseTemplates(useTemplates...),
+			builder.WithCluster(builder.NewCluster("main")),
+		))
+		version, _ := model.GetObjectVersion(c.CreateStatefulSet(chi.FirstHost(), false).ObjectMeta)
+		return version
+	}
+
+	// Change of the template is a change of the stateful set of every CHI using the template
+	if getVersion(newTemplate("clickhouse/clickhouse-server:23.8"), "standard") == getVersion(newTemplate("clickhouse/clickhouse-server:24.3"), "standard") {
+		t.Errorf("object version is not changed by the template change")
+	}
+	if getVersion(newTemplate("clickhouse/clickhouse-server:23.8")) != getVersion(newTemplate("clickhouse/clickhouse-server:24.3")) {
+		t.Errorf("object version is changed by the change of the template not used")
+	}
+}
+
+func TestCreateStatefulSetStopped(t *testing.T) {
+	for _, stop := range []bool{false, true} {
+		chi, c := newCreator(t, builder.NewCHI("test", "stopped",
+			builder.WithCluster(builder.NewCluster("main", builder.WithShards(2))),
+			builder.WithStop(stop),
+		))
+		want := int32(1)
+		if stop {
+			want = 0
+		}
+		chi.WalkHosts(func(host *api.ChiHost) error {
+			statefulSet := c.CreateStatefulSet(host, false)
+			if (statefulSet.Spec.Replicas == nil) || (*statefulSet.Spec.Replicas != want) {
+				t.Errorf("stop %v: host %s: got replicas %v want %d", stop, host.GetName(), statefulSet.Spec.Replicas, want)
+			}
+			return nil
+		})
+	}
+}
+
+func TestCreateStatefulSetTroubleshoot(t *testing.T) {
+	input := builder.NewCHI("test", "troubleshoot",
+		builder.WithCluster(builder.NewCluster("main")),
+		builder.WithPodTemplates(builder.NewPodTemplate("probed", "clickhouse/clickhouse-server:23.8")),
+		builder.WithDefaultPodTemplate("probed"),
+	)
+	input.Spec.Troubleshoot = api.NewStringBool(true)
+	input.Spec.Templates.PodTemplates[0].Spec.Containers[0].StartupProbe = &core.Probe{InitialDelaySeconds: 10}
+	chi, c := newCreator(t, input)
+
+	statefulSet := c.CreateStatefulSet(chi.FirstHost(), false)
+	container := getContainer(t, &statefulSet.Spec.Template.Spec, model.ClickHouseContainerName)
+	if len(container.Command) != 3 || !strings.HasSuffix(container.Command[2], "|| sleep 1800") {
+		t.Errorf("unexpected troubleshoot command: %v", container.Command)
+	}
+	if container.LivenessProbe != nil || container.ReadinessProbe != nil || container.StartupProbe != nil {
+		t.Errorf("probes must be disabled in troubleshoot mode")
+	}
+}
+
+func TestCreateStatefulSetArchitecture(t *testing.T) {
+	input := builder.NewCHI("test", "arm",
+		builder.WithCluster(builder.NewCluster("main")),
+		builder.WithPodTemplates(builder.NewPodTemplate("arm", "clickhouse/clickhouse-server:23.8")),
+		builder.WithDefaultPodTemplate("arm"),
+	)
+	template := &input.Spec.Templates.PodTemplates[0]
+	template.Architecture = "ARM64"
+	template.Container = &api.ChiPodTemplateContainer{
+		Images: map[string]string{
+			"amd64": "clickhouse/clickhouse-server:23.8-amd64",
+			"arm64": "clickhouse/clickhouse-server:23.8-arm64",
+		},
+	}
+	chi, c := newCreator(t, input)
+
+	statefulSet := c.CreateStatefulSet(chi.FirstHost(), false)
+	if image := getContainer(t, &statefulSet.Spec.Template.Spec, model.ClickHouseContainerName).Image; image != "clickhouse/clickhouse-server:23.8-arm64" {
+		t.Errorf("unexpected image: %s", image)
+	}
+	if affinity := statefulSet.Spec.Template.Spec.Affinity; (affinity == nil) || (affinity.NodeAffinity == nil) {
+		t.Errorf("architecture is not applied: %v", affinity)
+	}
+}
+
+func TestCreateStatefulSetImagePullSecrets(t *testing.T) {
+	config := chop.Config()
+	saved := config.Pod.ImagePullSecrets
+	config.Pod.ImagePullSecrets = []core.LocalObjectReference{{Name: "registry"}, {Name: "mirror"}}
+	defer func() { config.Pod.ImagePullSecrets = saved }()
+
+	input := builder.NewCHI("test", "private",
+		builder.WithCluster(builder.NewCluster("main")),
+		builder.WithPodTemplates(builder.NewPodTemplate("private", "registry.example.com/clickhouse-server:23.8")),
+		builder.WithDefaultPodTemplate("private"),
+	)
+	input.Spec.Templates.PodTemplates[0].Spec.ImagePullSecrets = []core.LocalObjectReference{{Name: "mirror"}}
+	chi, c := newCreator(t, input)
+
+	// Secrets of the pod template go first, secrets of the same names are not duplicated
+	secrets := c.CreateStatefulSet(chi.FirstHost(), false).Spec.Template.Spec.ImagePullSecrets
+	if len(secrets) != 2 || secrets[0].Name != "mirror" || secrets[1].Name != "registry" {
+		t.Errorf("unexpected image pull secrets: %v", secrets)
+	}
+}
+
+func TestCreateStatefulSetHostAnnotations(t *testing.T) {
+	cluster := builder.NewCluster("main", builder.WithReplicas(2))
+	cluster.Layout.Replicas = []api.ChiReplica{
+		{},
+		{
+			Annotations: map[string]string{"scrape": "yes", "role": "{chi}-backup"},
+			Hosts: []*api.ChiHost{
+				{Annotations: map[string]string{"scrape": "no"}},
+			},
+		},
+	}
+	chi, c := newCreator(t, builder.NewCHI("test", "annotated", builder.WithCluster(cluster)))
+
+	// Host annotations override the ones of the replica, macros are expanded
+	chi.WalkHosts(func(host *api.ChiHost) error {
+		annotations := c.CreateStatefulSet(host, false).Spec.Template.Annotations
+		if host.Runtime.Address.ReplicaIndex == 0 {
+			if _, ok := annotations["scrape"]; ok {
+				t.Errorf("host %s: unexpected annotations: %v", host.GetName(), annotations)
+			}
+			return nil
+		}
+		if annotations["scrape"] != "no" || annotations["role"] != "annotated-backup" {
+			t.Errorf("host %s: unexpected annotations: %v", host.GetName(), annotations)
+		}
+		return nil
+	})
+}
+
+func TestCreateStatefulSetManagedTLS(t *testing.T) {
+	cluster := builder.NewCluster("main", builder.WithReplicas(2))
+	cluster.TLS = &api.ChiClusterTLS{Managed: api.NewStringBool(true)}
+	chi, c := newCreator(t, builder.NewCHI("test", "secure", builder.WithCluster(cluster)))
+
+	chi.WalkHosts(func(host *api.ChiHost) error {
+		statefulSet := c.CreateStatefulSet(host, false)
+		mounted := false
+		for _, mount := range getContainer(t, &statefulSet.Spec.Template.Spec, model.ClickHouseContainerName).VolumeMounts {
+			mounted = mounted || (mount.Name == "secure-main-tls" && mount.MountPath == model.DirPathTLS)
+		}
+		if !mounted {
+			t.Errorf("host %s: TLS secret is not mounted", host.GetName())
+		}
+		return nil
+	})
+}
+
+func TestCreateStatefulSetPriorityClassName(t *testing.T) {
+	pinned := builder.NewCluster("pinned")
+	pinned.Templates = &api.ChiTemplateNames{PodTemplate: "pinned"}
+	input := builder.NewCHI("test", "priority",
+		builder.WithCluster(builder.NewCluster("main")),
+		builder.WithCluster(builder.NewCluster("adhoc")),
+		builder.WithCluster(pinned),
+		builder.WithPodTemplates(builder.NewPodTemplate("pinned", "clickhouse/clickhouse-server:23.8")),
+	)
+	input.Spec.Defaults = &api.ChiDefaults{PriorityClassName: "high"}
+	input.Spec.Configuration.Clusters[1].PriorityClassName = "low"
+	input.Spec.Templates.PodTemplates[0].Spec.PriorityClassName = "pinned"
+	chi, c := newCreator(t, input)
+
+	// Pod template takes precedence over the cluster, the cluster over the CHI defaults
+	want := map[string]string{"main": "high", "adhoc": "low", "pinned": "pinned"}
+	chi.WalkHosts(func(host *api.ChiHost) error {
+		cluster := host.Runtime.Address.ClusterName
+		if got := c.CreateStatefulSet(host, false).Spec.Template.Spec.PriorityClassName; got != want[cluster] {
+			t.Errorf("cluster %s: got priority class %q want %q", cluster, got, want[cluster])
+		}
+		return nil
+	})
+}
+
+func TestCreateStatefulSetSidecars(t *testing.T) {
+	template := builder.NewPodTemplate("custom", "clickhouse/clickhouse-server:23.8")
+	template.Container = &api.ChiPodTemplateContainer{
+		ExtraVolumes: []core.Volume{
+			{Name: "scratch", VolumeSource: core.VolumeSource{EmptyDir: &core.EmptyDirVolumeSource{}}},
+		},
+		ExtraVolumeMounts: []core.VolumeMount{
+			{Name: "scratch", MountPath: "/var/lib/clickhouse/backup"},
+		},
+	}
+	template.Sidecars = []core.Container{
+		{
+			Name:         "backup",
+			Image:        "altinity/clickhouse-backup:2.4.0",
+			VolumeMounts: []core.VolumeMount{{Name: "scratch", MountPath: "/backup"}},
+		},
+		{Image: "chproxy:latest"},
+		{Name: "broken"},
+	}
+	chi, c := newCreator(t, builder.NewCHI("test", "sidecars",
+		builder.WithPodTemplates(template),
+		builder.WithVolumeClaimTemplates(builder.NewVolumeClaimTemplate("data", resource.MustParse("10Gi"))),
+		builder.WithCluster(builder.NewCluster("main",
+			builder.WithPodTemplate("custom"),
+			builder.WithDataVolumeClaimTemplate("data"),
+		)),
+	))
+
+	// Sidecars without name are named by position, sidecars without image are skipped
+	spec := &c.CreateStatefulSet(chi.FirstHost(), false).Spec.Template.Spec
+	if len(spec.Containers) != 3 {
+		t.Fatalf("unexpected containers: %v", spec.Containers)
+	}
+	getContainer(t, spec, "sidecar-1")
+	mounts := map[string]string{}
+	for _, mount := range getContainer(t, spec, "backup").VolumeMounts {
+		mounts[mount.MountPath] = mount.Name
+	}
+	if mounts["/backup"] != "scratch" {
+		t.Errorf("scratch volume is not shared with sidecar: %v", mounts)
+	}
+	if mounts[model.DirPathClickHouseData] != "data" {
+		t.Errorf("data volume is not shared with sidecar: %v", mounts)
+	}
+	if mounts[model.DirPathCommonConfig] == "" {
+		t.Errorf("configuration is not shared with sidecar: %v", mounts)
+	}
+}
+
+func TestCreateStatefulSetInitContainers(t *testing.T) {
+	template := builder.NewPodTemplate("custom", "clickhouse/clickhouse-server:23.8")
+	template.Spec.InitContainers = []core.Container{{Name: "explicit", Image: "busybox:1.36"}}
+	template.InitContainers = []core.Container{
+		{Name: "fix-permissions", Image: "busybox:1.36", Command: []string{"chown", "-R", "101:101", "/var/lib/clickhouse"}},
+		{Image: "schema-seeder:latest"},
+		{Name: "explicit", Image: "busybox:1.36"},
+		{Name: "broken"},
+	}
+	chi, c := newCreator(t, builder.NewCHI("test", "init",
+		builder.WithPodTemplates(template),
+		builder.WithVolumeClaimTemplates(builder.NewVolumeClaimTemplate("data", resource.MustParse("10Gi"))),
+		builder.WithCluster(builder.NewCluster("main",
+			builder.WithPodTemplate("custom"),
+			builder.WithDataVolumeClaimTemplate("data"),
+		)),
+	))
+
+	// Explicit init containers go first, duplicates and init containers without image are skipped
+	spec := c.CreateStatefulSet(chi.FirstHost(), false).Spec.Template.Spec
+	var names []string
+	for _, container := range spec.InitContainers {
+		names = append(names, container.Name)
+	}
+	if want := []string{"explicit", "fix-permissions", "init-1"}; !reflect.DeepEqual(names, want) {
+		t.Fatalf("got init containers %v want %v", names, want)
+	}
+	for _, container := range spec.InitContainers {
+		mounts := map[string]string{}
+		for _, mount := range container.VolumeMounts {
+			mounts[mount.MountPath] = mount.Name
+		}
+		if mounts[model.DirPathClickHouseData] != "data" {
+			t.Errorf("data volume is not mounted into init container %s: %v", container.Name, mounts)
+		}
+		if mounts[model.DirPathCommonConfig] == "" {
+			t.Errorf("configuration is not mounted into init container %s: %v", container.Name, mounts)
+		}
+	}
+}
+
+func TestCreateStatefulSetHostNetwork(t *testing.T) {
+	input := builder.NewCHI("test", "hostnet",
+		builder.WithCluster(builder.NewCluster("main", builder.WithReplicas(2))),
+		builder.WithCluster(builder.NewCluster("adhoc")),
+	)
+	input.Spec.Defaults = &api.ChiDefaults{HostNetwork: api.NewStringBool(true)}
+	input.Spec.Configuration.Clusters[1].HostNetwork = api.NewStringBool(false)
+	chi, c := newCreator(t, input)
+
+	// Hosts in host network do not share ports, since they may land on the same node
+	ports := map[int32]bool{}
+	chi.WalkHosts(func(host *api.ChiHost) error {
+		spec := &c.CreateStatefulSet(host, false).Spec.Template.Spec
+		hostNetwork := host.Runtime.Address.ClusterName == "main"
+		if spec.HostNetwork != hostNetwork {
+			t.Errorf("host %s: got hostNetwork %v want %v", host.GetName(), spec.HostNetwork, hostNetwork)
+		}
+		if !hostNetwork {
+			return nil
+		}
+		if spec.DNSPolicy != core.DNSClusterFirstWithHostNet {
+			t.Errorf("host %s: got dnsPolicy %s want %s", host.GetName(), spec.DNSPolicy, core.DNSClusterFirstWithHostNet)
+		}
+		for _, port := range getContainer(t, spec, model.ClickHouseContainerName).Ports {
+			if ports[port.ContainerPort] {
+				t.Errorf("host %s: port %d is shared with another host in host network", host.GetName(), port.ContainerPort)
+			}
+			ports[port.ContainerPort] = true
+		}
+		return nil
+	})
+	if len(ports) == 0 {
+		t.Errorf("no hosts in host network are created")
+	}
+}
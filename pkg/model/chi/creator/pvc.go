@@ -76,6 +76,18 @@ func (c *Creator) CreatePVC(name string, host *api.ChiHost, spec *core.Persisten
 	return &pvc
 }
 
+// CreateForkPVC creates PVC restored from the volume snapshot
+func (c *Creator) CreateForkPVC(name string, host *api.ChiHost, spec *core.PersistentVolumeClaimSpec, snapshotName string) *core.PersistentVolumeClaim {
+	pvc := c.CreatePVC(name, host, spec)
+	apiGroup := VolumeSnapshotGroupVersionResource.Group
+	pvc.Spec.DataSource = &core.TypedLocalObjectReference{
+		APIGroup: &apiGroup,
+		Kind:     VolumeSnapshotKind,
+		Name:     snapshotName,
+	}
+	return pvc
+}
+
 // OperatorShouldCreatePVC checks whether operator should create PVC for specified volumeCLimaTemplate
 func OperatorShouldCreatePVC(host *api.ChiHost, volumeClaimTemplate *api.ChiVolumeClaimTemplate) bool {
 	return model.GetPVCProvisioner(host, volumeClaimTemplate) == api.PVCProvisionerOperator
@@ -15,18 +15,26 @@
 package creator
 
 import (
+	"time"
+
 	core "k8s.io/api/core/v1"
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	log "github.com/altinity/clickhouse-operator/pkg/announcer"
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	model "github.com/altinity/clickhouse-operator/pkg/model/chi"
 	"github.com/altinity/clickhouse-operator/pkg/util"
 )
 
-// CreateClusterSecret creates cluster secret
-func (c *Creator) CreateClusterSecret(name string) *core.Secret {
+// CreateClusterSecret creates cluster secret.
+// Secret is labeled and owned by the CHI, so it is pruned along with the cluster.
+func (c *Creator) CreateClusterSecret(cluster *api.Cluster) *core.Secret {
 	return &core.Secret{
 		ObjectMeta: meta.ObjectMeta{
-			Namespace: c.chi.Namespace,
-			Name:      name,
+			Namespace:       c.chi.Namespace,
+			Name:            model.CreateClusterAutoSecretName(cluster),
+			Labels:          model.Macro(c.chi).Map(c.labels.GetSecretCluster(cluster)),
+			OwnerReferences: getOwnerReferences(c.chi),
 		},
 		StringData: map[string]string{
 			"secret": util.RandStringRange(10, 20),
@@ -34,3 +42,40 @@ func (c *Creator) CreateClusterSecret(name string) *core.Secret {
 		Type: core.SecretTypeOpaque,
 	}
 }
+
+// CreateClusterTLSSecret creates Secret with certificates of the cluster issued by the operator.
+// Secret is labeled and owned by the CHI, so it is pruned along with the cluster.
+func (c *Creator) CreateClusterTLSSecret(cluster *api.Cluster) *core.Secret {
+	data, err := model.CreateClusterCertificate(cluster, nil, time.Now())
+	if err != nil {
+		log.V(1).M(cluster).F().Error("unable to issue certificate of the cluster: %s err: %v", cluster.Name, err)
+		return nil
+	}
+	return &core.Secret{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace:       c.chi.Namespace,
+			Name:            model.CreateClusterTLSSecretName(cluster),
+			Labels:          model.Macro(c.chi).Map(c.labels.GetSecretClusterTLS(cluster)),
+			OwnerReferences: getOwnerReferences(c.chi),
+		},
+		Data: data,
+		Type: core.SecretTypeTLS,
+	}
+}
+
+// CreateSecretConnection creates Secret with connection details of the CHI,
+// so applications can mount it instead of relying on operator naming rules.
+// Data is kept up to date on each reconcile.
+func (c *Creator) CreateSecretConnection() *core.Secret {
+	return &core.Secret{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace:       c.chi.Namespace,
+			Name:            model.CreateConnectionSecretName(c.chi),
+			Labels:          model.Macro(c.chi).Map(c.labels.GetSecretConnection()),
+			Annotations:     model.Macro(c.chi).Map(c.annotations.GetSecretConnection()),
+			OwnerReferences: getOwnerReferences(c.chi),
+		},
+		StringData: model.CreateConnectionDetails(c.chi),
+		Type:       core.SecretTypeOpaque,
+	}
+}
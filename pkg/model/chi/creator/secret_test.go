@@ -0,0 +1,54 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package creator_test
+
+import (
+	"testing"
+
+	core "k8s.io/api/core/v1"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	model "github.com/altinity/clickhouse-operator/pkg/model/chi"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/builder"
+)
+
+func TestCreateSecretConnection(t *testing.T) {
+	_, c := newCreator(t, builder.NewCHI("test", "apps", builder.WithCluster(builder.NewCluster("main"))))
+
+	secret := c.CreateSecretConnection()
+	if secret.Name != "chi-apps-connection" {
+		t.Errorf("got secret %s want chi-apps-connection", secret.Name)
+	}
+	if got := secret.StringData[model.ConnectionKeyHost]; got != "clickhouse-apps.test.svc.cluster.local" {
+		t.Errorf("got host %q want clickhouse-apps.test.svc.cluster.local", got)
+	}
+}
+
+func TestCreateClusterTLSSecret(t *testing.T) {
+	cluster := builder.NewCluster("main", builder.WithReplicas(2))
+	cluster.TLS = &api.ChiClusterTLS{Managed: api.NewStringBool(true)}
+	chi, c := newCreator(t, builder.NewCHI("test", "secure", builder.WithCluster(cluster)))
+
+	secret := c.CreateClusterTLSSecret(chi.FindCluster("main"))
+	if secret == nil {
+		t.Fatalf("TLS secret is not created")
+	}
+	if secret.Name != "secure-main-tls" {
+		t.Errorf("got secret %s want secure-main-tls", secret.Name)
+	}
+	if secret.Type != core.SecretTypeTLS || len(secret.Data[core.TLSCertKey]) == 0 || len(secret.Data[core.TLSPrivateKeyKey]) == 0 {
+		t.Errorf("unexpected TLS secret: %v", secret)
+	}
+}
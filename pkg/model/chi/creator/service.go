@@ -74,6 +74,9 @@ func (c *Creator) CreateServiceCHI() *core.Service {
 			// ExternalTrafficPolicy: core.ServiceExternalTrafficPolicyTypeLocal, // For core.ServiceTypeLoadBalancer only
 		},
 	}
+	if hasSecureHosts(c.chi.WalkHosts) {
+		appendServiceSecurePorts(svc)
+	}
 	model.MakeObjectVersion(&svc.ObjectMeta, svc)
 	return svc
 }
@@ -101,6 +104,86 @@ func (c *Creator) CreateServiceCluster(cluster *api.Cluster) *core.Service {
 	return nil
 }
 
+// CreateServiceClusterReadTier creates new core.Service for read tier hosts of specified Cluster
+func (c *Creator) CreateServiceClusterReadTier(cluster *api.Cluster) *core.Service {
+	if !cluster.HasReadTier() {
+		// No read tier hosts, no need to create service
+		return nil
+	}
+
+	svc := &core.Service{
+		ObjectMeta: meta.ObjectMeta{
+			Name:            model.CreateClusterReadTierServiceName(cluster),
+			Namespace:       cluster.Runtime.Address.Namespace,
+			Labels:          model.Macro(cluster).Map(c.labels.GetServiceClusterReadTier(cluster)),
+			Annotations:     model.Macro(cluster).Map(c.annotations.GetServiceCluster(cluster)),
+			OwnerReferences: getOwnerReferences(c.chi),
+		},
+		Spec: core.ServiceSpec{
+			ClusterIP: model.TemplateDefaultsServiceClusterIP,
+			Ports: []core.ServicePort{
+				{
+					Name:       model.ChDefaultHTTPPortName,
+					Protocol:   core.ProtocolTCP,
+					Port:       model.ChDefaultHTTPPortNumber,
+					TargetPort: intstr.FromString(model.ChDefaultHTTPPortName),
+				},
+				{
+					Name:       model.ChDefaultTCPPortName,
+					Protocol:   core.ProtocolTCP,
+					Port:       model.ChDefaultTCPPortNumber,
+					TargetPort: intstr.FromString(model.ChDefaultTCPPortName),
+				},
+			},
+			Selector: model.GetSelectorClusterScopeReadTierReady(cluster),
+			Type:     core.ServiceTypeClusterIP,
+		},
+	}
+	if hasSecureHosts(cluster.WalkHosts) {
+		appendServiceSecurePorts(svc)
+	}
+	model.MakeObjectVersion(&svc.ObjectMeta, svc)
+	return svc
+}
+
+// CreateServiceClusterReader creates new core.Service for all replicas of specified Cluster
+func (c *Creator) CreateServiceClusterReader(cluster *api.Cluster) *core.Service {
+	if template, ok := cluster.GetReaderServiceTemplate(); ok {
+		// .templates.ServiceTemplate specified
+		return c.createServiceFromTemplate(
+			template,
+			cluster.Runtime.Address.Namespace,
+			model.CreateClusterReaderServiceName(cluster),
+			c.labels.GetServiceClusterReader(cluster),
+			c.annotations.GetServiceCluster(cluster),
+			model.GetSelectorClusterScopeReady(cluster),
+			getOwnerReferences(c.chi),
+			model.Macro(cluster),
+		)
+	}
+	// No template specified, no need to create service
+	return nil
+}
+
+// CreateServiceClusterWriter creates new core.Service for one replica per shard of specified Cluster
+func (c *Creator) CreateServiceClusterWriter(cluster *api.Cluster) *core.Service {
+	if template, ok := cluster.GetWriterServiceTemplate(); ok {
+		// .templates.ServiceTemplate specified
+		return c.createServiceFromTemplate(
+			template,
+			cluster.Runtime.Address.Namespace,
+			model.CreateClusterWriterServiceName(cluster),
+			c.labels.GetServiceClusterWriter(cluster),
+			c.annotations.GetServiceCluster(cluster),
+			model.GetSelectorClusterScopeWriterReady(cluster),
+			getOwnerReferences(c.chi),
+			model.Macro(cluster),
+		)
+	}
+	// No template specified, no need to create service
+	return nil
+}
+
 // CreateServiceShard creates new core.Service for specified Shard
 func (c *Creator) CreateServiceShard(shard *api.ChiShard) *core.Service {
 	if template, ok := shard.GetServiceTemplate(); ok {
@@ -124,7 +207,7 @@ func (c *Creator) CreateServiceShard(shard *api.ChiShard) *core.Service {
 func (c *Creator) CreateServiceHost(host *api.ChiHost) *core.Service {
 	if template, ok := host.GetServiceTemplate(); ok {
 		// .templates.ServiceTemplate specified
-		return c.createServiceFromTemplate(
+		svc := c.createServiceFromTemplate(
 			template,
 			host.Runtime.Address.Namespace,
 			model.CreateStatefulSetServiceName(host),
@@ -134,6 +217,10 @@ func (c *Creator) CreateServiceHost(host *api.ChiHost) *core.Service {
 			getOwnerReferences(c.chi),
 			model.Macro(host),
 		)
+		if applyAllocatedNodePorts(svc, host) {
+			model.MakeObjectVersion(&svc.ObjectMeta, svc)
+		}
+		return svc
 	}
 
 	// Create default Service
@@ -158,6 +245,34 @@ func (c *Creator) CreateServiceHost(host *api.ChiHost) *core.Service {
 	return svc
 }
 
+// hasSecureHosts checks whether any of the walked hosts is secure
+func hasSecureHosts(walk func(f func(host *api.ChiHost) error) []error) bool {
+	secure := false
+	walk(func(host *api.ChiHost) error {
+		secure = secure || host.IsSecure()
+		return nil
+	})
+	return secure
+}
+
+// appendServiceSecurePorts appends default secure native and HTTPS ports to the default service
+func appendServiceSecurePorts(service *core.Service) {
+	service.Spec.Ports = append(service.Spec.Ports,
+		core.ServicePort{
+			Name:       model.ChDefaultHTTPSPortName,
+			Protocol:   core.ProtocolTCP,
+			Port:       model.ChDefaultHTTPSPortNumber,
+			TargetPort: intstr.FromString(model.ChDefaultHTTPSPortName),
+		},
+		core.ServicePort{
+			Name:       model.ChDefaultTLSPortName,
+			Protocol:   core.ProtocolTCP,
+			Port:       model.ChDefaultTLSPortNumber,
+			TargetPort: intstr.FromString(model.ChDefaultTLSPortName),
+		},
+	)
+}
+
 func appendServicePorts(service *core.Service, host *api.ChiHost) {
 	// Walk over all assigned ports of the host and append each port to the list of service's ports
 	model.HostWalkAssignedPorts(
@@ -178,6 +293,32 @@ func appendServicePorts(service *core.Service, host *api.ChiHost) {
 	)
 }
 
+// applyAllocatedNodePorts exposes allocated ports of the host on the same node ports in case of NodePort service.
+// Service ports are matched to ports of the host by name. Returns whether service is modified
+func applyAllocatedNodePorts(service *core.Service, host *api.ChiHost) bool {
+	if (service == nil) || !host.Runtime.PortsAllocated || (service.Spec.Type != core.ServiceTypeNodePort) {
+		return false
+	}
+	modified := false
+	model.HostWalkAssignedPorts(
+		host,
+		func(name string, port *int32, protocol core.Protocol) bool {
+			for i := range service.Spec.Ports {
+				servicePort := &service.Spec.Ports[i]
+				if servicePort.Name == name {
+					servicePort.Port = *port
+					servicePort.TargetPort = intstr.FromInt(int(*port))
+					servicePort.NodePort = *port
+					modified = true
+				}
+			}
+			// Do not abort, continue iterating
+			return false
+		},
+	)
+	return modified
+}
+
 // createServiceFromTemplate create Service from ChiServiceTemplate and additional info
 func (c *Creator) createServiceFromTemplate(
 	template *api.ChiServiceTemplate,
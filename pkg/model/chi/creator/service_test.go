@@ -0,0 +1,179 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package creator_test
+
+import (
+	"testing"
+
+	core "k8s.io/api/core/v1"
+	k8sLabels "k8s.io/apimachinery/pkg/labels"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/apis/deployment"
+	model "github.com/altinity/clickhouse-operator/pkg/model/chi"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/builder"
+)
+
+func TestCreateServiceClusterReadTier(t *testing.T) {
+	cluster := builder.NewCluster("main",
+		builder.WithShards(2),
+		builder.WithReplicas(2),
+		builder.WithReadTierReplicas(1),
+	)
+	chi, c := newCreator(t, builder.NewCHI("test", "tiers", builder.WithCluster(cluster)))
+
+	service := c.CreateServiceClusterReadTier(chi.FindCluster("main"))
+	if service == nil {
+		t.Fatalf("read tier service is not created")
+	}
+	if service.Name != "cluster-tiers-main-read" {
+		t.Errorf("got read tier service %s want cluster-tiers-main-read", service.Name)
+	}
+	if service.Spec.Selector[model.LabelTier] != api.HostTierRead {
+		t.Errorf("read tier service selector %v does not select read tier", service.Spec.Selector)
+	}
+
+	selector := k8sLabels.SelectorFromSet(service.Spec.Selector)
+	chi.WalkHosts(func(host *api.ChiHost) error {
+		statefulSet := c.CreateStatefulSet(host, false)
+		want := host.Runtime.Address.ReplicaIndex == 1
+		if got := selector.Matches(k8sLabels.Set(statefulSet.Spec.Template.Labels)); got != want {
+			t.Errorf("host %s: selected by read tier service %v want %v", host.GetName(), got, want)
+		}
+		if _, ok := statefulSet.Spec.Selector.MatchLabels[model.LabelTier]; ok {
+			t.Errorf("host %s: tier label must not be a part of stateful set selector", host.GetName())
+		}
+		return nil
+	})
+}
+
+func TestCreateServiceClusterReaderWriter(t *testing.T) {
+	cluster := builder.NewCluster("main",
+		builder.WithShards(2),
+		builder.WithReplicas(3),
+		builder.WithReadTierReplicas(0),
+		builder.WithReaderServiceTemplate("reader"),
+		builder.WithWriterServiceTemplate("writer"),
+	)
+	chi, c := newCreator(t, builder.NewCHI("test", "rw",
+		builder.WithCluster(cluster),
+		builder.WithServiceTemplates(builder.NewServiceTemplate("reader"), builder.NewServiceTemplate("writer")),
+	))
+	cluster = chi.FindCluster("main")
+
+	reader := c.CreateServiceClusterReader(cluster)
+	if (reader == nil) || (reader.Name != "cluster-rw-main-reader") {
+		t.Fatalf("reader service is not created: %v", reader)
+	}
+	if _, ok := reader.Spec.Selector[model.LabelWriter]; ok {
+		t.Errorf("reader service selector %v must select all replicas", reader.Spec.Selector)
+	}
+	writer := c.CreateServiceClusterWriter(cluster)
+	if (writer == nil) || (writer.Name != "cluster-rw-main-writer") {
+		t.Fatalf("writer service is not created: %v", writer)
+	}
+	if writer.Spec.Selector[model.LabelWriter] != model.LabelWriterValueWriter {
+		t.Errorf("writer service selector %v does not select writers", writer.Spec.Selector)
+	}
+
+	// Replica 0 is of the read tier, so replica 1 of each shard is the writer
+	selector := k8sLabels.SelectorFromSet(writer.Spec.Selector)
+	chi.WalkHosts(func(host *api.ChiHost) error {
+		labels := k8sLabels.Set(c.CreateStatefulSet(host, false).Spec.Template.Labels)
+		want := host.Runtime.Address.ReplicaIndex == 1
+		if got := selector.Matches(labels); got != want {
+			t.Errorf("host %s: selected by writer service %v want %v", host.GetName(), got, want)
+		}
+		return nil
+	})
+}
+
+func TestCreateServiceHostAllocatedNodePorts(t *testing.T) {
+	service := builder.NewServiceTemplate("node-port")
+	service.Spec.Type = core.ServiceTypeNodePort
+	chi, c := newCreator(t, builder.NewCHI("test", "np",
+		builder.WithHostTemplates(api.ChiHostTemplate{
+			Name:             "allocated",
+			PortDistribution: []api.ChiPortDistribution{{Type: deployment.PortDistributionAllocated}},
+			Spec:             api.ChiHost{TCPPort: 30000, HTTPPort: 31000},
+		}),
+		builder.WithServiceTemplates(service),
+		builder.WithCluster(builder.NewCluster("main",
+			builder.WithShards(2),
+			builder.WithHostTemplate("allocated"),
+			builder.WithReplicaServiceTemplate("node-port"),
+		)),
+	))
+
+	// Allocated ports are exposed as node ports of the same numbers
+	want := map[string]map[string]int32{
+		"0-0": {model.ChDefaultTCPPortName: 30000, model.ChDefaultHTTPPortName: 31000},
+		"1-0": {model.ChDefaultTCPPortName: 30001, model.ChDefaultHTTPPortName: 31001},
+	}
+	chi.WalkHosts(func(host *api.ChiHost) error {
+		ports := want[host.GetName()]
+		for _, port := range c.CreateServiceHost(host).Spec.Ports {
+			if (port.NodePort != ports[port.Name]) || (port.Port != ports[port.Name]) {
+				t.Errorf("host %s: port %s: got %d/%d want %d", host.GetName(), port.Name, port.Port, port.NodePort, ports[port.Name])
+			}
+		}
+		return nil
+	})
+}
+
+func TestCreateServiceHostLevelPorts(t *testing.T) {
+	cluster := builder.NewCluster("main", builder.WithShards(2), builder.WithReplicas(2))
+	cluster.TCPPort = 9100
+	cluster.Layout.Replicas = []api.ChiReplica{
+		{},
+		{HostPorts: api.HostPorts{TCPPort: 9200, HTTPPort: 8200}},
+	}
+	chi, c := newCreator(t, builder.NewCHI("test", "ports", builder.WithCluster(cluster)))
+
+	want := map[int]map[string]int32{
+		0: {model.ChDefaultTCPPortName: 9100, model.ChDefaultHTTPPortName: model.ChDefaultHTTPPortNumber},
+		1: {model.ChDefaultTCPPortName: 9200, model.ChDefaultHTTPPortName: 8200},
+	}
+	chi.WalkHosts(func(host *api.ChiHost) error {
+		ports := want[host.Runtime.Address.ReplicaIndex]
+		for _, port := range c.CreateServiceHost(host).Spec.Ports {
+			if expected, ok := ports[port.Name]; ok && (port.Port != expected) {
+				t.Errorf("host %s: port %s: got %d want %d", host.GetName(), port.Name, port.Port, expected)
+			}
+		}
+		return nil
+	})
+}
+
+func TestCreateServiceSecureCluster(t *testing.T) {
+	cluster := builder.NewCluster("main", builder.WithReplicas(2))
+	cluster.Secure = api.NewStringBool(true)
+	chi, c := newCreator(t, builder.NewCHI("test", "secure", builder.WithCluster(cluster)))
+
+	services := map[string]*core.Service{"CHI": c.CreateServiceCHI()}
+	chi.WalkHosts(func(host *api.ChiHost) error {
+		services["host "+host.GetName()] = c.CreateServiceHost(host)
+		return nil
+	})
+	for name, service := range services {
+		secure := false
+		for _, port := range service.Spec.Ports {
+			secure = secure || ((port.Name == model.ChDefaultTLSPortName) && (port.Port == model.ChDefaultTLSPortNumber))
+		}
+		if !secure {
+			t.Errorf("service %s does not expose secure port", name)
+		}
+	}
+}
@@ -15,6 +15,8 @@
 package creator
 
 import (
+	"strconv"
+
 	apps "k8s.io/api/apps/v1"
 	core "k8s.io/api/core/v1"
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -26,6 +28,9 @@ import (
 	"github.com/altinity/clickhouse-operator/pkg/util"
 )
 
+// loggingLogsVolumeName specifies name of the volume ClickHouse logs are shared with the log shipping agent on
+const loggingLogsVolumeName = "clickhouse-logging-logs"
+
 // CreateStatefulSet creates new apps.StatefulSet
 func (c *Creator) CreateStatefulSet(host *api.ChiHost, shutdown bool) *apps.StatefulSet {
 	statefulSet := &apps.StatefulSet{
@@ -72,6 +77,11 @@ func (c *Creator) setupStatefulSetPodTemplate(statefulSet *apps.StatefulSet, hos
 
 	// Post-process StatefulSet
 	ensureStatefulSetTemplateIntegrity(statefulSet, host)
+	applyPodTemplateContainer(statefulSet, podTemplate)
+	applyPodTemplateSidecars(statefulSet, podTemplate)
+	applyPodTemplateInitContainers(statefulSet, podTemplate)
+	applyCoreDumpPattern(statefulSet, host)
+	applyHostResources(statefulSet, host)
 	setupEnvVars(statefulSet, host)
 	c.personalizeStatefulSetTemplate(statefulSet, host)
 }
@@ -83,6 +93,103 @@ func ensureStatefulSetTemplateIntegrity(statefulSet *apps.StatefulSet, host *api
 	ensureNamedPortsSpecified(statefulSet, host)
 }
 
+// applyPodTemplateContainer applies container customization of the pod template to clickhouse container
+func applyPodTemplateContainer(statefulSet *apps.StatefulSet, podTemplate *api.ChiPodTemplate) {
+	customization := podTemplate.Container
+	if customization == nil {
+		return
+	}
+	container, ok := getMainContainer(statefulSet)
+	if !ok {
+		return
+	}
+
+	if image, ok := customization.Images[podTemplate.Architecture]; ok && (podTemplate.Architecture != "") {
+		container.Image = image
+	}
+	if len(customization.Command) > 0 {
+		container.Command = append([]string{}, customization.Command...)
+	}
+	if len(customization.Args) > 0 {
+		container.Args = append([]string{}, customization.Args...)
+	}
+	for i := range customization.ExtraVolumes {
+		volume := customization.ExtraVolumes[i]
+		if !k8s.StatefulSetHasVolumeByName(statefulSet, volume.Name) {
+			k8s.StatefulSetAppendVolumes(statefulSet, *volume.DeepCopy())
+		}
+	}
+	k8s.ContainerAppendVolumeMounts(container, customization.ExtraVolumeMounts...)
+}
+
+// applyPodTemplateSidecars appends sidecar containers of the pod template to the Pod.
+// Sidecars are appended before volumes are set up, so config, data and log volumes are mounted into them as well
+func applyPodTemplateSidecars(statefulSet *apps.StatefulSet, podTemplate *api.ChiPodTemplate) {
+	for i := range podTemplate.Sidecars {
+		sidecar := &podTemplate.Sidecars[i]
+		if _, ok := k8s.StatefulSetContainerGet(statefulSet, sidecar.Name, -1); ok {
+			// Container of the same name is specified in the spec explicitly
+			continue
+		}
+		k8s.PodSpecAddContainer(&statefulSet.Spec.Template.Spec, *sidecar.DeepCopy())
+	}
+}
+
+// applyPodTemplateInitContainers appends init containers of the pod template to the Pod, so they run
+// before ClickHouse container starts. Config, data and log volumes are mounted into init containers as well
+func applyPodTemplateInitContainers(statefulSet *apps.StatefulSet, podTemplate *api.ChiPodTemplate) {
+	for i := range podTemplate.InitContainers {
+		initContainer := &podTemplate.InitContainers[i]
+		if k8s.PodSpecHasContainer(&statefulSet.Spec.Template.Spec, initContainer.Name) {
+			// Container of the same name is specified in the spec explicitly
+			continue
+		}
+		k8s.PodSpecAddInitContainer(&statefulSet.Spec.Template.Spec, *initContainer.DeepCopy())
+	}
+}
+
+// applyCoreDumpPattern appends init container pointing node's kernel.core_pattern into core dumps volume.
+// kernel.core_pattern is not namespaced, so the container has to be privileged and the pattern applies to the whole node
+func applyCoreDumpPattern(statefulSet *apps.StatefulSet, host *api.ChiHost) {
+	if !host.GetCHI().Spec.Debug.IsCoreDumpPattern() {
+		return
+	}
+	if k8s.PodSpecHasContainer(&statefulSet.Spec.Template.Spec, model.CoreDumpPatternContainerName) {
+		// Container of the same name is specified in the spec explicitly
+		return
+	}
+	container, ok := getMainContainer(statefulSet)
+	if !ok {
+		return
+	}
+
+	privileged := true
+	k8s.PodSpecAddInitContainer(&statefulSet.Spec.Template.Spec, core.Container{
+		Name:  model.CoreDumpPatternContainerName,
+		Image: container.Image,
+		Command: []string{
+			"/bin/sh",
+			"-c",
+			"echo '" + model.CoreDumpPattern + "' > /proc/sys/kernel/core_pattern",
+		},
+		SecurityContext: &core.SecurityContext{
+			Privileged: &privileged,
+		},
+	})
+}
+
+// applyHostResources applies resources specified for the host in CHI spec to clickhouse container
+func applyHostResources(statefulSet *apps.StatefulSet, host *api.ChiHost) {
+	if host.Resources == nil {
+		return
+	}
+	container, ok := getMainContainer(statefulSet)
+	if !ok {
+		return
+	}
+	container.Resources = *api.MergeResourceRequirements(host.Resources.DeepCopy(), &container.Resources)
+}
+
 // setupEnvVars setup ENV vars for clickhouse container
 func setupEnvVars(statefulSet *apps.StatefulSet, host *api.ChiHost) {
 	container, ok := getMainContainer(statefulSet)
@@ -91,6 +198,45 @@ func setupEnvVars(statefulSet *apps.StatefulSet, host *api.ChiHost) {
 	}
 
 	container.Env = append(container.Env, host.GetCHI().EnsureRuntime().EnsureAttributes().AdditionalEnvVars...)
+	container.Env = append(container.Env, getWriteReliabilityEnvVars(host)...)
+}
+
+// getWriteReliabilityEnvVars creates ENV vars carrying write reliability settings of the host's cluster
+// into the default profile shared by all hosts. Hosts of clusters without such settings get ClickHouse defaults
+func getWriteReliabilityEnvVars(host *api.ChiHost) []core.EnvVar {
+	if !host.GetCHI().HasWriteReliability() {
+		return nil
+	}
+
+	insertQuorum := "0"
+	insertQuorumTimeout := model.InsertQuorumTimeoutDefault
+	selectSequentialConsistency := "0"
+	if cluster := host.GetCluster(); (cluster != nil) && (cluster.WriteReliability != nil) {
+		replicasCount := len(cluster.RemoteReplicas)
+		if shard := host.GetShard(); shard != nil {
+			replicasCount += shard.ReplicasCount
+		}
+		insertQuorum = cluster.WriteReliability.GetInsertQuorum(replicasCount)
+		if cluster.WriteReliability.InsertQuorumTimeout != nil {
+			insertQuorumTimeout = *cluster.WriteReliability.InsertQuorumTimeout
+		}
+		selectSequentialConsistency = cluster.WriteReliability.SelectSequentialConsistency.CastTo01(false)
+	}
+
+	return []core.EnvVar{
+		{
+			Name:  model.InsertQuorumEnvName,
+			Value: insertQuorum,
+		},
+		{
+			Name:  model.InsertQuorumTimeoutEnvName,
+			Value: strconv.FormatInt(insertQuorumTimeout, 10),
+		},
+		{
+			Name:  model.SelectSequentialConsistencyEnvName,
+			Value: selectSequentialConsistency,
+		},
+	}
 }
 
 // ensureMainContainerSpecified is a unification wrapper
@@ -164,6 +310,8 @@ func (c *Creator) personalizeStatefulSetTemplate(statefulSet *apps.StatefulSet,
 	c.setupTroubleshootingMode(statefulSet, host)
 	// Setup dedicated log container
 	c.setupLogContainer(statefulSet, host)
+	// Setup log shipping agent container
+	c.setupLoggingContainer(statefulSet, host)
 }
 
 // setupTroubleshootingMode
@@ -199,6 +347,8 @@ func (c *Creator) setupTroubleshootingMode(statefulSet *apps.StatefulSet, host *
 	// Thus we need to disable all probes in troubleshooting mode.
 	container.LivenessProbe = nil
 	container.ReadinessProbe = nil
+	// Failing startup probe, in case pod template specifies one, restarts container the same way
+	container.StartupProbe = nil
 }
 
 // setupLogContainer
@@ -210,6 +360,36 @@ func (c *Creator) setupLogContainer(statefulSet *apps.StatefulSet, host *api.Chi
 	}
 }
 
+// setupLoggingContainer injects log shipping agent container, in case logging is enabled
+func (c *Creator) setupLoggingContainer(statefulSet *apps.StatefulSet, host *api.ChiHost) {
+	logging := host.GetCHI().Spec.Logging
+	if !logging.IsEnabled() {
+		return
+	}
+
+	configMapName := model.CreateConfigMapLoggingName(c.chi)
+	k8s.StatefulSetAppendVolumes(statefulSet, newVolumeForConfigMap(configMapName))
+	container := newLoggingContainer(host, logging, configMapName)
+
+	if !host.Templates.HasLogVolumeClaimTemplate() {
+		// ClickHouse logs are not on a persistent volume, which would be mounted into all containers,
+		// so they are shared with the agent via emptyDir volume
+		k8s.StatefulSetAppendVolumes(statefulSet, core.Volume{
+			Name: loggingLogsVolumeName,
+			VolumeSource: core.VolumeSource{
+				EmptyDir: &core.EmptyDirVolumeSource{},
+			},
+		})
+		if clickhouse, ok := getMainContainer(statefulSet); ok {
+			k8s.ContainerAppendVolumeMounts(clickhouse, newVolumeMount(loggingLogsVolumeName, model.DirPathClickHouseLog))
+		}
+		k8s.ContainerAppendVolumeMounts(&container, newVolumeMount(loggingLogsVolumeName, model.DirPathClickHouseLog))
+	}
+
+	k8s.PodSpecAddContainer(&statefulSet.Spec.Template.Spec, container)
+	c.a.V(1).F().Info("add %s logging container for host: %s", logging.Agent, host.Runtime.Address.HostName)
+}
+
 // getPodTemplate gets Pod Template to be used to create StatefulSet
 func (c *Creator) getPodTemplate(host *api.ChiHost) *api.ChiPodTemplate {
 	// Which pod template should be used - either explicitly defined or a default one
@@ -229,14 +409,43 @@ func (c *Creator) getPodTemplate(host *api.ChiHost) *api.ChiPodTemplate {
 	// Now we can customize this Pod Template for particular host
 
 	model.PrepareAffinity(podTemplate, host)
+	model.PrepareTopologySpreadConstraints(podTemplate, host)
+	model.ApplyHostZone(podTemplate, host)
+	model.ApplyNamespaceZone(podTemplate, host)
+	model.ApplyArchitecture(podTemplate)
+	model.ApplySpot(podTemplate, host)
+	model.ApplyCHIAntiAffinity(podTemplate, host)
+	applyPriorityClassName(podTemplate, host)
+	applyHostNetwork(podTemplate, host)
 
 	return podTemplate
 }
 
+// applyPriorityClassName applies priority class of the host's cluster, unless the pod template specifies its own one
+func applyPriorityClassName(podTemplate *api.ChiPodTemplate, host *api.ChiHost) {
+	if podTemplate.Spec.PriorityClassName != "" {
+		return
+	}
+	if cluster := host.GetCluster(); cluster != nil {
+		podTemplate.Spec.PriorityClassName = cluster.PriorityClassName
+	}
+}
+
+// applyHostNetwork runs pod in the host network namespace of the node in case the host's cluster requests so.
+// ClusterFirstWithHostNet DNS policy keeps cluster DNS names resolvable from the host network
+func applyHostNetwork(podTemplate *api.ChiPodTemplate, host *api.ChiHost) {
+	if !host.GetCluster().IsHostNetwork() {
+		return
+	}
+	podTemplate.Spec.HostNetwork = true
+	podTemplate.Spec.DNSPolicy = core.DNSClusterFirstWithHostNet
+}
+
 // statefulSetSetupVolumes setup all volumes
 func (c *Creator) statefulSetSetupVolumes(statefulSet *apps.StatefulSet, host *api.ChiHost) {
 	c.statefulSetSetupVolumesForConfigMaps(statefulSet, host)
 	c.statefulSetSetupVolumesForSecrets(statefulSet, host)
+	c.statefulSetSetupVolumesForTLS(statefulSet, host)
 }
 
 // statefulSetSetupVolumesForConfigMaps adds to each container in the Pod VolumeMount objects
@@ -280,6 +489,43 @@ func (c *Creator) statefulSetSetupVolumesForSecrets(statefulSet *apps.StatefulSe
 	)
 }
 
+// statefulSetSetupVolumesForTLS mounts Secret with managed certificates of the cluster into each container in the Pod.
+// Kubelet refreshes mounted Secret on rotation, so the Pod is not restarted
+func (c *Creator) statefulSetSetupVolumesForTLS(statefulSet *apps.StatefulSet, host *api.ChiHost) {
+	cluster := host.GetCluster()
+	if (cluster == nil) || !cluster.TLS.IsManaged() {
+		return
+	}
+
+	name := model.CreateClusterTLSSecretName(cluster)
+	var defaultMode int32 = 0644
+	k8s.StatefulSetAppendVolumes(
+		statefulSet,
+		core.Volume{
+			Name: name,
+			VolumeSource: core.VolumeSource{
+				Secret: &core.SecretVolumeSource{
+					SecretName:  name,
+					DefaultMode: &defaultMode,
+					Items: []core.KeyToPath{
+						{Key: model.TLSCertificateFile, Path: model.TLSCertificateFile},
+						{Key: model.TLSPrivateKeyFile, Path: model.TLSPrivateKeyFile},
+						{Key: model.TLSCAFile, Path: model.TLSCAFile},
+					},
+				},
+			},
+		},
+	)
+	k8s.StatefulSetAppendVolumeMounts(
+		statefulSet,
+		core.VolumeMount{
+			Name:      name,
+			MountPath: model.DirPathTLS,
+			ReadOnly:  true,
+		},
+	)
+}
+
 // statefulSetAppendUsedPVCTemplates appends all PVC templates which are used (referenced by name) by containers
 // to the StatefulSet.Spec.VolumeClaimTemplates list
 func (c *Creator) statefulSetAppendUsedPVCTemplates(statefulSet *apps.StatefulSet, host *api.ChiHost) {
@@ -288,9 +534,8 @@ func (c *Creator) statefulSetAppendUsedPVCTemplates(statefulSet *apps.StatefulSe
 	//
 	// Deal with `volumeMounts` of a `container`, located by the path:
 	// .spec.templates.podTemplates.*.spec.containers.volumeMounts.*
-	for i := range statefulSet.Spec.Template.Spec.Containers {
-		// Convenience wrapper
-		container := &statefulSet.Spec.Template.Spec.Containers[i]
+	// as well as of init containers
+	k8s.StatefulSetWalkContainers(statefulSet, func(container *core.Container) {
 		for j := range container.VolumeMounts {
 			// Convenience wrapper
 			volumeMount := &container.VolumeMounts[j]
@@ -298,17 +543,15 @@ func (c *Creator) statefulSetAppendUsedPVCTemplates(statefulSet *apps.StatefulSe
 				c.statefulSetAppendPVCTemplate(statefulSet, host, volumeClaimTemplate)
 			}
 		}
-	}
+	})
 }
 
 // statefulSetAppendVolumeMountsForDataAndLogVolumeClaimTemplates
 // appends VolumeMounts for Data and Log VolumeClaimTemplates on all containers.
 // Creates VolumeMounts for Data and Log volumes in case these volume templates are specified in `templates`.
 func (c *Creator) statefulSetAppendVolumeMountsForDataAndLogVolumeClaimTemplates(statefulSet *apps.StatefulSet, host *api.ChiHost) {
-	// Mount all named (data and log so far) VolumeClaimTemplates into all containers
-	for i := range statefulSet.Spec.Template.Spec.Containers {
-		// Convenience wrapper
-		container := &statefulSet.Spec.Template.Spec.Containers[i]
+	// Mount all named (data, log and system log so far) VolumeClaimTemplates into all containers, init ones included
+	k8s.StatefulSetWalkContainers(statefulSet, func(container *core.Container) {
 		k8s.ContainerAppendVolumeMounts(
 			container,
 			newVolumeMount(host.Templates.GetDataVolumeClaimTemplate(), model.DirPathClickHouseData),
@@ -317,7 +560,11 @@ func (c *Creator) statefulSetAppendVolumeMountsForDataAndLogVolumeClaimTemplates
 			container,
 			newVolumeMount(host.Templates.GetLogVolumeClaimTemplate(), model.DirPathClickHouseLog),
 		)
-	}
+		k8s.ContainerAppendVolumeMounts(
+			container,
+			newVolumeMount(host.Templates.GetSystemLogVolumeClaimTemplate(), model.DirPathClickHouseSystemLogs),
+		)
+	})
 }
 
 // setupStatefulSetVolumeClaimTemplates performs VolumeClaimTemplate setup for Containers in PodTemplate of a StatefulSet
@@ -342,8 +589,8 @@ func (c *Creator) statefulSetApplyPodTemplate(
 				template.ObjectMeta.Labels,
 			)),
 			Annotations: model.Macro(host).Map(util.MergeStringMapsOverwrite(
-				c.annotations.GetHostScope(host),
-				template.ObjectMeta.Annotations,
+				util.MergeStringMapsOverwrite(c.annotations.GetHostScope(host), template.ObjectMeta.Annotations),
+				host.Annotations,
 			)),
 		},
 		Spec: *template.Spec.DeepCopy(),
@@ -352,6 +599,7 @@ func (c *Creator) statefulSetApplyPodTemplate(
 	if statefulSet.Spec.Template.Spec.TerminationGracePeriodSeconds == nil {
 		statefulSet.Spec.Template.Spec.TerminationGracePeriodSeconds = chop.Config().GetTerminationGracePeriod()
 	}
+	k8s.PodSpecAppendImagePullSecrets(&statefulSet.Spec.Template.Spec, chop.Config().Pod.ImagePullSecrets...)
 }
 
 // getMainContainer is a unification wrapper
@@ -483,6 +731,27 @@ func newDefaultClickHouseContainer(host *api.ChiHost) core.Container {
 	return container
 }
 
+// newLoggingContainer returns log shipping agent container labeling log entries with the host's address
+func newLoggingContainer(host *api.ChiHost, logging *api.ChiLogging, configMapName string) core.Container {
+	container := core.Container{
+		Name:  model.LoggingContainerName,
+		Image: logging.Image,
+		Args:  model.GetLoggingArgs(logging),
+		Env:   model.GetLoggingEnvVars(host),
+		VolumeMounts: []core.VolumeMount{
+			{
+				Name:      configMapName,
+				MountPath: model.DirPathLoggingConfig,
+				ReadOnly:  true,
+			},
+		},
+	}
+	if logging.Resources != nil {
+		container.Resources = *logging.Resources.DeepCopy()
+	}
+	return container
+}
+
 // newDefaultLogContainer returns default ClickHouse Log Container
 func newDefaultLogContainer() core.Container {
 	return core.Container{
@@ -0,0 +1,66 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package creator
+
+import (
+	"testing"
+
+	apps "k8s.io/api/apps/v1"
+	core "k8s.io/api/core/v1"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	model "github.com/altinity/clickhouse-operator/pkg/model/chi"
+)
+
+func TestApplyCoreDumpPattern(t *testing.T) {
+	tests := []struct {
+		name    string
+		debug   *api.ChiDebug
+		applied bool
+	}{
+		{name: "no debug", applied: false},
+		{name: "core dumps only", debug: &api.ChiDebug{CoreDump: api.NewStringBool(true)}, applied: false},
+		{name: "pattern without core dumps", debug: &api.ChiDebug{CoreDumpPattern: api.NewStringBool(true)}, applied: false},
+		{name: "pattern", debug: &api.ChiDebug{CoreDump: api.NewStringBool(true), CoreDumpPattern: api.NewStringBool(true)}, applied: true},
+	}
+	for _, test := range tests {
+		host := &api.ChiHost{}
+		host.Runtime.CHI = &api.ClickHouseInstallation{Spec: api.ChiSpec{Debug: test.debug}}
+		statefulSet := &apps.StatefulSet{}
+		statefulSet.Spec.Template.Spec.Containers = []core.Container{
+			{Name: model.ClickHouseContainerName, Image: "clickhouse/clickhouse-server:23.8"},
+		}
+
+		applyCoreDumpPattern(statefulSet, host)
+		initContainers := statefulSet.Spec.Template.Spec.InitContainers
+		if applied := len(initContainers) > 0; applied != test.applied {
+			t.Fatalf("%s: got applied %v want %v", test.name, applied, test.applied)
+		}
+		if !test.applied {
+			continue
+		}
+
+		container := initContainers[0]
+		if container.Name != model.CoreDumpPatternContainerName || container.Image != "clickhouse/clickhouse-server:23.8" {
+			t.Errorf("%s: unexpected init container %s image %s", test.name, container.Name, container.Image)
+		}
+		if (container.SecurityContext == nil) || (container.SecurityContext.Privileged == nil) || !*container.SecurityContext.Privileged {
+			t.Errorf("%s: init container has to be privileged in order to write kernel.core_pattern", test.name)
+		}
+		if want := "echo '/var/lib/clickhouse-core-dumps/core.%e.%p.%t' > /proc/sys/kernel/core_pattern"; container.Command[len(container.Command)-1] != want {
+			t.Errorf("%s: got command %v want %s", test.name, container.Command, want)
+		}
+	}
+}
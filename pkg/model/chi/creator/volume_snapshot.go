@@ -0,0 +1,57 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package creator
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	model "github.com/altinity/clickhouse-operator/pkg/model/chi"
+)
+
+// VolumeSnapshotGroupVersionResource specifies API of volume snapshots.
+// There is no typed client for volume snapshots, so they are handled as unstructured objects
+var VolumeSnapshotGroupVersionResource = schema.GroupVersionResource{
+	Group:    "snapshot.storage.k8s.io",
+	Version:  "v1",
+	Resource: "volumesnapshots",
+}
+
+// VolumeSnapshotKind specifies kind of volume snapshots
+const VolumeSnapshotKind = "VolumeSnapshot"
+
+// CreateForkVolumeSnapshot creates VolumeSnapshot of the source PVC, volume of the host is to be cloned from
+func (c *Creator) CreateForkVolumeSnapshot(host *api.ChiHost, name, sourcePVCName string) *unstructured.Unstructured {
+	snapshot := &unstructured.Unstructured{}
+	snapshot.SetAPIVersion(VolumeSnapshotGroupVersionResource.GroupVersion().String())
+	snapshot.SetKind(VolumeSnapshotKind)
+	snapshot.SetName(name)
+	snapshot.SetNamespace(host.Runtime.Address.Namespace)
+	snapshot.SetLabels(model.Macro(host).Map(c.labels.GetHostScope(host, false)))
+	snapshot.SetOwnerReferences(getOwnerReferences(c.chi))
+
+	spec := map[string]interface{}{
+		"source": map[string]interface{}{
+			"persistentVolumeClaimName": sourcePVCName,
+		},
+	}
+	if className := c.chi.Spec.Fork.GetVolumeSnapshotClassName(); className != "" {
+		spec["volumeSnapshotClassName"] = className
+	}
+	snapshot.Object["spec"] = spec
+
+	return snapshot
+}
@@ -0,0 +1,99 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"fmt"
+	"strings"
+
+	core "k8s.io/api/core/v1"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/chop"
+	"github.com/altinity/clickhouse-operator/pkg/util"
+)
+
+// Kinds of objects CHI normalization depends on
+const (
+	DependencyKindTemplate = "ClickHouseInstallationTemplate"
+	DependencyKindSecret   = "Secret"
+	// DependencyKindMetadata specifies labels and annotations of the CHI itself, which are propagated to CHI objects.
+	// Change of metadata does not change generation, so metadata is versioned by its hash
+	DependencyKindMetadata = "Metadata"
+)
+
+// Dependency describes particular version of an object CHI normalization depends on
+type Dependency struct {
+	Kind            string
+	Namespace       string
+	Name            string
+	ResourceVersion string
+}
+
+// String stringifies dependency as kind/namespace/name@resourceVersion
+func (d Dependency) String() string {
+	return fmt.Sprintf("%s/%s/%s@%s", d.Kind, d.Namespace, d.Name, d.ResourceVersion)
+}
+
+// CreateTemplateDependency creates dependency string for the template
+func CreateTemplateDependency(template *api.ClickHouseInstallation) string {
+	return Dependency{
+		Kind:            DependencyKindTemplate,
+		Namespace:       template.Namespace,
+		Name:            template.Name,
+		ResourceVersion: template.ResourceVersion,
+	}.String()
+}
+
+// CreateSecretDependency creates dependency string for the secret
+func CreateSecretDependency(secret *core.Secret) string {
+	return Dependency{
+		Kind:            DependencyKindSecret,
+		Namespace:       secret.Namespace,
+		Name:            secret.Name,
+		ResourceVersion: secret.ResourceVersion,
+	}.String()
+}
+
+// CreateMetadataDependency creates dependency string for labels and annotations of the CHI propagated to CHI objects
+func CreateMetadataDependency(chi *api.ClickHouseInstallation) string {
+	labels := util.CopyMapFilter(chi.Labels, chop.Config().Label.Include, chop.Config().Label.Exclude)
+	annotations := util.CopyMapFilter(chi.Annotations, chop.Config().Annotation.Include, chop.Config().Annotation.Exclude)
+	annotations = util.CopyMapFilter(annotations, nil, util.AnnotationsTobeSkipped)
+	return Dependency{
+		Kind:            DependencyKindMetadata,
+		Namespace:       chi.Namespace,
+		Name:            chi.Name,
+		ResourceVersion: util.Fingerprint([]map[string]string{labels, annotations}),
+	}.String()
+}
+
+// ParseDependency parses dependency string
+func ParseDependency(str string) (Dependency, bool) {
+	at := strings.LastIndex(str, "@")
+	if at < 0 {
+		return Dependency{}, false
+	}
+	parts := strings.SplitN(str[:at], "/", 3)
+	if len(parts) != 3 {
+		return Dependency{}, false
+	}
+	return Dependency{
+		Kind:            parts[0],
+		Namespace:       parts[1],
+		Name:            parts[2],
+		ResourceVersion: str[at+1:],
+	}, true
+}
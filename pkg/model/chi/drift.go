@@ -0,0 +1,128 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+)
+
+// HostLoadedConfig describes configuration actually loaded by a host
+type HostLoadedConfig struct {
+	// Settings maps server setting name to its value, as reported by system.server_settings.
+	// Nil in case the host is not able to report server settings
+	Settings map[string]string
+	// Macros maps macro name to its substitution, as reported by system.macros
+	Macros map[string]string
+	// Clusters maps cluster name to number of its replicas, as reported by system.clusters
+	Clusters map[string]int
+}
+
+// GetHostConfigDrift compares configuration loaded by the host with the generated one and returns list of mismatches
+func GetHostConfigDrift(host *api.ChiHost, loaded *HostLoadedConfig) (drift []string) {
+	generator := NewClickHouseConfigGenerator(host.GetCHI())
+
+	// Host-level settings take precedence over the common ones
+	settings := api.NewSettings().MergeFrom(host.Settings).MergeFrom(host.GetCHI().Spec.Configuration.Settings)
+	if loaded.Settings != nil {
+		drift = append(drift, getSettingsDrift(settings, loaded.Settings)...)
+	}
+	drift = append(drift, getMacrosDrift(generator.GetHostMacrosValues(host), loaded.Macros)...)
+	drift = append(drift, getClustersDrift(generator.GetRemoteServersReplicasNum(nil), loaded.Clusters)...)
+
+	return drift
+}
+
+// getSettingsDrift compares top-level scalar settings with the loaded server settings.
+// Settings unknown to the server, like sections or settings of newer versions, are skipped
+func getSettingsDrift(settings *api.Settings, loaded map[string]string) (drift []string) {
+	names := settings.Names()
+	sort.Strings(names)
+	for _, name := range names {
+		setting := settings.Get(name)
+		if strings.Contains(name, "/") || !setting.IsScalar() {
+			continue
+		}
+		value, ok := loaded[name]
+		if !ok || isSameSettingValue(setting.ScalarString(), value) {
+			continue
+		}
+		drift = append(drift, fmt.Sprintf("setting %s is %s instead of %s", name, value, setting.ScalarString()))
+	}
+	return drift
+}
+
+// isSameSettingValue checks whether generated and loaded values of a setting are the same,
+// taking into account the server reports booleans and numbers in canonical form
+func isSameSettingValue(generated, loaded string) bool {
+	canonical := func(value string) string {
+		value = strings.TrimSpace(value)
+		switch strings.ToLower(value) {
+		case "true":
+			return "1"
+		case "false":
+			return "0"
+		}
+		return value
+	}
+	generated, loaded = canonical(generated), canonical(loaded)
+	if generated == loaded {
+		return true
+	}
+	a, errA := strconv.ParseFloat(generated, 64)
+	b, errB := strconv.ParseFloat(loaded, 64)
+	return (errA == nil) && (errB == nil) && (a == b)
+}
+
+// getMacrosDrift compares generated macros with the loaded ones
+func getMacrosDrift(macros, loaded map[string]string) (drift []string) {
+	for _, name := range sortedKeys(macros) {
+		value, ok := loaded[name]
+		switch {
+		case !ok:
+			drift = append(drift, fmt.Sprintf("macro %s is missing", name))
+		case value != macros[name]:
+			drift = append(drift, fmt.Sprintf("macro %s is %s instead of %s", name, value, macros[name]))
+		}
+	}
+	return drift
+}
+
+// getClustersDrift compares numbers of replicas of generated clusters with the loaded ones
+func getClustersDrift(clusters, loaded map[string]int) (drift []string) {
+	for _, name := range sortedKeys(clusters) {
+		num, ok := loaded[name]
+		switch {
+		case !ok:
+			drift = append(drift, fmt.Sprintf("cluster %s is missing", name))
+		case num != clusters[name]:
+			drift = append(drift, fmt.Sprintf("cluster %s has %d replicas instead of %d", name, num, clusters[name]))
+		}
+	}
+	return drift
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
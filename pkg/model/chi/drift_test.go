@@ -0,0 +1,85 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"reflect"
+	"testing"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+)
+
+func TestGetSettingsDrift(t *testing.T) {
+	settings := api.NewSettings().
+		Set("max_concurrent_queries", api.NewSettingScalar("200")).
+		Set("listen_reuse_port", api.NewSettingScalar("true")).
+		Set("max_server_memory_usage_to_ram_ratio", api.NewSettingScalar("0.90")).
+		Set("logger/level", api.NewSettingScalar("debug")).
+		Set("newer_setting", api.NewSettingScalar("1"))
+	loaded := map[string]string{
+		"max_concurrent_queries":               "100",
+		"listen_reuse_port":                    "1",
+		"max_server_memory_usage_to_ram_ratio": "0.9",
+	}
+
+	got := getSettingsDrift(settings, loaded)
+	want := []string{"setting max_concurrent_queries is 100 instead of 200"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v want %v", got, want)
+	}
+}
+
+func TestGetMacrosDrift(t *testing.T) {
+	macros := map[string]string{
+		"cluster": "main",
+		"replica": "chi-test-main-0-1",
+		"shard":   "0",
+	}
+	loaded := map[string]string{
+		"cluster": "main",
+		"replica": "chi-test-main-0-0",
+	}
+
+	got := getMacrosDrift(macros, loaded)
+	want := []string{
+		"macro replica is chi-test-main-0-0 instead of chi-test-main-0-1",
+		"macro shard is missing",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v want %v", got, want)
+	}
+}
+
+func TestGetClustersDrift(t *testing.T) {
+	clusters := map[string]int{
+		"all-replicated": 4,
+		"main":           4,
+		"reports":        2,
+	}
+	loaded := map[string]int{
+		"all-replicated": 4,
+		"main":           2,
+		"default":        1,
+	}
+
+	got := getClustersDrift(clusters, loaded)
+	want := []string{
+		"cluster main has 2 replicas instead of 4",
+		"cluster reports is missing",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v want %v", got, want)
+	}
+}
@@ -16,8 +16,10 @@ package chi
 
 import (
 	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 
 	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/model/k8s"
 	"github.com/altinity/clickhouse-operator/pkg/util"
 )
 
@@ -85,3 +87,103 @@ func HostWalkInvalidPorts(host *api.ChiHost, f func(name string, port *int32, pr
 		},
 	)
 }
+
+// HostGetClickHouseContainerResources gets resources of ClickHouse container of the host.
+// Resources specified for the host in CHI spec take precedence over the ones of the pod template
+func HostGetClickHouseContainerResources(host *api.ChiHost) *core.ResourceRequirements {
+	var fromPodTemplate *core.ResourceRequirements
+	if podTemplate, ok := host.GetPodTemplate(); ok {
+		if container, ok := k8s.PodSpecContainerGet(&podTemplate.Spec, ClickHouseContainerName, 0); ok {
+			fromPodTemplate = &container.Resources
+		}
+	}
+	return api.MergeResourceRequirements(host.Resources.DeepCopy(), fromPodTemplate)
+}
+
+// HostGetClickHouseImage gets image of ClickHouse container of the host
+func HostGetClickHouseImage(host *api.ChiHost) string {
+	if podTemplate, ok := host.GetPodTemplate(); ok {
+		if container, ok := k8s.PodSpecContainerGet(&podTemplate.Spec, ClickHouseContainerName, 0); ok && (container.Image != "") {
+			return container.Image
+		}
+	}
+	return DefaultClickHouseDockerImage
+}
+
+// HostGetCPUs gets number of CPUs available to ClickHouse container of the host as specified in the CHI spec.
+// CPU limit is preferred over CPU request, fractional values are rounded up. 0 means CPUs are not specified
+func HostGetCPUs(host *api.ChiHost) int {
+	resources := HostGetClickHouseContainerResources(host)
+	if resources == nil {
+		return 0
+	}
+
+	cpu := resources.Limits.Cpu()
+	if cpu.IsZero() {
+		cpu = resources.Requests.Cpu()
+	}
+
+	return int((cpu.MilliValue() + 999) / 1000)
+}
+
+// HostGetVolumeClaimTemplates gets VolumeClaimTemplates used by the host -
+// data, log and system log templates along with templates referenced by volumeMounts of the pod template containers
+func HostGetVolumeClaimTemplates(host *api.ChiHost) (templates []*api.ChiVolumeClaimTemplate) {
+	names := []string{
+		host.Templates.GetDataVolumeClaimTemplate(),
+		host.Templates.GetLogVolumeClaimTemplate(),
+		host.Templates.GetSystemLogVolumeClaimTemplate(),
+	}
+	if podTemplate, ok := host.GetPodTemplate(); ok {
+		for i := range podTemplate.Spec.Containers {
+			for j := range podTemplate.Spec.Containers[i].VolumeMounts {
+				names = append(names, podTemplate.Spec.Containers[i].VolumeMounts[j].Name)
+			}
+		}
+	}
+
+	var used []string
+	for _, name := range names {
+		if (name == "") || util.InArray(name, used) {
+			continue
+		}
+		if template, ok := host.GetCHI().GetVolumeClaimTemplate(name); ok {
+			used = append(used, name)
+			templates = append(templates, template)
+		}
+	}
+	return templates
+}
+
+// HostGetResourceRequests gets resources requested by the host - CPU and memory of all pod template containers
+// along with storage of all VolumeClaimTemplates used by the host.
+// Limits are used for containers having no requests specified, the same way k8s does.
+func HostGetResourceRequests(host *api.ChiHost) core.ResourceList {
+	requests := core.ResourceList{}
+	add := func(name core.ResourceName, quantity resource.Quantity) {
+		total := requests[name]
+		total.Add(quantity)
+		requests[name] = total
+	}
+
+	if podTemplate, ok := host.GetPodTemplate(); ok {
+		for i := range podTemplate.Spec.Containers {
+			resources := &podTemplate.Spec.Containers[i].Resources
+			for _, name := range []core.ResourceName{core.ResourceCPU, core.ResourceMemory} {
+				if quantity, ok := resources.Requests[name]; ok {
+					add(name, quantity)
+				} else if quantity, ok := resources.Limits[name]; ok {
+					add(name, quantity)
+				}
+			}
+		}
+	}
+
+	for _, template := range HostGetVolumeClaimTemplates(host) {
+		if quantity, ok := template.Spec.Resources.Requests[core.ResourceStorage]; ok {
+			add(core.ResourceStorage, quantity)
+		}
+	}
+
+	return requests
+}
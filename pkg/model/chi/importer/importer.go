@@ -0,0 +1,381 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package importer synthesizes CHI out of ClickHouse deployed without the operator - StatefulSets and Services,
+// so the existing deployment can be moved under operator management.
+package importer
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	apps "k8s.io/api/apps/v1"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	model "github.com/altinity/clickhouse-operator/pkg/model/chi"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/builder"
+)
+
+const (
+	// AnnotationImportedFrom lists StatefulSets the CHI is synthesized from
+	AnnotationImportedFrom = "clickhouse.altinity.com/imported-from"
+
+	defaultClusterName = "default"
+	// dirConfig is the root of ClickHouse configuration, mounts below it are replaced by the operator
+	dirConfig = "/etc/clickhouse-server"
+)
+
+// Options specifies options of the import
+type Options struct {
+	// ClusterName specifies name of the cluster hosts of the deployment are placed into
+	ClusterName string
+}
+
+// NewOptions creates new Options
+func NewOptions() *Options {
+	return &Options{
+		ClusterName: defaultClusterName,
+	}
+}
+
+// Result specifies CHI synthesized along with objects it takes over
+type Result struct {
+	// CHI is synthesized out of the deployment
+	CHI *api.ClickHouseInstallation
+	// StatefulSets lists StatefulSets the CHI is synthesized from
+	StatefulSets []string
+	// Adopted lists StatefulSets, Services and PVCs to be taken over by the operator in place, under the same names
+	Adopted []string
+	// Warnings lists parts of the deployment not carried into the CHI
+	Warnings []string
+}
+
+// Build synthesizes CHI out of StatefulSets and Services running ClickHouse.
+// Each StatefulSet becomes a shard, pods of the StatefulSet become replicas of the shard.
+func Build(namespace, name string, statefulSets []apps.StatefulSet, services []core.Service, options *Options) (*Result, error) {
+	if options == nil {
+		options = NewOptions()
+	}
+
+	sources := findClickHouseStatefulSets(statefulSets)
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("no StatefulSets running ClickHouse found in namespace %s", namespace)
+	}
+
+	result := &Result{}
+	cluster := builder.NewCluster(options.ClusterName)
+	cluster.Layout = api.NewChiClusterLayout()
+	chi := builder.NewCHI(namespace, name)
+	volumeClaimTemplates := newVolumeClaimTemplates()
+	for _, statefulSet := range sources {
+		shard := result.importStatefulSet(chi, statefulSet, volumeClaimTemplates)
+		cluster.Layout.Shards = append(cluster.Layout.Shards, shard)
+		result.StatefulSets = append(result.StatefulSets, statefulSet.Name)
+	}
+	builder.ApplyCHI(chi,
+		builder.WithCluster(cluster),
+		builder.WithVolumeClaimTemplates(volumeClaimTemplates.templates...),
+	)
+	result.importServices(chi, cluster, sources, services)
+
+	chi.Annotations = map[string]string{
+		AnnotationImportedFrom: strings.Join(result.StatefulSets, ","),
+	}
+	result.warn("settings, users, remote_servers and zookeeper configuration are not imported, " +
+		"specify them in spec.configuration")
+	result.CHI = chi
+	return result, nil
+}
+
+// findClickHouseStatefulSets finds StatefulSets having ClickHouse container, sorted by name
+func findClickHouseStatefulSets(statefulSets []apps.StatefulSet) (found []*apps.StatefulSet) {
+	for i := range statefulSets {
+		if _, ok := getClickHouseContainer(&statefulSets[i].Spec.Template.Spec); ok {
+			found = append(found, &statefulSets[i])
+		}
+	}
+	sort.Slice(found, func(i, j int) bool {
+		return found[i].Name < found[j].Name
+	})
+	return found
+}
+
+// getClickHouseContainer gets container running ClickHouse server
+func getClickHouseContainer(podSpec *core.PodSpec) (*core.Container, bool) {
+	for i := range podSpec.Containers {
+		container := &podSpec.Containers[i]
+		if container.Name == model.ClickHouseContainerName {
+			return container, true
+		}
+		if strings.Contains(container.Image, "clickhouse-server") {
+			return container, true
+		}
+	}
+	return nil, false
+}
+
+// importStatefulSet converts StatefulSet into pod template and shard of the CHI.
+// StatefulSet of one replica is taken over in place, since the operator runs StatefulSet per host
+// and StatefulSet of the same name reuses PVCs of the same names
+func (r *Result) importStatefulSet(
+	chi *api.ClickHouseInstallation,
+	statefulSet *apps.StatefulSet,
+	volumeClaimTemplates *volumeClaimTemplates,
+) api.ChiShard {
+	replicas := 1
+	if statefulSet.Spec.Replicas != nil {
+		replicas = int(*statefulSet.Spec.Replicas)
+	}
+	inPlace := replicas == 1
+
+	podTemplate := api.ChiPodTemplate{
+		Name:       statefulSet.Name,
+		ObjectMeta: *statefulSet.Spec.Template.ObjectMeta.DeepCopy(),
+		Spec:       *statefulSet.Spec.Template.Spec.DeepCopy(),
+	}
+	// Labels of the selector would make pods of the operator selected by the StatefulSet being replaced
+	podTemplate.ObjectMeta.Labels = withoutSelectorLabels(podTemplate.ObjectMeta.Labels, statefulSet.Spec.Selector)
+	if inPlace {
+		podTemplate.GenerateName = statefulSet.Name
+		r.Adopted = append(r.Adopted, "StatefulSet/"+statefulSet.Name)
+	} else {
+		r.warn(fmt.Sprintf("StatefulSet %s runs %d replicas, the operator runs StatefulSet per replica, "+
+			"so its pods and PVCs are replaced by new ones", statefulSet.Name, replicas))
+	}
+
+	templates := &api.ChiTemplateNames{
+		PodTemplate: podTemplate.Name,
+	}
+	container, _ := getClickHouseContainer(&podTemplate.Spec)
+	container.Name = model.ClickHouseContainerName
+	var mounts []core.VolumeMount
+	for _, mount := range container.VolumeMounts {
+		if claim, ok := findVolumeClaimTemplate(statefulSet, mount.Name); ok {
+			// Volume claim templates are mounted by the operator
+			name := volumeClaimTemplates.add(statefulSet, claim)
+			switch mount.MountPath {
+			case model.DirPathClickHouseData:
+				templates.DataVolumeClaimTemplate = name
+			case model.DirPathClickHouseLog:
+				templates.LogVolumeClaimTemplate = name
+			default:
+				mount.Name = name
+				mounts = append(mounts, mount)
+			}
+			if inPlace && (name == claim.Name) {
+				r.Adopted = append(r.Adopted, fmt.Sprintf("PersistentVolumeClaim/%s-%s-0", claim.Name, statefulSet.Name))
+			}
+			continue
+		}
+		if strings.HasPrefix(mount.MountPath, dirConfig) {
+			// Configuration is generated by the operator
+			r.warn(fmt.Sprintf("volume %s mounted into %s of StatefulSet %s is dropped, "+
+				"move its configuration into spec.configuration.files", mount.Name, mount.MountPath, statefulSet.Name))
+			continue
+		}
+		mounts = append(mounts, mount)
+	}
+	container.VolumeMounts = mounts
+	podTemplate.Spec.Volumes = withoutUnmountedVolumes(&podTemplate.Spec)
+
+	builder.ApplyCHI(chi, builder.WithPodTemplates(podTemplate))
+	return api.ChiShard{
+		Name:          statefulSet.Name,
+		ReplicasCount: replicas,
+		Templates:     templates,
+	}
+}
+
+// importServices converts Services selecting pods of the imported StatefulSets into service templates.
+// Service selecting pods of all StatefulSets becomes service of the CHI, service selecting pods of one StatefulSet
+// becomes service of the corresponding shard. Services are taken over in place, under the same names.
+// Headless services are replaced by services the operator creates for each host
+func (r *Result) importServices(
+	chi *api.ClickHouseInstallation,
+	cluster *api.Cluster,
+	statefulSets []*apps.StatefulSet,
+	services []core.Service,
+) {
+	sort.Slice(services, func(i, j int) bool {
+		return services[i].Name < services[j].Name
+	})
+	for i := range services {
+		service := &services[i]
+		var selected []int
+		for j, statefulSet := range statefulSets {
+			if selects(service, statefulSet) {
+				selected = append(selected, j)
+			}
+		}
+		switch {
+		case len(selected) == 0:
+			continue
+		case service.Spec.ClusterIP == core.ClusterIPNone:
+			r.warn(fmt.Sprintf("headless Service %s is replaced by Services of hosts", service.Name))
+			continue
+		case len(selected) == len(statefulSets):
+			if chi.Spec.Defaults == nil {
+				chi.Spec.Defaults = api.NewChiDefaults()
+			}
+			if chi.Spec.Defaults.Templates.HasServiceTemplate() {
+				r.warn(fmt.Sprintf("Service %s is not imported, CHI has one service only", service.Name))
+				continue
+			}
+			if chi.Spec.Defaults.Templates == nil {
+				chi.Spec.Defaults.Templates = api.NewChiTemplateNames()
+			}
+			chi.Spec.Defaults.Templates.ServiceTemplate = service.Name
+		case len(selected) == 1:
+			shard := &cluster.Layout.Shards[selected[0]]
+			if shard.Templates.ShardServiceTemplate != "" {
+				r.warn(fmt.Sprintf("Service %s is not imported, shard %s has one service only", service.Name, shard.Name))
+				continue
+			}
+			shard.Templates.ShardServiceTemplate = service.Name
+		default:
+			r.warn(fmt.Sprintf("Service %s is not imported, it selects pods of some of the StatefulSets", service.Name))
+			continue
+		}
+		builder.ApplyCHI(chi, builder.WithServiceTemplates(newServiceTemplate(service)))
+		r.Adopted = append(r.Adopted, "Service/"+service.Name)
+	}
+}
+
+// selects checks whether Service selects pods of the StatefulSet
+func selects(service *core.Service, statefulSet *apps.StatefulSet) bool {
+	if len(service.Spec.Selector) == 0 {
+		return false
+	}
+	for key, value := range service.Spec.Selector {
+		if statefulSet.Spec.Template.Labels[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// newServiceTemplate creates service template reproducing the Service under the same name.
+// Addresses allocated by the cluster and selector are dropped, the operator sets selector of its own
+func newServiceTemplate(service *core.Service) api.ChiServiceTemplate {
+	spec := service.Spec.DeepCopy()
+	spec.Selector = nil
+	spec.ClusterIP = ""
+	spec.ClusterIPs = nil
+	return api.ChiServiceTemplate{
+		Name:         service.Name,
+		GenerateName: service.Name,
+		ObjectMeta:   newObjectMeta(service.Labels, service.Annotations),
+		Spec:         *spec,
+	}
+}
+
+// findVolumeClaimTemplate finds volume claim template of the StatefulSet by name
+func findVolumeClaimTemplate(statefulSet *apps.StatefulSet, name string) (*core.PersistentVolumeClaim, bool) {
+	for i := range statefulSet.Spec.VolumeClaimTemplates {
+		if claim := &statefulSet.Spec.VolumeClaimTemplates[i]; claim.Name == name {
+			return claim, true
+		}
+	}
+	return nil, false
+}
+
+// withoutSelectorLabels returns labels without those matched by the selector
+func withoutSelectorLabels(labels map[string]string, selector *meta.LabelSelector) map[string]string {
+	if selector == nil {
+		return labels
+	}
+	result := make(map[string]string)
+	for key, value := range labels {
+		if _, ok := selector.MatchLabels[key]; !ok {
+			result[key] = value
+		}
+	}
+	return result
+}
+
+// withoutUnmountedVolumes returns volumes of the pod, which are still mounted into any container
+func withoutUnmountedVolumes(podSpec *core.PodSpec) (volumes []core.Volume) {
+	mounted := make(map[string]bool)
+	for _, containers := range [][]core.Container{podSpec.InitContainers, podSpec.Containers} {
+		for i := range containers {
+			for _, mount := range containers[i].VolumeMounts {
+				mounted[mount.Name] = true
+			}
+		}
+	}
+	for _, volume := range podSpec.Volumes {
+		if mounted[volume.Name] {
+			volumes = append(volumes, volume)
+		}
+	}
+	return volumes
+}
+
+// volumeClaimTemplates collects volume claim templates of all StatefulSets.
+// Templates of the same name and spec are shared, otherwise template is named after its StatefulSet
+type volumeClaimTemplates struct {
+	templates []api.ChiVolumeClaimTemplate
+}
+
+// newVolumeClaimTemplates creates new volumeClaimTemplates
+func newVolumeClaimTemplates() *volumeClaimTemplates {
+	return &volumeClaimTemplates{}
+}
+
+// add adds volume claim template of the StatefulSet and returns name of the template
+func (t *volumeClaimTemplates) add(statefulSet *apps.StatefulSet, claim *core.PersistentVolumeClaim) string {
+	name := claim.Name
+	if existing, ok := t.find(name); ok {
+		if reflect.DeepEqual(existing.Spec, claim.Spec) {
+			return name
+		}
+		name = statefulSet.Name + "-" + claim.Name
+		if _, ok := t.find(name); ok {
+			return name
+		}
+	}
+	t.templates = append(t.templates, api.ChiVolumeClaimTemplate{
+		Name:       name,
+		ObjectMeta: newObjectMeta(claim.Labels, claim.Annotations),
+		Spec:       *claim.Spec.DeepCopy(),
+	})
+	return name
+}
+
+// find finds volume claim template by name
+func (t *volumeClaimTemplates) find(name string) (*api.ChiVolumeClaimTemplate, bool) {
+	for i := range t.templates {
+		if t.templates[i].Name == name {
+			return &t.templates[i], true
+		}
+	}
+	return nil, false
+}
+
+// warn appends warning to the result
+func (r *Result) warn(warning string) {
+	r.Warnings = append(r.Warnings, warning)
+}
+
+// newObjectMeta creates object meta carrying labels and annotations only
+func newObjectMeta(labels, annotations map[string]string) meta.ObjectMeta {
+	return meta.ObjectMeta{
+		Labels:      labels,
+		Annotations: annotations,
+	}
+}
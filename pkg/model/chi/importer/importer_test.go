@@ -0,0 +1,159 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package importer_test
+
+import (
+	"os"
+	"reflect"
+	"sort"
+	"testing"
+
+	apps "k8s.io/api/apps/v1"
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	model "github.com/altinity/clickhouse-operator/pkg/model/chi"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/importer"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/render"
+)
+
+func TestMain(m *testing.M) {
+	render.Init("../../../../config/config.yaml")
+	os.Exit(m.Run())
+}
+
+func newStatefulSet(name, image string, replicas int32) apps.StatefulSet {
+	labels := map[string]string{"app": "clickhouse", "shard": name}
+	return apps.StatefulSet{
+		ObjectMeta: meta.ObjectMeta{Name: name},
+		Spec: apps.StatefulSetSpec{
+			Replicas: &replicas,
+			Selector: &meta.LabelSelector{MatchLabels: map[string]string{"shard": name}},
+			Template: core.PodTemplateSpec{
+				ObjectMeta: meta.ObjectMeta{Labels: labels},
+				Spec: core.PodSpec{
+					Containers: []core.Container{{
+						Name:  "server",
+						Image: image,
+						VolumeMounts: []core.VolumeMount{
+							{Name: "data", MountPath: model.DirPathClickHouseData},
+							{Name: "config", MountPath: "/etc/clickhouse-server/config.d"},
+						},
+					}},
+					Volumes: []core.Volume{{
+						Name:         "config",
+						VolumeSource: core.VolumeSource{ConfigMap: &core.ConfigMapVolumeSource{}},
+					}},
+				},
+			},
+			VolumeClaimTemplates: []core.PersistentVolumeClaim{{
+				ObjectMeta: meta.ObjectMeta{Name: "data"},
+				Spec: core.PersistentVolumeClaimSpec{
+					Resources: core.ResourceRequirements{
+						Requests: core.ResourceList{core.ResourceStorage: resource.MustParse("100Gi")},
+					},
+				},
+			}},
+		},
+	}
+}
+
+func TestBuild(t *testing.T) {
+	statefulSets := []apps.StatefulSet{
+		newStatefulSet("ch-1", "clickhouse/clickhouse-server:23.8", 1),
+		newStatefulSet("ch-0", "clickhouse/clickhouse-server:23.8", 1),
+		newStatefulSet("zookeeper", "zookeeper:3.8", 3),
+	}
+	services := []core.Service{
+		{
+			ObjectMeta: meta.ObjectMeta{Name: "clickhouse"},
+			Spec: core.ServiceSpec{
+				Selector:  map[string]string{"app": "clickhouse"},
+				ClusterIP: "10.0.0.1",
+				Ports:     []core.ServicePort{{Name: model.ChDefaultHTTPPortName, Port: model.ChDefaultHTTPPortNumber}},
+			},
+		},
+		{
+			ObjectMeta: meta.ObjectMeta{Name: "ch-0-headless"},
+			Spec: core.ServiceSpec{
+				Selector:  map[string]string{"shard": "ch-0"},
+				ClusterIP: core.ClusterIPNone,
+			},
+		},
+		{
+			ObjectMeta: meta.ObjectMeta{Name: "zookeeper"},
+			Spec:       core.ServiceSpec{Selector: map[string]string{"app": "zookeeper"}},
+		},
+	}
+
+	result, err := importer.Build("prod", "events", statefulSets, services, nil)
+	if err != nil {
+		t.Fatalf("unable to import err: %v", err)
+	}
+	if want := []string{"ch-0", "ch-1"}; !reflect.DeepEqual(result.StatefulSets, want) {
+		t.Errorf("got StatefulSets %v want %v", result.StatefulSets, want)
+	}
+	want := []string{
+		"PersistentVolumeClaim/data-ch-0-0",
+		"PersistentVolumeClaim/data-ch-1-0",
+		"Service/clickhouse",
+		"StatefulSet/ch-0",
+		"StatefulSet/ch-1",
+	}
+	adopted := append([]string{}, result.Adopted...)
+	sort.Strings(adopted)
+	if !reflect.DeepEqual(adopted, want) {
+		t.Errorf("got adopted %v want %v", adopted, want)
+	}
+	// Headless service, config volumes and not imported configuration
+	if len(result.Warnings) != 4 {
+		t.Errorf("unexpected warnings: %v", result.Warnings)
+	}
+
+	// Objects of the operator have to reuse names of the imported ones
+	m, err := render.Render(result.CHI, nil)
+	if err != nil {
+		t.Fatalf("unable to render err: %v", err)
+	}
+	objects := map[string]bool{}
+	for _, statefulSet := range m.StatefulSets {
+		objects["StatefulSet/"+statefulSet.Name] = true
+		if container := statefulSet.Spec.Template.Spec.Containers[0]; container.Name != model.ClickHouseContainerName {
+			t.Errorf("StatefulSet %s: got container %s want %s", statefulSet.Name, container.Name, model.ClickHouseContainerName)
+		}
+		for _, claim := range statefulSet.Spec.VolumeClaimTemplates {
+			objects["PersistentVolumeClaim/"+claim.Name+"-"+model.CreatePodName(statefulSet)] = true
+		}
+		if _, ok := statefulSet.Spec.Template.Labels["shard"]; ok {
+			t.Errorf("StatefulSet %s: selector label of the imported StatefulSet is kept", statefulSet.Name)
+		}
+	}
+	for _, service := range m.Services {
+		objects["Service/"+service.Name] = true
+	}
+	for _, name := range want {
+		if !objects[name] {
+			t.Errorf("%s is not taken over", name)
+		}
+	}
+}
+
+func TestBuildNoClickHouse(t *testing.T) {
+	statefulSets := []apps.StatefulSet{newStatefulSet("zookeeper", "zookeeper:3.8", 3)}
+	if _, err := importer.Build("prod", "events", statefulSets, nil, nil); err == nil {
+		t.Errorf("expected error in case no ClickHouse is found")
+	}
+}
@@ -36,6 +36,7 @@ const (
 	LabelReadyValueNotReady           = "no"
 	LabelAppName                      = clickhouse_altinity_com.APIGroupName + "/" + "app"
 	LabelAppValue                     = "chop"
+	LabelAppValueChproxy              = "chproxy"
 	LabelCHOP                         = clickhouse_altinity_com.APIGroupName + "/" + "chop"
 	LabelCHOPCommit                   = clickhouse_altinity_com.APIGroupName + "/" + "chop-commit"
 	LabelCHOPDate                     = clickhouse_altinity_com.APIGroupName + "/" + "chop-date"
@@ -48,12 +49,26 @@ const (
 	labelConfigMapValueCHICommon      = "ChiCommon"
 	labelConfigMapValueCHICommonUsers = "ChiCommonUsers"
 	labelConfigMapValueHost           = "Host"
+	labelConfigMapValueCHILogging     = "ChiLogging"
+	labelConfigMapValueCHITopology    = "ChiTopology"
 	LabelService                      = clickhouse_altinity_com.APIGroupName + "/" + "Service"
 	labelServiceValueCHI              = "chi"
 	labelServiceValueCluster          = "cluster"
+	labelServiceValueClusterReadTier  = "cluster-read"
+	labelServiceValueClusterReader    = "cluster-reader"
+	labelServiceValueClusterWriter    = "cluster-writer"
 	labelServiceValueShard            = "shard"
 	labelServiceValueHost             = "host"
+	LabelSecret                       = clickhouse_altinity_com.APIGroupName + "/" + "Secret"
+	LabelChproxy                      = clickhouse_altinity_com.APIGroupName + "/" + "chproxy"
+	labelChproxyValue                 = "yes"
+	labelSecretValueCluster           = "cluster"
+	labelSecretValueConnection        = "connection"
+	labelSecretValueTLS               = "tls"
 	LabelPVCReclaimPolicyName         = clickhouse_altinity_com.APIGroupName + "/" + "reclaimPolicy"
+	LabelTier                         = clickhouse_altinity_com.APIGroupName + "/" + "tier"
+	LabelWriter                       = clickhouse_altinity_com.APIGroupName + "/" + "writer"
+	LabelWriterValueWriter            = "yes"
 
 	// Supplementary service labels - used to cooperate with k8s
 
@@ -96,6 +111,24 @@ func (l *Labeler) GetConfigMapCHICommon() map[string]string {
 		})
 }
 
+// GetConfigMapCHILogging
+func (l *Labeler) GetConfigMapCHILogging() map[string]string {
+	return util.MergeStringMapsOverwrite(
+		l.getCHIScope(),
+		map[string]string{
+			LabelConfigMap: labelConfigMapValueCHILogging,
+		})
+}
+
+// GetConfigMapCHITopology
+func (l *Labeler) GetConfigMapCHITopology() map[string]string {
+	return util.MergeStringMapsOverwrite(
+		l.getCHIScope(),
+		map[string]string{
+			LabelConfigMap: labelConfigMapValueCHITopology,
+		})
+}
+
 // GetConfigMapCHICommonUsers
 func (l *Labeler) GetConfigMapCHICommonUsers() map[string]string {
 	return util.MergeStringMapsOverwrite(
@@ -132,6 +165,60 @@ func (l *Labeler) GetServiceCluster(cluster *api.Cluster) map[string]string {
 		})
 }
 
+// GetServiceClusterReadTier
+func (l *Labeler) GetServiceClusterReadTier(cluster *api.Cluster) map[string]string {
+	return util.MergeStringMapsOverwrite(
+		l.GetClusterScope(cluster),
+		map[string]string{
+			LabelService: labelServiceValueClusterReadTier,
+		})
+}
+
+// GetServiceClusterReader
+func (l *Labeler) GetServiceClusterReader(cluster *api.Cluster) map[string]string {
+	return util.MergeStringMapsOverwrite(
+		l.GetClusterScope(cluster),
+		map[string]string{
+			LabelService: labelServiceValueClusterReader,
+		})
+}
+
+// GetServiceClusterWriter
+func (l *Labeler) GetServiceClusterWriter(cluster *api.Cluster) map[string]string {
+	return util.MergeStringMapsOverwrite(
+		l.GetClusterScope(cluster),
+		map[string]string{
+			LabelService: labelServiceValueClusterWriter,
+		})
+}
+
+// GetSecretCluster
+func (l *Labeler) GetSecretCluster(cluster *api.Cluster) map[string]string {
+	return util.MergeStringMapsOverwrite(
+		l.GetClusterScope(cluster),
+		map[string]string{
+			LabelSecret: labelSecretValueCluster,
+		})
+}
+
+// GetSecretClusterTLS gets labels of the Secret with managed certificates of the cluster
+func (l *Labeler) GetSecretClusterTLS(cluster *api.Cluster) map[string]string {
+	return util.MergeStringMapsOverwrite(
+		l.GetClusterScope(cluster),
+		map[string]string{
+			LabelSecret: labelSecretValueTLS,
+		})
+}
+
+// GetSecretConnection gets labels of the Secret with connection details of the CHI
+func (l *Labeler) GetSecretConnection() map[string]string {
+	return util.MergeStringMapsOverwrite(
+		l.getCHIScope(),
+		map[string]string{
+			LabelSecret: labelSecretValueConnection,
+		})
+}
+
 // GetServiceShard
 func (l *Labeler) GetServiceShard(shard *api.ChiShard) map[string]string {
 	return util.MergeStringMapsOverwrite(
@@ -152,8 +239,8 @@ func (l *Labeler) GetServiceHost(host *api.ChiHost) map[string]string {
 
 // getCHIScope gets labels for CHI-scoped object
 func (l *Labeler) getCHIScope() map[string]string {
-	// Combine generated labels and CHI-provided labels
-	return l.filterOutPredefined(l.appendCHIProvidedTo(l.GetSelectorCHIScope()))
+	// Combine generated, CHI-provided and custom labels
+	return l.filterOutPredefined(appendCustomTo(l.appendCHIProvidedTo(l.GetSelectorCHIScope()), l.chi.Spec.Defaults.GetLabels()))
 }
 
 var labelsNamer = newNamer(namerContextLabels)
@@ -168,6 +255,25 @@ func (l *Labeler) GetSelectorCHIScope() map[string]string {
 	}
 }
 
+// GetChproxy gets labels for chproxy objects of the CHI
+func (l *Labeler) GetChproxy() map[string]string {
+	return util.MergeStringMapsOverwrite(
+		l.getCHIScope(),
+		map[string]string{
+			LabelChproxy: labelChproxyValue,
+		})
+}
+
+// GetSelectorChproxy gets labels to select chproxy pods of the CHI.
+// chproxy pods are not labelled as operator's app, so they are not treated as ClickHouse hosts
+func (l *Labeler) GetSelectorChproxy() map[string]string {
+	return map[string]string{
+		LabelNamespace: labelsNamer.getNamePartNamespace(l.chi),
+		LabelAppName:   LabelAppValueChproxy,
+		LabelCHIName:   labelsNamer.getNamePartCHIName(l.chi),
+	}
+}
+
 // GetSelectorCHIScopeReady gets labels to select a ready-labelled CHI-scoped object
 func (l *Labeler) GetSelectorCHIScopeReady() map[string]string {
 	return appendKeyReady(l.GetSelectorCHIScope())
@@ -175,8 +281,8 @@ func (l *Labeler) GetSelectorCHIScopeReady() map[string]string {
 
 // GetClusterScope gets labels for Cluster-scoped object
 func (l *Labeler) GetClusterScope(cluster *api.Cluster) map[string]string {
-	// Combine generated labels and CHI-provided labels
-	return l.filterOutPredefined(l.appendCHIProvidedTo(GetSelectorClusterScope(cluster)))
+	// Combine generated, CHI-provided and custom labels
+	return l.filterOutPredefined(appendCustomTo(l.appendCHIProvidedTo(GetSelectorClusterScope(cluster)), cluster.GetLabels()))
 }
 
 // GetSelectorClusterScope gets labels to select a Cluster-scoped object
@@ -195,10 +301,29 @@ func GetSelectorClusterScopeReady(cluster *api.Cluster) map[string]string {
 	return appendKeyReady(GetSelectorClusterScope(cluster))
 }
 
+// GetSelectorClusterScopeReadTierReady gets labels to select a ready-labelled read tier hosts of the cluster
+func GetSelectorClusterScopeReadTierReady(cluster *api.Cluster) map[string]string {
+	return util.MergeStringMapsOverwrite(
+		GetSelectorClusterScopeReady(cluster),
+		map[string]string{
+			LabelTier: api.HostTierRead,
+		})
+}
+
+// GetSelectorClusterScopeWriterReady gets labels to select a ready-labelled writer hosts of the cluster
+func GetSelectorClusterScopeWriterReady(cluster *api.Cluster) map[string]string {
+	return util.MergeStringMapsOverwrite(
+		GetSelectorClusterScopeReady(cluster),
+		map[string]string{
+			LabelWriter: LabelWriterValueWriter,
+		})
+}
+
 // getShardScope gets labels for Shard-scoped object
 func (l *Labeler) getShardScope(shard *api.ChiShard) map[string]string {
-	// Combine generated labels and CHI-provided labels
-	return l.filterOutPredefined(l.appendCHIProvidedTo(getSelectorShardScope(shard)))
+	// Combine generated, CHI-provided and custom labels
+	cluster := l.chi.FindCluster(shard.Runtime.Address.ClusterName)
+	return l.filterOutPredefined(appendCustomTo(l.appendCHIProvidedTo(getSelectorShardScope(shard)), cluster.GetLabels()))
 }
 
 // getSelectorShardScope gets labels to select a Shard-scoped object
@@ -220,7 +345,7 @@ func GetSelectorShardScopeReady(shard *api.ChiShard) map[string]string {
 
 // GetHostScope gets labels for Host-scoped object
 func (l *Labeler) GetHostScope(host *api.ChiHost, applySupplementaryServiceLabels bool) map[string]string {
-	// Combine generated labels and CHI-provided labels
+	// Combine generated, CHI-provided and custom labels
 	labels := GetSelectorHostScope(host)
 	if chop.Config().Label.Runtime.AppendScope {
 		// Optional labels
@@ -235,13 +360,21 @@ func (l *Labeler) GetHostScope(host *api.ChiHost, applySupplementaryServiceLabel
 		labels[LabelClusterScopeCycleIndex] = getNamePartClusterScopeCycleIndex(host)
 		labels[LabelClusterScopeCycleOffset] = getNamePartClusterScopeCycleOffset(host)
 	}
+	if host.IsReadTier() {
+		// Tier label is not a part of the host selector, since StatefulSet selector is immutable
+		labels[LabelTier] = api.HostTierRead
+	}
+	if host.IsWriter() {
+		labels[LabelWriter] = LabelWriterValueWriter
+	}
 	if applySupplementaryServiceLabels {
 		// Optional labels
 		// TODO
 		// When we'll have ChkCluster Discovery functionality we can refactor this properly
 		labels = appendConfigLabels(host, labels)
 	}
-	return l.filterOutPredefined(l.appendCHIProvidedTo(labels))
+	cluster := l.chi.FindCluster(host.Runtime.Address.ClusterName)
+	return l.filterOutPredefined(appendCustomTo(l.appendCHIProvidedTo(labels), cluster.GetLabels()))
 }
 
 func appendConfigLabels(host *api.ChiHost, labels map[string]string) map[string]string {
@@ -317,6 +450,13 @@ func GetSelectorHostScope(host *api.ChiHost) map[string]string {
 	}
 }
 
+// GetSelectorHostScopeOfCHI gets selector of the host with the same cluster, shard and replica in another CHI
+func GetSelectorHostScopeOfCHI(host *api.ChiHost, chiName string) map[string]string {
+	selector := GetSelectorHostScope(host)
+	selector[LabelCHIName] = labelsNamer.namePartChiName(chiName)
+	return selector
+}
+
 // filterOutPredefined filters out predefined values
 func (l *Labeler) filterOutPredefined(m map[string]string) map[string]string {
 	return util.CopyMapFilter(m, nil, []string{})
@@ -328,6 +468,11 @@ func (l *Labeler) appendCHIProvidedTo(dst map[string]string) map[string]string {
 	return util.MergeStringMapsOverwrite(dst, sourceLabels)
 }
 
+// appendCustomTo appends labels or annotations specified by .spec.defaults or cluster, already present keys are kept
+func appendCustomTo(dst map[string]string, custom map[string]string) map[string]string {
+	return util.MergeStringMapsPreserve(dst, custom)
+}
+
 // makeSetFromObjectMeta makes k8sLabels.Set from ObjectMeta
 func makeSetFromObjectMeta(objMeta *meta.ObjectMeta) (k8sLabels.Set, error) {
 	// Check mandatory labels are in place
@@ -350,6 +495,7 @@ func makeSetFromObjectMeta(objMeta *meta.ObjectMeta) (k8sLabels.Set, error) {
 		LabelReplicaName,
 		LabelConfigMap,
 		LabelService,
+		LabelSecret,
 	}
 
 	set := k8sLabels.Set{}
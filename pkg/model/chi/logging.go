@@ -0,0 +1,213 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"bytes"
+
+	core "k8s.io/api/core/v1"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/util"
+)
+
+const (
+	// DefaultLoggingVectorDockerImage specifies default vector docker image to be used
+	DefaultLoggingVectorDockerImage = "timberio/vector:0.34.1-alpine"
+	// DefaultLoggingFluentBitDockerImage specifies default fluent-bit docker image to be used
+	DefaultLoggingFluentBitDockerImage = "fluent/fluent-bit:2.2.0"
+	// LoggingContainerName specifies name of the log shipping agent container in the pod
+	LoggingContainerName = "clickhouse-logging"
+
+	// DirPathLoggingConfig specifies full path of folder where log shipping agent config is mounted
+	DirPathLoggingConfig = "/etc/clickhouse-logging"
+	// FileNameClickHouseLog specifies name of the ClickHouse log file shipped by the agent
+	FileNameClickHouseLog = "clickhouse-server.log"
+
+	// Names of log shipping agent config files
+	fileNameLoggingVectorConfig    = "vector.yaml"
+	fileNameLoggingVectorOutput    = "output.yaml"
+	fileNameLoggingFluentBitConfig = "fluent-bit.conf"
+	fileNameLoggingFluentBitOutput = "output.conf"
+
+	// loggingTag specifies name of the vector transform or fluent-bit tag outputs consume logs from
+	loggingTag = "clickhouse"
+)
+
+// ENV vars log entries are labeled with
+const (
+	LoggingNamespaceEnvName = "LOGGING_NAMESPACE"
+	LoggingCHIEnvName       = "LOGGING_CHI"
+	LoggingClusterEnvName   = "LOGGING_CLUSTER"
+	LoggingShardEnvName     = "LOGGING_SHARD"
+	LoggingReplicaEnvName   = "LOGGING_REPLICA"
+	LoggingHostEnvName      = "LOGGING_HOST"
+)
+
+// loggingLabels maps log entry field to ENV var its value is taken from
+var loggingLabels = []struct {
+	field string
+	env   string
+}{
+	{"namespace", LoggingNamespaceEnvName},
+	{"chi", LoggingCHIEnvName},
+	{"cluster", LoggingClusterEnvName},
+	{"shard", LoggingShardEnvName},
+	{"replica", LoggingReplicaEnvName},
+	{"host", LoggingHostEnvName},
+}
+
+// GetLoggingEnvVars gets ENV vars the log shipping agent of the host labels log entries with
+func GetLoggingEnvVars(host *api.ChiHost) []core.EnvVar {
+	values := map[string]string{
+		LoggingNamespaceEnvName: host.Runtime.Address.Namespace,
+		LoggingCHIEnvName:       host.Runtime.Address.CHIName,
+		LoggingClusterEnvName:   host.Runtime.Address.ClusterName,
+		LoggingShardEnvName:     host.Runtime.Address.ShardName,
+		LoggingReplicaEnvName:   host.Runtime.Address.ReplicaName,
+		LoggingHostEnvName:      host.Runtime.Address.HostName,
+	}
+	var env []core.EnvVar
+	for _, label := range loggingLabels {
+		env = append(env, core.EnvVar{
+			Name:  label.env,
+			Value: values[label.env],
+		})
+	}
+	return env
+}
+
+// GetLoggingArgs gets args of the log shipping agent container
+func GetLoggingArgs(logging *api.ChiLogging) []string {
+	switch logging.Agent {
+	case api.LoggingAgentFluentBit:
+		return []string{"-c", DirPathLoggingConfig + "/" + fileNameLoggingFluentBitConfig}
+	default:
+		return []string{"--config-dir", DirPathLoggingConfig, "--watch-config"}
+	}
+}
+
+// CreateLoggingConfigFiles creates config files of the log shipping agent.
+// Returns nil in case logging is not enabled
+func CreateLoggingConfigFiles(chi *api.ClickHouseInstallation) map[string]string {
+	logging := chi.Spec.Logging
+	if !logging.IsEnabled() {
+		return nil
+	}
+
+	output := logging.Output
+	switch logging.Agent {
+	case api.LoggingAgentFluentBit:
+		if output == "" {
+			output = createLoggingFluentBitOutput()
+		}
+		return map[string]string{
+			fileNameLoggingFluentBitConfig: createLoggingFluentBitConfig(logging),
+			fileNameLoggingFluentBitOutput: output,
+		}
+	default:
+		if output == "" {
+			output = createLoggingVectorOutput()
+		}
+		return map[string]string{
+			fileNameLoggingVectorConfig: createLoggingVectorConfig(logging),
+			fileNameLoggingVectorOutput: output,
+		}
+	}
+}
+
+// createLoggingVectorConfig creates vector config tailing ClickHouse log and labeling log entries
+func createLoggingVectorConfig(logging *api.ChiLogging) string {
+	b := &bytes.Buffer{}
+	util.Iline(b, 0, "data_dir: /var/lib/vector")
+	util.Iline(b, 0, "sources:")
+	util.Iline(b, 2, "clickhouse_log:")
+	util.Iline(b, 4, "type: file")
+	util.Iline(b, 4, "include:")
+	util.Iline(b, 6, "- %s/%s", DirPathClickHouseLog, FileNameClickHouseLog)
+	if !logging.IsStructured() {
+		// Stack traces span multiple lines, each log entry starts with the date
+		util.Iline(b, 4, "multiline:")
+		util.Iline(b, 6, `start_pattern: '^\d{4}\.\d{2}\.\d{2} '`)
+		util.Iline(b, 6, `condition_pattern: '^\d{4}\.\d{2}\.\d{2} '`)
+		util.Iline(b, 6, "mode: halt_before")
+		util.Iline(b, 6, "timeout_ms: 1000")
+	}
+	util.Iline(b, 0, "transforms:")
+	util.Iline(b, 2, "%s:", loggingTag)
+	util.Iline(b, 4, "type: remap")
+	util.Iline(b, 4, "inputs:")
+	util.Iline(b, 6, "- clickhouse_log")
+	util.Iline(b, 4, "source: |")
+	if logging.IsStructured() {
+		util.Iline(b, 6, "parsed, err = parse_json(.message)")
+		util.Iline(b, 6, "if err == null && is_object(parsed) {")
+		util.Iline(b, 8, ". = merge(., object!(parsed))")
+		util.Iline(b, 6, "}")
+	}
+	for _, label := range loggingLabels {
+		util.Iline(b, 6, `.%s = "${%s}"`, label.field, label.env)
+	}
+	return b.String()
+}
+
+// createLoggingVectorOutput creates default vector output, which prints log entries to stdout
+func createLoggingVectorOutput() string {
+	b := &bytes.Buffer{}
+	util.Iline(b, 0, "sinks:")
+	util.Iline(b, 2, "stdout:")
+	util.Iline(b, 4, "type: console")
+	util.Iline(b, 4, "inputs:")
+	util.Iline(b, 6, "- %s", loggingTag)
+	util.Iline(b, 4, "encoding:")
+	util.Iline(b, 6, "codec: json")
+	return b.String()
+}
+
+// createLoggingFluentBitConfig creates fluent-bit config tailing ClickHouse log and labeling log entries
+func createLoggingFluentBitConfig(logging *api.ChiLogging) string {
+	b := &bytes.Buffer{}
+	util.Iline(b, 0, "[SERVICE]")
+	util.Iline(b, 4, "Flush        1")
+	util.Iline(b, 4, "Parsers_File /fluent-bit/etc/parsers.conf")
+	util.Iline(b, 0, "")
+	util.Iline(b, 0, "[INPUT]")
+	util.Iline(b, 4, "Name tail")
+	util.Iline(b, 4, "Tag  %s", loggingTag)
+	util.Iline(b, 4, "Path %s/%s", DirPathClickHouseLog, FileNameClickHouseLog)
+	if logging.IsStructured() {
+		util.Iline(b, 4, "Parser json")
+	}
+	util.Iline(b, 0, "")
+	util.Iline(b, 0, "[FILTER]")
+	util.Iline(b, 4, "Name  record_modifier")
+	util.Iline(b, 4, "Match %s", loggingTag)
+	for _, label := range loggingLabels {
+		util.Iline(b, 4, "Record %s ${%s}", label.field, label.env)
+	}
+	util.Iline(b, 0, "")
+	util.Iline(b, 0, "@INCLUDE %s", fileNameLoggingFluentBitOutput)
+	return b.String()
+}
+
+// createLoggingFluentBitOutput creates default fluent-bit output, which prints log entries to stdout
+func createLoggingFluentBitOutput() string {
+	b := &bytes.Buffer{}
+	util.Iline(b, 0, "[OUTPUT]")
+	util.Iline(b, 4, "Name   stdout")
+	util.Iline(b, 4, "Match  %s", loggingTag)
+	util.Iline(b, 4, "Format json_lines")
+	return b.String()
+}
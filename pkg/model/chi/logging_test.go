@@ -0,0 +1,61 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi_test
+
+import (
+	"strings"
+	"testing"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	model "github.com/altinity/clickhouse-operator/pkg/model/chi"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/builder"
+)
+
+func TestCreateLoggingConfigFiles(t *testing.T) {
+	for _, agent := range []string{api.LoggingAgentVector, api.LoggingAgentFluentBit} {
+		input := builder.NewCHI("test", "logging",
+			builder.WithCluster(builder.NewCluster("main", builder.WithShards(2))),
+		)
+		input.Spec.Logging = &api.ChiLogging{
+			Agent:      agent,
+			Structured: api.NewStringBool(true),
+		}
+		chi := normalize(t, input)
+
+		if logging := model.NewClickHouseConfigGenerator(chi).GetLogging(); !strings.Contains(logging, "<type>json</type>") {
+			t.Errorf("%s: structured logs are not configured:\n%s", agent, logging)
+		}
+
+		config := model.CreateLoggingConfigFiles(chi)
+		found := false
+		for _, content := range config {
+			found = found || strings.Contains(content, "${"+model.LoggingShardEnvName+"}")
+		}
+		if !found {
+			t.Errorf("%s: log entries are not labeled with shard:\n%v", agent, config)
+		}
+
+		chi.WalkHosts(func(host *api.ChiHost) error {
+			env := map[string]string{}
+			for _, envVar := range model.GetLoggingEnvVars(host) {
+				env[envVar.Name] = envVar.Value
+			}
+			if (env[model.LoggingClusterEnvName] != "main") || (env[model.LoggingShardEnvName] == "") {
+				t.Errorf("%s: host %s: logging agent is not labeled: %v", agent, host.GetName(), env)
+			}
+			return nil
+		})
+	}
+}
@@ -16,6 +16,7 @@ package chi
 
 import (
 	"fmt"
+	"regexp"
 	"strconv"
 	"strings"
 
@@ -44,9 +45,24 @@ const (
 	// chiServiceNamePattern is a template of CHI Service name. "clickhouse-{chi}"
 	chiServiceNamePattern = "clickhouse-" + macrosChiName
 
+	// chproxyNamePattern is a template of chproxy Deployment, Service and Secret name. "chproxy-{chi}"
+	chproxyNamePattern = "chproxy-" + macrosChiName
+
+	// connectionSecretNamePattern is a template of Secret with connection details name. "chi-{chi}-connection"
+	connectionSecretNamePattern = "chi-" + macrosChiName + "-connection"
+
 	// clusterServiceNamePattern is a template of cluster Service name. "cluster-{chi}-{cluster}"
 	clusterServiceNamePattern = "cluster-" + macrosChiName + "-" + macrosClusterName
 
+	// clusterReadTierServiceNamePattern is a template of cluster read tier Service name. "cluster-{chi}-{cluster}-read"
+	clusterReadTierServiceNamePattern = "cluster-" + macrosChiName + "-" + macrosClusterName + "-read"
+
+	// clusterReaderServiceNamePattern is a template of cluster reader Service name. "cluster-{chi}-{cluster}-reader"
+	clusterReaderServiceNamePattern = "cluster-" + macrosChiName + "-" + macrosClusterName + "-reader"
+
+	// clusterWriterServiceNamePattern is a template of cluster writer Service name. "cluster-{chi}-{cluster}-writer"
+	clusterWriterServiceNamePattern = "cluster-" + macrosChiName + "-" + macrosClusterName + "-writer"
+
 	// shardServiceNamePattern is a template of shard Service name. "shard-{chi}-{cluster}-{shard}"
 	shardServiceNamePattern = "shard-" + macrosChiName + "-" + macrosClusterName + "-" + macrosShardName
 
@@ -65,6 +81,12 @@ const (
 	// configMapCommonUsersNamePattern is a template of common users settings for the CHI ConfigMap. "chi-{chi}-common-usersd"
 	configMapCommonUsersNamePattern = "chi-" + macrosChiName + "-common-usersd"
 
+	// configMapLoggingNamePattern is a template of log shipping agent config ConfigMap. "chi-{chi}-logging"
+	configMapLoggingNamePattern = "chi-" + macrosChiName + "-logging"
+
+	// configMapTopologyNamePattern is a template of cluster topology ConfigMap. "chi-{chi}-topology"
+	configMapTopologyNamePattern = "chi-" + macrosChiName + "-topology"
+
 	// configMapHostNamePattern is a template of macros ConfigMap. "chi-{chi}-deploy-confd-{cluster}-{shard}-{host}"
 	configMapHostNamePattern = "chi-" + macrosChiName + "-deploy-confd-" + macrosClusterName + "-" + macrosHostName
 
@@ -76,6 +98,9 @@ const (
 	// Ex.: my-dev-namespace.svc.cluster.local
 	namespaceDomainPattern = "%s.svc.cluster.local"
 
+	// namespaceDomainDefaultRegexp is the default namespace domain as it appears in host regexp templates
+	namespaceDomainDefaultRegexp = macrosNamespace + `\.svc\.cluster\.local`
+
 	// ServiceName.domain.name
 	serviceFQDNPattern = "%s" + "." + namespaceDomainPattern
 
@@ -396,6 +421,26 @@ func CreateCHIServiceFQDN(chi *api.ClickHouseInstallation) string {
 	)
 }
 
+// CreateConfigMapLoggingName returns a name for a ConfigMap with log shipping agent config
+func CreateConfigMapLoggingName(chi *api.ClickHouseInstallation) string {
+	return Macro(chi).Line(configMapLoggingNamePattern)
+}
+
+// CreateConfigMapTopologyName returns a name for a ConfigMap with cluster topology published for external consumers
+func CreateConfigMapTopologyName(chi *api.ClickHouseInstallation) string {
+	return Macro(chi).Line(configMapTopologyNamePattern)
+}
+
+// CreateChproxyName returns a name of chproxy Deployment, Service and Secret of the CHI
+func CreateChproxyName(chi *api.ClickHouseInstallation) string {
+	return Macro(chi).Line(chproxyNamePattern)
+}
+
+// CreateConnectionSecretName returns a name of the Secret with connection details of the CHI
+func CreateConnectionSecretName(chi *api.ClickHouseInstallation) string {
+	return Macro(chi).Line(connectionSecretNamePattern)
+}
+
 // CreateClusterServiceName returns a name of a cluster's Service
 func CreateClusterServiceName(cluster *api.Cluster) string {
 	// Name can be generated either from default name pattern,
@@ -417,6 +462,31 @@ func CreateClusterServiceName(cluster *api.Cluster) string {
 	return Macro(cluster).Line(pattern)
 }
 
+// CreateClusterReadTierServiceName returns a name of a cluster's read tier Service
+func CreateClusterReadTierServiceName(cluster *api.Cluster) string {
+	return Macro(cluster).Line(clusterReadTierServiceNamePattern)
+}
+
+// CreateClusterReaderServiceName returns a name of a cluster's reader Service
+func CreateClusterReaderServiceName(cluster *api.Cluster) string {
+	pattern := clusterReaderServiceNamePattern
+	if template, ok := cluster.GetReaderServiceTemplate(); ok && (template.GenerateName != "") {
+		// ServiceTemplate has explicitly specified name pattern
+		pattern = template.GenerateName
+	}
+	return Macro(cluster).Line(pattern)
+}
+
+// CreateClusterWriterServiceName returns a name of a cluster's writer Service
+func CreateClusterWriterServiceName(cluster *api.Cluster) string {
+	pattern := clusterWriterServiceNamePattern
+	if template, ok := cluster.GetWriterServiceTemplate(); ok && (template.GenerateName != "") {
+		// ServiceTemplate has explicitly specified name pattern
+		pattern = template.GenerateName
+	}
+	return Macro(cluster).Line(pattern)
+}
+
 // CreateShardServiceName returns a name of a shard's Service
 func CreateShardServiceName(shard *api.ChiShard) string {
 	// Name can be generated either from default name pattern,
@@ -484,6 +554,10 @@ func CreateInstanceHostname(host *api.ChiHost) string {
 		// .my-dev-namespace.svc.cluster.local
 		return createPodFQDN(host)
 	}
+	if host.GetCluster().HasRemoteReplicas() {
+		// Remote replicas live outside of the namespace and have to reach hosts by FQDN
+		return createPodFQDN(host)
+	}
 
 	return CreatePodHostname(host)
 }
@@ -587,6 +661,33 @@ func createPodFQDN(host *api.ChiHost) string {
 	)
 }
 
+// CreateRemoteReplicaFQDN creates a fully qualified domain name of a host of another CHI
+// which serves as a remote replica of the shard. Hosts of the CHI are expected to be named by default patterns
+func CreateRemoteReplicaFQDN(remote *api.ChiRemoteReplicaCHI, shard *api.ChiShard) string {
+	replica := &api.ChiReplica{Name: remote.Replica}
+	host := &api.ChiHost{}
+	host.Runtime.Address = api.ChiHostAddress{
+		Namespace:   remote.Namespace,
+		CHIName:     remote.Name,
+		ClusterName: remote.Cluster,
+		ShardName:   shard.Name,
+		ReplicaName: remote.Replica,
+		HostName:    CreateHostName(host, shard, 0, replica, 0),
+	}
+
+	pattern := podFQDNPattern
+	if remote.NamespaceDomainPattern != "" {
+		// NamespaceDomainPattern has been explicitly specified
+		pattern = "%s." + remote.NamespaceDomainPattern
+	}
+
+	return fmt.Sprintf(
+		pattern,
+		Macro(host).Line(statefulSetServiceNamePattern),
+		remote.Namespace,
+	)
+}
+
 // createPodFQDNsOfCluster creates fully qualified domain names of all pods in a cluster
 func createPodFQDNsOfCluster(cluster *api.Cluster) (fqdns []string) {
 	cluster.WalkHosts(func(host *api.ChiHost) error {
@@ -654,6 +755,14 @@ func CreateFQDNs(obj interface{}, scope interface{}, excludeSelf bool) []string
 // For example, `template` can be defined in operator config:
 // HostRegexpTemplate: chi-{chi}-[^.]+\\d+-\\d+\\.{namespace}.svc.cluster.local$"
 func CreatePodHostnameRegexp(chi *api.ClickHouseInstallation, template string) string {
+	if chi.Spec.NamespaceDomainPattern != "" {
+		// Custom namespace domain replaces the default one, so interserver hosts still match
+		parts := strings.SplitN(chi.Spec.NamespaceDomainPattern, "%s", 2)
+		if len(parts) == 2 {
+			domain := regexp.QuoteMeta(parts[0]) + macrosNamespace + regexp.QuoteMeta(parts[1])
+			template = strings.Replace(template, namespaceDomainDefaultRegexp, domain, 1)
+		}
+	}
 	return Macro(chi).Line(template)
 }
 
@@ -738,6 +847,11 @@ func createPVCName(host *api.ChiHost, volumeMountName string) string {
 	return volumeMountName + "-" + CreatePodName(host)
 }
 
+// CreateForkVolumeSnapshotName creates name of the VolumeSnapshot the PVC of a forked CHI is restored from
+func CreateForkVolumeSnapshotName(pvcName string) string {
+	return pvcName + "-fork"
+}
+
 // CreateClusterAutoSecretName creates Secret name where auto-generated secret is kept
 func CreateClusterAutoSecretName(cluster *api.Cluster) string {
 	if cluster.Name == "" {
@@ -753,3 +867,12 @@ func CreateClusterAutoSecretName(cluster *api.Cluster) string {
 		cluster.Name,
 	)
 }
+
+// CreateClusterTLSSecretName creates name of the Secret with managed certificates of the cluster
+func CreateClusterTLSSecretName(cluster *api.Cluster) string {
+	return fmt.Sprintf(
+		"%s-%s-tls",
+		cluster.Runtime.CHI.Name,
+		cluster.Name,
+	)
+}
@@ -0,0 +1,42 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"testing"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+)
+
+func TestCreatePodHostnameRegexp(t *testing.T) {
+	template := `(chi-{chi}-[^.]+\d+-\d+|clickhouse\-{chi})\.{namespace}\.svc\.cluster\.local$`
+	tests := []struct {
+		pattern string
+		want    string
+	}{
+		{"", `(chi-test-[^.]+\d+-\d+|clickhouse\-test)\.dev\.svc\.cluster\.local$`},
+		{"%s.svc.my.domain", `(chi-test-[^.]+\d+-\d+|clickhouse\-test)\.dev\.svc\.my\.domain$`},
+		{"%s.svc.clusterset.local", `(chi-test-[^.]+\d+-\d+|clickhouse\-test)\.dev\.svc\.clusterset\.local$`},
+	}
+	for _, tt := range tests {
+		chi := &api.ClickHouseInstallation{}
+		chi.Name = "test"
+		chi.Namespace = "dev"
+		chi.Spec.NamespaceDomainPattern = tt.pattern
+		if got := CreatePodHostnameRegexp(chi, template); got != tt.want {
+			t.Errorf("pattern %q: got %s, want %s", tt.pattern, got, tt.want)
+		}
+	}
+}
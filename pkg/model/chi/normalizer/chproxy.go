@@ -0,0 +1,82 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package normalizer
+
+import (
+	log "github.com/altinity/clickhouse-operator/pkg/announcer"
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	model "github.com/altinity/clickhouse-operator/pkg/model/chi"
+)
+
+// normalizeChproxy normalizes .spec.chproxy. Should be called after clusters are normalized
+func (n *Normalizer) normalizeChproxy(chproxy *api.ChiChproxy) *api.ChiChproxy {
+	if !chproxy.IsEnabled() || (n.ctx.GetTarget().FindCluster(0) == nil) {
+		// Nothing to route queries to
+		return chproxy
+	}
+
+	chproxy.Enabled = chproxy.Enabled.Normalize(true)
+	if chproxy.Image == "" {
+		chproxy.Image = model.DefaultChproxyDockerImage
+	}
+	if chproxy.Replicas == nil {
+		replicas := int32(1)
+		chproxy.Replicas = &replicas
+	}
+
+	caches := chproxy.Caches[:0]
+	for _, cache := range chproxy.Caches {
+		if cache.Name == "" {
+			continue
+		}
+		if cache.MaxSize == "" {
+			cache.MaxSize = model.ChproxyDefaultCacheMaxSize
+		}
+		if cache.Expire == "" {
+			cache.Expire = model.ChproxyDefaultCacheExpire
+		}
+		caches = append(caches, cache)
+	}
+	chproxy.Caches = caches
+
+	if len(chproxy.Users) == 0 {
+		chproxy.Users = append(chproxy.Users, api.ChiChproxyUser{
+			Name: model.ChproxyDefaultUser,
+		})
+	}
+	users := chproxy.Users[:0]
+	for _, user := range chproxy.Users {
+		if user.Name == "" {
+			continue
+		}
+		if user.ToCluster == "" {
+			user.ToCluster = n.ctx.GetTarget().FindCluster(0).Name
+		}
+		if n.ctx.GetTarget().FindCluster(user.ToCluster) == nil {
+			log.V(1).M(n.ctx.GetTarget()).F().Warning("chproxy user %s refers to unknown cluster %s, skip it", user.Name, user.ToCluster)
+			continue
+		}
+		if user.ToUser == "" {
+			user.ToUser = model.ChproxyDefaultToUser
+		}
+		if chproxy.FindCache(user.Cache) == nil {
+			user.Cache = ""
+		}
+		users = append(users, user)
+	}
+	chproxy.Users = users
+
+	return chproxy
+}
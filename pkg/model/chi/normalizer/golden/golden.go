@@ -0,0 +1,115 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package golden provides golden-file test harness for CHI normalization.
+// Downstream tools may use it to assert normalization output stays stable across operator versions.
+package golden
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kubernetes-sigs/yaml"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/normalizer"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/render"
+)
+
+const (
+	// EnvUpdate specifies ENV var which, being set, makes golden files to be written instead of compared
+	EnvUpdate = "GOLDEN_UPDATE"
+	// SuffixGolden specifies suffix of golden files
+	SuffixGolden = ".golden.yaml"
+	// TaskID is used as .spec.taskID of CHIs having no taskID specified, in order to have stable output
+	TaskID = "golden"
+)
+
+// Init initializes operator's configuration used by normalization.
+// Configuration is read from configFilePath, empty path means default configuration.
+// Does nothing in case configuration is already initialized.
+func Init(configFilePath string) {
+	render.Init(configFilePath)
+}
+
+// Test normalizes each CHI manifest found in dir and compares normalized CHI with <manifest>.golden.yaml file
+func Test(t *testing.T, dir string, options *normalizer.Options) {
+	t.Helper()
+	Init("")
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.yaml"))
+	if err != nil {
+		t.Fatalf("unable to list manifests in %s err: %v", dir, err)
+	}
+
+	for _, file := range files {
+		if strings.HasSuffix(file, SuffixGolden) {
+			continue
+		}
+		file := file
+		t.Run(filepath.Base(file), func(t *testing.T) {
+			got, err := Normalize(file, options)
+			if err != nil {
+				t.Fatalf("unable to normalize %s err: %v", file, err)
+			}
+
+			goldenFile := strings.TrimSuffix(file, ".yaml") + SuffixGolden
+			if os.Getenv(EnvUpdate) != "" {
+				if err := os.WriteFile(goldenFile, got, 0644); err != nil {
+					t.Fatalf("unable to write %s err: %v", goldenFile, err)
+				}
+				return
+			}
+
+			want, err := os.ReadFile(goldenFile)
+			if err != nil {
+				t.Fatalf("unable to read %s err: %v. Set %s=1 to create it", goldenFile, err, EnvUpdate)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("normalized %s differs from %s. Set %s=1 to update.\ngot:\n%s", file, goldenFile, EnvUpdate, got)
+			}
+		})
+	}
+}
+
+// Normalize normalizes CHI manifest from the file and returns normalized CHI as YAML.
+// Status is skipped, since it carries operator's version and runtime info.
+func Normalize(file string, options *normalizer.Options) ([]byte, error) {
+	data, err := os.ReadFile(filepath.Clean(file))
+	if err != nil {
+		return nil, err
+	}
+
+	chi := &api.ClickHouseInstallation{}
+	if err := yaml.Unmarshal(data, chi); err != nil {
+		return nil, err
+	}
+	if chi.Spec.TaskID == nil {
+		taskID := TaskID
+		chi.Spec.TaskID = &taskID
+	}
+
+	normalized, err := normalizer.NewNormalizer(render.NoSecrets).CreateTemplatedCHI(chi, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return yaml.Marshal(normalized.Copy(api.CopyCHIOptions{
+		SkipStatus:        true,
+		SkipManagedFields: true,
+	}))
+}
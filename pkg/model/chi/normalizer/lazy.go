@@ -0,0 +1,108 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package normalizer
+
+import (
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/apis/deployment"
+	model "github.com/altinity/clickhouse-operator/pkg/model/chi"
+)
+
+// isLazyLayout checks whether shards of the cluster are to be expanded on demand instead of being materialized.
+// Only Standard layout, where neither shards nor replicas are specified explicitly, can be expanded lazily,
+// since all its shards are built from the cluster-level specification only.
+// Hosts with allocated ports are not expanded lazily, since ports are allocated across all hosts of the CHI.
+func (n *Normalizer) isLazyLayout(cluster *api.Cluster) bool {
+	if !n.ctx.Options().IsFeatureEnabled(FeatureGateLazyLayout) {
+		return false
+	}
+	if n.hasAllocatedPorts(cluster) {
+		return false
+	}
+	return !cluster.Layout.ShardsSpecified && !cluster.Layout.ReplicasSpecified
+}
+
+// hasAllocatedPorts checks whether host template of the cluster specifies Allocated port distribution.
+// Clusters are normalized before templates, so host template is looked up without index
+func (n *Normalizer) hasAllocatedPorts(cluster *api.Cluster) bool {
+	templates := n.ctx.GetTarget().Spec.Templates
+	if templates == nil {
+		return false
+	}
+	for i := range templates.HostTemplates {
+		if template := &templates.HostTemplates[i]; template.Name == cluster.Templates.GetHostTemplate() {
+			return hasPortDistribution(template, deployment.PortDistributionAllocated)
+		}
+	}
+	return false
+}
+
+// normalizeLazyCluster normalizes cluster with Standard layout without materializing shards and hosts.
+// Replicas are kept in the layout, however they do not reference hosts.
+func (n *Normalizer) normalizeLazyCluster(cluster *api.Cluster) *api.Cluster {
+	cluster.Layout.Shards = nil
+	cluster.Layout.HostsField = nil
+	n.ensureClusterLayoutReplicas(cluster.Layout)
+	n.appendClusterSecretEnvVar(cluster)
+
+	cluster.Runtime.ShardExpander = n.newShardExpander(cluster)
+
+	cluster.WalkReplicas(func(index int, replica *api.ChiReplica) error {
+		n.normalizeReplica(replica, cluster, index)
+		return nil
+	})
+
+	return cluster
+}
+
+// newShardExpander creates ShardExpander of the lazy cluster
+func (n *Normalizer) newShardExpander(cluster *api.Cluster) *api.ShardExpander {
+	// Expander is called after normalization is completed, and the normalizer may be reused for another CHI by then,
+	// so the expander keeps normalizer of its own bound to the current context
+	expander := &Normalizer{
+		secretGet: n.secretGet,
+		ctx:       n.ctx,
+	}
+	return api.NewShardExpander(func(index int) *api.ChiShard {
+		return expander.expandShard(cluster, index)
+	})
+}
+
+// expandShard builds normalized and finalized shard of the lazy cluster,
+// the same as it would be after eager normalization
+func (n *Normalizer) expandShard(cluster *api.Cluster, shardIndex int) *api.ChiShard {
+	chi := n.ctx.GetTarget()
+
+	shard := &api.ChiShard{}
+	n.normalizeShard(shard, cluster, shardIndex)
+	for replicaIndex, host := range shard.Hosts {
+		n.normalizeHost(host, shard, cluster.GetReplica(replicaIndex), cluster, shardIndex, replicaIndex)
+	}
+	shard.MarkWriterHost()
+	shard.MarkSpotHosts(cluster.Spot)
+
+	// Finalize the shard the same way finalizeCHI does
+	chi.FillShardSelfCalculatedAddressInfo(cluster, shardIndex, shard)
+	shard.Runtime.CHI = chi
+	shard.WalkHosts(func(host *api.ChiHost) error {
+		host.Runtime.CHI = chi
+		hostApplyHostTemplate(host, n.getHostTemplate(host))
+		host.Runtime.Address.StatefulSet = model.CreateStatefulSetName(host)
+		host.Runtime.Address.FQDN = model.CreateFQDN(host)
+		return nil
+	})
+
+	return shard
+}
@@ -0,0 +1,49 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package normalizer
+
+import (
+	log "github.com/altinity/clickhouse-operator/pkg/announcer"
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	model "github.com/altinity/clickhouse-operator/pkg/model/chi"
+)
+
+// normalizeLogging normalizes .spec.logging
+func (n *Normalizer) normalizeLogging(logging *api.ChiLogging) *api.ChiLogging {
+	if !logging.IsEnabled() {
+		return logging
+	}
+
+	logging.Enabled = logging.Enabled.Normalize(true)
+	switch logging.Agent {
+	case api.LoggingAgentVector, api.LoggingAgentFluentBit:
+	case "":
+		logging.Agent = api.LoggingAgentVector
+	default:
+		log.V(1).M(n.ctx.GetTarget()).F().Warning("unknown logging agent %s, use %s", logging.Agent, api.LoggingAgentVector)
+		logging.Agent = api.LoggingAgentVector
+	}
+	if logging.Image == "" {
+		switch logging.Agent {
+		case api.LoggingAgentFluentBit:
+			logging.Image = model.DefaultLoggingFluentBitDockerImage
+		default:
+			logging.Image = model.DefaultLoggingVectorDockerImage
+		}
+	}
+	logging.Structured = logging.Structured.Normalize(false)
+
+	return logging
+}
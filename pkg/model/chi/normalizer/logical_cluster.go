@@ -0,0 +1,90 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package normalizer
+
+import (
+	log "github.com/altinity/clickhouse-operator/pkg/announcer"
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	model "github.com/altinity/clickhouse-operator/pkg/model/chi"
+)
+
+// normalizeLogicalClusters normalizes .spec.configuration.logicalClusters. Should be called after clusters are normalized
+func (n *Normalizer) normalizeLogicalClusters(logicalClusters []api.ChiLogicalCluster) []api.ChiLogicalCluster {
+	result := logicalClusters[:0]
+	for _, logicalCluster := range logicalClusters {
+		if !n.isLogicalClusterNameAvailable(logicalCluster.Name, result) {
+			log.V(1).M(n.ctx.GetTarget()).F().Warning("logical cluster name '%s' is empty or already taken, skip it", logicalCluster.Name)
+			continue
+		}
+
+		switch logicalCluster.Layout {
+		case
+			api.LogicalClusterLayoutOneShard,
+			api.LogicalClusterLayoutShardPerHost,
+			api.LogicalClusterLayoutShards:
+		default:
+			logicalCluster.Layout = api.LogicalClusterLayoutOneShard
+		}
+
+		clusters := make([]string, 0, len(logicalCluster.Clusters))
+		for _, name := range logicalCluster.Clusters {
+			if n.ctx.GetTarget().FindCluster(name) == nil {
+				log.V(1).M(n.ctx.GetTarget()).F().Warning("logical cluster %s refers to unknown cluster %s, skip it", logicalCluster.Name, name)
+				continue
+			}
+			clusters = append(clusters, name)
+		}
+		if (len(logicalCluster.Clusters) > 0) && (len(clusters) == 0) {
+			// None of the requested clusters exists, do not fall back to all clusters
+			continue
+		}
+		logicalCluster.Clusters = clusters
+
+		// Shards layout inherits internal replication of the source shards unless specified explicitly
+		switch logicalCluster.Layout {
+		case api.LogicalClusterLayoutOneShard:
+			logicalCluster.InternalReplication = logicalCluster.InternalReplication.Normalize(true)
+		case api.LogicalClusterLayoutShardPerHost:
+			logicalCluster.InternalReplication = logicalCluster.InternalReplication.Normalize(false)
+		case api.LogicalClusterLayoutShards:
+			if logicalCluster.InternalReplication.HasValue() {
+				logicalCluster.InternalReplication = logicalCluster.InternalReplication.Normalize(true)
+			}
+		}
+
+		result = append(result, logicalCluster)
+	}
+	return result
+}
+
+// isLogicalClusterNameAvailable checks whether logical cluster name does not clash with other remote_servers entries
+func (n *Normalizer) isLogicalClusterNameAvailable(name string, logicalClusters []api.ChiLogicalCluster) bool {
+	switch name {
+	case
+		"",
+		model.OneShardAllReplicasClusterName,
+		model.AllShardsOneReplicaClusterName:
+		return false
+	}
+	if n.ctx.GetTarget().FindCluster(name) != nil {
+		return false
+	}
+	for i := range logicalClusters {
+		if logicalClusters[i].Name == name {
+			return false
+		}
+	}
+	return true
+}
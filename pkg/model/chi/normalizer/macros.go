@@ -0,0 +1,51 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package normalizer
+
+import (
+	"regexp"
+
+	log "github.com/altinity/clickhouse-operator/pkg/announcer"
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	model "github.com/altinity/clickhouse-operator/pkg/model/chi"
+)
+
+// macroNameRegexp specifies macro names which can be used as XML tags
+var macroNameRegexp = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_.-]*$`)
+
+// normalizeMacros normalizes user-defined macros.
+// Macros generated by the operator, invalid names and non-scalar values are skipped
+func (n *Normalizer) normalizeMacros(macros *api.Settings) *api.Settings {
+	if macros == nil {
+		return nil
+	}
+	macros.Normalize()
+
+	macros.WalkSafe(func(name string, setting *api.Setting) {
+		switch {
+		case model.IsReservedMacroName(name):
+			log.V(1).M(n.ctx.GetTarget()).F().Warning("macro %s is generated by the operator, skip it", name)
+		case !macroNameRegexp.MatchString(name):
+			log.V(1).M(n.ctx.GetTarget()).F().Warning("macro name %s is not valid, skip it", name)
+		case !setting.IsScalar():
+			log.V(1).M(n.ctx.GetTarget()).F().Warning("macro %s has non-scalar value, skip it", name)
+		default:
+			return
+		}
+		macros.Delete(name)
+	})
+
+	return macros
+}
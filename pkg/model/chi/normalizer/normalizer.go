@@ -12,6 +12,9 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
+// Package normalizer normalizes CHI into the form the operator reconciles.
+// It does not depend on Kubernetes client machinery, so it can be used in CLI validators, admission webhooks and tests.
+// Cluster access is limited to the secret getter provided by the caller.
 package normalizer
 
 import (
@@ -20,11 +23,13 @@ import (
 	"fmt"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/google/uuid"
 
 	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 
 	log "github.com/altinity/clickhouse-operator/pkg/announcer"
 	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
@@ -57,6 +62,10 @@ func (n *Normalizer) CreateTemplatedCHI(
 	chi *api.ClickHouseInstallation,
 	options *Options,
 ) (*api.ClickHouseInstallation, error) {
+	if options == nil {
+		options = NewOptions()
+	}
+
 	// New CHI starts with new context
 	n.ctx = NewContext(options)
 
@@ -69,9 +78,14 @@ func (n *Normalizer) CreateTemplatedCHI(
 	// At this moment target is either newly created 'empty' CHI or a system-wide template
 
 	// Apply templates - both auto and explicitly requested - on top of context target
-	for _, template := range templatesNormalizer.ApplyCHITemplates(n.ctx.GetTarget(), chi) {
+	withAutoTemplates := n.ctx.Options().IsFeatureEnabled(FeatureGateAutoTemplates)
+	for _, template := range templatesNormalizer.ApplyCHITemplates(n.ctx.GetTarget(), chi, withAutoTemplates) {
 		n.ctx.GetTarget().EnsureStatus().PushUsedTemplate(template)
 	}
+	for _, template := range templatesNormalizer.ListApplicableTemplates(chi, withAutoTemplates) {
+		n.ctx.GetTarget().EnsureStatus().PushDependency(model.CreateTemplateDependency(template))
+	}
+	n.ctx.GetTarget().EnsureStatus().PushDependency(model.CreateMetadataDependency(chi))
 
 	// After all templates applied, place provided CHI on top of the whole stack (target)
 	n.ctx.GetTarget().MergeFrom(chi, api.MergeTypeOverrideByNonEmptyValues)
@@ -111,16 +125,24 @@ func (n *Normalizer) normalize() (*api.ClickHouseInstallation, error) {
 	n.ctx.GetTarget().Spec.Stop = n.normalizeStop(n.ctx.GetTarget().Spec.Stop)
 	n.ctx.GetTarget().Spec.Restart = n.normalizeRestart(n.ctx.GetTarget().Spec.Restart)
 	n.ctx.GetTarget().Spec.Troubleshoot = n.normalizeTroubleshoot(n.ctx.GetTarget().Spec.Troubleshoot)
+	n.ctx.GetTarget().Spec.Debug = n.normalizeDebug(n.ctx.GetTarget().Spec.Debug)
 	n.ctx.GetTarget().Spec.NamespaceDomainPattern = n.normalizeNamespaceDomainPattern(n.ctx.GetTarget().Spec.NamespaceDomainPattern)
+	n.ctx.GetTarget().Spec.Timezone = n.normalizeTimezone(n.ctx.GetTarget().Spec.Timezone)
 	n.ctx.GetTarget().Spec.Templating = n.normalizeTemplating(n.ctx.GetTarget().Spec.Templating)
 	n.ctx.GetTarget().Spec.Reconciling = n.normalizeReconciling(n.ctx.GetTarget().Spec.Reconciling)
 	n.ctx.GetTarget().Spec.Defaults = n.normalizeDefaults(n.ctx.GetTarget().Spec.Defaults)
 	n.ctx.GetTarget().Spec.Configuration = n.normalizeConfiguration(n.ctx.GetTarget().Spec.Configuration)
+	n.appendTimezone(n.ctx.GetTarget().Spec.Timezone)
+	n.appendDebug(n.ctx.GetTarget().Spec.Debug)
 	n.ctx.GetTarget().Spec.Templates = n.normalizeTemplates(n.ctx.GetTarget().Spec.Templates)
+	n.ctx.GetTarget().Spec.Chproxy = n.normalizeChproxy(n.ctx.GetTarget().Spec.Chproxy)
+	n.ctx.GetTarget().Spec.Logging = n.normalizeLogging(n.ctx.GetTarget().Spec.Logging)
 	// UseTemplates already done
 
 	n.finalizeCHI()
-	n.fillStatus()
+	if n.ctx.Options().IsFeatureEnabled(FeatureGateStatus) {
+		n.fillStatus()
+	}
 
 	return n.ctx.GetTarget(), nil
 }
@@ -129,14 +151,126 @@ func (n *Normalizer) normalize() (*api.ClickHouseInstallation, error) {
 func (n *Normalizer) finalizeCHI() {
 	n.ctx.GetTarget().FillSelfCalculatedAddressInfo()
 	n.ctx.GetTarget().FillCHIPointer()
+	allocator := newPortAllocator(n.ctx.GetTarget().EnsureStatus().GetPortAllocations(), n.ctx.Options().GetReservedHostPorts())
 	n.ctx.GetTarget().WalkHosts(func(host *api.ChiHost) error {
 		hostTemplate := n.getHostTemplate(host)
+		allocator.allocate(host, hostTemplate)
 		hostApplyHostTemplate(host, hostTemplate)
 		return nil
 	})
+	n.ctx.GetTarget().EnsureStatus().SetPortAllocations(allocator.getAllocations())
+	n.resolvePodTemplates()
+	n.resolveVolumeClaimTemplates()
+	n.resolveServiceTemplates()
 	n.fillCHIAddressInfo()
 }
 
+// resolvePodTemplates ensures pod templates referenced by hosts are known.
+// Host referencing unknown pod template falls back to the default one.
+func (n *Normalizer) resolvePodTemplates() {
+	var unknown []string
+	n.ctx.GetTarget().WalkHosts(func(host *api.ChiHost) error {
+		if !host.Templates.HasPodTemplate() {
+			return nil
+		}
+		if _, ok := host.GetPodTemplate(); ok {
+			return nil
+		}
+		name := host.Templates.GetPodTemplate()
+		if !util.InArray(name, unknown) {
+			unknown = append(unknown, name)
+			log.V(1).M(n.ctx.GetTarget()).F().Warning("podTemplate %s is referenced but not defined in spec.templates.podTemplates, use default one", name)
+		}
+		host.Templates.PodTemplate = ""
+		return nil
+	})
+}
+
+// resolveVolumeClaimTemplates ensures data, log and system log volume claim templates referenced by hosts are known.
+// Reference may point to a volume claim template or to a volume of the pod template of the host.
+// Host referencing unknown volume claim template falls back to the storage of the container.
+func (n *Normalizer) resolveVolumeClaimTemplates() {
+	var unknown []string
+	n.ctx.GetTarget().WalkHosts(func(host *api.ChiHost) error {
+		if host.Templates == nil {
+			return nil
+		}
+		for _, name := range []*string{
+			&host.Templates.DataVolumeClaimTemplate,
+			&host.Templates.LogVolumeClaimTemplate,
+			&host.Templates.SystemLogVolumeClaimTemplate,
+		} {
+			if (*name == "") || n.isKnownVolume(host, *name) {
+				continue
+			}
+			if !util.InArray(*name, unknown) {
+				unknown = append(unknown, *name)
+				log.V(1).M(n.ctx.GetTarget()).F().Warning("volumeClaimTemplate %s is referenced but not defined in spec.templates.volumeClaimTemplates, skip it", *name)
+			}
+			*name = ""
+		}
+		return nil
+	})
+}
+
+// resolveServiceTemplates ensures service templates referenced by CHI, clusters, shards and hosts are known.
+// Object referencing unknown service template falls back to the default service.
+func (n *Normalizer) resolveServiceTemplates() {
+	var unknown []string
+	resolve := func(name *string) {
+		if *name == "" {
+			return
+		}
+		if _, ok := n.ctx.GetTarget().GetServiceTemplate(*name); ok {
+			return
+		}
+		if !util.InArray(*name, unknown) {
+			unknown = append(unknown, *name)
+			log.V(1).M(n.ctx.GetTarget()).F().Warning("serviceTemplate %s is referenced but not defined in spec.templates.serviceTemplates, use default one", *name)
+		}
+		*name = ""
+	}
+
+	if defaults := n.ctx.GetTarget().Spec.Defaults; (defaults != nil) && (defaults.Templates != nil) {
+		resolve(&defaults.Templates.ServiceTemplate)
+	}
+	n.ctx.GetTarget().WalkClusters(func(cluster *api.Cluster) error {
+		if cluster.Templates != nil {
+			resolve(&cluster.Templates.ClusterServiceTemplate)
+			resolve(&cluster.Templates.ReaderServiceTemplate)
+			resolve(&cluster.Templates.WriterServiceTemplate)
+		}
+		return nil
+	})
+	n.ctx.GetTarget().WalkShards(func(shard *api.ChiShard) error {
+		if shard.Templates != nil {
+			resolve(&shard.Templates.ShardServiceTemplate)
+		}
+		return nil
+	})
+	n.ctx.GetTarget().WalkHosts(func(host *api.ChiHost) error {
+		if host.Templates != nil {
+			resolve(&host.Templates.ReplicaServiceTemplate)
+		}
+		return nil
+	})
+}
+
+// isKnownVolume checks whether volume claim template or volume of the pod template of the host has specified name
+func (n *Normalizer) isKnownVolume(host *api.ChiHost, name string) bool {
+	if _, ok := n.ctx.GetTarget().GetVolumeClaimTemplate(name); ok {
+		return true
+	}
+	if podTemplate, ok := host.GetPodTemplate(); ok {
+		for i := range podTemplate.Spec.Volumes {
+			if podTemplate.Spec.Volumes[i].Name == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // fillCHIAddressInfo
 func (n *Normalizer) fillCHIAddressInfo() {
 	n.ctx.GetTarget().WalkHosts(func(host *api.ChiHost) error {
@@ -160,11 +294,9 @@ func (n *Normalizer) getHostTemplate(host *api.ChiHost) *api.ChiHostTemplate {
 	// However, with default template there is a nuance - hostNetwork requires different default host template
 
 	// Check hostNetwork case at first
-	if podTemplate, ok := host.GetPodTemplate(); ok {
-		if podTemplate.Spec.HostNetwork {
-			// HostNetwork
-			hostTemplate = creator.NewDefaultHostTemplateForHostNetwork(model.CreateHostTemplateName(host))
-		}
+	if host.IsHostNetwork() {
+		// HostNetwork
+		hostTemplate = creator.NewDefaultHostTemplateForHostNetwork(model.CreateHostTemplateName(host))
 	}
 
 	// In case hostTemplate still is not picked - use default one
@@ -304,6 +436,20 @@ func (n *Normalizer) fillStatus() {
 	})
 	ip, _ := chop.Get().ConfigManager.GetRuntimeParam(deployment.OPERATOR_POD_IP)
 	n.ctx.GetTarget().FillStatus(endpoint, pods, fqdns, ip)
+	n.ctx.GetTarget().EnsureStatus().SetFootprint(n.footprint())
+}
+
+// footprint sums up resources requested by all hosts of the CHI
+func (n *Normalizer) footprint() *api.ChiFootprint {
+	var cpu, memory, storage resource.Quantity
+	n.ctx.GetTarget().WalkHosts(func(host *api.ChiHost) error {
+		requests := model.HostGetResourceRequests(host)
+		cpu.Add(requests[core.ResourceCPU])
+		memory.Add(requests[core.ResourceMemory])
+		storage.Add(requests[core.ResourceStorage])
+		return nil
+	})
+	return api.NewChiFootprint(cpu, memory, storage)
 }
 
 // normalizeTaskID normalizes .spec.taskID
@@ -341,7 +487,7 @@ func (n *Normalizer) normalizeRestart(restart string) string {
 	return ""
 }
 
-// normalizeTroubleshoot normalizes .spec.stop
+// normalizeTroubleshoot normalizes .spec.troubleshoot
 func (n *Normalizer) normalizeTroubleshoot(troubleshoot *api.StringBool) *api.StringBool {
 	if troubleshoot.IsValid() {
 		// It is bool, use as it is
@@ -352,12 +498,86 @@ func (n *Normalizer) normalizeTroubleshoot(troubleshoot *api.StringBool) *api.St
 	return api.NewStringBool(false)
 }
 
-func isNamespaceDomainPatternValid(namespaceDomainPattern string) bool {
-	if strings.Count(namespaceDomainPattern, "%s") > 1 {
-		return false
-	} else {
-		return true
+// normalizeDebug normalizes .spec.debug
+func (n *Normalizer) normalizeDebug(debug *api.ChiDebug) *api.ChiDebug {
+	if debug == nil {
+		return nil
+	}
+
+	debug.CoreDump = debug.CoreDump.Normalize(false)
+	debug.CoreDumpPattern = debug.CoreDumpPattern.Normalize(false)
+	debug.IntrospectionFunctions = debug.IntrospectionFunctions.Normalize(false)
+	if volume := debug.CoreDumpVolume; volume != nil {
+		volume.VolumeClaimTemplate = strings.TrimSpace(volume.VolumeClaimTemplate)
+		volume.HostPath = strings.TrimSpace(volume.HostPath)
+		if (volume.HostPath != "") && !filepath.IsAbs(volume.HostPath) {
+			// Relative host path can not be mounted
+			volume.HostPath = ""
+		}
+	}
+	if _, err := strconv.ParseUint(debug.CoreDumpSizeLimit, 10, 64); err != nil {
+		// In case it is not a number - do not use it
+		debug.CoreDumpSizeLimit = ""
+	}
+
+	return debug
+}
+
+// appendDebug propagates .spec.debug into server settings, default profile and volumes of all hosts.
+// Settings explicitly specified in .spec.configuration take precedence
+func (n *Normalizer) appendDebug(debug *api.ChiDebug) {
+	conf := n.ctx.GetTarget().Spec.Configuration
+
+	if debug.IsCoreDump() {
+		sizeLimit := model.CoreDumpSizeLimitDefault
+		if debug.HasCoreDumpSizeLimit() {
+			sizeLimit = debug.GetCoreDumpSizeLimit()
+		}
+		conf.Settings = conf.Settings.Ensure().SetIfNotExists(model.CoreDumpSizeLimitSettingName, api.NewSettingScalar(sizeLimit))
+		n.appendCoreDumpsVolume(debug.GetCoreDumpVolume())
+	}
+
+	if debug.IsIntrospectionFunctions() {
+		conf.Profiles = conf.Profiles.Ensure().SetIfNotExists(model.IntrospectionFunctionsProfileSettingName, api.NewSettingScalar("1"))
+	}
+}
+
+// appendCoreDumpsVolume mounts core dumps volume into all containers of all hosts.
+// Volume claimed by the volume claim template is appended to the StatefulSet by the mount referencing the template
+func (n *Normalizer) appendCoreDumpsVolume(volume *api.ChiCoreDumpVolume) {
+	if name := volume.GetVolumeClaimTemplate(); name != "" {
+		n.appendAdditionalVolumeMount(core.VolumeMount{
+			Name:      name,
+			MountPath: model.DirPathClickHouseCoreDumps,
+		})
+		return
+	}
+
+	source := core.VolumeSource{
+		EmptyDir: &core.EmptyDirVolumeSource{},
 	}
+	if path := volume.GetHostPath(); path != "" {
+		hostPathType := core.HostPathDirectoryOrCreate
+		source = core.VolumeSource{
+			HostPath: &core.HostPathVolumeSource{
+				Path: path,
+				Type: &hostPathType,
+			},
+		}
+	}
+	n.appendAdditionalVolume(core.Volume{
+		Name:         model.CoreDumpsVolumeName,
+		VolumeSource: source,
+	})
+	n.appendAdditionalVolumeMount(core.VolumeMount{
+		Name:      model.CoreDumpsVolumeName,
+		MountPath: model.DirPathClickHouseCoreDumps,
+	})
+}
+
+// isNamespaceDomainPatternValid checks namespaceDomainPattern has exactly one namespace placeholder
+func isNamespaceDomainPatternValid(namespaceDomainPattern string) bool {
+	return strings.Count(namespaceDomainPattern, "%s") == 1
 }
 
 // normalizeNamespaceDomainPattern normalizes .spec.namespaceDomainPattern
@@ -369,6 +589,29 @@ func (n *Normalizer) normalizeNamespaceDomainPattern(namespaceDomainPattern stri
 	return ""
 }
 
+// normalizeTimezone normalizes .spec.timezone
+func (n *Normalizer) normalizeTimezone(timezone string) string {
+	return strings.TrimSpace(timezone)
+}
+
+// appendTimezone propagates .spec.timezone into server settings and ENV vars of all hosts.
+// Timezone explicitly specified in .spec.configuration.settings takes precedence
+func (n *Normalizer) appendTimezone(timezone string) {
+	if timezone == "" {
+		return
+	}
+
+	conf := n.ctx.GetTarget().Spec.Configuration
+	conf.Settings = conf.Settings.Ensure().SetIfNotExists(model.TimezoneSettingName, api.NewSettingScalar(timezone))
+
+	n.appendAdditionalEnvVar(
+		core.EnvVar{
+			Name:  model.TimezoneEnvName,
+			Value: timezone,
+		},
+	)
+}
+
 // normalizeDefaults normalizes .spec.defaults
 func (n *Normalizer) normalizeDefaults(defaults *api.ChiDefaults) *api.ChiDefaults {
 	if defaults == nil {
@@ -376,6 +619,11 @@ func (n *Normalizer) normalizeDefaults(defaults *api.ChiDefaults) *api.ChiDefaul
 	}
 	// Set defaults for CHI object properties
 	defaults.ReplicasUseFQDN = defaults.ReplicasUseFQDN.Normalize(false)
+	defaults.AutoTuning = defaults.AutoTuning.Normalize(false)
+	defaults.ManagedProfiles = n.normalizeManagedProfiles(defaults.ManagedProfiles)
+	if (defaults.QuotaUsageThreshold < 0) || (defaults.QuotaUsageThreshold > 100) {
+		defaults.QuotaUsageThreshold = 0
+	}
 	// Ensure field
 	if defaults.DistributedDDL == nil {
 		//defaults.DistributedDDL = api.NewChiDistributedDDL()
@@ -384,6 +632,7 @@ func (n *Normalizer) normalizeDefaults(defaults *api.ChiDefaults) *api.ChiDefaul
 	if defaults.StorageManagement == nil {
 		defaults.StorageManagement = api.NewStorageManagement()
 	}
+	templatesNormalizer.NormalizeStorageManagementDefaults(defaults.StorageManagement)
 	// Ensure field
 	if defaults.Templates == nil {
 		//defaults.Templates = api.NewChiTemplateNames()
@@ -392,6 +641,22 @@ func (n *Normalizer) normalizeDefaults(defaults *api.ChiDefaults) *api.ChiDefaul
 	return defaults
 }
 
+// normalizeManagedProfiles normalizes names of profiles shipped with the operator, unknown and duplicate names are dropped
+func (n *Normalizer) normalizeManagedProfiles(names []string) []string {
+	var profiles []string
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		switch {
+		case util.InArray(name, profiles):
+		case model.IsManagedProfile(name):
+			profiles = append(profiles, name)
+		default:
+			log.V(1).M(n.ctx.GetTarget()).F().Warning("unknown managed profile %q is skipped", name)
+		}
+	}
+	return profiles
+}
+
 // normalizeConfiguration normalizes .spec.configuration
 func (n *Normalizer) normalizeConfiguration(conf *api.Configuration) *api.Configuration {
 	if conf == nil {
@@ -399,7 +664,9 @@ func (n *Normalizer) normalizeConfiguration(conf *api.Configuration) *api.Config
 	}
 	conf.Zookeeper = n.normalizeConfigurationZookeeper(conf.Zookeeper)
 	n.normalizeConfigurationAllSettingsBasedSections(conf)
+	conf.Macros = n.normalizeMacros(conf.Macros)
 	conf.Clusters = n.normalizeClusters(conf.Clusters)
+	conf.LogicalClusters = n.normalizeLogicalClusters(conf.LogicalClusters)
 	return conf
 }
 
@@ -462,9 +729,32 @@ func (n *Normalizer) normalizeReconciling(reconciling *api.ChiReconciling) *api.
 		reconciling.SetPolicy(api.ReconcilingPolicyUnspecified)
 	}
 	reconciling.Cleanup = n.normalizeReconcilingCleanup(reconciling.Cleanup)
+	reconciling.SmokeTest = n.normalizeReconcilingSmokeTest(reconciling.SmokeTest)
 	return reconciling
 }
 
+// normalizeReconcilingSmokeTest skips queries without SQL and names unnamed ones
+func (n *Normalizer) normalizeReconcilingSmokeTest(smokeTest *api.ChiSmokeTest) *api.ChiSmokeTest {
+	if smokeTest == nil {
+		return nil
+	}
+
+	var queries []api.ChiSmokeTestQuery
+	for _, query := range smokeTest.Queries {
+		query.SQL = strings.TrimSpace(query.SQL)
+		if query.SQL == "" {
+			continue
+		}
+		if query.Name == "" {
+			query.Name = fmt.Sprintf("query-%d", len(queries))
+		}
+		query.Expect = strings.TrimSpace(query.Expect)
+		queries = append(queries, query)
+	}
+	smokeTest.Queries = queries
+	return smokeTest
+}
+
 func (n *Normalizer) normalizeReconcilingCleanup(cleanup *api.ChiCleanup) *api.ChiCleanup {
 	if cleanup == nil {
 		cleanup = api.NewChiCleanup()
@@ -477,6 +767,7 @@ func (n *Normalizer) normalizeReconcilingCleanup(cleanup *api.ChiCleanup) *api.C
 	n.normalizeCleanup(&cleanup.UnknownObjects.PVC, api.ObjectsCleanupDelete)
 	n.normalizeCleanup(&cleanup.UnknownObjects.ConfigMap, api.ObjectsCleanupDelete)
 	n.normalizeCleanup(&cleanup.UnknownObjects.Service, api.ObjectsCleanupDelete)
+	n.normalizeCleanup(&cleanup.UnknownObjects.Secret, api.ObjectsCleanupDelete)
 
 	if cleanup.ReconcileFailedObjects == nil {
 		cleanup.ReconcileFailedObjects = cleanup.DefaultReconcileFailedObjects()
@@ -485,6 +776,7 @@ func (n *Normalizer) normalizeReconcilingCleanup(cleanup *api.ChiCleanup) *api.C
 	n.normalizeCleanup(&cleanup.ReconcileFailedObjects.PVC, api.ObjectsCleanupRetain)
 	n.normalizeCleanup(&cleanup.ReconcileFailedObjects.ConfigMap, api.ObjectsCleanupRetain)
 	n.normalizeCleanup(&cleanup.ReconcileFailedObjects.Service, api.ObjectsCleanupRetain)
+	n.normalizeCleanup(&cleanup.ReconcileFailedObjects.Secret, api.ObjectsCleanupRetain)
 	return cleanup
 }
 
@@ -588,7 +880,7 @@ func (n *Normalizer) ensureClusters(clusters []*api.Cluster) []*api.Cluster {
 	// In case no clusters available, we may want to create a default one
 	if n.ctx.Options().WithDefaultCluster {
 		return []*api.Cluster{
-			creator.NewDefaultCluster(),
+			n.newDefaultCluster(),
 		}
 	}
 
@@ -596,6 +888,21 @@ func (n *Normalizer) ensureClusters(clusters []*api.Cluster) []*api.Cluster {
 	return nil
 }
 
+// newDefaultCluster creates default cluster as specified by default scenario
+func (n *Normalizer) newDefaultCluster() *api.Cluster {
+	scenario := n.ctx.Options().GetDefaultScenario()
+	cluster := creator.NewDefaultCluster()
+	if scenario.ClusterName != "" {
+		cluster.Name = scenario.ClusterName
+	}
+	if (scenario.ShardsCount > 0) || (scenario.ReplicasCount > 0) {
+		cluster.Layout = api.NewChiClusterLayout()
+		cluster.Layout.ShardsCount = scenario.ShardsCount
+		cluster.Layout.ReplicasCount = scenario.ReplicasCount
+	}
+	return cluster
+}
+
 // normalizeConfigurationZookeeper normalizes .spec.configuration.zookeeper
 func (n *Normalizer) normalizeConfigurationZookeeper(zk *api.ChiZookeeperConfig) *api.ChiZookeeperConfig {
 	if zk == nil {
@@ -854,6 +1161,7 @@ func (n *Normalizer) fetchSecretFieldValue(secretAddress api.ObjectAddress) (str
 		log.V(1).M(secretAddress.Namespace, secretAddress.Name).F().Info("unable to read secret %s %v", secretAddress, err)
 		return "", ErrSecretValueNotFound
 	}
+	n.ctx.GetTarget().EnsureStatus().PushDependency(model.CreateSecretDependency(secret))
 
 	// Find the field within the secret
 	for key, value := range secret.Data {
@@ -971,6 +1279,10 @@ func (n *Normalizer) normalizeConfigurationUserEnsureMandatoryFields(user *api.S
 		profile = chopProfile
 		quota = ""
 		ips = []string{ip}
+		if chop.Config().IsHealthCheckModeExec() {
+			// Health-checks are performed by clickhouse-client executed inside the pod
+			ips = append(ips, "127.0.0.1", "::1")
+		}
 		hostRegexp = ""
 	}
 
@@ -1140,24 +1452,50 @@ func (n *Normalizer) normalizeCluster(cluster *api.Cluster) *api.Cluster {
 
 	cluster.Runtime.CHI = n.ctx.GetTarget()
 
+	// Inherit from .spec.templates.clusterTemplates
+	cluster.InheritClusterTemplateFrom(n.ctx.GetTarget())
+	cluster.HostPorts.Normalize()
 	// Inherit from .spec.configuration.zookeeper
 	cluster.InheritZookeeperFrom(n.ctx.GetTarget())
 	// Inherit from .spec.configuration.files
 	cluster.InheritFilesFrom(n.ctx.GetTarget())
 	// Inherit from .spec.defaults
 	cluster.InheritTemplatesFrom(n.ctx.GetTarget())
+	cluster.InheritResourcesFrom(n.ctx.GetTarget())
+	cluster.InheritPriorityClassNameFrom(n.ctx.GetTarget())
+	cluster.InheritHostNetworkFrom(n.ctx.GetTarget())
+	cluster.InheritMetadataFrom(n.ctx.GetTarget())
+	// Inherit from .spec.configuration.macros
+	cluster.InheritMacrosFrom(n.ctx.GetTarget())
+	// Inherit from .spec.defaults.zookeeperPathTemplate
+	cluster.InheritZookeeperPathTemplateFrom(n.ctx.GetTarget())
 
 	cluster.Zookeeper = n.normalizeConfigurationZookeeper(cluster.Zookeeper)
 	cluster.Settings = n.normalizeConfigurationSettings(cluster.Settings)
 	cluster.Files = n.normalizeConfigurationFiles(cluster.Files)
+	cluster.Macros = n.normalizeMacros(cluster.Macros)
 
 	cluster.SchemaPolicy = n.normalizeClusterSchemaPolicy(cluster.SchemaPolicy)
+	cluster.ZookeeperPathTemplate = n.normalizeClusterZookeeperPathTemplate(cluster)
+	cluster.ReplicatedDatabases = n.normalizeClusterReplicatedDatabases(cluster)
+	cluster.Zones = n.normalizeZones(cluster.Zones)
+	cluster.RemoteReplicas = n.normalizeClusterRemoteReplicas(cluster)
+	cluster.WriteReliability = n.normalizeClusterWriteReliability(cluster)
+	cluster.Rebalancing = n.normalizeClusterRebalancing(cluster)
+	cluster.ScheduledRestart = n.normalizeClusterScheduledRestart(cluster)
+	cluster.Spot = n.normalizeClusterSpot(cluster)
+	cluster.TLS = n.normalizeClusterTLS(cluster)
 
 	if cluster.Layout == nil {
 		cluster.Layout = api.NewChiClusterLayout()
 	}
 	cluster.FillShardReplicaSpecified()
 	cluster.Layout = n.normalizeClusterLayoutShardsCountAndReplicasCount(cluster.Layout)
+
+	if n.isLazyLayout(cluster) {
+		return n.normalizeLazyCluster(cluster)
+	}
+
 	n.ensureClusterLayoutShards(cluster.Layout)
 	n.ensureClusterLayoutReplicas(cluster.Layout)
 
@@ -1180,6 +1518,13 @@ func (n *Normalizer) normalizeCluster(cluster *api.Cluster) *api.Cluster {
 		return nil
 	})
 
+	// Writer host depends on tiers of all hosts of the shard
+	cluster.WalkShards(func(index int, shard *api.ChiShard) error {
+		shard.MarkWriterHost()
+		shard.MarkSpotHosts(cluster.Spot)
+		return nil
+	})
+
 	return cluster
 }
 
@@ -1335,34 +1680,80 @@ func (n *Normalizer) ensureClusterLayoutReplicas(layout *api.ChiClusterLayout) {
 	}
 }
 
+// normalizeClusterZookeeperPathTemplate normalizes template of the ZooKeeper path macro of the cluster.
+// Template is not defaulted, so the macro is rendered only for clusters which have it specified explicitly
+func (n *Normalizer) normalizeClusterZookeeperPathTemplate(cluster *api.Cluster) string {
+	template := strings.TrimSpace(cluster.ZookeeperPathTemplate)
+	if (template != "") && !strings.HasPrefix(template, "/") {
+		log.V(1).M(n.ctx.GetTarget()).F().Warning("cluster %s has ZooKeeper path template %s which is not absolute, ignore it", cluster.Name, template)
+		return ""
+	}
+	return template
+}
+
+// normalizeClusterReplicatedDatabases drops unnamed and duplicated replicated databases and sets default ZooKeeper paths
+func (n *Normalizer) normalizeClusterReplicatedDatabases(cluster *api.Cluster) []api.ChiReplicatedDatabase {
+	var databases []api.ChiReplicatedDatabase
+	names := map[string]bool{}
+	for _, database := range cluster.ReplicatedDatabases {
+		database.Name = strings.TrimSpace(database.Name)
+		database.ZookeeperPath = strings.TrimSpace(database.ZookeeperPath)
+		if (database.Name == "") || names[database.Name] {
+			log.V(1).M(n.ctx.GetTarget()).F().Warning("cluster %s has replicated database with empty or duplicated name %q, skip it", cluster.Name, database.Name)
+			continue
+		}
+		if database.ZookeeperPath == "" {
+			database.ZookeeperPath = model.ReplicatedDatabaseZookeeperPathPrefixDefault + database.Name
+		}
+		names[database.Name] = true
+		databases = append(databases, database)
+	}
+	return databases
+}
+
 // normalizeShard normalizes a shard - walks over all fields
 func (n *Normalizer) normalizeShard(shard *api.ChiShard, cluster *api.Cluster, shardIndex int) {
 	n.normalizeShardName(shard, shardIndex)
 	n.normalizeShardWeight(shard)
+	shard.HostPorts.Normalize()
 	// For each shard of this normalized cluster inherit from cluster
 	shard.InheritSettingsFrom(cluster)
 	shard.Settings = n.normalizeConfigurationSettings(shard.Settings)
 	shard.InheritFilesFrom(cluster)
 	shard.Files = n.normalizeConfigurationFiles(shard.Files)
+	shard.Macros = n.normalizeMacros(shard.Macros)
+	// Templates overridden for the shard index take precedence over cluster's ones
+	shard.Templates = shard.Templates.MergeFrom(cluster.Layout.GetShardTemplates(shardIndex), api.MergeTypeFillEmptyValues)
 	shard.InheritTemplatesFrom(cluster)
+	shard.InheritResourcesFrom(cluster)
+	shard.InheritZonesFrom(cluster)
+	shard.Zones = n.normalizeZones(shard.Zones)
 	// Normalize Replicas
 	n.normalizeShardReplicasCount(shard, cluster.Layout.ReplicasCount)
 	n.normalizeShardHosts(shard, cluster, shardIndex)
 	// Internal replication uses ReplicasCount thus it has to be normalized after shard ReplicaCount normalized
-	n.normalizeShardInternalReplication(shard)
+	n.normalizeShardInternalReplication(shard, cluster)
 }
 
 // normalizeReplica normalizes a replica - walks over all fields
 func (n *Normalizer) normalizeReplica(replica *api.ChiReplica, cluster *api.Cluster, replicaIndex int) {
 	n.normalizeReplicaName(replica, replicaIndex)
+	replica.HostPorts.Normalize()
 	// For each replica of this normalized cluster inherit from cluster
 	replica.InheritSettingsFrom(cluster)
 	replica.Settings = n.normalizeConfigurationSettings(replica.Settings)
 	replica.InheritFilesFrom(cluster)
 	replica.Files = n.normalizeConfigurationFiles(replica.Files)
+	replica.Macros = n.normalizeMacros(replica.Macros)
 	replica.InheritTemplatesFrom(cluster)
+	replica.InheritResourcesFrom(cluster)
+	replica.Tier = n.normalizeTier(replica.Tier)
 	// Normalize Shards
 	n.normalizeReplicaShardsCount(replica, cluster.Layout.ShardsCount)
+	if cluster.IsLazy() {
+		// Hosts of lazy cluster are materialized by shards only
+		return
+	}
 	n.normalizeReplicaHosts(replica, cluster, replicaIndex)
 }
 
@@ -1421,6 +1812,9 @@ func (n *Normalizer) normalizeShardName(shard *api.ChiShard, index int) {
 	}
 
 	shard.Name = model.CreateShardName(shard, index)
+	if n.ctx.Options().GetNamingScheme() == NamingSchemePrefixed {
+		shard.Name = "shard" + shard.Name
+	}
 }
 
 // normalizeReplicaName normalizes replica name
@@ -1431,6 +1825,9 @@ func (n *Normalizer) normalizeReplicaName(replica *api.ChiReplica, index int) {
 	}
 
 	replica.Name = model.CreateReplicaName(replica, index)
+	if n.ctx.Options().GetNamingScheme() == NamingSchemePrefixed {
+		replica.Name = "replica" + replica.Name
+	}
 }
 
 // normalizeShardName normalizes shard weight
@@ -1474,6 +1871,9 @@ func (n *Normalizer) normalizeHost(
 ) {
 
 	n.normalizeHostName(host, shard, shardIndex, replica, replicaIndex)
+	// Ports are inherited from replica, shard and cluster regardless of the layout, since replicas sharing nodes
+	// need different ports, as well as shards do
+	host.InheritPortsFrom(shard, replica, cluster)
 	entitiesNormalizer.NormalizeHostPorts(host)
 	// Inherit from either Shard or Replica
 	var s *api.ChiShard
@@ -1488,6 +1888,60 @@ func (n *Normalizer) normalizeHost(
 	host.InheritFilesFrom(s, r)
 	host.Files = n.normalizeConfigurationFiles(host.Files)
 	host.InheritTemplatesFrom(s, r, nil)
+	host.InheritResourcesFrom(s, r)
+	// Macros are inherited from both shard and replica, since hosts are addressed by both
+	host.InheritMacrosFrom(shard, replica, cluster)
+	host.Macros = n.normalizeMacros(host.Macros)
+	n.normalizeHostZone(host, shard, shardIndex, replicaIndex)
+	n.normalizeHostTier(host, replica)
+	host.InheritPriorityFrom(replica)
+	host.InheritAnnotationsFrom(replica)
+}
+
+// normalizeHostTier normalizes tier of the host.
+// Host without explicitly specified tier inherits tier of the replica.
+func (n *Normalizer) normalizeHostTier(host *api.ChiHost, replica *api.ChiReplica) {
+	host.InheritTierFrom(replica)
+	host.Tier = n.normalizeTier(host.Tier)
+}
+
+// normalizeTier normalizes tier, unknown tiers fall back to the write tier, which is represented by empty value
+func (n *Normalizer) normalizeTier(tier string) string {
+	switch strings.ToLower(tier) {
+	case api.HostTierRead:
+		return api.HostTierRead
+	default:
+		return ""
+	}
+}
+
+// normalizeHostZone normalizes zone of the host.
+// Host without explicitly specified zone is assigned one of the shard zones.
+func (n *Normalizer) normalizeHostZone(host *api.ChiHost, shard *api.ChiShard, shardIndex, replicaIndex int) {
+	if host.Zone == nil {
+		host.Zone = shard.GetReplicaZone(shardIndex, replicaIndex)
+	}
+	host.Zone = n.normalizeZones(host.Zone)
+}
+
+// normalizeZones normalizes zones, key defaults to the well-known zone label.
+// Zones without values are kept in case they specify tolerations only
+func (n *Normalizer) normalizeZones(zones *api.ChiPodTemplateZone) *api.ChiPodTemplateZone {
+	if zones == nil {
+		return nil
+	}
+	zones.MatchExpressions = templatesNormalizer.NormalizeZoneMatchExpressions(zones.MatchExpressions)
+	if len(zones.Values) == 0 {
+		if (len(zones.Tolerations) == 0) && (len(zones.MatchExpressions) == 0) {
+			return nil
+		}
+		zones.Key = ""
+		return zones
+	}
+	if zones.Key == "" {
+		zones.Key = core.LabelTopologyZone
+	}
+	return zones
 }
 
 // normalizeHostName normalizes host's name
@@ -1508,10 +1962,10 @@ func (n *Normalizer) normalizeHostName(
 
 // normalizeShardInternalReplication ensures reasonable values in
 // .spec.configuration.clusters.layout.shards.internalReplication
-func (n *Normalizer) normalizeShardInternalReplication(shard *api.ChiShard) {
+func (n *Normalizer) normalizeShardInternalReplication(shard *api.ChiShard, cluster *api.Cluster) {
 	// Shards with replicas are expected to have internal replication on by default
 	defaultInternalReplication := false
-	if shard.ReplicasCount > 1 {
+	if (shard.ReplicasCount > 1) || cluster.HasRemoteReplicas() {
 		defaultInternalReplication = true
 	}
 	shard.InternalReplication = shard.InternalReplication.Normalize(defaultInternalReplication)
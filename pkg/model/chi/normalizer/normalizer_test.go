@@ -0,0 +1,289 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package normalizer_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/apis/deployment"
+	"github.com/altinity/clickhouse-operator/pkg/chop"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/builder"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/normalizer"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/normalizer/golden"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/render"
+)
+
+func TestMain(m *testing.M) {
+	golden.Init(filepath.Join("..", "..", "..", "..", "config", "config.yaml"))
+	os.Exit(m.Run())
+}
+
+func TestNormalizeGolden(t *testing.T) {
+	golden.Test(t, "testdata", normalizer.NewOptions())
+}
+
+func TestNormalizeGoldenPrefixed(t *testing.T) {
+	options := normalizer.NewOptions()
+	options.NamingScheme = normalizer.NamingSchemePrefixed
+	options.DefaultScenario = &normalizer.DefaultScenario{
+		ClusterName:   "main",
+		ShardsCount:   2,
+		ReplicasCount: 1,
+	}
+	options.FeatureGates = map[string]bool{
+		normalizer.FeatureGateAutoTemplates: false,
+	}
+	golden.Test(t, filepath.Join("testdata", "prefixed"), options)
+}
+
+func TestNormalizeUseTemplates(t *testing.T) {
+	standard := builder.NewCHI("test", "standard",
+		builder.WithPodTemplates(builder.NewPodTemplate("standard-pod", "clickhouse/clickhouse-server:23.8")),
+		builder.WithVolumeClaimTemplates(builder.NewVolumeClaimTemplate("standard-data", resource.MustParse("100Gi"))),
+		builder.WithDefaultPodTemplate("standard-pod"),
+	)
+	standard.Spec.Defaults.Templates.DataVolumeClaimTemplate = "standard-data"
+	standard.Spec.Defaults.ReplicasUseFQDN = api.NewStringBool(true)
+	large := builder.NewCHI("test", "large",
+		builder.WithPodTemplates(builder.NewPodTemplate("large-pod", "clickhouse/clickhouse-server:23.8")),
+		builder.WithDefaultPodTemplate("large-pod"),
+	)
+	chop.Config().AddCHITemplate(standard)
+	chop.Config().AddCHITemplate(large)
+	defer chop.Config().DeleteCHITemplate(standard)
+	defer chop.Config().DeleteCHITemplate(large)
+
+	chi := builder.NewCHI("test", "shaped",
+		builder.WithUseTemplates("standard", "large"),
+		builder.WithCluster(builder.NewCluster("main")),
+	)
+	chi.Spec.Defaults = &api.ChiDefaults{
+		ReplicasUseFQDN: api.NewStringBool(false),
+	}
+	normalized, err := normalizer.NewNormalizer(render.NoSecrets).CreateTemplatedCHI(chi, normalizer.NewOptions())
+	if err != nil {
+		t.Fatalf("unable to normalize err: %v", err)
+	}
+
+	host := normalized.FindCluster("main").FirstHost()
+	// Templates are applied in the order they are listed, the latter ones take precedence
+	if name := host.Templates.GetPodTemplate(); name != "large-pod" {
+		t.Errorf("got pod template %s want large-pod", name)
+	}
+	if name := host.Templates.GetDataVolumeClaimTemplate(); name != "standard-data" {
+		t.Errorf("got data volume claim template %s want standard-data", name)
+	}
+	if _, ok := normalized.GetVolumeClaimTemplate("standard-data"); !ok {
+		t.Errorf("volume claim template of the template is not merged")
+	}
+	// CHI itself takes precedence over templates
+	if normalized.Spec.Defaults.ReplicasUseFQDN.Value() {
+		t.Errorf("defaults of the CHI are overridden by the template")
+	}
+}
+
+func TestNormalizeSmokeTest(t *testing.T) {
+	verified := builder.NewCHI("test", "verified")
+	verified.Spec.Reconciling = &api.ChiReconciling{
+		SmokeTest: &api.ChiSmokeTest{
+			Queries: []api.ChiSmokeTestQuery{
+				{Name: "tables", SQL: " SELECT count() FROM system.tables WHERE database != 'system' "},
+				{Name: "empty"},
+				{SQL: "SELECT 1", Expect: " 1 "},
+			},
+		},
+	}
+	chop.Config().AddCHITemplate(verified)
+	defer chop.Config().DeleteCHITemplate(verified)
+
+	chi := builder.NewCHI("test", "smoke",
+		builder.WithUseTemplates("verified"),
+		builder.WithCluster(builder.NewCluster("main")),
+	)
+	normalized, err := normalizer.NewNormalizer(render.NoSecrets).CreateTemplatedCHI(chi, normalizer.NewOptions())
+	if err != nil {
+		t.Fatalf("unable to normalize err: %v", err)
+	}
+
+	// Queries without SQL are skipped, unnamed ones are named
+	queries := normalized.Spec.Reconciling.GetSmokeTest().GetQueries()
+	if len(queries) != 2 {
+		t.Fatalf("unexpected smoke test queries: %v", queries)
+	}
+	if queries[0].SQL != "SELECT count() FROM system.tables WHERE database != 'system'" || queries[0].HasExpect() {
+		t.Errorf("unexpected first query: %v", queries[0])
+	}
+	if queries[1].Name != "query-1" || queries[1].Expect != "1" {
+		t.Errorf("unexpected second query: %v", queries[1])
+	}
+}
+
+func TestNormalizeReplicatedDatabases(t *testing.T) {
+	cluster := builder.NewCluster("main")
+	cluster.ReplicatedDatabases = []api.ChiReplicatedDatabase{
+		{Name: " analytics "},
+		{Name: "events", ZookeeperPath: "/clickhouse/shared/databases/events"},
+		{Name: "analytics"},
+		{ZookeeperPath: "/clickhouse/unnamed"},
+	}
+	chi := builder.NewCHI("test", "replicated", builder.WithCluster(cluster))
+	normalized, err := normalizer.NewNormalizer(render.NoSecrets).CreateTemplatedCHI(chi, normalizer.NewOptions())
+	if err != nil {
+		t.Fatalf("unable to normalize err: %v", err)
+	}
+
+	// Unnamed and duplicated databases are skipped, default path is set
+	want := []api.ChiReplicatedDatabase{
+		{Name: "analytics", ZookeeperPath: "/clickhouse/{installation}/{cluster}/databases/analytics"},
+		{Name: "events", ZookeeperPath: "/clickhouse/shared/databases/events"},
+	}
+	if got := normalized.FindCluster("main").ReplicatedDatabases; !reflect.DeepEqual(got, want) {
+		t.Errorf("got replicated databases %v want %v", got, want)
+	}
+}
+
+func TestNormalizeCoreDumpsVolume(t *testing.T) {
+	hostPathType := core.HostPathDirectoryOrCreate
+	tests := []struct {
+		name    string
+		volume  *api.ChiCoreDumpVolume
+		volumes []core.Volume
+		mount   string
+	}{
+		{
+			name:    "emptyDir by default",
+			volumes: []core.Volume{{Name: "core-dumps", VolumeSource: core.VolumeSource{EmptyDir: &core.EmptyDirVolumeSource{}}}},
+			mount:   "core-dumps",
+		},
+		{
+			name:   "host path",
+			volume: &api.ChiCoreDumpVolume{HostPath: "/var/crash"},
+			volumes: []core.Volume{{Name: "core-dumps", VolumeSource: core.VolumeSource{
+				HostPath: &core.HostPathVolumeSource{Path: "/var/crash", Type: &hostPathType},
+			}}},
+			mount: "core-dumps",
+		},
+		{
+			name:    "relative host path is ignored",
+			volume:  &api.ChiCoreDumpVolume{HostPath: "crash"},
+			volumes: []core.Volume{{Name: "core-dumps", VolumeSource: core.VolumeSource{EmptyDir: &core.EmptyDirVolumeSource{}}}},
+			mount:   "core-dumps",
+		},
+		{
+			name:   "volume claim template",
+			volume: &api.ChiCoreDumpVolume{VolumeClaimTemplate: "dumps"},
+			mount:  "dumps",
+		},
+	}
+	for _, test := range tests {
+		chi := builder.NewCHI("test", "debug", builder.WithCluster(builder.NewCluster("main")))
+		chi.Spec.Debug = &api.ChiDebug{
+			CoreDump:       api.NewStringBool(true),
+			CoreDumpVolume: test.volume,
+		}
+		normalized, err := normalizer.NewNormalizer(render.NoSecrets).CreateTemplatedCHI(chi, normalizer.NewOptions())
+		if err != nil {
+			t.Fatalf("%s: unable to normalize err: %v", test.name, err)
+		}
+
+		attributes := normalized.EnsureRuntime().EnsureAttributes()
+		if !reflect.DeepEqual(attributes.AdditionalVolumes, test.volumes) {
+			t.Errorf("%s: got volumes %v want %v", test.name, attributes.AdditionalVolumes, test.volumes)
+		}
+		want := []core.VolumeMount{{Name: test.mount, MountPath: "/var/lib/clickhouse-core-dumps"}}
+		if !reflect.DeepEqual(attributes.AdditionalVolumeMounts, want) {
+			t.Errorf("%s: got mounts %v want %v", test.name, attributes.AdditionalVolumeMounts, want)
+		}
+	}
+}
+
+func TestNormalizeAllocatedPorts(t *testing.T) {
+	newCHI := func() *api.ClickHouseInstallation {
+		return builder.NewCHI("test", "allocated",
+			builder.WithHostTemplates(api.ChiHostTemplate{
+				Name:             "allocated",
+				PortDistribution: []api.ChiPortDistribution{{Type: deployment.PortDistributionAllocated}},
+				Spec:             api.ChiHost{TCPPort: 10000},
+			}),
+			builder.WithCluster(builder.NewCluster("main", builder.WithShards(3), builder.WithHostTemplate("allocated"))),
+		)
+	}
+	tcpPorts := func(chi *api.ClickHouseInstallation) (ports []int32) {
+		chi.WalkHosts(func(host *api.ChiHost) error {
+			ports = append(ports, host.TCPPort)
+			return nil
+		})
+		return ports
+	}
+
+	// Ports of other CHIs are skipped
+	opts := normalizer.NewOptions()
+	opts.ReservedHostPorts = []int32{10001}
+	normalized, err := normalizer.NewNormalizer(render.NoSecrets).CreateTemplatedCHI(newCHI(), opts)
+	if err != nil {
+		t.Fatalf("unable to normalize err: %v", err)
+	}
+	if got, want := tcpPorts(normalized), []int32{10000, 10002, 10003}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got tcp ports %v want %v", got, want)
+	}
+	allocations := normalized.EnsureStatus().GetPortAllocations()
+	if len(allocations) != 3 {
+		t.Fatalf("got %d allocations want 3", len(allocations))
+	}
+
+	// Hosts keep recorded ports, new hosts take the lowest free ones
+	chi := newCHI()
+	chi.EnsureStatus().SetPortAllocations(allocations[2:])
+	normalized, err = normalizer.NewNormalizer(render.NoSecrets).CreateTemplatedCHI(chi, normalizer.NewOptions())
+	if err != nil {
+		t.Fatalf("unable to normalize err: %v", err)
+	}
+	if got, want := tcpPorts(normalized), []int32{10000, 10001, 10003}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got tcp ports %v want %v", got, want)
+	}
+}
+
+func TestNormalizeLevelPorts(t *testing.T) {
+	cluster := builder.NewCluster("main", builder.WithShards(2), builder.WithReplicas(2))
+	cluster.TCPPort = 9100
+	cluster.Layout.Replicas = []api.ChiReplica{
+		{},
+		{HostPorts: api.HostPorts{TCPPort: 9200, HTTPPort: 8200}},
+	}
+	normalized, err := normalizer.NewNormalizer(render.NoSecrets).CreateTemplatedCHI(builder.NewCHI("test", "ports", builder.WithCluster(cluster)), normalizer.NewOptions())
+	if err != nil {
+		t.Fatalf("unable to normalize err: %v", err)
+	}
+
+	// Ports of the replica take precedence over the ones of the cluster, unspecified ports are defaulted
+	want := map[int][2]int32{
+		0: {9100, 8123},
+		1: {9200, 8200},
+	}
+	normalized.WalkHosts(func(host *api.ChiHost) error {
+		ports := want[host.Runtime.Address.ReplicaIndex]
+		if (host.TCPPort != ports[0]) || (host.HTTPPort != ports[1]) {
+			t.Errorf("host %s: got ports %d/%d want %d/%d", host.GetName(), host.TCPPort, host.HTTPPort, ports[0], ports[1])
+		}
+		return nil
+	})
+}
@@ -14,6 +14,31 @@
 
 package normalizer
 
+// Naming schemes of shards and replicas with auto-generated names
+const (
+	// NamingSchemeIndex names shards and replicas by their index: 0, 1, ...
+	NamingSchemeIndex = "index"
+	// NamingSchemePrefixed names shards and replicas by their index with entity prefix: shard0, replica0, ...
+	NamingSchemePrefixed = "prefixed"
+)
+
+// Feature gates of optional normalization steps
+const (
+	// FeatureGateAutoTemplates applies auto templates specified in operator's configuration
+	FeatureGateAutoTemplates = "AutoTemplates"
+	// FeatureGateStatus fills status of the normalized CHI
+	FeatureGateStatus = "Status"
+	// FeatureGateLazyLayout expands shards of Standard layout clusters on demand instead of materializing them
+	FeatureGateLazyLayout = "LazyLayout"
+)
+
+// defaultFeatureGates specifies feature gates values used in case gate is not specified explicitly
+var defaultFeatureGates = map[string]bool{
+	FeatureGateAutoTemplates: true,
+	FeatureGateStatus:        true,
+	FeatureGateLazyLayout:    false,
+}
+
 // Options specifies normalization options
 type Options struct {
 	// WithDefaultCluster specifies whether to insert default cluster in case no cluster specified
@@ -21,11 +46,72 @@ type Options struct {
 	// DefaultUserAdditionalIPs specifies set of additional IPs applied to default user
 	DefaultUserAdditionalIPs   []string
 	DefaultUserInsertHostRegex bool
+	// DefaultScenario specifies cluster to be inserted in case no cluster specified
+	DefaultScenario *DefaultScenario
+	// NamingScheme specifies how shards and replicas with auto-generated names are named
+	NamingScheme string
+	// FeatureGates enables or disables optional normalization steps
+	FeatureGates map[string]bool
+	// ReservedHostPorts specifies ports allocated to hosts of other CHIs, which are not allocated to hosts of this CHI
+	ReservedHostPorts []int32
+}
+
+// DefaultScenario specifies default cluster
+type DefaultScenario struct {
+	ClusterName   string
+	ShardsCount   int
+	ReplicasCount int
 }
 
 // NewOptions creates new Options
 func NewOptions() *Options {
 	return &Options{
 		DefaultUserInsertHostRegex: true,
+		DefaultScenario:            NewDefaultScenario(),
+		NamingScheme:               NamingSchemeIndex,
+	}
+}
+
+// NewDefaultScenario creates new DefaultScenario with single-host cluster
+func NewDefaultScenario() *DefaultScenario {
+	return &DefaultScenario{
+		ClusterName:   "cluster",
+		ShardsCount:   1,
+		ReplicasCount: 1,
+	}
+}
+
+// IsFeatureEnabled checks whether specified feature gate is enabled
+func (o *Options) IsFeatureEnabled(gate string) bool {
+	if o == nil {
+		return defaultFeatureGates[gate]
+	}
+	if enabled, ok := o.FeatureGates[gate]; ok {
+		return enabled
+	}
+	return defaultFeatureGates[gate]
+}
+
+// GetDefaultScenario gets default scenario
+func (o *Options) GetDefaultScenario() *DefaultScenario {
+	if (o == nil) || (o.DefaultScenario == nil) {
+		return NewDefaultScenario()
+	}
+	return o.DefaultScenario
+}
+
+// GetReservedHostPorts gets ports allocated to hosts of other CHIs
+func (o *Options) GetReservedHostPorts() []int32 {
+	if o == nil {
+		return nil
+	}
+	return o.ReservedHostPorts
+}
+
+// GetNamingScheme gets naming scheme
+func (o *Options) GetNamingScheme() string {
+	if (o == nil) || (o.NamingScheme == "") {
+		return NamingSchemeIndex
 	}
+	return o.NamingScheme
 }
@@ -0,0 +1,150 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package normalizer
+
+import (
+	log "github.com/altinity/clickhouse-operator/pkg/announcer"
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/apis/deployment"
+	model "github.com/altinity/clickhouse-operator/pkg/model/chi"
+)
+
+// maxPortNumber is the highest port number available for allocation
+const maxPortNumber = 65535
+
+// portAllocator allocates unique ports to hosts of host templates with Allocated port distribution.
+// Host gets ports of a slot, which are base ports shifted by slot number. Hosts keep slots recorded in the status,
+// new hosts get the lowest free slot, ports of which are allocated neither to other hosts nor to hosts of other CHIs
+type portAllocator struct {
+	previous    map[string]int
+	slots       map[int]bool
+	taken       map[int32]bool
+	allocations []api.ChiPortAllocation
+}
+
+// newPortAllocator creates new portAllocator
+func newPortAllocator(previous []api.ChiPortAllocation, reserved []int32) *portAllocator {
+	a := &portAllocator{
+		previous: make(map[string]int),
+		slots:    make(map[int]bool),
+		taken:    make(map[int32]bool),
+	}
+	for _, allocation := range previous {
+		a.previous[allocation.Host] = allocation.Slot
+		a.slots[allocation.Slot] = true
+		for _, port := range allocation.Ports {
+			a.taken[port] = true
+		}
+	}
+	for _, port := range reserved {
+		a.taken[port] = true
+	}
+	return a
+}
+
+// allocatedPort is a port of the host to be allocated along with its base value
+type allocatedPort struct {
+	port *int32
+	base int32
+}
+
+// allocate allocates ports of a slot to the host's ports which are not assigned explicitly
+func (a *portAllocator) allocate(host *api.ChiHost, template *api.ChiHostTemplate) {
+	if !hasPortDistribution(template, deployment.PortDistributionAllocated) {
+		return
+	}
+
+	var ports []allocatedPort
+	for _, port := range []allocatedPort{
+		{port: &host.TCPPort, base: getBasePort(template.Spec.TCPPort, model.ChDefaultTCPPortNumber)},
+		{port: &host.TLSPort, base: getBasePort(template.Spec.TLSPort, model.ChDefaultTLSPortNumber)},
+		{port: &host.HTTPPort, base: getBasePort(template.Spec.HTTPPort, model.ChDefaultHTTPPortNumber)},
+		{port: &host.HTTPSPort, base: getBasePort(template.Spec.HTTPSPort, model.ChDefaultHTTPSPortNumber)},
+		{port: &host.InterserverHTTPPort, base: getBasePort(template.Spec.InterserverHTTPPort, model.ChDefaultInterserverHTTPPortNumber)},
+	} {
+		if api.IsPortUnassigned(*port.port) {
+			ports = append(ports, port)
+		}
+	}
+	if len(ports) == 0 {
+		// All ports are specified explicitly
+		return
+	}
+
+	name := model.CreateStatefulSetName(host)
+	slot, ok := a.previous[name]
+	if !ok {
+		if slot, ok = a.findFreeSlot(ports); !ok {
+			log.V(1).M(host).F().Warning("unable to allocate ports to host %s, no free ports left", name)
+			return
+		}
+	}
+
+	allocation := api.ChiPortAllocation{
+		Host: name,
+		Slot: slot,
+	}
+	for _, port := range ports {
+		*port.port = port.base + int32(slot)
+		a.taken[*port.port] = true
+		allocation.Ports = append(allocation.Ports, *port.port)
+	}
+	a.slots[slot] = true
+	a.allocations = append(a.allocations, allocation)
+	host.Runtime.PortsAllocated = true
+}
+
+// findFreeSlot finds the lowest slot, which is not allocated and ports of which are not taken
+func (a *portAllocator) findFreeSlot(ports []allocatedPort) (int, bool) {
+	for slot := 0; ; slot++ {
+		if a.slots[slot] {
+			continue
+		}
+		free := true
+		for _, port := range ports {
+			value := port.base + int32(slot)
+			if value > maxPortNumber {
+				return 0, false
+			}
+			free = free && !a.taken[value]
+		}
+		if free {
+			return slot, true
+		}
+	}
+}
+
+// getAllocations gets allocations made
+func (a *portAllocator) getAllocations() []api.ChiPortAllocation {
+	return a.allocations
+}
+
+// hasPortDistribution checks whether host template specifies port distribution of the specified type
+func hasPortDistribution(template *api.ChiHostTemplate, _type string) bool {
+	for _, portDistribution := range template.PortDistribution {
+		if portDistribution.Type == _type {
+			return true
+		}
+	}
+	return false
+}
+
+// getBasePort gets base port of the allocation, either specified in the template or the default one
+func getBasePort(port, _default int32) int32 {
+	if api.IsPortAssigned(port) {
+		return port
+	}
+	return _default
+}
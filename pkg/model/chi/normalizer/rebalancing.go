@@ -0,0 +1,47 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package normalizer
+
+import (
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+)
+
+// normalizeClusterRebalancing normalizes rebalancing of the cluster
+func (n *Normalizer) normalizeClusterRebalancing(cluster *api.Cluster) *api.ChiClusterRebalancing {
+	rebalancing := cluster.Rebalancing
+	if rebalancing == nil {
+		return nil
+	}
+
+	rebalancing.Enabled = rebalancing.Enabled.Normalize(false)
+	rebalancing.Drain = rebalancing.Drain.Normalize(false)
+
+	var tables []string
+	for _, table := range rebalancing.Tables {
+		if table != "" {
+			tables = append(tables, table)
+		}
+	}
+	rebalancing.Tables = tables
+
+	if rebalancing.PartitionsPerRun <= 0 {
+		rebalancing.PartitionsPerRun = api.RebalancingPartitionsPerRunDefault
+	}
+	if rebalancing.PauseSeconds < 0 {
+		rebalancing.PauseSeconds = 0
+	}
+
+	return rebalancing
+}
@@ -0,0 +1,62 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package normalizer
+
+import (
+	log "github.com/altinity/clickhouse-operator/pkg/announcer"
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	model "github.com/altinity/clickhouse-operator/pkg/model/chi"
+)
+
+// normalizeClusterRemoteReplicas normalizes replicas of the cluster living outside of the CHI
+func (n *Normalizer) normalizeClusterRemoteReplicas(cluster *api.Cluster) []api.ChiRemoteReplica {
+	remoteReplicas := cluster.RemoteReplicas[:0]
+	for _, remoteReplica := range cluster.RemoteReplicas {
+		if remoteReplica.Name == "" {
+			log.V(1).M(n.ctx.GetTarget()).F().Warning("cluster %s has remote replica without name, skip it", cluster.Name)
+			continue
+		}
+		if (len(remoteReplica.Hosts) == 0) && (remoteReplica.CHI == nil) {
+			log.V(1).M(n.ctx.GetTarget()).F().Warning("remote replica %s of cluster %s has neither hosts nor CHI specified, skip it", remoteReplica.Name, cluster.Name)
+			continue
+		}
+
+		if remoteReplica.CHI != nil {
+			if remoteReplica.CHI.Namespace == "" {
+				remoteReplica.CHI.Namespace = n.ctx.GetTarget().Namespace
+			}
+			if remoteReplica.CHI.Cluster == "" {
+				remoteReplica.CHI.Cluster = cluster.Name
+			}
+			if remoteReplica.CHI.Replica == "" {
+				remoteReplica.CHI.Replica = model.CreateReplicaName(nil, 0)
+			}
+		}
+
+		// Links to remote regions are expected to be secure and compressed unless specified otherwise
+		remoteReplica.Secure = remoteReplica.Secure.Normalize(true)
+		remoteReplica.Compression = remoteReplica.Compression.Normalize(true)
+		if remoteReplica.Port == 0 {
+			if remoteReplica.IsSecure() {
+				remoteReplica.Port = model.ChDefaultTLSPortNumber
+			} else {
+				remoteReplica.Port = model.ChDefaultTCPPortNumber
+			}
+		}
+
+		remoteReplicas = append(remoteReplicas, remoteReplica)
+	}
+	return remoteReplicas
+}
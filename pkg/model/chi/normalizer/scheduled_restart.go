@@ -0,0 +1,44 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package normalizer
+
+import (
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+)
+
+// normalizeClusterScheduledRestart normalizes scheduled restarts of the cluster
+func (n *Normalizer) normalizeClusterScheduledRestart(cluster *api.Cluster) *api.ChiClusterScheduledRestart {
+	restart := cluster.ScheduledRestart
+	if restart == nil {
+		return nil
+	}
+
+	restart.Enabled = restart.Enabled.Normalize(false)
+	if restart.IntervalHours <= 0 {
+		restart.IntervalHours = api.ScheduledRestartIntervalHoursDefault
+	}
+
+	if window := restart.Window; window != nil {
+		var days []string
+		for _, day := range window.Days {
+			if day != "" {
+				days = append(days, day)
+			}
+		}
+		window.Days = days
+	}
+
+	return restart
+}
@@ -27,9 +27,11 @@ const (
 )
 
 // prepareListOfTemplates prepares list of CHI templates to be used by the CHI
-func prepareListOfTemplates(chi *api.ClickHouseInstallation) (templates []*api.ChiTemplateRef) {
+func prepareListOfTemplates(chi *api.ClickHouseInstallation, withAutoTemplates bool) (templates []*api.ChiTemplateRef) {
 	// 1. Get list of auto templates available
-	templates = append(templates, prepareListOfAutoTemplates(chi)...)
+	if withAutoTemplates {
+		templates = append(templates, prepareListOfAutoTemplates(chi)...)
+	}
 	// 2. Append templates which are explicitly requested by the CHI
 	templates = append(templates, prepareListOfManualTemplates(chi)...)
 	// 3 Normalize list of templates
@@ -41,7 +43,7 @@ func prepareListOfTemplates(chi *api.ClickHouseInstallation) (templates []*api.C
 
 func prepareListOfAutoTemplates(chi *api.ClickHouseInstallation) (templates []*api.ChiTemplateRef) {
 	// 1. Get list of auto templates available
-	if autoTemplates := chop.Config().GetAutoTemplates(); len(autoTemplates) > 0 {
+	if autoTemplates := chop.Config().GetAutoTemplates(chi.Namespace); len(autoTemplates) > 0 {
 		log.V(1).M(chi).F().Info("Found auto-templates num: %d", len(autoTemplates))
 		for _, template := range autoTemplates {
 			log.V(1).M(chi).F().Info(
@@ -68,9 +70,9 @@ func prepareListOfManualTemplates(chi *api.ClickHouseInstallation) (templates []
 }
 
 // ApplyCHITemplates applies templates over target n.ctx.chi
-func ApplyCHITemplates(target, chi *api.ClickHouseInstallation) (appliedTemplates []*api.ChiTemplateRef) {
+func ApplyCHITemplates(target, chi *api.ClickHouseInstallation, withAutoTemplates bool) (appliedTemplates []*api.ChiTemplateRef) {
 	// Prepare list of templates to be applied to the CHI
-	templates := prepareListOfTemplates(chi)
+	templates := prepareListOfTemplates(chi, withAutoTemplates)
 
 	// Apply templates from the list and count applied templates - just to make nice log entry
 	for _, template := range templates {
@@ -83,15 +85,39 @@ func ApplyCHITemplates(target, chi *api.ClickHouseInstallation) (appliedTemplate
 	return appliedTemplates
 }
 
+// ListApplicableTemplates lists templates which would be applied to the CHI
+func ListApplicableTemplates(chi *api.ClickHouseInstallation, withAutoTemplates bool) (templates []*api.ClickHouseInstallation) {
+	for _, templateRef := range prepareListOfTemplates(chi, withAutoTemplates) {
+		if template := findApplicableTemplate(templateRef, chi); template != nil {
+			templates = append(templates, template)
+		}
+	}
+	return templates
+}
+
 // applyTemplate applies a template over target n.ctx.chi
 // `chi *api.ClickHouseInstallation` is used to determine whether the template should be applied or not only
 func applyTemplate(target *api.ClickHouseInstallation, templateRef *api.ChiTemplateRef, chi *api.ClickHouseInstallation) bool {
-	if templateRef == nil {
-		log.Warning("unable to apply template - nil templateRef provided")
+	template := findApplicableTemplate(templateRef, chi)
+	if template == nil {
 		// Template is not applied
 		return false
 	}
 
+	//  Let's apply template and append used template to the list of used templates
+	mergeFromTemplate(target, template)
+
+	// Template is applied
+	return true
+}
+
+// findApplicableTemplate finds template by templateRef, in case the template wants to be applied to the CHI
+func findApplicableTemplate(templateRef *api.ChiTemplateRef, chi *api.ClickHouseInstallation) *api.ClickHouseInstallation {
+	if templateRef == nil {
+		log.Warning("unable to apply template - nil templateRef provided")
+		return nil
+	}
+
 	// What template are we going to apply?
 	defaultNamespace := chi.Namespace
 	template := chop.Config().FindTemplate(templateRef, defaultNamespace)
@@ -99,8 +125,7 @@ func applyTemplate(target *api.ClickHouseInstallation, templateRef *api.ChiTempl
 		log.V(1).M(templateRef.Namespace, templateRef.Name).F().Warning(
 			"skip template - UNABLE to find by templateRef: %s/%s",
 			templateRef.Namespace, templateRef.Name)
-		// Template is not applied
-		return false
+		return nil
 	}
 
 	// What target(s) this template wants to be applied to?
@@ -112,8 +137,7 @@ func applyTemplate(target *api.ClickHouseInstallation, templateRef *api.ChiTempl
 		log.V(1).M(templateRef.Namespace, templateRef.Name).F().Info(
 			"Skip template: %s/%s. Selector: %v does not match labels: %v",
 			templateRef.Namespace, templateRef.Name, selector, chi.Labels)
-		// Template is not applied
-		return false
+		return nil
 	}
 
 	//
@@ -124,11 +148,7 @@ func applyTemplate(target *api.ClickHouseInstallation, templateRef *api.ChiTempl
 		"Apply template: %s/%s. Selector: %v matches labels: %v",
 		templateRef.Namespace, templateRef.Name, selector, chi.Labels)
 
-	//  Let's apply template and append used template to the list of used templates
-	mergeFromTemplate(target, template)
-
-	// Template is applied
-	return true
+	return template
 }
 
 func mergeFromTemplate(target, template *api.ClickHouseInstallation) *api.ClickHouseInstallation {
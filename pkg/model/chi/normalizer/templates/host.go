@@ -41,7 +41,8 @@ func NormalizeHostTemplate(template *api.ChiHostTemplate) {
 		switch portDistribution.Type {
 		case
 			deployment.PortDistributionUnspecified,
-			deployment.PortDistributionClusterScopeIndex:
+			deployment.PortDistributionClusterScopeIndex,
+			deployment.PortDistributionAllocated:
 			// distribution is known
 		default:
 			// distribution is not known
@@ -15,6 +15,9 @@
 package templates
 
 import (
+	"fmt"
+	"strings"
+
 	core "k8s.io/api/core/v1"
 
 	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
@@ -34,8 +37,19 @@ func NormalizePodTemplate(replicasCount int, template *api.ChiPodTemplate) {
 	// PodDistribution
 	normalizePodTemplateDistribution(replicasCount, template)
 
+	// Architecture
+	template.Architecture = strings.ToLower(strings.TrimSpace(template.Architecture))
+
+	// Sidecars and init containers
+	template.Sidecars = normalizePodTemplateContainers(template.Sidecars, "sidecar")
+	template.InitContainers = normalizePodTemplateContainers(template.InitContainers, "init")
+
 	// Spec
 	template.Spec.Affinity = model.MergeAffinity(template.Spec.Affinity, model.NewAffinity(template))
+	template.Spec.TopologySpreadConstraints = model.MergeTopologySpreadConstraints(
+		template.Spec.TopologySpreadConstraints,
+		model.NewTopologySpreadConstraints(template),
+	)
 
 	// In case we have hostNetwork specified, we need to have ClusterFirstWithHostNet DNS policy, because of
 	// https://kubernetes.io/docs/concepts/services-networking/dns-pod-service/#pod-s-dns-policy
@@ -46,6 +60,10 @@ func NormalizePodTemplate(replicasCount int, template *api.ChiPodTemplate) {
 }
 
 func normalizePodTemplateZone(template *api.ChiPodTemplate) {
+	// Tolerations of the zone are applicable without node labels as well
+	model.AppendTolerations(template, template.Zone.Tolerations...)
+	template.Zone.MatchExpressions = NormalizeZoneMatchExpressions(template.Zone.MatchExpressions)
+
 	switch {
 	case len(template.Zone.Values) == 0:
 		// In case no values specified - no key is reasonable
@@ -60,6 +78,24 @@ func normalizePodTemplateZone(template *api.ChiPodTemplate) {
 	}
 }
 
+// normalizePodTemplateContainers drops containers without image and names unnamed ones as <prefix>-N
+func normalizePodTemplateContainers(containers []core.Container, prefix string) []core.Container {
+	var normalized []core.Container
+	for i := range containers {
+		container := containers[i]
+		container.Name = strings.TrimSpace(container.Name)
+		container.Image = strings.TrimSpace(container.Image)
+		if container.Image == "" {
+			continue
+		}
+		if container.Name == "" {
+			container.Name = fmt.Sprintf("%s-%d", prefix, i)
+		}
+		normalized = append(normalized, container)
+	}
+	return normalized
+}
+
 func normalizePodTemplateDistribution(replicasCount int, template *api.ChiPodTemplate) {
 	for i := range template.PodDistribution {
 		if additionalPodDistributions := normalizePodDistribution(replicasCount, &template.PodDistribution[i]); additionalPodDistributions != nil {
@@ -76,84 +112,13 @@ func normalizePodDistribution(replicasCount int, podDistribution *api.ChiPodDist
 		podDistribution.TopologyKey = defaultTopologyKey
 	}
 
-	switch podDistribution.Type {
-	case
-		deployment.PodDistributionUnspecified,
-		// AntiAffinity section
-		deployment.PodDistributionClickHouseAntiAffinity,
-		deployment.PodDistributionShardAntiAffinity,
-		deployment.PodDistributionReplicaAntiAffinity:
-		// PodDistribution is known
-		if podDistribution.Scope == "" {
-			podDistribution.Scope = deployment.PodDistributionScopeCluster
-		}
+	scenario, ok := model.GetPodDistributionScenario(podDistribution.Type)
+	if !ok {
+		// PodDistribution is not known
+		podDistribution.Type = deployment.PodDistributionUnspecified
 		return nil
-	case
-		deployment.PodDistributionAnotherNamespaceAntiAffinity,
-		deployment.PodDistributionAnotherClickHouseInstallationAntiAffinity,
-		deployment.PodDistributionAnotherClusterAntiAffinity:
-		// PodDistribution is known
-		return nil
-	case
-		deployment.PodDistributionMaxNumberPerNode:
-		// PodDistribution is known
-		if podDistribution.Number < 0 {
-			podDistribution.Number = 0
-		}
-		return nil
-	case
-		// Affinity section
-		deployment.PodDistributionNamespaceAffinity,
-		deployment.PodDistributionClickHouseInstallationAffinity,
-		deployment.PodDistributionClusterAffinity,
-		deployment.PodDistributionShardAffinity,
-		deployment.PodDistributionReplicaAffinity,
-		deployment.PodDistributionPreviousTailAffinity:
-		// PodDistribution is known
-		return nil
-
-	case deployment.PodDistributionCircularReplication:
-		// PodDistribution is known
-		// PodDistributionCircularReplication is a shortcut to simplify complex set of other distributions
-		// All shortcuts have to be expanded
-
-		if podDistribution.Scope == "" {
-			podDistribution.Scope = deployment.PodDistributionScopeCluster
-		}
-
-		// Expand shortcut
-		return []api.ChiPodDistribution{
-			{
-				Type:  deployment.PodDistributionShardAntiAffinity,
-				Scope: podDistribution.Scope,
-			},
-			{
-				Type:  deployment.PodDistributionReplicaAntiAffinity,
-				Scope: podDistribution.Scope,
-			},
-			{
-				Type:   deployment.PodDistributionMaxNumberPerNode,
-				Scope:  podDistribution.Scope,
-				Number: replicasCount,
-			},
-
-			{
-				Type: deployment.PodDistributionPreviousTailAffinity,
-			},
-
-			{
-				Type: deployment.PodDistributionNamespaceAffinity,
-			},
-			{
-				Type: deployment.PodDistributionClickHouseInstallationAffinity,
-			},
-			{
-				Type: deployment.PodDistributionClusterAffinity,
-			},
-		}
 	}
 
-	// PodDistribution is not known
-	podDistribution.Type = deployment.PodDistributionUnspecified
-	return nil
+	// PodDistribution is known
+	return scenario.Normalize(podDistribution, replicasCount)
 }
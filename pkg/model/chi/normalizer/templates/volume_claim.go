@@ -39,4 +39,31 @@ func normalizeStorageManagement(storage *api.StorageManagement) {
 	if !storage.PVCReclaimPolicy.IsValid() {
 		storage.PVCReclaimPolicy = api.PVCReclaimPolicyUnspecified
 	}
+
+	// Check DiskPressureThreshold
+	if (storage.DiskPressureThreshold < 0) || (storage.DiskPressureThreshold > 100) {
+		storage.DiskPressureThreshold = 0
+	}
+
+	// Check AutoExpansion
+	if storage.AutoExpansion != nil {
+		if storage.AutoExpansion.StepPercent < 0 {
+			storage.AutoExpansion.StepPercent = 0
+		}
+		if _, ok := storage.AutoExpansion.GetMaxSize(); !ok {
+			storage.AutoExpansion.MaxSize = ""
+		}
+	}
+}
+
+// NormalizeStorageManagementDefaults normalizes StorageManagement of .spec.defaults
+func NormalizeStorageManagementDefaults(storage *api.StorageManagement) {
+	normalizeStorageManagement(storage)
+
+	if storage.AutoExpansion != nil {
+		storage.AutoExpansion.Enabled = storage.AutoExpansion.Enabled.Normalize(false)
+		if storage.AutoExpansion.StepPercent == 0 {
+			storage.AutoExpansion.StepPercent = api.AutoExpansionStepPercentDefault
+		}
+	}
 }
@@ -0,0 +1,62 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package templates
+
+import (
+	"sort"
+	"strings"
+
+	core "k8s.io/api/core/v1"
+)
+
+// NormalizeZoneMatchExpressions normalizes node selector requirements of the zone.
+// Malformed requirements are dropped, values and requirements are sorted,
+// so pod template generated from the zone is stable and does not trigger restarts on reorder
+func NormalizeZoneMatchExpressions(requirements []core.NodeSelectorRequirement) []core.NodeSelectorRequirement {
+	var res []core.NodeSelectorRequirement
+	for i := range requirements {
+		requirement := requirements[i].DeepCopy()
+		requirement.Key = strings.TrimSpace(requirement.Key)
+		if requirement.Key == "" {
+			continue
+		}
+		switch requirement.Operator {
+		case core.NodeSelectorOpIn, core.NodeSelectorOpNotIn:
+			if len(requirement.Values) == 0 {
+				continue
+			}
+			sort.Strings(requirement.Values)
+		case core.NodeSelectorOpExists, core.NodeSelectorOpDoesNotExist:
+			// No values are allowed for these operators
+			requirement.Values = nil
+		case core.NodeSelectorOpGt, core.NodeSelectorOpLt:
+			if len(requirement.Values) != 1 {
+				continue
+			}
+		default:
+			// Unknown operator
+			continue
+		}
+		res = append(res, *requirement)
+	}
+
+	sort.SliceStable(res, func(i, j int) bool {
+		if res[i].Key != res[j].Key {
+			return res[i].Key < res[j].Key
+		}
+		return res[i].Operator < res[j].Operator
+	})
+	return res
+}
@@ -0,0 +1,41 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package normalizer
+
+import (
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+)
+
+// normalizeClusterTLS normalizes interserver TLS of the cluster
+func (n *Normalizer) normalizeClusterTLS(cluster *api.Cluster) *api.ChiClusterTLS {
+	tls := cluster.TLS
+	if tls == nil {
+		return nil
+	}
+
+	tls.Managed = tls.Managed.Normalize(false)
+	if tls.ValidityDays <= 0 {
+		tls.ValidityDays = api.TLSValidityDaysDefault
+	}
+	if tls.RenewBeforeDays <= 0 {
+		tls.RenewBeforeDays = api.TLSRenewBeforeDaysDefault
+	}
+	if tls.RenewBeforeDays >= tls.ValidityDays {
+		// Certificate would be rotated right after it is issued
+		tls.RenewBeforeDays = tls.ValidityDays / 2
+	}
+
+	return tls
+}
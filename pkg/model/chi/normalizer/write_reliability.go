@@ -0,0 +1,52 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package normalizer
+
+import (
+	"strconv"
+
+	log "github.com/altinity/clickhouse-operator/pkg/announcer"
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	model "github.com/altinity/clickhouse-operator/pkg/model/chi"
+)
+
+// normalizeClusterWriteReliability normalizes write reliability defaults of the cluster
+func (n *Normalizer) normalizeClusterWriteReliability(cluster *api.Cluster) *api.ChiWriteReliability {
+	writeReliability := cluster.WriteReliability
+	if writeReliability == nil {
+		return nil
+	}
+
+	switch writeReliability.InsertQuorum {
+	case "":
+		// Quorum of the majority of replicas is a safe default
+		writeReliability.InsertQuorum = api.InsertQuorumMajority
+	case api.InsertQuorumMajority:
+	default:
+		if quorum, err := strconv.Atoi(writeReliability.InsertQuorum); (err != nil) || (quorum < 0) {
+			log.V(1).M(n.ctx.GetTarget()).F().Warning("cluster %s has invalid insert quorum %s, use %s", cluster.Name, writeReliability.InsertQuorum, api.InsertQuorumMajority)
+			writeReliability.InsertQuorum = api.InsertQuorumMajority
+		}
+	}
+
+	if (writeReliability.InsertQuorumTimeout == nil) || (*writeReliability.InsertQuorumTimeout < 0) {
+		timeout := model.InsertQuorumTimeoutDefault
+		writeReliability.InsertQuorumTimeout = &timeout
+	}
+
+	writeReliability.SelectSequentialConsistency = writeReliability.SelectSequentialConsistency.Normalize(false)
+
+	return writeReliability
+}
@@ -0,0 +1,160 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/altinity/clickhouse-operator/pkg/util"
+)
+
+// PlannedActionType specifies type of planned action
+type PlannedActionType string
+
+// Possible planned action types
+const (
+	// PlannedActionCreate creates new object
+	PlannedActionCreate PlannedActionType = "create"
+	// PlannedActionUpdate updates existing object
+	PlannedActionUpdate PlannedActionType = "update"
+	// PlannedActionDelete deletes existing object
+	PlannedActionDelete PlannedActionType = "delete"
+	// PlannedActionRestart restarts host
+	PlannedActionRestart PlannedActionType = "restart"
+	// PlannedActionDDL runs DDL on host
+	PlannedActionDDL PlannedActionType = "ddl"
+)
+
+// Host describes ClickHouse host entity type. Used by planned actions, not registered in Registry
+const Host EntityType = "Host"
+
+// PlannedAction describes one action reconcile is going to perform
+type PlannedAction struct {
+	Type   PlannedActionType `json:"type"             yaml:"type"`
+	Kind   EntityType        `json:"kind"             yaml:"kind"`
+	Name   string            `json:"name"             yaml:"name"`
+	Reason string            `json:"reason,omitempty" yaml:"reason,omitempty"`
+}
+
+// IsDisruptive checks whether action disrupts running hosts or removes objects.
+// Update of StatefulSet is disruptive, since it rolls pods of the host.
+func (a *PlannedAction) IsDisruptive() bool {
+	if a == nil {
+		return false
+	}
+	switch a.Type {
+	case PlannedActionRestart, PlannedActionDelete:
+		return true
+	case PlannedActionUpdate:
+		return a.Kind == StatefulSet
+	}
+	return false
+}
+
+// String returns string representation of the action
+func (a *PlannedAction) String() string {
+	if a == nil {
+		return ""
+	}
+	if a.Reason == "" {
+		return fmt.Sprintf("%s %s %s", a.Type, a.Kind, a.Name)
+	}
+	return fmt.Sprintf("%s %s %s (%s)", a.Type, a.Kind, a.Name, a.Reason)
+}
+
+// Plan is an ordered list of actions reconcile is going to perform
+type Plan struct {
+	Actions []*PlannedAction `json:"actions,omitempty" yaml:"actions,omitempty"`
+}
+
+// NewPlan creates new empty plan
+func NewPlan() *Plan {
+	return new(Plan)
+}
+
+// Add appends action to the plan
+func (p *Plan) Add(_type PlannedActionType, kind EntityType, name, reason string) *Plan {
+	if p == nil {
+		return nil
+	}
+	p.Actions = append(p.Actions, &PlannedAction{
+		Type:   _type,
+		Kind:   kind,
+		Name:   name,
+		Reason: reason,
+	})
+	return p
+}
+
+// Len returns number of actions in the plan
+func (p *Plan) Len() int {
+	if p == nil {
+		return 0
+	}
+	return len(p.Actions)
+}
+
+// Walk calls f for each action of the plan in order
+func (p *Plan) Walk(f func(action *PlannedAction)) {
+	if p == nil {
+		return
+	}
+	for _, action := range p.Actions {
+		f(action)
+	}
+}
+
+// HasDisruptive checks whether plan has disruptive actions
+func (p *Plan) HasDisruptive() bool {
+	has := false
+	p.Walk(func(action *PlannedAction) {
+		has = has || action.IsDisruptive()
+	})
+	return has
+}
+
+// Disruptive returns plan consisting of disruptive actions only
+func (p *Plan) Disruptive() *Plan {
+	disruptive := NewPlan()
+	p.Walk(func(action *PlannedAction) {
+		if action.IsDisruptive() {
+			disruptive.Actions = append(disruptive.Actions, action)
+		}
+	})
+	return disruptive
+}
+
+// Hash returns hash of the plan, used to approve exactly this plan
+func (p *Plan) Hash() string {
+	if p.Len() == 0 {
+		return ""
+	}
+	return util.HashIntoString([]byte(p.String()))
+}
+
+// Lines returns string representation of each action of the plan
+func (p *Plan) Lines() []string {
+	var lines []string
+	p.Walk(func(action *PlannedAction) {
+		lines = append(lines, action.String())
+	})
+	return lines
+}
+
+// String returns string representation of the plan, one action per line
+func (p *Plan) String() string {
+	return strings.Join(p.Lines(), "\n")
+}
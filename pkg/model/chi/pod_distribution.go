@@ -0,0 +1,262 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"sync"
+
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/apis/deployment"
+	"github.com/altinity/clickhouse-operator/pkg/util"
+)
+
+// PodDistributionScenario describes how one type of pod distribution is normalized and
+// how it contributes to the affinity of the pod.
+// New scenario types have to be registered with RegisterPodDistributionScenario
+// and added to the podDistribution type enum of the CRD.
+type PodDistributionScenario interface {
+	// Normalize normalizes pod distribution in place and returns additional pod distributions
+	// in case the scenario is a shortcut to be expanded
+	Normalize(podDistribution *api.ChiPodDistribution, replicasCount int) []api.ChiPodDistribution
+	// Apply adds affinity rules of the pod distribution into affinity
+	Apply(affinity *core.Affinity, podDistribution *api.ChiPodDistribution)
+}
+
+// PodDistributionScenarioFuncs is an adapter to build PodDistributionScenario out of functions.
+// Any of the functions can be nil
+type PodDistributionScenarioFuncs struct {
+	NormalizeFunc func(podDistribution *api.ChiPodDistribution, replicasCount int) []api.ChiPodDistribution
+	ApplyFunc     func(affinity *core.Affinity, podDistribution *api.ChiPodDistribution)
+}
+
+// Normalize calls NormalizeFunc, if any
+func (f PodDistributionScenarioFuncs) Normalize(podDistribution *api.ChiPodDistribution, replicasCount int) []api.ChiPodDistribution {
+	if f.NormalizeFunc == nil {
+		return nil
+	}
+	return f.NormalizeFunc(podDistribution, replicasCount)
+}
+
+// Apply calls ApplyFunc, if any
+func (f PodDistributionScenarioFuncs) Apply(affinity *core.Affinity, podDistribution *api.ChiPodDistribution) {
+	if f.ApplyFunc == nil {
+		return
+	}
+	f.ApplyFunc(affinity, podDistribution)
+}
+
+var (
+	podDistributionScenariosMutex sync.RWMutex
+	podDistributionScenarios      = map[string]PodDistributionScenario{
+		deployment.PodDistributionUnspecified: PodDistributionScenarioFuncs{
+			NormalizeFunc: normalizePodDistributionScope,
+		},
+
+		// AntiAffinity section
+		deployment.PodDistributionClickHouseAntiAffinity: PodDistributionScenarioFuncs{
+			NormalizeFunc: normalizePodDistributionScope,
+			ApplyFunc: newPodAntiAffinityWithMatchLabels(map[string]string{
+				LabelAppName: LabelAppValue,
+			}),
+		},
+		deployment.PodDistributionShardAntiAffinity: PodDistributionScenarioFuncs{
+			NormalizeFunc: normalizePodDistributionScope,
+			ApplyFunc: newPodAntiAffinityWithMatchLabels(map[string]string{
+				LabelShardName: macrosShardName,
+			}),
+		},
+		deployment.PodDistributionReplicaAntiAffinity: PodDistributionScenarioFuncs{
+			NormalizeFunc: normalizePodDistributionScope,
+			ApplyFunc: newPodAntiAffinityWithMatchLabels(map[string]string{
+				LabelReplicaName: macrosReplicaName,
+			}),
+		},
+		deployment.PodDistributionAnotherNamespaceAntiAffinity: PodDistributionScenarioFuncs{
+			ApplyFunc: newPodAntiAffinityWithNotIn(LabelNamespace, macrosNamespace),
+		},
+		deployment.PodDistributionAnotherClickHouseInstallationAntiAffinity: PodDistributionScenarioFuncs{
+			ApplyFunc: newPodAntiAffinityWithNotIn(LabelCHIName, macrosChiName),
+		},
+		deployment.PodDistributionAnotherClusterAntiAffinity: PodDistributionScenarioFuncs{
+			ApplyFunc: newPodAntiAffinityWithNotIn(LabelClusterName, macrosClusterName),
+		},
+		deployment.PodDistributionMaxNumberPerNode: PodDistributionScenarioFuncs{
+			NormalizeFunc: func(podDistribution *api.ChiPodDistribution, _ int) []api.ChiPodDistribution {
+				if podDistribution.Number < 0 {
+					podDistribution.Number = 0
+				}
+				return nil
+			},
+			ApplyFunc: newPodAntiAffinityWithMatchLabels(map[string]string{
+				LabelClusterScopeCycleIndex: macrosClusterScopeCycleIndex,
+			}),
+		},
+
+		// Affinity section
+		deployment.PodDistributionNamespaceAffinity: PodDistributionScenarioFuncs{
+			ApplyFunc: newPreferredPodAffinity(map[string]string{
+				LabelNamespace: macrosNamespace,
+			}),
+		},
+		deployment.PodDistributionClickHouseInstallationAffinity: PodDistributionScenarioFuncs{
+			ApplyFunc: newPreferredPodAffinity(map[string]string{
+				LabelCHIName: macrosChiName,
+			}),
+		},
+		deployment.PodDistributionClusterAffinity: PodDistributionScenarioFuncs{
+			ApplyFunc: newPreferredPodAffinity(map[string]string{
+				LabelClusterName: macrosClusterName,
+			}),
+		},
+		deployment.PodDistributionShardAffinity: PodDistributionScenarioFuncs{
+			ApplyFunc: newPreferredPodAffinity(map[string]string{
+				LabelShardName: macrosShardName,
+			}),
+		},
+		deployment.PodDistributionReplicaAffinity: PodDistributionScenarioFuncs{
+			ApplyFunc: newPreferredPodAffinity(map[string]string{
+				LabelReplicaName: macrosReplicaName,
+			}),
+		},
+		deployment.PodDistributionPreviousTailAffinity: PodDistributionScenarioFuncs{
+			ApplyFunc: applyPreviousTailAffinity,
+		},
+
+		// Shortcuts section
+		deployment.PodDistributionCircularReplication: PodDistributionScenarioFuncs{
+			NormalizeFunc: expandCircularReplication,
+		},
+
+		// Topology spread section. Provides no affinity, see NewTopologySpreadConstraints
+		deployment.PodDistributionTopologySpread: PodDistributionScenarioFuncs{
+			NormalizeFunc: normalizeTopologySpread,
+		},
+	}
+)
+
+// RegisterPodDistributionScenario registers scenario for the specified pod distribution type.
+// Already registered scenario of the same type is replaced.
+func RegisterPodDistributionScenario(_type string, scenario PodDistributionScenario) {
+	podDistributionScenariosMutex.Lock()
+	defer podDistributionScenariosMutex.Unlock()
+	podDistributionScenarios[_type] = scenario
+}
+
+// GetPodDistributionScenario gets scenario registered for the specified pod distribution type
+func GetPodDistributionScenario(_type string) (PodDistributionScenario, bool) {
+	podDistributionScenariosMutex.RLock()
+	defer podDistributionScenariosMutex.RUnlock()
+	scenario, ok := podDistributionScenarios[_type]
+	return scenario, ok
+}
+
+// normalizePodDistributionScope ensures pod distribution has scope specified
+func normalizePodDistributionScope(podDistribution *api.ChiPodDistribution, _ int) []api.ChiPodDistribution {
+	if podDistribution.Scope == "" {
+		podDistribution.Scope = deployment.PodDistributionScopeCluster
+	}
+	return nil
+}
+
+// expandCircularReplication expands CircularReplication shortcut into a set of other distributions
+func expandCircularReplication(podDistribution *api.ChiPodDistribution, replicasCount int) []api.ChiPodDistribution {
+	normalizePodDistributionScope(podDistribution, replicasCount)
+
+	return []api.ChiPodDistribution{
+		{
+			Type:  deployment.PodDistributionShardAntiAffinity,
+			Scope: podDistribution.Scope,
+		},
+		{
+			Type:  deployment.PodDistributionReplicaAntiAffinity,
+			Scope: podDistribution.Scope,
+		},
+		{
+			Type:   deployment.PodDistributionMaxNumberPerNode,
+			Scope:  podDistribution.Scope,
+			Number: replicasCount,
+		},
+
+		{
+			Type: deployment.PodDistributionPreviousTailAffinity,
+		},
+
+		{
+			Type: deployment.PodDistributionNamespaceAffinity,
+		},
+		{
+			Type: deployment.PodDistributionClickHouseInstallationAffinity,
+		},
+		{
+			Type: deployment.PodDistributionClusterAffinity,
+		},
+	}
+}
+
+// newPreferredPodAffinity creates apply function which adds preferred pod affinity term with specified labels
+func newPreferredPodAffinity(matchLabels map[string]string) func(*core.Affinity, *api.ChiPodDistribution) {
+	return func(affinity *core.Affinity, podDistribution *api.ChiPodDistribution) {
+		// Each term receives its own copy of labels
+		term := newWeightedPodAffinityTermWithMatchLabels(1, podDistribution, util.MergeStringMapsOverwrite(nil, matchLabels))
+		affinity.PodAffinity = appendWeightedPodAffinityTerm(affinity.PodAffinity, &term)
+	}
+}
+
+// applyPreviousTailAffinity adds pod affinity pointing to the tail of the previous cycle
+func applyPreviousTailAffinity(affinity *core.Affinity, podDistribution *api.ChiPodDistribution) {
+	matchLabels := map[string]string{
+		LabelClusterScopeIndex: macrosClusterScopeCycleHeadPointsToPreviousCycleTail,
+	}
+	// Newer k8s insists on Required for this Affinity
+	term := newPodAffinityTermWithMatchLabels(podDistribution, matchLabels)
+	affinity.PodAffinity = appendPodAffinityTerm(affinity.PodAffinity, &term)
+	newPreferredPodAffinity(matchLabels)(affinity, podDistribution)
+}
+
+// newPodAntiAffinityWithMatchLabels creates apply function which adds required pod anti-affinity term
+// with specified labels limited by the scope of the pod distribution
+func newPodAntiAffinityWithMatchLabels(matchLabels map[string]string) func(*core.Affinity, *api.ChiPodDistribution) {
+	return func(affinity *core.Affinity, podDistribution *api.ChiPodDistribution) {
+		// Each term receives its own copy of labels
+		term := newPodAffinityTermWithMatchLabels(
+			podDistribution,
+			newMatchLabels(podDistribution, util.MergeStringMapsOverwrite(nil, matchLabels)),
+		)
+		affinity.PodAntiAffinity = appendPodAntiAffinityTerm(affinity.PodAntiAffinity, &term)
+	}
+}
+
+// newPodAntiAffinityWithNotIn creates apply function which adds required pod anti-affinity term
+// avoiding pods with the label not equal to the specified value
+func newPodAntiAffinityWithNotIn(label, value string) func(*core.Affinity, *api.ChiPodDistribution) {
+	return func(affinity *core.Affinity, podDistribution *api.ChiPodDistribution) {
+		term := newPodAffinityTermWithMatchExpressions(
+			podDistribution,
+			[]meta.LabelSelectorRequirement{
+				{
+					Key:      label,
+					Operator: meta.LabelSelectorOpNotIn,
+					Values: []string{
+						value,
+					},
+				},
+			},
+		)
+		affinity.PodAntiAffinity = appendPodAntiAffinityTerm(affinity.PodAntiAffinity, &term)
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	core "k8s.io/api/core/v1"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+)
+
+func TestPodDistributionScenarioRegistry(t *testing.T) {
+	const spotTolerant = "TestSpotTolerant"
+
+	// Unregistered types provide no affinity
+	_, ok := GetPodDistributionScenario(spotTolerant)
+	require.False(t, ok)
+	template := &api.ChiPodTemplate{
+		PodDistribution: []api.ChiPodDistribution{{Type: spotTolerant}},
+	}
+	require.Nil(t, NewAffinity(template))
+
+	RegisterPodDistributionScenario(spotTolerant, PodDistributionScenarioFuncs{
+		NormalizeFunc: func(podDistribution *api.ChiPodDistribution, _ int) []api.ChiPodDistribution {
+			podDistribution.TopologyKey = "node.kubernetes.io/instance-type"
+			return nil
+		},
+		ApplyFunc: func(affinity *core.Affinity, podDistribution *api.ChiPodDistribution) {
+			affinity.PodAntiAffinity = appendWeightedPodAntiAffinityTerm(affinity.PodAntiAffinity, &core.WeightedPodAffinityTerm{
+				Weight: 1,
+				PodAffinityTerm: core.PodAffinityTerm{
+					TopologyKey: podDistribution.TopologyKey,
+				},
+			})
+		},
+	})
+	defer func() {
+		podDistributionScenariosMutex.Lock()
+		delete(podDistributionScenarios, spotTolerant)
+		podDistributionScenariosMutex.Unlock()
+	}()
+
+	scenario, ok := GetPodDistributionScenario(spotTolerant)
+	require.True(t, ok)
+	require.Nil(t, scenario.Normalize(&template.PodDistribution[0], 1))
+
+	affinity := NewAffinity(template)
+	require.NotNil(t, affinity)
+	require.Nil(t, affinity.NodeAffinity)
+	require.Nil(t, affinity.PodAffinity)
+	require.Len(t, affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution, 1)
+	require.Equal(t, "node.kubernetes.io/instance-type", affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution[0].PodAffinityTerm.TopologyKey)
+}
@@ -43,6 +43,10 @@ const (
 	//PV EntityType = "PV"
 	// PDB describes PodDisruptionBudget entity type
 	PDB EntityType = "PDB"
+	// Deployment describes Deployment entity type
+	Deployment EntityType = "Deployment"
+	// VolumeSnapshot describes VolumeSnapshot entity type
+	VolumeSnapshot EntityType = "VolumeSnapshot"
 )
 
 // Registry specifies registry struct
@@ -302,6 +306,46 @@ func (r *Registry) WalkPDB(f func(meta meta.ObjectMeta)) {
 	r.WalkEntityType(PDB, f)
 }
 
+// RegisterDeployment register Deployment
+func (r *Registry) RegisterDeployment(meta meta.ObjectMeta) {
+	r.registerEntity(Deployment, meta)
+}
+
+// HasDeployment checks whether registry has specified Deployment
+func (r *Registry) HasDeployment(meta meta.ObjectMeta) bool {
+	return r.hasEntity(Deployment, meta)
+}
+
+// NumDeployment gets number of Deployment
+func (r *Registry) NumDeployment() int {
+	return r.Len(Deployment)
+}
+
+// WalkDeployment walk over specified entity types
+func (r *Registry) WalkDeployment(f func(meta meta.ObjectMeta)) {
+	r.WalkEntityType(Deployment, f)
+}
+
+// RegisterVolumeSnapshot register VolumeSnapshot
+func (r *Registry) RegisterVolumeSnapshot(meta meta.ObjectMeta) {
+	r.registerEntity(VolumeSnapshot, meta)
+}
+
+// HasVolumeSnapshot checks whether registry has specified VolumeSnapshot
+func (r *Registry) HasVolumeSnapshot(meta meta.ObjectMeta) bool {
+	return r.hasEntity(VolumeSnapshot, meta)
+}
+
+// NumVolumeSnapshot gets number of VolumeSnapshot
+func (r *Registry) NumVolumeSnapshot() int {
+	return r.Len(VolumeSnapshot)
+}
+
+// WalkVolumeSnapshot walk over specified entity types
+func (r *Registry) WalkVolumeSnapshot(f func(meta meta.ObjectMeta)) {
+	r.WalkEntityType(VolumeSnapshot, f)
+}
+
 // Subtract subtracts specified registry from main
 func (r *Registry) Subtract(sub *Registry) *Registry {
 	if sub.Len() == 0 {
@@ -322,6 +366,11 @@ func (r *Registry) Subtract(sub *Registry) *Registry {
 
 // hasEntity
 func (r *Registry) hasEntity(entityType EntityType, meta meta.ObjectMeta) bool {
+	if r == nil {
+		// Nil registry, used as no failed objects on delete, has nothing
+		return false
+	}
+
 	// Try to minimize coarse grained locking at the registry level. Immediately getOrCreate for the entity type
 	// and then begin operating on that (it uses a finer grained lock).
 	setForType := r.ensureObjectSetForType(entityType)
@@ -0,0 +1,196 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package render renders Kubernetes objects the operator creates for a CHI.
+// It has no dependencies on the controller and Kubernetes clients, so third-party tools and tests
+// are able to render exactly what the operator would create for a given CHI.
+package render
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/kubernetes-sigs/yaml"
+
+	apps "k8s.io/api/apps/v1"
+	core "k8s.io/api/core/v1"
+	policy "k8s.io/api/policy/v1"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/chop"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/creator"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/normalizer"
+)
+
+// Manifests specifies objects rendered for a CHI
+type Manifests struct {
+	ConfigMaps           []*core.ConfigMap
+	Services             []*core.Service
+	Secrets              []*core.Secret
+	PodDisruptionBudgets []*policy.PodDisruptionBudget
+	StatefulSets         []*apps.StatefulSet
+	Deployments          []*apps.Deployment
+
+	// objects lists all rendered objects in order of reconcile
+	objects []runtime.Object
+}
+
+// Init initializes operator's configuration used by rendering.
+// Configuration is read from configFilePath, empty path means default configuration.
+// Does nothing in case configuration is already initialized.
+func Init(configFilePath string) {
+	if chop.Get() == nil {
+		chop.New(nil, configFilePath)
+	}
+}
+
+// NoSecrets reports all secrets as absent, rendering does not have access to the cluster.
+// Suitable as secret getter of the normalizer.
+func NoSecrets(namespace, name string) (*core.Secret, error) {
+	return nil, apiErrors.NewNotFound(core.Resource("secrets"), fmt.Sprintf("%s/%s", namespace, name))
+}
+
+// Render normalizes CHI and renders objects the operator would create for it.
+// Default configuration is used in case configuration is not initialized with Init.
+func Render(chi *api.ClickHouseInstallation, options *normalizer.Options) (*Manifests, error) {
+	Init("")
+
+	normalized, err := normalizer.NewNormalizer(NoSecrets).CreateTemplatedCHI(chi, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return RenderNormalized(normalized), nil
+}
+
+// RenderNormalized renders objects the operator would create for already normalized CHI.
+// Auto-generated cluster secrets carry random password and freshly issued certificates, same as the operator creates.
+func RenderNormalized(chi *api.ClickHouseInstallation) *Manifests {
+	m := &Manifests{}
+	c := creator.NewCreator(chi)
+
+	m.addConfigMap(c.CreateConfigMapCHICommon(nil))
+	m.addConfigMap(c.CreateConfigMapCHICommonUsers())
+	m.addConfigMap(c.CreateConfigMapCHILogging())
+	m.addConfigMap(c.CreateConfigMapCHITopology())
+	if !chi.IsStopped() {
+		m.addService(c.CreateServiceCHI())
+	}
+
+	chi.WalkClusters(func(cluster *api.Cluster) error {
+		m.addService(c.CreateServiceCluster(cluster))
+		m.addService(c.CreateServiceClusterReadTier(cluster))
+		m.addService(c.CreateServiceClusterReader(cluster))
+		m.addService(c.CreateServiceClusterWriter(cluster))
+		if cluster.Secret.Source() == api.ClusterSecretSourceAuto {
+			m.addSecret(c.CreateClusterSecret(cluster))
+		}
+		if cluster.TLS.IsManaged() {
+			m.addSecret(c.CreateClusterTLSSecret(cluster))
+		}
+		m.addPDB(c.NewPodDisruptionBudget(cluster))
+
+		cluster.WalkShards(func(index int, shard *api.ChiShard) error {
+			m.addService(c.CreateServiceShard(shard))
+			shard.WalkHosts(func(host *api.ChiHost) error {
+				m.addConfigMap(c.CreateConfigMapHost(host))
+				m.addStatefulSet(c.CreateStatefulSet(host, false))
+				m.addService(c.CreateServiceHost(host))
+				return nil
+			})
+			return nil
+		})
+		return nil
+	})
+
+	m.addSecret(c.CreateSecretConnection())
+	m.addSecret(c.CreateSecretChproxy())
+	m.addDeployment(c.CreateDeploymentChproxy())
+	m.addService(c.CreateServiceChproxy())
+
+	return m
+}
+
+// Objects returns all rendered objects in order the operator reconciles them
+func (m *Manifests) Objects() []runtime.Object {
+	if m == nil {
+		return nil
+	}
+	return m.objects
+}
+
+// YAML returns all rendered objects as multi-document YAML
+func (m *Manifests) YAML() ([]byte, error) {
+	buf := &bytes.Buffer{}
+	for _, obj := range m.Objects() {
+		data, err := yaml.Marshal(obj)
+		if err != nil {
+			return nil, err
+		}
+		buf.WriteString("---\n")
+		buf.Write(data)
+	}
+	return buf.Bytes(), nil
+}
+
+func (m *Manifests) addConfigMap(configMap *core.ConfigMap) {
+	if configMap == nil {
+		return
+	}
+	configMap.TypeMeta = meta.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"}
+	m.ConfigMaps = append(m.ConfigMaps, configMap)
+	m.objects = append(m.objects, configMap)
+}
+
+func (m *Manifests) addService(service *core.Service) {
+	if service == nil {
+		return
+	}
+	service.TypeMeta = meta.TypeMeta{Kind: "Service", APIVersion: "v1"}
+	m.Services = append(m.Services, service)
+	m.objects = append(m.objects, service)
+}
+
+func (m *Manifests) addSecret(secret *core.Secret) {
+	if secret == nil {
+		return
+	}
+	secret.TypeMeta = meta.TypeMeta{Kind: "Secret", APIVersion: "v1"}
+	m.Secrets = append(m.Secrets, secret)
+	m.objects = append(m.objects, secret)
+}
+
+func (m *Manifests) addPDB(pdb *policy.PodDisruptionBudget) {
+	pdb.TypeMeta = meta.TypeMeta{Kind: "PodDisruptionBudget", APIVersion: "policy/v1"}
+	m.PodDisruptionBudgets = append(m.PodDisruptionBudgets, pdb)
+	m.objects = append(m.objects, pdb)
+}
+
+func (m *Manifests) addStatefulSet(statefulSet *apps.StatefulSet) {
+	statefulSet.TypeMeta = meta.TypeMeta{Kind: "StatefulSet", APIVersion: "apps/v1"}
+	m.StatefulSets = append(m.StatefulSets, statefulSet)
+	m.objects = append(m.objects, statefulSet)
+}
+
+func (m *Manifests) addDeployment(deployment *apps.Deployment) {
+	if deployment == nil {
+		return
+	}
+	deployment.TypeMeta = meta.TypeMeta{Kind: "Deployment", APIVersion: "apps/v1"}
+	m.Deployments = append(m.Deployments, deployment)
+	m.objects = append(m.objects, deployment)
+}
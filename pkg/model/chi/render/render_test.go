@@ -0,0 +1,202 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render_test
+
+import (
+	"os"
+	"testing"
+
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/apis/deployment"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/builder"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/normalizer"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/render"
+)
+
+func TestMain(m *testing.M) {
+	render.Init("../../../../config/config.yaml")
+	os.Exit(m.Run())
+}
+
+func TestRender(t *testing.T) {
+	chi := &api.ClickHouseInstallation{
+		ObjectMeta: meta.ObjectMeta{Namespace: "test", Name: "render"},
+		Spec: api.ChiSpec{
+			Configuration: &api.Configuration{
+				Clusters: []*api.Cluster{
+					{
+						Name: "main",
+						Layout: &api.ChiClusterLayout{
+							ShardsCount:   2,
+							ReplicasCount: 2,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	m, err := render.Render(chi, nil)
+	if err != nil {
+		t.Fatalf("unable to render err: %v", err)
+	}
+
+	// Common, common users, topology and per-host config maps
+	if got := len(m.ConfigMaps); got != 7 {
+		t.Errorf("config maps: got %d want %d", got, 7)
+	}
+	// CHI and per-host services, cluster and shard services require templates
+	if got := len(m.Services); got != 5 {
+		t.Errorf("services: got %d want %d", got, 5)
+	}
+	if got := len(m.StatefulSets); got != 4 {
+		t.Errorf("stateful sets: got %d want %d", got, 4)
+	}
+	if got := len(m.PodDisruptionBudgets); got != 1 {
+		t.Errorf("pod disruption budgets: got %d want %d", got, 1)
+	}
+	if got, want := len(m.Objects()), 7+5+4+1+len(m.Secrets); got != want {
+		t.Errorf("objects: got %d want %d", got, want)
+	}
+	for _, sts := range m.StatefulSets {
+		if sts.Namespace != "test" || sts.Kind != "StatefulSet" {
+			t.Errorf("unexpected stateful set %s/%s kind %s", sts.Namespace, sts.Name, sts.Kind)
+		}
+	}
+	if _, err := m.YAML(); err != nil {
+		t.Errorf("unable to marshal err: %v", err)
+	}
+}
+
+func TestRenderLazyLayout(t *testing.T) {
+	newCHI := func() *api.ClickHouseInstallation {
+		podTemplate := builder.NewPodTemplate("pod", "clickhouse/clickhouse-server:23.8")
+		podTemplate.PodDistribution = []api.ChiPodDistribution{
+			{Type: deployment.PodDistributionMaxNumberPerNode, Number: 2},
+		}
+		explicit := builder.NewCluster("explicit")
+		explicit.Layout = &api.ChiClusterLayout{
+			Shards: []api.ChiShard{{ReplicasCount: 2}, {ReplicasCount: 1}},
+		}
+		return builder.NewCHI("test", "lazy",
+			builder.WithPodTemplates(podTemplate),
+			builder.WithCluster(explicit),
+			builder.WithCluster(builder.NewCluster("standard", builder.WithShards(5), builder.WithReplicas(3), builder.WithPodTemplate("pod"))),
+		)
+	}
+
+	eager, err := render.Render(newCHI(), nil)
+	if err != nil {
+		t.Fatalf("unable to render eager err: %v", err)
+	}
+	lazy, err := render.Render(newCHI(), newLazyLayoutOptions())
+	if err != nil {
+		t.Fatalf("unable to render lazy err: %v", err)
+	}
+
+	if got, want := len(lazy.StatefulSets), 3+5*3; got != want {
+		t.Errorf("stateful sets: got %d want %d", got, want)
+	}
+	eagerYAML, err := eager.YAML()
+	if err != nil {
+		t.Fatalf("unable to marshal eager err: %v", err)
+	}
+	lazyYAML, err := lazy.YAML()
+	if err != nil {
+		t.Fatalf("unable to marshal lazy err: %v", err)
+	}
+	if string(eagerYAML) != string(lazyYAML) {
+		t.Errorf("lazy layout renders different objects than eager one")
+	}
+}
+
+func newLazyLayoutOptions() *normalizer.Options {
+	options := normalizer.NewOptions()
+	options.FeatureGates = map[string]bool{
+		normalizer.FeatureGateLazyLayout: true,
+	}
+	return options
+}
+
+func TestLazyLayoutKeepsHosts(t *testing.T) {
+	chi := builder.NewCHI("test", "lazy",
+		builder.WithCluster(builder.NewCluster("standard", builder.WithShards(3), builder.WithReplicas(2))),
+	)
+	normalized, err := normalizer.NewNormalizer(render.NoSecrets).CreateTemplatedCHI(chi, newLazyLayoutOptions())
+	if err != nil {
+		t.Fatalf("unable to normalize err: %v", err)
+	}
+	if !normalized.Spec.Configuration.Clusters[0].IsLazy() {
+		t.Fatalf("cluster is expected to be lazy")
+	}
+
+	normalized.WalkHosts(func(host *api.ChiHost) error {
+		host.Runtime.NodeAddress = host.Name
+		return nil
+	})
+	hosts := 0
+	normalized.WalkHosts(func(host *api.ChiHost) error {
+		hosts++
+		if host.Runtime.NodeAddress != host.Name {
+			t.Errorf("host %s lost modification between walks", host.Name)
+		}
+		return nil
+	})
+	if hosts != 3*2 {
+		t.Errorf("hosts: got %d want %d", hosts, 3*2)
+	}
+}
+
+func benchmarkRenderLayout(b *testing.B, options *normalizer.Options) {
+	for i := 0; i < b.N; i++ {
+		chi := builder.NewCHI("test", "bench",
+			builder.WithCluster(builder.NewCluster("standard", builder.WithShards(50), builder.WithReplicas(2))),
+		)
+		if _, err := render.Render(chi, options); err != nil {
+			b.Fatalf("unable to render err: %v", err)
+		}
+	}
+}
+
+func BenchmarkRenderEagerLayout(b *testing.B) {
+	benchmarkRenderLayout(b, nil)
+}
+
+func BenchmarkRenderLazyLayout(b *testing.B) {
+	benchmarkRenderLayout(b, newLazyLayoutOptions())
+}
+
+func TestRenderStopped(t *testing.T) {
+	chi := builder.NewCHI("test", "stopped",
+		builder.WithCluster(builder.NewCluster("main", builder.WithShards(2))),
+		builder.WithStop(true),
+	)
+	m, err := render.Render(chi, nil)
+	if err != nil {
+		t.Fatalf("unable to render err: %v", err)
+	}
+
+	// Per-host services are kept, CHI entry point is not, so clients do not reach installation without pods
+	for _, svc := range m.Services {
+		if svc.Name == "clickhouse-stopped" {
+			t.Errorf("stopped CHI must have no entry point service")
+		}
+	}
+	if got := len(m.Services); got != 2 {
+		t.Errorf("services: got %d want %d", got, 2)
+	}
+}
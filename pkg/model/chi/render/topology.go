@@ -0,0 +1,220 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"fmt"
+
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	model "github.com/altinity/clickhouse-operator/pkg/model/chi"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/normalizer"
+)
+
+// Capacity specifies resources requested by rendered objects
+type Capacity struct {
+	Pods    int               `json:"pods"`
+	CPU     resource.Quantity `json:"cpu"`
+	Memory  resource.Quantity `json:"memory"`
+	Storage resource.Quantity `json:"storage"`
+}
+
+const (
+	// TopologyChangeMaxShards and TopologyChangeMaxReplicas limit the size of the cluster the change is
+	// simulated for, since the whole proposed CHI is rendered in memory
+	TopologyChangeMaxShards   = 256
+	TopologyChangeMaxReplicas = 32
+)
+
+// TopologyChange specifies proposed shards and replicas count of the cluster.
+// Zero count means count is not changed.
+type TopologyChange struct {
+	// Cluster specifies name of the cluster. Can be omitted in case CHI has the only cluster
+	Cluster  string `json:"cluster,omitempty"`
+	Shards   int    `json:"shards,omitempty"`
+	Replicas int    `json:"replicas,omitempty"`
+}
+
+// TopologySimulation describes projected outcome of the topology change
+type TopologySimulation struct {
+	Current  *Capacity `json:"current"`
+	Proposed *Capacity `json:"proposed"`
+	Delta    *Capacity `json:"delta"`
+	// AddedHosts and RemovedHosts count hosts created and deleted by the change
+	AddedHosts   int `json:"addedHosts"`
+	RemovedHosts int `json:"removedHosts"`
+	// HostRestarts counts hosts kept by the change, which StatefulSets are going to be rolled
+	HostRestarts int `json:"hostRestarts"`
+}
+
+// Capacity sums up pods and resources requested by rendered StatefulSets and Deployments.
+// Container requests default to limits, same as Kubernetes does.
+func (m *Manifests) Capacity() *Capacity {
+	res := &Capacity{}
+	if m == nil {
+		return res
+	}
+	add := func(replicas *int32, spec *core.PodSpec, claims []core.PersistentVolumeClaim) {
+		count := 1
+		if replicas != nil {
+			count = int(*replicas)
+		}
+		for i := 0; i < count; i++ {
+			res.Pods++
+			for j := range spec.Containers {
+				res.CPU.Add(getContainerRequest(&spec.Containers[j], core.ResourceCPU))
+				res.Memory.Add(getContainerRequest(&spec.Containers[j], core.ResourceMemory))
+			}
+			for j := range claims {
+				res.Storage.Add(claims[j].Spec.Resources.Requests[core.ResourceStorage])
+			}
+		}
+	}
+	for _, statefulSet := range m.StatefulSets {
+		add(statefulSet.Spec.Replicas, &statefulSet.Spec.Template.Spec, statefulSet.Spec.VolumeClaimTemplates)
+	}
+	for _, deployment := range m.Deployments {
+		add(deployment.Spec.Replicas, &deployment.Spec.Template.Spec, nil)
+	}
+	return res
+}
+
+// getContainerRequest gets requested amount of the resource, falls back to the limit
+func getContainerRequest(container *core.Container, name core.ResourceName) resource.Quantity {
+	if quantity, ok := container.Resources.Requests[name]; ok {
+		return quantity
+	}
+	return container.Resources.Limits[name]
+}
+
+// Sub calculates difference between the capacity and the specified one
+func (c *Capacity) Sub(from *Capacity) *Capacity {
+	res := &Capacity{
+		Pods:    c.Pods - from.Pods,
+		CPU:     c.CPU.DeepCopy(),
+		Memory:  c.Memory.DeepCopy(),
+		Storage: c.Storage.DeepCopy(),
+	}
+	res.CPU.Sub(from.CPU)
+	res.Memory.Sub(from.Memory)
+	res.Storage.Sub(from.Storage)
+	return res
+}
+
+// Apply applies topology change to the CHI
+func (change *TopologyChange) Apply(chi *api.ClickHouseInstallation) error {
+	if (change.Shards < 0) || (change.Replicas < 0) {
+		return fmt.Errorf("shards and replicas count can not be negative")
+	}
+	if change.Shards > TopologyChangeMaxShards {
+		return fmt.Errorf("shards count %d exceeds max %d", change.Shards, TopologyChangeMaxShards)
+	}
+	if change.Replicas > TopologyChangeMaxReplicas {
+		return fmt.Errorf("replicas count %d exceeds max %d", change.Replicas, TopologyChangeMaxReplicas)
+	}
+	if chi.Spec.Configuration == nil {
+		return fmt.Errorf("CHI has no clusters")
+	}
+
+	var cluster *api.Cluster
+	switch clusters := chi.Spec.Configuration.Clusters; {
+	case change.Cluster != "":
+		cluster = chi.FindCluster(change.Cluster)
+	case len(clusters) == 1:
+		cluster = clusters[0]
+	default:
+		return fmt.Errorf("cluster has to be specified for CHI with %d clusters", len(clusters))
+	}
+	if cluster == nil {
+		return fmt.Errorf("cluster %q not found", change.Cluster)
+	}
+
+	if cluster.Layout == nil {
+		cluster.Layout = api.NewChiClusterLayout()
+	}
+	layout := cluster.Layout
+	if change.Shards > 0 {
+		layout.ShardsCount = change.Shards
+		// Explicitly specified shards beyond the count are removed
+		if len(layout.Shards) > change.Shards {
+			layout.Shards = layout.Shards[:change.Shards]
+		}
+		for i := range layout.Replicas {
+			layout.Replicas[i].ShardsCount = 0
+			if len(layout.Replicas[i].Hosts) > change.Shards {
+				layout.Replicas[i].Hosts = layout.Replicas[i].Hosts[:change.Shards]
+			}
+		}
+	}
+	if change.Replicas > 0 {
+		layout.ReplicasCount = change.Replicas
+		// Explicitly specified replicas beyond the count are removed
+		if len(layout.Replicas) > change.Replicas {
+			layout.Replicas = layout.Replicas[:change.Replicas]
+		}
+		for i := range layout.Shards {
+			layout.Shards[i].ReplicasCount = 0
+			if len(layout.Shards[i].Hosts) > change.Replicas {
+				layout.Shards[i].Hosts = layout.Shards[i].Hosts[:change.Replicas]
+			}
+		}
+	}
+	return nil
+}
+
+// SimulateTopology projects pods, resources, storage and host restarts the topology change of the CHI leads to.
+// Current state is expected to match rendered spec of the CHI, nothing is read from the cluster.
+func SimulateTopology(chi *api.ClickHouseInstallation, change *TopologyChange, options *normalizer.Options) (*TopologySimulation, error) {
+	proposedCHI := chi.DeepCopy()
+	if err := change.Apply(proposedCHI); err != nil {
+		return nil, err
+	}
+
+	current, err := Render(chi.DeepCopy(), options)
+	if err != nil {
+		return nil, err
+	}
+	proposed, err := Render(proposedCHI, options)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &TopologySimulation{
+		Current:  current.Capacity(),
+		Proposed: proposed.Capacity(),
+	}
+	res.Delta = res.Proposed.Sub(res.Current)
+
+	versions := make(map[string]string)
+	for _, statefulSet := range current.StatefulSets {
+		versions[statefulSet.Name], _ = model.GetObjectVersion(statefulSet.ObjectMeta)
+	}
+	for _, statefulSet := range proposed.StatefulSets {
+		version, found := versions[statefulSet.Name]
+		if !found {
+			res.AddedHosts++
+			continue
+		}
+		delete(versions, statefulSet.Name)
+		if proposedVersion, _ := model.GetObjectVersion(statefulSet.ObjectMeta); proposedVersion != version {
+			res.HostRestarts++
+		}
+	}
+	res.RemovedHosts = len(versions)
+
+	return res, nil
+}
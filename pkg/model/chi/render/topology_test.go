@@ -0,0 +1,81 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render_test
+
+import (
+	"testing"
+
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/builder"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/render"
+)
+
+func TestSimulateTopology(t *testing.T) {
+	chi := builder.NewCHI("test", "topology",
+		builder.WithVolumeClaimTemplates(builder.NewVolumeClaimTemplate("data", resource.MustParse("10Gi"))),
+		builder.WithCluster(builder.NewCluster("main",
+			builder.WithShards(2),
+			builder.WithDataVolumeClaimTemplate("data"),
+			builder.WithResources(core.ResourceRequirements{
+				Requests: core.ResourceList{
+					core.ResourceCPU: resource.MustParse("1"),
+				},
+				Limits: core.ResourceList{
+					core.ResourceMemory: resource.MustParse("2Gi"),
+				},
+			}),
+		)),
+	)
+
+	sim, err := render.SimulateTopology(chi, &render.TopologyChange{Replicas: 2}, nil)
+	if err != nil {
+		t.Fatalf("unable to simulate err: %v", err)
+	}
+	if sim.Current.Pods != 2 || sim.Proposed.Pods != 4 || sim.Delta.Pods != 2 {
+		t.Errorf("pods: got %d -> %d delta %d", sim.Current.Pods, sim.Proposed.Pods, sim.Delta.Pods)
+	}
+	for name, got := range map[string]resource.Quantity{"cpu": sim.Delta.CPU, "memory": sim.Delta.Memory, "storage": sim.Delta.Storage} {
+		want := map[string]string{"cpu": "2", "memory": "4Gi", "storage": "20Gi"}[name]
+		if got.Cmp(resource.MustParse(want)) != 0 {
+			t.Errorf("%s delta: got %s want %s", name, got.String(), want)
+		}
+	}
+	if sim.AddedHosts != 2 || sim.RemovedHosts != 0 || sim.HostRestarts != 0 {
+		t.Errorf("hosts: added %d removed %d restarts %d", sim.AddedHosts, sim.RemovedHosts, sim.HostRestarts)
+	}
+
+	sim, err = render.SimulateTopology(chi, &render.TopologyChange{Cluster: "main", Shards: 1}, nil)
+	if err != nil {
+		t.Fatalf("unable to simulate err: %v", err)
+	}
+	if sim.Delta.Pods != -1 || sim.RemovedHosts != 1 || sim.Delta.Storage.Cmp(resource.MustParse("-10Gi")) != 0 {
+		t.Errorf("scale down: pods delta %d removed %d storage delta %s", sim.Delta.Pods, sim.RemovedHosts, sim.Delta.Storage.String())
+	}
+
+	if _, err := render.SimulateTopology(chi, &render.TopologyChange{Cluster: "absent", Shards: 1}, nil); err == nil {
+		t.Errorf("expected error for unknown cluster")
+	}
+	for _, change := range []*render.TopologyChange{
+		{Shards: -1},
+		{Shards: render.TopologyChangeMaxShards + 1},
+		{Replicas: render.TopologyChangeMaxReplicas + 1},
+	} {
+		if _, err := render.SimulateTopology(chi, change, nil); err == nil {
+			t.Errorf("expected error for shards %d replicas %d", change.Shards, change.Replicas)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scaffold
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/kubernetes-sigs/yaml"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/bundle"
+)
+
+// addHelm adds Helm chart with values, values schema and templated CHI
+func addHelm(b *bundle.Bundle, chi *api.ClickHouseInstallation, manifest map[string]interface{}, params []*Param) error {
+	chart, err := yaml.Marshal(map[string]interface{}{
+		"apiVersion":  "v2",
+		"name":        chi.Name,
+		"description": fmt.Sprintf("ClickHouse installation %s", chi.Name),
+		"type":        "application",
+		"version":     "0.1.0",
+	})
+	if err != nil {
+		return err
+	}
+
+	values := make(map[string]interface{})
+	schema := newSchema()
+	for _, param := range params {
+		setNested(values, param.Key, param.Value)
+		schema.add(param.Key, param.Value)
+	}
+	valuesData, err := yaml.Marshal(values)
+	if err != nil {
+		return err
+	}
+	schemaData, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// Namespace is provided by the release
+	manifest = deepCopy(manifest)
+	if metadata, ok := manifest["metadata"].(map[string]interface{}); ok {
+		delete(metadata, "namespace")
+	}
+	template, err := marshal(manifest, params, helmExpression)
+	if err != nil {
+		return err
+	}
+
+	b.Files[path.Join(DirHelm, "Chart.yaml")] = chart
+	b.Files[path.Join(DirHelm, "values.yaml")] = valuesData
+	b.Files[path.Join(DirHelm, "values.schema.json")] = schemaData
+	b.Files[path.Join(DirHelm, "templates", fileManifest)] = template
+	return nil
+}
+
+// helmExpression creates Helm template expression of the param
+func helmExpression(param *Param) string {
+	var keys []string
+	for _, key := range param.Key {
+		keys = append(keys, fmt.Sprintf("%q", key))
+	}
+	expression := "index .Values " + strings.Join(keys, " ")
+	if _, ok := param.Value.(string); ok {
+		expression += " | quote"
+	}
+	return "{{ " + expression + " }}"
+}
+
+// setNested sets value into nested maps by the path of keys
+func setNested(values map[string]interface{}, keys []string, value interface{}) {
+	for _, key := range keys[:len(keys)-1] {
+		next, ok := values[key].(map[string]interface{})
+		if !ok {
+			next = make(map[string]interface{})
+			values[key] = next
+		}
+		values = next
+	}
+	values[keys[len(keys)-1]] = value
+}
+
+// schema specifies JSON schema of values
+type schema struct {
+	Schema     string             `json:"$schema,omitempty"`
+	Type       string             `json:"type"`
+	Properties map[string]*schema `json:"properties,omitempty"`
+}
+
+// newSchema creates new schema of values
+func newSchema() *schema {
+	return &schema{
+		Schema: "https://json-schema.org/draft-07/schema#",
+		Type:   "object",
+	}
+}
+
+// add adds property of the value by the path of keys
+func (s *schema) add(keys []string, value interface{}) {
+	for _, key := range keys[:len(keys)-1] {
+		if s.Properties == nil {
+			s.Properties = make(map[string]*schema)
+		}
+		if _, ok := s.Properties[key]; !ok {
+			s.Properties[key] = &schema{Type: "object"}
+		}
+		s = s.Properties[key]
+	}
+	if s.Properties == nil {
+		s.Properties = make(map[string]*schema)
+	}
+	s.Properties[keys[len(keys)-1]] = &schema{Type: schemaType(value)}
+}
+
+// schemaType returns JSON schema type of the value
+func schemaType(value interface{}) string {
+	switch typed := value.(type) {
+	case json.Number:
+		if _, err := typed.Int64(); err == nil {
+			return "integer"
+		}
+		return "number"
+	case bool:
+		return "boolean"
+	default:
+		return "string"
+	}
+}
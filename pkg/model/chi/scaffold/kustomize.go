@@ -0,0 +1,83 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scaffold
+
+import (
+	"path"
+
+	"github.com/kubernetes-sigs/yaml"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/bundle"
+)
+
+// OverlayDefault specifies name of the kustomize overlay generated
+const OverlayDefault = "default"
+
+// addKustomize adds kustomize base with the CHI and overlay patching parameterized values
+func addKustomize(b *bundle.Bundle, chi *api.ClickHouseInstallation, manifest map[string]interface{}, params []*Param) error {
+	base, err := yaml.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+	baseKustomization, err := yaml.Marshal(map[string]interface{}{
+		"apiVersion": "kustomize.config.k8s.io/v1beta1",
+		"kind":       "Kustomization",
+		"resources":  []string{fileManifest},
+	})
+	if err != nil {
+		return err
+	}
+
+	var ops []map[string]interface{}
+	for _, param := range params {
+		ops = append(ops, map[string]interface{}{
+			"op":    "replace",
+			"path":  pointer(param.Pointer),
+			"value": param.Value,
+		})
+	}
+	overlay := map[string]interface{}{
+		"apiVersion": "kustomize.config.k8s.io/v1beta1",
+		"kind":       "Kustomization",
+		"resources":  []string{"../../base"},
+	}
+	if len(ops) > 0 {
+		patch, err := yaml.Marshal(ops)
+		if err != nil {
+			return err
+		}
+		overlay["patches"] = []map[string]interface{}{
+			{
+				"target": map[string]interface{}{
+					"group":   api.SchemeGroupVersion.Group,
+					"version": api.SchemeGroupVersion.Version,
+					"kind":    api.ClickHouseInstallationCRDResourceKind,
+					"name":    chi.Name,
+				},
+				"patch": string(patch),
+			},
+		}
+	}
+	overlayKustomization, err := yaml.Marshal(overlay)
+	if err != nil {
+		return err
+	}
+
+	b.Files[path.Join(DirKustomize, "base", fileManifest)] = base
+	b.Files[path.Join(DirKustomize, "base", "kustomization.yaml")] = baseKustomization
+	b.Files[path.Join(DirKustomize, "overlays", OverlayDefault, "kustomization.yaml")] = overlayKustomization
+	return nil
+}
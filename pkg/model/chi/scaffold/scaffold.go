@@ -0,0 +1,235 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package scaffold generates parameterized delivery manifests - Helm chart or kustomize overlay -
+// out of existing live CHI, easing the move from hand-managed CHIs to templated delivery.
+package scaffold
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/kubernetes-sigs/yaml"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/bundle"
+)
+
+const (
+	// DirHelm specifies directory within the bundle Helm chart is placed into
+	DirHelm = "helm"
+	// DirKustomize specifies directory within the bundle kustomize base and overlay are placed into
+	DirKustomize = "kustomize"
+
+	fileManifest = "clickhouse-installation.yaml"
+	// marker is a placeholder of parameterized value, replaced after manifest is marshaled
+	marker = "__SCAFFOLD_%d__"
+)
+
+// annotationsSkipped lists annotations of live CHI which are not to be carried into delivery manifests
+var annotationsSkipped = []string{
+	"kubectl.kubernetes.io/last-applied-configuration",
+}
+
+// Param specifies parameterized value of the CHI
+type Param struct {
+	// Key specifies path of the value within values, like [clusters main shards]
+	Key []string
+	// Pointer specifies path of the value within CHI manifest
+	Pointer []string
+	// Value specifies current value
+	Value interface{}
+}
+
+// Build generates Helm chart and kustomize base with overlay out of the live CHI
+func Build(chi *api.ClickHouseInstallation) (*bundle.Bundle, error) {
+	manifest, err := toManifest(clean(chi))
+	if err != nil {
+		return nil, err
+	}
+	params := discover(manifest)
+
+	b := &bundle.Bundle{
+		Files: make(map[string][]byte),
+	}
+	if err := addHelm(b, chi, manifest, params); err != nil {
+		return nil, err
+	}
+	if err := addKustomize(b, chi, manifest, params); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// clean returns copy of the CHI without fields set by the cluster and the operator
+func clean(chi *api.ClickHouseInstallation) *api.ClickHouseInstallation {
+	chi = chi.Copy(api.CopyCHIOptions{
+		SkipStatus:        true,
+		SkipManagedFields: true,
+	})
+	chi.TypeMeta.Kind = api.ClickHouseInstallationCRDResourceKind
+	chi.TypeMeta.APIVersion = api.SchemeGroupVersion.String()
+	chi.ObjectMeta.ResourceVersion = ""
+	chi.ObjectMeta.UID = ""
+	chi.ObjectMeta.Generation = 0
+	chi.ObjectMeta.Finalizers = nil
+	chi.ObjectMeta.OwnerReferences = nil
+	for _, annotation := range annotationsSkipped {
+		delete(chi.ObjectMeta.Annotations, annotation)
+	}
+	chi.Spec.TaskID = nil
+	return chi
+}
+
+// toManifest converts CHI into generic manifest
+func toManifest(chi *api.ClickHouseInstallation) (map[string]interface{}, error) {
+	data, err := json.Marshal(chi)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	manifest := make(map[string]interface{})
+	if err := decoder.Decode(&manifest); err != nil {
+		return nil, err
+	}
+	// Zero timestamp is marshaled as null, which is noise in the manifest
+	if metadata, ok := manifest["metadata"].(map[string]interface{}); ok {
+		delete(metadata, "creationTimestamp")
+	}
+	return manifest, nil
+}
+
+// discover finds values of the manifest worth to be parameterized:
+// layout of clusters, images of pod templates and storage size of volume claim templates
+func discover(manifest map[string]interface{}) (params []*Param) {
+	add := func(key []string, pointer ...string) {
+		if value, ok := get(manifest, pointer); ok {
+			params = append(params, &Param{Key: key, Pointer: pointer, Value: value})
+		}
+	}
+
+	for i, cluster := range list(manifest, "spec", "configuration", "clusters") {
+		name, _ := cluster["name"].(string)
+		index := fmt.Sprint(i)
+		add([]string{"clusters", name, "shards"}, "spec", "configuration", "clusters", index, "layout", "shardsCount")
+		add([]string{"clusters", name, "replicas"}, "spec", "configuration", "clusters", index, "layout", "replicasCount")
+	}
+	for i, template := range list(manifest, "spec", "templates", "podTemplates") {
+		name, _ := template["name"].(string)
+		for j, container := range list(template, "spec", "containers") {
+			containerName, _ := container["name"].(string)
+			add(
+				[]string{"podTemplates", name, containerName, "image"},
+				"spec", "templates", "podTemplates", fmt.Sprint(i), "spec", "containers", fmt.Sprint(j), "image",
+			)
+		}
+	}
+	for i, template := range list(manifest, "spec", "templates", "volumeClaimTemplates") {
+		name, _ := template["name"].(string)
+		add(
+			[]string{"volumeClaimTemplates", name, "storage"},
+			"spec", "templates", "volumeClaimTemplates", fmt.Sprint(i), "spec", "resources", "requests", "storage",
+		)
+	}
+	return params
+}
+
+// get gets value by the path
+func get(obj interface{}, path []string) (interface{}, bool) {
+	for _, segment := range path {
+		switch typed := obj.(type) {
+		case map[string]interface{}:
+			value, ok := typed[segment]
+			if !ok {
+				return nil, false
+			}
+			obj = value
+		case []interface{}:
+			var index int
+			if _, err := fmt.Sscan(segment, &index); err != nil || index >= len(typed) {
+				return nil, false
+			}
+			obj = typed[index]
+		default:
+			return nil, false
+		}
+	}
+	return obj, true
+}
+
+// set sets value by the path, path is expected to exist
+func set(obj interface{}, path []string, value interface{}) {
+	parent, _ := get(obj, path[:len(path)-1])
+	last := path[len(path)-1]
+	switch typed := parent.(type) {
+	case map[string]interface{}:
+		typed[last] = value
+	case []interface{}:
+		var index int
+		if _, err := fmt.Sscan(last, &index); err == nil {
+			typed[index] = value
+		}
+	}
+}
+
+// list gets list of objects by the path
+func list(obj interface{}, path ...string) (res []map[string]interface{}) {
+	value, _ := get(obj, path)
+	items, _ := value.([]interface{})
+	for _, item := range items {
+		if typed, ok := item.(map[string]interface{}); ok {
+			res = append(res, typed)
+		}
+	}
+	return res
+}
+
+// pointer creates JSON pointer out of path
+func pointer(path []string) string {
+	escaper := strings.NewReplacer("~", "~0", "/", "~1")
+	res := ""
+	for _, segment := range path {
+		res += "/" + escaper.Replace(segment)
+	}
+	return res
+}
+
+// marshal marshals manifest having parameterized values replaced with the expressions
+func marshal(manifest map[string]interface{}, params []*Param, expression func(param *Param) string) ([]byte, error) {
+	manifest = deepCopy(manifest)
+	for i, param := range params {
+		set(manifest, param.Pointer, fmt.Sprintf(marker, i))
+	}
+	data, err := yaml.Marshal(manifest)
+	if err != nil {
+		return nil, err
+	}
+	for i, param := range params {
+		data = bytes.ReplaceAll(data, []byte(fmt.Sprintf(marker, i)), []byte(expression(param)))
+	}
+	return data, nil
+}
+
+// deepCopy copies generic manifest
+func deepCopy(manifest map[string]interface{}) map[string]interface{} {
+	data, _ := json.Marshal(manifest)
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	res := make(map[string]interface{})
+	_ = decoder.Decode(&res)
+	return res
+}
@@ -0,0 +1,78 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scaffold_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kubernetes-sigs/yaml"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/builder"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/scaffold"
+)
+
+func TestBuild(t *testing.T) {
+	chi := builder.NewCHI("prod", "events",
+		builder.WithPodTemplates(builder.NewPodTemplate("clickhouse", "clickhouse/clickhouse-server:23.8")),
+		builder.WithVolumeClaimTemplates(builder.NewVolumeClaimTemplate("data", resource.MustParse("100Gi"))),
+		builder.WithCluster(builder.NewCluster("main", builder.WithShards(4), builder.WithReplicas(2))),
+	)
+	chi.ResourceVersion = "12345"
+	chi.Annotations = map[string]string{"kubectl.kubernetes.io/last-applied-configuration": "{}"}
+	chi.EnsureStatus().Status = api.StatusCompleted
+
+	b, err := scaffold.Build(chi)
+	if err != nil {
+		t.Fatalf("unable to build scaffold err: %v", err)
+	}
+
+	values := map[string]map[string]map[string]interface{}{}
+	if err := yaml.Unmarshal(b.Files["helm/values.yaml"], &values); err != nil {
+		t.Fatalf("unable to parse values err: %v", err)
+	}
+	if got := values["clusters"]["main"]["shards"]; got != float64(4) {
+		t.Errorf("shards value: got %v want 4", got)
+	}
+	if got := values["volumeClaimTemplates"]["data"]["storage"]; got != "100Gi" {
+		t.Errorf("storage value: got %v want 100Gi", got)
+	}
+
+	template := string(b.Files["helm/templates/clickhouse-installation.yaml"])
+	for _, expected := range []string{
+		`shardsCount: {{ index .Values "clusters" "main" "shards" }}`,
+		`image: {{ index .Values "podTemplates" "clickhouse" "clickhouse" "image" | quote }}`,
+	} {
+		if !strings.Contains(template, expected) {
+			t.Errorf("template does not contain %s\n%s", expected, template)
+		}
+	}
+	for _, unexpected := range []string{"resourceVersion", "last-applied-configuration", "status", "namespace"} {
+		if strings.Contains(template, unexpected) {
+			t.Errorf("template contains %s\n%s", unexpected, template)
+		}
+	}
+
+	overlay := string(b.Files["kustomize/overlays/default/kustomization.yaml"])
+	if !strings.Contains(overlay, "path: /spec/configuration/clusters/0/layout/replicasCount") {
+		t.Errorf("overlay does not patch replicas\n%s", overlay)
+	}
+	if _, ok := b.Files["kustomize/base/clickhouse-installation.yaml"]; !ok {
+		t.Errorf("kustomize base is missing")
+	}
+}
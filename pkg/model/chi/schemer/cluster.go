@@ -28,12 +28,15 @@ import (
 // Cluster specifies ClickHouse cluster
 type Cluster struct {
 	*clickhouse.Cluster
+	// queries specifies retries of queries
+	queries *api.ChiQueries
 }
 
 // NewCluster creates new cluster object
 func NewCluster() *Cluster {
 	return &Cluster{
 		clickhouse.NewCluster(),
+		nil,
 	}
 }
 
@@ -46,6 +49,30 @@ func (c *Cluster) SetClusterConnectionParams(clusterConnectionParams *clickhouse
 	return c
 }
 
+// SetQueries sets retries of queries
+func (c *Cluster) SetQueries(queries *api.ChiQueries) *Cluster {
+	if c == nil {
+		return nil
+	}
+	c.queries = queries
+	return c
+}
+
+// normalizeQueryOptions normalizes options and applies retries of queries to retriable queries
+func (c *Cluster) normalizeQueryOptions(_opts ...*clickhouse.QueryOptions) *clickhouse.QueryOptions {
+	opts := clickhouse.NewQueryOptions()
+	if len(_opts) > 0 && (_opts[0] != nil) {
+		opts = _opts[0]
+	}
+	if opts.GetRetry() && (opts.Tries == 0) && (c.queries.GetRetries() > 0) {
+		opts.SetTries(c.queries.GetRetries() + 1)
+	}
+	if opts.Backoff == 0 {
+		opts.SetBackoff(c.queries.GetBackoff())
+	}
+	return opts.Normalize()
+}
+
 // queryUnzipColumns
 func (c *Cluster) queryUnzipColumns(ctx context.Context, hosts []string, sql string, columns ...*[]string) error {
 	if util.IsContextDone(ctx) {
@@ -104,28 +131,28 @@ func (c *Cluster) QueryUnzipAndApplyUUIDs(ctx context.Context, endpoints []strin
 // ExecCHI runs set of SQL queries over the whole CHI
 func (c *Cluster) ExecCHI(ctx context.Context, chi *api.ClickHouseInstallation, SQLs []string, _opts ...*clickhouse.QueryOptions) error {
 	hosts := model.CreateFQDNs(chi, nil, false)
-	opts := clickhouse.QueryOptionsNormalize(_opts...)
+	opts := c.normalizeQueryOptions(_opts...)
 	return c.SetHosts(hosts).ExecAll(ctx, SQLs, opts)
 }
 
 // ExecCluster runs set of SQL queries over the cluster
 func (c *Cluster) ExecCluster(ctx context.Context, cluster *api.Cluster, SQLs []string, _opts ...*clickhouse.QueryOptions) error {
 	hosts := model.CreateFQDNs(cluster, nil, false)
-	opts := clickhouse.QueryOptionsNormalize(_opts...)
+	opts := c.normalizeQueryOptions(_opts...)
 	return c.SetHosts(hosts).ExecAll(ctx, SQLs, opts)
 }
 
 // ExecShard runs set of SQL queries over the shard replicas
 func (c *Cluster) ExecShard(ctx context.Context, shard *api.ChiShard, SQLs []string, _opts ...*clickhouse.QueryOptions) error {
 	hosts := model.CreateFQDNs(shard, nil, false)
-	opts := clickhouse.QueryOptionsNormalize(_opts...)
+	opts := c.normalizeQueryOptions(_opts...)
 	return c.SetHosts(hosts).ExecAll(ctx, SQLs, opts)
 }
 
 // ExecHost runs set of SQL queries over the replica
 func (c *Cluster) ExecHost(ctx context.Context, host *api.ChiHost, SQLs []string, _opts ...*clickhouse.QueryOptions) error {
 	hosts := model.CreateFQDNs(host, api.ChiHost{}, false)
-	opts := clickhouse.QueryOptionsNormalize(_opts...)
+	opts := c.normalizeQueryOptions(_opts...)
 	c.SetHosts(hosts)
 	if opts.GetSilent() {
 		c.SetLog(log.Silence())
@@ -138,7 +165,7 @@ func (c *Cluster) ExecHost(ctx context.Context, host *api.ChiHost, SQLs []string
 // QueryHost runs specified query on specified host
 func (c *Cluster) QueryHost(ctx context.Context, host *api.ChiHost, sql string, _opts ...*clickhouse.QueryOptions) (*clickhouse.QueryResult, error) {
 	hosts := model.CreateFQDNs(host, api.ChiHost{}, false)
-	opts := clickhouse.QueryOptionsNormalize(_opts...)
+	opts := c.normalizeQueryOptions(_opts...)
 	c.SetHosts(hosts)
 	if opts.GetSilent() {
 		c.SetLog(log.Silence())
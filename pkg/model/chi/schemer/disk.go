@@ -0,0 +1,67 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schemer
+
+import (
+	"context"
+	"strconv"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+)
+
+// Disk describes space usage of a local disk of a host
+type Disk struct {
+	Name       string
+	Path       string
+	FreeSpace  uint64
+	TotalSpace uint64
+}
+
+// GetUsedPercent gets percentage of used disk space
+func (d *Disk) GetUsedPercent() int {
+	if d.TotalSpace == 0 {
+		return 0
+	}
+	return int((d.TotalSpace - d.FreeSpace) * 100 / d.TotalSpace)
+}
+
+// HostDisks returns space usage of local disks of the host
+func (s *ClusterSchemer) HostDisks(ctx context.Context, host *api.ChiHost) ([]*Disk, error) {
+	query, err := s.QueryHost(ctx, host, s.sqlDisks())
+	defer query.Close()
+	if query == nil {
+		return nil, err
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var names, paths, free, total []string
+	if err := query.UnzipColumnsAsStrings(&names, &paths, &free, &total); err != nil {
+		return nil, err
+	}
+
+	var disks []*Disk
+	for i := range names {
+		disk := &Disk{
+			Name: names[i],
+			Path: paths[i],
+		}
+		disk.FreeSpace, _ = strconv.ParseUint(free[i], 10, 64)
+		disk.TotalSpace, _ = strconv.ParseUint(total[i], 10, 64)
+		disks = append(disks, disk)
+	}
+	return disks, nil
+}
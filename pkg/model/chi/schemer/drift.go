@@ -0,0 +1,71 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schemer
+
+import (
+	"context"
+	"strconv"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	model "github.com/altinity/clickhouse-operator/pkg/model/chi"
+)
+
+// HostLoadedConfig fetches configuration actually loaded by the host.
+// Server settings are not fetched from versions lacking system.server_settings
+func (s *ClusterSchemer) HostLoadedConfig(ctx context.Context, host *api.ChiHost) (*model.HostLoadedConfig, error) {
+	macros, err := s.hostNameValues(ctx, host, s.sqlMacros())
+	if err != nil {
+		return nil, err
+	}
+	clusters, err := s.hostNameValues(ctx, host, s.sqlClustersReplicasNum())
+	if err != nil {
+		return nil, err
+	}
+	// system.server_settings is available since 23.3
+	settings, _ := s.hostNameValues(ctx, host, s.sqlServerSettings())
+
+	loaded := &model.HostLoadedConfig{
+		Settings: settings,
+		Macros:   macros,
+		Clusters: make(map[string]int),
+	}
+	for name, value := range clusters {
+		loaded.Clusters[name], _ = strconv.Atoi(value)
+	}
+	return loaded, nil
+}
+
+// hostNameValues runs query returning name and value columns on the host and returns them as a map
+func (s *ClusterSchemer) hostNameValues(ctx context.Context, host *api.ChiHost, sql string) (map[string]string, error) {
+	query, err := s.QueryHost(ctx, host, sql)
+	defer query.Close()
+	if query == nil {
+		return nil, err
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var names, values []string
+	if err := query.UnzipColumnsAsStrings(&names, &values); err != nil {
+		return nil, err
+	}
+
+	res := make(map[string]string, len(names))
+	for i := range names {
+		res[names[i]] = values[i]
+	}
+	return res, nil
+}
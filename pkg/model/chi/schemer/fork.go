@@ -0,0 +1,101 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schemer
+
+import (
+	"context"
+	"path"
+	"strings"
+
+	log "github.com/altinity/clickhouse-operator/pkg/announcer"
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/util"
+)
+
+// ReadonlyReplica describes replicated table which is in readonly mode on a host
+type ReadonlyReplica struct {
+	Database      string
+	Table         string
+	ZookeeperPath string
+	ReplicaName   string
+}
+
+// GetTableName gets full name of the table
+func (r *ReadonlyReplica) GetTableName() string {
+	return r.Database + "." + r.Table
+}
+
+// HostReadonlyReplicas fetches replicated tables being in readonly mode on the host
+func (s *ClusterSchemer) HostReadonlyReplicas(ctx context.Context, host *api.ChiHost) ([]*ReadonlyReplica, error) {
+	var databases, tables, paths, names []string
+	if err := s.queryHostUnzipColumns(ctx, host, s.sqlReadonlyReplicas(), &databases, &tables, &paths, &names); err != nil {
+		return nil, err
+	}
+
+	var replicas []*ReadonlyReplica
+	for i := range databases {
+		replicas = append(replicas, &ReadonlyReplica{
+			Database:      databases[i],
+			Table:         tables[i],
+			ZookeeperPath: paths[i],
+			ReplicaName:   names[i],
+		})
+	}
+	return replicas, nil
+}
+
+// HostZookeeperChildren lists children of the ZooKeeper node via the host.
+// Node is looked up level by level, since system.zookeeper fails on nodes which do not exist
+func (s *ClusterSchemer) HostZookeeperChildren(ctx context.Context, host *api.ChiHost, zkPath string) (children []string, exists bool, err error) {
+	node := "/"
+	for _, name := range strings.Split(strings.Trim(zkPath, "/"), "/") {
+		if name == "" {
+			continue
+		}
+		if children, err = s.hostZookeeperChildren(ctx, host, node); err != nil {
+			return nil, false, err
+		}
+		if !util.InArray(name, children) {
+			return nil, false, nil
+		}
+		node = path.Join(node, name)
+	}
+
+	if children, err = s.hostZookeeperChildren(ctx, host, node); err != nil {
+		return nil, false, err
+	}
+	return children, true, nil
+}
+
+// hostZookeeperChildren lists children of the existing ZooKeeper node via the host
+func (s *ClusterSchemer) hostZookeeperChildren(ctx context.Context, host *api.ChiHost, zkPath string) ([]string, error) {
+	var names []string
+	if err := s.queryHostUnzipColumns(ctx, host, s.sqlZookeeperChildren(zkPath), &names); err != nil {
+		return nil, err
+	}
+	return names, nil
+}
+
+// HostRestoreReplica restores metadata of the replicated table in ZooKeeper from the local parts of the host
+func (s *ClusterSchemer) HostRestoreReplica(ctx context.Context, host *api.ChiHost, replica *ReadonlyReplica) error {
+	log.V(1).M(host).F().Info("Restore replica of %s at %s", replica.GetTableName(), host.Runtime.Address.HostName)
+	return s.ExecHost(ctx, host, []string{s.sqlRestoreReplica(replica)})
+}
+
+// HostRestartReplica reinitializes ZooKeeper session of the replicated table on the host
+func (s *ClusterSchemer) HostRestartReplica(ctx context.Context, host *api.ChiHost, replica *ReadonlyReplica) error {
+	log.V(1).M(host).F().Info("Restart replica of %s at %s", replica.GetTableName(), host.Runtime.Address.HostName)
+	return s.ExecHost(ctx, host, []string{s.sqlRestartReplica(replica)})
+}
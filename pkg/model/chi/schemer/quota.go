@@ -0,0 +1,73 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schemer
+
+import (
+	"context"
+	"strconv"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+)
+
+// QuotaUsage describes usage of a limited resource within a quota interval of a host
+type QuotaUsage struct {
+	Quota string
+	// Key is the key quota is tracked by, user name by default
+	Key string
+	// Duration of the quota interval in seconds
+	Duration uint64
+	Resource string
+	Used     float64
+	Max      float64
+}
+
+// GetUsedPercent gets percentage of the used resource limit
+func (q *QuotaUsage) GetUsedPercent() int {
+	if q.Max <= 0 {
+		return 0
+	}
+	return int(q.Used * 100 / q.Max)
+}
+
+// HostQuotaUsage returns usage of limited resources of quotas of the host
+func (s *ClusterSchemer) HostQuotaUsage(ctx context.Context, host *api.ChiHost) ([]*QuotaUsage, error) {
+	query, err := s.QueryHost(ctx, host, s.sqlQuotaUsage())
+	defer query.Close()
+	if query == nil {
+		return nil, err
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var quotas, keys, durations, resources, used, max []string
+	if err := query.UnzipColumnsAsStrings(&quotas, &keys, &durations, &resources, &used, &max); err != nil {
+		return nil, err
+	}
+
+	var usages []*QuotaUsage
+	for i := range quotas {
+		usage := &QuotaUsage{
+			Quota:    quotas[i],
+			Key:      keys[i],
+			Resource: resources[i],
+		}
+		usage.Duration, _ = strconv.ParseUint(durations[i], 10, 64)
+		usage.Used, _ = strconv.ParseFloat(used[i], 64)
+		usage.Max, _ = strconv.ParseFloat(max[i], 64)
+		usages = append(usages, usage)
+	}
+	return usages, nil
+}
@@ -0,0 +1,34 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schemer
+
+import "testing"
+
+func TestQuotaUsageGetUsedPercent(t *testing.T) {
+	tests := []struct {
+		usage QuotaUsage
+		want  int
+	}{
+		{QuotaUsage{Used: 950, Max: 1000}, 95},
+		{QuotaUsage{Used: 1200, Max: 1000}, 120},
+		{QuotaUsage{Used: 1.5, Max: 2}, 75},
+		{QuotaUsage{Used: 10, Max: 0}, 0},
+	}
+	for _, tt := range tests {
+		if got := tt.usage.GetUsedPercent(); got != tt.want {
+			t.Errorf("used %v max %v: got %d, want %d", tt.usage.Used, tt.usage.Max, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,311 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schemer
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	log "github.com/altinity/clickhouse-operator/pkg/announcer"
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/model/clickhouse"
+	"github.com/altinity/clickhouse-operator/pkg/util"
+)
+
+// Partition describes active partition of a replicated table
+type Partition struct {
+	Database string
+	Table    string
+	ID       string
+	Bytes    uint64
+}
+
+// GetTableName gets full name of the table of the partition
+func (p *Partition) GetTableName() string {
+	return p.Database + "." + p.Table
+}
+
+// ShardPartitions describes replicated tables and their partitions of a shard
+type ShardPartitions struct {
+	// Host specifies replica of the shard to run queries on
+	Host *api.ChiHost
+	// Tables maps full name of a replicated table to ZooKeeper path of the replica of the host
+	Tables     map[string]string
+	Partitions []*Partition
+}
+
+// GetBytes gets size of all partitions of the shard
+func (s *ShardPartitions) GetBytes() (bytes uint64) {
+	for _, partition := range s.Partitions {
+		bytes += partition.Bytes
+	}
+	return bytes
+}
+
+// PartitionMove describes move of a partition from one shard to another
+type PartitionMove struct {
+	Partition *Partition
+	From      *ShardPartitions
+	To        *ShardPartitions
+}
+
+// HostShardPartitions fetches replicated tables and their active partitions from the host
+func (s *ClusterSchemer) HostShardPartitions(ctx context.Context, host *api.ChiHost) (*ShardPartitions, error) {
+	res := &ShardPartitions{
+		Host:   host,
+		Tables: make(map[string]string),
+	}
+
+	var databases, tables, paths []string
+	if err := s.queryHostUnzipColumns(ctx, host, s.sqlReplicatedTables(), &databases, &tables, &paths); err != nil {
+		return nil, err
+	}
+	for i := range databases {
+		res.Tables[databases[i]+"."+tables[i]] = paths[i]
+	}
+
+	var ids, bytes []string
+	databases, tables = nil, nil
+	if err := s.queryHostUnzipColumns(ctx, host, s.sqlReplicatedPartitions(), &databases, &tables, &ids, &bytes); err != nil {
+		return nil, err
+	}
+	for i := range databases {
+		partition := &Partition{
+			Database: databases[i],
+			Table:    tables[i],
+			ID:       ids[i],
+		}
+		partition.Bytes, _ = strconv.ParseUint(bytes[i], 10, 64)
+		res.Partitions = append(res.Partitions, partition)
+	}
+
+	return res, nil
+}
+
+// queryHostUnzipColumns runs specified query on specified host and unzips result into columns
+func (s *ClusterSchemer) queryHostUnzipColumns(ctx context.Context, host *api.ChiHost, sql string, columns ...*[]string) error {
+	query, err := s.QueryHost(ctx, host, sql)
+	defer query.Close()
+	if query == nil {
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	return query.UnzipColumnsAsStrings(columns...)
+}
+
+// Part describes active part of a partition
+type Part struct {
+	Name string
+	// Hash specifies hash of all files of the part, which is kept by fetch and attach
+	Hash string
+}
+
+// MovePartition moves active parts of the partition from the source shard to the target shard part by part.
+// Each part is fetched into the target shard and attached, after that moved parts, and only them, are dropped
+// on the source shard, so rows inserted into the source shard in the meantime are kept.
+// Parts present on the target shard already, e.g. attached by the previous failed move, are not attached again.
+func (s *ClusterSchemer) MovePartition(ctx context.Context, move *PartitionMove) error {
+	partition := move.Partition
+	log.V(1).M(move.To.Host).F().Info(
+		"Move partition %s of %s from %s to %s",
+		partition.ID, partition.GetTableName(), move.From.Host.Runtime.Address.HostName, move.To.Host.Runtime.Address.HostName,
+	)
+
+	opts := clickhouse.NewQueryOptions().SetRetry(false)
+	opts.SetQueryTimeout(time.Hour)
+
+	// Merges would replace moved parts of the source shard with the ones the target shard knows nothing about
+	if err := s.ExecHost(ctx, move.From.Host, []string{s.sqlStopMerges(partition)}, opts); err != nil {
+		return err
+	}
+	defer func() {
+		_ = s.ExecHost(ctx, move.From.Host, []string{s.sqlStartMerges(partition)}, opts)
+	}()
+
+	sourceParts, err := s.hostPartitionParts(ctx, move.From.Host, partition)
+	if err != nil {
+		return err
+	}
+	targetParts, err := s.hostPartitionParts(ctx, move.To.Host, partition)
+	if err != nil {
+		return err
+	}
+	var detached []string
+	if err := s.queryHostUnzipColumns(ctx, move.To.Host, s.sqlPartitionDetachedParts(partition), &detached); err != nil {
+		return err
+	}
+
+	path := move.From.Tables[partition.GetTableName()]
+	for _, part := range selectPartsToAttach(sourceParts, targetParts) {
+		if !util.InArray(part, detached) {
+			if err := s.ExecHost(ctx, move.To.Host, []string{s.sqlFetchPart(partition, part, path)}, opts); err != nil {
+				return err
+			}
+		}
+		if err := s.ExecHost(ctx, move.To.Host, []string{s.sqlAttachPart(partition, part)}, opts); err != nil {
+			return err
+		}
+	}
+
+	var drops []string
+	for _, part := range sourceParts {
+		drops = append(drops, s.sqlDropPart(partition, part.Name))
+	}
+	return s.ExecHost(ctx, move.From.Host, drops, opts)
+}
+
+// hostPartitionParts fetches active parts of the partition from the host
+func (s *ClusterSchemer) hostPartitionParts(ctx context.Context, host *api.ChiHost, partition *Partition) ([]Part, error) {
+	var names, hashes []string
+	if err := s.queryHostUnzipColumns(ctx, host, s.sqlPartitionParts(partition), &names, &hashes); err != nil {
+		return nil, err
+	}
+	parts := make([]Part, len(names))
+	for i := range names {
+		parts[i] = Part{Name: names[i], Hash: hashes[i]}
+	}
+	return parts, nil
+}
+
+// selectPartsToAttach selects names of source parts which are to be attached to the target.
+// Source parts having a counterpart with the same hash on the target are attached already.
+func selectPartsToAttach(source, target []Part) (names []string) {
+	present := make(map[string]int)
+	for _, part := range target {
+		present[part.Hash]++
+	}
+	for _, part := range source {
+		if present[part.Hash] > 0 {
+			present[part.Hash]--
+			continue
+		}
+		names = append(names, part.Name)
+	}
+	return names
+}
+
+// PlanRebalance plans at most limit moves of partitions from source shards to target shards.
+// Oldest partitions are moved first. Each move makes sizes of the source and target shards closer,
+// moves stop as soon as target shards reach average size of a shard.
+func PlanRebalance(sources, targets []*ShardPartitions, limit int) (moves []*PartitionMove) {
+	if (len(sources) == 0) || (len(targets) == 0) {
+		return nil
+	}
+
+	loads := make(map[*ShardPartitions]uint64)
+	var total uint64
+	for _, shard := range append(append([]*ShardPartitions{}, sources...), targets...) {
+		loads[shard] = shard.GetBytes()
+		total += loads[shard]
+	}
+	average := total / uint64(len(sources)+len(targets))
+
+	// Partitions of each source ordered from the oldest to the newest
+	candidates := make(map[*ShardPartitions][]*Partition)
+	for _, source := range sources {
+		partitions := append([]*Partition{}, source.Partitions...)
+		sort.SliceStable(partitions, func(i, j int) bool {
+			return partitions[i].ID < partitions[j].ID
+		})
+		candidates[source] = partitions
+	}
+
+	for len(moves) < limit {
+		to := targets[0]
+		for _, target := range targets {
+			if loads[target] < loads[to] {
+				to = target
+			}
+		}
+		from := sources[0]
+		for _, source := range sources {
+			if loads[source] > loads[from] {
+				from = source
+			}
+		}
+		if loads[to] >= average {
+			break
+		}
+
+		index := -1
+		for i, partition := range candidates[from] {
+			if _, ok := to.Tables[partition.GetTableName()]; !ok {
+				continue
+			}
+			if loads[to]+partition.Bytes < loads[from] {
+				index = i
+				break
+			}
+		}
+		if index < 0 {
+			break
+		}
+
+		partition := candidates[from][index]
+		candidates[from] = append(candidates[from][:index], candidates[from][index+1:]...)
+		loads[from] -= partition.Bytes
+		loads[to] += partition.Bytes
+		moves = append(moves, &PartitionMove{
+			Partition: partition,
+			From:      from,
+			To:        to,
+		})
+	}
+
+	return moves
+}
+
+// PlanDrain plans moves of all partitions of source shards to target shards.
+// Each partition is moved to the smallest target shard having the table of the partition.
+func PlanDrain(sources, targets []*ShardPartitions) (moves []*PartitionMove, err error) {
+	loads := make(map[*ShardPartitions]uint64)
+	for _, target := range targets {
+		loads[target] = target.GetBytes()
+	}
+
+	for _, source := range sources {
+		partitions := append([]*Partition{}, source.Partitions...)
+		sort.SliceStable(partitions, func(i, j int) bool {
+			return partitions[i].ID < partitions[j].ID
+		})
+		for _, partition := range partitions {
+			var to *ShardPartitions
+			for _, target := range targets {
+				if _, ok := target.Tables[partition.GetTableName()]; !ok {
+					continue
+				}
+				if (to == nil) || (loads[target] < loads[to]) {
+					to = target
+				}
+			}
+			if to == nil {
+				return nil, fmt.Errorf("no shard to move partition %s of %s to", partition.ID, partition.GetTableName())
+			}
+			loads[to] += partition.Bytes
+			moves = append(moves, &PartitionMove{
+				Partition: partition,
+				From:      source,
+				To:        to,
+			})
+		}
+	}
+
+	return moves, nil
+}
@@ -0,0 +1,151 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schemer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newShardPartitions(tables []string, partitions ...*Partition) *ShardPartitions {
+	res := &ShardPartitions{
+		Tables:     make(map[string]string),
+		Partitions: partitions,
+	}
+	for _, table := range tables {
+		res.Tables[table] = "/clickhouse/tables/" + table
+	}
+	return res
+}
+
+func TestPlanRebalance(t *testing.T) {
+	tables := []string{"db.events"}
+
+	t.Run("oldest first until balanced", func(t *testing.T) {
+		source := newShardPartitions(tables,
+			&Partition{Database: "db", Table: "events", ID: "202403", Bytes: 100},
+			&Partition{Database: "db", Table: "events", ID: "202401", Bytes: 100},
+			&Partition{Database: "db", Table: "events", ID: "202402", Bytes: 100},
+			&Partition{Database: "db", Table: "events", ID: "202404", Bytes: 100},
+		)
+		target := newShardPartitions(tables)
+
+		moves := PlanRebalance([]*ShardPartitions{source}, []*ShardPartitions{target}, 10)
+		require.Len(t, moves, 2)
+		require.Equal(t, "202401", moves[0].Partition.ID)
+		require.Equal(t, "202402", moves[1].Partition.ID)
+		require.Same(t, source, moves[0].From)
+		require.Same(t, target, moves[0].To)
+	})
+
+	t.Run("limited", func(t *testing.T) {
+		source := newShardPartitions(tables,
+			&Partition{Database: "db", Table: "events", ID: "202401", Bytes: 100},
+			&Partition{Database: "db", Table: "events", ID: "202402", Bytes: 100},
+			&Partition{Database: "db", Table: "events", ID: "202403", Bytes: 100},
+			&Partition{Database: "db", Table: "events", ID: "202404", Bytes: 100},
+		)
+		moves := PlanRebalance([]*ShardPartitions{source}, []*ShardPartitions{newShardPartitions(tables)}, 1)
+		require.Len(t, moves, 1)
+	})
+
+	t.Run("missing table on target", func(t *testing.T) {
+		source := newShardPartitions(tables,
+			&Partition{Database: "db", Table: "events", ID: "202401", Bytes: 100},
+			&Partition{Database: "db", Table: "events", ID: "202402", Bytes: 100},
+		)
+		moves := PlanRebalance([]*ShardPartitions{source}, []*ShardPartitions{newShardPartitions(nil)}, 10)
+		require.Empty(t, moves)
+	})
+
+	t.Run("single partition is not moved", func(t *testing.T) {
+		source := newShardPartitions(tables,
+			&Partition{Database: "db", Table: "events", ID: "202401", Bytes: 100},
+		)
+		moves := PlanRebalance([]*ShardPartitions{source}, []*ShardPartitions{newShardPartitions(tables)}, 10)
+		require.Empty(t, moves)
+	})
+
+	t.Run("heaviest source first", func(t *testing.T) {
+		light := newShardPartitions(tables,
+			&Partition{Database: "db", Table: "events", ID: "202401", Bytes: 100},
+			&Partition{Database: "db", Table: "events", ID: "202402", Bytes: 100},
+		)
+		heavy := newShardPartitions(tables,
+			&Partition{Database: "db", Table: "events", ID: "202401", Bytes: 300},
+			&Partition{Database: "db", Table: "events", ID: "202402", Bytes: 300},
+		)
+		moves := PlanRebalance([]*ShardPartitions{light, heavy}, []*ShardPartitions{newShardPartitions(tables)}, 10)
+		require.Len(t, moves, 1)
+		require.Same(t, heavy, moves[0].From)
+	})
+}
+
+func TestPlanDrain(t *testing.T) {
+	tables := []string{"db.events"}
+
+	t.Run("all partitions to the smallest shards", func(t *testing.T) {
+		source := newShardPartitions(tables,
+			&Partition{Database: "db", Table: "events", ID: "202402", Bytes: 100},
+			&Partition{Database: "db", Table: "events", ID: "202401", Bytes: 100},
+			&Partition{Database: "db", Table: "events", ID: "202403", Bytes: 100},
+		)
+		small := newShardPartitions(tables)
+		big := newShardPartitions(tables,
+			&Partition{Database: "db", Table: "events", ID: "202401", Bytes: 150},
+		)
+
+		moves, err := PlanDrain([]*ShardPartitions{source}, []*ShardPartitions{big, small})
+		require.NoError(t, err)
+		require.Len(t, moves, 3)
+		require.Equal(t, "202401", moves[0].Partition.ID)
+		require.Same(t, small, moves[0].To)
+		require.Same(t, small, moves[1].To)
+		require.Same(t, big, moves[2].To)
+	})
+
+	t.Run("missing table on all targets", func(t *testing.T) {
+		source := newShardPartitions(tables,
+			&Partition{Database: "db", Table: "events", ID: "202401", Bytes: 100},
+		)
+		_, err := PlanDrain([]*ShardPartitions{source}, []*ShardPartitions{newShardPartitions(nil)})
+		require.Error(t, err)
+	})
+
+	t.Run("nothing to drain", func(t *testing.T) {
+		moves, err := PlanDrain([]*ShardPartitions{newShardPartitions(tables)}, []*ShardPartitions{newShardPartitions(tables)})
+		require.NoError(t, err)
+		require.Empty(t, moves)
+	})
+}
+
+func TestSelectPartsToAttach(t *testing.T) {
+	source := []Part{
+		{Name: "202401_0_0_0", Hash: "a"},
+		{Name: "202401_1_1_0", Hash: "b"},
+		{Name: "202401_2_2_0", Hash: "c"},
+	}
+
+	require.Equal(t, []string{"202401_0_0_0", "202401_1_1_0", "202401_2_2_0"}, selectPartsToAttach(source, nil))
+
+	// Parts attached by the previous failed move have another names on the target
+	target := []Part{
+		{Name: "202401_5_5_0", Hash: "a"},
+		{Name: "202401_6_6_0", Hash: "c"},
+	}
+	require.Equal(t, []string{"202401_1_1_0"}, selectPartsToAttach(source, target))
+	require.Empty(t, selectPartsToAttach(source, append(target, Part{Name: "202401_7_7_0", Hash: "b"})))
+}
@@ -51,6 +51,34 @@ func shouldCreateReplicatedObjects(host *api.ChiHost) bool {
 	return true
 }
 
+// getReplicatedDatabasesSQLs returns a list of databases of Replicated engine that needs to be created on a host.
+// These are databases specified in the cluster as well as databases of Replicated engine found on other hosts of the cluster
+func (s *ClusterSchemer) getReplicatedDatabasesSQLs(ctx context.Context, host *api.ChiHost) ([]string, []string) {
+	var names, sqls []string
+	for i := range host.GetCluster().ReplicatedDatabases {
+		database := &host.GetCluster().ReplicatedDatabases[i]
+		names = append(names, database.Name)
+		sqls = append(sqls, s.sqlCreateDatabaseReplicatedDeclared(database))
+	}
+
+	if !s.version.Matches(">= 22.12") {
+		// Engine arguments are not available, databases are not propagated
+		return names, sqls
+	}
+	if len(model.CreateFQDNs(host, api.Cluster{}, false)) < 2 {
+		// Single host in a cluster. Nothing to create databases from
+		return names, sqls
+	}
+	foundNames, foundSQLs := debugCreateSQLs(
+		s.QueryUnzip2Columns(
+			ctx,
+			model.CreateFQDNs(host, api.ClickHouseInstallation{}, false),
+			s.sqlCreateDatabaseReplicatedEngine(host.Runtime.Address.ClusterName),
+		),
+	)
+	return append(names, foundNames...), append(sqls, foundSQLs...)
+}
+
 // getReplicatedObjectsSQLs returns a list of objects that needs to be created on a host in a cluster
 func (s *ClusterSchemer) getReplicatedObjectsSQLs(ctx context.Context, host *api.ChiHost) ([]string, []string, error) {
 	if util.IsContextDone(ctx) {
@@ -16,6 +16,8 @@ package schemer
 
 import (
 	"context"
+	"strconv"
+	"strings"
 	"time"
 
 	log "github.com/altinity/clickhouse-operator/pkg/announcer"
@@ -26,10 +28,15 @@ import (
 	"github.com/altinity/clickhouse-operator/pkg/util"
 )
 
+// HostQuerier runs SQL query on the host bypassing network connection and returns query's output
+type HostQuerier func(ctx context.Context, host *api.ChiHost, sql string) (string, error)
+
 // ClusterSchemer specifies cluster schema manager
 type ClusterSchemer struct {
 	*Cluster
 	version *swversion.SoftWareVersion
+	// healthChecker, in case specified, is used for health-check queries instead of network connection
+	healthChecker HostQuerier
 }
 
 // NewClusterSchemer creates new Schemer object
@@ -40,12 +47,34 @@ func NewClusterSchemer(clusterConnectionParams *clickhouse.ClusterConnectionPara
 	}
 }
 
+// SetQueries sets retries of queries
+func (s *ClusterSchemer) SetQueries(queries *api.ChiQueries) *ClusterSchemer {
+	if s == nil {
+		return nil
+	}
+	s.Cluster.SetQueries(queries)
+	return s
+}
+
+// SetHealthChecker sets querier to be used for health-check queries instead of network connection
+func (s *ClusterSchemer) SetHealthChecker(healthChecker HostQuerier) *ClusterSchemer {
+	if s == nil {
+		return nil
+	}
+	s.healthChecker = healthChecker
+	return s
+}
+
 // HostSyncTables calls SYSTEM SYNC REPLICA for replicated tables
 func (s *ClusterSchemer) HostSyncTables(ctx context.Context, host *api.ChiHost) error {
 	tableNames, syncTableSQLs, _ := s.sqlSyncTable(ctx, host)
 	log.V(1).M(host).F().Info("Sync tables: %v as %v", tableNames, syncTableSQLs)
+	timeout := 120 * time.Second
+	if s.queries.GetQueryTimeout() > timeout {
+		timeout = s.queries.GetQueryTimeout()
+	}
 	opts := clickhouse.NewQueryOptions()
-	opts.SetQueryTimeout(120 * time.Second)
+	opts.SetQueryTimeout(timeout)
 	return s.ExecHost(ctx, host, syncTableSQLs, opts)
 }
 
@@ -117,6 +146,23 @@ func (s *ClusterSchemer) HostCreateTables(ctx context.Context, host *api.ChiHost
 	return nil
 }
 
+// HostCreateReplicatedDatabases creates databases of Replicated engine on a host.
+// Tables of these databases are created by ClickHouse, as the host joins the databases
+func (s *ClusterSchemer) HostCreateReplicatedDatabases(ctx context.Context, host *api.ChiHost) error {
+	if util.IsContextDone(ctx) {
+		log.V(2).Info("ctx is done")
+		return nil
+	}
+
+	names, sqls := s.getReplicatedDatabasesSQLs(ctx, host)
+	if len(sqls) == 0 {
+		return nil
+	}
+	log.V(1).M(host).F().Info("Creating replicated databases at %s: %v", host.Runtime.Address.HostName, names)
+	log.V(2).M(host).F().Info("\n%v", sqls)
+	return s.ExecHost(ctx, host, sqls, clickhouse.NewQueryOptions().SetRetry(true))
+}
+
 // HostDropTables drops tables on a host
 func (s *ClusterSchemer) HostDropTables(ctx context.Context, host *api.ChiHost) error {
 	tableNames, dropTableSQLs, _ := s.sqlDropTable(ctx, host)
@@ -127,9 +173,14 @@ func (s *ClusterSchemer) HostDropTables(ctx context.Context, host *api.ChiHost)
 // IsHostInCluster checks whether host is a member of at least one ClickHouse cluster
 func (s *ClusterSchemer) IsHostInCluster(ctx context.Context, host *api.ChiHost) bool {
 	inside := false
-	SQLs := []string{s.sqlHostInCluster()}
-	opts := clickhouse.NewQueryOptions().SetSilent(true)
-	err := s.ExecHost(ctx, host, SQLs, opts)
+	var err error
+	if s.healthChecker != nil {
+		_, err = s.healthChecker(ctx, host, s.sqlHostInCluster())
+	} else {
+		SQLs := []string{s.sqlHostInCluster()}
+		opts := clickhouse.NewQueryOptions().SetSilent(true)
+		err = s.ExecHost(ctx, host, SQLs, opts)
+	}
 	if err == nil {
 		log.V(1).M(host).F().Info("The host %s is inside the cluster", host.GetName())
 		inside = true
@@ -148,16 +199,51 @@ func (s *ClusterSchemer) CHIDropDnsCache(ctx context.Context, chi *api.ClickHous
 	return nil
 }
 
+// HostReloadDictionaries runs 'RELOAD DICTIONARIES' on the host
+func (s *ClusterSchemer) HostReloadDictionaries(ctx context.Context, host *api.ChiHost) error {
+	log.V(1).M(host).F().Info("Reload dictionaries at %v", host.Runtime.Address.HostName)
+	return s.ExecHost(ctx, host, []string{s.sqlReloadDictionaries()})
+}
+
+// HostReloadFunctions runs 'RELOAD FUNCTIONS' on the host
+func (s *ClusterSchemer) HostReloadFunctions(ctx context.Context, host *api.ChiHost) error {
+	log.V(1).M(host).F().Info("Reload functions at %v", host.Runtime.Address.HostName)
+	return s.ExecHost(ctx, host, []string{s.sqlReloadFunctions()})
+}
+
+// HostReloadConfig runs 'RELOAD CONFIG' on the host, which reloads certificates as well
+func (s *ClusterSchemer) HostReloadConfig(ctx context.Context, host *api.ChiHost) error {
+	log.V(1).M(host).F().Info("Reload config at %v", host.Runtime.Address.HostName)
+	return s.ExecHost(ctx, host, []string{s.sqlReloadConfig()})
+}
+
 // HostActiveQueriesNum returns how many active queries are on the host
 func (s *ClusterSchemer) HostActiveQueriesNum(ctx context.Context, host *api.ChiHost) (int, error) {
+	if s.healthChecker != nil {
+		out, err := s.healthChecker(ctx, host, s.sqlActiveQueriesNum())
+		if err != nil {
+			return 0, err
+		}
+		return strconv.Atoi(strings.TrimSpace(out))
+	}
 	return s.QueryHostInt(ctx, host, s.sqlActiveQueriesNum())
 }
 
 // HostClickHouseVersion returns ClickHouse version on the host
 func (s *ClusterSchemer) HostClickHouseVersion(ctx context.Context, host *api.ChiHost) (string, error) {
+	if s.healthChecker != nil {
+		out, err := s.healthChecker(ctx, host, s.sqlVersion())
+		return strings.TrimSpace(out), err
+	}
 	return s.QueryHostString(ctx, host, s.sqlVersion())
 }
 
+// HostSmokeTestQuery runs smoke test query on the host and returns its single value result
+func (s *ClusterSchemer) HostSmokeTestQuery(ctx context.Context, host *api.ChiHost, query *api.ChiSmokeTestQuery) (string, error) {
+	value, err := s.QueryHostString(ctx, host, model.Macro(host).Line(query.SQL))
+	return strings.TrimSpace(value), err
+}
+
 func debugCreateSQLs(names, sqls []string, err error) ([]string, []string) {
 	if err != nil {
 		log.V(1).Warning("got error: %v", err)
@@ -0,0 +1,67 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schemer
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/model/clickhouse"
+)
+
+func TestHealthChecker(t *testing.T) {
+	var queries []string
+	s := NewClusterSchemer(nil, nil)
+	s.SetHealthChecker(func(_ context.Context, _ *api.ChiHost, sql string) (string, error) {
+		queries = append(queries, sql)
+		switch sql {
+		case s.sqlVersion():
+			return "23.8.1.1\n", nil
+		case s.sqlActiveQueriesNum():
+			return "3\n", nil
+		}
+		return "", fmt.Errorf("Code: 395. DB::Exception: Value passed to 'throwIf' function is non-zero")
+	})
+	host := &api.ChiHost{}
+
+	version, err := s.HostClickHouseVersion(context.Background(), host)
+	require.NoError(t, err)
+	require.Equal(t, "23.8.1.1", version)
+
+	n, err := s.HostActiveQueriesNum(context.Background(), host)
+	require.NoError(t, err)
+	require.Equal(t, 3, n)
+
+	require.False(t, s.IsHostInCluster(context.Background(), host))
+	require.Equal(t, []string{s.sqlVersion(), s.sqlActiveQueriesNum(), s.sqlHostInCluster()}, queries)
+}
+
+func TestQueryOptions(t *testing.T) {
+	s := NewClusterSchemer(nil, nil)
+	require.Equal(t, 10, s.normalizeQueryOptions(clickhouse.NewQueryOptions().SetRetry(true)).Tries)
+	require.Equal(t, 1, s.normalizeQueryOptions(clickhouse.NewQueryOptions().SetRetry(false)).Tries)
+
+	s.SetQueries(&api.ChiQueries{Retries: 4, Backoff: 30})
+	opts := s.normalizeQueryOptions(clickhouse.NewQueryOptions().SetRetry(true))
+	require.Equal(t, 5, opts.Tries)
+	require.Equal(t, 30*time.Second, opts.Backoff)
+	require.Equal(t, 1, s.normalizeQueryOptions(clickhouse.NewQueryOptions().SetRetry(false)).Tries)
+	require.Equal(t, 1, s.normalizeQueryOptions().Tries)
+}
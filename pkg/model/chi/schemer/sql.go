@@ -17,6 +17,7 @@ package schemer
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/MakeNowJust/heredoc"
 
@@ -100,7 +101,7 @@ func (s *ClusterSchemer) sqlCreateDatabaseDistributed(cluster string) string {
 				clusterAllReplicas('%s', system.databases) databases
 			SETTINGS skip_unavailable_shards = 1
 		)
-		WHERE name IN (
+		WHERE engine != 'Replicated' AND name IN (
 			SELECT
 				DISTINCT arrayJoin([database, extract(engine_full, 'Distributed\\([^,]+, *\'?([^,\']+)\'?, *[^,]+')]) database
 			FROM
@@ -182,7 +183,8 @@ func (s *ClusterSchemer) sqlCreateDatabaseReplicated(cluster string) string {
 		FROM
 			clusterAllReplicas('%s', system.databases) databases
 		WHERE
-			name NOT IN (%s)
+			name NOT IN (%s) AND
+			engine != 'Replicated'
 		SETTINGS skip_unavailable_shards = 1
 		`,
 		createDatabaseStmt,
@@ -191,6 +193,33 @@ func (s *ClusterSchemer) sqlCreateDatabaseReplicated(cluster string) string {
 	)
 }
 
+// sqlCreateDatabaseReplicatedEngine returns SQL, which builds 'CREATE DATABASE' statements for databases of Replicated engine
+// of the cluster. Shard and replica names of the engine are stored expanded, so they are replaced with macros of the host
+func (s *ClusterSchemer) sqlCreateDatabaseReplicatedEngine(cluster string) string {
+	return heredoc.Docf(`
+		SELECT
+			DISTINCT name,
+			'CREATE DATABASE IF NOT EXISTS "' || name || '" Engine = ' ||
+				replaceRegexpOne(engine_full, '^Replicated\\(([^,)]+)[^)]*\\)', 'Replicated(\\1, \'{shard}\', \'{replica}\')') AS create_db_query
+		FROM
+			clusterAllReplicas('%s', system.databases) databases
+		WHERE
+			engine = 'Replicated'
+		SETTINGS skip_unavailable_shards = 1
+		`,
+		cluster,
+	)
+}
+
+// sqlCreateDatabaseReplicatedDeclared returns 'CREATE DATABASE' statement of the replicated database specified in the cluster
+func (s *ClusterSchemer) sqlCreateDatabaseReplicatedDeclared(database *api.ChiReplicatedDatabase) string {
+	return fmt.Sprintf(
+		`CREATE DATABASE IF NOT EXISTS "%s" Engine = Replicated('%s', '{shard}', '{replica}')`,
+		database.Name,
+		database.ZookeeperPath,
+	)
+}
+
 func (s *ClusterSchemer) sqlCreateTableReplicated(cluster string) string {
 	return heredoc.Docf(`
 		SELECT
@@ -240,6 +269,220 @@ func (s *ClusterSchemer) sqlDropDNSCache() string {
 	return `SYSTEM DROP DNS CACHE`
 }
 
+func (s *ClusterSchemer) sqlReloadDictionaries() string {
+	return `SYSTEM RELOAD DICTIONARIES`
+}
+
+func (s *ClusterSchemer) sqlReloadFunctions() string {
+	return `SYSTEM RELOAD FUNCTIONS`
+}
+
+func (s *ClusterSchemer) sqlReloadConfig() string {
+	return `SYSTEM RELOAD CONFIG`
+}
+
+func (s *ClusterSchemer) sqlDisks() string {
+	return heredoc.Doc(`
+		SELECT
+			name,
+			path,
+			toString(free_space)  AS free_space,
+			toString(total_space) AS total_space
+		FROM
+			system.disks
+		WHERE
+			type = 'local'
+		`,
+	)
+}
+
+func (s *ClusterSchemer) sqlQuotaUsage() string {
+	return heredoc.Doc(`
+		SELECT
+			quota_name,
+			quota_key,
+			toString(assumeNotNull(duration))  AS duration,
+			resource.1                         AS resource,
+			toString(assumeNotNull(resource.2)) AS used,
+			toString(assumeNotNull(resource.3)) AS max
+		FROM
+			system.quotas_usage
+		ARRAY JOIN
+			[
+				('queries',        toFloat64(queries),        toFloat64(max_queries)),
+				('errors',         toFloat64(errors),         toFloat64(max_errors)),
+				('result_rows',    toFloat64(result_rows),    toFloat64(max_result_rows)),
+				('result_bytes',   toFloat64(result_bytes),   toFloat64(max_result_bytes)),
+				('read_rows',      toFloat64(read_rows),      toFloat64(max_read_rows)),
+				('read_bytes',     toFloat64(read_bytes),     toFloat64(max_read_bytes)),
+				('execution_time', toFloat64(execution_time), toFloat64(max_execution_time))
+			] AS resource
+		WHERE
+			(duration IS NOT NULL) AND (resource.2 IS NOT NULL) AND (resource.3 IS NOT NULL) AND (resource.3 > 0)
+		ORDER BY
+			quota_name, quota_key, duration, resource
+		`,
+	)
+}
+
+func (s *ClusterSchemer) sqlReplicatedTables() string {
+	return heredoc.Docf(`
+		SELECT
+			database,
+			table,
+			replica_path
+		FROM
+			system.replicas
+		WHERE
+			database NOT IN (%s)
+		`,
+		ignoredDBs,
+	)
+}
+
+func (s *ClusterSchemer) sqlReplicatedPartitions() string {
+	return heredoc.Docf(`
+		SELECT
+			database,
+			table,
+			partition_id,
+			toString(sum(bytes_on_disk)) AS bytes
+		FROM
+			system.parts
+		WHERE
+			active AND
+			database NOT IN (%s) AND
+			(database, table) IN (SELECT database, table FROM system.replicas)
+		GROUP BY
+			database, table, partition_id
+		ORDER BY
+			database, table, partition_id
+		`,
+		ignoredDBs,
+	)
+}
+
+func (s *ClusterSchemer) sqlPartitionParts(partition *Partition) string {
+	return heredoc.Docf(`
+		SELECT
+			name,
+			hash_of_all_files
+		FROM
+			system.parts
+		WHERE
+			active AND database = '%s' AND table = '%s' AND partition_id = '%s'
+		ORDER BY
+			name
+		`,
+		escapeString(partition.Database), escapeString(partition.Table), escapeString(partition.ID),
+	)
+}
+
+func (s *ClusterSchemer) sqlPartitionDetachedParts(partition *Partition) string {
+	return heredoc.Docf(`
+		SELECT
+			name
+		FROM
+			system.detached_parts
+		WHERE
+			database = '%s' AND table = '%s' AND partition_id = '%s'
+		`,
+		escapeString(partition.Database), escapeString(partition.Table), escapeString(partition.ID),
+	)
+}
+
+func (s *ClusterSchemer) sqlFetchPart(partition *Partition, part, replicaPath string) string {
+	return fmt.Sprintf(
+		"ALTER TABLE \"%s\".\"%s\" FETCH PART '%s' FROM '%s'",
+		partition.Database, partition.Table, escapeString(part), escapeString(replicaPath),
+	)
+}
+
+func (s *ClusterSchemer) sqlAttachPart(partition *Partition, part string) string {
+	return fmt.Sprintf(
+		"ALTER TABLE \"%s\".\"%s\" ATTACH PART '%s'",
+		partition.Database, partition.Table, escapeString(part),
+	)
+}
+
+func (s *ClusterSchemer) sqlDropPart(partition *Partition, part string) string {
+	return fmt.Sprintf(
+		"ALTER TABLE \"%s\".\"%s\" DROP PART '%s'",
+		partition.Database, partition.Table, escapeString(part),
+	)
+}
+
+func (s *ClusterSchemer) sqlStopMerges(partition *Partition) string {
+	return fmt.Sprintf("SYSTEM STOP MERGES \"%s\".\"%s\"", partition.Database, partition.Table)
+}
+
+func (s *ClusterSchemer) sqlStartMerges(partition *Partition) string {
+	return fmt.Sprintf("SYSTEM START MERGES \"%s\".\"%s\"", partition.Database, partition.Table)
+}
+
+// escapeString escapes string to be used as a quoted SQL literal
+func escapeString(str string) string {
+	return strings.ReplaceAll(str, "'", "\\'")
+}
+
+func (s *ClusterSchemer) sqlReadonlyReplicas() string {
+	return heredoc.Docf(`
+		SELECT
+			database,
+			table,
+			zookeeper_path,
+			replica_name
+		FROM
+			system.replicas
+		WHERE
+			is_readonly AND database NOT IN (%s)
+		`,
+		ignoredDBs,
+	)
+}
+
+func (s *ClusterSchemer) sqlZookeeperChildren(path string) string {
+	return fmt.Sprintf(
+		"SELECT name FROM system.zookeeper WHERE path = '%s'",
+		strings.ReplaceAll(path, "'", "\\'"),
+	)
+}
+
+func (s *ClusterSchemer) sqlRestoreReplica(replica *ReadonlyReplica) string {
+	return fmt.Sprintf(
+		"SYSTEM RESTORE REPLICA \"%s\".\"%s\"",
+		replica.Database, replica.Table,
+	)
+}
+
+func (s *ClusterSchemer) sqlRestartReplica(replica *ReadonlyReplica) string {
+	return fmt.Sprintf(
+		"SYSTEM RESTART REPLICA \"%s\".\"%s\"",
+		replica.Database, replica.Table,
+	)
+}
+
+func (s *ClusterSchemer) sqlServerSettings() string {
+	return `SELECT name, value FROM system.server_settings`
+}
+
+func (s *ClusterSchemer) sqlMacros() string {
+	return `SELECT macro, substitution FROM system.macros`
+}
+
+func (s *ClusterSchemer) sqlClustersReplicasNum() string {
+	return heredoc.Doc(`
+		SELECT
+			cluster,
+			toString(count()) AS replicas
+		FROM
+			system.clusters
+		GROUP BY
+			cluster
+		`,
+	)
+}
+
 func (s *ClusterSchemer) sqlActiveQueriesNum() string {
 	return `SELECT count() FROM system.processes`
 }
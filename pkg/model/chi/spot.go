@@ -0,0 +1,88 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"sort"
+
+	core "k8s.io/api/core/v1"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+)
+
+// ApplySpot schedules pod template of the host either on spot nodes, in case host is designated to run on them,
+// or on on-demand nodes otherwise, so each shard keeps its on-demand replica
+func ApplySpot(podTemplate *api.ChiPodTemplate, host *api.ChiHost) {
+	if podTemplate == nil {
+		return
+	}
+	spot := host.GetCluster().Spot
+	if spot == nil {
+		return
+	}
+
+	operator := core.NodeSelectorOpNotIn
+	if host.IsSpot() {
+		operator = core.NodeSelectorOpIn
+		applySpotTolerations(podTemplate, spot)
+	}
+
+	// Sort keys, so pod template is stable across reconciles
+	var keys []string
+	for key := range spot.NodeSelector {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var requirements []core.NodeSelectorRequirement
+	for _, key := range keys {
+		requirements = append(requirements, core.NodeSelectorRequirement{
+			Key:      key,
+			Operator: operator,
+			Values:   []string{spot.NodeSelector[key]},
+		})
+	}
+	appendNodeSelectorRequirements(podTemplate, requirements...)
+}
+
+// applySpotTolerations makes pod template tolerate spot nodes and leave preempted nodes fast
+func applySpotTolerations(podTemplate *api.ChiPodTemplate, spot *api.ChiClusterSpot) {
+	AppendTolerations(podTemplate, spot.Tolerations...)
+
+	// Pods are bound to not ready and unreachable nodes for 5 minutes by default,
+	// while preempted node is not coming back, so the pod is to be rescheduled asap
+	seconds := spot.GetEvictionSeconds()
+	for _, taint := range []string{core.TaintNodeNotReady, core.TaintNodeUnreachable} {
+		if hasToleration(podTemplate, taint) {
+			continue
+		}
+		podTemplate.Spec.Tolerations = append(podTemplate.Spec.Tolerations, core.Toleration{
+			Key:               taint,
+			Operator:          core.TolerationOpExists,
+			Effect:            core.TaintEffectNoExecute,
+			TolerationSeconds: &seconds,
+		})
+	}
+}
+
+// hasToleration checks whether pod template tolerates specified taint explicitly
+func hasToleration(podTemplate *api.ChiPodTemplate, key string) bool {
+	for _, toleration := range podTemplate.Spec.Tolerations {
+		if toleration.Key == key {
+			return true
+		}
+	}
+	return false
+}
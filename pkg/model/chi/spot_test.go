@@ -0,0 +1,75 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi_test
+
+import (
+	"testing"
+
+	core "k8s.io/api/core/v1"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	model "github.com/altinity/clickhouse-operator/pkg/model/chi"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/builder"
+)
+
+func TestApplySpot(t *testing.T) {
+	chi := normalize(t, builder.NewCHI("test", "spot", builder.WithCluster(builder.NewCluster("main",
+		builder.WithShards(2),
+		builder.WithReplicas(3),
+		builder.WithSpot(api.ChiClusterSpot{
+			Replicas:     5,
+			NodeSelector: map[string]string{"cloud.google.com/gke-spot": "true"},
+			Tolerations: []core.Toleration{
+				{Key: "cloud.google.com/gke-spot", Operator: core.TolerationOpEqual, Value: "true", Effect: core.TaintEffectNoSchedule},
+			},
+		}),
+	))))
+
+	spot := 0
+	chi.WalkHosts(func(host *api.ChiHost) error {
+		podTemplate := &api.ChiPodTemplate{Name: host.GetName()}
+		model.ApplySpot(podTemplate, host)
+		requirements := getNodeSelectorRequirements(t, podTemplate)
+		if (len(requirements) != 1) || (requirements[0].Key != "cloud.google.com/gke-spot") {
+			t.Fatalf("host %s: unexpected node selector requirements: %v", host.GetName(), requirements)
+		}
+		tolerations := podTemplate.Spec.Tolerations
+		switch requirements[0].Operator {
+		case core.NodeSelectorOpIn:
+			spot++
+			if host.Runtime.Address.ReplicaIndex == 0 {
+				t.Errorf("first replica %s is scheduled on spot nodes", host.GetName())
+			}
+			if len(tolerations) != 3 {
+				t.Errorf("host %s: unexpected tolerations: %v", host.GetName(), tolerations)
+				return nil
+			}
+			for _, toleration := range tolerations[1:] {
+				if (toleration.TolerationSeconds == nil) || (*toleration.TolerationSeconds != api.SpotEvictionSecondsDefault) {
+					t.Errorf("host %s: unexpected eviction toleration: %v", host.GetName(), toleration)
+				}
+			}
+		case core.NodeSelectorOpNotIn:
+			if len(tolerations) != 0 {
+				t.Errorf("on-demand host %s: unexpected tolerations: %v", host.GetName(), tolerations)
+			}
+		}
+		return nil
+	})
+	// At least one on-demand replica per shard
+	if spot != 4 {
+		t.Errorf("unexpected number of spot replicas: %d", spot)
+	}
+}
@@ -0,0 +1,194 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+)
+
+const (
+	// tlsCAValidityFactor specifies how many times CA outlives certificates it issues,
+	// so CA stays the same across rotations and hosts which are not reloaded yet keep trusting reloaded ones
+	tlsCAValidityFactor = 10
+	// tlsClockSkew specifies how long before issue time certificates are valid, to tolerate clock skew of hosts
+	tlsClockSkew = 5 * time.Minute
+)
+
+// CreateClusterCertificate issues certificate for hosts of the cluster signed by CA of the cluster.
+// CA of the current Secret data is reused, unless it is missing or expires before the new certificate does.
+// Renewed CA is bundled with the previous one, so hosts trust certificates issued by both of them during rotation.
+func CreateClusterCertificate(cluster *api.Cluster, current map[string][]byte, now time.Time) (map[string][]byte, error) {
+	validity := cluster.TLS.GetValidity()
+	notAfter := now.Add(validity)
+
+	caBundle := current[TLSCAFile]
+	ca, caKey, err := parseCA(current)
+	if (err != nil) || ca.NotAfter.Before(notAfter) {
+		var caPEM []byte
+		ca, caKey, caPEM, err = createCA(cluster, now, tlsCAValidityFactor*validity)
+		if err != nil {
+			return nil, err
+		}
+		if prev, err := parseCertificate(current[TLSCAFile]); (err == nil) && prev.NotAfter.After(now) {
+			caPEM = append(caPEM, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: prev.Raw})...)
+		}
+		caBundle = caPEM
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	serial, err := newSerialNumber()
+	if err != nil {
+		return nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject: pkix.Name{
+			CommonName: fmt.Sprintf("%s-%s", cluster.Runtime.CHI.Name, cluster.Name),
+		},
+		DNSNames:    getClusterCertificateDNSNames(cluster),
+		NotBefore:   now.Add(-tlsClockSkew),
+		NotAfter:    notAfter,
+		KeyUsage:    x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca, &key.PublicKey, caKey)
+	if err != nil {
+		return nil, err
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	caKeyDER, err := x509.MarshalECPrivateKey(caKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string][]byte{
+		TLSCertificateFile:  pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		TLSPrivateKeyFile:   pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+		TLSCAFile:           caBundle,
+		TLSCAPrivateKeyFile: pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: caKeyDER}),
+	}, nil
+}
+
+// GetCertificateNotAfter gets expiry of the certificate of the Secret data
+func GetCertificateNotAfter(data map[string][]byte) (time.Time, error) {
+	cert, err := parseCertificate(data[TLSCertificateFile])
+	if err != nil {
+		return time.Time{}, err
+	}
+	return cert.NotAfter, nil
+}
+
+// getClusterCertificateDNSNames gets names hosts of the cluster are addressed by
+func getClusterCertificateDNSNames(cluster *api.Cluster) []string {
+	names := map[string]bool{}
+	cluster.WalkHosts(func(host *api.ChiHost) error {
+		fqdn := createPodFQDN(host)
+		names[CreatePodHostname(host)] = true
+		names[fqdn] = true
+		if i := strings.Index(fqdn, "."); i > 0 {
+			// Hosts added to the cluster later on are covered as well
+			names["*"+fqdn[i:]] = true
+		}
+		return nil
+	})
+
+	var res []string
+	for name := range names {
+		res = append(res, name)
+	}
+	sort.Strings(res)
+	return res
+}
+
+// createCA creates self-signed CA of the cluster
+func createCA(cluster *api.Cluster, now time.Time, validity time.Duration) (*x509.Certificate, *ecdsa.PrivateKey, []byte, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	serial, err := newSerialNumber()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject: pkix.Name{
+			CommonName: fmt.Sprintf("%s-%s-ca", cluster.Runtime.CHI.Name, cluster.Name),
+		},
+		NotBefore:             now.Add(-tlsClockSkew),
+		NotAfter:              now.Add(validity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return cert, key, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), nil
+}
+
+// parseCA parses CA of the Secret data. The first certificate of the bundle is the current CA
+func parseCA(data map[string][]byte) (*x509.Certificate, *ecdsa.PrivateKey, error) {
+	cert, err := parseCertificate(data[TLSCAFile])
+	if err != nil {
+		return nil, nil, err
+	}
+	block, _ := pem.Decode(data[TLSCAPrivateKeyFile])
+	if block == nil {
+		return nil, nil, fmt.Errorf("no CA private key found")
+	}
+	key, err := x509.ParseECPrivateKey(block.Bytes)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cert, key, nil
+}
+
+// parseCertificate parses the first certificate of PEM data
+func parseCertificate(data []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no certificate found")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+// newSerialNumber creates random serial number of a certificate
+func newSerialNumber() (*big.Int, error) {
+	return rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+}
@@ -0,0 +1,103 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"bytes"
+	"crypto/x509"
+	"testing"
+	"time"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+)
+
+func newTLSCluster(validityDays int) *api.Cluster {
+	chi := &api.ClickHouseInstallation{}
+	chi.Name = "test"
+	chi.Namespace = "dev"
+	return &api.Cluster{
+		Name: "main",
+		TLS: &api.ChiClusterTLS{
+			Managed:      api.NewStringBool(true),
+			ValidityDays: validityDays,
+		},
+		Layout: api.NewChiClusterLayout(),
+		Runtime: api.ClusterRuntime{
+			CHI: chi,
+		},
+	}
+}
+
+// verifyClusterCertificate verifies certificate of the Secret data against CA bundle of the Secret data
+func verifyClusterCertificate(t *testing.T, data map[string][]byte, now time.Time) {
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(data[TLSCAFile]) {
+		t.Fatalf("no CA certificates found")
+	}
+	cert, err := parseCertificate(data[TLSCertificateFile])
+	if err != nil {
+		t.Fatalf("unable to parse certificate err: %v", err)
+	}
+	if _, err := cert.Verify(x509.VerifyOptions{Roots: roots, CurrentTime: now, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}}); err != nil {
+		t.Errorf("unable to verify certificate err: %v", err)
+	}
+}
+
+func TestCreateClusterCertificate(t *testing.T) {
+	cluster := newTLSCluster(30)
+	now := time.Now()
+
+	issued, err := CreateClusterCertificate(cluster, nil, now)
+	if err != nil {
+		t.Fatalf("unable to issue certificate err: %v", err)
+	}
+	verifyClusterCertificate(t, issued, now)
+	if notAfter, err := GetCertificateNotAfter(issued); (err != nil) || !notAfter.Equal(now.Add(30*24*time.Hour).Truncate(time.Second)) {
+		t.Errorf("unexpected expiry of the certificate: %v err: %v", notAfter, err)
+	}
+
+	// Rotation keeps CA, so hosts not reloaded yet trust the new certificate
+	rotatedAt := now.Add(269 * 24 * time.Hour)
+	rotated, err := CreateClusterCertificate(cluster, issued, rotatedAt)
+	if err != nil {
+		t.Fatalf("unable to rotate certificate err: %v", err)
+	}
+	if !bytes.Equal(rotated[TLSCAFile], issued[TLSCAFile]) || !bytes.Equal(rotated[TLSCAPrivateKeyFile], issued[TLSCAPrivateKeyFile]) {
+		t.Errorf("CA is changed by rotation")
+	}
+	if bytes.Equal(rotated[TLSCertificateFile], issued[TLSCertificateFile]) {
+		t.Errorf("certificate is not changed by rotation")
+	}
+	verifyClusterCertificate(t, rotated, rotatedAt)
+
+	// CA expiring before the new certificate is renewed and bundled with the previous one
+	later := now.Add(285 * 24 * time.Hour)
+	renewed, err := CreateClusterCertificate(cluster, rotated, later)
+	if err != nil {
+		t.Fatalf("unable to rotate certificate err: %v", err)
+	}
+	if bytes.Equal(renewed[TLSCAPrivateKeyFile], rotated[TLSCAPrivateKeyFile]) {
+		t.Errorf("CA is not renewed")
+	}
+	if !bytes.HasSuffix(renewed[TLSCAFile], rotated[TLSCAFile]) {
+		t.Errorf("previous CA is not bundled")
+	}
+	verifyClusterCertificate(t, renewed, later)
+	// Hosts not reloaded yet are trusted by the renewed bundle
+	verifyClusterCertificate(t, map[string][]byte{
+		TLSCertificateFile: rotated[TLSCertificateFile],
+		TLSCAFile:          renewed[TLSCAFile],
+	}, later)
+}
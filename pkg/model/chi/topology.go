@@ -0,0 +1,112 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"encoding/json"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+)
+
+// Names of files of the topology ConfigMap
+const (
+	FileNameTopologyRemoteServers = "remote_servers.xml"
+	FileNameTopologyJSON          = "topology.json"
+)
+
+// Topology describes layout of the CHI for external consumers
+type Topology struct {
+	Namespace string            `json:"namespace"`
+	Name      string            `json:"name"`
+	Clusters  []TopologyCluster `json:"clusters"`
+}
+
+// TopologyCluster describes a cluster of the topology
+type TopologyCluster struct {
+	Name   string          `json:"name"`
+	Shards []TopologyShard `json:"shards"`
+}
+
+// TopologyShard describes a shard of the topology
+type TopologyShard struct {
+	Name                string            `json:"name"`
+	Weight              int               `json:"weight,omitempty"`
+	InternalReplication bool              `json:"internal_replication"`
+	Replicas            []TopologyReplica `json:"replicas"`
+}
+
+// TopologyReplica describes a host of the topology
+type TopologyReplica struct {
+	Name      string `json:"name"`
+	Host      string `json:"host"`
+	TCPPort   int32  `json:"tcp_port,omitempty"`
+	TLSPort   int32  `json:"tls_port,omitempty"`
+	HTTPPort  int32  `json:"http_port,omitempty"`
+	HTTPSPort int32  `json:"https_port,omitempty"`
+	Secure    bool   `json:"secure"`
+}
+
+// CreateTopology builds topology of the CHI user-specified clusters
+func CreateTopology(chi *api.ClickHouseInstallation) *Topology {
+	topology := &Topology{
+		Namespace: chi.Namespace,
+		Name:      chi.Name,
+		Clusters:  []TopologyCluster{},
+	}
+	chi.WalkClusters(func(cluster *api.Cluster) error {
+		c := TopologyCluster{
+			Name:   cluster.Name,
+			Shards: []TopologyShard{},
+		}
+		cluster.WalkShards(func(index int, shard *api.ChiShard) error {
+			s := TopologyShard{
+				Name:                shard.Name,
+				InternalReplication: shard.InternalReplication.IsTrue(),
+				Replicas:            []TopologyReplica{},
+			}
+			if shard.HasWeight() {
+				s.Weight = shard.GetWeight()
+			}
+			shard.WalkHosts(func(host *api.ChiHost) error {
+				s.Replicas = append(s.Replicas, TopologyReplica{
+					Name:      host.Name,
+					Host:      CreateFQDN(host),
+					TCPPort:   host.TCPPort,
+					TLSPort:   host.TLSPort,
+					HTTPPort:  host.HTTPPort,
+					HTTPSPort: host.HTTPSPort,
+					Secure:    host.IsSecure(),
+				})
+				return nil
+			})
+			c.Shards = append(c.Shards, s)
+			return nil
+		})
+		topology.Clusters = append(topology.Clusters, c)
+		return nil
+	})
+	return topology
+}
+
+// CreateTopologyFiles creates content of the topology ConfigMap.
+// Cluster secrets are omitted and hosts are addressed by FQDN, so the files can be mounted by any workload
+func CreateTopologyFiles(chi *api.ClickHouseInstallation) map[string]string {
+	options := NewRemoteServersGeneratorOptions().ExcludeSecrets().UseFQDN()
+	topology, _ := json.MarshalIndent(CreateTopology(chi), "", "  ")
+	return map[string]string{
+		FileNameTopologyRemoteServers: NewClickHouseConfigGenerator(chi).GetRemoteServers(options),
+		FileNameTopologyJSON:          string(topology),
+	}
+}
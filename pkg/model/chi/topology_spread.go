@@ -0,0 +1,92 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"gopkg.in/d4l3k/messagediff.v1"
+
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/apis/deployment"
+)
+
+// normalizeTopologySpread ensures TopologySpread distribution has scope, max skew and unsatisfiable action specified.
+// Shard scope spreads replicas of each shard
+func normalizeTopologySpread(podDistribution *api.ChiPodDistribution, _ int) []api.ChiPodDistribution {
+	if podDistribution.Scope == "" {
+		podDistribution.Scope = deployment.PodDistributionScopeShard
+	}
+	// Number specifies max skew
+	if podDistribution.Number < 1 {
+		podDistribution.Number = 1
+	}
+	if podDistribution.WhenUnsatisfiable != string(core.ScheduleAnyway) {
+		podDistribution.WhenUnsatisfiable = string(core.DoNotSchedule)
+	}
+	return nil
+}
+
+// NewTopologySpreadConstraints creates topology spread constraints out of TopologySpread pod distributions.
+// Label selectors contain macros to be expanded for each host by PrepareTopologySpreadConstraints
+func NewTopologySpreadConstraints(template *api.ChiPodTemplate) []core.TopologySpreadConstraint {
+	var constraints []core.TopologySpreadConstraint
+	for i := range template.PodDistribution {
+		podDistribution := &template.PodDistribution[i]
+		if podDistribution.Type != deployment.PodDistributionTopologySpread {
+			continue
+		}
+		constraints = append(constraints, core.TopologySpreadConstraint{
+			MaxSkew:           int32(podDistribution.Number),
+			TopologyKey:       podDistribution.TopologyKey,
+			WhenUnsatisfiable: core.UnsatisfiableConstraintAction(podDistribution.WhenUnsatisfiable),
+			LabelSelector: &meta.LabelSelector{
+				MatchLabels: newMatchLabels(podDistribution, map[string]string{
+					LabelAppName: LabelAppValue,
+				}),
+			},
+		})
+	}
+	return constraints
+}
+
+// MergeTopologySpreadConstraints merges constraints from src into dst skipping already present ones and returns dst
+func MergeTopologySpreadConstraints(dst, src []core.TopologySpreadConstraint) []core.TopologySpreadConstraint {
+	for i := range src {
+		equal := false
+		for j := range dst {
+			if _, equal = messagediff.DeepDiff(src[i], dst[j]); equal {
+				break
+			}
+		}
+		if !equal {
+			dst = append(dst, src[i])
+		}
+	}
+	return dst
+}
+
+// PrepareTopologySpreadConstraints expands macros of topology spread constraints for the host
+func PrepareTopologySpreadConstraints(podTemplate *api.ChiPodTemplate, host *api.ChiHost) {
+	if podTemplate == nil {
+		return
+	}
+	for i := range podTemplate.Spec.TopologySpreadConstraints {
+		constraint := &podTemplate.Spec.TopologySpreadConstraints[i]
+		processLabelSelector(constraint.LabelSelector, host)
+		constraint.TopologyKey = Macro(host).Line(constraint.TopologyKey)
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi_test
+
+import (
+	"testing"
+
+	core "k8s.io/api/core/v1"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	model "github.com/altinity/clickhouse-operator/pkg/model/chi"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/builder"
+)
+
+func TestPrepareTopologySpreadConstraints(t *testing.T) {
+	template := builder.NewPodTemplate("spread", "clickhouse/clickhouse-server:23.8")
+	template.PodDistribution = []api.ChiPodDistribution{
+		{Type: "TopologySpread", TopologyKey: core.LabelTopologyZone},
+	}
+	chi := normalize(t, builder.NewCHI("test", "spread",
+		builder.WithCluster(builder.NewCluster("main", builder.WithShards(2), builder.WithReplicas(2))),
+		builder.WithPodTemplates(template),
+		builder.WithDefaultPodTemplate("spread"),
+	))
+	normalized, ok := chi.GetPodTemplate("spread")
+	if !ok {
+		t.Fatalf("pod template is not normalized")
+	}
+
+	chi.WalkHosts(func(host *api.ChiHost) error {
+		podTemplate := normalized.DeepCopy()
+		model.PrepareTopologySpreadConstraints(podTemplate, host)
+		constraints := podTemplate.Spec.TopologySpreadConstraints
+		if len(constraints) != 1 {
+			t.Fatalf("host %s: unexpected topology spread constraints: %v", host.GetName(), constraints)
+		}
+		constraint := constraints[0]
+		if constraint.MaxSkew != 1 || constraint.TopologyKey != core.LabelTopologyZone || constraint.WhenUnsatisfiable != core.DoNotSchedule {
+			t.Errorf("host %s: unexpected topology spread constraint: %v", host.GetName(), constraint)
+		}
+		// Replicas of the same shard are spread
+		if shard := constraint.LabelSelector.MatchLabels[model.LabelShardName]; shard != host.Runtime.Address.ShardName {
+			t.Errorf("host %s: got shard %q want %q", host.GetName(), shard, host.Runtime.Address.ShardName)
+		}
+		return nil
+	})
+}
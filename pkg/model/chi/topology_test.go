@@ -0,0 +1,54 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	model "github.com/altinity/clickhouse-operator/pkg/model/chi"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/builder"
+)
+
+func TestCreateTopologyFiles(t *testing.T) {
+	input := builder.NewCHI("test", "apps",
+		builder.WithCluster(builder.NewCluster("main", builder.WithShards(2), builder.WithReplicas(2))),
+		builder.WithCluster(builder.NewCluster("events")),
+	)
+	input.Spec.Configuration.Clusters[0].Secret = &api.ClusterSecret{Value: "plaintext"}
+	files := model.CreateTopologyFiles(normalize(t, input))
+
+	xml := files[model.FileNameTopologyRemoteServers]
+	if !strings.Contains(xml, "<host>chi-apps-main-1-1.test.svc.cluster.local</host>") {
+		t.Errorf("remote servers must address hosts by FQDN:\n%s", xml)
+	}
+	if strings.Contains(xml, "<secret") {
+		t.Errorf("remote servers must not expose cluster secret:\n%s", xml)
+	}
+
+	var topology model.Topology
+	if err := json.Unmarshal([]byte(files[model.FileNameTopologyJSON]), &topology); err != nil {
+		t.Fatalf("unable to unmarshal topology err: %v", err)
+	}
+	if len(topology.Clusters) != 2 || len(topology.Clusters[0].Shards) != 2 || len(topology.Clusters[0].Shards[1].Replicas) != 2 {
+		t.Fatalf("unexpected topology: %+v", topology)
+	}
+	replica := topology.Clusters[0].Shards[1].Replicas[1]
+	if replica.Host != "chi-apps-main-1-1.test.svc.cluster.local" || replica.TCPPort != 9000 || replica.HTTPPort != 8123 {
+		t.Errorf("unexpected replica: %+v", replica)
+	}
+}
@@ -16,6 +16,7 @@ package chi
 
 import (
 	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 
 	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
 )
@@ -59,3 +60,61 @@ func GetPVCProvisioner(host *api.ChiHost, template *api.ChiVolumeClaimTemplate)
 	// Default value
 	return api.PVCProvisionerStatefulSet
 }
+
+// GetDiskPressureThreshold gets percentage of used disk space at which disk pressure is reported
+func GetDiskPressureThreshold(host *api.ChiHost, template *api.ChiVolumeClaimTemplate) int {
+	// VolumeClaimTemplate.DiskPressureThreshold, in case specified
+	if (template != nil) && (template.DiskPressureThreshold > 0) {
+		return template.DiskPressureThreshold
+	}
+
+	return host.GetCHI().Spec.Defaults.StorageManagement.DiskPressureThreshold
+}
+
+// GetAutoExpansion gets auto expansion config of the volume claim template, defaults are used for unspecified fields
+func GetAutoExpansion(host *api.ChiHost, template *api.ChiVolumeClaimTemplate) *api.StorageAutoExpansion {
+	var expansion *api.StorageAutoExpansion
+	if template != nil {
+		expansion = expansion.MergeFrom(template.AutoExpansion, api.MergeTypeOverrideByNonEmptyValues)
+	}
+	return expansion.MergeFrom(host.GetCHI().Spec.Defaults.StorageManagement.AutoExpansion, api.MergeTypeFillEmptyValues)
+}
+
+// IsDiskUsageMonitored checks whether disk usage of any host of the CHI has to be monitored
+func IsDiskUsageMonitored(chi *api.ClickHouseInstallation) bool {
+	if (chi.Spec.Defaults != nil) && (chi.Spec.Defaults.StorageManagement != nil) &&
+		(chi.Spec.Defaults.StorageManagement.DiskPressureThreshold > 0) {
+		return true
+	}
+	monitored := false
+	chi.WalkVolumeClaimTemplates(func(template *api.ChiVolumeClaimTemplate) {
+		if template.DiskPressureThreshold > 0 {
+			monitored = true
+		}
+	})
+	return monitored
+}
+
+// GetAutoExpandedStorageSize calculates storage request expanded by one step of auto expansion, capped by max size.
+// Returns false in case storage request can not be expanded.
+func GetAutoExpandedStorageSize(cur resource.Quantity, expansion *api.StorageAutoExpansion) (resource.Quantity, bool) {
+	maxSize, ok := expansion.GetMaxSize()
+	if !expansion.IsEnabled() || !ok || (cur.Cmp(maxSize) >= 0) {
+		return cur, false
+	}
+
+	step := expansion.StepPercent
+	if step <= 0 {
+		step = api.AutoExpansionStepPercentDefault
+	}
+
+	// Round expanded size up to whole mebibytes
+	const mebibyte = 1024 * 1024
+	value := cur.Value() + cur.Value()*int64(step)/100
+	value = (value + mebibyte - 1) / mebibyte * mebibyte
+	size := resource.NewQuantity(value, resource.BinarySI)
+	if size.Cmp(maxSize) > 0 {
+		return maxSize, true
+	}
+	return *size, true
+}
@@ -0,0 +1,89 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+)
+
+func TestGetAutoExpandedStorageSize(t *testing.T) {
+	tests := []struct {
+		name      string
+		cur       string
+		expansion *api.StorageAutoExpansion
+		want      string
+		expanded  bool
+	}{
+		{
+			name:      "step",
+			cur:       "10Gi",
+			expansion: &api.StorageAutoExpansion{Enabled: api.NewStringBool(true), StepPercent: 50, MaxSize: "100Gi"},
+			want:      "15Gi",
+			expanded:  true,
+		},
+		{
+			name:      "default step",
+			cur:       "10Gi",
+			expansion: &api.StorageAutoExpansion{Enabled: api.NewStringBool(true), MaxSize: "100Gi"},
+			want:      "12Gi",
+			expanded:  true,
+		},
+		{
+			name:      "rounded",
+			cur:       "1G",
+			expansion: &api.StorageAutoExpansion{Enabled: api.NewStringBool(true), StepPercent: 10, MaxSize: "100Gi"},
+			want:      "1050Mi",
+			expanded:  true,
+		},
+		{
+			name:      "capped",
+			cur:       "90Gi",
+			expansion: &api.StorageAutoExpansion{Enabled: api.NewStringBool(true), StepPercent: 50, MaxSize: "100Gi"},
+			want:      "100Gi",
+			expanded:  true,
+		},
+		{
+			name:      "max size reached",
+			cur:       "100Gi",
+			expansion: &api.StorageAutoExpansion{Enabled: api.NewStringBool(true), StepPercent: 50, MaxSize: "100Gi"},
+			want:      "100Gi",
+		},
+		{
+			name:      "no max size",
+			cur:       "10Gi",
+			expansion: &api.StorageAutoExpansion{Enabled: api.NewStringBool(true), StepPercent: 50},
+			want:      "10Gi",
+		},
+		{
+			name:      "disabled",
+			cur:       "10Gi",
+			expansion: &api.StorageAutoExpansion{Enabled: api.NewStringBool(false), StepPercent: 50, MaxSize: "100Gi"},
+			want:      "10Gi",
+		},
+	}
+	for _, tt := range tests {
+		size, expanded := GetAutoExpandedStorageSize(resource.MustParse(tt.cur), tt.expansion)
+		if expanded != tt.expanded {
+			t.Errorf("%s: expanded: got %v want %v", tt.name, expanded, tt.expanded)
+		}
+		if want := resource.MustParse(tt.want); size.Cmp(want) != 0 {
+			t.Errorf("%s: size: got %s want %s", tt.name, size.String(), want.String())
+		}
+	}
+}
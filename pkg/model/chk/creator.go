@@ -25,6 +25,7 @@ import (
 
 	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse-keeper.altinity.com/v1"
 	"github.com/altinity/clickhouse-operator/pkg/chop"
+	"github.com/altinity/clickhouse-operator/pkg/model/k8s"
 )
 
 // CreateConfigMap returns a config map containing ClickHouse Keeper config XML
@@ -94,6 +95,7 @@ func createPodTemplateSpec(chk *api.ClickHouseKeeperInstallation) core.PodSpec {
 	}
 	podSpec.InitContainers = createInitContainers(chk)
 	podSpec.Containers = createContainers(chk)
+	k8s.PodSpecAppendImagePullSecrets(&podSpec, chop.Config().Pod.ImagePullSecrets...)
 
 	return podSpec
 }
@@ -142,7 +142,7 @@ func (c *Cluster) exec(ctx context.Context, host string, queries []string, _opts
 	}
 
 	opts := QueryOptionsNormalize(_opts...)
-	err := r.Retry(ctx, opts.Tries, "Applying sqls", c.l.V(1).M(host).F(),
+	err := r.RetryWithBackoff(ctx, opts.Tries, opts.Backoff, "Applying sqls", c.l.V(1).M(host).F(),
 		func() error {
 			var errors []error
 			for i, sql := range queries {
@@ -14,6 +14,10 @@
 
 package clickhouse
 
+import (
+	"time"
+)
+
 const (
 	// Max number of tries for SQL queries
 	defaultMaxTries = 10
@@ -23,6 +27,7 @@ const (
 type QueryOptions struct {
 	Retry    bool
 	Tries    int
+	Backoff  time.Duration
 	Parallel bool
 	Silent   bool
 	*Timeouts
@@ -31,7 +36,8 @@ type QueryOptions struct {
 // NewQueryOptions creates new query options
 func NewQueryOptions() *QueryOptions {
 	opts := new(QueryOptions)
-	opts.Timeouts = NewTimeouts()
+	// Zero timeouts fall back to timeouts of the connection
+	opts.Timeouts = NewTimeouts(0, 0)
 	return opts
 }
 
@@ -75,6 +81,24 @@ func (o *QueryOptions) SetRetry(retry bool) *QueryOptions {
 	return o
 }
 
+// SetTries sets number of tries
+func (o *QueryOptions) SetTries(tries int) *QueryOptions {
+	if o == nil {
+		return nil
+	}
+	o.Tries = tries
+	return o
+}
+
+// SetBackoff sets base delay between tries
+func (o *QueryOptions) SetBackoff(backoff time.Duration) *QueryOptions {
+	if o == nil {
+		return nil
+	}
+	o.Backoff = backoff
+	return o
+}
+
 // GetSilent gets silent option
 func (o *QueryOptions) GetSilent() bool {
 	if o == nil {
@@ -25,6 +25,45 @@ func PodSpecAddContainer(podSpec *core.PodSpec, container core.Container) {
 	podSpec.Containers = append(podSpec.Containers, container)
 }
 
+// PodSpecAddInitContainer adds init container to PodSpec
+func PodSpecAddInitContainer(podSpec *core.PodSpec, container core.Container) {
+	podSpec.InitContainers = append(podSpec.InitContainers, container)
+}
+
+// PodSpecHasContainer checks whether PodSpec has either container or init container of the specified name
+func PodSpecHasContainer(podSpec *core.PodSpec, name string) bool {
+	for i := range podSpec.InitContainers {
+		if podSpec.InitContainers[i].Name == name {
+			return true
+		}
+	}
+	_, ok := PodSpecContainerGet(podSpec, name, -1)
+	return ok
+}
+
+// PodSpecContainerGet gets container from the PodSpec either by name or by index
+func PodSpecContainerGet(podSpec *core.PodSpec, name string, index int) (*core.Container, bool) {
+	// Find by name
+	if len(name) > 0 {
+		for i := range podSpec.Containers {
+			// Convenience wrapper
+			container := &podSpec.Containers[i]
+			if container.Name == name {
+				return container, true
+			}
+		}
+	}
+
+	// Find by index
+	if index >= 0 {
+		if len(podSpec.Containers) > index {
+			return &podSpec.Containers[index], true
+		}
+	}
+
+	return nil, false
+}
+
 // ContainerAppendVolumeMounts appends multiple VolumeMount(s) to the specified container
 func ContainerAppendVolumeMounts(container *core.Container, volumeMounts ...core.VolumeMount) {
 	for _, volumeMount := range volumeMounts {
@@ -0,0 +1,53 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8s
+
+import (
+	core "k8s.io/api/core/v1"
+)
+
+// IsPodReady returns whether Pod has Ready condition set
+func IsPodReady(pod *core.Pod) bool {
+	if pod == nil {
+		return false
+	}
+
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == core.PodReady {
+			return condition.Status == core.ConditionTrue
+		}
+	}
+	return false
+}
+
+// PodSpecAppendImagePullSecrets appends image pull secrets to PodSpec, unless secrets of the same names are listed already
+func PodSpecAppendImagePullSecrets(podSpec *core.PodSpec, secrets ...core.LocalObjectReference) {
+	for _, secret := range secrets {
+		if (secret.Name == "") || podSpecHasImagePullSecret(podSpec, secret.Name) {
+			continue
+		}
+		podSpec.ImagePullSecrets = append(podSpec.ImagePullSecrets, secret)
+	}
+}
+
+// podSpecHasImagePullSecret checks whether PodSpec lists image pull secret with specified name
+func podSpecHasImagePullSecret(podSpec *core.PodSpec, name string) bool {
+	for _, secret := range podSpec.ImagePullSecrets {
+		if secret.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -120,12 +120,20 @@ func StatefulSetAppendVolumes(statefulSet *apps.StatefulSet, volumes ...core.Vol
 func StatefulSetAppendVolumeMounts(statefulSet *apps.StatefulSet, volumeMounts ...core.VolumeMount) {
 	// And reference these Volumes in each Container via VolumeMount
 	// So Pod will have VolumeMounts mounted as Volumes
-	for i := range statefulSet.Spec.Template.Spec.Containers {
-		// Convenience wrapper
-		container := &statefulSet.Spec.Template.Spec.Containers[i]
+	StatefulSetWalkContainers(statefulSet, func(container *core.Container) {
 		ContainerAppendVolumeMounts(
 			container,
 			volumeMounts...,
 		)
+	})
+}
+
+// StatefulSetWalkContainers walks over all containers of the StatefulSet, init containers included
+func StatefulSetWalkContainers(statefulSet *apps.StatefulSet, f func(container *core.Container)) {
+	for i := range statefulSet.Spec.Template.Spec.InitContainers {
+		f(&statefulSet.Spec.Template.Spec.InitContainers[i])
+	}
+	for i := range statefulSet.Spec.Template.Spec.Containers {
+		f(&statefulSet.Spec.Template.Spec.Containers[i])
 	}
 }
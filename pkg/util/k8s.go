@@ -29,6 +29,10 @@ func NamespaceNameString(meta v1.ObjectMeta) string {
 // AnnotationsTobeSkipped kubectl service annotation that we'd like to skip
 var AnnotationsTobeSkipped = []string{
 	"kubectl.kubernetes.io/last-applied-configuration",
+	// Approval of the reconcile plan is not propagated to CHI objects
+	"clickhouse.altinity.com/approved-plan",
+	// Restart request is not propagated to CHI objects, so pods are restarted by the operator one by one
+	"clickhouse.altinity.com/restart",
 }
 
 // IsAnnotationToBeSkipped checks whether an annotation should be skipped
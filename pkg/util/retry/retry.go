@@ -22,8 +22,19 @@ import (
 	"github.com/altinity/clickhouse-operator/pkg/util"
 )
 
+// defaultBackoff specifies base delay between tries
+const defaultBackoff = 5 * time.Second
+
 // Retry retries specified function
 func Retry(ctx context.Context, tries int, desc string, a log.Announcer, f func() error) error {
+	return RetryWithBackoff(ctx, tries, defaultBackoff, desc, a, f)
+}
+
+// RetryWithBackoff retries specified function with delay between tries growing linearly by backoff
+func RetryWithBackoff(ctx context.Context, tries int, backoff time.Duration, desc string, a log.Announcer, f func() error) error {
+	if backoff <= 0 {
+		backoff = defaultBackoff
+	}
 	var err error
 	for try := 1; try <= tries; try++ {
 		if util.IsContextDone(ctx) {
@@ -44,9 +55,9 @@ func Retry(ctx context.Context, tries int, desc string, a log.Announcer, f func(
 
 		if try < tries {
 			// Try failed, need to sleep and retry
-			seconds := try * 5
-			a.Info("FAILED attempt %d of %d, sleep %d sec and retry: %s", try, tries, seconds, desc)
-			util.WaitContextDoneOrTimeout(ctx, time.Duration(seconds)*time.Second)
+			delay := time.Duration(try) * backoff
+			a.Info("FAILED attempt %d of %d, sleep %s and retry: %s", try, tries, delay, desc)
+			util.WaitContextDoneOrTimeout(ctx, delay)
 		} else if tries == 1 {
 			// On single try do not put so much emotion. It just failed and user is not intended to retry
 			a.Warning("FAILED single try. No retries will be made for %s", desc)